@@ -0,0 +1,79 @@
+package cal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Archive is the on-disk format written by `pylon cal archive`. It holds the
+// events removed from a single feed so they can be restored later.
+type Archive struct {
+	Feed       Feed      `json:"feed"`
+	ArchivedAt time.Time `json:"archived_at"`
+	Before     time.Time `json:"before"`
+	Events     []Event   `json:"events"`
+}
+
+// WriteArchive encodes an archive as indented JSON.
+func WriteArchive(w io.Writer, a *Archive) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(a); err != nil {
+		return fmt.Errorf("encode archive: %w", err)
+	}
+	return nil
+}
+
+// ReadArchive decodes an archive previously written by WriteArchive.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	var a Archive
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, fmt.Errorf("decode archive: %w", err)
+	}
+	return &a, nil
+}
+
+// CreateRequest converts an existing event back into a create payload, used
+// when restoring archived events. The event is recreated in feedID with its
+// UID and SEQUENCE, so subscribers see the same event rather than a new one.
+func (e *Event) CreateRequest(feedID string) *CreateEventRequest {
+	req := &CreateEventRequest{
+		FeedID:      feedID,
+		Summary:     e.Summary,
+		Description: e.Description,
+		Location:    e.Location,
+		URL:         e.URL,
+		Start:       e.Start.Format(time.RFC3339),
+		AllDay:      e.AllDay,
+		Status:      e.Status,
+		Categories:  e.Categories,
+		ExternalID:  e.ExternalID,
+		RRule:       e.RRule,
+		Alarms:      e.Alarms,
+		Attachments: e.Attachments,
+		UID:         e.UID,
+		Sequence:    e.Sequence,
+	}
+	if e.End != nil {
+		req.End = e.End.Format(time.RFC3339)
+	}
+	if e.Deadline != nil {
+		req.Deadline = e.Deadline.Format(time.RFC3339)
+	}
+	for _, t := range e.ExDates {
+		req.ExDates = append(req.ExDates, t.Format(time.RFC3339))
+	}
+	return req
+}
+
+// CopyRequest returns the payload for an independent copy of e in feedID.
+// Unlike CreateRequest it leaves out the external ID, UID and SEQUENCE:
+// the copy is a new event, which automation keyed on the external ID and
+// calendar apps matching by UID must not mistake for the original.
+func (e *Event) CopyRequest(feedID string) *CreateEventRequest {
+	req := e.CreateRequest(feedID)
+	req.ExternalID, req.UID, req.Sequence = "", "", 0
+	return req
+}
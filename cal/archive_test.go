@@ -0,0 +1,143 @@
+package cal
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	a := &Archive{
+		Feed:       Feed{ID: "feed-1", Name: "Work", Token: "work"},
+		ArchivedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Before:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Events: []Event{
+			{ID: "evt-1", FeedID: "feed-1", Summary: "Standup", Start: start, End: &end},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, a); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := ReadArchive(&buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if got.Feed.ID != "feed-1" {
+		t.Errorf("expected feed ID %q, got %q", "feed-1", got.Feed.ID)
+	}
+	if len(got.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(got.Events))
+	}
+	if !got.Events[0].Start.Equal(start) {
+		t.Errorf("expected start %v, got %v", start, got.Events[0].Start)
+	}
+	if !got.Before.Equal(a.Before) {
+		t.Errorf("expected before %v, got %v", a.Before, got.Before)
+	}
+}
+
+func TestReadArchiveInvalid(t *testing.T) {
+	if _, err := ReadArchive(bytes.NewBufferString("not json")); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestEventCreateRequest(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	deadline := start.Add(-time.Hour)
+	e := &Event{
+		ID:          "evt-1",
+		FeedID:      "old-feed",
+		Summary:     "Review",
+		Description: "Quarterly",
+		Location:    "Room 1",
+		URL:         "https://example.com",
+		Start:       start,
+		End:         &end,
+		AllDay:      true,
+		Deadline:    &deadline,
+		Status:      "CONFIRMED",
+		Categories:  "work,review",
+	}
+
+	req := e.CreateRequest("new-feed")
+
+	if req.FeedID != "new-feed" {
+		t.Errorf("expected feed %q, got %q", "new-feed", req.FeedID)
+	}
+	if req.Start != "2024-03-01T09:00:00Z" {
+		t.Errorf("unexpected start %q", req.Start)
+	}
+	if req.End != "2024-03-01T10:00:00Z" {
+		t.Errorf("unexpected end %q", req.End)
+	}
+	if req.Deadline != "2024-03-01T08:00:00Z" {
+		t.Errorf("unexpected deadline %q", req.Deadline)
+	}
+	if req.Summary != e.Summary || req.Location != e.Location || req.Categories != e.Categories {
+		t.Errorf("fields not copied: %+v", req)
+	}
+	if !req.AllDay {
+		t.Error("expected AllDay to be copied")
+	}
+}
+
+func TestEventCreateRequestOptionalTimes(t *testing.T) {
+	e := &Event{Summary: "Open", Start: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)}
+	req := e.CreateRequest("f")
+	if req.End != "" || req.Deadline != "" {
+		t.Errorf("expected empty end/deadline, got %q/%q", req.End, req.Deadline)
+	}
+}
+
+func TestEventCopyRequest(t *testing.T) {
+	e := &Event{Summary: "Standup", Start: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), ExternalID: "ext-1", UID: "standup@example.com", Sequence: 4}
+	if req := e.CreateRequest("f"); req.ExternalID != "ext-1" || req.UID != e.UID || req.Sequence != 4 {
+		t.Errorf("CreateRequest should keep the event's identity: %+v", req)
+	}
+	req := e.CopyRequest("f")
+	if req.ExternalID != "" || req.UID != "" || req.Sequence != 0 || req.Summary != "Standup" || req.FeedID != "f" {
+		t.Errorf("CopyRequest = %+v", req)
+	}
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	b := &Backup{
+		Version:   BackupVersion,
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Feeds: []FeedBackup{{
+			Feed:   Feed{ID: "feed-1", Name: "Work", Token: "work", RefreshInterval: "PT1H"},
+			Events: []Event{{ID: "evt-1", FeedID: "feed-1", Summary: "Standup", Start: start, UID: "standup@example.com"}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBackup(&buf, b); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := ReadBackup(&buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got.Feeds) != 1 || got.Feeds[0].Feed.Token != "work" || got.Feeds[0].Feed.RefreshInterval != "PT1H" {
+		t.Fatalf("feeds = %+v", got.Feeds)
+	}
+	if e := got.Feeds[0].Events; len(e) != 1 || e[0].UID != "standup@example.com" || !e[0].Start.Equal(start) {
+		t.Errorf("events = %+v", e)
+	}
+}
+
+func TestReadBackupVersion(t *testing.T) {
+	for _, in := range []string{`{"feeds": []}`, `{"version": 99, "feeds": []}`, "not json"} {
+		if _, err := ReadBackup(bytes.NewBufferString(in)); err == nil {
+			t.Errorf("ReadBackup(%s): expected error", in)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// BackupVersion is the version of the backup format WriteBackup writes.
+// ReadBackup refuses backups from a newer version.
+const BackupVersion = 1
+
+// Backup is the on-disk format written by `pylon cal backup`: every feed,
+// with its subscription token and settings, and all of its events.
+type Backup struct {
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	Server    string       `json:"server,omitempty"` // cal URL the backup was taken from
+	Feeds     []FeedBackup `json:"feeds"`
+}
+
+// FeedBackup is one feed in a Backup.
+type FeedBackup struct {
+	Feed   Feed    `json:"feed"`
+	Events []Event `json:"events"`
+}
+
+// WriteBackup encodes a backup as indented JSON.
+func WriteBackup(w io.Writer, b *Backup) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(b); err != nil {
+		return fmt.Errorf("encode backup: %w", err)
+	}
+	return nil
+}
+
+// ReadBackup decodes a backup previously written by WriteBackup.
+func ReadBackup(r io.Reader) (*Backup, error) {
+	var b Backup
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("decode backup: %w", err)
+	}
+	if b.Version < 1 || b.Version > BackupVersion {
+		return nil, fmt.Errorf("decode backup: unsupported version %d (this pylon reads up to %d)", b.Version, BackupVersion)
+	}
+	return &b, nil
+}
@@ -0,0 +1,204 @@
+package cal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// CachedResponse is a listing as last returned by the server, with the
+// validators to revalidate it.
+type CachedResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// A Cache stores listings by key between runs. Implementations must be
+// safe for concurrent use.
+type Cache interface {
+	Get(key string) (CachedResponse, bool)
+	Put(key string, r CachedResponse) error
+}
+
+// WithCache makes ListFeeds and ListEvents send conditional requests
+// (If-None-Match / If-Modified-Since) and answer from cache when the server
+// replies 304 Not Modified. A listing that is unchanged since the client
+// last decoded it is not parsed again.
+func WithCache(cache Cache) Option {
+	return func(c *Client) { c.cache = cache }
+}
+
+// DirCache is a Cache keeping one JSON file per key in a directory.
+type DirCache struct {
+	dir string
+}
+
+// NewDirCache returns a Cache storing its entries in dir, which is created
+// on the first Put.
+func NewDirCache(dir string) *DirCache {
+	return &DirCache{dir: dir}
+}
+
+func (d *DirCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the entry for key. Missing and unreadable entries are both
+// a miss.
+func (d *DirCache) Get(key string) (CachedResponse, bool) {
+	var r CachedResponse
+	data, err := os.ReadFile(d.path(key))
+	if err != nil || json.Unmarshal(data, &r) != nil {
+		return CachedResponse{}, false
+	}
+	return r, true
+}
+
+// Put stores r under key, replacing the file atomically so a concurrent
+// reader never sees half an entry.
+func (d *DirCache) Put(key string, r CachedResponse) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), d.path(key))
+}
+
+// decoded is a listing the client has already parsed, kept so that a 304
+// for the same ETag costs no JSON decoding.
+type decoded struct {
+	etag  string
+	value any
+}
+
+// memo holds decoded listings by cache key.
+type memo struct {
+	mu      sync.Mutex
+	entries map[string]decoded
+}
+
+func (m *memo) get(key, etag string) (any, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.entries[key]
+	if !ok || etag == "" || d.etag != etag {
+		return nil, false
+	}
+	return d.value, true
+}
+
+func (m *memo) put(key, etag string, v any) {
+	if etag == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]decoded)
+	}
+	m.entries[key] = decoded{etag: etag, value: v}
+}
+
+// cacheKey identifies a listing: the same path on another server, or read
+// with another API key, is another entry.
+func (c *Client) cacheKey(path string) string {
+	return c.baseURL + path + "\x00" + c.apiKey
+}
+
+// list fetches a JSON array at path, revalidating a cached copy when the
+// client has a cache. The result is a fresh slice the caller may modify.
+func list[T any](c *Client, path string) ([]T, error) {
+	if c.cache == nil {
+		resp, err := c.get(path)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, parseError(resp)
+		}
+		var items []T
+		if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		return items, nil
+	}
+
+	key := c.cacheKey(path)
+	cached, hit := c.cache.Get(key)
+	header := http.Header{}
+	if hit {
+		if cached.ETag != "" {
+			header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := c.doHeader(http.MethodGet, path, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hit:
+		if v, ok := c.memo.get(key, cached.ETag); ok {
+			return slices.Clone(v.([]T)), nil
+		}
+		var items []T
+		if err := json.Unmarshal(cached.Body, &items); err != nil {
+			return nil, fmt.Errorf("decode cached response: %w", err)
+		}
+		c.memo.put(key, cached.ETag, items)
+		return slices.Clone(items), nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, parseError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var items []T
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	fresh := CachedResponse{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Body:         body,
+	}
+	if fresh.ETag != "" || fresh.LastModified != "" {
+		// A cache that cannot be written only costs the next run a full
+		// response, so it is not worth failing the listing over.
+		_ = c.cache.Put(key, fresh)
+		c.memo.put(key, fresh.ETag, items)
+	}
+	return slices.Clone(items), nil
+}
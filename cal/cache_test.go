@@ -0,0 +1,82 @@
+package cal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListFeedsCache(t *testing.T) {
+	body := `[{"id":"f1","name":"Team"}]`
+	etag := `"v1"`
+	var full, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	cache := NewDirCache(t.TempDir())
+	list := func(c *Client) []Feed {
+		t.Helper()
+		feeds, err := c.ListFeeds()
+		if err != nil {
+			t.Fatalf("ListFeeds: %v", err)
+		}
+		return feeds
+	}
+
+	c := NewClient(srv.URL, WithCache(cache))
+	if feeds := list(c); len(feeds) != 1 || feeds[0].Name != "Team" {
+		t.Fatalf("first listing = %+v", feeds)
+	}
+	feeds := list(c)
+	if len(feeds) != 1 || feeds[0].Name != "Team" {
+		t.Fatalf("revalidated listing = %+v", feeds)
+	}
+	feeds[0].Name = "changed by caller"
+
+	// A new client, as on the next run, revalidates the on-disk entry.
+	if feeds := list(NewClient(srv.URL, WithCache(cache))); feeds[0].Name != "Team" {
+		t.Errorf("listing from disk = %+v", feeds)
+	}
+	if full != 1 || notModified != 2 {
+		t.Errorf("full = %d, not modified = %d, want 1 and 2", full, notModified)
+	}
+
+	// A changed listing gets a new tag and replaces the cached one.
+	body, etag = `[{"id":"f1","name":"Team"},{"id":"f2","name":"Ops"}]`, `"v2"`
+	if feeds := list(c); len(feeds) != 2 {
+		t.Errorf("changed listing = %+v", feeds)
+	}
+	if feeds := list(c); len(feeds) != 2 || full != 2 {
+		t.Errorf("listing after change = %+v, %d full responses", feeds, full)
+	}
+
+	// Another API key is another cache entry.
+	list(NewClient(srv.URL, WithCache(cache), WithAPIKey("other")))
+	if full != 3 {
+		t.Errorf("full = %d, want a full response for a new key", full)
+	}
+}
+
+func TestDirCacheMiss(t *testing.T) {
+	cache := NewDirCache(t.TempDir())
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Get on an empty cache reported a hit")
+	}
+	want := CachedResponse{ETag: `"x"`, Body: []byte("[]")}
+	if err := cache.Put("k", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, ok := cache.Get("k")
+	if !ok || got.ETag != want.ETag || string(got.Body) != "[]" {
+		t.Errorf("Get = %+v, %v", got, ok)
+	}
+}
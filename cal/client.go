@@ -0,0 +1,671 @@
+package cal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/httpx"
+)
+
+// Client talks to the cal service API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retries    int
+	apiKey     string
+	authHeader string
+	readOnly   bool
+	cache      Cache
+	memo       memo
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRetries retries transient failures (429, 502, 503, 504) up to n times
+// with exponential backoff. The default is no retries.
+func WithRetries(n int) Option {
+	return func(c *Client) { c.retries = n }
+}
+
+// WithHTTPClient sends requests through hc instead of a default client,
+// e.g. to supply a custom transport. Options apply in order, so pass it
+// before WithTimeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithTimeout sets the per-request timeout (default 15s). A client given
+// with WithHTTPClient is copied rather than changed.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = d
+		c.httpClient = &hc
+	}
+}
+
+// WithAPIKey authenticates every request with key, sent as
+// "Authorization: Bearer <key>" unless WithAuthHeader names another header.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithAuthHeader sends the API key verbatim in the named header (e.g.
+// X-Api-Key) instead of as a bearer token. An empty name keeps the default.
+func WithAuthHeader(name string) Option {
+	return func(c *Client) { c.authHeader = name }
+}
+
+// WithReadOnly makes the client refuse every request that could change
+// something (anything but GET and HEAD) with ErrReadOnly, before it is
+// sent, so a powerful API key can be reused safely by dashboards.
+func WithReadOnly() Option {
+	return func(c *Client) { c.readOnly = true }
+}
+
+// NewClient creates a cal API client.
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Feed represents a calendar feed.
+type Feed struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"`
+
+	// How the feed's .ics output presents itself to calendar apps; see
+	// UpdateFeedRequest.
+	ProdID          string `json:"prodid,omitempty"`
+	CalendarName    string `json:"calendar_name,omitempty"`
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+	OmitAlarms      bool   `json:"omit_alarms,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateFeedResponse is the response from creating a feed.
+type CreateFeedResponse struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// Event represents a calendar event.
+type Event struct {
+	ID          string      `json:"id"`
+	FeedID      string      `json:"feed_id"`
+	Summary     string      `json:"summary"`
+	Description string      `json:"description"`
+	Location    string      `json:"location"`
+	URL         string      `json:"url"`
+	Start       time.Time   `json:"start"`
+	End         *time.Time  `json:"end,omitempty"`
+	AllDay      bool        `json:"all_day"`
+	Deadline    *time.Time  `json:"deadline,omitempty"`
+	Status      string      `json:"status"`
+	Categories  string      `json:"categories"`
+	ExternalID  string      `json:"external_id,omitempty"`
+	RRule       string      `json:"rrule,omitempty"`
+	ExDates     []time.Time `json:"exdates,omitempty"`
+	Alarms      []string    `json:"alarms,omitempty"`
+	Attachments []string    `json:"attachments,omitempty"` // file URLs, see pylon cal event attach
+	UID         string      `json:"uid,omitempty"`         // ICS UID; see ICalUID
+	Sequence    int         `json:"sequence,omitempty"`    // ICS SEQUENCE, raised on every change
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// ICalUID returns the UID the event is published under in the feed's ICS:
+// the one it was imported with, or else its ID. Calendar apps match
+// updates to the events they already have by UID and SEQUENCE, so both
+// must survive a round trip through another calendar.
+func (e *Event) ICalUID() string {
+	if e.UID != "" {
+		return e.UID
+	}
+	return e.ID
+}
+
+// UpdateFeedRequest changes a feed's settings. Empty fields are left as
+// they are.
+type UpdateFeedRequest struct {
+	Name string `json:"name,omitempty"`
+	// Slug replaces the feed's token with a readable one, which changes the
+	// subscribe URL.
+	Slug string `json:"slug,omitempty"`
+
+	// The .ics settings are left as they are when nil; an empty string
+	// restores the default.
+	//
+	// ProdID replaces pylon's PRODID. CalendarName is the name calendar
+	// apps show (X-WR-CALNAME) instead of Name. RefreshInterval is an RFC
+	// 5545 duration such as "PT1H", published as REFRESH-INTERVAL and
+	// X-PUBLISHED-TTL to tell apps how often to poll. OmitAlarms leaves
+	// the events' alarms out, for subscribers who set their own.
+	ProdID          *string `json:"prodid,omitempty"`
+	CalendarName    *string `json:"calendar_name,omitempty"`
+	RefreshInterval *string `json:"refresh_interval,omitempty"`
+	OmitAlarms      *bool   `json:"omit_alarms,omitempty"`
+}
+
+// CreateEventRequest is the payload for creating an event.
+type CreateEventRequest struct {
+	FeedID      string   `json:"feed_id"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Start       string   `json:"start"`
+	End         string   `json:"end,omitempty"`
+	AllDay      bool     `json:"all_day,omitempty"`
+	Deadline    string   `json:"deadline,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Categories  string   `json:"categories,omitempty"`
+	ExternalID  string   `json:"external_id,omitempty"`
+	RRule       string   `json:"rrule,omitempty"`
+	ExDates     []string `json:"exdates,omitempty"`
+	// Alarms are RFC 5545 TRIGGER durations relative to the start, e.g.
+	// "-PT15M" for 15 minutes before. The ICS feed carries one VALARM each.
+	Alarms []string `json:"alarms,omitempty"`
+	// Attachments are URLs of files such as flyers, published as ATTACH.
+	Attachments []string `json:"attachments,omitempty"`
+	// UID and Sequence keep the identity of an event imported from another
+	// calendar. The server raises Sequence past the stored one on every
+	// change, so it only needs setting to carry a higher one over.
+	UID      string `json:"uid,omitempty"`
+	Sequence int    `json:"sequence,omitempty"`
+}
+
+// SetDuration sets End to Start plus d. Start is an RFC 3339 time, whose
+// offset End keeps, or for all-day events a date (2006-01-02), in which
+// case d must be whole days and End is the exclusive end date.
+func (r *CreateEventRequest) SetDuration(d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("duration must be positive, got %s", d)
+	}
+	if start, err := time.Parse(time.RFC3339, r.Start); err == nil {
+		r.End = start.Add(d).Format(time.RFC3339)
+		return nil
+	}
+	start, err := time.Parse(time.DateOnly, r.Start)
+	if err != nil {
+		return fmt.Errorf("start %q is neither an RFC 3339 time nor a date", r.Start)
+	}
+	if d%(24*time.Hour) != 0 {
+		return fmt.Errorf("duration %s is not whole days, as a date start needs", d)
+	}
+	r.End = start.AddDate(0, 0, int(d/(24*time.Hour))).Format(time.DateOnly)
+	return nil
+}
+
+// SignedURL is a time-limited subscription URL issued by the server.
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SubscriberStats is what the server knows about who fetches a feed's ICS
+// URLs.
+type SubscriberStats struct {
+	FeedID string       `json:"feed_id"`
+	Tokens []TokenStats `json:"tokens"`
+}
+
+// TokenStats counts the fetches of one subscription token (the feed's
+// token or a signed URL).
+type TokenStats struct {
+	Token      string       `json:"token"`
+	Fetches    int          `json:"fetches"`
+	UserAgents []AgentStats `json:"user_agents"`
+	FirstFetch *time.Time   `json:"first_fetch,omitempty"`
+	LastFetch  *time.Time   `json:"last_fetch,omitempty"`
+}
+
+// AgentStats counts the fetches by one User-Agent, which roughly tells
+// subscribers (and calendar apps) apart.
+type AgentStats struct {
+	UserAgent string     `json:"user_agent"`
+	Fetches   int        `json:"fetches"`
+	LastFetch *time.Time `json:"last_fetch,omitempty"`
+}
+
+// ErrNotSupported is matched (via errors.Is) by API errors indicating the
+// server does not implement an endpoint, e.g. an older cal deployment.
+var ErrNotSupported = errors.New("not supported by this cal server")
+
+// ErrReadOnly is returned for a request a WithReadOnly client refused.
+var ErrReadOnly = errors.New("read-only mode")
+
+// APIError is returned when the API responds with an error.
+type APIError struct {
+	StatusCode int
+	Message    string
+
+	unsupported bool
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("cal api: %d %s", e.StatusCode, e.Message)
+}
+
+// Unwrap lets errors.Is(err, ErrNotSupported) detect missing endpoints.
+func (e *APIError) Unwrap() error {
+	if e.unsupported {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+// CreateFeed creates a new calendar feed. If slug is non-empty, it is used as
+// a readable token for the subscription URL (e.g. "my-calendar" ->
+// /my-calendar.ics). Otherwise the server generates a UUID token.
+func (c *Client) CreateFeed(name, slug string) (*CreateFeedResponse, error) {
+	payload := map[string]string{"name": name}
+	if slug != "" {
+		payload["slug"] = slug
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.post("/api/feeds", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseError(resp)
+	}
+
+	var feed CreateFeedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &feed, nil
+}
+
+// ListFeeds returns all feeds.
+func (c *Client) ListFeeds() ([]Feed, error) {
+	return list[Feed](c, "/api/feeds")
+}
+
+// DeleteFeed deletes a feed by ID.
+func (c *Client) DeleteFeed(id string) error {
+	resp, err := c.delete("/api/feeds/" + id)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseError(resp)
+	}
+	return nil
+}
+
+// RotateFeedToken replaces a feed's subscription token, invalidating the old
+// subscribe URL. If slug is non-empty it becomes the new readable token;
+// otherwise the server generates a random one. Servers without rotation
+// support return an error matching ErrNotSupported.
+func (c *Client) RotateFeedToken(id, slug string) (*CreateFeedResponse, error) {
+	payload := map[string]string{}
+	if slug != "" {
+		payload["slug"] = slug
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.post("/api/feeds/"+id+"/rotate-token", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var feed CreateFeedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &feed, nil
+}
+
+// UpdateFeed renames a feed or changes its slug without touching its
+// events. Servers without feed updates return an error matching
+// ErrNotSupported.
+func (c *Client) UpdateFeed(id string, req *UpdateFeedRequest) (*Feed, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.patch("/api/feeds/"+id, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var feed Feed
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &feed, nil
+}
+
+// CreateEvent creates a new event.
+func (c *Client) CreateEvent(req *CreateEventRequest) (*Event, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.post("/api/events", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, parseError(resp)
+	}
+
+	var event Event
+	if err := json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &event, nil
+}
+
+// UpsertEvent creates or replaces the event identified by a caller-chosen
+// external UID, so repeated runs of the same automation update one event
+// instead of creating duplicates. created reports whether a new event was
+// made. Servers without upsert support return an error matching
+// ErrNotSupported.
+func (c *Client) UpsertEvent(externalUID string, req *CreateEventRequest) (event *Event, created bool, err error) {
+	if externalUID == "" {
+		return nil, false, errors.New("external UID is required")
+	}
+	r := *req
+	r.ExternalID = externalUID
+	body, err := json.Marshal(&r)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.put("/api/events/external/"+url.PathEscape(externalUID), body)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusCreated:
+		created = true
+	default:
+		return nil, false, parseError(resp)
+	}
+
+	event = new(Event)
+	if err := json.NewDecoder(resp.Body).Decode(event); err != nil {
+		return nil, false, fmt.Errorf("decode response: %w", err)
+	}
+	return event, created, nil
+}
+
+// UpdateEvent replaces the details of the event with id. Servers without
+// the endpoint return an error matching ErrNotSupported; events with an
+// external ID can still be updated there with UpsertEvent.
+func (c *Client) UpdateEvent(id string, req *CreateEventRequest) (*Event, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.put("/api/events/"+url.PathEscape(id), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var event Event
+	if err := json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &event, nil
+}
+
+// ListEvents returns all events for a feed.
+func (c *Client) ListEvents(feedID string) ([]Event, error) {
+	return list[Event](c, "/api/feeds/"+feedID+"/events")
+}
+
+// GetEvent fetches a single event by ID. Servers without the endpoint
+// return an error matching ErrNotSupported.
+func (c *Client) GetEvent(id string) (*Event, error) {
+	resp, err := c.get("/api/events/" + url.PathEscape(id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var event Event
+	if err := json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &event, nil
+}
+
+// CancelEvent marks an event CANCELLED and raises its SEQUENCE, so
+// calendar apps subscribed to the feed drop it on their next refresh;
+// deleting it outright leaves them showing the last copy they saw.
+// Servers without the endpoint return an error matching ErrNotSupported.
+func (c *Client) CancelEvent(id string) (*Event, error) {
+	resp, err := c.post("/api/events/"+url.PathEscape(id)+"/cancel", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var event Event
+	if err := json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &event, nil
+}
+
+// DeleteEvent deletes an event by ID.
+func (c *Client) DeleteEvent(id string) error {
+	resp, err := c.delete("/api/events/" + id)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return parseError(resp)
+	}
+	return nil
+}
+
+// SubscribeURL returns the webcal subscription URL for a feed token.
+func (c *Client) SubscribeURL(token string) string {
+	return c.baseURL + "/" + token + ".ics"
+}
+
+// SignedSubscribeURL asks the server for an HMAC-signed subscription URL for
+// a feed that stops working after ttl. Servers without signing support
+// return an error matching ErrNotSupported.
+func (c *Client) SignedSubscribeURL(feedID string, ttl time.Duration) (*SignedURL, error) {
+	body, err := json.Marshal(map[string]int64{"expires_in": int64(ttl / time.Second)})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	resp, err := c.post("/api/feeds/"+feedID+"/signed-url", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, parseError(resp)
+	}
+
+	var signed SignedURL
+	if err := json.NewDecoder(resp.Body).Decode(&signed); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	// Servers may return a path relative to the API base.
+	if strings.HasPrefix(signed.URL, "/") {
+		signed.URL = c.baseURL + signed.URL
+	}
+	return &signed, nil
+}
+
+// Subscribers returns fetch statistics for a feed's subscription URLs.
+// Servers that don't track fetches return an error matching
+// ErrNotSupported.
+func (c *Client) Subscribers(feedID string) (*SubscriberStats, error) {
+	resp, err := c.get("/api/feeds/" + feedID + "/subscribers")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var stats SubscriberStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &stats, nil
+}
+
+// WebcalURL rewrites an http(s) subscription URL to the webcal:// scheme
+// that calendar apps register for.
+func WebcalURL(url string) string {
+	webcal := strings.Replace(url, "http://", "webcal://", 1)
+	return strings.Replace(webcal, "https://", "webcal://", 1)
+}
+
+// --- HTTP helpers ---
+
+func (c *Client) get(path string) (*http.Response, error) {
+	return c.do(http.MethodGet, path, nil)
+}
+
+func (c *Client) post(path string, body []byte) (*http.Response, error) {
+	return c.do(http.MethodPost, path, body)
+}
+
+func (c *Client) put(path string, body []byte) (*http.Response, error) {
+	return c.do(http.MethodPut, path, body)
+}
+
+func (c *Client) patch(path string, body []byte) (*http.Response, error) {
+	return c.do(http.MethodPatch, path, body)
+}
+
+func (c *Client) delete(path string) (*http.Response, error) {
+	return c.do(http.MethodDelete, path, nil)
+}
+
+// do sends a request to the API, retrying transient failures. A non-nil body
+// is sent as JSON.
+func (c *Client) do(method, path string, body []byte) (*http.Response, error) {
+	return c.doHeader(method, path, body, nil)
+}
+
+// doHeader is do with extra request headers.
+func (c *Client) doHeader(method, path string, body []byte, header http.Header) (*http.Response, error) {
+	if c.readOnly && method != http.MethodGet && method != http.MethodHead {
+		return nil, fmt.Errorf("%w: refusing %s %s", ErrReadOnly, method, path)
+	}
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.apiKey == "":
+	case c.authHeader != "":
+		req.Header.Set(c.authHeader, c.apiKey)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return httpx.Do(c.httpClient, req, c.retries)
+}
+
+func parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: errResp.Error}
+	}
+	// A bare (non-JSON) 404 comes from the router rather than a handler, so
+	// the route itself is missing. 405 and 501 say so explicitly.
+	unsupported := resp.StatusCode == http.StatusNotFound ||
+		resp.StatusCode == http.StatusMethodNotAllowed ||
+		resp.StatusCode == http.StatusNotImplemented
+	return &APIError{
+		StatusCode:  resp.StatusCode,
+		Message:     strings.TrimSpace(string(body)),
+		unsupported: unsupported,
+	}
+}
@@ -0,0 +1,1256 @@
+package cal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateFeed(t *testing.T) {
+	tests := []struct {
+		name       string
+		feedName   string
+		slug       string
+		status     int
+		response   string
+		wantErr    bool
+		wantFeedID string
+		wantSlug   bool // expect slug in request body
+	}{
+		{
+			name:       "success without slug",
+			feedName:   "Work",
+			slug:       "",
+			status:     http.StatusCreated,
+			response:   `{"id":"feed-1","name":"Work","token":"abc123","url":"/abc123.ics"}`,
+			wantErr:    false,
+			wantFeedID: "feed-1",
+		},
+		{
+			name:       "success with slug",
+			feedName:   "My Calendar",
+			slug:       "my-calendar",
+			status:     http.StatusCreated,
+			response:   `{"id":"feed-2","name":"My Calendar","token":"my-calendar","url":"/my-calendar.ics"}`,
+			wantErr:    false,
+			wantFeedID: "feed-2",
+			wantSlug:   true,
+		},
+		{
+			name:     "server error",
+			feedName: "Bad",
+			slug:     "",
+			status:   http.StatusInternalServerError,
+			response: `{"error":"database error"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "conflict",
+			feedName: "Duplicate",
+			slug:     "taken-slug",
+			status:   http.StatusConflict,
+			response: `{"error":"feed already exists"}`,
+			wantErr:  true,
+			wantSlug: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/feeds" {
+					t.Errorf("expected /api/feeds, got %s", r.URL.Path)
+				}
+
+				var body map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if body["name"] != tt.feedName {
+					t.Errorf("expected name %q, got %q", tt.feedName, body["name"])
+				}
+				if tt.wantSlug {
+					if body["slug"] != tt.slug {
+						t.Errorf("expected slug %q, got %q", tt.slug, body["slug"])
+					}
+				} else {
+					if _, ok := body["slug"]; ok {
+						t.Error("expected no slug in request body, but got one")
+					}
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			feed, err := client.CreateFeed(tt.feedName, tt.slug)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if feed.ID != tt.wantFeedID {
+				t.Errorf("expected feed ID %q, got %q", tt.wantFeedID, feed.ID)
+			}
+			if feed.Name != tt.feedName {
+				t.Errorf("expected feed name %q, got %q", tt.feedName, feed.Name)
+			}
+		})
+	}
+}
+
+func TestListFeeds(t *testing.T) {
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		status    int
+		response  string
+		wantErr   bool
+		wantCount int
+	}{
+		{
+			name:   "success with feeds",
+			status: http.StatusOK,
+			response: mustJSON(t, []Feed{
+				{ID: "f1", Name: "Work", Token: "tok1", CreatedAt: now, UpdatedAt: now},
+				{ID: "f2", Name: "Personal", Token: "tok2", CreatedAt: now, UpdatedAt: now},
+			}),
+			wantErr:   false,
+			wantCount: 2,
+		},
+		{
+			name:      "success empty",
+			status:    http.StatusOK,
+			response:  `[]`,
+			wantErr:   false,
+			wantCount: 0,
+		},
+		{
+			name:     "server error",
+			status:   http.StatusInternalServerError,
+			response: `{"error":"internal"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("expected GET, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/feeds" {
+					t.Errorf("expected /api/feeds, got %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			feeds, err := client.ListFeeds()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(feeds) != tt.wantCount {
+				t.Errorf("expected %d feeds, got %d", tt.wantCount, len(feeds))
+			}
+		})
+	}
+}
+
+func TestDeleteFeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		feedID  string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			feedID:  "feed-1",
+			status:  http.StatusNoContent,
+			wantErr: false,
+		},
+		{
+			name:    "not found",
+			feedID:  "nonexistent",
+			status:  http.StatusNotFound,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("expected DELETE, got %s", r.Method)
+				}
+				expectedPath := "/api/feeds/" + tt.feedID
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected %s, got %s", expectedPath, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				if tt.status != http.StatusNoContent {
+					_, _ = w.Write([]byte(`{"error":"not found"}`))
+				}
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			err := client.DeleteFeed(tt.feedID)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		response    string
+		wantSummary string
+		wantErr     bool
+		wantErrIs   error
+	}{
+		{
+			name:        "found",
+			status:      http.StatusOK,
+			response:    `{"id":"ev-1","feed_id":"feed-1","summary":"Standup","start":"2026-03-02T09:00:00Z","deadline":"2026-03-01T17:00:00Z","rrule":"FREQ=WEEKLY"}`,
+			wantSummary: "Standup",
+		},
+		{
+			name:     "missing event",
+			status:   http.StatusNotFound,
+			response: `{"error":"event not found"}`,
+			wantErr:  true,
+		},
+		{
+			name:      "old server",
+			status:    http.StatusMethodNotAllowed,
+			response:  "method not allowed",
+			wantErr:   true,
+			wantErrIs: ErrNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/api/events/ev-1" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			event, err := NewClient(srv.URL).GetEvent("ev-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("expected %v, got %v", tt.wantErrIs, err)
+				}
+				if tt.wantErrIs == nil && errors.Is(err, ErrNotSupported) {
+					t.Errorf("a missing event must not look like a missing endpoint: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.Summary != tt.wantSummary || event.Deadline == nil || event.RRule != "FREQ=WEEKLY" {
+				t.Errorf("unexpected event %+v", event)
+			}
+		})
+	}
+}
+
+func TestCancelEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		response  string
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name:     "cancelled",
+			status:   http.StatusOK,
+			response: `{"id":"ev-1","summary":"Standup","status":"CANCELLED","sequence":1}`,
+		},
+		{
+			name:     "missing event",
+			status:   http.StatusNotFound,
+			response: `{"error":"event not found"}`,
+			wantErr:  true,
+		},
+		{
+			name:      "old server",
+			status:    http.StatusNotFound,
+			response:  "404 page not found",
+			wantErr:   true,
+			wantErrIs: ErrNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/api/events/ev-1/cancel" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			event, err := NewClient(srv.URL).CancelEvent("ev-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if got := errors.Is(err, ErrNotSupported); got != (tt.wantErrIs != nil) {
+					t.Errorf("errors.Is(ErrNotSupported) = %v for %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.Status != "CANCELLED" || event.Sequence != 1 {
+				t.Errorf("unexpected event %+v", event)
+			}
+		})
+	}
+}
+
+func TestUpdateEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CreateEventRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case r.Method != http.MethodPut:
+			t.Errorf("unexpected method %s", r.Method)
+		case r.URL.Path == "/api/events/ev-1":
+			_, _ = fmt.Fprintf(w, `{"id":"ev-1","summary":%q,"sequence":2}`, req.Summary)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL)
+
+	e, err := client.UpdateEvent("ev-1", &CreateEventRequest{FeedID: "feed-1", Summary: "Moved", Start: "2026-03-02T09:00:00Z"})
+	if err != nil || e.Summary != "Moved" || e.Sequence != 2 {
+		t.Errorf("UpdateEvent = %+v, %v", e, err)
+	}
+	if _, err := client.UpdateEvent("ev-2", &CreateEventRequest{}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("UpdateEvent on an old server = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestRotateFeedToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		slug      string
+		status    int
+		response  string
+		wantToken string
+		wantErr   error
+	}{
+		{
+			name:      "random token",
+			status:    http.StatusOK,
+			response:  `{"id":"feed-1","name":"Work","token":"9c1e","url":"http://cal/9c1e.ics"}`,
+			wantToken: "9c1e",
+		},
+		{
+			name:      "chosen slug",
+			slug:      "work-2",
+			status:    http.StatusOK,
+			response:  `{"id":"feed-1","name":"Work","token":"work-2","url":"http://cal/work-2.ics"}`,
+			wantToken: "work-2",
+		},
+		{
+			name:     "slug taken",
+			slug:     "taken",
+			status:   http.StatusConflict,
+			response: `{"error":"slug already in use"}`,
+		},
+		{
+			name:     "old server",
+			status:   http.StatusNotFound,
+			response: "404 page not found",
+			wantErr:  ErrNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/feeds/feed-1/rotate-token" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				var body map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if body["slug"] != tt.slug {
+					t.Errorf("expected slug %q, got %q", tt.slug, body["slug"])
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			feed, err := NewClient(srv.URL).RotateFeedToken("feed-1", tt.slug)
+			if tt.wantToken == "" {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if feed.Token != tt.wantToken {
+				t.Errorf("expected token %q, got %q", tt.wantToken, feed.Token)
+			}
+		})
+	}
+}
+
+func TestUpdateFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      UpdateFeedRequest
+		wantBody map[string]string
+		status   int
+		response string
+		wantName string
+		wantErr  error
+	}{
+		{
+			name:     "rename",
+			req:      UpdateFeedRequest{Name: "Team Calendar"},
+			wantBody: map[string]string{"name": "Team Calendar"},
+			status:   http.StatusOK,
+			response: `{"id":"feed-1","name":"Team Calendar","token":"team"}`,
+			wantName: "Team Calendar",
+		},
+		{
+			name:     "new slug only",
+			req:      UpdateFeedRequest{Slug: "team-2"},
+			wantBody: map[string]string{"slug": "team-2"},
+			status:   http.StatusOK,
+			response: `{"id":"feed-1","name":"Team","token":"team-2"}`,
+			wantName: "Team",
+		},
+		{
+			name:     "slug taken",
+			req:      UpdateFeedRequest{Slug: "taken"},
+			wantBody: map[string]string{"slug": "taken"},
+			status:   http.StatusConflict,
+			response: `{"error":"slug already in use"}`,
+		},
+		{
+			name:     "old server",
+			req:      UpdateFeedRequest{Name: "x"},
+			wantBody: map[string]string{"name": "x"},
+			status:   http.StatusMethodNotAllowed,
+			response: "method not allowed",
+			wantErr:  ErrNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch {
+					t.Errorf("expected PATCH, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/feeds/feed-1" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				var body map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if !reflect.DeepEqual(body, tt.wantBody) {
+					t.Errorf("expected body %v, got %v", tt.wantBody, body)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			feed, err := NewClient(srv.URL).UpdateFeed("feed-1", &tt.req)
+			if tt.wantName == "" {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if feed.Name != tt.wantName {
+				t.Errorf("expected name %q, got %q", tt.wantName, feed.Name)
+			}
+		})
+	}
+}
+
+func TestCreateEvent(t *testing.T) {
+	now := time.Date(2026, 2, 1, 14, 0, 0, 0, time.UTC)
+	end := now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		req         *CreateEventRequest
+		status      int
+		response    string
+		wantErr     bool
+		wantEventID string
+	}{
+		{
+			name: "success",
+			req: &CreateEventRequest{
+				FeedID:  "feed-1",
+				Summary: "Meeting",
+				Start:   now.Format(time.RFC3339),
+				End:     end.Format(time.RFC3339),
+			},
+			status: http.StatusCreated,
+			response: mustJSON(t, Event{
+				ID: "evt-1", FeedID: "feed-1", Summary: "Meeting",
+				Start: now, End: &end, Status: "CONFIRMED",
+				CreatedAt: now, UpdatedAt: now,
+			}),
+			wantErr:     false,
+			wantEventID: "evt-1",
+		},
+		{
+			name: "bad request",
+			req: &CreateEventRequest{
+				FeedID:  "",
+				Summary: "No Feed",
+				Start:   now.Format(time.RFC3339),
+			},
+			status:   http.StatusBadRequest,
+			response: `{"error":"feed_id is required"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/events" {
+					t.Errorf("expected /api/events, got %s", r.URL.Path)
+				}
+
+				var body CreateEventRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if body.Summary != tt.req.Summary {
+					t.Errorf("expected summary %q, got %q", tt.req.Summary, body.Summary)
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			event, err := client.CreateEvent(tt.req)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if event.ID != tt.wantEventID {
+				t.Errorf("expected event ID %q, got %q", tt.wantEventID, event.ID)
+			}
+		})
+	}
+}
+
+func TestSetDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		duration time.Duration
+		wantEnd  string
+		wantErr  bool
+	}{
+		{name: "utc", start: "2025-07-01T10:00:00Z", duration: 45 * time.Minute, wantEnd: "2025-07-01T10:45:00Z"},
+		{name: "keeps offset", start: "2025-07-01T23:30:00+02:00", duration: time.Hour, wantEnd: "2025-07-02T00:30:00+02:00"},
+		{name: "date", start: "2025-07-01", duration: 48 * time.Hour, wantEnd: "2025-07-03"},
+		{name: "date needs whole days", start: "2025-07-01", duration: 90 * time.Minute, wantErr: true},
+		{name: "unparseable start", start: "tomorrow", duration: time.Hour, wantErr: true},
+		{name: "non-positive", start: "2025-07-01T10:00:00Z", duration: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateEventRequest{Start: tt.start}
+			err := req.SetDuration(tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetDuration error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if req.End != tt.wantEnd {
+				t.Errorf("End = %q, want %q", req.End, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestUpsertEvent(t *testing.T) {
+	now := time.Date(2026, 2, 1, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		uid         string
+		status      int
+		response    string
+		wantPath    string
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:        "created",
+			uid:         "ci-deploy-42",
+			status:      http.StatusCreated,
+			response:    mustJSON(t, Event{ID: "evt-1", ExternalID: "ci-deploy-42", Start: now}),
+			wantPath:    "/api/events/external/ci-deploy-42",
+			wantCreated: true,
+		},
+		{
+			name:     "updated",
+			uid:      "ci-deploy-42",
+			status:   http.StatusOK,
+			response: mustJSON(t, Event{ID: "evt-1", ExternalID: "ci-deploy-42", Start: now}),
+			wantPath: "/api/events/external/ci-deploy-42",
+		},
+		{
+			name:     "uid is escaped",
+			uid:      "build/7@ci",
+			status:   http.StatusOK,
+			response: mustJSON(t, Event{ID: "evt-2", ExternalID: "build/7@ci", Start: now}),
+			wantPath: "/api/events/external/build%2F7@ci",
+		},
+		{
+			name:     "old server",
+			uid:      "ci-deploy-42",
+			status:   http.StatusNotFound,
+			response: "404 page not found",
+			wantPath: "/api/events/external/ci-deploy-42",
+			wantErr:  ErrNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					t.Errorf("expected PUT, got %s", r.Method)
+				}
+				if r.URL.EscapedPath() != tt.wantPath {
+					t.Errorf("expected %s, got %s", tt.wantPath, r.URL.EscapedPath())
+				}
+				var body CreateEventRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if body.ExternalID != tt.uid {
+					t.Errorf("expected external_id %q, got %q", tt.uid, body.ExternalID)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			req := &CreateEventRequest{FeedID: "feed-1", Summary: "Deploy", Start: now.Format(time.RFC3339)}
+			event, created, err := client.UpsertEvent(tt.uid, req)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("expected created=%v, got %v", tt.wantCreated, created)
+			}
+			if event.ExternalID != tt.uid {
+				t.Errorf("expected external ID %q, got %q", tt.uid, event.ExternalID)
+			}
+			if req.ExternalID != "" {
+				t.Error("UpsertEvent modified the caller's request")
+			}
+		})
+	}
+
+	if _, _, err := NewClient("http://unused").UpsertEvent("", &CreateEventRequest{}); err == nil {
+		t.Error("expected error for empty external UID")
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	now := time.Date(2026, 2, 1, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		feedID    string
+		status    int
+		response  string
+		wantErr   bool
+		wantCount int
+	}{
+		{
+			name:   "success",
+			feedID: "feed-1",
+			status: http.StatusOK,
+			response: mustJSON(t, []Event{
+				{ID: "e1", FeedID: "feed-1", Summary: "Meeting", Start: now, Status: "CONFIRMED", CreatedAt: now, UpdatedAt: now},
+			}),
+			wantErr:   false,
+			wantCount: 1,
+		},
+		{
+			name:      "empty",
+			feedID:    "feed-2",
+			status:    http.StatusOK,
+			response:  `[]`,
+			wantErr:   false,
+			wantCount: 0,
+		},
+		{
+			name:     "not found",
+			feedID:   "nonexistent",
+			status:   http.StatusNotFound,
+			response: `{"error":"feed not found"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("expected GET, got %s", r.Method)
+				}
+				expectedPath := "/api/feeds/" + tt.feedID + "/events"
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected %s, got %s", expectedPath, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			events, err := client.ListEvents(tt.feedID)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != tt.wantCount {
+				t.Errorf("expected %d events, got %d", tt.wantCount, len(events))
+			}
+		})
+	}
+}
+
+func TestDeleteEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		eventID string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			eventID: "evt-1",
+			status:  http.StatusNoContent,
+			wantErr: false,
+		},
+		{
+			name:    "not found",
+			eventID: "nonexistent",
+			status:  http.StatusNotFound,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("expected DELETE, got %s", r.Method)
+				}
+				expectedPath := "/api/events/" + tt.eventID
+				if r.URL.Path != expectedPath {
+					t.Errorf("expected %s, got %s", expectedPath, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				if tt.status != http.StatusNoContent {
+					_, _ = w.Write([]byte(`{"error":"not found"}`))
+				}
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			err := client.DeleteEvent(tt.eventID)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSubscribeURL(t *testing.T) {
+	client := NewClient("https://cal.example.com")
+	got := client.SubscribeURL("my-token")
+	want := "https://cal.example.com/my-token.ics"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSubscribers(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		response    string
+		wantFetches int
+		wantErr     error
+	}{
+		{
+			name:   "tracked",
+			status: http.StatusOK,
+			response: `{"feed_id":"feed-1","tokens":[{"token":"team","fetches":42,` +
+				`"user_agents":[{"user_agent":"Google-Calendar-Importer","fetches":40},{"user_agent":"iOS/17.0 dataaccessd/1.0","fetches":2}],` +
+				`"first_fetch":"2026-01-01T00:00:00Z","last_fetch":"2026-02-01T08:00:00Z"}]}`,
+			wantFetches: 42,
+		},
+		{
+			name:     "old server",
+			status:   http.StatusNotFound,
+			response: "404 page not found",
+			wantErr:  ErrNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/api/feeds/feed-1/subscribers" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			stats, err := NewClient(srv.URL).Subscribers("feed-1")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(stats.Tokens) != 1 {
+				t.Fatalf("expected 1 token, got %d", len(stats.Tokens))
+			}
+			tok := stats.Tokens[0]
+			if tok.Fetches != tt.wantFetches || len(tok.UserAgents) != 2 || tok.LastFetch == nil {
+				t.Errorf("unexpected token stats %+v", tok)
+			}
+		})
+	}
+}
+
+func TestSignedSubscribeURL(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		response        string
+		wantErr         bool
+		wantUnsupported bool
+		wantURL         string
+	}{
+		{
+			name:     "absolute url",
+			status:   http.StatusCreated,
+			response: `{"url":"https://cal.example.com/tok.ics?exp=1&sig=abc","expires_at":"2026-02-01T00:00:00Z"}`,
+			wantURL:  "https://cal.example.com/tok.ics?exp=1&sig=abc",
+		},
+		{
+			name:     "relative url",
+			status:   http.StatusOK,
+			response: `{"url":"/tok.ics?exp=1&sig=abc","expires_at":"2026-02-01T00:00:00Z"}`,
+			wantURL:  "SERVER/tok.ics?exp=1&sig=abc",
+		},
+		{
+			name:            "server without signing",
+			status:          http.StatusNotFound,
+			response:        "404 page not found",
+			wantErr:         true,
+			wantUnsupported: true,
+		},
+		{
+			name:     "unknown feed",
+			status:   http.StatusNotFound,
+			response: `{"error":"feed not found"}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/feeds/feed-1/signed-url" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				var body map[string]int64
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if body["expires_in"] != 86400 {
+					t.Errorf("expected expires_in 86400, got %d", body["expires_in"])
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL)
+			signed, err := client.SignedSubscribeURL("feed-1", 24*time.Hour)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if got := errors.Is(err, ErrNotSupported); got != tt.wantUnsupported {
+					t.Errorf("errors.Is(ErrNotSupported) = %v, want %v", got, tt.wantUnsupported)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := strings.Replace(tt.wantURL, "SERVER", srv.URL, 1)
+			if signed.URL != want {
+				t.Errorf("expected URL %q, got %q", want, signed.URL)
+			}
+			if signed.ExpiresAt.IsZero() {
+				t.Error("expected expires_at to be set")
+			}
+		})
+	}
+}
+
+func TestWebcalURL(t *testing.T) {
+	tests := map[string]string{
+		"https://cal.example.com/a.ics": "webcal://cal.example.com/a.ics",
+		"http://localhost:8085/a.ics":   "webcal://localhost:8085/a.ics",
+	}
+	for in, want := range tests {
+		if got := WebcalURL(in); got != want {
+			t.Errorf("WebcalURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	err := &APIError{StatusCode: 404, Message: "not found"}
+	want := "cal api: 404 not found"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		wantMsg string
+	}{
+		{
+			name:    "json error response",
+			body:    `{"error":"bad request"}`,
+			status:  400,
+			wantMsg: "bad request",
+		},
+		{
+			name:    "plain text response",
+			body:    "something went wrong",
+			status:  500,
+			wantMsg: "something went wrong",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			recorder.WriteHeader(tt.status)
+			_, _ = recorder.Write([]byte(tt.body))
+			resp := recorder.Result()
+
+			apiErr := parseError(resp)
+			if apiErr == nil {
+				t.Fatal("expected error, got nil")
+			}
+			ae, ok := apiErr.(*APIError)
+			if !ok {
+				t.Fatalf("expected *APIError, got %T", apiErr)
+			}
+			if ae.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, ae.StatusCode)
+			}
+			if ae.Message != tt.wantMsg {
+				t.Errorf("expected message %q, got %q", tt.wantMsg, ae.Message)
+			}
+		})
+	}
+}
+
+func TestWithRetries(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithRetries(3))
+	if _, err := client.ListFeeds(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+
+	// Without retries the first 503 is returned.
+	calls = 0
+	client = NewClient(srv.URL)
+	if _, err := client.ListFeeds(); err == nil {
+		t.Fatal("expected error without retries, got nil")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewClient(srv.URL, WithTimeout(50*time.Millisecond))
+	if _, err := client.ListFeeds(); err == nil {
+		t.Fatal("expected timeout error, got nil")
+	}
+}
+
+func TestWithAPIKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		header string
+		want   string
+	}{
+		{name: "no key", header: "Authorization", want: ""},
+		{name: "bearer", opts: []Option{WithAPIKey("s3cret")}, header: "Authorization", want: "Bearer s3cret"},
+		{name: "custom header", opts: []Option{WithAPIKey("s3cret"), WithAuthHeader("X-Api-Key")}, header: "X-Api-Key", want: "s3cret"},
+		{name: "header without key", opts: []Option{WithAuthHeader("X-Api-Key")}, header: "X-Api-Key", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(tt.header)
+				_, _ = w.Write([]byte("[]"))
+			}))
+			defer srv.Close()
+
+			client := NewClient(srv.URL, tt.opts...)
+			if _, err := client.ListFeeds(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithReadOnly(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, WithReadOnly())
+	if _, err := client.ListFeeds(); err != nil {
+		t.Fatalf("ListFeeds: %v", err)
+	}
+	for name, call := range map[string]func() error{
+		"CreateFeed":  func() error { _, err := client.CreateFeed("Team", ""); return err },
+		"DeleteEvent": func() error { return client.DeleteEvent("e1") },
+		"UpdateFeed":  func() error { _, err := client.UpdateFeed("f1", &UpdateFeedRequest{}); return err },
+	} {
+		if err := call(); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s = %v, want ErrReadOnly", name, err)
+		}
+	}
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Errorf("server saw %v, want only the GET", methods)
+	}
+}
+
+// roundTripFunc counts requests passing through a custom transport.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestWithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	var calls int
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	client := NewClient(srv.URL, WithHTTPClient(hc))
+	if _, err := client.ListFeeds(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("custom transport saw %d requests, want 1", calls)
+	}
+}
+
+func TestWithTimeoutKeepsCallerClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	var calls int
+	hc := &http.Client{Timeout: time.Minute, Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	client := NewClient(srv.URL, WithHTTPClient(hc), WithTimeout(time.Second))
+	if hc.Timeout != time.Minute {
+		t.Errorf("caller's client timeout changed to %v", hc.Timeout)
+	}
+	if _, err := client.ListFeeds(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("custom transport saw %d requests, want 1", calls)
+	}
+}
+
+// mustJSON marshals v to JSON for use in test table data.
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal test data: %v", err)
+	}
+	return string(b)
+}
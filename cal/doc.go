@@ -0,0 +1,12 @@
+// Package cal is a client for the cal service API: feeds, events and their
+// subscription URLs.
+//
+//	client := cal.NewClient("https://cal.example.com",
+//		cal.WithAPIKey(os.Getenv("CAL_API_KEY")),
+//		cal.WithRetries(3),
+//	)
+//	feeds, err := client.ListFeeds()
+//
+// Endpoints an older cal server lacks fail with an error matching
+// ErrNotSupported; any other error response is an *APIError.
+package cal
@@ -0,0 +1,51 @@
+package cal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// A mirror is a copy of an event in another feed, kept in step with the
+// original by `pylon cal event mirror --sync --apply`. The link lives in the
+// mirror's external ID, so mirrors can be found from the events API alone
+// and re-mirroring the same event updates its copy instead of duplicating
+// it.
+
+const mirrorPrefix = "mirror:"
+
+// MirrorID returns the external ID of the mirror of event srcID in feedID.
+func MirrorID(srcID, feedID string) string {
+	return mirrorPrefix + srcID + "@" + feedID
+}
+
+// MirrorSource returns the ID of the event e mirrors, if it is a mirror.
+func (e *Event) MirrorSource() (string, bool) {
+	rest, ok := strings.CutPrefix(e.ExternalID, mirrorPrefix)
+	if !ok {
+		return "", false
+	}
+	src, _, ok := strings.Cut(rest, "@")
+	if !ok || src == "" {
+		return "", false
+	}
+	return src, true
+}
+
+// MirrorRequest returns the payload that creates or updates the mirror of
+// e in feedID. Pass it to UpsertEvent with MirrorID(e.ID, feedID).
+func (e *Event) MirrorRequest(feedID string) *CreateEventRequest {
+	req := e.CreateRequest(feedID)
+	req.ExternalID = MirrorID(e.ID, feedID)
+	// A mirror is an event of its own in its feed, with its own revisions.
+	req.UID, req.Sequence = "", 0
+	return req
+}
+
+// InSync reports whether mirror m carries the same details as its source.
+func InSync(m, source *Event) bool {
+	a, b := m.CreateRequest(""), source.CreateRequest("")
+	a.ExternalID, b.ExternalID = "", ""
+	a.UID, b.UID = "", ""
+	a.Sequence, b.Sequence = 0, 0
+	return reflect.DeepEqual(a, b)
+}
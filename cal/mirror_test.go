@@ -0,0 +1,57 @@
+package cal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMirrorSource(t *testing.T) {
+	tests := []struct {
+		externalID string
+		want       string
+		ok         bool
+	}{
+		{MirrorID("evt-1", "feed-2"), "evt-1", true},
+		{"mirror:@feed-2", "", false},
+		{"mirror:evt-1", "", false},
+		{"import-uid@example.com", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		e := Event{ExternalID: tt.externalID}
+		got, ok := e.MirrorSource()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MirrorSource(%q) = %q, %v; want %q, %v", tt.externalID, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMirrorRequestAndInSync(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	src := Event{
+		ID: "evt-1", FeedID: "team", Summary: "Launch", Start: start,
+		Location: "Hall", ExternalID: "uid-from-import", Status: "CONFIRMED",
+	}
+
+	req := src.MirrorRequest("public")
+	if req.FeedID != "public" || req.ExternalID != "mirror:evt-1@public" || req.Summary != "Launch" {
+		t.Errorf("MirrorRequest = %+v", req)
+	}
+
+	mirror := src
+	mirror.ID, mirror.FeedID, mirror.ExternalID = "evt-9", "public", req.ExternalID
+	if !InSync(&mirror, &src) {
+		t.Error("identical details should be in sync")
+	}
+
+	src.Status = "CANCELLED"
+	if InSync(&mirror, &src) {
+		t.Error("a cancelled source should put the mirror out of sync")
+	}
+	src.Status = "CONFIRMED"
+	moved := start.Add(time.Hour)
+	src.Start = moved
+	if InSync(&mirror, &src) {
+		t.Error("a moved source should put the mirror out of sync")
+	}
+}
@@ -0,0 +1,86 @@
+package cal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jredh-dev/pylon/internal/mergepatch"
+)
+
+// Filter selects events by one field, parsed from "field=value" by
+// ParseFilter. Category matches one of the event's categories, status and
+// external_id the whole value, and summary, location and description any
+// part of it; all ignore case except external_id.
+type Filter struct {
+	Field string
+	Value string
+}
+
+// filterFields are the fields a Filter can test.
+var filterFields = []string{"category", "status", "summary", "location", "description", "external_id"}
+
+// ParseFilter parses a "field=value" filter.
+func ParseFilter(s string) (Filter, error) {
+	field, value, ok := strings.Cut(s, "=")
+	field = strings.ToLower(strings.TrimSpace(field))
+	if !ok || field == "" {
+		return Filter{}, fmt.Errorf("invalid filter %q: want field=value", s)
+	}
+	for _, f := range filterFields {
+		if f == field {
+			return Filter{Field: field, Value: strings.TrimSpace(value)}, nil
+		}
+	}
+	return Filter{}, fmt.Errorf("invalid filter %q: field must be one of %s", s, strings.Join(filterFields, ", "))
+}
+
+// Matches reports whether e is selected by f.
+func (f Filter) Matches(e *Event) bool {
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(f.Value))
+	}
+	switch f.Field {
+	case "category":
+		return e.HasCategory(f.Value)
+	case "status":
+		return strings.EqualFold(e.Status, f.Value)
+	case "summary":
+		return contains(e.Summary)
+	case "location":
+		return contains(e.Location)
+	case "description":
+		return contains(e.Description)
+	case "external_id":
+		return e.ExternalID == f.Value
+	}
+	return false
+}
+
+// PatchRequest applies a JSON merge patch (RFC 7396) to e's CreateRequest
+// and returns the result, the payload for UpdateEvent. The patch names
+// fields as CreateEventRequest's JSON does, e.g. {"location": "Room 2",
+// "alarms": null}. Unknown fields are an error, as is changing feed_id:
+// an event is moved, not patched, into another feed.
+func (e *Event) PatchRequest(patch []byte) (*CreateEventRequest, error) {
+	doc, err := json.Marshal(e.CreateRequest(e.FeedID))
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	var req CreateEventRequest
+	if err := dec.Decode(&req); err != nil {
+		return nil, fmt.Errorf("patch: %w", err)
+	}
+	if req.FeedID != e.FeedID {
+		return nil, errors.New("patch: feed_id can't be changed; move the event instead")
+	}
+	return &req, nil
+}
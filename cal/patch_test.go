@@ -0,0 +1,60 @@
+package cal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilter(t *testing.T) {
+	e := &Event{Summary: "Team Standup", Location: "Berlin HQ", Status: "CONFIRMED", Categories: "meeting, Work", ExternalID: "ext-1"}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"category=work", true},
+		{"category=wor", false},
+		{"status=confirmed", true},
+		{"summary=standup", true},
+		{"location=munich", false},
+		{"external_id=ext-1", true},
+		{"external_id=EXT-1", false},
+		{" Location = berlin ", true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.filter, err)
+			continue
+		}
+		if got := f.Matches(e); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"category", "=work", "colour=red"} {
+		if _, err := ParseFilter(bad); err == nil {
+			t.Errorf("ParseFilter(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestPatchRequest(t *testing.T) {
+	end := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	e := &Event{
+		ID: "ev-1", FeedID: "feed-1", Summary: "Standup", Location: "Old office",
+		Start: end.Add(-time.Hour), End: &end, Alarms: []string{"-PT10M"}, ExternalID: "ext-1",
+	}
+
+	req, err := e.PatchRequest([]byte(`{"location": "New office", "alarms": null}`))
+	if err != nil {
+		t.Fatalf("PatchRequest: %v", err)
+	}
+	if req.Location != "New office" || req.Alarms != nil || req.Summary != "Standup" || req.End != "2026-03-02T10:00:00Z" || req.ExternalID != "ext-1" {
+		t.Errorf("patched request = %+v", req)
+	}
+
+	for _, bad := range []string{`{"colour": "red"}`, `{"feed_id": "feed-2"}`, `["location"]`, `{"start": 5}`} {
+		if _, err := e.PatchRequest([]byte(bad)); err == nil {
+			t.Errorf("PatchRequest(%s): expected an error", bad)
+		}
+	}
+}
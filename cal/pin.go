@@ -0,0 +1,44 @@
+package cal
+
+import "time"
+
+// A pin is an undated announcement ("Wifi password rotates Friday") kept on
+// a feed's pin board. Pins are stored as all-day events carrying
+// PinCategory, starting the day they were pinned and running through their
+// until date if they have one, so calendar apps show them as an all-day
+// banner; `pylon cal agenda --pins` lists every current pin whatever its
+// date.
+
+// PinCategory is the category that marks an event as a pin.
+const PinCategory = "pin"
+
+// Pinned reports whether e is a pin.
+func (e *Event) Pinned() bool {
+	return e.HasCategory(PinCategory)
+}
+
+// PinRequest returns the payload that pins text to feedID on the day of
+// pinned. A non-zero until keeps the pin up through that day; the event's
+// end is the following midnight, exclusive as in ICS.
+func PinRequest(feedID, text string, pinned, until time.Time) *CreateEventRequest {
+	day := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+	req := &CreateEventRequest{
+		FeedID:     feedID,
+		Summary:    text,
+		Start:      day(pinned).Format(time.RFC3339),
+		AllDay:     true,
+		Categories: PinCategory,
+	}
+	if !until.IsZero() {
+		req.End = day(until).AddDate(0, 0, 1).Format(time.RFC3339)
+	}
+	return req
+}
+
+// Expired reports whether pin e's until date has passed at now.
+func (e *Event) Expired(now time.Time) bool {
+	return e.End != nil && !now.Before(*e.End)
+}
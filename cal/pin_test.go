@@ -0,0 +1,53 @@
+package cal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPinned(t *testing.T) {
+	tests := []struct {
+		categories string
+		want       bool
+	}{
+		{"pin", true},
+		{"team, PIN", true},
+		{"pinned", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		e := Event{Categories: tt.categories}
+		if got := e.Pinned(); got != tt.want {
+			t.Errorf("Pinned(%q) = %v, want %v", tt.categories, got, tt.want)
+		}
+	}
+}
+
+func TestPinRequest(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	pinned := time.Date(2026, 3, 2, 15, 4, 0, 0, loc)
+
+	req := PinRequest("team", "Wifi password rotates Friday", pinned, time.Time{})
+	if req.Start != "2026-03-02T00:00:00+01:00" || req.End != "" || !req.AllDay || req.Categories != PinCategory {
+		t.Errorf("PinRequest without until = %+v", req)
+	}
+
+	req = PinRequest("team", "Office closed", pinned, time.Date(2026, 3, 6, 9, 0, 0, 0, loc))
+	if req.End != "2026-03-07T00:00:00+01:00" {
+		t.Errorf("End = %q, want the midnight after the until date", req.End)
+	}
+}
+
+func TestPinExpired(t *testing.T) {
+	end := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	e := Event{End: &end}
+	if e.Expired(end.Add(-time.Minute)) {
+		t.Error("expired before its end")
+	}
+	if !e.Expired(end) {
+		t.Error("not expired at its end")
+	}
+	if (&Event{}).Expired(end) {
+		t.Error("pin without until expired")
+	}
+}
@@ -0,0 +1,131 @@
+package cal
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SearchQuery selects events across feeds. Zero fields don't filter.
+type SearchQuery struct {
+	// Text is matched case-insensitively against the summary, description
+	// and location; every word must appear in one of them.
+	Text     string
+	FeedIDs  []string
+	Category string
+	From     time.Time // events starting at or after
+	To       time.Time // events starting before
+}
+
+// Matches reports whether e is selected by q.
+func (q *SearchQuery) Matches(e *Event) bool {
+	if len(q.FeedIDs) > 0 && !slices.Contains(q.FeedIDs, e.FeedID) {
+		return false
+	}
+	if q.Category != "" && !e.HasCategory(q.Category) {
+		return false
+	}
+	if !q.From.IsZero() && e.Start.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !e.Start.Before(q.To) {
+		return false
+	}
+	text := strings.ToLower(e.Summary + "\n" + e.Description + "\n" + e.Location)
+	for _, w := range strings.Fields(strings.ToLower(q.Text)) {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchEvents asks the server for the events matching q across all feeds.
+// Servers without a search endpoint return an error matching
+// ErrNotSupported; callers can then list each feed's events and filter
+// them with q.Matches.
+func (c *Client) SearchEvents(q *SearchQuery) ([]Event, error) {
+	v := url.Values{}
+	v.Set("q", q.Text)
+	for _, id := range q.FeedIDs {
+		v.Add("feed_id", id)
+	}
+	if q.Category != "" {
+		v.Set("category", q.Category)
+	}
+	if !q.From.IsZero() {
+		v.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		v.Set("to", q.To.Format(time.RFC3339))
+	}
+
+	resp, err := c.get("/api/search?" + v.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var events []Event
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return events, nil
+}
+
+// HasCategory reports whether name is one of e's comma-separated
+// categories, ignoring case.
+func (e *Event) HasCategory(name string) bool {
+	for _, c := range strings.Split(e.Categories, ",") {
+		if strings.EqualFold(strings.TrimSpace(c), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryList returns e's categories, trimmed, without empty entries.
+func (e *Event) CategoryList() []string {
+	var list []string
+	for _, c := range strings.Split(e.Categories, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// CategoryCount is a category and the number of events carrying it.
+type CategoryCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// CountCategories counts the categories used by events, most used first.
+// Spellings are counted separately, so "Work" and "work" both show up and
+// can be made consistent.
+func CountCategories(events []Event) []CategoryCount {
+	counts := make(map[string]int)
+	for i := range events {
+		for _, c := range events[i].CategoryList() {
+			counts[c]++
+		}
+	}
+	list := make([]CategoryCount, 0, len(counts))
+	for name, n := range counts {
+		list = append(list, CategoryCount{Name: name, Count: n})
+	}
+	slices.SortFunc(list, func(a, b CategoryCount) int {
+		return cmp.Or(b.Count-a.Count, strings.Compare(a.Name, b.Name))
+	})
+	return list
+}
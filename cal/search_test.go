@@ -0,0 +1,93 @@
+package cal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSearchQueryMatches(t *testing.T) {
+	start := time.Date(2026, 3, 5, 18, 0, 0, 0, time.UTC)
+	e := &Event{
+		FeedID:      "feed-1",
+		Summary:     "Board games night",
+		Description: "Bring snacks",
+		Location:    "Community Hall",
+		Categories:  "social, Games",
+		Start:       start,
+	}
+	tests := []struct {
+		name string
+		q    SearchQuery
+		want bool
+	}{
+		{name: "empty", q: SearchQuery{}, want: true},
+		{name: "summary", q: SearchQuery{Text: "board"}, want: true},
+		{name: "words across fields", q: SearchQuery{Text: "GAMES hall snacks"}, want: true},
+		{name: "missing word", q: SearchQuery{Text: "games pizza"}, want: false},
+		{name: "feed", q: SearchQuery{FeedIDs: []string{"feed-2", "feed-1"}}, want: true},
+		{name: "other feed", q: SearchQuery{FeedIDs: []string{"feed-2"}}, want: false},
+		{name: "category", q: SearchQuery{Category: "games"}, want: true},
+		{name: "other category", q: SearchQuery{Category: "work"}, want: false},
+		{name: "in range", q: SearchQuery{From: start, To: start.Add(time.Hour)}, want: true},
+		{name: "before range", q: SearchQuery{From: start.Add(time.Minute)}, want: false},
+		{name: "to is exclusive", q: SearchQuery{To: start}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Matches(e); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("q") != "games" || q.Get("category") != "social" || q.Get("from") != "2026-03-01T00:00:00Z" || len(q["feed_id"]) != 2 {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`[{"id":"e1","feed_id":"feed-1","summary":"Board games night"}]`))
+	}))
+	defer srv.Close()
+
+	events, err := NewClient(srv.URL).SearchEvents(&SearchQuery{Text: "games", FeedIDs: []string{"feed-1", "feed-2"}, Category: "social", From: from})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != "e1" {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestSearchEventsNotSupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL).SearchEvents(&SearchQuery{Text: "games"})
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestCountCategories(t *testing.T) {
+	events := []Event{
+		{Categories: "work, meeting"},
+		{Categories: "work"},
+		{Categories: "Work,,social"},
+		{},
+	}
+	got := CountCategories(events)
+	want := []CategoryCount{{"work", 2}, {"Work", 1}, {"meeting", 1}, {"social", 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("CountCategories = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,79 @@
+package cal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// APIVersion is the cal API revision this package speaks.
+const APIVersion = 1
+
+// Capabilities a server can report, for endpoints that older cal servers
+// lack.
+const (
+	CapUpsert      = "upsert"       // UpsertEvent
+	CapRotateToken = "rotate-token" // RotateFeedToken
+	CapUpdateFeed  = "update-feed"  // UpdateFeed
+	CapGetEvent    = "get-event"    // GetEvent
+	CapSignedURL   = "signed-url"   // SignedSubscribeURL
+	CapSubscribers = "subscribers"  // Subscribers
+	CapSearch      = "search"       // SearchEvents
+	CapCancel      = "cancel"       // CancelEvent
+	CapUpdateEvent = "update-event" // UpdateEvent
+)
+
+// Capabilities lists every capability this package can use, in display
+// order.
+var Capabilities = []string{
+	CapUpsert, CapRotateToken, CapUpdateFeed, CapGetEvent,
+	CapSignedURL, CapSubscribers, CapSearch, CapCancel, CapUpdateEvent,
+}
+
+// ServerInfo describes a cal server.
+type ServerInfo struct {
+	Version string `json:"version"`
+	// APIVersion is the newest API revision the server speaks, and
+	// MinAPIVersion the oldest it still accepts from clients.
+	APIVersion    int      `json:"api_version"`
+	MinAPIVersion int      `json:"min_api_version,omitempty"`
+	Capabilities  []string `json:"capabilities"`
+}
+
+// Supports reports whether the server lists capability.
+func (s *ServerInfo) Supports(capability string) bool {
+	return slices.Contains(s.Capabilities, capability)
+}
+
+// Compatibility describes how well this client and the server fit:
+// "" when they match, otherwise a warning.
+func (s *ServerInfo) Compatibility() string {
+	switch {
+	case s.MinAPIVersion > APIVersion:
+		return fmt.Sprintf("pylon is too old for this server: it speaks cal API v%d, the server needs v%d or newer", APIVersion, s.MinAPIVersion)
+	case s.APIVersion > 0 && s.APIVersion < APIVersion:
+		return fmt.Sprintf("the server is older than pylon: it speaks cal API v%d, pylon v%d; some commands will report they are not supported", s.APIVersion, APIVersion)
+	}
+	return ""
+}
+
+// ServerInfo fetches the server's version and capabilities. Servers that
+// predate the endpoint return an error matching ErrNotSupported.
+func (c *Client) ServerInfo() (*ServerInfo, error) {
+	resp, err := c.get("/api/version")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseError(resp)
+	}
+
+	var info ServerInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &info, nil
+}
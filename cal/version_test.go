@@ -0,0 +1,61 @@
+package cal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/version" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"version":"1.4.0","api_version":1,"capabilities":["upsert","search"]}`))
+	}))
+	defer srv.Close()
+
+	info, err := NewClient(srv.URL).ServerInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Version != "1.4.0" || !info.Supports(CapSearch) || info.Supports(CapSignedURL) {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if c := info.Compatibility(); c != "" {
+		t.Errorf("Compatibility = %q, want none", c)
+	}
+}
+
+func TestServerInfoNotSupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := NewClient(srv.URL).ServerInfo(); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestCompatibility(t *testing.T) {
+	tests := []struct {
+		name string
+		info ServerInfo
+		want string
+	}{
+		{name: "same", info: ServerInfo{APIVersion: APIVersion}, want: ""},
+		{name: "newer but compatible", info: ServerInfo{APIVersion: APIVersion + 1, MinAPIVersion: APIVersion}, want: ""},
+		{name: "client too old", info: ServerInfo{APIVersion: APIVersion + 2, MinAPIVersion: APIVersion + 1}, want: "pylon is too old"},
+		{name: "unknown API version", info: ServerInfo{Version: "0.9"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.info.Compatibility()
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("Compatibility = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/agenda"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalAgenda prints upcoming events across feeds, grouped by day.
+func runCalAgenda(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "agenda")
+	fs.noArgs()
+	days := fs.Int("days", 7)
+	feedIDs := fs.Strings("feed")
+	showPins := fs.Bool("pins")
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("list feeds: %v", err)
+	}
+	if len(feedIDs) > 0 {
+		var picked []cal.Feed
+		for _, id := range feedIDs {
+			f := filterFeeds(feeds, id)
+			if len(f) == 0 {
+				fatal("feed not found: %s", id)
+			}
+			picked = append(picked, f...)
+		}
+		feeds = picked
+	}
+
+	names := map[string]string{}
+	var events []cal.Event
+	for _, f := range feeds {
+		names[f.ID] = f.Name
+		evs, err := client.ListEvents(f.ID)
+		if err != nil {
+			fatal("list events for %s: %v", f.ID, err)
+		}
+		events = append(events, evs...)
+	}
+
+	now := time.Now()
+	if showPins {
+		var pins []cal.Event
+		if pins, events = agenda.Pins(events, now); len(pins) > 0 {
+			fmt.Print(agenda.FormatPins(pins, names))
+			fmt.Println()
+		}
+	}
+	list := agenda.Build(events, now, days, time.Local)
+	if len(list) == 0 {
+		fmt.Println(i18n.T("agenda.none", days))
+		return
+	}
+	fmt.Print(agenda.Format(list, now, time.Local, names))
+}
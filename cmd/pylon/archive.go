@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/backup"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/plan"
+	"github.com/jredh-dev/pylon/internal/recur"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runCalArchive exports events that started before a cutoff to one JSON file
+// per feed, then deletes them from the live feed so ICS generation stays fast.
+func runCalArchive(client *cal.Client, args []string) {
+	if len(args) > 0 && args[0] == "restore" {
+		runCalArchiveRestore(client, args[1:])
+		return
+	}
+
+	fs := parseFlags(args, "cal", "archive")
+	fs.noArgs()
+	before, outDir, feedID := fs.String("before", ""), fs.String("out", ""), fs.String("feed", "")
+	dryRun, yes := fs.Bool("dry-run"), fs.Bool("yes")
+	if before == "" || outDir == "" {
+		fatal("usage: pylon cal archive --before <age|date> --out <dir> [--feed <id>] [--dry-run] [--yes]")
+	}
+
+	now := time.Now()
+	cutoff, err := timeutil.ParseCutoff(before, now, time.Local)
+	if err != nil {
+		fatal("archive: %v", err)
+	}
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("list feeds: %v", err)
+	}
+	if feedID != "" {
+		feeds = filterFeedsStrict(feeds, feedID)
+		if len(feeds) == 0 {
+			fatal("feed not found: %s", feedID)
+		}
+	}
+
+	if !dryRun && !yes {
+		// List what would go first: the events are deleted once archived.
+		n, _, err := archiveEvents(client, feeds, cutoff, outDir, true, now)
+		if err != nil {
+			fatal("archive: %v", err)
+		}
+		if n > 0 {
+			confirmDelete(false, i18n.T("archive.confirm", n))
+		}
+	}
+
+	archived, failed, err := archiveEvents(client, feeds, cutoff, outDir, dryRun, now)
+	if err != nil {
+		fatal("archive: %v", err)
+	}
+	if dryRun {
+		fmt.Println(i18n.T("archive.dry_run", archived, cutoff.Format(time.RFC3339)))
+		return
+	}
+	fmt.Println(i18n.T("archive.summary", archived, failed))
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// archiveEvents writes the events of each feed that started before cutoff,
+// recurring ones only once their last occurrence has, to a JSON file in
+// outDir, then deletes them, printing a line per feed.
+// With dryRun it only reports what would be archived. Deletions that fail
+// are logged and counted; any other error stops the run.
+func archiveEvents(client *cal.Client, feeds []cal.Feed, cutoff time.Time, outDir string, dryRun bool, now time.Time) (archived, failed int, err error) {
+	if !dryRun {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	for _, f := range feeds {
+		events, err := client.ListEvents(f.ID)
+		if err != nil {
+			return archived, failed, fmt.Errorf("list events for %s: %w", f.ID, err)
+		}
+		var old []cal.Event
+		for _, e := range events {
+			if recur.EndsBefore(e, cutoff) {
+				old = append(old, e)
+			}
+		}
+		if len(old) == 0 {
+			continue
+		}
+
+		if dryRun {
+			fmt.Println(i18n.T("archive.would", f.Name, len(old)))
+			archived += len(old)
+			continue
+		}
+
+		path := filepath.Join(outDir, fmt.Sprintf("%s-%s.json", f.ID, now.UTC().Format("20060102T150405Z")))
+		a := &cal.Archive{Feed: f, ArchivedAt: now.UTC(), Before: cutoff, Events: old}
+		if err := writeArchiveFile(path, a); err != nil {
+			return archived, failed, fmt.Errorf("archive %s: %w", f.ID, err)
+		}
+
+		// Only delete once the archive is safely on disk.
+		for _, e := range old {
+			if err := client.DeleteEvent(e.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "pylon: delete event %s: %v\n", e.ID, err)
+				failed++
+				continue
+			}
+			archived++
+		}
+		fmt.Println(i18n.T("archive.feed", f.Name, len(old), path))
+	}
+	return archived, failed, nil
+}
+
+// runCalArchiveRestore recreates archived events, optionally limited to a
+// start-time range and redirected to a different feed. Events already in
+// the feed are matched as pylon cal restore matches them and updated or
+// left alone, so restoring the same archive twice changes nothing.
+func runCalArchiveRestore(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "archive", "restore")
+	feedID, from, to := fs.String("feed", ""), fs.String("from", ""), fs.String("to", "")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal archive restore <file|dir> [--feed <id>] [--from <date>] [--to <date>]")
+	}
+
+	feedID = resolveFeedStrict(client, feedID)
+
+	now := time.Now()
+	var fromT, toT time.Time
+	var err error
+	if from != "" {
+		if fromT, err = timeutil.ParseCutoff(from, now, time.Local); err != nil {
+			fatal("restore: %v", err)
+		}
+	}
+	if to != "" {
+		if toT, err = timeutil.ParseCutoff(to, now, time.Local); err != nil {
+			fatal("restore: %v", err)
+		}
+	}
+
+	files, err := archiveFiles(fs.args[0])
+	if err != nil {
+		fatal("restore: %v", err)
+	}
+
+	// A feed that cannot be restored is reported and skipped so the rest
+	// of a directory still goes through.
+	var changes, restored, failed int
+	var skipped []string
+	for _, file := range files {
+		a, err := readArchiveFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: restore %s: %v\n", file, err)
+			skipped = append(skipped, file)
+			continue
+		}
+		target := a.Feed.ID
+		if feedID != "" {
+			target = feedID
+		}
+		var events []cal.Event
+		for _, e := range a.Events {
+			if !fromT.IsZero() && e.Start.Before(fromT) {
+				continue
+			}
+			if !toT.IsZero() && !e.Start.Before(toT) {
+				continue
+			}
+			events = append(events, e)
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		// Events restored before are found again rather than duplicated.
+		have, err := client.ListEvents(target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: restore %s: list events for %s: %v\n", file, target, err)
+			skipped = append(skipped, file)
+			continue
+		}
+		p := backup.Events(client, target, events, have)
+		changes += len(p.Changes)
+		n, bad := p.Apply(func(c plan.Change, err error) {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "pylon: restore event %q: %v\n", c.Title, err)
+			}
+		})
+		restored += n
+		failed += bad
+	}
+
+	if changes == 0 && len(skipped) == 0 {
+		fmt.Println(i18n.T("plan.none"))
+		return
+	}
+	fmt.Println(i18n.T("archive.restored", restored, failed))
+	if len(skipped) > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("archive.skipped", len(skipped), strings.Join(skipped, ", ")))
+	}
+	if failed > 0 || len(skipped) > 0 {
+		exit(1)
+	}
+}
+
+// archiveFiles returns path itself if it is a file, or every *.json file in
+// it (sorted) if it is a directory.
+func archiveFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// writeArchiveFile writes an archive via a temp file and rename so a crash
+// never leaves a truncated archive behind.
+func writeArchiveFile(path string, a *cal.Archive) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := cal.WriteArchive(tmp, a); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func readArchiveFile(path string) (*cal.Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return cal.ReadArchive(f)
+}
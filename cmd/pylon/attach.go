@@ -0,0 +1,81 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalEventAttach uploads files to the attachment channel on Discord and
+// adds their CDN URLs to an event, so flyers need no separate hosting.
+// With --refresh it renews the event's Discord links instead, which
+// Discord expires after about a day.
+func runCalEventAttach(cfg *config.Config, client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "event", "attach")
+	paths, channel, refresh := fs.Strings("file"), fs.String("channel", ""), fs.Bool("refresh")
+	if len(fs.args) != 1 || (len(paths) == 0) == !refresh {
+		fatal("usage: pylon cal event attach <id> --file <path>... [--channel <id>], or pylon cal event attach <id> --refresh")
+	}
+	id := fs.args[0]
+
+	e, err := getEvent(client, id)
+	if err != nil {
+		fatal("attach: %v", err)
+	}
+	dc := newDiscordClient(cfg)
+
+	req := e.CreateRequest(e.FeedID)
+	if refresh {
+		refreshed, err := dc.RefreshAttachmentURLs(e.Attachments)
+		if err != nil {
+			fatal("attach: refresh: %v", err)
+		}
+		n := 0
+		for i, a := range req.Attachments {
+			if r, ok := refreshed[a]; ok && r != a {
+				req.Attachments[i] = r
+				n++
+			}
+		}
+		if n == 0 {
+			fmt.Println(i18n.T("attach.none"))
+			return
+		}
+		if _, err := updateEvent(client, e, req); err != nil {
+			fatal("attach: %v", err)
+		}
+		fmt.Println(i18n.T("attach.refreshed", n))
+		return
+	}
+
+	channel = cfg.Channel(cmp.Or(channel, cfg.DiscordAttachmentChannel))
+	if channel == "" {
+		fatal("attach: no channel to upload to; set discord.attachment_channel or pass --channel")
+	}
+	var files []discord.Attachment
+	for _, p := range paths {
+		f, err := discord.LoadAttachment(p)
+		if err != nil {
+			fatal("attach: %v", err)
+		}
+		files = append(files, f)
+	}
+	caption := fmt.Sprintf("%s (%s)", e.Summary, e.Start.Local().Format("2006-01-02 15:04"))
+	msg, err := dc.SendChannelMessageFiles(channel, caption, "", files)
+	if err != nil {
+		fatal("attach: upload: %v", err)
+	}
+	for _, a := range msg.Attachments {
+		req.Attachments = append(req.Attachments, a.URL)
+	}
+	if _, err := updateEvent(client, e, req); err != nil {
+		fatal("attach: uploaded to message %s but could not update the event: %v", msg.ID, err)
+	}
+	for _, a := range msg.Attachments {
+		fmt.Println(a.URL)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/digest"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// auditor prints one line per config entry: ok, DEAD when the service says
+// it no longer exists or is no longer accepted, or ? when it couldn't be
+// checked either way.
+type auditor struct {
+	checked, dead, unknown int
+}
+
+func (a *auditor) report(status, entry, detail string) {
+	switch status {
+	case "DEAD":
+		a.dead++
+	case "?":
+		a.unknown++
+	}
+	a.checked++
+	fmt.Printf("%-5s %-22s %s\n", status, entry, detail)
+}
+
+// result reports err against entry, telling rejections (401, 403, 404)
+// apart from failures that say nothing about the entry itself.
+func (a *auditor) result(entry string, err error, ok string) {
+	switch {
+	case err == nil:
+		a.report("ok", entry, ok)
+	case isDead(err):
+		a.report("DEAD", entry, errorLine(err))
+	default:
+		a.report("?", entry, errorLine(err))
+	}
+}
+
+func isDead(err error) bool {
+	var status int
+	var calErr *cal.APIError
+	var discordErr *discord.APIError
+	switch {
+	case errors.As(err, &calErr):
+		status = calErr.StatusCode
+	case errors.As(err, &discordErr):
+		status = discordErr.StatusCode
+	}
+	return status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound
+}
+
+// runAuditConfig verifies every credential and ID in the config, and in a
+// digest routes file if given, against the live services.
+func runAuditConfig(args []string) {
+	fs := parseFlags(args, "audit-config")
+	fs.noArgs()
+	routesFile := fs.String("routes", "")
+	cfg := loadConfig()
+	a := &auditor{}
+
+	feeds := a.auditCal(cfg)
+	bot := a.auditDiscord(cfg)
+	if routesFile != "" {
+		a.auditRoutes(cfg, routesFile, feeds, bot)
+	}
+
+	fmt.Println()
+	fmt.Println(i18n.T("audit.summary", a.checked, a.dead, a.unknown))
+	if a.dead > 0 {
+		exit(1)
+	}
+}
+
+// auditCal checks the cal URL and API key, returning the IDs of the feeds
+// that exist, or nil if they couldn't be listed.
+func (a *auditor) auditCal(cfg *config.Config) map[string]bool {
+	client := newCalClient(cfg, cfg.CalURL, cal.WithTimeout(doctorTimeout))
+	feeds, err := client.ListFeeds()
+	var apiErr *cal.APIError
+	switch {
+	case err == nil:
+		a.report("ok", "cal.url", fmt.Sprintf("%s: %d feed(s)", cfg.CalURL, len(feeds)))
+		if cfg.CalAPIKey != "" {
+			a.report("ok", "cal.api_key", "accepted")
+		}
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		a.report("ok", "cal.url", cfg.CalURL)
+		a.report("DEAD", "cal.api_key", errorLine(err))
+		return nil
+	case errors.Is(err, cal.ErrNotSupported):
+		a.report("DEAD", "cal.url", cfg.CalURL+" doesn't serve /api/feeds")
+		return nil
+	default:
+		a.report("?", "cal.url", fmt.Sprintf("%s: %v", cfg.CalURL, err))
+		return nil
+	}
+
+	ids := make(map[string]bool, len(feeds))
+	for _, f := range feeds {
+		ids[f.ID] = true
+	}
+	return ids
+}
+
+// auditDiscord checks the webhook, bot token, guild and channel, returning a
+// client for further checks if the bot token works.
+func (a *auditor) auditDiscord(cfg *config.Config) *discord.Client {
+	client := newDiscordClient(cfg)
+
+	if cfg.DiscordWebhook != "" {
+		w, err := client.Webhook()
+		ok := ""
+		if err == nil {
+			ok = fmt.Sprintf("%q posts to channel %s", w.Name, w.ChannelID)
+		}
+		a.result("discord.webhook", err, ok)
+	}
+
+	if cfg.DiscordBotToken == "" {
+		if cfg.DiscordGuildID != "" || cfg.DiscordChannelID != "" {
+			fmt.Fprintln(os.Stderr, "pylon: no discord.bot_token; guild_id and channel_id can't be checked")
+		}
+		return nil
+	}
+	u, err := client.CurrentUser()
+	if err != nil {
+		a.result("discord.bot_token", err, "")
+		return nil
+	}
+	a.report("ok", "discord.bot_token", "logged in as "+u.DisplayName())
+
+	if cfg.DiscordGuildID != "" {
+		g, err := client.GetGuild(cfg.DiscordGuildID)
+		ok := ""
+		if err == nil {
+			ok = fmt.Sprintf("%s (%s)", cfg.DiscordGuildID, g.Name)
+		}
+		a.result("discord.guild_id", err, ok)
+	}
+	if cfg.DiscordChannelID != "" {
+		ch, err := client.GetChannel(cfg.DiscordChannelID)
+		switch {
+		case err != nil:
+			a.result("discord.channel_id", err, "")
+		case cfg.DiscordGuildID != "" && ch.GuildID != cfg.DiscordGuildID:
+			a.report("DEAD", "discord.channel_id", fmt.Sprintf("#%s is in guild %s, not discord.guild_id", ch.Name, ch.GuildID))
+		default:
+			a.report("ok", "discord.channel_id", fmt.Sprintf("%s (#%s)", cfg.DiscordChannelID, ch.Name))
+		}
+	}
+	return client
+}
+
+// auditRoutes checks the feeds and destinations of a digest routes file.
+// feeds and bot are nil when cal or the bot token couldn't be verified.
+func (a *auditor) auditRoutes(cfg *config.Config, path string, feeds map[string]bool, bot *discord.Client) {
+	f, err := os.Open(path)
+	if err != nil {
+		fatal("routes: %v", err)
+	}
+	routes, err := digest.ParseRoutes(f)
+	f.Close()
+	if err != nil {
+		fatal("routes: %s: %v", path, err)
+	}
+
+	for _, r := range routes {
+		entry := fmt.Sprintf("%s:%d", path, r.Line)
+		for _, id := range r.Feeds {
+			switch {
+			case feeds == nil:
+				a.report("?", entry, "feed "+id+": cal not reachable")
+			case !feeds[id]:
+				a.report("DEAD", entry, "feed "+id+" doesn't exist")
+			default:
+				a.report("ok", entry, "feed "+id)
+			}
+		}
+
+		switch {
+		case r.To.Webhook != "":
+			_, err := discord.NewClient("", r.To.Webhook, discordOptions(cfg)...).Webhook()
+			a.result(entry, err, r.To.String())
+		case r.To.Channel != "" && bot == nil:
+			a.report("?", entry, r.To.String()+": no working bot token")
+		case r.To.Channel != "":
+			_, err := bot.GetChannel(r.To.Channel)
+			a.result(entry, err, r.To.String())
+		}
+	}
+}
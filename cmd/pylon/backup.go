@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/backup"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/plan"
+	"github.com/jredh-dev/pylon/internal/platform"
+)
+
+// runCalBackup implements `pylon cal backup`: every feed, with its token
+// and settings, and all of its events, in one JSON file. url is the cal
+// service's, recorded in the backup.
+func runCalBackup(client *cal.Client, url string, args []string) {
+	fs := parseFlags(args, "cal", "backup")
+	fs.noArgs()
+	out := fs.String("out", "")
+	if out == "" {
+		fatal("usage: pylon cal backup --out <file|-> [--feed <id>]...")
+	}
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("backup: %v", err)
+	}
+	if refs := fs.Strings("feed"); len(refs) > 0 {
+		ids := matchFeeds(feeds, refs)
+		feeds = slices.DeleteFunc(feeds, func(f cal.Feed) bool { return !slices.Contains(ids, f.ID) })
+	}
+
+	b := &cal.Backup{Version: cal.BackupVersion, CreatedAt: time.Now().UTC(), Server: url}
+	count := 0
+	for _, f := range feeds {
+		events, err := client.ListEvents(f.ID)
+		if err != nil {
+			fatal("backup: list events for %s: %v", f.ID, err)
+		}
+		b.Feeds = append(b.Feeds, cal.FeedBackup{Feed: f, Events: events})
+		count += len(events)
+	}
+
+	if out == "-" {
+		if err := cal.WriteBackup(os.Stdout, b); err != nil {
+			fatal("backup: %v", err)
+		}
+		fmt.Fprintln(os.Stderr, i18n.T("backup.written", len(b.Feeds), count, "stdout"))
+		return
+	}
+	if err := writeBackupFile(out, b); err != nil {
+		fatal("backup: %v", err)
+	}
+	fmt.Println(i18n.T("backup.written", len(b.Feeds), count, out))
+}
+
+// writeBackupFile writes a backup via a temp file and rename, as
+// writeArchiveFile does. The temp file is only readable by its owner,
+// which the backup keeps, since feed tokens are secrets.
+func writeBackupFile(path string, b *cal.Backup) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := cal.WriteBackup(tmp, b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// runCalRestore implements `pylon cal restore`: recreate the feeds and
+// events of a backup, printing the plan unless --apply is given.
+func runCalRestore(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "restore")
+	planOnly, apply := fs.Bool("plan"), fs.Bool("apply")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal restore <file|-> [--feed <id>]... [--plan | --apply]")
+	}
+	if planOnly && apply {
+		fatal("use either --plan or --apply, not both")
+	}
+
+	var b *cal.Backup
+	var err error
+	if path := fs.args[0]; path == "-" {
+		b, err = cal.ReadBackup(os.Stdin)
+	} else {
+		b, err = readBackupFile(path)
+	}
+	if err != nil {
+		fatal("restore: %v", err)
+	}
+	if refs := fs.Strings("feed"); len(refs) > 0 {
+		// --feed names feeds as they are in the backup.
+		backedUp := make([]cal.Feed, len(b.Feeds))
+		for i, fb := range b.Feeds {
+			backedUp[i] = fb.Feed
+		}
+		ids := matchFeedsStrict(backedUp, refs)
+		b.Feeds = slices.DeleteFunc(b.Feeds, func(fb cal.FeedBackup) bool { return !slices.Contains(ids, fb.Feed.ID) })
+	}
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("restore: %v", err)
+	}
+	p, err := backup.Restore(client, b, feeds, client.ListEvents)
+	if err != nil {
+		fatal("restore: %v", err)
+	}
+	if !apply || len(p.Changes) == 0 {
+		showPlan(p)
+		return
+	}
+
+	if err := p.Write(os.Stdout, platform.Color(os.Stdout)); err != nil {
+		fatal("restore: %v", err)
+	}
+	var feedsDone, created, updated int
+	_, failed := p.Apply(func(c plan.Change, err error) {
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "pylon: %s %s %s: %v\n", c.Action, c.Kind, c.ID, err)
+		case c.Kind == "feed":
+			feedsDone++
+		case c.Action == plan.Create:
+			created++
+		default:
+			updated++
+		}
+	})
+	fmt.Println(i18n.T("backup.restored", feedsDone, created, updated, failed))
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+func readBackupFile(path string) (*cal.Backup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return cal.ReadBackup(f)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/bridge"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/plan"
+	"github.com/jredh-dev/pylon/internal/platform"
+)
+
+func runBridge(args []string) {
+	if len(args) == 0 {
+		usageFor("bridge")
+		fail()
+	}
+	switch args[0] {
+	case "import-discord-events":
+		runBridgeImportDiscordEvents(args[1:])
+	case "discord-events":
+		runBridgeDiscordEvents(args[1:])
+	default:
+		unknownCommand(args[0], "bridge")
+	}
+}
+
+// runBridgeImportDiscordEvents copies a guild's scheduled events into a
+// feed, planning the changes and, with --apply, making them.
+func runBridgeImportDiscordEvents(args []string) {
+	cfg := loadConfig()
+	fs := parseFlags(args, "bridge", "import-discord-events")
+	guildID, feedID := fs.String("guild", cfg.DiscordGuildID), fs.String("feed", cfg.CalDefaultFeed)
+	planOnly, apply := fs.Bool("plan"), fs.Bool("apply")
+	lockOpts := lockFlagsFrom(fs)
+	if len(fs.args) > 0 || guildID == "" || feedID == "" {
+		fatal("usage: pylon bridge import-discord-events --guild <id> --feed <id> [--plan | --apply]")
+	}
+	client := newCalClient(cfg, cfg.CalURL)
+	feedID = resolveFeed(client, feedID)
+	if planOnly && apply {
+		fatal("use either --plan or --apply, not both")
+	}
+
+	if apply {
+		acquireLock("feed-"+feedID, "bridge import-discord-events", lockOpts)
+	}
+
+	events, err := newDiscordClient(cfg).ScheduledEvents(guildID)
+	if err != nil {
+		fatal("list scheduled events: %v", err)
+	}
+	existing, err := client.ListEvents(feedID)
+	if err != nil {
+		fatal("list events: %v", err)
+	}
+
+	p := bridge.ImportDiscordEvents(client, feedID, events, existing, time.Now())
+	if !apply {
+		showPlan(p)
+		return
+	}
+	applyImport(p, "bridge.imported")
+}
+
+// applyImport prints and makes the changes an import planned, then the
+// counts with the message summaryKey. Any failure makes the exit status 1.
+func applyImport(p *plan.Plan, summaryKey string) {
+	if err := p.Write(os.Stdout, platform.Color(os.Stdout)); err != nil {
+		fatal("bridge: %v", err)
+	}
+	var created, updated, deleted int
+	_, failed := p.Apply(func(c plan.Change, err error) {
+		switch {
+		case errors.Is(err, cal.ErrNotSupported):
+			fatal("this cal server does not support upserts, which the bridge needs")
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "pylon: %s event %s: %v\n", c.Action, c.ID, err)
+		case c.Action == plan.Create:
+			created++
+		case c.Action == plan.Update:
+			updated++
+		default:
+			deleted++
+		}
+	})
+	fmt.Println(i18n.T(summaryKey, created, updated, deleted, failed))
+	if failed > 0 {
+		exit(1)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalEventCancel marks an event CANCELLED so subscribed calendar apps
+// remove it, where deleting it would leave their last copy in place.
+func runCalEventCancel(cfg *config.Config, client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "event", "cancel")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal event cancel <id> [--notify-changes]")
+	}
+	id := resolveEvent(client, fs.args[0])
+
+	e, err := client.CancelEvent(id)
+	if errors.Is(err, cal.ErrNotSupported) {
+		e, _, err = setStatus(client, id, "CANCELLED")
+	}
+	if err != nil {
+		fatal("cancel event: %v", err)
+	}
+	fmt.Println(i18n.T("event.cancelled", e.Summary, e.Sequence))
+	if notifyChanges(cfg, fs) {
+		sendNotices(cfg, []string{cancelNotice(e)})
+	}
+}
+
+// runCalEventConfirm marks a cancelled (or tentative) event CONFIRMED
+// again, so subscribed calendar apps bring it back.
+func runCalEventConfirm(cfg *config.Config, client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "event", "confirm")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal event confirm <id> [--notify-changes]")
+	}
+	e, changed, err := setStatus(client, resolveEvent(client, fs.args[0]), "CONFIRMED")
+	if err != nil {
+		fatal("confirm event: %v", err)
+	}
+	if !changed {
+		fmt.Println(i18n.T("event.still_on", e.Summary))
+		return
+	}
+	fmt.Println(i18n.T("event.confirmed", e.Summary, e.Sequence))
+	if notifyChanges(cfg, fs) {
+		sendNotices(cfg, []string{confirmNotice(e)})
+	}
+}
+
+// setStatus updates event id to the iCalendar STATUS status, which the
+// server counts as a change and raises SEQUENCE for, and reports whether
+// it had another status before. Servers that can't update events in place
+// are sent an upsert, which needs the event to have an external ID.
+func setStatus(client *cal.Client, id, status string) (e *cal.Event, changed bool, err error) {
+	e, err = getEvent(client, id)
+	if err != nil || strings.EqualFold(e.Status, status) {
+		return e, false, err
+	}
+	req := e.CreateRequest(e.FeedID)
+	req.Status = status
+	e, err = updateEvent(client, e, req)
+	if errors.Is(err, cal.ErrNotSupported) {
+		return nil, false, errors.New("this cal server does not support changing events")
+	}
+	return e, err == nil, err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalCategories lists the categories in use with how many events carry
+// each, to help keep the taxonomy consistent.
+func runCalCategories(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "categories")
+	fs.noArgs()
+	feedIDs := fs.Strings("feed")
+	format := fs.String("output", "text")
+	checkListFormat("categories", format)
+
+	feedIDs = resolveFeeds(client, feedIDs)
+	if len(feedIDs) == 0 {
+		feeds, err := client.ListFeeds()
+		if err != nil {
+			fatal("categories: %v", err)
+		}
+		for _, f := range feeds {
+			feedIDs = append(feedIDs, f.ID)
+		}
+	}
+	var events []cal.Event
+	for _, id := range feedIDs {
+		list, err := client.ListEvents(id)
+		if err != nil {
+			fatal("categories: %v", err)
+		}
+		events = append(events, list...)
+	}
+
+	counts := cal.CountCategories(events)
+	if format == "csv" {
+		rows := make([][]string, len(counts))
+		for i, c := range counts {
+			rows[i] = []string{c.Name, strconv.Itoa(c.Count)}
+		}
+		writeCSV("categories", []string{"category", "events"}, rows)
+		return
+	}
+	if len(counts) == 0 {
+		fmt.Println(i18n.T("categories.none"))
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "CATEGORY\tEVENTS\n")
+	for _, c := range counts {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\n", c.Name, c.Count)
+	}
+	_ = tw.Flush()
+}
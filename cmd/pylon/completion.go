@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// The generated scripts are thin shims: they pass the words typed so far to
+// the hidden `pylon __complete` command, which answers from the same command
+// tree that renders help, so completions never drift from the real CLI.
+
+const bashCompletion = `# bash completion for pylon
+_pylon() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -W "$(pylon __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F _pylon pylon
+`
+
+const zshCompletion = `#compdef pylon
+# zsh completion for pylon
+_pylon() {
+    local -a candidates
+    candidates=("${(@f)$(pylon __complete "${(@)words[2,CURRENT-1]}" 2>/dev/null)}")
+    compadd -a candidates
+}
+if [ "$funcstack[1]" = "_pylon" ]; then
+    _pylon "$@"
+else
+    compdef _pylon pylon
+fi
+`
+
+const fishCompletion = `# fish completion for pylon
+function __pylon_complete
+    set -l tokens (commandline -opc)
+    pylon __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c pylon -f -a '(__pylon_complete)'
+`
+
+// runCompletion implements `pylon completion <shell>`.
+func runCompletion(args []string) {
+	if len(args) > 0 && args[0] == "install" {
+		runCompletionInstall(args[1:])
+		return
+	}
+	if len(args) != 1 {
+		fatal("usage: pylon completion bash|zsh|fish, or pylon completion install")
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Print(bashCompletion)
+	case "zsh":
+		fmt.Print(zshCompletion)
+	case "fish":
+		fmt.Print(fishCompletion)
+	default:
+		fatal("unsupported shell %q (want bash, zsh or fish)", args[0])
+	}
+}
+
+// runComplete prints completion candidates, one per line, for the word after
+// words. The shell filters them by the prefix being typed.
+func runComplete(words []string) {
+	for _, c := range completions(words) {
+		fmt.Println(c)
+	}
+}
+
+// completions returns the candidates runComplete prints.
+func completions(words []string) []string {
+	path := lookup(words)
+	c := path[len(path)-1]
+
+	// Complete a flag's value when the previous word is a flag that takes one.
+	if n := len(words); n > 0 {
+		if f, ok := valueFlag(c, words[n-1]); ok {
+			return completeValues(f)
+		}
+	}
+
+	var out []string
+	for _, sub := range c.Subcommands {
+		out = append(out, sub.Name)
+	}
+	for _, f := range c.Flags {
+		out = append(out, "--"+f.Name)
+	}
+	return out
+}
+
+// valueFlag returns the flag of c that word names if it is still waiting for
+// its value: "--name" rather than "--name=value", or a short flag such as
+// "-o" whose value isn't attached.
+func valueFlag(c *command, word string) (flagDoc, bool) {
+	var name string
+	switch {
+	case strings.HasPrefix(word, "--"):
+		if strings.Contains(word, "=") {
+			return flagDoc{}, false
+		}
+		name = word[2:]
+	case len(word) == 2 && word[0] == '-':
+		short, ok := shortFlags[word[1]]
+		if !ok {
+			return flagDoc{}, false
+		}
+		name = short
+	default:
+		return flagDoc{}, false
+	}
+	f, ok := c.flag(name)
+	if alias, isAlias := flagAliases[name]; !ok && isAlias {
+		f, ok = c.flag(alias)
+	}
+	return f, ok && f.Arg != "" && !f.Optional
+}
+
+// completeValues returns candidates for a flag value. Lookups that
+// need the network are kept short and fail silently: a slow or unreachable
+// service must never hang the user's shell.
+func completeValues(flag flagDoc) []string {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	switch flag.Name {
+	case "feed":
+		client := newCalClient(cfg, cfg.CalURL, cal.WithTimeout(2*time.Second), cal.WithRetries(0))
+		feeds, err := client.ListFeeds()
+		if err != nil {
+			return nil
+		}
+		ids := make([]string, len(feeds))
+		for i, f := range feeds {
+			ids[i] = f.ID
+		}
+		return ids
+	case "channel":
+		names := slices.Sorted(maps.Keys(cfg.ChannelAliases))
+		return append(nonEmpty(cfg.DiscordChannelID), names...)
+	case "guild":
+		return nonEmpty(cfg.DiscordGuildID)
+	case "shell":
+		return config.Shells
+	case "format", "output":
+		return strings.Split(flag.Arg, "|")
+	}
+	return nil
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// flag finds a documented flag on c by name.
+func (c *command) flag(name string) (flagDoc, bool) {
+	for _, f := range c.Flags {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return flagDoc{}, false
+}
+
+// completionHelp is appended to the completion command's help.
+const completionHelp = `Load completions for the current shell session:
+  bash:  source <(pylon completion bash)
+  zsh:   source <(pylon completion zsh)
+  fish:  pylon completion fish | source
+
+Feed IDs are completed live from the cal service after --feed.
+
+Package managers can install the output of pylon completion <shell> in
+their completion directories; otherwise pylon completion install sets up
+the current user.`
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompletions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{name: "long flag value", words: []string{"cal", "event", "list", "--output"}, want: []string{"text", "csv"}},
+		{name: "alias flag value", words: []string{"cal", "event", "list", "--format"}, want: []string{"text", "csv"}},
+		{name: "short flag value", words: []string{"cal", "event", "list", "-o"}, want: []string{"text", "csv"}},
+		{name: "value already given", words: []string{"cal", "event", "list", "-o", "csv"}, want: []string{"--feed", "--category", "--output"}},
+		{name: "value attached", words: []string{"cal", "event", "list", "--output=csv"}, want: []string{"--feed", "--category", "--output"}},
+		{name: "short switch", words: []string{"cal", "event", "delete", "-f"}, want: []string{"--yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completions(tt.words); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completions(%q) = %q, want %q", tt.words, got, tt.want)
+			}
+		})
+	}
+}
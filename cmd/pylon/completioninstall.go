@@ -0,0 +1,179 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// completionShell describes where a shell looks for pylon's completion
+// script and how to tell that it loaded it.
+type completionShell struct {
+	name   string
+	script string
+	// path returns the per-user file the shell loads the script from.
+	path func(home string) string
+	// check is run in an interactive shell and succeeds once pylon's
+	// completion is loaded.
+	check string
+	// hint says how to make the shell pick up the script at path.
+	hint func(path string) string
+}
+
+var completionShells = []completionShell{
+	{
+		name:   "bash",
+		script: bashCompletion,
+		path: func(home string) string {
+			data := cmp.Or(os.Getenv("XDG_DATA_HOME"), filepath.Join(home, ".local", "share"))
+			return filepath.Join(data, "bash-completion", "completions", "pylon")
+		},
+		// bash-completion loads scripts on the first <Tab>; ask its loader
+		// (_comp_load in 2.12+, _completion_loader before) to do it now.
+		check: `if type _comp_load >/dev/null 2>&1; then _comp_load pylon; elif type _completion_loader >/dev/null 2>&1; then _completion_loader pylon; fi; complete -p pylon`,
+		hint: func(path string) string {
+			return "Install bash-completion 2.x, or add 'source " + path + "' to ~/.bashrc"
+		},
+	},
+	{
+		name:   "zsh",
+		script: zshCompletion,
+		path: func(home string) string {
+			return filepath.Join(cmp.Or(os.Getenv("ZDOTDIR"), home), ".zfunc", "_pylon")
+		},
+		check: `(( ${+_comps[pylon]} ))`,
+		hint: func(path string) string {
+			return "Add 'fpath=(" + filepath.Dir(path) + " $fpath)' to ~/.zshrc before 'autoload -Uz compinit && compinit'"
+		},
+	},
+	{
+		name:   "fish",
+		script: fishCompletion,
+		path: func(home string) string {
+			conf := cmp.Or(os.Getenv("XDG_CONFIG_HOME"), filepath.Join(home, ".config"))
+			return filepath.Join(conf, "fish", "completions", "pylon.fish")
+		},
+		check: `complete -C 'pylon ' >/dev/null; functions -q __pylon_complete`,
+		hint: func(path string) string {
+			return "Make sure " + filepath.Dir(path) + " is in $fish_complete_path"
+		},
+	},
+}
+
+// findCompletionShell returns the completion support for shell, which may
+// be a path such as $SHELL.
+func findCompletionShell(shell string) (completionShell, bool) {
+	name := filepath.Base(shell)
+	for _, s := range completionShells {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return completionShell{}, false
+}
+
+// completionVerifyTimeout bounds starting an interactive shell to check
+// the script loads, in case the user's rc files wait for input.
+const completionVerifyTimeout = 10 * time.Second
+
+// runCompletionInstall implements `pylon completion install`: it writes
+// the script for the user's shell where the shell loads it from, then
+// starts the shell to check that it does.
+func runCompletionInstall(args []string) {
+	fs := parseFlags(args, "completion", "install")
+	fs.noArgs()
+	shell, path := fs.String("shell", os.Getenv("SHELL")), fs.String("path", "")
+	verify := !fs.Bool("no-verify")
+	s, ok := findCompletionShell(shell)
+	if !ok {
+		if shell == "" {
+			fatal("can't tell your shell: $SHELL is not set; pass --shell bash|zsh|fish")
+		}
+		fatal("unsupported shell %q (want bash, zsh or fish); pass --shell", shell)
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			fatal("completion install: %v", err)
+		}
+		path = s.path(home)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		fatal("completion install: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(s.script), 0o644); err != nil {
+		fatal("completion install: %v", err)
+	}
+	fmt.Println(i18n.T("completion.wrote", s.name, path))
+
+	if _, hint, ok := checkOnPath(); !ok {
+		fmt.Fprintf(os.Stderr, "pylon: warning: completion runs pylon from PATH: %s\n", hint)
+	}
+	if !verify {
+		return
+	}
+	switch err := verifyCompletion(s); {
+	case errors.Is(err, exec.ErrNotFound):
+		fmt.Fprintf(os.Stderr, "pylon: %s is not installed, so the completion could not be checked\n", s.name)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "pylon: a new %s does not load the completion yet.\n%s, then open a new shell.\n", s.name, s.hint(path))
+		exit(1)
+	default:
+		fmt.Println(i18n.T("completion.ok", s.name))
+	}
+}
+
+// verifyCompletion starts an interactive s and reports whether it loads
+// pylon's completion.
+func verifyCompletion(s completionShell) error {
+	bin, err := exec.LookPath(s.name)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionVerifyTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, bin, "-i", "-c", s.check).Run()
+}
+
+// checkOnPath reports whether running "pylon", as the completion scripts
+// do, finds this executable, with a hint if it doesn't.
+func checkOnPath() (found, hint string, ok bool) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", "", true // can't tell; don't cry wolf
+	}
+	exe, _ = filepath.EvalSymlinks(exe)
+	found, err = exec.LookPath("pylon")
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return "", "pylon on PATH is not executable; run 'chmod +x' on it", false
+		}
+		return "", "add " + filepath.Dir(exe) + " to PATH", false
+	}
+	if abs, err := filepath.Abs(found); err == nil {
+		found = abs
+	}
+	resolved, _ := filepath.EvalSymlinks(found)
+	if !sameFile(resolved, exe) {
+		return found, "PATH runs " + found + ", not this pylon (" + exe + "); remove the other copy or put " + filepath.Dir(exe) + " first in PATH", false
+	}
+	return found, "", true
+}
+
+func sameFile(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	return err == nil && os.SameFile(fa, fb)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// runConfig implements `pylon config`, which reads and edits the config
+// file (see config.Path).
+func runConfig(args []string) {
+	switch args[0] {
+	case "list", "ls":
+		fs := parseFlags(args[1:], "config", "list")
+		fs.noArgs()
+		showSecrets := fs.Bool("show-secrets")
+		cfg := loadConfig()
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "KEY\tVALUE\n")
+		for _, k := range config.Keys {
+			v, _ := cfg.Get(k.Name)
+			if k.Secret && !showSecrets {
+				v = config.Mask(v)
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\n", k.Name, v)
+		}
+		_ = tw.Flush()
+
+	case "get":
+		if len(args) != 2 {
+			fatal("usage: pylon config get <section.key>")
+		}
+		cfg := loadConfig()
+		v, ok := cfg.Get(args[1])
+		if !ok {
+			unknownKey(args[1])
+		}
+		fmt.Println(v)
+
+	case "set":
+		if len(args) < 3 {
+			fatal("usage: pylon config set <section.key> <value>")
+		}
+		name, value := args[1], strings.Join(args[2:], " ")
+		requireKey(name)
+		if err := config.Validate(name, value); err != nil {
+			fatal("config set: %v", err)
+		}
+		f := openConfigFile()
+		section, key := config.SplitKey(name)
+		f.Set(section, key, value)
+		if err := f.Save(); err != nil {
+			fatal("config set: %v", err)
+		}
+		fmt.Printf("Set %s in %s\n", name, f.Path())
+
+	case "unset":
+		if len(args) != 2 {
+			fatal("usage: pylon config unset <section.key>")
+		}
+		requireKey(args[1])
+		f := openConfigFile()
+		section, key := config.SplitKey(args[1])
+		if !f.Unset(section, key) {
+			fmt.Printf("%s is not set in %s\n", args[1], f.Path())
+			return
+		}
+		if err := f.Save(); err != nil {
+			fatal("config unset: %v", err)
+		}
+		fmt.Printf("Unset %s in %s\n", args[1], f.Path())
+
+	case "explain":
+		fs := parseFlags(args[1:], "config", "explain")
+		if len(fs.args) > 1 {
+			fatal("usage: pylon config explain [section.key] [--show-secrets]")
+		}
+		var name string
+		if len(fs.args) == 1 {
+			name = fs.args[0]
+			requireKey(name)
+		}
+		explainConfig(loadConfig(), name, fs.Bool("show-secrets"))
+
+	case "path":
+		path, err := config.Path()
+		if err != nil {
+			fatal("config path: %v", err)
+		}
+		fmt.Println(path)
+
+	default:
+		unknownCommand(args[0], "config")
+	}
+}
+
+// explainConfig prints where the effective value of each setting, or just
+// the named one, came from.
+func explainConfig(cfg *config.Config, name string, showSecrets bool) {
+	path, err := config.Path()
+	if err != nil {
+		fatal("config: %v", err)
+	}
+	how := "default location"
+	if origin := config.PathOrigin(); origin != "" {
+		how = "chosen by " + origin
+	}
+	if _, err := os.Stat(path); err != nil {
+		how += ", not found"
+	}
+	fmt.Printf("Config file: %s (%s)\n\n", path, how)
+
+	display := func(k config.Key, v string) string {
+		if k.Secret && !showSecrets {
+			return config.Mask(v)
+		}
+		return v
+	}
+
+	if name == "" {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "KEY\tVALUE\tSOURCE\n")
+		for _, k := range config.Keys {
+			v, _ := cfg.Get(k.Name)
+			source := "default"
+			if ss := cfg.Sources(k.Name); len(ss) > 0 {
+				source = ss[len(ss)-1].String()
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", k.Name, display(k, v), source)
+		}
+		_ = tw.Flush()
+		return
+	}
+
+	k, _ := config.LookupKey(name)
+	v, _ := cfg.Get(name)
+	fmt.Printf("%s = %s\n", name, display(k, v))
+	ss := cfg.Sources(name)
+	if len(ss) == 0 {
+		fmt.Println("  default; not set in the config file or " + k.Env)
+	}
+	for i := len(ss) - 1; i >= 0; i-- {
+		verb := "set by"
+		if i < len(ss)-1 {
+			verb = "overrides"
+		}
+		fmt.Printf("  %-9s %s (%s)\n", verb, ss[i], display(k, ss[i].Value))
+	}
+	if len(ss) > 0 {
+		fmt.Printf("  %-9s the default\n", "overrides")
+	}
+	if name == "cal.url" {
+		fmt.Println("  pylon cal --url <url> overrides it for a single command")
+	}
+}
+
+// requireKey exits unless name is a known config key.
+func requireKey(name string) {
+	if _, ok := config.LookupKey(name); !ok {
+		unknownKey(name)
+	}
+}
+
+// unknownKey reports an unknown config key, suggesting the closest known
+// keys, and exits.
+func unknownKey(name string) {
+	hint := didYouMean(name, config.KeyNames())
+	if hint == "" {
+		hint = fmt.Sprintf("known keys: %s\n", strings.Join(config.KeyNames(), ", "))
+	}
+	fmt.Fprintf(os.Stderr, "pylon: unknown config key: %s\n%s", name, hint)
+	fail()
+}
+
+func openConfigFile() *config.File {
+	path, err := config.Path()
+	if err != nil {
+		fatal("config: %v", err)
+	}
+	f, err := config.OpenFile(path)
+	if err != nil {
+		fatal("config: %v", err)
+	}
+	return f
+}
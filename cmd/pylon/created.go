@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/output"
+)
+
+// createdFormat is how a create command reports what it made.
+type createdFormat int
+
+const (
+	createdText      createdFormat = iota // human-readable, then a porcelain line
+	createdJSON                           // the object as JSON
+	createdPorcelain                      // only the porcelain line
+)
+
+// createdFormatFlag returns the format selected by a create command's
+// --json and --porcelain switches.
+func createdFormatFlag(fs *flagSet) createdFormat {
+	switch asJSON, porcelain := fs.Bool("json"), fs.Bool("porcelain"); {
+	case asJSON && porcelain:
+		fatal("--json and --porcelain cannot be used together")
+	case asJSON:
+		return createdJSON
+	case porcelain:
+		return createdPorcelain
+	}
+	return createdText
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fatal("%v", err)
+	}
+}
+
+// feedPorcelain prints the stable one-line summary of a created feed.
+func feedPorcelain(f *cal.CreateFeedResponse) {
+	_ = output.Porcelain(os.Stdout, "feed",
+		"id", f.ID,
+		"name", f.Name,
+		"token", f.Token,
+		"url", f.URL,
+	)
+}
+
+// printCreatedEvent reports a created or updated event in format.
+func printCreatedEvent(format createdFormat, event *cal.Event, created bool) {
+	switch format {
+	case createdJSON:
+		printJSON(event)
+		return
+	case createdText:
+		if created {
+			fmt.Println(i18n.T("event.created"))
+		} else {
+			fmt.Println(i18n.T("event.updated"))
+		}
+		fmt.Printf("  ID:      %s\n", event.ID)
+		fmt.Printf("  Summary: %s\n", event.Summary)
+		fmt.Printf("  Start:   %s\n", event.Start.Format(time.RFC3339))
+		if event.End != nil {
+			fmt.Printf("  End:     %s\n", event.End.Format(time.RFC3339))
+		}
+		if event.Location != "" {
+			fmt.Printf("  Location: %s\n", event.Location)
+		}
+	}
+	eventPorcelain(event, created)
+}
+
+// eventPorcelain prints the stable one-line summary of a created or
+// updated event.
+func eventPorcelain(e *cal.Event, created bool) {
+	action := "updated"
+	if created {
+		action = "created"
+	}
+	_ = output.Porcelain(os.Stdout, "event",
+		"action", action,
+		"id", e.ID,
+		"feed_id", e.FeedID,
+		"external_id", e.ExternalID,
+		"url", e.URL,
+	)
+}
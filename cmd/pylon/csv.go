@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+)
+
+// checkListFormat exits unless format is one the list commands print: text
+// (aligned columns, the default) or csv.
+func checkListFormat(cmd, format string) {
+	if format != "text" && format != "csv" {
+		fatal("%s: unknown format %q: want text or csv", cmd, format)
+	}
+}
+
+// writeCSV prints a listing as CSV, a header row first, so --output csv can
+// be piped into a spreadsheet or xsv instead of parsing the text columns.
+func writeCSV(cmd string, header []string, rows [][]string) {
+	cw := csv.NewWriter(os.Stdout)
+	_ = cw.Write(header)
+	_ = cw.WriteAll(rows)
+	if err := cw.Error(); err != nil {
+		fatal("%s: %v", cmd, err)
+	}
+}
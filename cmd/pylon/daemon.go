@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/agenda"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/watchdog"
+)
+
+// runDaemon handles `pylon daemon`, which reports on the long-running jobs.
+func runDaemon(args []string) {
+	if len(args) < 1 {
+		usageFor("daemon")
+		fail()
+	}
+	switch args[0] {
+	case "jobs":
+		parseFlags(args[1:], "daemon", "jobs").noArgs()
+		jobs, err := watchdog.List(jobsDir())
+		if err != nil {
+			fatal("daemon jobs: %v", err)
+		}
+		if len(jobs) == 0 {
+			fmt.Println(i18n.T("daemon.none"))
+			return
+		}
+		now := time.Now()
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "JOB\tSTATUS\tLAST RUN\tDURATION\tLAST SUCCESS\tERROR\n")
+		for _, j := range jobs {
+			status := "ok"
+			if !j.OK() {
+				status = fmt.Sprintf("failing (%d)", j.Failures)
+			}
+			success := "never"
+			if !j.LastSuccess.IsZero() {
+				success = agenda.Relative(j.LastSuccess, now)
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", j.Job, status,
+				agenda.Relative(j.LastRun, now), j.Duration.Round(time.Millisecond), success, j.LastError)
+		}
+		_ = tw.Flush()
+	default:
+		unknownCommand(args[0], "daemon")
+	}
+}
+
+// jobsDir is where the watchdog keeps job status files.
+func jobsDir() string {
+	dir, err := config.StateDir()
+	if err != nil {
+		fatal("%v", err)
+	}
+	return filepath.Join(dir, "jobs")
+}
+
+// newWatchdog returns the watchdog for a long-running job, which tells the
+// [daemon] admin channel and user when the job keeps failing.
+func newWatchdog(cfg *config.Config) *watchdog.Watchdog {
+	w := &watchdog.Watchdog{Dir: jobsDir(), Threshold: cfg.DaemonFailureThreshold}
+	if cfg.DaemonAdminChannel == "" && cfg.DaemonAdminUser == "" {
+		return w
+	}
+	client := newDiscordClient(cfg)
+	host, _ := os.Hostname()
+	w.Notify = func(st watchdog.Status, recovered bool) error {
+		msg := i18n.T("daemon.failing", st.Job, host, st.Failures, st.LastError)
+		if recovered {
+			msg = i18n.T("daemon.recovered", st.Job, host, st.Failures)
+		}
+		var errs []error
+		if cfg.DaemonAdminChannel != "" {
+			if _, err := client.SendChannelMessage(cfg.Channel(cfg.DaemonAdminChannel), msg, ""); err != nil {
+				errs = append(errs, fmt.Errorf("admin channel: %w", err))
+			}
+		}
+		if cfg.DaemonAdminUser != "" {
+			if _, err := client.SendDirectMessage(cfg.DaemonAdminUser, msg); err != nil {
+				errs = append(errs, fmt.Errorf("admin user: %w", err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return w
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/agenda"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/digest"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runDigest posts the upcoming agenda of one or more feed sets to Discord.
+func runDigest(args []string) {
+	fs := parseFlags(args, "digest")
+	fs.noArgs()
+	days, parallel := fs.Int("days", 7), fs.Int("parallel", 4)
+	feedIDs := fs.Strings("feed")
+	to, routesFile := fs.String("to", ""), fs.String("routes", "")
+	dryRun := fs.Bool("dry-run")
+	if routesFile != "" && (len(feedIDs) > 0 || to != "") {
+		fatal("--routes cannot be combined with --feed or --to")
+	}
+
+	cfg := loadConfig()
+	client := newCalClient(cfg, cfg.CalURL)
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("list feeds: %v", err)
+	}
+	names := map[string]string{}
+	for _, f := range feeds {
+		names[f.ID] = f.Name
+	}
+
+	var routes []digest.Route
+	if routesFile != "" {
+		f, err := os.Open(routesFile)
+		if err != nil {
+			fatal("routes: %v", err)
+		}
+		routes, err = digest.ParseRoutes(f)
+		f.Close()
+		if err != nil {
+			fatal("routes: %s: %v", routesFile, err)
+		}
+	} else {
+		route := digest.Route{Feeds: feedIDs}
+		if len(route.Feeds) == 0 {
+			for _, f := range feeds {
+				route.Feeds = append(route.Feeds, f.ID)
+			}
+		}
+		if to != "" {
+			if route.To, err = digest.ParseDestination(to); err != nil {
+				fatal("%v", err)
+			}
+		}
+		routes = []digest.Route{route}
+	}
+	// A feed that can't be found fails its routes below, not the digest.
+	for _, r := range routes {
+		for i, ref := range r.Feeds {
+			if f := filterFeeds(feeds, ref); len(f) == 1 {
+				r.Feeds[i] = f[0].ID
+			}
+		}
+	}
+
+	now := time.Now()
+	render := func(r digest.Route, events []cal.Event) string {
+		list := agenda.Build(events, now, days, time.Local)
+		if len(list) == 0 {
+			return i18n.T("agenda.none", days)
+		}
+		routeNames := map[string]string{}
+		for _, id := range r.Feeds {
+			routeNames[id] = names[id]
+		}
+		return agenda.Format(list, now, time.Local, routeNames)
+	}
+	send := func(d digest.Destination, msg string) error {
+		if dryRun {
+			fmt.Printf("--- %s\n%s\n", d, msg)
+			return nil
+		}
+		return sendDigest(cfg, d, msg)
+	}
+
+	failed := 0
+	for _, res := range digest.Run(routes, client.ListEvents, render, send, parallel) {
+		if res.Err == nil {
+			continue
+		}
+		failed++
+		where := res.Route.To.String()
+		if res.Route.Line > 0 {
+			where = fmt.Sprintf("%s:%d (%s)", routesFile, res.Route.Line, where)
+		}
+		fmt.Fprintf(os.Stderr, "pylon: digest %s: %v\n", where, res.Err)
+	}
+	if !dryRun {
+		fmt.Println(i18n.T("digest.summary", len(routes)-failed, failed))
+	}
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// sendDigest posts msg to d, falling back to the configured webhook or
+// default channel for the default destination.
+func sendDigest(cfg *config.Config, d digest.Destination, msg string) error {
+	if d.Webhook == "" && d.Channel == "" {
+		if cfg.DiscordWebhook != "" {
+			d.Webhook = cfg.DiscordWebhook
+		} else {
+			d.Channel = cfg.DiscordChannelID
+		}
+	}
+	switch {
+	case d.Webhook != "":
+		return discord.NewClient("", d.Webhook, discordOptions(cfg)...).SendMessage(msg)
+	case d.Channel != "":
+		_, err := newDiscordClient(cfg).SendChannelMessage(cfg.Channel(d.Channel), msg, "")
+		return err
+	}
+	return fmt.Errorf("no destination: set discord.webhook or discord.channel_id, or use --to")
+}
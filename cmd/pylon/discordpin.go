@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runDiscordPin pins (or with action "unpin", unpins) a message.
+func runDiscordPin(cfg *config.Config, client *discord.Client, action string, args []string) {
+	fs := parseFlags(args, "discord", action)
+	channelID := cfg.DiscordChannelID
+	if v := fs.String("channel", ""); v != "" {
+		channelID = cfg.Channel(v)
+	}
+	messageID := fs.String("message", "")
+	if messageID == "" && len(fs.args) > 0 {
+		messageID, fs.args = fs.args[0], fs.args[1:]
+	}
+	if len(fs.args) > 0 {
+		fatal("discord %s: unexpected argument %q", action, fs.args[0])
+	}
+	if channelID == "" || messageID == "" {
+		fatal("usage: pylon discord %s --message <id> [--channel <id>]", action)
+	}
+
+	if action == "unpin" {
+		if err := client.Unpin(channelID, messageID); err != nil {
+			fatal("discord unpin: %v", err)
+		}
+		fmt.Println(i18n.T("discord.unpinned", messageID))
+		return
+	}
+	if err := client.Pin(channelID, messageID); err != nil {
+		fatal("discord pin: %v", err)
+	}
+	fmt.Println(i18n.T("discord.pinned", messageID))
+}
+
+// runDiscordPins lists a channel's pinned messages with their IDs.
+func runDiscordPins(cfg *config.Config, client *discord.Client, args []string) {
+	fs := parseFlags(args, "discord", "pins")
+	fs.noArgs()
+	channelID := cfg.DiscordChannelID
+	if v := fs.String("channel", ""); v != "" {
+		channelID = cfg.Channel(v)
+	}
+	raw := fs.Bool("raw")
+	if channelID == "" {
+		fatal("usage: pylon discord pins --channel <id>")
+	}
+	msgs, err := client.Pins(channelID)
+	if err != nil {
+		fatal("discord pins: %v", err)
+	}
+	if len(msgs) == 0 {
+		fmt.Println(i18n.T("discord.no_pins"))
+		return
+	}
+	var r *discord.Resolver
+	if !raw {
+		r = client.NewResolver(cfg.DiscordGuildID, msgs)
+	}
+	for _, m := range msgs {
+		fmt.Printf("%s  %s", m.ID, r.Format([]discord.Message{m}))
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// doctorTimeout bounds each network check, so a black-holed host doesn't
+// stall the report.
+const doctorTimeout = 10 * time.Second
+
+// doctor prints one line per check, with a hint on what to do about
+// anything that isn't fine.
+type doctor struct {
+	failed bool
+}
+
+func (d *doctor) report(status, check, detail, hint string) {
+	if status == "FAIL" {
+		d.failed = true
+	}
+	fmt.Printf("%-5s %-13s %s\n", status, check, detail)
+	if hint != "" {
+		fmt.Printf("%-20s→ %s\n", "", hint)
+	}
+}
+
+// runDoctor checks the config and connectivity to each configured service.
+func runDoctor(args []string) {
+	parseFlags(args, "doctor").noArgs()
+	d := &doctor{}
+
+	path, err := config.Path()
+	if err != nil {
+		fatal("config: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		d.report("FAIL", "config", err.Error(), "Fix the setting in "+path+"; 'pylon config explain' shows where each value comes from")
+		exit(1)
+	}
+	if _, err := os.Stat(path); err == nil {
+		d.report("ok", "config", path, "")
+	} else {
+		d.report("ok", "config", "no config file; using defaults and PYLON_* variables", "")
+	}
+	if err := i18n.SetLanguage(cfg.Language); err != nil {
+		d.report("FAIL", "ui.language", err.Error(), "Set ui.language to one of en, es, de")
+	}
+	d.checkHTTP(cfg)
+
+	d.checkCal(cfg)
+	d.checkDiscord(cfg)
+	d.checkShell()
+
+	fmt.Println()
+	if d.failed {
+		fmt.Println(i18n.T("doctor.failed"))
+		exit(1)
+	}
+	fmt.Println(i18n.T("doctor.ok"))
+}
+
+// checkShell checks that the pylon on PATH is this one, which shell
+// completion runs, and that an installed completion script is readable and
+// current.
+func (d *doctor) checkShell() {
+	if found, hint, ok := checkOnPath(); !ok {
+		d.report("warn", "path", "pylon is not the one on PATH", cmp.Or(hint, "Check PATH"))
+	} else if found != "" {
+		d.report("ok", "path", found, "")
+	}
+
+	s, ok := findCompletionShell(os.Getenv("SHELL"))
+	if !ok {
+		return
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	path := s.path(home)
+	script, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Not installed this way; package managers put it elsewhere.
+	case err != nil:
+		d.report("warn", "completion", err.Error(), "Fix the permissions on "+path+" or run 'pylon completion install'")
+	case string(script) != s.script:
+		d.report("warn", "completion", path+" is from another pylon version", "Run 'pylon completion install'")
+	default:
+		d.report("ok", "completion", s.name+": "+path, "")
+	}
+}
+
+// checkHTTP builds the [http] transport the clients use, so a bad CA
+// file shows up here rather than as a TLS error from every check.
+func (d *doctor) checkHTTP(cfg *config.Config) {
+	t, err := newTransport(cfg)
+	switch {
+	case err != nil:
+		d.report("FAIL", "http", err.Error(), "Fix http.proxy or http.ca_file (PYLON_HTTP_PROXY, PYLON_HTTP_CA_FILE)")
+		return
+	case t == nil:
+		return
+	}
+	transport = t
+	if cfg.HTTPInsecureSkipVerify {
+		d.report("warn", "http", "TLS certificates are not verified", "Set http.ca_file to your CA bundle and turn off http.insecure_skip_verify")
+		return
+	}
+	var using []string
+	if proxy := cmp.Or(proxyFlag, cfg.HTTPProxy); proxy != "" {
+		using = append(using, "proxy "+proxy)
+	}
+	if cfg.HTTPCAFile != "" {
+		using = append(using, "CAs from "+cfg.HTTPCAFile)
+	}
+	d.report("ok", "http", strings.Join(using, ", "), "")
+}
+
+func (d *doctor) checkCal(cfg *config.Config) {
+	client := cal.NewClient(cfg.CalURL,
+		cal.WithHTTPClient(&http.Client{Transport: transport}),
+		cal.WithTimeout(doctorTimeout),
+		cal.WithAPIKey(cfg.CalAPIKey),
+		cal.WithAuthHeader(cfg.CalAuthHeader),
+	)
+	feeds, err := client.ListFeeds()
+	if err == nil {
+		d.report("ok", "cal", fmt.Sprintf("%s: %d feed(s)", cfg.CalURL, len(feeds)), "")
+		return
+	}
+
+	hint := "Check cal.url (PYLON_CAL_URL) and the service's logs"
+	var apiErr *cal.APIError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		if cfg.CalAPIKey == "" {
+			hint = "The server wants credentials: set cal.api_key (PYLON_CAL_API_KEY)"
+		} else {
+			hint = "The API key was rejected: check cal.api_key, and cal.auth_header if the server expects another header"
+		}
+	case errors.Is(err, cal.ErrNotSupported):
+		hint = cfg.CalURL + " doesn't serve /api/feeds; is cal.url the cal service's base URL?"
+	case errors.As(err, &netErr):
+		hint = "Is the cal service running at " + cfg.CalURL + "? Set cal.url (PYLON_CAL_URL) to its address"
+	}
+	d.report("FAIL", "cal", fmt.Sprintf("%s: %v", cfg.CalURL, err), hint)
+}
+
+func (d *doctor) checkDiscord(cfg *config.Config) {
+	client := discord.NewClient(cfg.DiscordBotToken, cfg.DiscordWebhook,
+		discord.WithHTTPClient(&http.Client{Timeout: doctorTimeout, Transport: transport}))
+
+	if cfg.DiscordWebhook == "" {
+		d.report("skip", "webhook", "not configured", "Set discord.webhook to post messages and reminders without a bot")
+	} else if w, err := client.Webhook(); err != nil {
+		hint := "Check discord.webhook"
+		var apiErr *discord.APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusUnauthorized) {
+			hint = "The webhook was deleted or its URL is wrong: create a new one under Channel settings > Integrations and set discord.webhook"
+		}
+		d.report("FAIL", "webhook", errorLine(err), hint)
+	} else {
+		d.report("ok", "webhook", fmt.Sprintf("%q posts to channel %s", w.Name, w.ChannelID), "")
+	}
+
+	if cfg.DiscordBotToken == "" {
+		d.report("skip", "bot token", "not configured", "Set discord.bot_token to read messages, list channels and use threads")
+		return
+	}
+	u, err := client.CurrentUser()
+	if err != nil {
+		hint := "Check discord.bot_token"
+		var apiErr *discord.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			hint = "The token was rejected: reset it in the Discord developer portal (Bot > Reset Token) and set discord.bot_token"
+		}
+		d.report("FAIL", "bot token", errorLine(err), hint)
+		return
+	}
+	d.report("ok", "bot token", "logged in as "+u.DisplayName(), "")
+
+	if cfg.DiscordGuildID == "" {
+		d.report("warn", "guild_id", "not set", "Set discord.guild_id so channels, threads and moderation work without --guild")
+	}
+	if cfg.DiscordChannelID == "" {
+		d.report("warn", "channel_id", "not set", "Set discord.channel_id so read and msg work without --channel")
+		return
+	}
+	if _, err := client.ReadMessages(cfg.DiscordChannelID, 1); err != nil {
+		hint := "Check discord.channel_id"
+		var apiErr *discord.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.StatusCode {
+			case http.StatusForbidden:
+				hint = "The bot can't read the channel: give it View Channel and Read Message History there"
+			case http.StatusNotFound:
+				hint = "No such channel, or the bot isn't in its server: check discord.channel_id and invite the bot"
+			}
+		}
+		d.report("FAIL", "channel_id", errorLine(err), hint)
+		return
+	}
+	d.report("ok", "channel_id", cfg.DiscordChannelID+" is readable", "")
+}
+
+// errorLine shortens an error for the report: API errors are given by
+// status only, since their bodies are JSON noise.
+func errorLine(err error) string {
+	var apiErr *discord.APIError
+	if errors.As(err, &apiErr) {
+		return fmt.Sprintf("status %d %s", apiErr.StatusCode, http.StatusText(apiErr.StatusCode))
+	}
+	return err.Error()
+}
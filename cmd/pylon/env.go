@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// runEnv implements `pylon env`, which prints the effective configuration as
+// environment variable assignments.
+func runEnv(args []string) {
+	shell := "sh"
+	if runtime.GOOS == "windows" {
+		shell = "powershell"
+	}
+	fs := parseFlags(args, "env")
+	fs.noArgs()
+	shell = fs.String("shell", shell)
+	showSecrets := fs.Bool("show-secrets")
+	cfg := loadConfig()
+	if err := config.Export(os.Stdout, cfg, shell, showSecrets); err != nil {
+		fatal("env: %v", err)
+	}
+}
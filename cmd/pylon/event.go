@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/ics"
+	"github.com/jredh-dev/pylon/internal/resolve"
+)
+
+// runCalEventShow prints every field of one event, or the event as JSON.
+func runCalEventShow(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "event", "show")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal event show <id> [--json]")
+	}
+	id, asJSON := fs.args[0], fs.Bool("json")
+
+	e, err := getEvent(client, id)
+	if err != nil {
+		fatal("show event: %v", err)
+	}
+
+	if asJSON {
+		printJSON(e)
+		return
+	}
+
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Printf("%-13s %s\n", label+":", value)
+		}
+	}
+	stamp := func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	field("ID", e.ID)
+	field("Feed", e.FeedID)
+	field("Summary", e.Summary)
+	field("Status", e.Status)
+	field("Start", stamp(&e.Start))
+	field("End", stamp(e.End))
+	if e.AllDay {
+		field("All day", "yes")
+	}
+	field("Deadline", stamp(e.Deadline))
+	for _, a := range e.Alarms {
+		field("Alarm", alarmLabel(a))
+	}
+	field("Location", e.Location)
+	field("URL", e.URL)
+	for _, a := range e.Attachments {
+		field("Attachment", a)
+	}
+	field("Categories", e.Categories)
+	field("External ID", e.ExternalID)
+	field("Repeats", e.RRule)
+	for _, x := range e.ExDates {
+		field("Except", stamp(&x))
+	}
+	field("UID", e.UID)
+	if e.Sequence > 0 {
+		field("Sequence", fmt.Sprint(e.Sequence))
+	}
+	field("Created", stamp(&e.CreatedAt))
+	field("Updated", stamp(&e.UpdatedAt))
+	if e.Description != "" {
+		fmt.Println("Description:")
+		for _, line := range strings.Split(e.Description, "\n") {
+			fmt.Println("  " + line)
+		}
+	}
+}
+
+// alarmLabel describes an alarm trigger as "15m before" or "1h after", or
+// returns it as is if it doesn't parse.
+func alarmLabel(trigger string) string {
+	d, err := ics.ParseDuration(trigger)
+	if err != nil {
+		return trigger
+	}
+	when := "before"
+	if d > 0 {
+		when = "after"
+	}
+	d = d.Abs()
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	if d == 0 {
+		s, when = "at", "start"
+	}
+	return s + " " + when
+}
+
+// findEvent looks an event up across all feeds by its ID or an
+// unambiguous prefix of it.
+func findEvent(client *cal.Client, ref string) (*cal.Event, error) {
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		return nil, fmt.Errorf("list feeds: %w", err)
+	}
+	var all []cal.Event
+	for _, f := range feeds {
+		events, err := client.ListEvents(f.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list events for %s: %w", f.ID, err)
+		}
+		all = append(all, events...)
+	}
+	items := make([]resolve.Item, len(all))
+	for i, e := range all {
+		items[i] = resolve.Item{ID: e.ID, Label: e.Summary}
+	}
+	id, err := resolve.Resolve("event", ref, items)
+	if err != nil {
+		return nil, err
+	}
+	for i := range all {
+		if all[i].ID == id {
+			return &all[i], nil
+		}
+	}
+	return nil, fmt.Errorf("event not found: %s", ref)
+}
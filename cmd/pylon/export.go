@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/checkpoint"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runDiscordExport writes a channel's full history, or everything since a
+// date, to stdout as JSON, CSV or Markdown. Progress is checkpointed in the
+// state directory until the export is written.
+func runDiscordExport(cfg *config.Config, client *discord.Client, args []string) {
+	fs := parseFlags(args, "discord", "export")
+	fs.noArgs()
+	if fs.Has("channel") && fs.Has("thread") {
+		fatal("use either --channel or --thread, not both")
+	}
+	channelID := cfg.DiscordChannelID
+	if v := fs.String("channel", ""); v != "" {
+		channelID = cfg.Channel(v)
+	}
+	channelID = fs.String("thread", channelID)
+	since, format := fs.String("since", ""), fs.String("format", "json")
+	resume, stats := fs.Bool("resume"), fs.Bool("stats")
+	if channelID == "" {
+		fatal("usage: pylon discord export [--channel <id> | --thread <id>] [--since <age|date>] [--format json|csv|md | --stats] [--resume]")
+	}
+	if !slices.Contains(discord.ExportFormats, format) {
+		fatal("export: unknown format %q: want json, csv or md", format)
+	}
+	if stats && fs.Has("format") {
+		fatal("use either --stats or --format, not both")
+	}
+
+	after := "0"
+	if since != "" {
+		t, err := timeutil.ParseCutoff(since, time.Now(), time.Local)
+		if err != nil {
+			fatal("export: %v", err)
+		}
+		after = discord.Snowflake(t)
+	}
+
+	// Pages are saved as they arrive, so an interrupted export can carry on
+	// from the last saved message with --resume.
+	dir, err := config.StateDir()
+	if err != nil {
+		fatal("export: %v", err)
+	}
+	path := filepath.Join(dir, "export-"+channelID+".ndjson")
+	var msgs []discord.Message
+	if resume {
+		msgs, err = checkpoint.Read[discord.Message](path)
+		if errors.Is(err, os.ErrNotExist) {
+			fatal("export: nothing to resume for channel %s", channelID)
+		}
+		if err != nil {
+			fatal("export: %v", err)
+		}
+		if len(msgs) > 0 {
+			after = msgs[len(msgs)-1].ID
+			fmt.Fprintf(os.Stderr, "pylon: resuming after %d saved message(s)\n", len(msgs))
+		}
+	} else if _, err := os.Stat(path); err == nil {
+		fmt.Fprintf(os.Stderr, "pylon: starting over; an interrupted export of %s could have been continued with --resume\n", channelID)
+	}
+	cp, err := checkpoint.Create[discord.Message](path, resume)
+	if err != nil {
+		fatal("export: %v", err)
+	}
+	err = client.HistoryPages(channelID, after, func(page []discord.Message) error {
+		msgs = append(msgs, page...)
+		return cp.Write(page)
+	})
+	if err != nil {
+		cp.Close()
+		fatal("discord export: %v\n%d message(s) saved; run again with --resume to continue", err, len(msgs))
+	}
+	if stats && len(msgs) == 0 {
+		fmt.Println(i18n.T("message.none"))
+	} else if stats {
+		fmt.Print(discord.FormatStats(discord.ComputeStats(msgs, time.Local), 10))
+	} else if err := discord.WriteExport(os.Stdout, channelID, msgs, format); err != nil {
+		fatal("discord export: %v", err)
+	}
+	if err := cp.Done(); err != nil {
+		fmt.Fprintf(os.Stderr, "pylon: export: %v\n", err)
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("export.done", len(msgs)))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commands parse their flags with parseFlags, which reads the flags a
+// command accepts from its help entry, so what `--help` documents is exactly
+// what is accepted. Every command gets the same syntax:
+//
+//	--name value and --name=value for flags that take a value
+//	--name and --name=true|false for switches
+//	--name and --name=value for flags whose value is optional
+//	-o and -f for --output and --yes, combinable as in -fo csv
+//	--format and --output as names for each other
+//	-- to end the flags, so the rest is taken literally
+//
+// Flags and arguments may be mixed in any order. An undocumented flag is
+// reported with suggestions by unknownFlag, and a missing value is an error
+// rather than being taken from the next flag; a value that starts with "-"
+// has to be given as --name=value.
+
+// shortFlags are the one-letter forms of common flags.
+var shortFlags = map[byte]string{'o': "output", 'f': "yes"}
+
+// flagAliases are other names accepted for documented flags. Commands
+// document their output format as either --output or --format.
+var flagAliases = map[string]string{"format": "output", "output": "format"}
+
+// flagSet is a command line parsed against the command at path.
+type flagSet struct {
+	path   []string
+	values map[string][]string
+	args   []string // arguments that aren't flags, in order
+}
+
+// parseFlags parses args, the arguments after the command named by path,
+// against the flags documented for that command. It exits with an error for
+// undocumented flags and flags missing their value.
+func parseFlags(args []string, path ...string) *flagSet {
+	cmds := lookup(path)
+	if len(cmds) != len(path)+1 {
+		panic("parseFlags: no help entry for " + strings.Join(path, " "))
+	}
+	cmd := cmds[len(cmds)-1]
+	fs := &flagSet{path: path, values: map[string][]string{}}
+
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			fs.args = append(fs.args, args[i+1:]...)
+			return fs
+		case strings.HasPrefix(a, "--"):
+			name, value, hasValue := strings.Cut(a[2:], "=")
+			f, ok := fs.lookup(cmd, name)
+			if !ok {
+				unknownFlag(a, path...)
+			}
+			if f.Arg != "" && !f.Optional && !hasValue {
+				if i+1 >= len(args) || isFlag(args[i+1]) {
+					fatal("--%s requires a value", f.Name)
+				}
+				i++
+				value = args[i]
+			}
+			fs.set(f, value, hasValue)
+		case isFlag(a):
+			// One or more short flags; only the last may take a value.
+			for j := 1; j < len(a); j++ {
+				name, ok := shortFlags[a[j]]
+				f, documented := fs.lookup(cmd, name)
+				if !ok || !documented {
+					unknownFlag("-"+a[j:j+1], path...)
+				}
+				value, hasValue := "", false
+				if f.Arg != "" && !f.Optional {
+					if j != len(a)-1 {
+						value, hasValue = a[j+1:], true // -ocsv
+					} else if i+1 < len(args) && !isFlag(args[i+1]) {
+						i++
+						value, hasValue = args[i], true
+					} else {
+						fatal("-%c requires a value", a[j])
+					}
+				}
+				fs.set(f, value, hasValue)
+				if hasValue {
+					break
+				}
+			}
+		default:
+			fs.args = append(fs.args, a)
+		}
+	}
+	return fs
+}
+
+// lookup finds the documented flag called name, or by one of its aliases.
+func (fs *flagSet) lookup(cmd *command, name string) (flagDoc, bool) {
+	if f, ok := cmd.flag(name); ok {
+		return f, true
+	}
+	if alias, ok := flagAliases[name]; ok {
+		return cmd.flag(alias)
+	}
+	return flagDoc{}, false
+}
+
+func (fs *flagSet) set(f flagDoc, value string, hasValue bool) {
+	if f.Arg == "" {
+		if !hasValue {
+			value = "true"
+		} else if _, err := strconv.ParseBool(value); err != nil {
+			fatal("--%s takes no value (or true/false), got %q", f.Name, value)
+		}
+	}
+	fs.values[f.Name] = append(fs.values[f.Name], value)
+}
+
+// isFlag reports whether a is a flag rather than a value: it starts with
+// "-" but isn't "-" (stdin) or a negative number. Values that look like
+// flags must be given as --name=value.
+func isFlag(a string) bool {
+	return len(a) > 1 && a[0] == '-' && !isNumber(a)
+}
+
+// isNumber reports whether a is a negative number, which is a value rather
+// than a flag.
+func isNumber(a string) bool {
+	_, err := strconv.ParseFloat(a, 64)
+	return err == nil
+}
+
+// String returns the last value given for name, or def.
+func (fs *flagSet) String(name, def string) string {
+	if v := fs.values[name]; len(v) > 0 {
+		return v[len(v)-1]
+	}
+	return def
+}
+
+// Strings returns every value given for a repeatable flag, in order.
+func (fs *flagSet) Strings(name string) []string {
+	return fs.values[name]
+}
+
+// Has reports whether name was given at all.
+func (fs *flagSet) Has(name string) bool {
+	return len(fs.values[name]) > 0
+}
+
+// Bool reports whether the switch name is on.
+func (fs *flagSet) Bool(name string) bool {
+	b, _ := strconv.ParseBool(fs.String(name, "false"))
+	return b
+}
+
+// Int returns the value of name as a positive integer, or def if it wasn't
+// given.
+func (fs *flagSet) Int(name string, def int) int {
+	v := fs.String(name, "")
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		fatal("invalid --%s %q: want a positive number", name, v)
+	}
+	return n
+}
+
+// Duration returns the value of name as a positive duration, or def if it
+// wasn't given.
+func (fs *flagSet) Duration(name string, def time.Duration) time.Duration {
+	v := fs.String(name, "")
+	if v == "" {
+		return def
+	}
+	return parsePositiveDuration(name, v)
+}
+
+// noArgs exits with an error if any arguments besides flags were given.
+func (fs *flagSet) noArgs() {
+	if len(fs.args) > 0 {
+		fatal("unexpected argument %q\nRun 'pylon %s --help' for usage.", fs.args[0], strings.Join(fs.path, " "))
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		path []string
+		args []string
+		want map[string][]string
+		rest []string
+	}{
+		{
+			name: "equals value",
+			path: []string{"cal", "event", "list"},
+			args: []string{"--feed=work", "--output=csv"},
+			want: map[string][]string{"feed": {"work"}, "output": {"csv"}},
+		},
+		{
+			name: "separate value",
+			path: []string{"cal", "event", "list"},
+			args: []string{"--feed", "work", "--category", "team"},
+			want: map[string][]string{"feed": {"work"}, "category": {"team"}},
+		},
+		{
+			name: "value that looks like a flag given with equals",
+			path: []string{"cal", "event", "list"},
+			args: []string{"--feed=--odd"},
+			want: map[string][]string{"feed": {"--odd"}},
+		},
+		{
+			name: "negative number and stdin are values",
+			path: []string{"cal", "event", "list"},
+			args: []string{"--feed", "-1", "--category", "-"},
+			want: map[string][]string{"feed": {"-1"}, "category": {"-"}},
+		},
+		{
+			name: "short flag with separate value",
+			path: []string{"cal", "event", "list"},
+			args: []string{"-o", "csv"},
+			want: map[string][]string{"output": {"csv"}},
+		},
+		{
+			name: "short flag with attached value",
+			path: []string{"cal", "event", "list"},
+			args: []string{"-ocsv"},
+			want: map[string][]string{"output": {"csv"}},
+		},
+		{
+			name: "short switch and arguments",
+			path: []string{"cal", "event", "delete"},
+			args: []string{"ev-1", "-f"},
+			want: map[string][]string{"yes": {"true"}},
+			rest: []string{"ev-1"},
+		},
+		{
+			name: "double dash ends the flags",
+			path: []string{"cal", "event", "delete"},
+			args: []string{"--yes", "--", "--not-a-flag"},
+			want: map[string][]string{"yes": {"true"}},
+			rest: []string{"--not-a-flag"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := parseFlags(tt.args, tt.path...)
+			if !reflect.DeepEqual(fs.values, tt.want) {
+				t.Errorf("values = %v, want %v", fs.values, tt.want)
+			}
+			if !reflect.DeepEqual(fs.args, tt.rest) {
+				t.Errorf("args = %q, want %q", fs.args, tt.rest)
+			}
+		})
+	}
+}
+
+func TestParseFlagsErrors(t *testing.T) {
+	// Errors exit the process, so each case runs in a copy of the test
+	// binary.
+	if args, ok := os.LookupEnv("PYLON_TEST_PARSE_ARGS"); ok {
+		parseFlags(strings.Fields(args), strings.Fields(os.Getenv("PYLON_TEST_PARSE_PATH"))...)
+		os.Exit(0)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		args string
+		want string
+	}{
+		{name: "missing value", path: "cal event list", args: "--feed", want: "--feed requires a value"},
+		{name: "value looks like a flag", path: "cal event list", args: "--feed --output csv", want: "--feed requires a value"},
+		{name: "short flag missing value", path: "cal event list", args: "-o", want: "-o requires a value"},
+		{name: "short flag value looks like a flag", path: "cal event list", args: "-o --feed x", want: "-o requires a value"},
+		{name: "unknown flag", path: "cal event list", args: "--fede x", want: "unknown flag: --fede"},
+		{name: "unknown short flag", path: "cal event list", args: "-f", want: "unknown flag: -f"},
+		{name: "switch with a value", path: "cal event delete", args: "--yes=maybe", want: "--yes takes no value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestParseFlagsErrors$")
+			cmd.Env = append(os.Environ(), "PYLON_TEST_PARSE_ARGS="+tt.args, "PYLON_TEST_PARSE_PATH="+tt.path)
+			out, err := cmd.CombinedOutput()
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+				t.Fatalf("parseFlags(%q) = %v, want exit status 1\n%s", tt.args, err, out)
+			}
+			if !strings.Contains(string(out), tt.want) {
+				t.Errorf("output = %q, want it to contain %q", out, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// readOptions are the display flags of discord read.
+type readOptions struct {
+	raw       bool // don't resolve mentions and emoji
+	reactions bool // show reaction counts under each message
+}
+
+// printMessages prints msgs, read from channelID, as discord read does.
+// Replies whose target came without them have it fetched first, so each
+// shows what it replies to.
+func printMessages(cfg *config.Config, client *discord.Client, channelID string, msgs []discord.Message, opts readOptions) {
+	client.FillReferences(channelID, msgs)
+	var r *discord.Resolver
+	if !opts.raw {
+		r = client.NewResolver(cfg.DiscordGuildID, msgs)
+	}
+	if !opts.reactions {
+		fmt.Print(r.Format(msgs))
+		return
+	}
+	for _, m := range msgs {
+		fmt.Print(r.Format([]discord.Message{m}))
+		if len(m.Reactions) > 0 {
+			fmt.Printf("    %s\n", discord.FormatReactions(m.Reactions))
+		}
+	}
+}
+
+// followChannel polls a channel for messages newer than the last one in
+// seen and prints them as they arrive, until interrupted. Polling needs no
+// gateway connection or intents, only Read Message History.
+func followChannel(cfg *config.Config, client *discord.Client, channelID string, seen []discord.Message, interval time.Duration, opts readOptions) {
+	// With nothing seen, start from now rather than the start of history.
+	last := discord.Snowflake(time.Now())
+	if len(seen) > 0 {
+		last = seen[len(seen)-1].ID
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// A failed poll is logged and retried next tick.
+		start := time.Now()
+		msgs, err := client.History(channelID, last)
+		recordRun("discord-read", start, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: discord read: %v\n", err)
+			continue
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+		printMessages(cfg, client, channelID, msgs, opts)
+		last = msgs[len(msgs)-1].ID
+	}
+}
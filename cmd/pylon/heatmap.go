@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/heatmap"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalHeatmap prints a year of event density, one cell per day, to spot
+// overloaded weeks and gaps at a glance.
+func runCalHeatmap(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "heatmap")
+	fs.noArgs()
+	feedIDs := fs.Strings("feed")
+	year := time.Now().Year()
+	if v := fs.String("year", ""); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 9999 {
+			fatal("--year: invalid year %q", v)
+		}
+		year = n
+	}
+	shades := heatmap.Shades
+	if fs.Bool("ascii") {
+		shades = heatmap.ASCII
+	}
+
+	feedIDs = resolveFeeds(client, feedIDs)
+	if len(feedIDs) == 0 {
+		feeds, err := client.ListFeeds()
+		if err != nil {
+			fatal("heatmap: %v", err)
+		}
+		for _, f := range feeds {
+			feedIDs = append(feedIDs, f.ID)
+		}
+	}
+	var events []cal.Event
+	for _, id := range feedIDs {
+		list, err := client.ListEvents(id)
+		if err != nil {
+			fatal("heatmap: %v", err)
+		}
+		events = append(events, list...)
+	}
+
+	y := heatmap.Count(events, year, time.Local)
+	fmt.Print(y.Render(shades))
+	fmt.Println()
+	if y.Total() == 0 {
+		fmt.Println(i18n.T("heatmap.empty", year))
+		return
+	}
+	busiest := y.Busiest()
+	fmt.Println(i18n.T("heatmap.busiest", busiest.Start.Format("Jan 2"), busiest.Count))
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+	if gap := y.LongestGap(jan1, jan1.AddDate(1, 0, 0)); gap.Days > 0 {
+		fmt.Println(i18n.T("heatmap.gap", gap.First.Format("Jan 2"), gap.Last.Format("Jan 2"), gap.Days))
+	}
+}
@@ -0,0 +1,1957 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// command describes a CLI command. The tree rooted at cli is the single
+// source for help output, so flags and examples live next to each other.
+type command struct {
+	Name        string
+	Aliases     []string
+	Args        string // positional synopsis, e.g. "<name> [slug]"
+	Summary     string // one line, shown in parent listings
+	Description string // optional paragraphs shown in the command's own help
+	Flags       []flagDoc
+	Examples    []string
+	Subcommands []*command
+}
+
+// flagDoc documents a single flag.
+type flagDoc struct {
+	Name     string // without dashes
+	Arg      string // value placeholder; empty for boolean flags
+	Optional bool   // the value may be left out, and is only given as --name=value
+	Help     string
+}
+
+var cli = &command{
+	Name:    "pylon",
+	Args:    "<service> <command> [flags]",
+	Summary: "interact with deployed infrastructure",
+	Description: `Configuration:
+  ~/.pylonrc            INI-style config file (optional)
+  $XDG_CONFIG_HOME/pylon/config
+                        Used when ~/.pylonrc does not exist
+  --config <path>       Use this config file instead (or PYLON_CONFIG)
+  PYLON_* env vars      Override config file values
+
+  [http] retries = N    Retry transient API failures (429/5xx), default 3
+  PYLON_HTTP_RETRIES    Env var override
+  [http] proxy = URL    Proxy for every request (default: HTTP_PROXY,
+                        HTTPS_PROXY and NO_PROXY)
+  [http] ca_file = PATH PEM bundle of extra CA certificates to trust
+  [http] insecure_skip_verify = true
+                        Don't verify TLS certificates (last resort)
+  [ui] language = es    Language for status messages (en, es, de)
+  PYLON_LANGUAGE        Env var override
+
+Output:
+  --output-file <path>  Write standard output to path instead of the terminal.
+                        The file is replaced in one step when the command
+                        finishes; a failed command leaves it untouched
+  --output-append       Append to the file as output is produced instead,
+                        for long-running captures such as NDJSON logs
+
+Request limits:
+  --max-requests <n>    Stop after n HTTP requests to cal and Discord,
+                        retries included; further requests fail
+  --requests-per-second <r>
+                        Space cal and Discord requests at most r per second
+                        (fractions such as 0.5 allowed), on top of Discord's
+                        own rate limits
+
+Network:
+  --proxy <url>         Send this run's requests through url, overriding
+                        [http] proxy and HTTP(S)_PROXY
+  --read-only           Refuse every cal and Discord request that would
+                        change something (also [http] read_only = true or
+                        PYLON_READ_ONLY=1), for dashboards and kiosks
+
+Flags take their value as --name value or --name=value; -o and -f are
+short for --output and --yes. Use -- to pass an argument that starts with
+a dash, e.g. a message.
+
+Run 'pylon help <command>' or add --help to any command for details.`,
+	Flags: []flagDoc{
+		{Name: "config", Arg: "path", Help: "Config file to use (accepted anywhere on the command line)"},
+		{Name: "output-file", Arg: "path", Help: "Write output to path, replacing it only once the command succeeds"},
+		{Name: "output-append", Help: "With --output-file, append to the file as output is produced"},
+		{Name: "max-requests", Arg: "n", Help: "Cap the number of cal and Discord API requests this run may make"},
+		{Name: "requests-per-second", Arg: "r", Help: "Limit cal and Discord API requests to r per second"},
+		{Name: "proxy", Arg: "url", Help: "Proxy for this run's requests, overriding [http] proxy"},
+		{Name: "read-only", Help: "Refuse requests that would change anything on cal or Discord"},
+	},
+	Subcommands: []*command{
+		calCommand,
+		discordCommand,
+		slackCommand,
+		configCommand,
+		envCommand,
+		doctorCommand,
+		auditConfigCommand,
+		remindCommand,
+		digestCommand,
+		retentionCommand,
+		daemonCommand,
+		bridgeCommand,
+		mcpCommand,
+		viewCommand,
+		{
+			Name:    "history",
+			Args:    "[text]",
+			Summary: "List the commands pylon has run",
+			Description: `Lists the last commands run, oldest first, numbered for pylon redo; with
+text, only those containing it. The history is kept in the state directory
+(history.jsonl, the last 1000 commands). Secrets are replaced by
+<redacted> before they are recorded: config set values of secret keys,
+flags named like a token, secret or password, webhook URLs, and URLs with
+a password or token in them. Message text is recorded as typed.`,
+			Flags: []flagDoc{
+				{Name: "count", Arg: "n", Help: "Number of commands to list (default 20)"},
+				{Name: "json", Help: "Print the entries as JSON"},
+			},
+			Examples: []string{"pylon history", "pylon history digest --count 5"},
+		},
+		{
+			Name:    "redo",
+			Args:    "[n]",
+			Summary: "Run a command from the history again",
+			Description: `Runs command n of pylon history again, from the directory it was first run
+in, so relative paths still work; negative numbers count back from the
+last command, which is the default. Names such as --channel aliases and
+defaults such as cal.default_feed are resolved again from the current
+config. Commands whose secrets were redacted can't be redone.`,
+			Flags: []flagDoc{
+				{Name: "print", Help: "Only print the command"},
+			},
+			Examples: []string{"pylon redo 42", "pylon redo -2 --print"},
+		},
+		{
+			Name:        "completion",
+			Args:        "bash|zsh|fish",
+			Summary:     "Print a shell completion script",
+			Description: completionHelp,
+			Examples:    []string{"pylon completion bash > /etc/bash_completion.d/pylon"},
+			Subcommands: []*command{
+				{
+					Name:    "install",
+					Summary: "Install the completion script for your shell",
+					Description: `Writes the script for the shell in $SHELL (or --shell) where that shell
+loads completions from, for the current user:
+  bash:  $XDG_DATA_HOME/bash-completion/completions/pylon (needs bash-completion)
+  zsh:   ${ZDOTDIR:-$HOME}/.zfunc/_pylon (must be in fpath)
+  fish:  $XDG_CONFIG_HOME/fish/completions/pylon.fish
+
+It then starts an interactive shell to check that the completion loads,
+and exits 1 with what to change if it doesn't. Run it again after
+upgrading pylon; pylon doctor says when the installed script is stale.`,
+					Flags: []flagDoc{
+						{Name: "shell", Arg: "bash|zsh|fish", Help: "Shell to install for (default: from $SHELL)"},
+						{Name: "path", Arg: "file", Help: "Write the script here instead"},
+						{Name: "no-verify", Help: "Don't start the shell to check"},
+					},
+					Examples: []string{
+						"pylon completion install",
+						"pylon completion install --shell zsh --path /usr/local/share/zsh/site-functions/_pylon",
+					},
+				},
+			},
+		},
+		{
+			Name:    "version",
+			Args:    "[--check-server]",
+			Summary: "Show version",
+			Description: `With --check-server, also asks the configured cal server for its version
+and the optional features it supports, listing the commands that need
+each, and checks that Discord still serves the API version pylon uses.
+Exits 1 with a warning if pylon is too old or too new for the server or
+either service can't be checked.`,
+			Flags: []flagDoc{
+				{Name: "check-server", Help: "Report what the cal server and Discord API support"},
+			},
+			Examples: []string{"pylon version --check-server"},
+		},
+		{
+			Name:     "help",
+			Args:     "[command...]",
+			Summary:  "Show help for a command",
+			Examples: []string{"pylon help cal event add"},
+		},
+	},
+}
+
+var calCommand = &command{
+	Name:    "cal",
+	Args:    "[--url <base-url>] <resource> <action> [flags]",
+	Summary: "Calendar subscription service",
+	Description: `Configuration:
+  ~/.pylonrc [cal] url = ...     Base URL for the cal service
+  PYLON_CAL_URL                  Env var override (default: http://localhost:8085)
+  [cal] api_key / PYLON_CAL_API_KEY
+                                 API key, sent as Authorization: Bearer <key>
+  [cal] auth_header / PYLON_CAL_AUTH_HEADER
+                                 Send the key in this header instead (e.g. X-Api-Key)
+  [cal] default_feed / PYLON_CAL_DEFAULT_FEED
+                                 Feed for pylon cal quick without --feed
+  [cal] notify_changes / PYLON_CAL_NOTIFY_CHANGES
+                                 Post a summary to Discord when an event is
+                                 patched or cancelled (see --notify-changes)
+  [cal] notify_channel / PYLON_CAL_NOTIFY_CHANNEL
+                                 Channel for those summaries (default: webhook)
+
+Wherever a feed or event ID is expected, a prefix of it will do if no other
+ID starts the same way, and a feed can also be given by its name or part of
+it (--feed work). A reference that matches several is an error listing them.
+Commands that delete or overwrite a feed (feed delete, event prune, archive
+and the two restores) take only its full name, not part of it.`,
+	Flags: []flagDoc{
+		{Name: "url", Arg: "base-url", Help: "Override the cal service base URL"},
+	},
+	Subcommands: []*command{
+		{
+			Name:    "feed",
+			Summary: "Manage calendar feeds",
+			Subcommands: []*command{
+				{
+					Name:    "create",
+					Args:    "<name> [slug]",
+					Summary: "Create a new feed",
+					Description: `The name may be several words. With three or more arguments the last one
+is the slug, a readable token for the subscription URL (/<slug>.ics);
+without it the server generates a random token.
+
+The last line of output is always a porcelain line for scripts:
+
+  feed id=<id> name=<name> token=<token> url=<url>
+
+--porcelain prints only that line. Its keys keep their names and order
+across versions; new keys are only added at the end. Values containing
+spaces, quotes or backslashes are double-quoted with Go escapes.`,
+					Flags: []flagDoc{
+						{Name: "json", Help: "Print the new feed as JSON instead"},
+						{Name: "porcelain", Help: "Print only the stable one-line summary"},
+					},
+					Examples: []string{
+						"pylon cal feed create Work",
+						"pylon cal feed create Team Calendar team-cal",
+						"pylon cal feed create Work --porcelain | sed -n 's/.* url=//p'",
+					},
+				},
+				{
+					Name:     "list",
+					Aliases:  []string{"ls"},
+					Summary:  "List all feeds",
+					Flags:    []flagDoc{{Name: "output", Arg: "text|csv", Help: "Output format (default text); -o for short"}},
+					Examples: []string{"pylon cal feed list", "pylon cal feed list -o csv > feeds.csv"},
+				},
+				{
+					Name:    "update",
+					Aliases: []string{"rename"},
+					Args:    "<id>",
+					Summary: "Rename a feed, change its slug or its .ics settings, keeping its events",
+					Description: `Changing the slug changes the subscribe URL, so existing subscribers
+stop receiving updates until they subscribe to the new one.
+
+The .ics settings change how the feed presents itself to calendar apps,
+in the embedded server's output and in cal export: --prodid replaces
+pylon's PRODID, --calendar-name the name apps show (default: the feed's
+name), --refresh-interval asks apps to poll that often (REFRESH-INTERVAL
+and X-PUBLISHED-TTL), and --alarms=false leaves the events' alarms out for
+subscribers who set their own. An empty value restores the default.`,
+					Flags: []flagDoc{
+						{Name: "name", Arg: "name", Help: "New display name"},
+						{Name: "slug", Arg: "slug", Help: "New readable token for the subscribe URL"},
+						{Name: "prodid", Arg: "id", Help: "PRODID of the .ics output"},
+						{Name: "calendar-name", Arg: "name", Help: "Name calendar apps show (X-WR-CALNAME)"},
+						{Name: "refresh-interval", Arg: "duration", Help: "How often apps should refetch, e.g. 1h or PT1H"},
+						{Name: "alarms", Help: "Include the events' alarms (default); --alarms=false leaves them out"},
+					},
+					Examples: []string{
+						`pylon cal feed update 3f2a... --name "Team Calendar"`,
+						"pylon cal feed update 3f2a... --slug team-cal",
+						`pylon cal feed update 3f2a... --calendar-name "ACME Team" --refresh-interval 1h --alarms=false`,
+						`pylon cal feed update 3f2a... --prodid ""`,
+					},
+				},
+				{
+					Name:    "rotate-token",
+					Args:    "<id>",
+					Summary: "Issue a new subscription token, invalidating the old URL",
+					Description: `Use this when a subscribe URL has leaked. Existing subscribers stop
+receiving updates until they subscribe to the new URL.`,
+					Flags: []flagDoc{{Name: "slug", Arg: "slug", Help: "Readable token to use instead of a random one"}},
+					Examples: []string{
+						"pylon cal feed rotate-token 3f2a...",
+						"pylon cal feed rotate-token 3f2a... --slug team-cal-2",
+					},
+				},
+				{
+					Name:    "delete",
+					Aliases: []string{"rm"},
+					Args:    "<id>",
+					Summary: "Delete a feed and all its events",
+					Description: `Shows the feed's name and how many events it holds and asks before
+deleting. Pass --yes (or -f) in scripts: without a terminal to ask on it
+fails, and answering no exits 1.`,
+					Flags:    []flagDoc{{Name: "yes", Help: "Don't ask for confirmation; -f for short"}},
+					Examples: []string{"pylon cal feed delete 3f2a...", "pylon cal feed delete 3f2a... --yes"},
+				},
+			},
+		},
+		{
+			Name:    "event",
+			Summary: "Manage calendar events",
+			Subcommands: []*command{
+				{
+					Name:    "add",
+					Aliases: []string{"create"},
+					Args:    "[summary] [flags]",
+					Summary: "Create a new event",
+					Description: `The last line of output is always a porcelain line for scripts:
+
+  event action=<created|updated> id=<id> feed_id=<id> external_id=<uid> url=<url>
+
+--porcelain prints only that line, with the same stability guarantees as
+pylon cal feed create --porcelain; --json prints the event instead.`,
+					Flags: []flagDoc{
+						{Name: "feed", Arg: "id", Help: "Feed ID (required)"},
+						{Name: "summary", Arg: "text", Help: "Event title (required; or pass it positionally)"},
+						{Name: "start", Arg: "datetime", Help: "Start time in RFC 3339 format (required)"},
+						{Name: "end", Arg: "datetime", Help: "End time in RFC 3339 format"},
+						{Name: "duration", Arg: "duration", Help: "End this long after the start, e.g. 45m or 2d, instead of --end"},
+						{Name: "description", Arg: "text", Help: "Longer description"},
+						{Name: "location", Arg: "text", Help: "Where the event happens"},
+						{Name: "url", Arg: "url", Help: "Link shown with the event"},
+						{Name: "all-day", Help: "Mark as all-day event"},
+						{Name: "deadline", Arg: "datetime", Help: "Deadline with alarm"},
+						{Name: "alarm", Arg: "duration", Help: "Notify subscribers this long before the start, e.g. 15m or 1d, or 0 at the start (repeatable)"},
+						{Name: "status", Arg: "status", Help: "TENTATIVE, CONFIRMED, or CANCELLED"},
+						{Name: "categories", Arg: "list", Help: "Comma-separated categories"},
+						{Name: "external-id", Arg: "uid", Help: "Stable ID from your automation; re-runs update the same event"},
+						{Name: "rrule", Arg: "rule", Help: "Repeat by an RFC 5545 rule, e.g. FREQ=WEEKLY;BYDAY=MO"},
+						{Name: "exdate", Arg: "datetime", Help: "Skip the occurrence starting at this time (repeatable)"},
+						{Name: "json", Help: "Print the event as JSON instead"},
+						{Name: "porcelain", Help: "Print only the stable one-line summary"},
+					},
+					Examples: []string{
+						"pylon cal event add --feed 3f2a... --summary Standup --start 2026-03-02T09:00:00Z",
+						"pylon cal event add Retro --feed 3f2a... --start 2026-03-06T10:00:00Z --duration 45m",
+						"pylon cal event add Launch --feed 3f2a... --start 2026-04-01T00:00:00Z --all-day",
+						"pylon cal event add Review --feed 3f2a... --start 2026-03-05T14:00:00Z --alarm 1d --alarm 15m",
+						"pylon cal event add Deploy --feed 3f2a... --start 2026-03-02T15:00:00Z --external-id ci-$PIPELINE_ID",
+						"pylon cal event add Standup --feed 3f2a... --start 2026-03-02T09:00:00+01:00 --rrule 'FREQ=WEEKLY;BYDAY=MO,WE,FR'",
+					},
+				},
+				{
+					Name:    "list",
+					Aliases: []string{"ls"},
+					Summary: "List events for a feed",
+					Flags: []flagDoc{
+						{Name: "feed", Arg: "id", Help: "Feed ID (required)"},
+						{Name: "category", Arg: "name", Help: "Only events with this category"},
+						{Name: "output", Arg: "text|csv", Help: "Output format (default text); -o for short"},
+					},
+					Examples: []string{
+						"pylon cal event list --feed 3f2a...",
+						"pylon cal event list --feed 3f2a... --category work",
+						"pylon cal event list --feed 3f2a... --output csv | xsv select summary,start",
+					},
+				},
+				{
+					Name:    "show",
+					Aliases: []string{"get"},
+					Args:    "<id>",
+					Summary: "Show every field of one event",
+					Flags:   []flagDoc{{Name: "json", Help: "Print the event as JSON"}},
+					Examples: []string{
+						"pylon cal event show 7c1e...",
+						"pylon cal event show 7c1e... --json | jq .deadline",
+					},
+				},
+				{
+					Name:    "mirror",
+					Args:    "<id> --to <feed-id> | --sync",
+					Summary: "Copy an event into other feeds and keep the copies in sync",
+					Description: `Creates a linked copy of the event in each --to feed, for events that
+belong on more than one calendar. Mirroring the same event again updates
+its copy.
+
+--sync brings every mirror (or those in the --feed feeds) back in step:
+mirrors of changed or cancelled events are updated, and mirrors of deleted
+events are deleted. On its own (or with --plan) it only prints the changes
+as a diff; --apply makes them. Run it with --apply from cron, or after
+editing a mirrored event. Mirrors need a cal server with upsert support.
+
+` + lockHelp,
+					Flags: append([]flagDoc{
+						{Name: "to", Arg: "feed-id", Help: "Feed to mirror into (repeatable)"},
+						{Name: "sync", Help: "Update or delete mirrors whose source changed"},
+						{Name: "feed", Arg: "feed-id", Help: "With --sync, only mirrors in this feed (repeatable)"},
+						{Name: "plan", Help: "With --sync, print the changes without making them (the default)"},
+						{Name: "apply", Help: "With --sync, make the changes"},
+					}, lockFlags...),
+					Examples: []string{
+						"pylon cal event mirror 7c1e... --to 9b1c...",
+						"pylon cal event mirror --sync --plan",
+						"pylon cal event mirror --sync --apply",
+					},
+				},
+				{
+					Name:    "prune",
+					Args:    "--feed <id> --before <age|date>",
+					Summary: "Delete every event that started before a date",
+					Description: `Lists the events in the feed that started before --before and asks
+before deleting them. A recurring event counts once its last occurrence is
+before the cutoff; a series without an end is never pruned. Nothing is
+kept; use pylon cal archive to save a copy first. Exits 1 if any deletion
+failed.`,
+					Flags: []flagDoc{
+						{Name: "feed", Arg: "id", Help: "Feed ID (required)"},
+						{Name: "before", Arg: "age|date", Help: "Cutoff: RFC 3339, YYYY-MM-DD or an age like 1y (required)"},
+						{Name: "yes", Help: "Don't ask for confirmation; -f for short"},
+					},
+					Examples: []string{
+						"pylon cal event prune --feed 3f2a... --before 2025-01-01",
+						"pylon cal event prune --feed 3f2a... --before 1y --yes",
+					},
+				},
+				{
+					Name:    "patch",
+					Args:    "--feed <id> [--filter field=value]... [<patch.json|->]",
+					Summary: "Change many events at once with a JSON merge patch",
+					Description: `Applies a JSON merge patch (RFC 7396), read from a file or stdin ("-"),
+to every event in the --feed feeds that matches all the --filter
+conditions. The patch names fields as the create API does: {"location":
+"New office"} sets the location, {"alarms": null} removes every alarm.
+
+--alarm sets the matching events' alarms, replacing any they have, and
+--no-alarms removes them; either can stand in for the patch or be combined
+with it.
+
+Filters are field=value, where field is category (one of the event's
+categories), status, external_id (the whole value), or summary, location
+or description (any part of it); all but external_id ignore case.
+
+On its own (or with --plan) it only prints the changes as a diff; --apply
+makes them. Events are updated in place, keeping their IDs; cal servers
+without that can only patch events that have an external ID.
+
+` + notifyChangesHelp,
+					Flags: []flagDoc{
+						{Name: "feed", Arg: "id", Help: "Feed whose events to patch (required, repeatable)"},
+						{Name: "filter", Arg: "field=value", Help: "Only patch matching events (repeatable; all must match)"},
+						{Name: "alarm", Arg: "duration", Help: "Notify subscribers this long before the start, e.g. 15m or 1d, or 0 at the start (repeatable)"},
+						{Name: "no-alarms", Help: "Remove every alarm"},
+						{Name: "plan", Help: "Print the changes without making them (the default)"},
+						{Name: "apply", Help: "Make the changes"},
+						{Name: "notify-changes", Help: "Post what changed to Discord (default: cal.notify_changes)"},
+					},
+					Examples: []string{
+						`echo '{"location": "Office B, 2nd floor"}' | pylon cal event patch --feed 3f2a... --filter category=work -`,
+						"pylon cal event patch --feed 3f2a... --filter location=old --apply patch.json",
+						"pylon cal event patch --feed 3f2a... --filter category=standup --alarm 10m --apply",
+					},
+				},
+				{
+					Name:    "move",
+					Aliases: []string{"mv"},
+					Args:    "<id> --to-feed <feed-id>",
+					Summary: "Move an event to another feed",
+					Description: `Creates the event in the --to-feed feed and deletes the original. The
+moved event keeps its external ID, UID and SEQUENCE but gets a new ID, so
+mirrors of it are removed by the next mirror --sync; mirror it again.
+
+The cal API can't move an event in one step. If deleting the original
+fails, the new copy is deleted again so the event stays where it was; if
+that fails too, both IDs are printed so you can delete one.`,
+					Flags:    []flagDoc{{Name: "to-feed", Arg: "feed-id", Help: "Feed to move the event to (required)"}},
+					Examples: []string{"pylon cal event move 7c1e... --to-feed 9b1c..."},
+				},
+				{
+					Name:    "copy",
+					Aliases: []string{"cp"},
+					Args:    "<id> --to-feed <feed-id>",
+					Summary: "Copy an event to another feed",
+					Description: `Creates an independent copy of the event in the --to-feed feed: a new
+event without the original's external ID or UID, which later edits of
+either leave alone. Use mirror for a copy that follows the original.`,
+					Flags:    []flagDoc{{Name: "to-feed", Arg: "feed-id", Help: "Feed to copy the event to (required)"}},
+					Examples: []string{"pylon cal event copy 7c1e... --to-feed 9b1c..."},
+				},
+				{
+					Name:    "cancel",
+					Args:    "<id>",
+					Summary: "Cancel an event so subscribers remove it",
+					Description: `Marks the event CANCELLED and raises its SEQUENCE. Calendar apps
+subscribed to the feed then drop it on their next refresh, where deleting
+the event leaves them showing the last copy they fetched. On a cal server
+without the cancel endpoint, the event is updated with STATUS:CANCELLED
+instead (by upsert where updates aren't supported, which needs an external
+ID). pylon cal event confirm undoes it.
+
+` + notifyChangesHelp,
+					Flags: []flagDoc{
+						{Name: "notify-changes", Help: "Post the cancellation to Discord (default: cal.notify_changes)"},
+					},
+					Examples: []string{
+						"pylon cal event cancel 7c1e...",
+						"pylon cal event cancel 7c1e... --notify-changes",
+					},
+				},
+				{
+					Name:    "confirm",
+					Args:    "<id>",
+					Summary: "Confirm an event, bringing a cancelled one back",
+					Description: `Updates the event with STATUS:CONFIRMED, which raises its SEQUENCE, so
+calendar apps subscribed to the feed show a cancelled event again on their
+next refresh. An event that is already confirmed is left alone.
+
+` + notifyChangesHelp,
+					Flags: []flagDoc{
+						{Name: "notify-changes", Help: "Post that the event is back on to Discord (default: cal.notify_changes)"},
+					},
+					Examples: []string{"pylon cal event confirm 7c1e..."},
+				},
+				{
+					Name:    "attach",
+					Args:    "<id>",
+					Summary: "Attach files such as flyers to an event, hosted on Discord",
+					Description: `Uploads the files with the bot token to discord.attachment_channel (or
+--channel) and adds their CDN URLs to the event, which the feed publishes
+as ATTACH so calendar apps can show them. The URLs are printed.
+
+Discord expires attachment links after about a day when they are opened
+outside Discord; --refresh renews the event's links, so run it from cron
+(or before announcing the event) to keep them working.`,
+					Flags: []flagDoc{
+						{Name: "file", Arg: "path", Help: "File to attach (repeatable)"},
+						{Name: "channel", Arg: "id", Help: "Channel to upload to (default: discord.attachment_channel)"},
+						{Name: "refresh", Help: "Renew the event's Discord attachment links instead"},
+					},
+					Examples: []string{
+						"pylon cal event attach 7c1e... --file poster.png",
+						"pylon cal event attach 7c1e... --refresh",
+					},
+				},
+				{
+					Name:     "delete",
+					Aliases:  []string{"rm"},
+					Args:     "<id>",
+					Summary:  "Delete an event",
+					Flags:    []flagDoc{{Name: "yes", Help: "Don't ask for confirmation; -f for short"}},
+					Examples: []string{"pylon cal event delete 9c1b...", "pylon cal event delete 9c1b... -f"},
+				},
+			},
+		},
+		{
+			Name:    "subscribe",
+			Args:    "<token> | <feed-id> --expires <ttl>",
+			Summary: "Get subscription URLs for a feed",
+			Description: `Prints the https and webcal:// URLs for a feed token. With --expires the
+argument is a feed ID and the server issues a time-limited signed URL.
+--open hands the webcal:// URL to the system calendar app; --copy puts the
+https URL on the clipboard (clip on Windows, pbcopy on macOS, wl-copy,
+xclip or xsel elsewhere).`,
+			Flags: []flagDoc{
+				{Name: "expires", Arg: "ttl", Help: "Signed URL lifetime, e.g. 30d or 12h"},
+				{Name: "open", Help: "Open the webcal:// URL in the default calendar app"},
+				{Name: "copy", Help: "Copy the subscribe URL to the clipboard"},
+			},
+			Examples: []string{
+				"pylon cal subscribe team-cal",
+				"pylon cal subscribe 3f2a... --expires 30d",
+				"pylon cal subscribe team-cal --open",
+			},
+		},
+		{
+			Name:    "subscribers",
+			Args:    "--feed <feed-id>",
+			Summary: "Show who fetches a feed's subscription URLs",
+			Description: `Lists each subscription token of the feed with its fetch count, the
+number of distinct User-Agents (roughly, subscribers) and the last fetch,
+so you can tell whether anyone still subscribes before deleting a feed.
+Needs a cal server that tracks ICS fetches.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Feed to report on (required)"},
+				{Name: "agents", Help: "Also list each User-Agent's fetches"},
+			},
+			Examples: []string{"pylon cal subscribers --feed 3f2a... --agents"},
+		},
+		{
+			Name:    "archive",
+			Summary: "Move old events to local JSON archives",
+			Description: `Exports events that started before the cutoff to one JSON file per feed
+in <dir>, then deletes them from the live feed. Cutoffs may be an age
+(1y, 90d) or a date (2025-01-01). A recurring event is only archived once
+its last occurrence is before the cutoff, so a series without an end
+(no COUNT or UNTIL) is never archived.
+
+Lists how many events each feed would lose and asks before archiving them;
+pass --yes (or -f) in scripts.`,
+			Flags: []flagDoc{
+				{Name: "before", Arg: "age|date", Help: "Archive events starting before this (required)"},
+				{Name: "out", Arg: "dir", Help: "Directory for archive files (required)"},
+				{Name: "feed", Arg: "id", Help: "Only archive this feed"},
+				{Name: "dry-run", Help: "Report what would be archived"},
+				{Name: "yes", Help: "Don't ask for confirmation; -f for short"},
+			},
+			Examples: []string{
+				"pylon cal archive --before 1y --out archive/",
+				"pylon cal archive restore archive/ --from 2024-01-01 --to 2024-07-01",
+			},
+			Subcommands: []*command{
+				{
+					Name:    "restore",
+					Args:    "<file|dir>",
+					Summary: "Recreate archived events",
+					Description: `Recreates the events in an archive file, or every archive in a
+directory, in their feed. Events that are already there, matched by ID,
+external ID or UID as pylon cal restore does, are updated if they differ
+and otherwise left alone, so an archive can be restored again, or a failed
+restore resumed, without duplicating anything.`,
+					Flags: []flagDoc{
+						{Name: "feed", Arg: "id", Help: "Restore into this feed instead of the original"},
+						{Name: "from", Arg: "date", Help: "Only events starting at or after this"},
+						{Name: "to", Arg: "date", Help: "Only events starting before this"},
+					},
+					Examples: []string{"pylon cal archive restore archive/3f2a...-20260101T000000Z.json"},
+				},
+			},
+		},
+		{
+			Name:    "backup",
+			Summary: "Save every feed and its events to a JSON file",
+			Description: `Writes every feed, with its subscription token and .ics settings, and
+all of its events to one JSON file, "-" for stdout, for pylon cal restore.
+Take one before upgrading or moving the cal service. The tokens are
+secrets: anyone with them can subscribe to the feeds, so the file is only
+readable by you.`,
+			Flags: []flagDoc{
+				{Name: "out", Arg: "file", Help: "File to write (required); - for stdout"},
+				{Name: "feed", Arg: "id", Help: "Only back up this feed (repeatable)"},
+			},
+			Examples: []string{
+				"pylon cal backup --out backup.json",
+				"pylon cal backup --out - --feed team | gzip > team.json.gz",
+			},
+		},
+		{
+			Name:    "restore",
+			Args:    "<file|->",
+			Summary: "Recreate the feeds and events of a backup",
+			Description: `Brings the cal service in line with a backup from pylon cal backup. Feeds
+are matched by ID, or else by token, and missing ones are created with
+their old token, so subscribe URLs keep working. Events are matched by ID,
+external ID or UID. Missing ones are created with their UID and SEQUENCE,
+so calendar apps see the same events, and under their external ID, or
+restore:<id> if they have none, so they are found again on servers that
+don't keep UIDs. Feeds and events that differ from
+the backup are changed back; anything not in the backup is left alone.
+
+Restoring is idempotent: run it again, for instance after a failure, and
+only what is still missing or different changes. Like the bridges, it
+prints the changes unless --apply is given.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Only restore this feed, by its ID or name in the backup (repeatable)"},
+				{Name: "plan", Help: "Print the changes without making them (the default)"},
+				{Name: "apply", Help: "Make the changes"},
+			},
+			Examples: []string{
+				"pylon cal restore backup.json",
+				"pylon cal restore backup.json --apply",
+				"pylon cal --url http://new-cal:8085 restore backup.json --feed team --apply",
+			},
+		},
+		{
+			Name:    "import",
+			Args:    "<file|url|-> --feed <id>",
+			Summary: "Create events from an ICS file or URL",
+			Description: `Reads an iCalendar file (a path or file:// URL), an http(s) or webcal://
+URL, or stdin ("-") and creates its events in the feed. Alarms (VALARM)
+relative to the event are kept as its alarms; the earliest alarm at a
+fixed time becomes its deadline, which the cal service exports with an
+alarm, and further fixed-time alarms are reported. RRULE and EXDATE are
+kept, so recurring events repeat in the agenda and in reminders.
+
+--uid keeps each event's UID and SEQUENCE, so the feed publishes it as the
+same event the source calendar has: calendar apps that see both treat an
+edit as an update instead of a new event. The cal server raises SEQUENCE
+on every later change.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Feed to import into (required)"},
+				{Name: "upsert", Help: "Use each event's UID as its external ID so re-imports update events"},
+				{Name: "uid", Help: "Publish each event under its source UID and SEQUENCE"},
+				{Name: "dry-run", Help: "Parse and report without creating events"},
+			},
+			Examples: []string{
+				"pylon cal import calendar.ics --feed 3f2a...",
+				"pylon cal import webcal://example.com/team.ics --feed 3f2a... --upsert --uid",
+			},
+		},
+		{
+			Name:    "export",
+			Summary: "Write a feed as an ICS file",
+			Description: `Prints the feed's events as an iCalendar file, the counterpart of import.
+Use the global --output-file to write it to a file.
+
+--redact turns it into a free/busy calendar that is safe to share outside
+the team: every event is called "Busy" and keeps only its times, duration
+and recurrence. Descriptions, locations, links, categories, attachments and
+alarms are left out, UIDs are replaced by opaque ones and cancelled events
+are dropped.
+
+Cancelled events are otherwise exported with STATUS:CANCELLED, so a calendar
+app that imported them before removes them; --skip-cancelled leaves them out.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Feed to export (required)"},
+				{Name: "redact", Help: "Replace everything but the times with \"Busy\""},
+				{Name: "skip-cancelled", Help: "Leave cancelled events out"},
+			},
+			Examples: []string{
+				"pylon --output-file team.ics cal export --feed 3f2a...",
+				"pylon cal export --feed 3f2a... --redact > busy.ics",
+			},
+		},
+		{
+			Name:    "sync",
+			Args:    "<source> [flags]",
+			Summary: "Keep a feed in step with a calendar kept elsewhere",
+			Subcommands: []*command{
+				{
+					Name:    "gcal",
+					Aliases: []string{"google"},
+					Args:    "--calendar <id> --feed <id>",
+					Summary: "Copy a Google calendar's upcoming events into a feed",
+					Description: `One-way sync from Google Calendar: the events of the next --days days are
+created in the feed, changed ones updated and deleted ones removed, so the
+team subscribed to the feed sees them. Recurring events are copied one
+occurrence at a time. Past copies are kept, and nothing is written to Google.
+
+--credentials is a file from the Google Cloud console, either a service
+account key (share the calendar with the account's email address) or an
+OAuth client of the "TVs and Limited Input devices" type. An OAuth client is
+authorized once: pylon prints a URL and a code to enter there, and keeps
+the refresh token in the state directory for later runs.
+
+On its own (or with --plan) it only prints the changes as a diff; --apply
+makes them. Run it with --apply from cron. Needs a cal server with upsert
+support.
+
+` + lockHelp,
+					Flags: append([]flagDoc{
+						{Name: "calendar", Arg: "id", Help: "Google calendar ID, e.g. an email address or primary (required)"},
+						{Name: "feed", Arg: "id", Help: "Feed to sync into (default: cal.default_feed)"},
+						{Name: "credentials", Arg: "file", Help: "Service account key or OAuth client file (default: gcal.credentials)"},
+						{Name: "days", Arg: "N", Help: "How far ahead to sync (default 180)"},
+						{Name: "plan", Help: "Print the changes without making them (the default)"},
+						{Name: "apply", Help: "Make the changes"},
+					}, lockFlags...),
+					Examples: []string{
+						"pylon cal sync gcal --calendar me@example.com --feed 3f2a... --credentials google.json",
+						"pylon cal sync gcal --calendar primary --feed 3f2a... --apply",
+					},
+				},
+			},
+		},
+		{
+			Name:    "agenda",
+			Summary: "Show upcoming events grouped by day",
+			Description: `Merges the events of every feed (or only the --feed ones), groups them by
+day in local time and shows how far away each one is. Deadlines get a line
+of their own on the day they fall due; cancelled events are left out.
+--pins lists the current pins (see pylon cal pin) first, whatever their
+date, instead of on the day they were pinned.`,
+			Flags: []flagDoc{
+				{Name: "days", Arg: "n", Help: "Number of days to show, starting today (default 7)"},
+				{Name: "feed", Arg: "id", Help: "Only show this feed (repeatable)"},
+				{Name: "pins", Help: "List current pins above the agenda"},
+			},
+			Examples: []string{
+				"pylon cal agenda",
+				"pylon cal agenda --days 1 --feed 3f2a...",
+				"pylon cal agenda --pins",
+			},
+		},
+		{
+			Name:    "search",
+			Args:    "<query> [flags]",
+			Summary: "Find events across feeds",
+			Description: `Lists the events whose summary, description or location contains every
+word of <query> (ignoring case), with the feed each belongs to, oldest
+first. --from and --to take a date, an RFC 3339 time or an age such as 30d
+and limit the results by start time.
+
+Servers with a search endpoint do the matching; with older servers every
+feed's events are listed and matched locally, which takes one request per
+feed (see --max-requests).`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Only search this feed (repeatable)"},
+				{Name: "category", Arg: "name", Help: "Only events with this category"},
+				{Name: "from", Arg: "date", Help: "Only events starting at or after"},
+				{Name: "to", Arg: "date", Help: "Only events starting before"},
+			},
+			Examples: []string{
+				"pylon cal search standup",
+				`pylon cal search "board games" --category social --from 2026-01-01`,
+				"pylon cal search --category pin --feed 3f2a...",
+			},
+		},
+		{
+			Name:    "categories",
+			Summary: "List the categories in use, with event counts",
+			Description: `Counts how many events carry each category, most used first, to help
+keep the taxonomy consistent. Spellings are counted separately, so "Work"
+and "work" both show up. Without --feed every feed is counted.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Only count this feed (repeatable)"},
+				{Name: "output", Arg: "text|csv", Help: "Output format (default text); -o for short"},
+			},
+			Examples: []string{
+				"pylon cal categories --feed 3f2a...",
+				"pylon cal categories -o csv",
+			},
+		},
+		{
+			Name:    "heatmap",
+			Summary: "Show a year of event density, one cell per day",
+			Description: `Draws the year as weeks in columns, Monday to Sunday top to bottom, with
+each day shaded by how many events fall on it relative to the busiest day,
+followed by the busiest week and the longest run of empty days. Recurring
+events count once per occurrence and events spanning several days count
+on each; cancelled events are left out. Without --feed every feed is
+counted.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Only count this feed (repeatable)"},
+				{Name: "year", Arg: "yyyy", Help: "Year to show (default: this year)"},
+				{Name: "ascii", Help: "Shade with ASCII characters instead of blocks"},
+			},
+			Examples: []string{
+				"pylon cal heatmap --feed 3f2a... --year 2026",
+				"pylon cal heatmap --ascii",
+			},
+		},
+		{
+			Name:    "serve",
+			Args:    "[flags]",
+			Summary: "Run a local cal service for development",
+			Description: `Serves a minimal implementation of the cal API on --host:--port until
+interrupted, so pylon works without a deployed cal service: feeds, events,
+upserts, search and the public /<token>.ics subscription URLs. Signed
+subscribe URLs and subscriber statistics are not available.
+
+Feeds and events are kept in a JSON file, cal-serve.json in the state
+directory unless --data names another; --memory keeps nothing. There is no
+authentication, so it listens on 127.0.0.1 unless --host says otherwise.
+Point pylon at it with PYLON_CAL_URL=http://127.0.0.1:8085 (the default).
+Feed URLs are built from the address a request came to; behind a proxy,
+--base-url sets the public URL instead.
+
+Cancelled events stay in the .ics feeds with STATUS:CANCELLED, which is
+what makes subscribed calendar apps remove them; --skip-cancelled leaves
+them out instead.
+
+Recurring events whose start has the UTC offset --timezone (default: the
+local time zone) has at that time are published in that zone, with a
+VTIMEZONE, so their occurrences stay at the same local time across daylight
+saving changes. Other events are published in UTC.`,
+			Flags: []flagDoc{
+				{Name: "port", Arg: "n", Help: "Port to listen on (default 8085)"},
+				{Name: "host", Arg: "addr", Help: "Address to listen on (default 127.0.0.1)"},
+				{Name: "data", Arg: "file", Help: "JSON file to keep feeds and events in"},
+				{Name: "memory", Help: "Keep everything in memory"},
+				{Name: "skip-cancelled", Help: "Leave cancelled events out of the .ics feeds"},
+				{Name: "base-url", Arg: "url", Help: "Public URL feed subscription URLs are built on"},
+				{Name: "timezone", Arg: "zone", Help: "IANA time zone recurring events are published in (default: local)"},
+			},
+			Examples: []string{
+				"pylon cal serve",
+				"pylon cal serve --port 9000 --data ./dev-cal.json",
+			},
+		},
+		{
+			Name:    "pin",
+			Summary: "Pin undated announcements to a feed",
+			Description: `A pin is information that belongs on the calendar but has no time, such
+as "Wifi password rotates Friday". It is stored as an all-day event with
+the category "pin" on the day it was pinned (through --until, if given),
+so calendar apps show it as an all-day banner, and pylon cal agenda --pins
+lists every current pin at the top.`,
+			Subcommands: []*command{
+				{
+					Name:    "add",
+					Args:    "<text>",
+					Summary: "Pin an announcement",
+					Flags: []flagDoc{
+						{Name: "feed", Arg: "id", Help: "Feed to pin to (default: [cal] default_feed)"},
+						{Name: "until", Arg: "date|age", Help: "Keep it up through this date (2026-03-06) or for this long (7d)"},
+					},
+					Examples: []string{
+						`pylon cal pin add --feed 3f2a... "Wifi password rotates Friday"`,
+						`pylon cal pin add "Office closed for renovation" --until 2026-03-06`,
+					},
+				},
+				{
+					Name:     "list",
+					Aliases:  []string{"ls"},
+					Summary:  "List pins, including expired ones",
+					Flags:    []flagDoc{{Name: "feed", Arg: "id", Help: "Only this feed"}},
+					Examples: []string{"pylon cal pin list"},
+				},
+				{
+					Name:     "remove",
+					Aliases:  []string{"rm"},
+					Args:     "<id>",
+					Summary:  "Take a pin down",
+					Flags:    []flagDoc{{Name: "yes", Help: "Don't ask for confirmation; -f for short"}},
+					Examples: []string{"pylon cal pin remove 7c1e..."},
+				},
+			},
+		},
+		{
+			Name:    "verify-subscription",
+			Args:    "<token|url>",
+			Summary: "Check a feed's public ICS URL end to end",
+			Description: `Fetches the subscribe URL without credentials, as a calendar app would,
+checks the response (status, Content-Type, UTF-8, CRLF line endings, line
+folding, time zones, the checks of lint), parses it and compares its events with the API's
+listing for the feed. Missing, extra or differing events are reported.
+Exits 1 if anything was found.`,
+			Examples: []string{
+				"pylon cal verify-subscription team-calendar",
+				"pylon cal verify-subscription webcal://cal.example.com/team-calendar.ics",
+			},
+		},
+		{
+			Name:    "lint",
+			Summary: "Validate a feed's .ics output",
+			Description: `Fetches the feed's .ics output and reports what makes Apple and Google
+Calendar drop events without a word: events without a UID or DTSTART,
+UIDs shared by several events, events ending before they start or with
+both DTEND and DURATION, unbalanced BEGIN/END, unknown TZIDs, and broken
+lines (bare LF, unfolded long lines, invalid UTF-8). Exits 1 if anything
+was found; verify-subscription also compares the feed with the API.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Feed to check (required, repeatable)"},
+			},
+			Examples: []string{"pylon cal lint --feed 3f2a..."},
+		},
+		{
+			Name:    "quick",
+			Args:    "<description>",
+			Summary: "Create an event from a one-line description",
+			Description: `Parses the summary, date, time, location and categories from one line,
+like Google Calendar's quick add, and creates the event in the default
+feed ([cal] default_feed) or --feed.
+
+  Dates       today, tomorrow, Friday, next Monday, Mar 5, 5 March,
+              2026-03-05 (a weekday or month-day means the next one)
+  Times       9am, 9:30pm, 14:00, noon; ranges 9am-10am, 11-1pm,
+              from 9 to 11; "at 9" and "from 9" read a bare hour as 24-hour
+  Length      for 45m, for 2h (events without an end last an hour)
+  Location    at Main St Clinic (up to the next date, time or #tag)
+  Categories  #health #team
+
+Without a time the event is all-day; without a date it is today, or
+tomorrow once the time has passed. Check the result with --dry-run, which
+prints the event that would be created as JSON with --json. Output is the
+same as for pylon cal event add.`,
+			Flags: []flagDoc{
+				{Name: "feed", Arg: "id", Help: "Feed to add to (default: [cal] default_feed)"},
+				{Name: "dry-run", Help: "Print the parsed event without creating it"},
+				{Name: "json", Help: "Print the event as JSON instead"},
+				{Name: "porcelain", Help: "Print only the stable one-line summary"},
+			},
+			Examples: []string{
+				`pylon cal quick "Dentist Tuesday 9am-10am at Main St Clinic #health"`,
+				`pylon cal quick "Lunch with Sam tomorrow at noon for 90m" --dry-run`,
+				`pylon cal quick "Offsite next Monday #team" --feed 3f2a...`,
+			},
+		},
+	},
+}
+
+var slackCommand = &command{
+	Name:    "slack",
+	Args:    "<command> [flags]",
+	Summary: "Slack messaging and channel access",
+	Description: `Configuration (~/.pylonrc [slack] section or env vars):
+  bot_token    / PYLON_SLACK_BOT_TOKEN    Bot token (xoxb-...) for reading and posting
+  webhook      / PYLON_SLACK_WEBHOOK      Incoming webhook URL for posting
+  channel_id   / PYLON_SLACK_CHANNEL_ID   Default channel ID for reading
+
+The bot needs the chat:write, channels:history, channels:read and
+users:read scopes (groups:* for private channels), and must be a member of
+the channels it reads or posts to.`,
+	Subcommands: []*command{
+		{
+			Name:    "msg",
+			Aliases: []string{"send"},
+			Args:    "[flags] <message>",
+			Summary: "Send a message via webhook (or bot token)",
+			Flags: []flagDoc{
+				{Name: "channel", Arg: "id", Help: "Send via bot token to this channel instead"},
+				{Name: "thread", Arg: "ts", Help: "Reply in the thread of this message (bot token)"},
+			},
+			Examples: []string{
+				`pylon slack msg "deploy finished"`,
+				`pylon slack msg --channel C0123456789 --thread 1712345678.000200 "on it"`,
+			},
+		},
+		{
+			Name:    "read",
+			Summary: "Read recent messages from a channel",
+			Flags: []flagDoc{
+				{Name: "channel", Arg: "id", Help: "Channel to read (default: channel_id)"},
+				{Name: "count", Arg: "n", Help: "Number of messages (default 20)"},
+				{Name: "raw", Help: "Show user IDs instead of looking up names"},
+			},
+			Examples: []string{"pylon slack read --channel C0123456789 --count 50"},
+		},
+		{
+			Name:    "channels",
+			Summary: "List the channels the bot can see",
+			Flags: []flagDoc{
+				{Name: "archived", Help: "Include archived channels"},
+				{Name: "output", Arg: "text|csv", Help: "Output format (default text); -o for short"},
+			},
+			Examples: []string{"pylon slack channels", "pylon slack channels -o csv"},
+		},
+	},
+}
+
+var discordCommand = &command{
+	Name:    "discord",
+	Args:    "<command> [flags]",
+	Summary: "Discord messaging and channel access",
+	Description: `Configuration (~/.pylonrc [discord] section or env vars):
+  webhook      / PYLON_DISCORD_WEBHOOK      Webhook URL for sending messages
+  bot_token    / PYLON_DISCORD_BOT_TOKEN    Bot token for reading messages/channels
+  guild_id     / PYLON_DISCORD_GUILD_ID     Default guild (server) ID; see guilds
+  channel_id   / PYLON_DISCORD_CHANNEL_ID   Default channel ID for reading
+  allow_moderation / PYLON_DISCORD_ALLOW_MODERATION  Enable timeout/kick/ban
+
+Names in the [channels] section (name = id) can be given to --channel
+instead of an ID; see pick-channel.`,
+	Subcommands: []*command{
+		{
+			Name:    "msg",
+			Aliases: []string{"send"},
+			Args:    "[flags] [message | -]",
+			Summary: "Send a message via webhook (or bot token)",
+			Description: `The message is the arguments, or read from standard input when it is "-",
+or from a file with --message-file; line breaks are kept. A message over
+Discord's 2000-character limit is sent as several, broken between
+paragraphs, lines or words, with code blocks closed and reopened across the
+break. Only the first is a reply and only the last has the attachments.
+
+Mentions in the text ping as usual. For automated messages that may quote
+an "@everyone", --no-mentions keeps anyone from being pinged, and
+--mention-users or --mention-roles allow only those kinds.`,
+			Flags: append([]flagDoc{
+				{Name: "channel", Arg: "id", Help: "Send via bot token to this channel instead"},
+				{Name: "thread", Arg: "id", Help: "Send into a thread (webhook if it has one in its channel, else bot token)"},
+				{Name: "reply-to", Arg: "message-id", Help: "Reply to a message (bot token; uses --channel or the default channel)"},
+				{Name: "file", Arg: "path", Help: "Attach a file (repeatable; up to 10, within Discord's size limit)"},
+				{Name: "message-file", Arg: "path", Help: "Read the message from a file (- for standard input)"},
+				{Name: "no-split", Help: "Fail instead of splitting a message over 2000 characters"},
+			}, mentionFlags...),
+			Examples: []string{
+				`pylon discord msg "deploy finished"`,
+				`make test 2>&1 | tail -50 | pylon discord msg -`,
+				`pylon discord msg --message-file report.md`,
+				`pylon discord msg --file report.pdf --file chart.png "here's the report"`,
+				`pylon discord send --channel 1234 --reply-to 5678 "on it"`,
+				`pylon discord msg --thread 4321 "notes are up"`,
+				`pylon discord msg -- "--force is not the answer"`,
+				`tail -20 build.log | pylon discord msg --no-mentions -`,
+			},
+		},
+		{
+			Name:    "dm",
+			Args:    "<user-id> <message>",
+			Summary: "Send a direct message to a user (bot token)",
+			Description: `Opens a direct message channel with the user and posts the message there
+with the bot token, e.g. to page the on-call person about a failed build
+instead of a shared channel. Discord only delivers it if the user shares a
+guild with the bot and accepts direct messages from its members.`,
+			Flags: mentionFlags,
+			Examples: []string{
+				`pylon discord dm 80351110224678912 "build #512 failed on main"`,
+			},
+		},
+		{
+			Name:    "read",
+			Summary: "Read recent messages from a channel",
+			Description: `User, role and channel mentions are shown by name and custom emoji as
+:name:. Role names and nicknames of users not mentioned in the messages
+need guild_id; --raw prints the tokens as Discord stores them.
+
+--follow keeps polling the channel after the last message shown and prints
+new ones until interrupted. It needs no gateway connection or intents,
+only Read Message History.`,
+			Flags: []flagDoc{
+				{Name: "channel", Arg: "id", Help: "Channel to read (default: channel_id)"},
+				{Name: "thread", Arg: "id", Help: "Read a thread instead of a channel"},
+				{Name: "count", Arg: "N", Help: "Number of messages, up to 100 (default 20)"},
+				{Name: "stats", Help: "Print per-author, per-hour and emoji counts instead of messages"},
+				{Name: "raw", Help: "Don't resolve mentions and emoji"},
+				{Name: "reactions", Help: "Show reaction counts under each message"},
+				{Name: "follow", Help: "Keep polling and print new messages as they arrive"},
+				{Name: "interval", Arg: "duration", Help: "With --follow, time between polls (default 10s)"},
+				{Name: "metrics-addr", Arg: "host:port", Help: "With --follow, serve Prometheus metrics on /metrics"},
+				{Name: "output", Arg: "text|csv", Help: "Output format (default text); -o for short"},
+			},
+			Examples: []string{
+				"pylon discord read --channel 1234 --count 50",
+				"pylon discord read --count 100 --stats",
+				"pylon discord read --count 5 --reactions",
+				"pylon discord read --follow --interval 10s",
+			},
+		},
+		{
+			Name:    "export",
+			Summary: "Export a channel's message history",
+			Description: `Pages through the whole history of a channel or thread, or everything
+since --since, and prints it oldest first with each message's author,
+timestamp, content, attachments and the message it replies to. Use the
+global --output-file to write the archive atomically.
+
+Messages are saved to the state directory as they are fetched. If an
+export is interrupted, --resume carries on after the last saved message
+instead of fetching the whole history again.
+
+--stats prints the same report as discord read --stats, but over the whole
+history or everything since --since rather than the last 100 messages.`,
+			Flags: []flagDoc{
+				{Name: "channel", Arg: "id", Help: "Channel to export (default: channel_id)"},
+				{Name: "thread", Arg: "id", Help: "Export a thread instead of a channel"},
+				{Name: "since", Arg: "age|date", Help: "Only messages sent after this: RFC 3339, YYYY-MM-DD or an age like 90d"},
+				{Name: "format", Arg: "json|csv|md", Help: "Output format (default json); -o for short"},
+				{Name: "resume", Help: "Continue an interrupted export of the same channel"},
+				{Name: "stats", Help: "Print per-author, per-hour and emoji counts instead of messages"},
+			},
+			Examples: []string{
+				"pylon discord export --channel 1234 --since 2025-01-01 --format md",
+				"pylon discord export --channel 1234 --since 90d --stats",
+				"pylon --output-file general.json discord export --channel 1234",
+			},
+		},
+		{
+			Name:    "pin",
+			Args:    "--message <id>",
+			Summary: "Pin a message in its channel",
+			Description: `Pins a message with the bot token, e.g. to keep a channel's wiki pages at
+hand. The bot needs the Pin Messages (or Manage Messages) permission, and a
+channel holds at most 50 pins. The message ID can also be given on its own.`,
+			Flags: []flagDoc{
+				{Name: "message", Arg: "id", Help: "Message ID (required)"},
+				{Name: "channel", Arg: "id", Help: "Channel the message is in (default: channel_id)"},
+			},
+			Examples: []string{
+				"pylon discord pin --channel ops --message 1122...",
+				"pylon discord pin 1122...",
+			},
+		},
+		{
+			Name:    "unpin",
+			Args:    "--message <id>",
+			Summary: "Unpin a message",
+			Flags: []flagDoc{
+				{Name: "message", Arg: "id", Help: "Message ID (required)"},
+				{Name: "channel", Arg: "id", Help: "Channel the message is in (default: channel_id)"},
+			},
+			Examples: []string{"pylon discord unpin --channel ops --message 1122..."},
+		},
+		{
+			Name:    "pins",
+			Summary: "List a channel's pinned messages, newest pin first",
+			Flags: []flagDoc{
+				{Name: "channel", Arg: "id", Help: "Channel to list (default: channel_id)"},
+				{Name: "raw", Help: "Don't resolve mentions and emoji"},
+			},
+			Examples: []string{"pylon discord pins --channel ops"},
+		},
+		{
+			Name:    "react",
+			Args:    "<emoji>...",
+			Summary: "Add the bot's reactions to a message",
+			Description: `Adds each emoji as a reaction, in order, e.g. to seed an emoji poll.
+Emoji are Unicode or custom ones as <:name:id> or name:id. --remove takes
+the bot's reactions away instead. Count the votes with discord read
+--reactions, and list the voters with discord reactors.`,
+			Flags: []flagDoc{
+				{Name: "message", Arg: "id", Help: "Message ID (required)"},
+				{Name: "channel", Arg: "id", Help: "Channel the message is in (default: channel_id)"},
+				{Name: "remove", Help: "Remove the bot's reactions instead of adding them"},
+			},
+			Examples: []string{
+				"pylon discord react --channel 1234 --message 1122... 👍",
+				"pylon discord react --message 1122... 1️⃣ 2️⃣ 3️⃣",
+				"pylon discord react --message 1122... --remove 👍",
+			},
+		},
+		{
+			Name:    "reactors",
+			Summary: "List everyone who reacted to a message with an emoji",
+			Description: `Pages through every user who added --emoji to --message, for sign-ups
+and giveaways. --emoji takes a Unicode emoji or a custom one as <:name:id>
+or name:id.`,
+			Flags: []flagDoc{
+				{Name: "message", Arg: "id", Help: "Message ID (required)"},
+				{Name: "emoji", Arg: "emoji", Help: "Reaction to list (required)"},
+				{Name: "channel", Arg: "id", Help: "Channel the message is in (default: channel_id)"},
+				{Name: "format", Arg: "text|json|csv", Help: "Output format (default text); -o for short"},
+			},
+			Examples: []string{
+				"pylon discord reactors --message 1122... --emoji 🎟️ -o json",
+				"pylon discord reactors --channel ops --message 1122... --emoji '<:yes:9876>' -o csv",
+			},
+		},
+		{
+			Name:    "pick-channel",
+			Summary: "Choose a channel from a list and save it to the config",
+			Description: `Lists the text channels the bot can see, in guild_id or else in every
+guild the bot is in, asks for one by number, and saves it as channel_id.
+With --alias, the channel is saved under that name in the [channels]
+section instead, and --channel <name> works anywhere an ID does.`,
+			Flags: []flagDoc{
+				{Name: "guild", Arg: "id", Help: "Only list this guild's channels (default: guild_id)"},
+				{Name: "alias", Arg: "name", Help: "Save as a named alias instead of channel_id"},
+			},
+			Examples: []string{
+				"pylon discord pick-channel",
+				"pylon discord pick-channel --alias ops",
+			},
+		},
+		{
+			Name:    "guilds",
+			Aliases: []string{"servers"},
+			Summary: "List the guilds the bot belongs to",
+			Description: `Lists the ID, name and approximate member count of every guild (server)
+the bot has been added to, so you can find the ID for guild_id or --guild
+without turning on developer mode in Discord. The configured guild_id is
+marked (default). Needs a bot token.`,
+			Flags: []flagDoc{{Name: "json", Help: "Print the guilds as JSON"}},
+			Examples: []string{
+				"pylon discord guilds",
+				"pylon config set discord.guild_id $(pylon discord guilds --json | jq -r '.[0].id')",
+			},
+		},
+		{
+			Name:    "channels",
+			Summary: "List text channels in a guild",
+			Flags: []flagDoc{
+				{Name: "guild", Arg: "id", Help: "Guild to list (default: guild_id)"},
+				{Name: "output", Arg: "text|csv", Help: "Output format (default text); -o for short"},
+			},
+			Examples: []string{"pylon discord channels --guild 9876", "pylon discord channels -o csv"},
+		},
+		{
+			Name:    "webhook",
+			Aliases: []string{"webhooks"},
+			Args:    "<command> [flags]",
+			Summary: "Create, list and delete a channel's webhooks",
+			Description: `Manages webhooks through the bot API, so the webhook pylon sends through
+can be provisioned without Discord's channel settings. The bot needs the
+Manage Webhooks permission in the channel. --channel defaults to
+channel_id and takes a [channels] alias.
+
+create prints the new webhook's URL, which is a secret: anyone with it can
+post to the channel. With --save it goes straight into the config file as
+discord.webhook instead.`,
+			Subcommands: []*command{
+				{
+					Name:    "create",
+					Summary: "Create an incoming webhook and print its URL",
+					Flags: []flagDoc{
+						{Name: "channel", Arg: "id", Help: "Channel to post to (default: channel_id)"},
+						{Name: "name", Arg: "name", Help: "Name the webhook posts as (default pylon)"},
+						{Name: "save", Help: "Save the URL as discord.webhook instead of printing it"},
+					},
+					Examples: []string{
+						"pylon discord webhook create --channel ops --save",
+						`pylon discord webhook create --channel 1234 --name "Event bot"`,
+					},
+				},
+				{
+					Name:     "list",
+					Aliases:  []string{"ls"},
+					Summary:  "List a channel's webhooks",
+					Flags:    []flagDoc{{Name: "channel", Arg: "id", Help: "Channel to list (default: channel_id)"}},
+					Examples: []string{"pylon discord webhook list --channel ops"},
+				},
+				{
+					Name:    "delete",
+					Aliases: []string{"rm"},
+					Args:    "<webhook-id>",
+					Summary: "Delete a webhook, disabling its URL",
+					Description: `Asks for confirmation first, since anything posting to the webhook's URL
+stops working. Pass --yes (or -f) in scripts: without a terminal to ask on
+it fails, and answering no exits 1.`,
+					Flags: []flagDoc{
+						{Name: "channel", Arg: "id", Help: "Accepted for symmetry with create and list; the ID is enough"},
+						{Name: "yes", Help: "Don't ask for confirmation; -f for short"},
+					},
+					Examples: []string{"pylon discord webhook delete 5678", "pylon discord webhook delete 5678 --yes"},
+				},
+			},
+		},
+		{
+			Name:    "events",
+			Args:    "<command> [flags]",
+			Summary: "List and create a guild's scheduled events",
+			Description: `Works with the native scheduled events in the server's Events tab, through
+the bot API; creating them needs the Manage Events permission. --guild
+defaults to guild_id. To keep them in step with a feed, see pylon bridge
+discord-events.`,
+			Subcommands: []*command{
+				{
+					Name:    "list",
+					Aliases: []string{"ls"},
+					Summary: "List upcoming and active scheduled events",
+					Flags: []flagDoc{
+						{Name: "guild", Arg: "id", Help: "Guild to list (default: guild_id)"},
+						{Name: "output", Arg: "text|csv", Help: "Output format (default text); -o for short"},
+					},
+					Examples: []string{"pylon discord events list", "pylon discord events list --guild 9876 -o csv"},
+				},
+				{
+					Name:    "create",
+					Aliases: []string{"add"},
+					Args:    "[flags] <name>",
+					Summary: "Create a scheduled event",
+					Description: `An event is either in a voice channel (--channel) or somewhere else
+(--location), in which case Discord needs an end too. Times are RFC 3339 or
+a local date and time like "2026-03-06 19:00".`,
+					Flags: []flagDoc{
+						{Name: "guild", Arg: "id", Help: "Guild to create it in (default: guild_id)"},
+						{Name: "start", Arg: "time", Help: "When it starts (required)"},
+						{Name: "end", Arg: "time", Help: "When it ends"},
+						{Name: "duration", Arg: "duration", Help: "How long it lasts, instead of --end (e.g. 2h)"},
+						{Name: "location", Arg: "place", Help: "Where it takes place"},
+						{Name: "channel", Arg: "id", Help: "Voice channel it takes place in, instead of --location"},
+						{Name: "description", Arg: "text", Help: "What it is about"},
+					},
+					Examples: []string{
+						`pylon discord events create --start "2026-03-06 19:00" --duration 2h --location "Main St Cafe" Meetup`,
+						`pylon discord events create --start 2026-03-07T18:00:00Z --channel 1234 "Game night"`,
+					},
+				},
+			},
+		},
+		{
+			Name:    "threads",
+			Summary: "List active threads in a guild or channel",
+			Flags: []flagDoc{
+				{Name: "channel", Arg: "id", Help: "Only threads started in this channel"},
+				{Name: "guild", Arg: "id", Help: "Guild to list (default: guild_id)"},
+			},
+			Examples: []string{
+				"pylon discord threads --channel 1234",
+				"pylon discord read --thread 4321",
+			},
+		},
+		{
+			Name:        "timeout",
+			Args:        "<user> <duration> --reason <text>",
+			Summary:     "Time out a member (needs allow_moderation)",
+			Description: moderationHelp + "\n\nA duration of 0 lifts an existing timeout; the maximum is 28d.",
+			Flags: []flagDoc{
+				{Name: "reason", Arg: "text", Help: "Why, recorded in the audit log (required)"},
+				{Name: "guild", Arg: "id", Help: "Guild to act in (default: guild_id)"},
+			},
+			Examples: []string{`pylon discord timeout 1234 1h --reason "spamming invite links"`},
+		},
+		{
+			Name:        "kick",
+			Args:        "<user> --reason <text>",
+			Summary:     "Remove a member from the guild (needs allow_moderation)",
+			Description: moderationHelp,
+			Flags: []flagDoc{
+				{Name: "reason", Arg: "text", Help: "Why, recorded in the audit log (required)"},
+				{Name: "guild", Arg: "id", Help: "Guild to act in (default: guild_id)"},
+			},
+			Examples: []string{`pylon discord kick 1234 --reason "repeated harassment"`},
+		},
+		{
+			Name:        "ban",
+			Args:        "<user> --reason <text>",
+			Summary:     "Ban a user from the guild (needs allow_moderation)",
+			Description: moderationHelp,
+			Flags: []flagDoc{
+				{Name: "reason", Arg: "text", Help: "Why, recorded in the audit log (required)"},
+				{Name: "guild", Arg: "id", Help: "Guild to act in (default: guild_id)"},
+				{Name: "delete-messages", Arg: "duration", Help: "Also delete their messages from this far back (max 7d)"},
+			},
+			Examples: []string{`pylon discord ban 1234 --reason "scam DMs" --delete-messages 1d`},
+		},
+	},
+}
+
+// notifyChangesHelp describes --notify-changes.
+const notifyChangesHelp = `With --notify-changes, or [cal] notify_changes = true, a summary of the
+change is posted to [cal] notify_channel, or the webhook, so the people
+affected hear about it; --notify-changes=false skips it for one run.`
+
+// lockHelp describes the lock taken by commands with lockFlags.
+const lockHelp = `Runs that change something take a lock in the state directory, so a second
+one fails while the first is in progress, or waits for it with --wait. A
+lock left by a run that died is taken over; --force takes one that is stuck.`
+
+const moderationHelp = `Moderation commands are disabled unless [discord] allow_moderation = true
+(or PYLON_DISCORD_ALLOW_MODERATION=true). The bot needs the matching
+permission in the guild. <user> is a user ID or a pasted <@mention>.`
+
+var remindCommand = &command{
+	Name:    "remind",
+	Args:    "--feed <id> [flags]",
+	Summary: "Post Discord reminders before events start",
+	Description: `Runs until interrupted, polling the feeds and posting a message --before
+each event starts, and when an event's deadline passes. Sent reminders are
+remembered in the state directory ($XDG_STATE_HOME/pylon,
+%LocalAppData%\pylon on Windows, or PYLON_STATE_DIR), so restarts never
+notify twice. Messages go to the webhook, or to --channel via the bot token.
+
+With --thread-category, events in that category also get a Discord thread
+named after them in --channel (or channel_id), opened --thread-before the
+start with the event description posted as the agenda, and archived
+--thread-archive-after the event ends. Threads need the bot token.
+
+--template replaces the reminder text; {summary}, {when}, {in}, {location}
+and {url} are replaced with the event's details.
+
+With --follow-up, a prompt is posted when each event ends (into its thread
+if it has one). --follow-up-template customizes it; {summary}, {start},
+{end} and {url} are replaced with the event's details.
+
+Event URLs are tagged with the [announce] utm_source, utm_medium and
+utm_campaign settings, and passed through the [announce] shortener when one
+is configured, before they are posted. If the shortener fails, the long
+URL is posted.
+
+The config file is reloaded when it changes or on SIGHUP: credentials,
+[announce] and [daemon] settings take effect from the next poll, while
+flags keep their values. A config that fails to load is reported and the
+old one kept.
+
+` + lockHelp,
+	Flags: append([]flagDoc{
+		{Name: "feed", Arg: "id", Help: "Feed to watch (repeatable, required)"},
+		{Name: "before", Arg: "duration", Help: "Lead time before the start (default 15m)"},
+		{Name: "to", Arg: "sink", Help: "Where to send reminders (default discord)"},
+		{Name: "channel", Arg: "id", Help: "Post via bot token to this channel instead of the webhook"},
+		{Name: "interval", Arg: "duration", Help: "Polling interval (default 1m)"},
+		{Name: "once", Help: "Poll once and exit (for cron)"},
+		{Name: "thread-category", Arg: "name", Help: "Open a meeting thread for events in this category"},
+		{Name: "thread-before", Arg: "duration", Help: "When to open the thread (default: --before)"},
+		{Name: "thread-archive-after", Arg: "duration", Help: "Archive this long after the event ends (default 1h)"},
+		{Name: "template", Arg: "text", Help: "Custom reminder text"},
+		{Name: "follow-up", Help: "Ask for action items when events end"},
+		{Name: "follow-up-template", Arg: "text", Help: "Custom follow-up prompt (implies --follow-up)"},
+		{Name: "metrics-addr", Arg: "host:port", Help: "Serve Prometheus metrics on /metrics (see pylon daemon --help)"},
+	}, lockFlags...),
+	Examples: []string{
+		"pylon remind --feed 3f2a... --before 30m --to discord",
+		"pylon remind --feed 3f2a... --feed 8b1c... --channel 1234 --interval 5m",
+		"pylon remind --feed 3f2a... --metrics-addr 127.0.0.1:9464",
+		"pylon remind --feed 3f2a... --channel 1234 --thread-category meeting --thread-before 1h",
+		`pylon remind --feed 3f2a... --channel 1234 --follow-up-template "Action items from {summary}?"`,
+		`pylon remind --feed 3f2a... --template "{summary} starts in {in}. RSVP: {url}"`,
+	},
+}
+
+var doctorCommand = &command{
+	Name:    "doctor",
+	Summary: "Check the config and connectivity to cal and Discord",
+	Description: `Validates the config file, lists feeds to check the cal API is reachable
+and accepts the API key, checks the Discord bot token and webhook without
+posting anything, and checks the default channel is readable. It also
+checks that the pylon on PATH is this one and that a completion script
+installed by pylon completion install is readable and current. Each
+problem comes with a hint on how to fix it. The exit status is 1 if any
+check failed.`,
+	Examples: []string{"pylon doctor"},
+}
+
+var auditConfigCommand = &command{
+	Name:    "audit-config",
+	Args:    "[--routes <file>]",
+	Summary: "Find credentials and IDs in the config that no longer work",
+	Description: `Checks every credential and ID in the config against the live services:
+the cal API key, the webhook, the bot token, and that guild_id and
+channel_id still exist (and that the channel is in the guild). With
+--routes, the feeds and destinations of a digest routes file are checked
+too.
+
+Entries the service rejects or no longer knows are marked DEAD, and the
+exit status is 1. Entries that couldn't be checked, because of a network
+error or a missing bot token, are marked ? and don't fail the audit.`,
+	Flags: []flagDoc{
+		{Name: "routes", Arg: "file", Help: "Also check a pylon digest routes file"},
+	},
+	Examples: []string{
+		"pylon audit-config",
+		"pylon audit-config --routes ~/.config/pylon/digest.routes",
+	},
+}
+
+var retentionCommand = &command{
+	Name:    "retention",
+	Args:    "[flags]",
+	Summary: "Archive old events and remove old Discord exports",
+	Description: `Enforces the [retention] policy so long-running deployments don't grow
+without bound. Runs until interrupted, once every --interval:
+
+  [retention] events_older_than = 2y
+      Events that started longer ago are archived to archive_dir, one JSON
+      file per feed as with cal archive, and deleted once the archive is
+      written. Recurring events wait for their last occurrence, and series
+      without an end are kept. Restore them with pylon cal archive restore.
+  [retention] discord_export_keep = 90d
+      JSON, CSV and Markdown files in export_dir last written longer ago
+      are removed. Point discord export --output-file into export_dir.
+
+Either half can be left unset. archive_dir and export_dir must not overlap,
+since rotating exports would remove the archives. --dry-run prints what would be archived and
+removed, once, without changing anything.
+
+The config file is reloaded when it changes or on SIGHUP, and the new
+policy applies from the next run; an incomplete policy or a config that
+fails to load is reported and the old one kept.
+
+` + lockHelp,
+	Flags: append([]flagDoc{
+		{Name: "interval", Arg: "duration", Help: "How often to enforce the policy (default 24h)"},
+		{Name: "once", Help: "Enforce the policy once and exit (for cron)"},
+		{Name: "dry-run", Help: "Show what would be archived and removed"},
+		{Name: "metrics-addr", Arg: "host:port", Help: "Serve Prometheus metrics on /metrics (see pylon daemon --help)"},
+	}, lockFlags...),
+	Examples: []string{
+		"pylon config set retention.events_older_than 2y",
+		"pylon retention --dry-run",
+		"pylon retention --interval 12h",
+	},
+}
+
+var daemonCommand = &command{
+	Name:    "daemon",
+	Args:    "<command>",
+	Summary: "Report on long-running jobs (remind, retention)",
+	Description: `The loops of pylon remind and pylon retention are watched: every run's
+start, duration and outcome is recorded in the state directory (see
+PYLON_STATE_DIR), and a job that fails failure_threshold times in a row is
+reported to an admin, once, and again when it recovers:
+
+  [daemon] admin_channel = ops      Channel ID or [channels] alias
+  [daemon] admin_user = 1234567890  User sent a direct message
+  [daemon] failure_threshold = 3    Consecutive failures (default 3)
+
+Notifications are sent with the bot token. Runs with --once are not
+recorded. Two loops of the same command share one status.
+
+With --metrics-addr, pylon remind, pylon retention and pylon discord read
+--follow serve Prometheus metrics on http://<addr>/metrics:
+
+  pylon_api_requests_total                  Requests by host and status code
+  pylon_api_errors_total                    Requests with no response or a 5xx
+  pylon_rate_limited_total                  Requests answered with a 429
+  pylon_notifications_sent_total            Reminders, follow-ups and agendas
+  pylon_job_runs_total                      Runs by job and result
+  pylon_job_consecutive_failures            Failed runs in a row
+  pylon_job_last_run_timestamp_seconds      When each job last ran
+  pylon_job_last_success_timestamp_seconds  When each job last succeeded
+
+Alert on the last success falling behind to catch a loop that keeps
+running but no longer gets anything done.`,
+	Subcommands: []*command{
+		{
+			Name:     "jobs",
+			Summary:  "Show each job's last run, duration and status",
+			Examples: []string{"pylon daemon jobs"},
+		},
+	},
+}
+
+var bridgeCommand = &command{
+	Name:    "bridge",
+	Args:    "<command> [flags]",
+	Summary: "Sync pylon feeds with calendars kept elsewhere",
+	Subcommands: []*command{
+		{
+			Name:    "import-discord-events",
+			Summary: "Copy a guild's scheduled events into a feed",
+			Description: `Brings a feed in step with the guild's native scheduled events, so people
+who only use calendar apps see community events too. New events are
+created, changed ones updated and canceled ones marked CANCELLED; copies of
+upcoming events that were deleted on Discord are deleted. Copies of past
+events are kept, since Discord drops finished events from its listing.
+
+On its own (or with --plan) it only prints the changes as a diff; --apply
+makes them. Run it with --apply from cron. Needs the bot token and a cal
+server with upsert support.
+
+` + lockHelp,
+			Flags: append([]flagDoc{
+				{Name: "guild", Arg: "id", Help: "Guild to import from (default: discord.guild_id)"},
+				{Name: "feed", Arg: "id", Help: "Feed to import into (default: cal.default_feed)"},
+				{Name: "plan", Help: "Print the changes without making them (the default)"},
+				{Name: "apply", Help: "Make the changes"},
+			}, lockFlags...),
+			Examples: []string{
+				"pylon bridge import-discord-events --guild 1234567890 --feed 3f2a...",
+				"pylon bridge import-discord-events --guild 1234567890 --feed 3f2a... --apply",
+			},
+		},
+		{
+			Name:    "discord-events",
+			Summary: "Mirror a feed's events into a guild's scheduled events",
+			Description: `Brings the guild's scheduled events in step with a feed, so people who
+live in Discord see what's on the calendar. Each occurrence starting in the
+next --days days gets an event: external, at the event's location (or its
+URL), or in a voice channel with --channel. New events are created and
+changed ones updated; events whose occurrence was deleted or cancelled are
+deleted. Only events the bridge made are touched, recognized by a tag at
+the end of their description, and import-discord-events leaves them out.
+Discord doesn't allow changing events once they have started.
+
+On its own (or with --plan) it only prints the changes as a diff; --apply
+makes them. Run it with --apply from cron. Needs the bot token, with the
+Manage Events permission.
+
+` + lockHelp,
+			Flags: append([]flagDoc{
+				{Name: "feed", Arg: "id", Help: "Feed to mirror (default: cal.default_feed)"},
+				{Name: "guild", Arg: "id", Help: "Guild to mirror into (default: discord.guild_id)"},
+				{Name: "channel", Arg: "id", Help: "Voice channel for the events (default: external events)"},
+				{Name: "days", Arg: "n", Help: "How far ahead to mirror (default 30)"},
+				{Name: "plan", Help: "Print the changes without making them (the default)"},
+				{Name: "apply", Help: "Make the changes"},
+			}, lockFlags...),
+			Examples: []string{
+				"pylon bridge discord-events --feed work --guild 1234567890",
+				"pylon bridge discord-events --feed work --guild 1234567890 --days 14 --apply",
+			},
+		},
+	},
+}
+
+var digestCommand = &command{
+	Name:    "digest",
+	Args:    "[--feed <id>...] [--to <dest>] | --routes <file>",
+	Summary: "Post the upcoming agenda to Discord",
+	Description: `Posts the agenda of the next --days days, as 'pylon cal agenda' prints it,
+for the given feeds (default: all) to --to. Destinations are
+webhook:<url>, channel:<id> or default (the configured webhook, or
+channel_id). Meant to run from cron.
+
+With --routes, one run serves many feed sets, one route per line:
+
+  # feeds                      destination
+  3f2a...,9b1c...           -> channel:1234567890
+  7d4e...                   -> webhook:https://discord.com/api/webhooks/...
+
+Each feed is fetched once, routes are processed concurrently, and a
+failing feed or destination only fails its own routes. The exit status is
+1 if any route failed.`,
+	Flags: []flagDoc{
+		{Name: "feed", Arg: "id", Help: "Feed to include (repeatable; default: all feeds)"},
+		{Name: "to", Arg: "dest", Help: "webhook:<url>, channel:<id> or default"},
+		{Name: "routes", Arg: "file", Help: "File of feed-set -> destination routes"},
+		{Name: "days", Arg: "n", Help: "Days ahead to include (default 7)"},
+		{Name: "parallel", Arg: "n", Help: "Feeds and routes to process at once (default 4)"},
+		{Name: "dry-run", Help: "Print the messages instead of posting them"},
+	},
+	Examples: []string{
+		"pylon digest --feed 3f2a... --to channel:1234567890",
+		"pylon digest --routes /etc/pylon/digest.routes --days 1",
+	},
+}
+
+var envCommand = &command{
+	Name:    "env",
+	Summary: "Print the effective config as environment variables",
+	Description: `Prints one assignment per set key, after the config file and PYLON_*
+environment variables have been applied, so the same configuration can be
+reproduced where the config file isn't available (a CI job, a container).
+Secrets are printed as comments with their value masked unless
+--show-secrets is given. The default shell is powershell on Windows and sh
+elsewhere.`,
+	Flags: []flagDoc{
+		{Name: "shell", Arg: "sh|fish|powershell", Help: "Syntax of the assignments"},
+		{Name: "show-secrets", Help: "Include secret values"},
+	},
+	Examples: []string{
+		"eval \"$(pylon env --show-secrets)\"",
+		"pylon env --shell fish | source",
+	},
+}
+
+var mcpCommand = &command{
+	Name:    "mcp",
+	Summary: "Let AI agents use pylon over the Model Context Protocol",
+	Subcommands: []*command{
+		{
+			Name:    "serve",
+			Summary: "Serve MCP on stdin/stdout",
+			Description: `Speaks the Model Context Protocol over stdio so an agent can manage the
+calendar and Discord through pylon's configured clients. Tools:
+
+  list_feeds            List calendar feeds
+  list_events           List a feed's events, optionally within a window
+  create_event          Create an event
+  send_discord_message  Post to the webhook or a channel
+  read_channel          Read recent messages from a channel or thread
+
+Moderation is not exposed. Register the command with the agent, e.g. in its
+MCP settings: {"command": "pylon", "args": ["mcp", "serve"]}.`,
+			Examples: []string{"pylon mcp serve"},
+		},
+	},
+}
+
+var viewCommand = &command{
+	Name:    "view",
+	Args:    "<command> [args]",
+	Summary: "Save command lines under a name and run them later",
+	Description: `A view is a pylon command line saved in the [views] section of the config
+file, so a long combination of filters becomes a command of its own:
+
+  [views]
+  standup = cal agenda --days 1 --feed 3f2a...
+
+Quote the command when saving it, so that the shell passes it as one
+argument and global flags such as --output-file apply to view save rather
+than being saved. cal and discord commands may leave out the service.
+Arguments after the name in view run are appended to the saved command.`,
+	Subcommands: []*command{
+		{
+			Name:    "save",
+			Args:    "<name> <command>",
+			Summary: "Save a command line as a view, replacing any of that name",
+			Examples: []string{
+				"pylon view save standup 'cal agenda --days 1 --feed 3f2a...'",
+				`pylon view save games 'search "board games" --category social'`,
+			},
+		},
+		{
+			Name:     "run",
+			Args:     "<name> [args...]",
+			Summary:  "Run a saved view",
+			Examples: []string{"pylon view run standup", "pylon view run games --from 2026-01-01"},
+		},
+		{
+			Name:     "show",
+			Args:     "<name>",
+			Summary:  "Print a view's command line",
+			Examples: []string{"pylon view show standup"},
+		},
+		{
+			Name:    "list",
+			Aliases: []string{"ls"},
+			Summary: "List saved views",
+		},
+		{
+			Name:     "delete",
+			Aliases:  []string{"rm"},
+			Args:     "<name>",
+			Summary:  "Remove a view from the config file",
+			Flags:    []flagDoc{{Name: "yes", Help: "Don't ask for confirmation; -f for short"}},
+			Examples: []string{"pylon view delete standup", "pylon view delete standup --yes"},
+		},
+	},
+}
+
+var configCommand = &command{
+	Name:        "config",
+	Args:        "<command> [args]",
+	Summary:     "Read and edit the config file (get/set/list/unset)",
+	Description: "Edits preserve comments and unrelated lines. Keys:\n" + configKeysHelp(),
+	Subcommands: []*command{
+		{
+			Name:     "list",
+			Aliases:  []string{"ls"},
+			Summary:  "Show effective values (file + env); secrets masked",
+			Flags:    []flagDoc{{Name: "show-secrets", Help: "Print secrets in full"}},
+			Examples: []string{"pylon config list"},
+		},
+		{
+			Name:     "get",
+			Args:     "<section.key>",
+			Summary:  "Print the effective value of a key",
+			Examples: []string{"pylon config get discord.guild_id"},
+		},
+		{
+			Name:     "set",
+			Args:     "<section.key> <value>",
+			Summary:  "Write a value to the config file",
+			Examples: []string{"pylon config set cal.url https://cal.example.com"},
+		},
+		{
+			Name:     "unset",
+			Args:     "<section.key>",
+			Summary:  "Remove a value from the config file",
+			Examples: []string{"pylon config unset discord.webhook"},
+		},
+		{
+			Name:    "explain",
+			Args:    "[section.key]",
+			Summary: "Show where each effective value came from",
+			Description: `Lists every setting with the source of its effective value: the default,
+a config file line, or a PYLON_* environment variable. With a key, also
+shows the values it overrides, latest first.`,
+			Flags: []flagDoc{{Name: "show-secrets", Help: "Print secrets in full"}},
+			Examples: []string{
+				"pylon config explain",
+				"pylon config explain cal.url",
+			},
+		},
+		{
+			Name:    "path",
+			Summary: "Print the config file location",
+		},
+	},
+}
+
+// configKeysHelp lists the config keys for the config command's help.
+func configKeysHelp() string {
+	var sb strings.Builder
+	for _, k := range config.Keys {
+		fmt.Fprintf(&sb, "  %-20s %s\n", k.Name, k.Help)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// find returns the direct subcommand named name (or aliased to it).
+func (c *command) find(name string) *command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+		for _, a := range sub.Aliases {
+			if a == name {
+				return sub
+			}
+		}
+	}
+	return nil
+}
+
+// lookup walks the command tree along args, ignoring flags and their
+// values, and returns the deepest matching command path (always starting at
+// the root).
+func lookup(args []string) []*command {
+	path := []*command{cli}
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		sub := path[len(path)-1].find(a)
+		if sub == nil {
+			break
+		}
+		path = append(path, sub)
+	}
+	return path
+}
+
+// wantsHelp reports whether args ask for help anywhere on the command line.
+func wantsHelp(args []string) bool {
+	for _, a := range args {
+		if a == "--help" || a == "-h" {
+			return true
+		}
+		if a == "--" {
+			return false
+		}
+	}
+	return false
+}
+
+// writeHelp renders help for the last command in path.
+func writeHelp(w io.Writer, path []*command) {
+	c := path[len(path)-1]
+	names := make([]string, len(path))
+	for i, p := range path {
+		names[i] = p.Name
+	}
+	full := strings.Join(names, " ")
+
+	fmt.Fprintf(w, "%s - %s\n\n", full, c.Summary)
+	fmt.Fprintf(w, "Usage:\n  %s", full)
+	if c.Args != "" {
+		fmt.Fprintf(w, " %s", c.Args)
+	} else if len(c.Subcommands) > 0 {
+		fmt.Fprint(w, " <command>")
+	}
+	fmt.Fprintln(w)
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(w, "\nAliases: %s\n", strings.Join(c.Aliases, ", "))
+	}
+
+	if c.Description != "" {
+		fmt.Fprintf(w, "\n%s\n", c.Description)
+	}
+
+	if len(c.Subcommands) > 0 {
+		fmt.Fprintf(w, "\nCommands:\n")
+		for _, sub := range c.Subcommands {
+			fmt.Fprintf(w, "  %-12s %s\n", sub.Name, sub.Summary)
+		}
+	}
+
+	if len(c.Flags) > 0 {
+		fmt.Fprintf(w, "\nFlags:\n")
+		width := 0
+		labels := make([]string, len(c.Flags))
+		for i, f := range c.Flags {
+			labels[i] = "--" + f.Name
+			if f.Optional {
+				labels[i] += "[=<" + f.Arg + ">]"
+			} else if f.Arg != "" {
+				labels[i] += " <" + f.Arg + ">"
+			}
+			width = max(width, len(labels[i]))
+		}
+		for i, f := range c.Flags {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, labels[i], f.Help)
+		}
+	}
+
+	if len(c.Examples) > 0 {
+		fmt.Fprintf(w, "\nExamples:\n")
+		for _, ex := range c.Examples {
+			fmt.Fprintf(w, "  %s\n", ex)
+		}
+	}
+}
+
+// usageFor prints help for the command at names (e.g. "cal", "feed") to
+// stderr. It is used when a command is invoked incorrectly.
+func usageFor(names ...string) {
+	writeHelp(os.Stderr, lookup(names))
+}
+
+// runHelp implements `pylon help [command...]`.
+func runHelp(args []string) {
+	path := lookup(args)
+	var rest []string
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			rest = append(rest, a)
+		}
+	}
+	if len(rest) >= len(path) {
+		names := make([]string, 0, len(path)-1)
+		for _, c := range path[1:] {
+			names = append(names, c.Name)
+		}
+		unknownCommand(rest[len(path)-1], names...)
+	}
+	writeHelp(os.Stdout, path)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/history"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// historyKeep is how many commands the history keeps.
+const historyKeep = 1000
+
+// historyPath returns the history file in the state directory.
+func historyPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// recordHistory adds the command line raw to the history, with secrets
+// stripped. args is raw without the global flags, to tell which command
+// it is. Looking at the history, help and completion aren't recorded, and
+// a history that can't be written doesn't stop the command.
+func recordHistory(raw, args []string) {
+	if len(args) == 0 || wantsHelp(args) {
+		return
+	}
+	switch args[0] {
+	case "history", "redo", "help", "__complete":
+		return
+	}
+	path, err := historyPath()
+	if err != nil {
+		return
+	}
+	dir, _ := os.Getwd()
+	secret := func(key string) bool {
+		k, ok := config.LookupKey(key)
+		return ok && k.Secret
+	}
+	e := history.Entry{Time: time.Now(), Dir: dir, Args: history.Redact(raw, secret)}
+	_ = history.Append(path, e, historyKeep)
+}
+
+// readHistory loads the history, exiting on errors.
+func readHistory() []history.Entry {
+	path, err := historyPath()
+	if err != nil {
+		fatal("history: %v", err)
+	}
+	entries, err := history.Read(path)
+	if err != nil {
+		fatal("history: %v", err)
+	}
+	return entries
+}
+
+// runHistory implements `pylon history`: the last commands run, numbered
+// for `pylon redo`.
+func runHistory(args []string) {
+	fs := parseFlags(args, "history")
+	count := fs.Int("count", 20)
+	search := strings.ToLower(strings.Join(fs.args, " "))
+
+	type numbered struct {
+		N int `json:"n"`
+		history.Entry
+	}
+	var list []numbered
+	for i, e := range readHistory() {
+		if search == "" || strings.Contains(strings.ToLower(e.String()), search) {
+			list = append(list, numbered{i + 1, e})
+		}
+	}
+	list = list[max(0, len(list)-count):]
+
+	if fs.Bool("json") {
+		printJSON(list)
+		return
+	}
+	if len(list) == 0 {
+		fmt.Println(i18n.T("history.none"))
+		return
+	}
+	for _, e := range list {
+		fmt.Printf("%5d  %s  %s\n", e.N, e.Time.Local().Format("2006-01-02 15:04"), e)
+	}
+}
+
+// runRedo implements `pylon redo`: run a command from the history again,
+// in the directory it was run from.
+func runRedo(args []string) {
+	fs := parseFlags(args, "redo")
+	if len(fs.args) > 1 {
+		fatal("usage: pylon redo [n]")
+	}
+	entries := readHistory()
+	n := -1
+	if len(fs.args) == 1 {
+		v, err := strconv.Atoi(fs.args[0])
+		if err != nil || v == 0 {
+			fatal("redo: invalid history number %q", fs.args[0])
+		}
+		n = v
+	}
+	i := n - 1
+	if n < 0 {
+		i = len(entries) + n
+	}
+	if i < 0 || i >= len(entries) {
+		fatal("redo: no command %d in the history (see pylon history)", n)
+	}
+	e := entries[i]
+	if e.HasSecrets() {
+		fatal("redo: %s had secrets, which aren't recorded; run it again by hand", e)
+	}
+
+	fmt.Fprintln(os.Stderr, e)
+	if fs.Bool("print") {
+		return
+	}
+	self, err := os.Executable()
+	if err != nil {
+		fatal("redo: %v", err)
+	}
+	cmd := exec.Command(self, e.Args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if info, err := os.Stat(e.Dir); err == nil && info.IsDir() {
+		cmd.Dir = e.Dir
+	}
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		fatal("redo: %v", err)
+	}
+}
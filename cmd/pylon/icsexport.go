@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/ics"
+)
+
+// runCalExport writes a feed as an iCalendar file, or with --redact as a
+// free/busy calendar that can be shared outside the team.
+func runCalExport(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "export")
+	fs.noArgs()
+	feedID := fs.String("feed", "")
+	redact, skipCancelled := fs.Bool("redact"), fs.Bool("skip-cancelled")
+	if feedID == "" {
+		fatal("usage: pylon cal export --feed <id> [--redact] [--skip-cancelled]")
+	}
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("export: %v", err)
+	}
+	feeds = filterFeeds(feeds, feedID)
+	if len(feeds) == 0 {
+		fatal("feed not found: %s", feedID)
+	}
+	events, err := client.ListEvents(feeds[0].ID)
+	if err != nil {
+		fatal("export: %v", err)
+	}
+
+	c := ics.FromFeed(feeds[0], events)
+	// A cancelled event doesn't make anyone busy.
+	if skipCancelled || redact {
+		c.DropCancelled()
+	}
+	if redact {
+		c.Name += " (free/busy)"
+		for i, e := range c.Events {
+			c.Events[i] = ics.Redact(e)
+		}
+	}
+	if err := ics.Write(os.Stdout, c); err != nil {
+		fatal("export: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/ics"
+	"github.com/jredh-dev/pylon/internal/platform"
+	"github.com/jredh-dev/pylon/internal/recur"
+)
+
+// runCalImport creates events in a feed from an ICS file, URL or stdin.
+// Alarms are carried over as event deadlines.
+func runCalImport(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "import")
+	feedID := fs.String("feed", "")
+	upsert, keepUID, dryRun := fs.Bool("upsert"), fs.Bool("uid"), fs.Bool("dry-run")
+	if len(fs.args) != 1 || feedID == "" {
+		fatal("usage: pylon cal import <file|url|-> --feed <id> [--upsert] [--uid] [--dry-run]")
+	}
+	source := fs.args[0]
+	feedID = resolveFeed(client, feedID)
+
+	r, err := openICS(source)
+	if err != nil {
+		fatal("import: %v", err)
+	}
+	calendar, err := ics.Parse(r)
+	r.Close()
+	if err != nil {
+		fatal("import %s: %v", source, err)
+	}
+
+	var imported, failed int
+	uidIgnored := false
+	for _, e := range calendar.Events {
+		req, extra := e.CreateRequest(feedID)
+		if extra > 0 {
+			fmt.Fprintf(os.Stderr, "pylon: %s: %d extra alarm(s) at a fixed time not imported; the earliest became the deadline\n", e.Summary, extra)
+		}
+		if e.RRule != "" {
+			if _, err := recur.Parse(e.RRule); err != nil {
+				fmt.Fprintf(os.Stderr, "pylon: %s: %v; pylon will only show its first occurrence\n", e.Summary, err)
+			}
+		}
+		if keepUID {
+			req.UID, req.Sequence = e.UID, e.Sequence
+		}
+		if dryRun {
+			imported++
+			continue
+		}
+
+		var created *cal.Event
+		var err error
+		if upsert && e.UID != "" {
+			created, _, err = client.UpsertEvent(e.UID, req)
+			if errors.Is(err, cal.ErrNotSupported) {
+				fatal("this cal server does not support --upsert")
+			}
+		} else {
+			created, err = client.CreateEvent(req)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: import %q: %v\n", e.Summary, err)
+			failed++
+			continue
+		}
+		if req.UID != "" && created.UID != req.UID && !uidIgnored {
+			fmt.Fprintln(os.Stderr, "pylon: this cal server ignored --uid; events are published under their own IDs")
+			uidIgnored = true
+		}
+		imported++
+	}
+
+	if dryRun {
+		fmt.Println(i18n.T("import.dry_run", imported))
+		return
+	}
+	fmt.Println(i18n.T("import.summary", imported, failed))
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// openICS opens an ICS source: "-" for stdin, an http(s) or webcal URL, or a
+// file path or file:// URL.
+func openICS(source string) (io.ReadCloser, error) {
+	if source == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	if strings.HasPrefix(source, "file://") {
+		path, err := platform.FilePath(source)
+		if err != nil {
+			return nil, err
+		}
+		return os.Open(path)
+	}
+	if strings.HasPrefix(source, "webcal://") {
+		source = "https://" + strings.TrimPrefix(source, "webcal://")
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.Open(source)
+	}
+
+	resp, err := fetchClient().Get(source)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetch %s: %s", source, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/feedcheck"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalLint validates the .ics output of feeds, reporting what makes
+// calendar apps drop events without a word.
+func runCalLint(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "lint")
+	fs.noArgs()
+	feedIDs := fs.Strings("feed")
+	if len(feedIDs) == 0 {
+		fatal("usage: pylon cal lint --feed <id>...")
+	}
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("lint: %v", err)
+	}
+
+	problems := 0
+	for _, id := range feedIDs {
+		found := filterFeeds(feeds, id)
+		if len(found) == 0 {
+			fatal("feed not found: %s", id)
+		}
+		report, err := feedcheck.Fetch(fetchClient(), client.SubscribeURL(found[0].Token))
+		if err != nil {
+			fatal("lint: %v", err)
+		}
+		if len(feedIDs) > 1 {
+			fmt.Printf("%s (%s):\n", found[0].Name, id)
+		}
+		if report.OK() {
+			fmt.Println(i18n.T("verify.ok"))
+			continue
+		}
+		problems += len(report.Problems)
+		fmt.Println(i18n.T("verify.problems", len(report.Problems)))
+		for _, p := range report.Problems {
+			fmt.Printf("  - %s\n", p)
+		}
+	}
+	if problems > 0 {
+		exit(1)
+	}
+}
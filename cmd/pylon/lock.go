@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/lock"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// lockOptions are the --wait and --force flags of commands that take a
+// lock against concurrent runs.
+type lockOptions struct {
+	wait    bool
+	timeout time.Duration // with wait; 0 waits until interrupted
+	force   bool
+}
+
+// lockFlags documents lockOptions for command help.
+var lockFlags = []flagDoc{
+	{Name: "wait", Arg: "duration", Optional: true, Help: "If another run holds the lock, wait for it instead of failing (--wait=10m: at most 10m)"},
+	{Name: "force", Help: "Take the lock even if another run holds it"},
+}
+
+// lockFlagsFrom returns the lockFlags given in fs.
+func lockFlagsFrom(fs *flagSet) lockOptions {
+	o := lockOptions{wait: fs.Has("wait"), force: fs.Bool("force")}
+	if v := fs.String("wait", ""); v != "" {
+		d, err := timeutil.ParseDuration(v)
+		if err != nil || d <= 0 {
+			fatal("invalid --wait %q: want a duration like 30s or 10m", v)
+		}
+		o.timeout = d
+	}
+	return o
+}
+
+// held are the locks this process holds; exit and fail release them.
+var held []*lock.Lock
+
+// acquireLock takes the lock called name in the state directory for
+// command, or exits if another run holds it. With --wait it waits for the
+// other run to finish, and with --force takes the lock regardless. Locks
+// left by runs that have died are taken over without either.
+func acquireLock(name, command string, o lockOptions) {
+	dir, err := config.StateDir()
+	if err != nil {
+		fatal("lock: %v", err)
+	}
+	name = strings.NewReplacer("/", "_", `\`, "_", ":", "_").Replace(name)
+	path := filepath.Join(dir, "locks", name+".lock")
+
+	var l *lock.Lock
+	switch {
+	case o.force:
+		var previous lock.Info
+		var ok bool
+		l, previous, ok, err = lock.Force(path, command)
+		if ok && err == nil {
+			fmt.Fprintf(os.Stderr, "pylon: warning: took the %s lock from %s\n", name, previous)
+		}
+	case o.wait:
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if o.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.timeout)
+			defer cancel()
+		}
+		l, err = lock.Wait(ctx, path, command, time.Second, func(holder lock.Info) {
+			fmt.Fprintf(os.Stderr, "pylon: waiting for %s to release the %s lock...\n", holder, name)
+		})
+	default:
+		l, err = lock.Acquire(path, command)
+	}
+	if errors.Is(err, lock.ErrLocked) && !o.wait {
+		fatal("%v\nAnother run is in progress: retry with --wait to wait for it, or --force if it is stuck", err)
+	}
+	if err != nil {
+		fatal("lock: %v", err)
+	}
+	held = append(held, l)
+}
+
+// releaseLocks releases the locks this process holds.
+func releaseLocks() {
+	for _, l := range held {
+		if err := l.Release(); err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: %v\n", err)
+		}
+	}
+	held = nil
+}
@@ -1,55 +1,362 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 
-	"github.com/jredh-dev/pylon/internal/cal"
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
 	"github.com/jredh-dev/pylon/internal/config"
-	"github.com/jredh-dev/pylon/internal/discord"
+	"github.com/jredh-dev/pylon/internal/httpx"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/ics"
+	"github.com/jredh-dev/pylon/internal/platform"
+	"github.com/jredh-dev/pylon/internal/recur"
+	"github.com/jredh-dev/pylon/internal/timeutil"
 )
 
 var version = "dev"
 
 func main() {
-	if len(os.Args) < 2 {
-		usage()
-		os.Exit(1)
+	args := globalFlags(os.Args[1:])
+	openOutput()
+	recordHistory(os.Args[1:], args)
+	run(args)
+	exit(0)
+}
+
+func run(args []string) {
+	if len(args) < 1 {
+		usageFor()
+		fail()
+	}
+	if wantsHelp(args) && args[0] != "__complete" {
+		writeHelp(os.Stdout, lookup(args))
+		return
 	}
 
-	switch os.Args[1] {
+	switch args[0] {
 	case "version":
-		fmt.Println("pylon", version)
+		runVersion(args[1:])
 	case "cal":
-		if len(os.Args) < 3 {
-			calUsage()
-			os.Exit(1)
+		if len(args) < 2 {
+			usageFor("cal")
+			fail()
 		}
-		runCal(os.Args[2:])
+		runCal(args[1:])
 	case "discord":
-		if len(os.Args) < 3 {
-			discordUsage()
-			os.Exit(1)
+		if len(args) < 2 {
+			usageFor("discord")
+			fail()
+		}
+		runDiscord(args[1:])
+	case "slack":
+		if len(args) < 2 {
+			usageFor("slack")
+			fail()
 		}
-		runDiscord(os.Args[2:])
-	case "help", "--help", "-h":
-		usage()
+		runSlack(args[1:])
+	case "config":
+		if len(args) < 2 {
+			usageFor("config")
+			fail()
+		}
+		runConfig(args[1:])
+	case "remind":
+		runRemind(args[1:])
+	case "digest":
+		runDigest(args[1:])
+	case "retention":
+		runRetention(args[1:])
+	case "daemon":
+		runDaemon(args[1:])
+	case "bridge":
+		runBridge(args[1:])
+	case "mcp":
+		runMCP(args[1:])
+	case "view":
+		runView(args[1:])
+	case "env":
+		runEnv(args[1:])
+	case "doctor":
+		runDoctor(args[1:])
+	case "audit-config":
+		runAuditConfig(args[1:])
+	case "history":
+		runHistory(args[1:])
+	case "redo":
+		runRedo(args[1:])
+	case "completion":
+		runCompletion(args[1:])
+	case "__complete":
+		runComplete(args[1:])
+	case "help":
+		runHelp(args[1:])
 	default:
-		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", os.Args[1])
-		usage()
-		os.Exit(1)
+		unknownCommand(args[0])
 	}
 }
 
-func runCal(args []string) {
+// globalFlags applies flags accepted anywhere on the command line (before a
+// "--" terminator) and returns the remaining arguments. Completion requests
+// are passed through untouched.
+func globalFlags(args []string) []string {
+	if len(args) > 0 && args[0] == "__complete" {
+		return args
+	}
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if v, ok := takeFlag(args, &i, "config"); ok {
+			config.SetPath(v)
+			continue
+		}
+		if v, ok := takeFlag(args, &i, "output-file"); ok {
+			outputPath = v
+			continue
+		}
+		if args[i] == "--output-append" {
+			outputAppend = true
+			continue
+		}
+		if args[i] == "--read-only" {
+			readOnlyFlag = true
+			continue
+		}
+		if v, ok := takeFlag(args, &i, "proxy"); ok {
+			if _, err := httpx.ParseProxy(v); err != nil {
+				fatal("invalid --proxy: %v", err)
+			}
+			proxyFlag = v
+			continue
+		}
+		if v, ok := takeFlag(args, &i, "max-requests"); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				fatal("invalid --max-requests %q: want a positive number", v)
+			}
+			requestThrottle().Max = n
+			continue
+		}
+		if v, ok := takeFlag(args, &i, "requests-per-second"); ok {
+			r, err := strconv.ParseFloat(v, 64)
+			if err != nil || r <= 0 {
+				fatal("invalid --requests-per-second %q: want a positive number", v)
+			}
+			requestThrottle().Interval = time.Duration(float64(time.Second) / r)
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	return rest
+}
+
+// loadConfig loads configuration and applies process-wide settings such as
+// the UI language. Errors are fatal.
+func loadConfig() *config.Config {
+	cfg, err := readConfig(nil)
+	if err != nil {
+		fatal("%v", err)
+	}
+	return cfg
+}
+
+// readConfig loads the configuration and checks it with check, if not nil,
+// before applying its process-wide settings (language, HTTP transport), so
+// a config that fails to reload leaves them as they were.
+func readConfig(check func(*config.Config) error) (*config.Config, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		fatal("config: %v", err)
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if readOnlyFlag {
+		cfg.ReadOnly = true
+	}
+	t, err := newTransport(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config: [http] %w", err)
+	}
+	faults, err := faultInjection()
+	if err != nil {
+		return nil, err
+	}
+	if check != nil {
+		if err := check(cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := i18n.SetLanguage(cfg.Language); err != nil {
+		return nil, fmt.Errorf("config: [ui] language: %w", err)
+	}
+	// A reload drops settings removed from the file.
+	transport = nil
+	if t != nil {
+		transport = t
+	}
+	if faults != nil {
+		faults.Next = transport
+		transport = faults
+	}
+	if throttle != nil {
+		throttle.Next = transport
+	}
+	return cfg, nil
+}
+
+// proxyFlag, when set by --proxy, overrides [http] proxy for this run.
+var proxyFlag string
+
+// readOnlyFlag is set by --read-only, which turns on [http] read_only for
+// this run.
+var readOnlyFlag bool
+
+// transport applies the [http] proxy and TLS settings to every request
+// pylon makes. It is set by loadConfig; nil means Go's default transport,
+// which honours HTTP_PROXY and HTTPS_PROXY.
+var transport http.RoundTripper
+
+// faultInjection reads the undocumented PYLON_FAULT_INJECT, which makes a
+// share of requests fail with a 429, a 500 or a timeout before they are
+// sent (see httpx.ParseFaults), for testing how pylon and scripts built on
+// it handle errors.
+var faultInjection = sync.OnceValues(func() (*httpx.Faults, error) {
+	spec := os.Getenv("PYLON_FAULT_INJECT")
+	if spec == "" {
+		return nil, nil
+	}
+	f, err := httpx.ParseFaults(spec)
+	if err != nil {
+		return nil, fmt.Errorf("PYLON_FAULT_INJECT: %w", err)
+	}
+	return f, nil
+})
+
+// newTransport builds the transport for cfg's [http] settings and
+// --proxy, or returns nil if they leave the default as it is.
+func newTransport(cfg *config.Config) (*http.Transport, error) {
+	opts := httpx.TransportOptions{
+		Proxy:              cfg.HTTPProxy,
+		CAFile:             cfg.HTTPCAFile,
+		InsecureSkipVerify: cfg.HTTPInsecureSkipVerify,
+	}
+	if proxyFlag != "" {
+		opts.Proxy = proxyFlag
+	}
+	if opts.IsZero() {
+		return nil, nil
+	}
+	return httpx.NewTransport(opts)
+}
+
+// throttle, when set by --max-requests or --requests-per-second, is shared
+// by every cal and Discord client in the process so the limits apply to
+// pylon's combined traffic.
+var throttle *httpx.Throttle
+
+func requestThrottle() *httpx.Throttle {
+	if throttle == nil {
+		throttle = &httpx.Throttle{}
+	}
+	return throttle
+}
+
+// apiHTTPClient returns an HTTP client that goes through the shared
+// throttle and the configured transport, counting requests once metrics
+// are served, or nil if none of those apply.
+func apiHTTPClient() *http.Client {
+	var rt http.RoundTripper
+	switch {
+	case throttle != nil:
+		rt = throttle
+	case transport != nil:
+		rt = transport
+	}
+	if metered {
+		rt = meteredTransport{next: rt}
+	}
+	if rt == nil {
+		return nil
+	}
+	return &http.Client{Timeout: 15 * time.Second, Transport: rt}
+}
+
+// fetchClient returns an HTTP client for downloading calendars from
+// arbitrary URLs: the configured transport, without the API throttle.
+func fetchClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second, Transport: transport}
+}
+
+// newCalClient builds a cal client for url with the configured HTTP options,
+// followed by extra.
+func newCalClient(cfg *config.Config, url string, extra ...cal.Option) *cal.Client {
+	var opts []cal.Option
+	if hc := apiHTTPClient(); hc != nil {
+		opts = append(opts, cal.WithHTTPClient(hc))
+	}
+	if cfg.ReadOnly {
+		opts = append(opts, cal.WithReadOnly())
+	}
+	if dir, err := config.StateDir(); err == nil {
+		// Listings are revalidated with their ETag, so agenda, remind and
+		// friends only download feeds that changed since the last run.
+		opts = append(opts, cal.WithCache(cal.NewDirCache(filepath.Join(dir, "http-cache"))))
+	}
+	opts = append(opts,
+		cal.WithRetries(cfg.HTTPRetries),
+		cal.WithAPIKey(cfg.CalAPIKey),
+		cal.WithAuthHeader(cfg.CalAuthHeader),
+	)
+	return cal.NewClient(url, append(opts, extra...)...)
+}
+
+// newDiscordClient builds a Discord client with the configured credentials
+// and HTTP options.
+func newDiscordClient(cfg *config.Config) *discord.Client {
+	return discord.NewClient(cfg.DiscordBotToken, cfg.DiscordWebhook, discordOptions(cfg)...)
+}
+
+// discordOptions returns the HTTP options shared by every Discord client.
+func discordOptions(cfg *config.Config) []discord.Option {
+	opts := []discord.Option{
+		discord.WithRetries(cfg.HTTPRetries),
+		discord.WithRateLimiter(discordLimiter()),
+	}
+	if hc := apiHTTPClient(); hc != nil {
+		opts = append(opts, discord.WithHTTPClient(hc))
+	}
+	if cfg.ReadOnly {
+		opts = append(opts, discord.WithReadOnly())
+	}
+	return opts
+}
+
+// discordLimiter is shared by every Discord client in the process and
+// keeps its state in the state directory, so that a script running pylon
+// discord msg in a loop waits out rate limits instead of hitting them.
+var discordLimiter = sync.OnceValue(func() *discord.RateLimiter {
+	dir, err := config.StateDir()
+	if err != nil {
+		return discord.NewRateLimiter("")
 	}
+	return discord.NewRateLimiter(filepath.Join(dir, "discord-ratelimit.json"))
+})
+
+func runCal(args []string) {
+	cfg := loadConfig()
 
 	// Allow --url flag to override
 	url := cfg.CalURL
@@ -67,67 +374,118 @@ func runCal(args []string) {
 		}
 	}
 
-	client := cal.NewClient(url)
+	client := newCalClient(cfg, url)
 
 	if len(rest) < 1 {
-		calUsage()
-		os.Exit(1)
+		usageFor("cal")
+		fail()
 	}
 
 	switch rest[0] {
 	case "feed":
 		if len(rest) < 2 {
-			calFeedUsage()
-			os.Exit(1)
+			usageFor("cal", "feed")
+			fail()
 		}
 		runCalFeed(client, rest[1:])
 	case "event":
 		if len(rest) < 2 {
-			calEventUsage()
-			os.Exit(1)
+			usageFor("cal", "event")
+			fail()
 		}
-		runCalEvent(client, rest[1:])
+		runCalEvent(cfg, client, rest[1:])
 	case "subscribe":
 		runCalSubscribe(client, rest[1:])
+	case "subscribers":
+		runCalSubscribers(client, rest[1:])
+	case "archive":
+		runCalArchive(client, rest[1:])
+	case "backup":
+		runCalBackup(client, url, rest[1:])
+	case "restore":
+		runCalRestore(client, rest[1:])
+	case "import":
+		runCalImport(client, rest[1:])
+	case "export":
+		runCalExport(client, rest[1:])
+	case "agenda":
+		runCalAgenda(client, rest[1:])
+	case "verify-subscription":
+		runCalVerifySubscription(client, rest[1:])
+	case "lint":
+		runCalLint(client, rest[1:])
+	case "quick":
+		runCalQuick(client, cfg.CalDefaultFeed, rest[1:])
+	case "pin":
+		runCalPin(client, cfg.CalDefaultFeed, rest[1:])
+	case "search":
+		runCalSearch(client, rest[1:])
+	case "categories":
+		runCalCategories(client, rest[1:])
+	case "heatmap":
+		runCalHeatmap(client, rest[1:])
+	case "sync":
+		runCalSync(cfg, client, rest[1:])
+	case "serve":
+		runCalServe(rest[1:])
 	default:
-		fmt.Fprintf(os.Stderr, "unknown cal command: %s\n\n", rest[0])
-		calUsage()
-		os.Exit(1)
+		unknownCommand(rest[0], "cal")
 	}
 }
 
 func runCalFeed(client *cal.Client, args []string) {
 	switch args[0] {
 	case "create":
-		if len(args) < 2 {
-			fatal("usage: pylon cal feed create <name> [slug]")
+		fs := parseFlags(args[1:], "cal", "feed", "create")
+		format, words := createdFormatFlag(fs), fs.args
+		if len(words) < 1 {
+			fatal("usage: pylon cal feed create <name> [slug] [--json|--porcelain]")
 		}
-		// Last arg is the slug if there are 3+ args, otherwise no slug.
+		// Last arg is the slug if there are 2+ args, otherwise no slug.
 		// Name can be multiple words, slug is always the final single token.
 		var name, slug string
-		if len(args) >= 3 {
-			slug = args[len(args)-1]
-			name = strings.Join(args[1:len(args)-1], " ")
+		if len(words) >= 2 {
+			slug = words[len(words)-1]
+			name = strings.Join(words[:len(words)-1], " ")
 		} else {
-			name = strings.Join(args[1:], " ")
+			name = strings.Join(words, " ")
 		}
 		feed, err := client.CreateFeed(name, slug)
 		if err != nil {
 			fatal("create feed: %v", err)
 		}
-		fmt.Printf("Created feed:\n")
-		fmt.Printf("  ID:    %s\n", feed.ID)
-		fmt.Printf("  Name:  %s\n", feed.Name)
-		fmt.Printf("  Token: %s\n", feed.Token)
-		fmt.Printf("  URL:   %s\n", feed.URL)
+		switch format {
+		case createdJSON:
+			printJSON(feed)
+			return
+		case createdText:
+			fmt.Println(i18n.T("feed.created"))
+			fmt.Printf("  ID:    %s\n", feed.ID)
+			fmt.Printf("  Name:  %s\n", feed.Name)
+			fmt.Printf("  Token: %s\n", feed.Token)
+			fmt.Printf("  URL:   %s\n", feed.URL)
+		}
+		feedPorcelain(feed)
 
 	case "list", "ls":
+		fs := parseFlags(args[1:], "cal", "feed", "list")
+		fs.noArgs()
+		format := fs.String("output", "text")
+		checkListFormat("list feeds", format)
 		feeds, err := client.ListFeeds()
 		if err != nil {
 			fatal("list feeds: %v", err)
 		}
+		if format == "csv" {
+			rows := make([][]string, len(feeds))
+			for i, f := range feeds {
+				rows[i] = []string{f.ID, f.Name, f.Token, f.CreatedAt.Format(time.RFC3339)}
+			}
+			writeCSV("list feeds", []string{"id", "name", "token", "created_at"}, rows)
+			return
+		}
 		if len(feeds) == 0 {
-			fmt.Println("No feeds.")
+			fmt.Println(i18n.T("feed.none"))
 			return
 		}
 		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
@@ -138,52 +496,172 @@ func runCalFeed(client *cal.Client, args []string) {
 		}
 		_ = tw.Flush()
 
+	case "rotate-token":
+		fs := parseFlags(args[1:], "cal", "feed", "rotate-token")
+		if len(fs.args) != 1 {
+			fatal("usage: pylon cal feed rotate-token <id> [--slug <slug>]")
+		}
+		feed, err := client.RotateFeedToken(resolveFeed(client, fs.args[0]), fs.String("slug", ""))
+		if errors.Is(err, cal.ErrNotSupported) {
+			fatal("this cal server does not support token rotation")
+		}
+		if err != nil {
+			fatal("rotate token: %v", err)
+		}
+		url := feed.URL
+		if url == "" {
+			url = client.SubscribeURL(feed.Token)
+		}
+		fmt.Println(i18n.T("feed.rotated"))
+		fmt.Printf("  Token:          %s\n", feed.Token)
+		fmt.Printf("  Subscribe URL:  %s\n", url)
+		fmt.Printf("  Webcal URL:     %s\n", cal.WebcalURL(url))
+
+	case "update", "rename":
+		fs := parseFlags(args[1:], "cal", "feed", "update")
+		req := cal.UpdateFeedRequest{Name: fs.String("name", ""), Slug: fs.String("slug", "")}
+		given := func(name string) *string {
+			if !fs.Has(name) {
+				return nil
+			}
+			v := fs.String(name, "")
+			return &v
+		}
+		req.ProdID = given("prodid")
+		req.CalendarName = given("calendar-name")
+		req.RefreshInterval = given("refresh-interval")
+		if v := req.RefreshInterval; v != nil && *v != "" {
+			// Accept 1h as well as RFC 5545's PT1H.
+			if !strings.HasPrefix(strings.ToUpper(*v), "P") {
+				*v = ics.FormatDuration(parsePositiveDuration("refresh-interval", *v))
+			} else if d, err := ics.ParseDuration(strings.ToUpper(*v)); err != nil || d <= 0 {
+				fatal("invalid --refresh-interval %q: want a duration like 1h or PT1H", *v)
+			}
+			*v = strings.ToUpper(*v)
+		}
+		if fs.Has("alarms") {
+			omit := !fs.Bool("alarms")
+			req.OmitAlarms = &omit
+		}
+		if len(fs.args) != 1 || req == (cal.UpdateFeedRequest{}) {
+			fatal("usage: pylon cal feed update <id> [--name <name>] [--slug <slug>] [--prodid <id>] [--calendar-name <name>] [--refresh-interval <duration>] [--alarms=false]")
+		}
+		id := resolveFeed(client, fs.args[0])
+		feed, err := client.UpdateFeed(id, &req)
+		if errors.Is(err, cal.ErrNotSupported) {
+			fatal("this cal server does not support updating feeds")
+		}
+		if err != nil {
+			fatal("update feed: %v", err)
+		}
+		fmt.Println(i18n.T("feed.updated"))
+		fmt.Printf("  ID:    %s\n", feed.ID)
+		fmt.Printf("  Name:  %s\n", feed.Name)
+		fmt.Printf("  Token: %s\n", feed.Token)
+		if req.Slug != "" {
+			fmt.Printf("  URL:   %s\n", client.SubscribeURL(feed.Token))
+		}
+		if feed.ProdID != "" {
+			fmt.Printf("  PRODID:           %s\n", feed.ProdID)
+		}
+		if feed.CalendarName != "" {
+			fmt.Printf("  Calendar name:    %s\n", feed.CalendarName)
+		}
+		if feed.RefreshInterval != "" {
+			fmt.Printf("  Refresh interval: %s\n", feed.RefreshInterval)
+		}
+		if feed.OmitAlarms {
+			fmt.Printf("  Alarms:           left out\n")
+		}
+
 	case "delete", "rm":
-		if len(args) < 2 {
-			fatal("usage: pylon cal feed delete <id>")
+		id, yes := deleteArgs(args[1:], "feed")
+		if !yes {
+			feeds, err := client.ListFeeds()
+			if err != nil {
+				fatal("list feeds: %v", err)
+			}
+			f := filterFeedsStrict(feeds, id)
+			if len(f) == 0 {
+				fatal("feed not found: %s", id)
+			}
+			id = f[0].ID
+			events, err := client.ListEvents(id)
+			if err != nil {
+				fatal("list events: %v", err)
+			}
+			confirmDelete(false, i18n.T("feed.confirm", f[0].Name, len(events)))
+		} else {
+			id = resolveFeedStrict(client, id)
 		}
-		if err := client.DeleteFeed(args[1]); err != nil {
+		if err := client.DeleteFeed(id); err != nil {
 			fatal("delete feed: %v", err)
 		}
-		fmt.Println("Feed deleted.")
+		fmt.Println(i18n.T("feed.deleted"))
 
 	default:
-		fmt.Fprintf(os.Stderr, "unknown feed command: %s\n\n", args[0])
-		calFeedUsage()
-		os.Exit(1)
+		unknownCommand(args[0], "cal", "feed")
 	}
 }
 
-func runCalEvent(client *cal.Client, args []string) {
+func runCalEvent(cfg *config.Config, client *cal.Client, args []string) {
 	switch args[0] {
 	case "add", "create":
-		req := parseEventFlags(args[1:])
-		event, err := client.CreateEvent(req)
+		fs := parseFlags(args[1:], "cal", "event", "add")
+		format := createdFormatFlag(fs)
+		req, externalID := parseEventFlags(fs)
+		req.FeedID = resolveFeed(client, req.FeedID)
+		var event *cal.Event
+		var err error
+		created := true
+		if externalID != "" {
+			event, created, err = client.UpsertEvent(externalID, req)
+			if errors.Is(err, cal.ErrNotSupported) {
+				fatal("this cal server does not support --external-id")
+			}
+		} else {
+			event, err = client.CreateEvent(req)
+		}
 		if err != nil {
 			fatal("create event: %v", err)
 		}
-		fmt.Printf("Created event:\n")
-		fmt.Printf("  ID:      %s\n", event.ID)
-		fmt.Printf("  Summary: %s\n", event.Summary)
-		fmt.Printf("  Start:   %s\n", event.Start.Format(time.RFC3339))
-		if event.End != nil {
-			fmt.Printf("  End:     %s\n", event.End.Format(time.RFC3339))
-		}
-		if event.Location != "" {
-			fmt.Printf("  Location: %s\n", event.Location)
+		if len(req.Alarms) > 0 && len(event.Alarms) == 0 {
+			fmt.Fprintln(os.Stderr, "pylon: this cal server ignored --alarm; the event has no alarms")
 		}
+		printCreatedEvent(format, event, created)
 
 	case "list", "ls":
-		feedID := parseFeedIDFlag(args[1:])
+		fs := parseFlags(args[1:], "cal", "event", "list")
+		fs.noArgs()
+		feedID := fs.String("feed", "")
+		category := fs.String("category", "")
+		format := fs.String("output", "text")
 		if feedID == "" {
-			fatal("usage: pylon cal event list --feed <feed-id>")
+			fatal("usage: pylon cal event list --feed <feed-id> [--category <name>] [--output text|csv]")
 		}
+		feedID = resolveFeed(client, feedID)
+		checkListFormat("list events", format)
 		events, err := client.ListEvents(feedID)
 		if err != nil {
 			fatal("list events: %v", err)
 		}
+		if category != "" {
+			events = slices.DeleteFunc(events, func(e cal.Event) bool { return !e.HasCategory(category) })
+		}
+		if format == "csv" {
+			rows := make([][]string, len(events))
+			for i, e := range events {
+				end := ""
+				if e.End != nil {
+					end = e.End.Format(time.RFC3339)
+				}
+				rows[i] = []string{e.ID, e.Summary, e.Start.Format(time.RFC3339), end, e.Status, e.Location}
+			}
+			writeCSV("list events", []string{"id", "summary", "start", "end", "status", "location"}, rows)
+			return
+		}
 		if len(events) == 0 {
-			fmt.Println("No events.")
+			fmt.Println(i18n.T("event.none"))
 			return
 		}
 		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
@@ -198,117 +676,348 @@ func runCalEvent(client *cal.Client, args []string) {
 		}
 		_ = tw.Flush()
 
+	case "show", "get":
+		runCalEventShow(client, args[1:])
+
+	case "mirror":
+		runCalEventMirror(client, args[1:])
+
+	case "prune":
+		runCalEventPrune(client, args[1:])
+
+	case "cancel":
+		runCalEventCancel(cfg, client, args[1:])
+
+	case "confirm":
+		runCalEventConfirm(cfg, client, args[1:])
+
+	case "attach":
+		runCalEventAttach(cfg, client, args[1:])
+
+	case "patch":
+		runCalEventPatch(cfg, client, args[1:])
+
+	case "move", "mv":
+		runCalEventMove(client, args[1:], true)
+
+	case "copy", "cp":
+		runCalEventMove(client, args[1:], false)
+
 	case "delete", "rm":
-		if len(args) < 2 {
-			fatal("usage: pylon cal event delete <id>")
+		id, yes := deleteArgs(args[1:], "event")
+		if !yes {
+			e, err := getEvent(client, id)
+			if err != nil {
+				fatal("delete event: %v", err)
+			}
+			confirmDelete(false, i18n.T("event.confirm", e.Summary, e.Start.Local().Format("2006-01-02 15:04")))
+			id = e.ID
+		} else {
+			id = resolveEvent(client, id)
 		}
-		if err := client.DeleteEvent(args[1]); err != nil {
+		if err := client.DeleteEvent(id); err != nil {
 			fatal("delete event: %v", err)
 		}
-		fmt.Println("Event deleted.")
+		fmt.Println(i18n.T("event.deleted"))
 
 	default:
-		fmt.Fprintf(os.Stderr, "unknown event command: %s\n\n", args[0])
-		calEventUsage()
-		os.Exit(1)
+		unknownCommand(args[0], "cal", "event")
 	}
 }
 
+// deleteArgs parses "<id> [--yes|-f]" for cal <resource> delete.
+func deleteArgs(args []string, resource string) (id string, yes bool) {
+	fs := parseFlags(args, "cal", resource, "delete")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal %s delete <id> [--yes]", resource)
+	}
+	return fs.args[0], fs.Bool("yes")
+}
+
 func runCalSubscribe(client *cal.Client, args []string) {
-	if len(args) < 1 {
-		fatal("usage: pylon cal subscribe <token>")
+	fs := parseFlags(args, "cal", "subscribe")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal subscribe <token>\n       pylon cal subscribe <feed-id> --expires <ttl>")
+	}
+	target, expires := fs.args[0], fs.String("expires", "")
+	openURL, copyURL := fs.Bool("open"), fs.Bool("copy")
+
+	url := client.SubscribeURL(target)
+	var expiresAt time.Time
+	if expires != "" {
+		ttl, err := timeutil.ParseDuration(expires)
+		if err != nil || ttl <= 0 {
+			fatal("invalid --expires %q: want a duration like 30d or 12h", expires)
+		}
+		signed, err := client.SignedSubscribeURL(target, ttl)
+		if errors.Is(err, cal.ErrNotSupported) {
+			fatal("this cal server does not support signed subscribe URLs")
+		}
+		if err != nil {
+			fatal("signed url: %v", err)
+		}
+		url = signed.URL
+		expiresAt = signed.ExpiresAt
 	}
-	token := args[0]
-	url := client.SubscribeURL(token)
-	webcal := strings.Replace(url, "http://", "webcal://", 1)
-	webcal = strings.Replace(webcal, "https://", "webcal://", 1)
+	webcal := cal.WebcalURL(url)
 
 	fmt.Printf("Subscribe URL:  %s\n", url)
 	fmt.Printf("Webcal URL:     %s\n", webcal)
+	if !expiresAt.IsZero() {
+		fmt.Printf("Expires:        %s\n", expiresAt.Local().Format(time.RFC1123))
+	}
 	fmt.Println()
-	fmt.Println("To subscribe in your calendar app, use the webcal URL.")
-	fmt.Println("For Google Calendar, use the https URL in 'Other calendars > From URL'.")
+	fmt.Println(i18n.T("subscribe.hint"))
+	fmt.Println(i18n.T("subscribe.google"))
+
+	// The URLs are already printed, so a missing opener or clipboard tool
+	// is only worth a warning.
+	if copyURL {
+		if err := platform.Copy(url); err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: %v\n", err)
+		} else {
+			fmt.Println(i18n.T("subscribe.copied"))
+		}
+	}
+	if openURL {
+		if err := platform.OpenURL(webcal); err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: %v\n", err)
+		}
+	}
 }
 
 // --- Discord commands ---
 
 func runDiscord(args []string) {
-	cfg, err := config.Load()
-	if err != nil {
-		fatal("config: %v", err)
-	}
-	client := discord.NewClient(cfg.DiscordBotToken, cfg.DiscordWebhook)
+	cfg := loadConfig()
+	client := newDiscordClient(cfg)
 
 	switch args[0] {
 	case "msg", "send":
-		if len(args) < 2 {
-			fatal("usage: pylon discord msg <message>")
+		fs := parseFlags(args[1:], "discord", "msg")
+		client := withMentionFlags(cfg, client, fs)
+		channelID := cfg.Channel(fs.String("channel", ""))
+		threadID := fs.String("thread", "")
+		replyTo := fs.String("reply-to", "")
+		var files []discord.Attachment
+		for _, path := range fs.Strings("file") {
+			f, err := discord.LoadAttachment(path)
+			if err != nil {
+				fatal("attach: %v", err)
+			}
+			files = append(files, f)
+		}
+		if len(files) > 10 {
+			fatal("discord allows at most 10 attachments per message, got %d", len(files))
 		}
-		message := strings.Join(args[1:], " ")
-		if err := client.SendMessage(message); err != nil {
-			fatal("discord msg: %v", err)
+		message := messageText(fs)
+		if message == "" && len(files) == 0 {
+			fatal("usage: pylon discord msg [--channel <id> | --thread <id>] [--reply-to <message-id>] [--file <path>]... <message | - | --message-file <path>>")
+		}
+		if n := utf8.RuneCountInString(message); n > discord.MaxMessageLength && fs.Bool("no-split") {
+			fatal("message is %d characters; Discord allows %d (drop --no-split to send it as several messages)", n, discord.MaxMessageLength)
 		}
-		fmt.Println("Message sent.")
 
-	case "read":
-		channelID := cfg.DiscordChannelID
-		count := 20
-		for i := 1; i < len(args); i++ {
-			switch args[i] {
-			case "--channel":
-				if i+1 < len(args) {
-					i++
-					channelID = args[i]
+		// send posts one message; only the first of a split message is the
+		// reply and only the last carries the attachments.
+		var send func(text, replyTo string, files []discord.Attachment) (id string, err error)
+
+		// A thread is a channel to the bot API. The webhook can only post to
+		// threads under its own channel, so prefer it only when the bot
+		// isn't needed anyway.
+		if threadID != "" {
+			if channelID != "" {
+				fatal("use either --channel or --thread, not both")
+			}
+			if replyTo == "" && cfg.DiscordWebhook != "" {
+				send = func(text, _ string, files []discord.Attachment) (string, error) {
+					return "", client.SendThreadMessage(threadID, text, files)
+				}
+			} else {
+				channelID = threadID
+			}
+		}
+
+		// Replies need a channel, so fall back to the default one.
+		if channelID == "" && replyTo != "" {
+			channelID = cfg.DiscordChannelID
+			if channelID == "" {
+				fatal("--reply-to needs --channel (or a default channel_id)")
+			}
+		}
+
+		switch {
+		case send != nil:
+		case channelID == "":
+			send = func(text, _ string, files []discord.Attachment) (string, error) {
+				return "", client.SendMessageFiles(text, files)
+			}
+		default:
+			send = func(text, replyTo string, files []discord.Attachment) (string, error) {
+				msg, err := client.SendChannelMessageFiles(channelID, text, replyTo, files)
+				if err != nil {
+					return "", err
 				}
-			case "--count":
-				if i+1 < len(args) {
-					i++
-					n, err := strconv.Atoi(args[i])
-					if err == nil && n > 0 {
-						count = n
-					}
+				return msg.ID, nil
+			}
+		}
+
+		chunks := discord.SplitMessage(message, discord.MaxMessageLength)
+		var firstID string
+		for i, text := range chunks {
+			var attach []discord.Attachment
+			if i == len(chunks)-1 {
+				attach = files
+			}
+			id, err := send(text, replyTo, attach)
+			if err != nil {
+				if i > 0 {
+					fatal("discord msg: part %d of %d: %v", i+1, len(chunks), err)
 				}
-			default:
-				if strings.HasPrefix(args[i], "--channel=") {
-					channelID = strings.TrimPrefix(args[i], "--channel=")
-				} else if strings.HasPrefix(args[i], "--count=") {
-					n, err := strconv.Atoi(strings.TrimPrefix(args[i], "--count="))
-					if err == nil && n > 0 {
-						count = n
-					}
+				fatal("discord msg: %v", err)
+			}
+			if i == 0 {
+				firstID = id
+			}
+			replyTo = ""
+		}
+		if firstID == "" {
+			fmt.Println(i18n.T("message.sent"))
+		} else {
+			fmt.Println(i18n.T("message.sent_id", firstID))
+		}
+
+	case "dm":
+		fs := parseFlags(args[1:], "discord", "dm")
+		client := withMentionFlags(cfg, client, fs)
+		if len(fs.args) < 2 {
+			fatal("usage: pylon discord dm <user-id> <message>")
+		}
+		// Long messages go out in parts, as with discord msg.
+		chunks := discord.SplitMessage(strings.Join(fs.args[1:], " "), discord.MaxMessageLength)
+		var firstID string
+		for i, text := range chunks {
+			msg, err := client.SendDirectMessage(fs.args[0], text)
+			if err != nil {
+				if i > 0 {
+					fatal("discord dm: part %d of %d: %v", i+1, len(chunks), err)
 				}
+				fatal("discord dm: %v", err)
+			}
+			if i == 0 {
+				firstID = msg.ID
 			}
 		}
+		fmt.Println(i18n.T("message.sent_id", firstID))
+
+	case "read":
+		fs := parseFlags(args[1:], "discord", "read")
+		fs.noArgs()
+		channelID := fs.String("channel", cfg.DiscordChannelID)
+		if fs.Has("thread") {
+			channelID = fs.String("thread", "")
+		}
+		count := fs.Int("count", 20)
+		if count > 100 {
+			fatal("--count can be at most 100; use pylon discord export for more")
+		}
+		stats, follow := fs.Bool("stats"), fs.Bool("follow")
+		opts := readOptions{raw: fs.Bool("raw"), reactions: fs.Bool("reactions")}
+		interval := fs.Duration("interval", 10*time.Second)
+		format := fs.String("output", "text")
+		channelID = cfg.Channel(channelID)
 		if channelID == "" {
-			fatal("channel ID required\nUsage: pylon discord read [--channel <id>] [--count N]\nOr set channel_id in ~/.pylonrc [discord] or PYLON_DISCORD_CHANNEL_ID")
+			fatal("channel ID required\nUsage: pylon discord read [--channel <id> | --thread <id>] [--count N] [--stats | --follow [--interval 10s] | --output csv]\nOr set channel_id in ~/.pylonrc [discord] or PYLON_DISCORD_CHANNEL_ID")
+		}
+		if stats && follow {
+			fatal("use either --stats or --follow, not both")
+		}
+		checkListFormat("discord read", format)
+		if format == "csv" && (stats || follow) {
+			fatal("--output csv can't be combined with --stats or --follow")
+		}
+		if fs.Has("metrics-addr") {
+			if !follow {
+				fatal("--metrics-addr needs --follow")
+			}
+			serveMetrics(fs.String("metrics-addr", ""))
+			client = newDiscordClient(cfg)
 		}
 		msgs, err := client.ReadMessages(channelID, count)
 		if err != nil {
 			fatal("discord read: %v", err)
 		}
-		if len(msgs) == 0 {
-			fmt.Println("No messages found.")
+		if format == "csv" {
+			if err := discord.WriteExport(os.Stdout, channelID, msgs, "csv"); err != nil {
+				fatal("discord read: %v", err)
+			}
 			return
 		}
-		fmt.Print(discord.FormatMessages(msgs))
+		if stats {
+			if len(msgs) == 0 {
+				fmt.Println(i18n.T("message.none"))
+				return
+			}
+			fmt.Print(discord.FormatStats(discord.ComputeStats(msgs, time.Local), 10))
+			return
+		}
+		if len(msgs) == 0 && !follow {
+			fmt.Println(i18n.T("message.none"))
+			return
+		}
+		printMessages(cfg, client, channelID, msgs, opts)
+		if follow {
+			followChannel(cfg, client, channelID, msgs, interval, opts)
+		}
 
-	case "channels":
-		guildID := cfg.DiscordGuildID
-		for i := 1; i < len(args); i++ {
-			if args[i] == "--guild" && i+1 < len(args) {
-				i++
-				guildID = args[i]
-			} else if strings.HasPrefix(args[i], "--guild=") {
-				guildID = strings.TrimPrefix(args[i], "--guild=")
+	case "guilds", "servers":
+		fs := parseFlags(args[1:], "discord", "guilds")
+		fs.noArgs()
+		asJSON := fs.Bool("json")
+		guilds, err := client.Guilds()
+		if err != nil {
+			fatal("discord guilds: %v", err)
+		}
+		if asJSON {
+			printJSON(guilds)
+			return
+		}
+		if len(guilds) == 0 {
+			fmt.Println(i18n.T("guild.none"))
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "ID\tNAME\tMEMBERS\n")
+		for _, g := range guilds {
+			name := g.Name
+			if g.ID == cfg.DiscordGuildID {
+				name += " (default)"
 			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\n", g.ID, name, g.Members)
 		}
+		_ = tw.Flush()
+
+	case "channels":
+		fs := parseFlags(args[1:], "discord", "channels")
+		fs.noArgs()
+		guildID := fs.String("guild", cfg.DiscordGuildID)
+		format := fs.String("output", "text")
 		if guildID == "" {
 			fatal("guild ID required\nUsage: pylon discord channels --guild <id>\nOr set guild_id in ~/.pylonrc [discord] or PYLON_DISCORD_GUILD_ID")
 		}
+		checkListFormat("discord channels", format)
 		channels, err := client.ListChannels(guildID)
 		if err != nil {
 			fatal("discord channels: %v", err)
 		}
+		if format == "csv" {
+			rows := make([][]string, len(channels))
+			for i, ch := range channels {
+				rows[i] = []string{ch.ID, ch.Name}
+			}
+			writeCSV("discord channels", []string{"id", "name"}, rows)
+			return
+		}
 		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
 		_, _ = fmt.Fprintf(tw, "ID\tNAME\n")
 		for _, ch := range channels {
@@ -316,61 +1025,126 @@ func runDiscord(args []string) {
 		}
 		_ = tw.Flush()
 
+	case "threads":
+		fs := parseFlags(args[1:], "discord", "threads")
+		fs.noArgs()
+		guildID := fs.String("guild", cfg.DiscordGuildID)
+		channelID := ""
+		if v := fs.String("channel", ""); v != "" {
+			channelID = cfg.Channel(v)
+		}
+		if guildID == "" {
+			fatal("guild ID required\nUsage: pylon discord threads [--channel <id>] [--guild <id>]\nOr set guild_id in ~/.pylonrc [discord] or PYLON_DISCORD_GUILD_ID")
+		}
+		threads, err := client.ListActiveThreads(guildID, channelID)
+		if err != nil {
+			fatal("discord threads: %v", err)
+		}
+		if len(threads) == 0 {
+			fmt.Println(i18n.T("thread.none"))
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "ID\tNAME\tCHANNEL\tMESSAGES\n")
+		for _, th := range threads {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", th.ID, th.Name, th.ParentID, th.MessageCount)
+		}
+		_ = tw.Flush()
+
+	case "export":
+		runDiscordExport(cfg, client, args[1:])
+
+	case "pick-channel":
+		runDiscordPickChannel(cfg, client, args[1:])
+
+	case "webhook", "webhooks":
+		runDiscordWebhook(cfg, client, args[1:])
+
+	case "events":
+		runDiscordEvents(cfg, client, args[1:])
+
+	case "pin", "unpin":
+		runDiscordPin(cfg, client, args[0], args[1:])
+	case "pins":
+		runDiscordPins(cfg, client, args[1:])
+
+	case "react":
+		runDiscordReact(cfg, client, args[1:])
+	case "reactors":
+		runDiscordReactors(cfg, client, args[1:])
+
+	case "timeout", "kick", "ban":
+		runDiscordModerate(cfg, client, args[0], args[1:])
+
 	default:
-		fmt.Fprintf(os.Stderr, "unknown discord command: %s\n\n", args[0])
-		discordUsage()
-		os.Exit(1)
+		unknownCommand(args[0], "discord")
 	}
 }
 
 // --- flag parsing helpers ---
 
-func parseEventFlags(args []string) *cal.CreateEventRequest {
-	req := &cal.CreateEventRequest{}
+// messageText returns the message for `discord msg`: the arguments, or
+// the contents of --message-file, or standard input when the message is
+// "-". Text read from a file keeps its line breaks but not its final one.
+func messageText(fs *flagSet) string {
+	source := fs.String("message-file", "")
+	if len(fs.args) > 0 && source != "" {
+		fatal("give the message either as arguments or with --message-file, not both")
+	}
+	if len(fs.args) == 1 && fs.args[0] == "-" {
+		source = "-"
+	} else if len(fs.args) > 0 {
+		return strings.Join(fs.args, " ")
+	}
+	if source == "" {
+		return ""
+	}
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		fatal("discord msg: %v", err)
+	}
+	text := strings.TrimRight(string(data), "\r\n")
+	if text == "" {
+		fatal("discord msg: the message is empty")
+	}
+	return text
+}
 
-	for i := 0; i < len(args); i++ {
-		switch args[i] {
-		case "--feed":
-			i++
-			req.FeedID = args[i]
-		case "--summary":
-			i++
-			req.Summary = args[i]
-		case "--start":
-			i++
-			req.Start = args[i]
-		case "--end":
-			i++
-			req.End = args[i]
-		case "--description":
-			i++
-			req.Description = args[i]
-		case "--location":
-			i++
-			req.Location = args[i]
-		case "--url":
-			i++
-			req.URL = args[i]
-		case "--all-day":
-			req.AllDay = true
-		case "--deadline":
-			i++
-			req.Deadline = args[i]
-		case "--status":
-			i++
-			req.Status = args[i]
-		case "--categories":
-			i++
-			req.Categories = args[i]
-		default:
-			if strings.HasPrefix(args[i], "--") {
-				fatal("unknown flag: %s", args[i])
-			}
-			// Positional: treat as summary if not set
-			if req.Summary == "" {
-				req.Summary = args[i]
-			}
-		}
+// parseEventFlags parses event add flags. The external ID, if any, is
+// returned separately since it selects upsert rather than create.
+func parseEventFlags(fs *flagSet) (req *cal.CreateEventRequest, externalID string) {
+	req = &cal.CreateEventRequest{
+		FeedID:      fs.String("feed", ""),
+		Summary:     fs.String("summary", ""),
+		Start:       fs.String("start", ""),
+		End:         fs.String("end", ""),
+		Description: fs.String("description", ""),
+		Location:    fs.String("location", ""),
+		URL:         fs.String("url", ""),
+		AllDay:      fs.Bool("all-day"),
+		Deadline:    fs.String("deadline", ""),
+		Status:      fs.String("status", ""),
+		Categories:  fs.String("categories", ""),
+		RRule:       fs.String("rrule", ""),
+		ExDates:     fs.Strings("exdate"),
+	}
+	externalID = fs.String("external-id", "")
+	duration := fs.Duration("duration", 0)
+	req.Alarms = alarmFlags(fs)
+
+	// The summary can be given positionally instead.
+	args := fs.args
+	if req.Summary == "" && len(args) > 0 {
+		req.Summary, args = args[0], args[1:]
+	}
+	if len(args) > 0 {
+		fatal("unexpected argument %q (quote a summary with spaces)\nRun 'pylon cal event add --help' for usage.", args[0])
 	}
 
 	if req.FeedID == "" {
@@ -382,114 +1156,56 @@ func parseEventFlags(args []string) *cal.CreateEventRequest {
 	if req.Start == "" {
 		fatal("--start is required")
 	}
-
-	return req
-}
-
-func parseFeedIDFlag(args []string) string {
-	for i := 0; i < len(args); i++ {
-		if args[i] == "--feed" && i+1 < len(args) {
-			return args[i+1]
+	if duration > 0 {
+		if req.End != "" {
+			fatal("--end and --duration are mutually exclusive")
 		}
-		if strings.HasPrefix(args[i], "--feed=") {
-			return strings.TrimPrefix(args[i], "--feed=")
+		if err := req.SetDuration(duration); err != nil {
+			fatal("--duration: %v", err)
+		}
+	}
+	if req.RRule != "" {
+		if _, err := recur.Parse(req.RRule); err != nil {
+			fatal("%v", err)
 		}
 	}
-	return ""
-}
-
-func fatal(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "pylon: "+format+"\n", args...)
-	os.Exit(1)
-}
-
-func usage() {
-	fmt.Fprintf(os.Stderr, `pylon - interact with deployed infrastructure
-
-Usage:
-  pylon <service> <command> [flags]
-
-Services:
-  cal         Calendar subscription service
-  discord     Discord messaging and channel access
-
-Other:
-  version     Show version
-  help        Show this help
-
-Configuration:
-  ~/.pylonrc            INI-style config file (optional)
-  PYLON_* env vars      Override config file values
-
-Run 'pylon <service> --help' for service-specific commands.
-`)
-}
-
-func calUsage() {
-	fmt.Fprintf(os.Stderr, `pylon cal - calendar service commands
-
-Usage:
-  pylon cal [--url <base-url>] <resource> <action> [flags]
-
-Resources:
-  feed        Manage calendar feeds
-  event       Manage calendar events
-  subscribe   Get subscription URLs for a feed
 
-Configuration:
-  ~/.pylonrc [cal] url = ...     Base URL for the cal service
-  PYLON_CAL_URL                  Env var override (default: http://localhost:8085)
-`)
+	return req, externalID
 }
 
-func calFeedUsage() {
-	fmt.Fprintf(os.Stderr, `pylon cal feed - manage calendar feeds
-
-Commands:
-  create <name> [slug]  Create a new feed (slug sets a readable URL token)
-  list                  List all feeds
-  delete <id>           Delete a feed and all its events
-`)
+// alarmFlags returns the --alarm durations as ICS triggers before the start.
+// Zero is an alarm at the start itself.
+func alarmFlags(fs *flagSet) []string {
+	var alarms []string
+	for _, v := range fs.Strings("alarm") {
+		d, err := timeutil.ParseDuration(v)
+		if err != nil || d < 0 {
+			fatal("invalid --alarm %q: want a duration like 15m or 1d, or 0 for the start", v)
+		}
+		alarms = append(alarms, ics.FormatDuration(-d))
+	}
+	return alarms
 }
 
-func calEventUsage() {
-	fmt.Fprintf(os.Stderr, `pylon cal event - manage calendar events
-
-Commands:
-  add [flags]         Create a new event
-  list --feed <id>    List events for a feed
-  delete <id>         Delete an event
-
-Flags for 'add':
-  --feed <id>         Feed ID (required)
-  --summary <text>    Event title (required)
-  --start <datetime>  Start time in RFC 3339 format (required)
-  --end <datetime>    End time in RFC 3339 format
-  --description <text>
-  --location <text>
-  --url <url>
-  --all-day           Mark as all-day event
-  --deadline <datetime>  Deadline with alarm
-  --status <status>   TENTATIVE, CONFIRMED, or CANCELLED
-  --categories <list> Comma-separated categories
-`)
+// takeFlag reports whether args[*i] is the flag --name, given either as
+// "--name value" or "--name=value", and returns its value. For the separate
+// form *i is advanced past the value.
+func takeFlag(args []string, i *int, name string) (string, bool) {
+	a := args[*i]
+	if a == "--"+name {
+		if *i+1 >= len(args) {
+			fatal("--%s requires a value", name)
+		}
+		*i++
+		return args[*i], true
+	}
+	if strings.HasPrefix(a, "--"+name+"=") {
+		return strings.TrimPrefix(a, "--"+name+"="), true
+	}
+	return "", false
 }
 
-func discordUsage() {
-	fmt.Fprintf(os.Stderr, `pylon discord - Discord messaging and channel access
-
-Usage:
-  pylon discord <command> [flags]
-
-Commands:
-  msg <message>                     Send a message via webhook
-  read [--channel <id>] [--count N] Read recent messages from a channel
-  channels [--guild <id>]           List text channels in a guild
-
-Configuration (~/.pylonrc [discord] section or env vars):
-  webhook      / PYLON_DISCORD_WEBHOOK      Webhook URL for sending messages
-  bot_token    / PYLON_DISCORD_BOT_TOKEN    Bot token for reading messages/channels
-  guild_id     / PYLON_DISCORD_GUILD_ID     Default guild (server) ID
-  channel_id   / PYLON_DISCORD_CHANNEL_ID   Default channel ID for reading
-`)
+func fatal(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "pylon: "+format+"\n", args...)
+	fail()
 }
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/mcp"
+	"github.com/jredh-dev/pylon/internal/recur"
+)
+
+func runMCP(args []string) {
+	if len(args) < 1 {
+		usageFor("mcp")
+		fail()
+	}
+	switch args[0] {
+	case "serve":
+		parseFlags(args[1:], "mcp", "serve").noArgs()
+		cfg := loadConfig()
+		s := mcp.NewServer("pylon", version)
+		addCalTools(s, newCalClient(cfg, cfg.CalURL))
+		addDiscordTools(s, cfg, newDiscordClient(cfg))
+		if err := s.Serve(os.Stdin, os.Stdout); err != nil {
+			fatal("mcp: %v", err)
+		}
+	default:
+		unknownCommand(args[0], "mcp")
+	}
+}
+
+// schema builds a JSON Schema object with the given properties, each
+// described by a type and description, and required property names.
+func schema(props map[string][2]string, required ...string) map[string]any {
+	p := map[string]any{}
+	for name, td := range props {
+		p[name] = map[string]any{"type": td[0], "description": td[1]}
+	}
+	s := map[string]any{"type": "object", "properties": p}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// decodeArgs unmarshals tool arguments into v, rejecting unknown fields so a
+// misspelt argument isn't silently ignored.
+func decodeArgs(args json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+func addCalTools(s *mcp.Server, client *cal.Client) {
+	s.Add(mcp.Tool{
+		Name:        "list_feeds",
+		Description: "List the calendar feeds. Every event belongs to a feed; use a feed's id with list_events and create_event.",
+		InputSchema: schema(nil),
+		Call: func(json.RawMessage) (any, error) {
+			return client.ListFeeds()
+		},
+	})
+
+	s.Add(mcp.Tool{
+		Name: "list_events",
+		Description: "List the events in a feed. With from and to, recurring events are expanded " +
+			"and only occurrences overlapping the window are returned.",
+		InputSchema: schema(map[string][2]string{
+			"feed_id": {"string", "Feed ID from list_feeds"},
+			"from":    {"string", "Window start, RFC 3339 (e.g. 2026-03-01T00:00:00Z)"},
+			"to":      {"string", "Window end, RFC 3339"},
+		}, "feed_id"),
+		Call: func(raw json.RawMessage) (any, error) {
+			var a struct {
+				FeedID string `json:"feed_id"`
+				From   string `json:"from"`
+				To     string `json:"to"`
+			}
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			if a.FeedID == "" {
+				return nil, errors.New("feed_id is required")
+			}
+			if (a.From == "") != (a.To == "") {
+				return nil, errors.New("from and to must be given together")
+			}
+			events, err := client.ListEvents(a.FeedID)
+			if err != nil || a.From == "" {
+				return events, err
+			}
+			from, err := time.Parse(time.RFC3339, a.From)
+			if err != nil {
+				return nil, fmt.Errorf("from: %v", err)
+			}
+			to, err := time.Parse(time.RFC3339, a.To)
+			if err != nil {
+				return nil, fmt.Errorf("to: %v", err)
+			}
+			out := []cal.Event{}
+			for _, e := range recur.ExpandAll(events, from, to) {
+				end := e.Start
+				if e.End != nil {
+					end = *e.End
+				}
+				if e.Start.Before(to) && !end.Before(from) {
+					out = append(out, e)
+				}
+			}
+			return out, nil
+		},
+	})
+
+	s.Add(mcp.Tool{
+		Name:        "create_event",
+		Description: "Create an event in a feed. Returns the created event.",
+		InputSchema: schema(map[string][2]string{
+			"feed_id":     {"string", "Feed ID from list_feeds"},
+			"summary":     {"string", "Event title"},
+			"start":       {"string", "Start, RFC 3339; a date (2026-03-01) for all-day events"},
+			"end":         {"string", "End, same format as start"},
+			"all_day":     {"boolean", "All-day event"},
+			"description": {"string", "Longer description"},
+			"location":    {"string", "Where the event takes place"},
+			"url":         {"string", "Link for the event"},
+			"deadline":    {"string", "Deadline, RFC 3339; exported with an alarm"},
+			"status":      {"string", "CONFIRMED, TENTATIVE or CANCELLED"},
+			"categories":  {"string", "Comma-separated categories"},
+			"rrule":       {"string", "RFC 5545 recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO"},
+		}, "feed_id", "summary", "start"),
+		Call: func(raw json.RawMessage) (any, error) {
+			var req cal.CreateEventRequest
+			if err := decodeArgs(raw, &req); err != nil {
+				return nil, err
+			}
+			if req.FeedID == "" || req.Summary == "" || req.Start == "" {
+				return nil, errors.New("feed_id, summary and start are required")
+			}
+			if req.RRule != "" {
+				if _, err := recur.Parse(req.RRule); err != nil {
+					return nil, err
+				}
+			}
+			return client.CreateEvent(&req)
+		},
+	})
+}
+
+func addDiscordTools(s *mcp.Server, cfg *config.Config, client *discord.Client) {
+	s.Add(mcp.Tool{
+		Name: "send_discord_message",
+		Description: "Post a message to Discord. Without channel_id it goes to the configured " +
+			"webhook, or the default channel if there is no webhook.",
+		InputSchema: schema(map[string][2]string{
+			"message":    {"string", "Message text (Discord markdown)"},
+			"channel_id": {"string", "Channel or thread ID to post to with the bot"},
+		}, "message"),
+		Call: func(raw json.RawMessage) (any, error) {
+			var a struct {
+				Message   string `json:"message"`
+				ChannelID string `json:"channel_id"`
+			}
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			if a.Message == "" {
+				return nil, errors.New("message is required")
+			}
+			if a.ChannelID == "" && cfg.DiscordWebhook != "" {
+				if err := client.SendMessage(a.Message); err != nil {
+					return nil, err
+				}
+				return "sent", nil
+			}
+			if a.ChannelID == "" {
+				a.ChannelID = cfg.DiscordChannelID
+			}
+			if a.ChannelID == "" {
+				return nil, errors.New("channel_id is required: no webhook or default channel is configured")
+			}
+			return client.SendChannelMessage(a.ChannelID, a.Message, "")
+		},
+	})
+
+	s.Add(mcp.Tool{
+		Name:        "read_channel",
+		Description: "Read the most recent messages in a Discord channel or thread, newest first.",
+		InputSchema: schema(map[string][2]string{
+			"channel_id": {"string", "Channel or thread ID; defaults to the configured channel"},
+			"limit":      {"integer", "Number of messages, 1-100 (default 20)"},
+		}),
+		Call: func(raw json.RawMessage) (any, error) {
+			var a struct {
+				ChannelID string `json:"channel_id"`
+				Limit     int    `json:"limit"`
+			}
+			if err := decodeArgs(raw, &a); err != nil {
+				return nil, err
+			}
+			if a.ChannelID == "" {
+				a.ChannelID = cfg.DiscordChannelID
+			}
+			if a.ChannelID == "" {
+				return nil, errors.New("channel_id is required: no default channel is configured")
+			}
+			if a.Limit <= 0 {
+				a.Limit = 20
+			}
+			return client.ReadMessages(a.ChannelID, min(a.Limit, 100))
+		},
+	})
+}
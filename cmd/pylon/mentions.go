@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// mentionFlags documents the flags withMentionFlags reads, for commands
+// that send messages.
+var mentionFlags = []flagDoc{
+	{Name: "no-mentions", Help: "Don't let mentions in the message ping anyone"},
+	{Name: "mention-users", Help: "Only let user mentions ping (not @everyone, @here or roles)"},
+	{Name: "mention-roles", Help: "Only let role mentions ping (not @everyone, @here or users)"},
+}
+
+// withMentionFlags returns client, or a client like it that sends with the
+// allowed mentions asked for by --no-mentions, --mention-users and
+// --mention-roles. Any of them keeps @everyone and @here from pinging.
+func withMentionFlags(cfg *config.Config, client *discord.Client, fs *flagSet) *discord.Client {
+	none, users, roles := fs.Bool("no-mentions"), fs.Bool("mention-users"), fs.Bool("mention-roles")
+	if !none && !users && !roles {
+		return client
+	}
+	if none && (users || roles) {
+		fatal("--no-mentions can't be combined with --mention-users or --mention-roles")
+	}
+	var m discord.AllowedMentions
+	if users {
+		m.Parse = append(m.Parse, "users")
+		m.RepliedUser = true
+	}
+	if roles {
+		m.Parse = append(m.Parse, "roles")
+	}
+	opts := append(discordOptions(cfg), discord.WithAllowedMentions(m))
+	return discord.NewClient(cfg.DiscordBotToken, cfg.DiscordWebhook, opts...)
+}
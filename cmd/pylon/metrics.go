@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/metrics"
+	"github.com/jredh-dev/pylon/internal/watchdog"
+)
+
+// The metrics long-running commands serve with --metrics-addr. Jobs are labelled with their watchdog name.
+var (
+	apiRequests = metrics.Default.Counter("pylon_api_requests_total",
+		"Requests to the cal and Discord APIs, by host and HTTP status code.", "host", "code")
+	apiErrors = metrics.Default.Counter("pylon_api_errors_total",
+		"API requests that got no response or a 5xx status, by host.", "host")
+	rateLimited = metrics.Default.Counter("pylon_rate_limited_total",
+		"API requests answered with 429 Too Many Requests, by host.", "host")
+	notificationsSent = metrics.Default.Counter("pylon_notifications_sent_total",
+		"Messages posted by a job: reminders, follow-up prompts and thread agendas.", "kind")
+	jobRuns = metrics.Default.Counter("pylon_job_runs_total",
+		"Runs of each job, by result (ok or error).", "job", "result")
+	jobFailures = metrics.Default.Gauge("pylon_job_consecutive_failures",
+		"Runs of each job that have failed in a row.", "job")
+	jobLastRun = metrics.Default.Gauge("pylon_job_last_run_timestamp_seconds",
+		"Unix time each job last started a run.", "job")
+	jobLastSuccess = metrics.Default.Gauge("pylon_job_last_success_timestamp_seconds",
+		"Unix time each job last started a run that succeeded.", "job")
+)
+
+// metered is set once metrics are served, so API clients built afterwards
+// count their requests.
+var metered bool
+
+// meteredTransport counts the requests that go through it.
+type meteredTransport struct {
+	next http.RoundTripper // nil means http.DefaultTransport
+}
+
+func (t meteredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	host := req.URL.Hostname()
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		apiErrors.Inc(host)
+		return nil, err
+	}
+	apiRequests.Inc(host, strconv.Itoa(resp.StatusCode))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		rateLimited.Inc(host)
+	case resp.StatusCode >= 500:
+		apiErrors.Inc(host)
+	}
+	return resp, nil
+}
+
+// serveMetrics serves /metrics on addr in the background for the rest of
+// the process, and makes API clients built from now on count requests. An
+// empty addr does nothing.
+func serveMetrics(addr string) {
+	if addr == "" {
+		return
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal("metrics: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Default.Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	metered = true
+	fmt.Fprintf(os.Stderr, "pylon: serving metrics on http://%s/metrics\n", ln.Addr())
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "pylon: metrics: %v\n", err)
+		}
+	}()
+}
+
+// runJob runs fn as a run of job under the watchdog and records it in the
+// job metrics. The failure count and last success come from the watchdog's
+// status, so they survive restarts.
+func runJob(wd *watchdog.Watchdog, job string, fn func() error) error {
+	start := time.Now()
+	var runErr error
+	err := wd.Run(job, func() error {
+		runErr = fn()
+		return runErr
+	})
+	// A watchdog that can't save or notify doesn't fail the run.
+	recordRun(job, start, runErr)
+	if st, lerr := watchdog.Load(wd.Dir, job); lerr == nil {
+		jobFailures.Set(float64(st.Failures), job)
+		if !st.LastSuccess.IsZero() {
+			jobLastSuccess.Set(unixSeconds(st.LastSuccess), job)
+		}
+	}
+	return err
+}
+
+// recordRun records a run of job that started at start and ended with err.
+func recordRun(job string, start time.Time, err error) {
+	jobLastRun.Set(unixSeconds(start), job)
+	if err != nil {
+		jobRuns.Inc(job, "error")
+		return
+	}
+	jobRuns.Inc(job, "ok")
+	jobLastSuccess.Set(unixSeconds(start), job)
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/plan"
+	"github.com/jredh-dev/pylon/internal/platform"
+)
+
+// runCalEventMirror copies an event into other feeds, or with --sync plans
+// (and with --apply makes) the changes that bring every existing mirror back
+// in step with its source.
+func runCalEventMirror(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "event", "mirror")
+	if len(fs.args) > 1 {
+		fatal("cal event mirror: unexpected argument %q; mirror one event at a time", fs.args[1])
+	}
+	var id string
+	if len(fs.args) > 0 {
+		id = fs.args[0]
+	}
+	to, only := fs.Strings("to"), fs.Strings("feed")
+	sync, planOnly, apply := fs.Bool("sync"), fs.Bool("plan"), fs.Bool("apply")
+	lockOpts := lockFlagsFrom(fs)
+	only, to = resolveFeeds(client, only), resolveFeeds(client, to)
+	if sync {
+		if id != "" || len(to) > 0 {
+			fatal("--sync takes no event or --to; use --feed to limit it to mirror feeds")
+		}
+		if planOnly && apply {
+			fatal("use either --plan or --apply, not both")
+		}
+		if apply {
+			acquireLock("mirror-sync", "cal event mirror --sync", lockOpts)
+		}
+		syncMirrors(client, only, apply)
+		return
+	}
+	if id == "" || len(to) == 0 || len(only) > 0 || planOnly || apply {
+		fatal("usage: pylon cal event mirror <id> --to <feed-id> [--to <feed-id>...]\n       pylon cal event mirror --sync [--feed <feed-id>] [--plan | --apply]")
+	}
+
+	src, err := getEvent(client, id)
+	if err != nil {
+		fatal("get event: %v", err)
+	}
+	if orig, ok := src.MirrorSource(); ok {
+		fatal("event %s is itself a mirror of %s; mirror that event instead", id, orig)
+	}
+	for _, feedID := range to {
+		if feedID == src.FeedID {
+			fatal("event %s is already in feed %s", id, feedID)
+		}
+		m, _, err := client.UpsertEvent(cal.MirrorID(src.ID, feedID), src.MirrorRequest(feedID))
+		if errors.Is(err, cal.ErrNotSupported) {
+			fatal("this cal server does not support upserts, which mirrors need")
+		}
+		if err != nil {
+			fatal("mirror to %s: %v", feedID, err)
+		}
+		fmt.Println(i18n.T("mirror.created", feedID, m.ID))
+	}
+}
+
+// syncMirrors plans an update for every mirror (in the given feeds, or all)
+// whose source has changed, and a delete for every mirror whose source is
+// gone. The plan is only carried out with apply.
+func syncMirrors(client *cal.Client, only []string, apply bool) {
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("list feeds: %v", err)
+	}
+	// Sources can be in any feed, so every feed is read.
+	byID := map[string]*cal.Event{}
+	var mirrors []*cal.Event
+	for _, f := range feeds {
+		events, err := client.ListEvents(f.ID)
+		if err != nil {
+			fatal("list events for %s: %v", f.ID, err)
+		}
+		for i := range events {
+			e := &events[i]
+			byID[e.ID] = e
+			if _, ok := e.MirrorSource(); ok && (len(only) == 0 || slices.Contains(only, e.FeedID)) {
+				mirrors = append(mirrors, e)
+			}
+		}
+	}
+
+	p := &plan.Plan{}
+	for _, m := range mirrors {
+		srcID, _ := m.MirrorSource()
+		src, ok := byID[srcID]
+		switch {
+		case !ok:
+			p.Add(plan.Change{
+				Action: plan.Delete, Kind: "mirror", ID: m.ID, Title: m.Summary,
+				Reason: "source " + srcID + " deleted",
+				Apply:  func() error { return client.DeleteEvent(m.ID) },
+			})
+		case !cal.InSync(m, src):
+			before, after := m.CreateRequest(""), src.CreateRequest("")
+			before.ExternalID, after.ExternalID = "", ""
+			p.Add(plan.Change{
+				Action: plan.Update, Kind: "mirror", ID: m.ID, Title: m.Summary,
+				Diffs: plan.Fields(before, after),
+				Apply: func() error {
+					_, _, err := client.UpsertEvent(m.ExternalID, src.MirrorRequest(m.FeedID))
+					return err
+				},
+			})
+		}
+	}
+
+	if !apply {
+		showPlan(p)
+		return
+	}
+	if err := p.Write(os.Stdout, platform.Color(os.Stdout)); err != nil {
+		fatal("mirror: %v", err)
+	}
+	var updated, deleted int
+	_, failed := p.Apply(func(c plan.Change, err error) {
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "pylon: %s mirror %s: %v\n", c.Action, c.ID, err)
+		case c.Action == plan.Delete:
+			deleted++
+		default:
+			updated++
+		}
+	})
+	fmt.Println(i18n.T("mirror.synced", len(mirrors), updated, deleted, failed))
+	if failed > 0 {
+		exit(1)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runDiscordModerate implements `pylon discord timeout|kick|ban`. These are
+// refused unless [discord] allow_moderation is enabled, and always need a
+// --reason for the audit log.
+func runDiscordModerate(cfg *config.Config, client *discord.Client, action string, args []string) {
+	fs := parseFlags(args, "discord", action)
+	reason, guildID, deleteMessages := fs.String("reason", ""), fs.String("guild", ""), fs.String("delete-messages", "")
+	positional := fs.args
+
+	want := 1
+	synopsis := "pylon discord " + action + " <user> --reason <text>"
+	if action == "timeout" {
+		want = 2
+		synopsis = "pylon discord timeout <user> <duration> --reason <text>"
+	}
+	if len(positional) != want {
+		fatal("usage: %s", synopsis)
+	}
+	if !cfg.DiscordAllowModeration {
+		fatal("moderation commands are disabled\nEnable them with: pylon config set discord.allow_moderation true")
+	}
+	if reason == "" {
+		fatal("--reason is required; it is recorded in the server's audit log")
+	}
+	if guildID == "" {
+		guildID = cfg.DiscordGuildID
+	}
+	if guildID == "" {
+		fatal("guild ID required\nPass --guild <id> or set guild_id in ~/.pylonrc [discord] or PYLON_DISCORD_GUILD_ID")
+	}
+	userID := memberID(positional[0])
+
+	var err error
+	switch action {
+	case "timeout":
+		var d time.Duration
+		if positional[1] != "0" {
+			d, err = timeutil.ParseDuration(positional[1])
+			if err != nil || d <= 0 {
+				fatal("invalid duration %q: want e.g. 10m, 1h or 7d (0 lifts the timeout)", positional[1])
+			}
+		}
+		err = client.TimeoutMember(guildID, userID, d, reason)
+		if err == nil {
+			if d == 0 {
+				fmt.Printf("Lifted timeout for %s.\n", userID)
+			} else {
+				fmt.Printf("Timed out %s for %s.\n", userID, positional[1])
+			}
+		}
+	case "kick":
+		err = client.KickMember(guildID, userID, reason)
+		if err == nil {
+			fmt.Printf("Kicked %s.\n", userID)
+		}
+	case "ban":
+		var window time.Duration
+		if deleteMessages != "" {
+			window, err = timeutil.ParseDuration(deleteMessages)
+			if err != nil || window < 0 {
+				fatal("invalid --delete-messages %q: want e.g. 1h or 7d", deleteMessages)
+			}
+		}
+		err = client.BanMember(guildID, userID, window, reason)
+		if err == nil {
+			fmt.Printf("Banned %s.\n", userID)
+		}
+	}
+	if err != nil {
+		fatal("discord %s: %v", action, err)
+	}
+}
+
+// memberID accepts a raw user ID or a pasted mention such as <@123> or
+// <@!123>.
+func memberID(s string) string {
+	if strings.HasPrefix(s, "<@") && strings.HasSuffix(s, ">") {
+		return strings.TrimPrefix(s[2:len(s)-1], "!")
+	}
+	return s
+}
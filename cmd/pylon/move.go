@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalEventMove copies an event into another feed and, for move, deletes
+// the original. The cal API has no way to change an event's feed, so a move
+// is a create and a delete; if the delete fails the copy is deleted again,
+// so the event ends up in exactly one feed, and anything left over is
+// reported with the command that cleans it up.
+func runCalEventMove(client *cal.Client, args []string, move bool) {
+	verb := "copy"
+	if move {
+		verb = "move"
+	}
+	fs := parseFlags(args, "cal", "event", verb)
+	to := fs.String("to-feed", "")
+	if len(fs.args) != 1 || to == "" {
+		fatal("usage: pylon cal event %s <id> --to-feed <feed-id>", verb)
+	}
+	id := fs.args[0]
+	to = resolveFeed(client, to)
+
+	src, err := getEvent(client, id)
+	if err != nil {
+		fatal("%s event: %v", verb, err)
+	}
+	if src.FeedID == to {
+		fatal("event %s is already in feed %s", id, to)
+	}
+	if orig, ok := src.MirrorSource(); ok {
+		fatal("event %s is a mirror of %s; %s that event, or mirror it with --to", id, orig, verb)
+	}
+
+	// A moved event keeps its external ID, UID and SEQUENCE, so it stays
+	// the same event to automation and calendar apps; a copy is a new one.
+	req := src.CopyRequest(to)
+	if move {
+		req = src.CreateRequest(to)
+	}
+	e, err := client.CreateEvent(req)
+	if err != nil {
+		fatal("%s event: create in %s: %v", verb, to, err)
+	}
+	if !move {
+		fmt.Println(i18n.T("event.copied", src.Summary, to, e.ID))
+		return
+	}
+
+	if err := client.DeleteEvent(src.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "pylon: move event: delete the original %s: %v\n", src.ID, err)
+		if rerr := client.DeleteEvent(e.ID); rerr != nil {
+			fatal("move event: the event is now in both feeds, and removing the copy failed too: %v\n"+
+				"Delete one of them: pylon cal event delete %s (original) or %s (copy)", rerr, src.ID, e.ID)
+		}
+		fatal("move event: nothing was moved; the copy in %s was removed again", to)
+	}
+	fmt.Println(i18n.T("event.moved", src.Summary, to, e.ID))
+}
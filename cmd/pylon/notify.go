@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+// notifyChanges reports whether a command that changes events should post
+// a summary of them: --notify-changes if given, else [cal] notify_changes.
+func notifyChanges(cfg *config.Config, fs *flagSet) bool {
+	if fs.Has("notify-changes") {
+		return fs.Bool("notify-changes")
+	}
+	return cfg.CalNotifyChanges
+}
+
+// noticeTime formats an event time for a change notice.
+func noticeTime(t time.Time, allDay bool) string {
+	if allDay {
+		return t.Format("Mon 2 Jan")
+	}
+	return t.In(time.Local).Format("Mon 2 Jan 15:04 MST")
+}
+
+// changeNotice renders the changed fields of e for a chat message.
+func changeNotice(e *cal.Event, diffs []plan.Diff) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T("notify.changed", e.Summary, noticeTime(e.Start, e.AllDay)))
+	for _, d := range diffs {
+		fmt.Fprintf(&sb, "\n• %s: %s → %s", d.Field, noticeValue(d.Old), noticeValue(d.New))
+	}
+	return sb.String()
+}
+
+// noticeValue makes a diff value readable: times as in noticeTime and
+// empty values as a dash.
+func noticeValue(v string) string {
+	if v == "" {
+		return "–"
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return noticeTime(t, false)
+	}
+	return v
+}
+
+// cancelNotice renders the cancellation of e for a chat message.
+func cancelNotice(e *cal.Event) string {
+	return i18n.T("notify.cancelled", e.Summary, noticeTime(e.Start, e.AllDay))
+}
+
+// confirmNotice announces that a cancelled event is back on.
+func confirmNotice(e *cal.Event) string {
+	return i18n.T("notify.confirmed", e.Summary, noticeTime(e.Start, e.AllDay))
+}
+
+// sendNotices posts notices to [cal] notify_channel, or the webhook, as few
+// messages as Discord's length limit allows. A failure is reported but
+// doesn't fail the command, whose changes are made by then.
+func sendNotices(cfg *config.Config, notices []string) {
+	if len(notices) == 0 {
+		return
+	}
+	msgs := discord.SplitMessage(strings.Join(notices, "\n\n"), discord.MaxMessageLength)
+
+	client := newDiscordClient(cfg)
+	for _, msg := range msgs {
+		var err error
+		switch {
+		case cfg.CalNotifyChannel != "":
+			_, err = client.SendChannelMessage(cfg.Channel(cfg.CalNotifyChannel), msg, "")
+		case cfg.DiscordWebhook != "":
+			err = client.SendMessage(msg)
+		default:
+			err = fmt.Errorf("set cal.notify_channel or discord.webhook")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: notify changes: %v\n", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jredh-dev/pylon/internal/output"
+)
+
+// Set by the --output-file and --output-append global flags.
+var (
+	outputPath   string
+	outputAppend bool
+)
+
+// out is where standard output goes when --output-file is given; nil
+// otherwise.
+var out output.Writer
+
+// openOutput redirects standard output to --output-file, if given.
+func openOutput() {
+	if outputPath == "" {
+		if outputAppend {
+			fatal("--output-append requires --output-file")
+		}
+		return
+	}
+	w, err := output.Open(outputPath, outputAppend)
+	if err != nil {
+		fatal("output: %v", err)
+	}
+	out = w
+	os.Stdout = w.File()
+}
+
+// exit finishes the output of a command that ran to completion and exits
+// with code. Completed runs keep their output even when code is non-zero,
+// e.g. a summary of partial failures.
+func exit(code int) {
+	releaseLocks()
+	if out != nil {
+		if err := out.Commit(); err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: output: %v\n", err)
+			code = 1
+		}
+		out = nil
+	}
+	os.Exit(code)
+}
+
+// fail exits with status 1 after a failed command, discarding its output so
+// an earlier capture in --output-file stays intact.
+func fail() {
+	releaseLocks()
+	if out != nil {
+		out.Abort()
+		out = nil
+	}
+	os.Exit(1)
+}
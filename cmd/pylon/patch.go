@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/plan"
+	"github.com/jredh-dev/pylon/internal/platform"
+)
+
+// runCalEventPatch applies a JSON merge patch to every event in the --feed
+// feeds that matches all --filter conditions. Like mirror --sync it only
+// prints the plan unless --apply is given.
+func runCalEventPatch(cfg *config.Config, client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "event", "patch")
+	feeds := fs.Strings("feed")
+	var filters []cal.Filter
+	for _, v := range fs.Strings("filter") {
+		f, err := cal.ParseFilter(v)
+		if err != nil {
+			fatal("--filter: %v", err)
+		}
+		filters = append(filters, f)
+	}
+	planOnly, apply := fs.Bool("plan"), fs.Bool("apply")
+	alarms, noAlarms := alarmFlags(fs), fs.Bool("no-alarms")
+	changesAlarms := len(alarms) > 0 || noAlarms
+	if len(feeds) == 0 || len(fs.args) > 1 || (len(fs.args) == 0 && !changesAlarms) {
+		fatal("usage: pylon cal event patch --feed <id> [--filter field=value]... [--alarm <duration>... | --no-alarms] [--plan | --apply] [<patch.json|->]")
+	}
+	feeds = resolveFeeds(client, feeds)
+	if planOnly && apply {
+		fatal("use either --plan or --apply, not both")
+	}
+	if len(alarms) > 0 && noAlarms {
+		fatal("use either --alarm or --no-alarms, not both")
+	}
+
+	patch := []byte("{}")
+	var err error
+	switch {
+	case len(fs.args) == 0:
+	case fs.args[0] == "-":
+		patch, err = io.ReadAll(os.Stdin)
+	default:
+		patch, err = os.ReadFile(fs.args[0])
+	}
+	if err != nil {
+		fatal("patch: %v", err)
+	}
+	if changesAlarms {
+		if patch, err = setPatchAlarms(patch, alarms); err != nil {
+			fatal("patch: %v", err)
+		}
+	}
+
+	p := &plan.Plan{}
+	patched := map[string]*cal.Event{}
+	for _, feedID := range feeds {
+		events, err := client.ListEvents(feedID)
+		if err != nil {
+			fatal("list events for %s: %v", feedID, err)
+		}
+		for i := range events {
+			e := &events[i]
+			if !matchesAll(filters, e) {
+				continue
+			}
+			after, err := e.PatchRequest(patch)
+			if err != nil {
+				fatal("%v", err)
+			}
+			diffs := plan.Fields(e.CreateRequest(e.FeedID), after)
+			if len(diffs) == 0 {
+				continue
+			}
+			patched[e.ID] = e
+			p.Add(plan.Change{
+				Action: plan.Update, Kind: "event", ID: e.ID, Title: e.Summary,
+				Diffs: diffs,
+				Apply: func() error {
+					_, err := updateEvent(client, e, after)
+					return err
+				},
+			})
+		}
+	}
+
+	if !apply {
+		showPlan(p)
+		return
+	}
+	if err := p.Write(os.Stdout, platform.Color(os.Stdout)); err != nil {
+		fatal("patch: %v", err)
+	}
+	var notices []string
+	applied, failed := p.Apply(func(c plan.Change, err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: patch event %s: %v\n", c.ID, err)
+			return
+		}
+		notices = append(notices, changeNotice(patched[c.ID], c.Diffs))
+	})
+	fmt.Println(i18n.T("patch.summary", applied, failed))
+	if notifyChanges(cfg, fs) {
+		sendNotices(cfg, notices)
+	}
+	if failed > 0 {
+		exit(1)
+	}
+}
+
+// setPatchAlarms returns patch with its alarms replaced by alarms, or
+// removed if there are none.
+func setPatchAlarms(patch []byte, alarms []string) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(patch, &doc); err != nil || doc == nil {
+		return nil, errors.New("with --alarm or --no-alarms the patch must be a JSON object")
+	}
+	doc["alarms"] = nil
+	if len(alarms) > 0 {
+		doc["alarms"] = alarms
+	}
+	return json.Marshal(doc)
+}
+
+func matchesAll(filters []cal.Filter, e *cal.Event) bool {
+	for _, f := range filters {
+		if !f.Matches(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateEvent replaces e's details with req, through upsert on servers
+// that can't update events in place, and returns the updated event.
+func updateEvent(client *cal.Client, e *cal.Event, req *cal.CreateEventRequest) (*cal.Event, error) {
+	updated, err := client.UpdateEvent(e.ID, req)
+	if !errors.Is(err, cal.ErrNotSupported) {
+		return updated, err
+	}
+	if e.ExternalID == "" {
+		return nil, errors.New("this cal server can't update events in place, and the event has no external ID to upsert it by")
+	}
+	updated, _, err = client.UpsertEvent(e.ExternalID, req)
+	return updated, err
+}
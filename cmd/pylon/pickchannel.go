@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runDiscordPickChannel lists the text channels the bot can see, asks which
+// one to use and saves it as channel_id, or under an alias in [channels].
+func runDiscordPickChannel(cfg *config.Config, client *discord.Client, args []string) {
+	fs := parseFlags(args, "discord", "pick-channel")
+	fs.noArgs()
+	guildID, alias := fs.String("guild", cfg.DiscordGuildID), fs.String("alias", "")
+
+	var guilds []discord.Guild
+	if guildID != "" {
+		g, err := client.GetGuild(guildID)
+		if err != nil {
+			fatal("discord pick-channel: %v", err)
+		}
+		guilds = []discord.Guild{*g}
+	} else {
+		var err error
+		if guilds, err = client.Guilds(); err != nil {
+			fatal("discord pick-channel: %v", err)
+		}
+	}
+
+	type choice struct {
+		guild   string
+		channel discord.Channel
+	}
+	var choices []choice
+	for _, g := range guilds {
+		channels, err := client.ListChannels(g.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: list channels in %s: %v\n", g.Name, err)
+			continue
+		}
+		for _, ch := range channels {
+			choices = append(choices, choice{g.Name, ch})
+		}
+	}
+	if len(choices) == 0 {
+		fatal("discord pick-channel: the bot can't see any text channels")
+	}
+
+	tw := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "#\tGUILD\tCHANNEL\tID\n")
+	for i, c := range choices {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t#%s\t%s\n", i+1, c.guild, c.channel.Name, c.channel.ID)
+	}
+	_ = tw.Flush()
+
+	answer := prompt(i18n.T("pick.prompt", len(choices)))
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(choices) {
+		if answer == "" {
+			fatal("no channel picked")
+		}
+		fatal("not a channel number: %q", answer)
+	}
+	picked := choices[n-1].channel
+
+	section, key := "discord", "channel_id"
+	if alias != "" {
+		section, key = "channels", alias
+	}
+	f := openConfigFile()
+	f.Set(section, key, picked.ID)
+	if err := f.Save(); err != nil {
+		fatal("discord pick-channel: %v", err)
+	}
+	fmt.Println(i18n.T("pick.saved", section+"."+key, picked.ID, picked.Name, f.Path()))
+}
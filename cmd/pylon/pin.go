@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runCalPin manages pins, undated announcements kept on a feed's pin board.
+func runCalPin(client *cal.Client, defaultFeed string, args []string) {
+	if len(args) == 0 {
+		usageFor("cal", "pin")
+		fail()
+	}
+	switch args[0] {
+	case "add":
+		runCalPinAdd(client, defaultFeed, args[1:])
+	case "list", "ls":
+		runCalPinList(client, args[1:])
+	case "remove", "rm":
+		runCalPinRemove(client, args[1:])
+	default:
+		unknownCommand(args[0], "cal", "pin")
+	}
+}
+
+func runCalPinAdd(client *cal.Client, feedID string, args []string) {
+	fs := parseFlags(args, "cal", "pin", "add")
+	words := fs.args
+	feedID = fs.String("feed", feedID)
+	until := fs.String("until", "")
+	if len(words) == 0 {
+		fatal("usage: pylon cal pin add <text> [--feed <id>] [--until <date>]")
+	}
+	if feedID == "" {
+		fatal("no feed: pass --feed, or set one with pylon config set cal.default_feed <id>")
+	}
+	feedID = resolveFeed(client, feedID)
+
+	now := time.Now()
+	var untilT time.Time
+	if until != "" {
+		// --until takes a date, or an age such as 7d counted forward.
+		t, err := time.ParseInLocation("2006-01-02", until, time.Local)
+		if err != nil {
+			d, derr := timeutil.ParseDuration(until)
+			if derr != nil || d <= 0 {
+				fatal("invalid --until %q: want a date (2006-01-02) or a duration like 7d", until)
+			}
+			t = timeutil.AddNominal(now, d)
+		}
+		if t.Before(timeutil.StartOfDay(now, time.Local)) {
+			fatal("--until %s is in the past", until)
+		}
+		untilT = t
+	}
+
+	event, err := client.CreateEvent(cal.PinRequest(feedID, strings.Join(words, " "), now, untilT))
+	if err != nil {
+		fatal("pin: %v", err)
+	}
+	fmt.Println(i18n.T("pin.added", feedID, event.Summary))
+	eventPorcelain(event, true)
+}
+
+func runCalPinList(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "pin", "list")
+	fs.noArgs()
+	feedID := fs.String("feed", "")
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("list feeds: %v", err)
+	}
+	if feedID != "" {
+		if feeds = filterFeeds(feeds, feedID); len(feeds) == 0 {
+			fatal("feed not found: %s", feedID)
+		}
+	}
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	found := false
+	for _, f := range feeds {
+		events, err := client.ListEvents(f.ID)
+		if err != nil {
+			fatal("list events for %s: %v", f.ID, err)
+		}
+		for _, e := range events {
+			if !e.Pinned() {
+				continue
+			}
+			if !found {
+				_, _ = fmt.Fprintf(tw, "ID\tFEED\tPINNED\tUNTIL\tTEXT\n")
+				found = true
+			}
+			until := ""
+			switch {
+			case e.Expired(now):
+				until = "expired"
+			case e.End != nil:
+				until = e.End.AddDate(0, 0, -1).Format("2006-01-02")
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.ID, f.Name, e.Start.Format("2006-01-02"), until, e.Summary)
+		}
+	}
+	if !found {
+		fmt.Println(i18n.T("pin.none"))
+		return
+	}
+	_ = tw.Flush()
+}
+
+func runCalPinRemove(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "pin", "remove")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal pin remove <id> [--yes]")
+	}
+	id, yes := fs.args[0], fs.Bool("yes")
+	e, err := getEvent(client, id)
+	if err != nil {
+		fatal("unpin: %v", err)
+	}
+	if !e.Pinned() {
+		fatal("event %s is not a pin; use pylon cal event delete", id)
+	}
+	confirmDelete(yes, i18n.T("pin.confirm", e.Summary))
+	if err := client.DeleteEvent(e.ID); err != nil {
+		fatal("unpin: %v", err)
+	}
+	fmt.Println(i18n.T("pin.removed", e.Summary))
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/plan"
+	"github.com/jredh-dev/pylon/internal/platform"
+)
+
+// showPlan prints the changes a reconciliation job would make, for --plan.
+func showPlan(p *plan.Plan) {
+	if len(p.Changes) == 0 {
+		fmt.Println(i18n.T("plan.none"))
+		return
+	}
+	if err := p.Write(os.Stdout, platform.Color(os.Stdout)); err != nil {
+		fatal("plan: %v", err)
+	}
+	create, update, del := p.Counts()
+	fmt.Println()
+	fmt.Println(i18n.T("plan.summary", create, update, del))
+}
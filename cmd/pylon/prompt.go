@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/platform"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// prompt asks a question on stderr and returns the trimmed line typed in
+// reply, or "" at end of input.
+func prompt(question string) string {
+	fmt.Fprint(os.Stderr, question+" ")
+	line, _ := stdin.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// confirmDelete asks question unless yes is set. It fails when there is no
+// terminal to ask on, stdin being redirected or at its end, so scripts have
+// to pass --yes, and exits 1 after reporting that nothing was deleted if the
+// answer is no.
+func confirmDelete(yes bool, question string) {
+	if yes {
+		return
+	}
+	if !platform.Terminal(os.Stdin) {
+		fatal("%s", i18n.T("delete.needs_yes"))
+	}
+	fmt.Fprint(os.Stderr, question+" ")
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(os.Stderr)
+		fatal("%s", i18n.T("delete.needs_yes"))
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return
+	}
+	fatal("%s", i18n.T("delete.aborted"))
+}
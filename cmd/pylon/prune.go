@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/recur"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runCalEventPrune deletes every event in a feed that started before a
+// cutoff, recurring ones only once their last occurrence has, after listing
+// them and asking for confirmation. Unlike cal archive it keeps no copy.
+func runCalEventPrune(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "event", "prune")
+	fs.noArgs()
+	feedID, before, yes := fs.String("feed", ""), fs.String("before", ""), fs.Bool("yes")
+	if feedID == "" || before == "" {
+		fatal("usage: pylon cal event prune --feed <id> --before <age|date> [--yes]")
+	}
+	feedID = resolveFeedStrict(client, feedID)
+
+	cutoff, err := timeutil.ParseCutoff(before, time.Now(), time.Local)
+	if err != nil {
+		fatal("prune: %v", err)
+	}
+
+	events, err := client.ListEvents(feedID)
+	if err != nil {
+		fatal("list events: %v", err)
+	}
+	var old []cal.Event
+	for _, e := range events {
+		if recur.EndsBefore(e, cutoff) {
+			old = append(old, e)
+		}
+	}
+	if len(old) == 0 {
+		fmt.Println(i18n.T("prune.none", cutoff.Format(time.RFC3339)))
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "ID\tSUMMARY\tSTART\n")
+	for _, e := range old {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", e.ID, e.Summary, e.Start.Format(time.RFC3339))
+	}
+	_ = tw.Flush()
+
+	confirmDelete(yes, i18n.T("prune.confirm", len(old)))
+
+	var deleted, failed int
+	for _, e := range old {
+		if err := client.DeleteEvent(e.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: delete event %s: %v\n", e.ID, err)
+			failed++
+			continue
+		}
+		deleted++
+	}
+	fmt.Println(i18n.T("prune.summary", deleted, failed))
+	if failed > 0 {
+		exit(1)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runDiscordReact adds (or with --remove, removes) the bot's reactions on
+// a message.
+func runDiscordReact(cfg *config.Config, client *discord.Client, args []string) {
+	fs := parseFlags(args, "discord", "react")
+	channelID := cfg.DiscordChannelID
+	if v := fs.String("channel", ""); v != "" {
+		channelID = cfg.Channel(v)
+	}
+	messageID, remove := fs.String("message", ""), fs.Bool("remove")
+	emoji := fs.args
+	if channelID == "" || messageID == "" || len(emoji) == 0 {
+		fatal("usage: pylon discord react --message <id> [--channel <id>] [--remove] <emoji>...")
+	}
+
+	for _, e := range emoji {
+		var err error
+		if remove {
+			err = client.Unreact(channelID, messageID, e)
+		} else {
+			err = client.React(channelID, messageID, e)
+		}
+		if err != nil {
+			fatal("discord react %s: %v", e, err)
+		}
+		if remove {
+			fmt.Println(i18n.T("react.removed", e))
+		} else {
+			fmt.Println(i18n.T("react.added", e))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runDiscordReactors lists everyone who reacted to a message with an emoji,
+// e.g. to collect sign-ups.
+func runDiscordReactors(cfg *config.Config, client *discord.Client, args []string) {
+	fs := parseFlags(args, "discord", "reactors")
+	fs.noArgs()
+	channelID := cfg.DiscordChannelID
+	if v := fs.String("channel", ""); v != "" {
+		channelID = cfg.Channel(v)
+	}
+	messageID, emoji := fs.String("message", ""), fs.String("emoji", "")
+	format := fs.String("format", "text")
+	if channelID == "" || messageID == "" || emoji == "" {
+		fatal("usage: pylon discord reactors --message <id> --emoji <emoji> [--channel <id>] [--format text|json|csv]")
+	}
+
+	users, err := client.Reactors(channelID, messageID, emoji)
+	if err != nil {
+		fatal("discord reactors: %v", err)
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if users == nil {
+			users = []discord.Author{}
+		}
+		if err := enc.Encode(users); err != nil {
+			fatal("discord reactors: %v", err)
+		}
+	case "csv":
+		cw := csv.NewWriter(os.Stdout)
+		_ = cw.Write([]string{"id", "username", "display_name"})
+		for _, u := range users {
+			_ = cw.Write([]string{u.ID, u.Username, u.DisplayName()})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			fatal("discord reactors: %v", err)
+		}
+	case "text":
+		if len(users) == 0 {
+			fmt.Println(i18n.T("reactors.none", emoji))
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "ID\tUSERNAME\tNAME\n")
+		for _, u := range users {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", u.ID, u.Username, u.DisplayName())
+		}
+		_ = tw.Flush()
+	default:
+		fatal("discord reactors: unknown format %q: want text, json or csv", format)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// configPoll is how often long-running commands check the config file for
+// changes. The standard library can't watch files, so it is polled.
+const configPoll = 5 * time.Second
+
+// runLoop calls job now and then every interval until ctx is done. When
+// the config changes (SIGHUP, or the file is edited) reload is called,
+// between runs; the schedule is not reset.
+func runLoop(ctx context.Context, interval time.Duration, job, reload func()) {
+	changes := configChanges(ctx, configPoll)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job()
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+				reload()
+			case <-ticker.C:
+				break wait
+			}
+		}
+	}
+}
+
+// configChanges returns a channel that receives whenever the process gets
+// SIGHUP or the config file's size or modification time changes (including
+// it being created or removed), checked every poll.
+func configChanges(ctx context.Context, poll time.Duration) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case out <- struct{}{}:
+		default: // a reload is already pending
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	path, _ := config.Path()
+	last := statConfig(path)
+	go func() {
+		defer signal.Stop(hup)
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				last = statConfig(path)
+				notify()
+			case <-ticker.C:
+				if st := statConfig(path); !st.equal(last) {
+					last = st
+					notify()
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// configStamp identifies a version of the config file; the zero value
+// means it doesn't exist.
+type configStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+func (s configStamp) equal(o configStamp) bool {
+	return s.size == o.size && s.modTime.Equal(o.modTime)
+}
+
+func statConfig(path string) configStamp {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return configStamp{}
+	}
+	return configStamp{fi.Size(), fi.ModTime()}
+}
+
+// reloadConfig rereads the config for the long-running command name,
+// checking it with check. On failure the error is logged and nil returned,
+// and the command carries on with its old config.
+func reloadConfig(name string, check func(*config.Config) error) *config.Config {
+	cfg, err := readConfig(check)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pylon: %s: keeping the old config: %v\n", name, err)
+		return nil
+	}
+	path, _ := config.Path()
+	fmt.Fprintf(os.Stderr, "pylon: %s: reloaded config from %s\n", name, path)
+	return cfg
+}
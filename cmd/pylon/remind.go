@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/links"
+	"github.com/jredh-dev/pylon/internal/recur"
+	"github.com/jredh-dev/pylon/internal/remind"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runRemind polls cal feeds and posts Discord reminders before events start
+// and when deadlines pass. Sent reminders are recorded in the state directory
+// so restarts don't re-notify.
+func runRemind(args []string) {
+	cfg := loadConfig()
+
+	fs := parseFlags(args, "remind")
+	fs.noArgs()
+	feeds := fs.Strings("feed")
+	var channelID string
+	if v := fs.String("channel", ""); v != "" {
+		channelID = cfg.Channel(v)
+	}
+	to := fs.String("to", "discord")
+	before := fs.Duration("before", 15*time.Minute)
+	interval := fs.Duration("interval", time.Minute)
+	once := fs.Bool("once")
+	thread := remind.ThreadRule{Category: fs.String("thread-category", ""), ArchiveAfter: fs.Duration("thread-archive-after", time.Hour)}
+	threadBefore := fs.String("thread-before", "")
+	followUpTemplate := fs.String("follow-up-template", "")
+	followUp := fs.Bool("follow-up") || fs.Has("follow-up-template")
+	template := fs.String("template", "")
+	metricsAddr := fs.String("metrics-addr", "")
+	lockOpts := lockFlagsFrom(fs)
+	if len(feeds) == 0 {
+		fatal("usage: pylon remind --feed <id> [--before 30m] [--to discord] [--channel <id>] [--interval 1m] [--once] [--thread-category <name>]")
+	}
+	feeds = resolveFeeds(newCalClient(cfg, cfg.CalURL), feeds)
+	if to != "discord" {
+		fatal("unsupported --to %q (only discord is supported)", to)
+	}
+
+	var threads *remind.ThreadRule
+	threadChannel := channelID
+	if thread.Category != "" {
+		thread.Before = before
+		if threadBefore != "" {
+			thread.Before = parsePositiveDuration("thread-before", threadBefore)
+		}
+		if threadChannel == "" {
+			threadChannel = cfg.DiscordChannelID
+		}
+		if threadChannel == "" {
+			fatal("--thread-category needs --channel (or a default channel_id) to create threads in")
+		}
+		threads = &thread
+	}
+
+	// One reminder loop at a time, as they share the store.
+	acquireLock("remind", "remind", lockOpts)
+	dir, err := config.StateDir()
+	if err != nil {
+		fatal("remind: %v", err)
+	}
+	store, err := remind.LoadStore(filepath.Join(dir, "remind.json"))
+	if err != nil {
+		fatal("remind: %v", err)
+	}
+	if !once {
+		serveMetrics(metricsAddr)
+	}
+
+	r := &reminder{
+		cal:       newCalClient(cfg, cfg.CalURL),
+		discord:   newDiscordClient(cfg),
+		channelID: channelID,
+		feeds:     feeds,
+		before:    before,
+		// A deadline must be caught by at least one poll.
+		grace:    2 * interval,
+		store:    store,
+		template: template,
+		links:    newLinkRewriter(cfg),
+
+		threads:  threads,
+		threadIn: threadChannel,
+
+		followUp:         followUp,
+		followUpTemplate: followUpTemplate,
+		// Late prompts are still useful, so allow more slack than deadlines.
+		followUpWindow: max(2*interval, 30*time.Minute),
+	}
+
+	if once {
+		if err := r.poll(time.Now()); err != nil {
+			fatal("remind: %v", err)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	wd := newWatchdog(cfg)
+	fmt.Fprintf(os.Stderr, "pylon: reminding %s before events in %d feed(s), polling every %s\n", before, len(feeds), interval)
+	job := func() {
+		// A failed poll is logged and retried next tick; the loop only
+		// exits on a signal. The watchdog reports a run of failures.
+		err := runJob(wd, "remind", func() error { return r.poll(time.Now()) })
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: remind: %v\n", err)
+		}
+	}
+	reload := func() {
+		// Flags keep their values; credentials, sinks and links follow
+		// the file.
+		if next := reloadConfig("remind", nil); next != nil {
+			r.cal, r.discord, r.links = newCalClient(next, next.CalURL), newDiscordClient(next), newLinkRewriter(next)
+			wd = newWatchdog(next)
+		}
+	}
+	runLoop(ctx, interval, job, reload)
+}
+
+// reminder holds the state of a running reminder loop.
+type reminder struct {
+	cal       *cal.Client
+	discord   *discord.Client
+	channelID string
+	feeds     []string
+	before    time.Duration
+	grace     time.Duration
+	store     *remind.Store
+	template  string          // custom reminder text, "" for the default
+	links     *links.Rewriter // tags and shortens announced event URLs
+
+	threads  *remind.ThreadRule // nil unless --thread-category is set
+	threadIn string             // channel that event threads are created in
+
+	followUp         bool
+	followUpTemplate string
+	followUpWindow   time.Duration
+}
+
+// poll fetches events, sends any due reminders that weren't sent yet, and
+// persists the store.
+func (r *reminder) poll(now time.Time) error {
+	var events []cal.Event
+	for _, id := range r.feeds {
+		evs, err := r.cal.ListEvents(id)
+		if err != nil {
+			return fmt.Errorf("list events for %s: %w", id, err)
+		}
+		events = append(events, evs...)
+	}
+	// Look back far enough for follow-ups and late deadlines, and ahead for
+	// reminders and threads.
+	lead := r.before
+	if r.threads != nil {
+		lead = max(lead, r.threads.Before)
+	}
+	events = recur.ExpandAll(events, now.Add(-7*24*time.Hour), now.Add(lead+24*time.Hour))
+
+	var sendErr error
+	for _, n := range remind.Due(events, now, r.before, r.grace) {
+		if r.store.Seen(n.Key) {
+			continue
+		}
+		n.Event = r.announce(n.Event)
+		if err := r.send(n.Render(r.template, now, time.Local)); err != nil {
+			// Leave it unmarked so the next poll retries.
+			sendErr = fmt.Errorf("send reminder for %q: %w", n.Event.Summary, err)
+			continue
+		}
+		notificationsSent.Inc("reminder")
+		r.store.Mark(n.Key, now)
+	}
+	if r.followUp {
+		if err := r.sendFollowUps(events, now); err != nil && sendErr == nil {
+			sendErr = err
+		}
+	}
+	if r.threads != nil {
+		if err := r.syncThreads(events, now); err != nil && sendErr == nil {
+			sendErr = err
+		}
+	}
+
+	// Keys only matter until their event is over; keep a week for safety.
+	r.store.Prune(now.Add(-7 * 24 * time.Hour))
+	if err := r.store.Save(); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+	return sendErr
+}
+
+// sendFollowUps posts the follow-up prompt for events that just ended, into
+// the event's thread when it has one. Prompts sent through the bot are
+// recorded so their replies can be collected later.
+func (r *reminder) sendFollowUps(events []cal.Event, now time.Time) error {
+	category := ""
+	if r.threads != nil {
+		category = r.threads.Category
+	}
+	var firstErr error
+	for _, n := range remind.FollowUps(events, now, r.followUpWindow, category) {
+		if r.store.Seen(n.Key) {
+			continue
+		}
+		n.Event = r.announce(n.Event)
+		msg := remind.FollowUpMessage(r.followUpTemplate, n, time.Local)
+		channelID := r.channelID
+		if t, ok := r.store.Thread(remind.EventKey(n.Event)); ok {
+			channelID = t.ID
+		}
+
+		var err error
+		if channelID == "" {
+			err = r.discord.SendMessage(msg)
+		} else {
+			var posted *discord.Message
+			posted, err = r.discord.SendChannelMessage(channelID, msg, "")
+			if err == nil {
+				r.store.SetPrompt(remind.EventKey(n.Event), remind.Prompt{ChannelID: channelID, MessageID: posted.ID, Summary: n.Event.Summary, PostedAt: now})
+			}
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("send follow-up for %q: %w", n.Event.Summary, err)
+			}
+			continue
+		}
+		notificationsSent.Inc("follow_up")
+		r.store.Mark(n.Key, now)
+	}
+	return firstErr
+}
+
+// syncThreads opens threads for upcoming events in the thread category,
+// posting the agenda into each, and archives threads whose event is over.
+func (r *reminder) syncThreads(events []cal.Event, now time.Time) error {
+	var firstErr error
+	record := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	// Follow reschedules so threads archive after the event's current end.
+	for _, e := range events {
+		if t, ok := r.store.Thread(remind.EventKey(e)); ok && !t.Archived {
+			t.ArchiveAt = r.threads.ArchiveAt(e)
+			r.store.SetThread(remind.EventKey(e), t)
+		}
+	}
+
+	for _, e := range r.threads.ThreadsToOpen(events, now, r.store) {
+		archiveAt := r.threads.ArchiveAt(e)
+		th, err := r.discord.CreateThread(r.threadIn, remind.ThreadName(e), archiveAt.Sub(now))
+		if err != nil {
+			record(fmt.Errorf("create thread for %q: %w", e.Summary, err))
+			continue
+		}
+		// Record the thread before posting so a failed post never leads to
+		// a second thread.
+		r.store.SetThread(remind.EventKey(e), remind.Thread{ID: th.ID, ChannelID: r.threadIn, Name: th.Name, ArchiveAt: archiveAt})
+		if _, err := r.discord.SendChannelMessage(th.ID, remind.Agenda(r.announce(e), time.Local), ""); err != nil {
+			record(fmt.Errorf("post agenda for %q: %w", e.Summary, err))
+		} else {
+			notificationsSent.Inc("agenda")
+		}
+	}
+
+	for _, id := range r.store.ThreadsToArchive(now) {
+		t, _ := r.store.Thread(id)
+		if err := r.discord.ArchiveThread(t.ID); err != nil {
+			record(fmt.Errorf("archive thread %q: %w", t.Name, err))
+			continue
+		}
+		t.Archived = true
+		r.store.SetThread(id, t)
+	}
+	return firstErr
+}
+
+// announce returns e with its URL tagged and shortened for posting. A
+// shortener failure is logged and the long URL used, so the announcement
+// still goes out.
+func (r *reminder) announce(e cal.Event) cal.Event {
+	url, err := r.links.Rewrite(e.URL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pylon: %s: %v\n", e.Summary, err)
+	}
+	e.URL = url
+	return e
+}
+
+func (r *reminder) send(msg string) error {
+	if r.channelID != "" {
+		_, err := r.discord.SendChannelMessage(r.channelID, msg, "")
+		return err
+	}
+	return r.discord.SendMessage(msg)
+}
+
+// newLinkRewriter builds the rewriter for announced event URLs from the
+// [announce] settings.
+func newLinkRewriter(cfg *config.Config) *links.Rewriter {
+	lr := &links.Rewriter{UTM: links.UTM{
+		Source:   cfg.AnnounceUTMSource,
+		Medium:   cfg.AnnounceUTMMedium,
+		Campaign: cfg.AnnounceUTMCampaign,
+	}}
+	if cfg.AnnounceShortener != "" {
+		lr.Shortener = &links.Shortener{Endpoint: cfg.AnnounceShortener, Client: apiHTTPClient()}
+	}
+	return lr
+}
+
+// parsePositiveDuration parses a flag value with timeutil.ParseDuration and
+// exits unless it is positive.
+func parsePositiveDuration(flag, v string) time.Duration {
+	d, err := timeutil.ParseDuration(v)
+	if err != nil || d <= 0 {
+		fatal("invalid --%s %q: want a duration like 30m or 2h", flag, v)
+	}
+	return d
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/resolve"
+)
+
+// Feeds and events can be given by a prefix of their ID, and feeds by
+// name, as long as it picks out one; see the resolve package for the rules.
+// Commands that delete or overwrite feeds use the Strict variants, which
+// don't match part of a name.
+
+// filterFeeds returns the feed of feeds that ref refers to, as a list of
+// one, or none if nothing matches. A ref matching several feeds is fatal.
+func filterFeeds(feeds []cal.Feed, ref string) []cal.Feed {
+	return findFeeds(feeds, ref, resolve.Resolve)
+}
+
+// filterFeedsStrict is filterFeeds by exact ID, exact name or ID prefix.
+func filterFeedsStrict(feeds []cal.Feed, ref string) []cal.Feed {
+	return findFeeds(feeds, ref, resolve.Strict)
+}
+
+func findFeeds(feeds []cal.Feed, ref string, resolveRef func(kind, ref string, items []resolve.Item) (string, error)) []cal.Feed {
+	items := make([]resolve.Item, len(feeds))
+	for i, f := range feeds {
+		items[i] = resolve.Item{ID: f.ID, Name: f.Name}
+	}
+	id, err := resolveRef("feed", ref, items)
+	var ambiguous *resolve.AmbiguousError
+	if errors.As(err, &ambiguous) {
+		fatal("%v", err)
+	}
+	for _, f := range feeds {
+		if f.ID == id {
+			return []cal.Feed{f}
+		}
+	}
+	return nil
+}
+
+// matchFeeds returns the IDs of the feeds refs refer to, exiting if one
+// matches none or several. Empty refs are kept.
+func matchFeeds(feeds []cal.Feed, refs []string) []string {
+	return matchFeedsWith(feeds, refs, filterFeeds)
+}
+
+// matchFeedsStrict is matchFeeds by exact ID, exact name or ID prefix.
+func matchFeedsStrict(feeds []cal.Feed, refs []string) []string {
+	return matchFeedsWith(feeds, refs, filterFeedsStrict)
+}
+
+func matchFeedsWith(feeds []cal.Feed, refs []string, filter func([]cal.Feed, string) []cal.Feed) []string {
+	out := make([]string, len(refs))
+	for i, ref := range refs {
+		if ref == "" {
+			continue
+		}
+		f := filter(feeds, ref)
+		if len(f) == 0 {
+			fatal("feed not found: %s", ref)
+		}
+		out[i] = f[0].ID
+	}
+	return out
+}
+
+// resolveFeed returns the ID of the feed ref refers to, exiting if it
+// matches none or several. An empty ref is returned as is.
+func resolveFeed(client *cal.Client, ref string) string {
+	return resolveFeeds(client, []string{ref})[0]
+}
+
+// resolveFeedStrict is resolveFeed by exact ID, exact name or ID prefix.
+func resolveFeedStrict(client *cal.Client, ref string) string {
+	return resolveFeedsWith(client, []string{ref}, matchFeedsStrict)[0]
+}
+
+// resolveFeeds is resolveFeed for several references. Feeds are only
+// listed if one of refs isn't a full ID.
+func resolveFeeds(client *cal.Client, refs []string) []string {
+	return resolveFeedsWith(client, refs, matchFeeds)
+}
+
+func resolveFeedsWith(client *cal.Client, refs []string, match func([]cal.Feed, []string) []string) []string {
+	if !slices.ContainsFunc(refs, func(ref string) bool { return ref != "" && !resolve.IsFullID(ref) }) {
+		return refs
+	}
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("list feeds: %v", err)
+	}
+	return match(feeds, refs)
+}
+
+// getEvent fetches the event ref refers to: its ID or an unambiguous
+// prefix of it. A prefix is looked for across every feed, as is any ID on
+// servers that can't fetch a single event.
+func getEvent(client *cal.Client, ref string) (*cal.Event, error) {
+	e, err := client.GetEvent(ref)
+	var apiErr *cal.APIError
+	notFound := errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+	if errors.Is(err, cal.ErrNotSupported) || notFound && !resolve.IsFullID(ref) {
+		return findEvent(client, ref)
+	}
+	return e, err
+}
+
+// resolveEvent returns the ID of the event ref refers to, exiting if it
+// can't be found.
+func resolveEvent(client *cal.Client, ref string) string {
+	if resolve.IsFullID(ref) {
+		return ref
+	}
+	e, err := getEvent(client, ref)
+	if err != nil {
+		fatal("%v", err)
+	}
+	return e.ID
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/retention"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runRetention enforces the [retention] policy: old events are archived and
+// then deleted, and old Discord export files are removed. It runs until
+// interrupted, enforcing the policy every --interval, or once with --once.
+func runRetention(args []string) {
+	cfg := loadConfig()
+
+	fs := parseFlags(args, "retention")
+	fs.noArgs()
+	interval := fs.Duration("interval", 24*time.Hour)
+	once, dryRun := fs.Bool("once"), fs.Bool("dry-run")
+	metricsAddr := fs.String("metrics-addr", "")
+	lockOpts := lockFlagsFrom(fs)
+	if err := checkRetention(cfg); err != nil {
+		fatal("retention: %v", err)
+	}
+
+	if !dryRun {
+		acquireLock("retention", "retention", lockOpts)
+	}
+	if !once && !dryRun {
+		serveMetrics(metricsAddr)
+	}
+	client := newCalClient(cfg, cfg.CalURL)
+	if once || dryRun {
+		if err := enforceRetention(cfg, client, time.Now(), dryRun); err != nil {
+			fatal("retention: %v", err)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	wd := newWatchdog(cfg)
+	fmt.Fprintf(os.Stderr, "pylon: enforcing the retention policy every %s\n", interval)
+	job := func() {
+		// A failed run is logged and retried next tick; the loop only exits
+		// on a signal. The watchdog reports a run of failures.
+		err := runJob(wd, "retention", func() error {
+			return enforceRetention(cfg, client, time.Now(), false)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pylon: retention: %v\n", err)
+		}
+	}
+	reload := func() {
+		if next := reloadConfig("retention", checkRetention); next != nil {
+			cfg, client, wd = next, newCalClient(next, next.CalURL), newWatchdog(next)
+		}
+	}
+	runLoop(ctx, interval, job, reload)
+}
+
+// checkRetention checks that cfg has a complete retention policy.
+func checkRetention(cfg *config.Config) error {
+	if cfg.RetentionEvents == "" && cfg.RetentionExportKeep == "" {
+		return errors.New("no policy: set retention.events_older_than and/or retention.discord_export_keep")
+	}
+	if cfg.RetentionEvents != "" && cfg.RetentionArchiveDir == "" {
+		return errors.New("events_older_than needs retention.archive_dir to archive events to before deleting them")
+	}
+	if cfg.RetentionExportKeep != "" && cfg.RetentionExportDir == "" {
+		return errors.New("discord_export_keep needs retention.export_dir, the directory exports are written to")
+	}
+	if cfg.RetentionArchiveDir != "" && cfg.RetentionExportDir != "" {
+		// Rotating exports would delete the event archives, which are JSON too.
+		overlap, err := retention.Overlap(cfg.RetentionArchiveDir, cfg.RetentionExportDir)
+		if err != nil {
+			return err
+		}
+		if overlap {
+			return errors.New("retention.archive_dir and retention.export_dir must be separate directories, not one inside the other")
+		}
+	}
+	return nil
+}
+
+// enforceRetention applies both halves of the policy once. Events are only
+// deleted after their archive is written, as with cal archive. Both halves
+// run even if the first fails.
+func enforceRetention(cfg *config.Config, client *cal.Client, now time.Time, dryRun bool) error {
+	var errs []error
+	if cfg.RetentionEvents != "" {
+		errs = append(errs, retainEvents(cfg, client, now, dryRun))
+	}
+	if cfg.RetentionExportKeep != "" {
+		errs = append(errs, rotateExports(cfg, now, dryRun))
+	}
+	return errors.Join(errs...)
+}
+
+func retainEvents(cfg *config.Config, client *cal.Client, now time.Time, dryRun bool) error {
+	cutoff, err := timeutil.ParseCutoff(cfg.RetentionEvents, now, time.Local)
+	if err != nil {
+		return err
+	}
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		return fmt.Errorf("list feeds: %w", err)
+	}
+	archived, failed, err := archiveEvents(client, feeds, cutoff, cfg.RetentionArchiveDir, dryRun, now)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Println(i18n.T("archive.dry_run", archived, cutoff.Format(time.RFC3339)))
+		return nil
+	}
+	if archived > 0 || failed > 0 {
+		fmt.Println(i18n.T("archive.summary", archived, failed))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d event(s) could not be deleted", failed)
+	}
+	return nil
+}
+
+func rotateExports(cfg *config.Config, now time.Time, dryRun bool) error {
+	cutoff, err := timeutil.ParseCutoff(cfg.RetentionExportKeep, now, time.Local)
+	if err != nil {
+		return err
+	}
+	stale, err := retention.StaleFiles(cfg.RetentionExportDir, discord.ExportFormats, cutoff)
+	if err != nil {
+		return fmt.Errorf("exports: %w", err)
+	}
+	var errs []error
+	for _, path := range stale {
+		if dryRun {
+			fmt.Println(i18n.T("retention.would", path))
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Println(i18n.T("retention.remove", path))
+	}
+	return errors.Join(errs...)
+}
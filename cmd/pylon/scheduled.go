@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/bridge"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runDiscordEvents handles `pylon discord events`, a guild's native
+// scheduled events.
+func runDiscordEvents(cfg *config.Config, client *discord.Client, args []string) {
+	if len(args) < 1 {
+		usageFor("discord", "events")
+		fail()
+	}
+	switch args[0] {
+	case "list", "ls":
+		fs := parseFlags(args[1:], "discord", "events", "list")
+		fs.noArgs()
+		guildID := fs.String("guild", cfg.DiscordGuildID)
+		format := fs.String("output", "text")
+		if guildID == "" {
+			fatal("usage: pylon discord events list --guild <id>")
+		}
+		checkListFormat("discord events", format)
+		events, err := client.ScheduledEvents(guildID)
+		if err != nil {
+			fatal("discord events: %v", err)
+		}
+		if format == "csv" {
+			rows := make([][]string, len(events))
+			for i, e := range events {
+				end := ""
+				if e.End != nil {
+					end = e.End.Format(time.RFC3339)
+				}
+				rows[i] = []string{e.ID, e.Name, e.Start.Format(time.RFC3339), end, eventStatus(e.Status), eventPlace(e), e.URL()}
+			}
+			writeCSV("discord events", []string{"id", "name", "start", "end", "status", "where", "url"}, rows)
+			return
+		}
+		if len(events) == 0 {
+			fmt.Println(i18n.T("scheduled.none"))
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "ID\tSTART\tSTATUS\tNAME\tWHERE\n")
+		for _, e := range events {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+				e.ID, e.Start.Local().Format("2006-01-02 15:04"), eventStatus(e.Status), e.Name, eventPlace(e))
+		}
+		_ = tw.Flush()
+
+	case "create", "add":
+		fs := parseFlags(args[1:], "discord", "events", "create")
+		guildID := fs.String("guild", cfg.DiscordGuildID)
+		p := &discord.EventParams{
+			Name:        strings.Join(fs.args, " "),
+			Description: fs.String("description", ""),
+			Location:    fs.String("location", ""),
+		}
+		if ch := fs.String("channel", ""); ch != "" {
+			p.ChannelID = cfg.Channel(ch)
+		}
+		if guildID == "" || p.Name == "" || !fs.Has("start") {
+			fatal("usage: pylon discord events create --guild <id> --start <time> [--end <time> | --duration <d>] [--location <place> | --channel <voice-id>] <name>")
+		}
+		if p.ChannelID != "" && p.Location != "" {
+			fatal("use either --location or --channel, not both")
+		}
+		p.Start = parseEventTime("start", fs.String("start", ""))
+		switch {
+		case fs.Has("end") && fs.Has("duration"):
+			fatal("use either --end or --duration, not both")
+		case fs.Has("end"):
+			end := parseEventTime("end", fs.String("end", ""))
+			p.End = &end
+		case fs.Has("duration"):
+			end := p.Start.Add(fs.Duration("duration", 0))
+			p.End = &end
+		}
+		if p.End != nil && !p.End.After(p.Start) {
+			fatal("the event must end after it starts")
+		}
+		e, err := client.CreateScheduledEvent(guildID, p)
+		if err != nil {
+			fatal("discord events create: %v", err)
+		}
+		fmt.Println(i18n.T("scheduled.created", e.Name, e.URL()))
+
+	default:
+		unknownCommand(args[0], "discord", "events")
+	}
+}
+
+// parseEventTime parses the value of a time flag: RFC 3339, or a local
+// date and time like 2026-03-06 19:00.
+func parseEventTime(flag, v string) time.Time {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t
+		}
+	}
+	fatal("invalid --%s %q: want a time like 2026-03-06 19:00 or 2026-03-06T19:00:00Z", flag, v)
+	return time.Time{}
+}
+
+// eventStatus names a scheduled event status.
+func eventStatus(s int) string {
+	switch s {
+	case discord.EventScheduled:
+		return "scheduled"
+	case discord.EventActive:
+		return "active"
+	case discord.EventCompleted:
+		return "completed"
+	case discord.EventCanceled:
+		return "canceled"
+	}
+	return fmt.Sprint(s)
+}
+
+// eventPlace returns where a scheduled event takes place: its location, or
+// its channel.
+func eventPlace(e discord.ScheduledEvent) string {
+	if loc := e.Location(); loc != "" {
+		return loc
+	}
+	if e.ChannelID != "" {
+		return "<#" + e.ChannelID + ">"
+	}
+	return ""
+}
+
+// runBridgeDiscordEvents mirrors a feed's upcoming events into a guild's
+// scheduled events, planning the changes and, with --apply, making them.
+func runBridgeDiscordEvents(args []string) {
+	const usage = "usage: pylon bridge discord-events --feed <id> --guild <id> [--channel <voice-id>] [--days <n>] [--plan | --apply]"
+	cfg := loadConfig()
+	fs := parseFlags(args, "bridge", "discord-events")
+	guildID, feedID, channelID := fs.String("guild", cfg.DiscordGuildID), fs.String("feed", cfg.CalDefaultFeed), ""
+	if v := fs.String("channel", ""); v != "" {
+		channelID = cfg.Channel(v)
+	}
+	days := fs.Int("days", 30)
+	planOnly, apply := fs.Bool("plan"), fs.Bool("apply")
+	lockOpts := lockFlagsFrom(fs)
+	if len(fs.args) > 0 || guildID == "" || feedID == "" {
+		fatal(usage)
+	}
+	if planOnly && apply {
+		fatal("use either --plan or --apply, not both")
+	}
+	client := newCalClient(cfg, cfg.CalURL)
+	feedID = resolveFeed(client, feedID)
+
+	if apply {
+		acquireLock("discord-events-"+guildID, "bridge discord-events", lockOpts)
+	}
+
+	events, err := client.ListEvents(feedID)
+	if err != nil {
+		fatal("list events: %v", err)
+	}
+	dc := newDiscordClient(cfg)
+	existing, err := dc.ScheduledEvents(guildID)
+	if err != nil {
+		fatal("list scheduled events: %v", err)
+	}
+
+	// Discord refuses events that start in the past.
+	from := time.Now().Add(time.Minute)
+	p := bridge.ExportDiscordEvents(dc, guildID, channelID, events, existing, from, from.AddDate(0, 0, days))
+	if !apply {
+		showPlan(p)
+		return
+	}
+	applyImport(p, "bridge.exported")
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// runCalSearch finds events across feeds by text in their summary,
+// description or location. The server searches when it can; older servers
+// have every feed listed and filtered here.
+func runCalSearch(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "search")
+	q := cal.SearchQuery{FeedIDs: fs.Strings("feed"), Category: fs.String("category", "")}
+	from, to := fs.String("from", ""), fs.String("to", "")
+	q.Text = strings.Join(fs.args, " ")
+	if q.Text == "" && q.Category == "" {
+		fatal("usage: pylon cal search <query> [--feed <id>] [--category <name>] [--from <date>] [--to <date>]")
+	}
+
+	now := time.Now()
+	var err error
+	if from != "" {
+		if q.From, err = timeutil.ParseCutoff(from, now, time.Local); err != nil {
+			fatal("search: %v", err)
+		}
+	}
+	if to != "" {
+		if q.To, err = timeutil.ParseCutoff(to, now, time.Local); err != nil {
+			fatal("search: %v", err)
+		}
+	}
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		fatal("list feeds: %v", err)
+	}
+	names := map[string]string{}
+	for _, f := range feeds {
+		names[f.ID] = f.Name
+	}
+	q.FeedIDs = matchFeeds(feeds, q.FeedIDs)
+
+	events, err := client.SearchEvents(&q)
+	if errors.Is(err, cal.ErrNotSupported) {
+		events, err = searchFeeds(client, feeds, &q)
+	}
+	if err != nil {
+		fatal("search: %v", err)
+	}
+	if len(events) == 0 {
+		fmt.Println(i18n.T("search.none", q.Text))
+		return
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "START\tFEED\tSUMMARY\tID\n")
+	for _, e := range events {
+		start := e.Start.In(time.Local).Format("2006-01-02 15:04")
+		if e.AllDay {
+			start = e.Start.Format(time.DateOnly)
+		}
+		feed := names[e.FeedID]
+		if feed == "" {
+			feed = e.FeedID
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", start, feed, e.Summary, e.ID)
+	}
+	_ = tw.Flush()
+}
+
+// searchFeeds lists the events of every feed q covers and keeps the
+// matches.
+func searchFeeds(client *cal.Client, feeds []cal.Feed, q *cal.SearchQuery) ([]cal.Event, error) {
+	var out []cal.Event
+	for _, f := range feeds {
+		if len(q.FeedIDs) > 0 && !slices.Contains(q.FeedIDs, f.ID) {
+			continue
+		}
+		events, err := client.ListEvents(f.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list events for %s: %w", f.ID, err)
+		}
+		for _, e := range events {
+			if q.Matches(&e) {
+				out = append(out, e)
+			}
+		}
+	}
+	return out, nil
+}
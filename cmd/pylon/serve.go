@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/calserver"
+	"github.com/jredh-dev/pylon/internal/config"
+)
+
+// runCalServe runs the embedded cal service until interrupted, keeping its
+// feeds and events in a JSON file in the state directory (or --data).
+func runCalServe(args []string) {
+	fs := parseFlags(args, "cal", "serve")
+	fs.noArgs()
+	port := 8085
+	if v := fs.String("port", ""); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 65535 {
+			fatal("invalid --port %q", v)
+		}
+		port = n
+	}
+	host := fs.String("host", "127.0.0.1")
+	data, baseURL := fs.String("data", ""), fs.String("base-url", "")
+	loc := time.Local
+	if v := fs.String("timezone", ""); v != "" {
+		l, err := time.LoadLocation(v)
+		if err != nil {
+			fatal("invalid --timezone %q: %v", v, err)
+		}
+		loc = l
+	}
+	memory, skipCancelled := fs.Bool("memory"), fs.Bool("skip-cancelled")
+	if memory && data != "" {
+		fatal("--memory and --data are mutually exclusive")
+	}
+	if !memory && data == "" {
+		dir, err := config.StateDir()
+		if err != nil {
+			fatal("serve: %v", err)
+		}
+		data = filepath.Join(dir, "cal-serve.json")
+	}
+
+	store, err := calserver.OpenStore(data)
+	if err != nil {
+		fatal("serve: %v", err)
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		fatal("serve: %v", err)
+	}
+	handler := calserver.New(store)
+	handler.Version = version
+	handler.SkipCancelled = skipCancelled
+	handler.Location = loc
+	handler.BaseURL = baseURL
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	where := data
+	if memory {
+		where = "memory only"
+	}
+	fmt.Fprintf(os.Stderr, "pylon: serving the cal API on http://%s (%s)\n", ln.Addr(), where)
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal("serve: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/slack"
+)
+
+// newSlackClient builds a Slack client with the configured credentials
+// and HTTP options.
+func newSlackClient(cfg *config.Config) *slack.Client {
+	opts := []slack.Option{slack.WithRetries(cfg.HTTPRetries)}
+	if hc := apiHTTPClient(); hc != nil {
+		opts = append(opts, slack.WithHTTPClient(hc))
+	}
+	if cfg.ReadOnly {
+		opts = append(opts, slack.WithReadOnly())
+	}
+	return slack.NewClient(cfg.SlackBotToken, cfg.SlackWebhook, opts...)
+}
+
+// runSlack implements `pylon slack`, the Slack counterpart of the msg,
+// read and channels commands of `pylon discord`.
+func runSlack(args []string) {
+	cfg := loadConfig()
+	client := newSlackClient(cfg)
+
+	switch args[0] {
+	case "msg", "send":
+		fs := parseFlags(args[1:], "slack", "msg")
+		channelID := fs.String("channel", "")
+		threadTS := fs.String("thread", "")
+		words := fs.args
+		if len(words) == 0 {
+			fatal("usage: pylon slack msg [--channel <id>] [--thread <ts>] <message>")
+		}
+		message := strings.Join(words, " ")
+
+		// Threads need the bot, and so does a channel other than the
+		// webhook's; fall back to the default channel for a thread.
+		if threadTS != "" && channelID == "" {
+			channelID = cfg.SlackChannelID
+			if channelID == "" {
+				fatal("--thread needs --channel (or a default channel_id)")
+			}
+		}
+		if channelID == "" {
+			if err := client.SendMessage(message); err != nil {
+				fatal("slack msg: %v", err)
+			}
+			fmt.Println(i18n.T("message.sent"))
+			return
+		}
+		ts, err := client.SendChannelMessage(channelID, message, threadTS)
+		if err != nil {
+			fatal("slack msg: %v", err)
+		}
+		fmt.Println(i18n.T("message.sent_id", ts))
+
+	case "read":
+		fs := parseFlags(args[1:], "slack", "read")
+		fs.noArgs()
+		channelID := fs.String("channel", cfg.SlackChannelID)
+		count := fs.Int("count", 20)
+		raw := fs.Bool("raw")
+		if channelID == "" {
+			fatal("channel ID required\nUsage: pylon slack read [--channel <id>] [--count N] [--raw]\nOr set channel_id in ~/.pylonrc [slack] or PYLON_SLACK_CHANNEL_ID")
+		}
+		msgs, err := client.ReadMessages(channelID, count)
+		if err != nil {
+			fatal("slack read: %v", err)
+		}
+		if len(msgs) == 0 {
+			fmt.Println(i18n.T("message.none"))
+			return
+		}
+		var names map[string]string
+		if !raw {
+			names = client.UserNames(slack.Authors(msgs))
+		}
+		fmt.Print(slack.Format(msgs, names))
+
+	case "channels":
+		fs := parseFlags(args[1:], "slack", "channels")
+		fs.noArgs()
+		format := fs.String("output", "text")
+		archived := fs.Bool("archived")
+		checkListFormat("slack channels", format)
+		channels, err := client.ListChannels(archived)
+		if err != nil {
+			fatal("slack channels: %v", err)
+		}
+		if format == "csv" {
+			rows := make([][]string, len(channels))
+			for i, ch := range channels {
+				rows[i] = []string{ch.ID, ch.Name, strconv.FormatBool(ch.IsPrivate), strconv.Itoa(ch.NumMembers), ch.Topic.Value}
+			}
+			writeCSV("slack channels", []string{"id", "name", "private", "members", "topic"}, rows)
+			return
+		}
+		if len(channels) == 0 {
+			fmt.Println(i18n.T("slack.no_channels"))
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "ID\tNAME\tMEMBERS\tTOPIC\n")
+		for _, ch := range channels {
+			name := "#" + ch.Name
+			if ch.IsPrivate {
+				name += " (private)"
+			}
+			if ch.ID == cfg.SlackChannelID {
+				name += " (default)"
+			}
+			topic := cmp.Or(ch.Topic.Value, "-")
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", ch.ID, name, ch.NumMembers, topic)
+		}
+		_ = tw.Flush()
+
+	default:
+		unknownCommand(args[0], "slack")
+	}
+}
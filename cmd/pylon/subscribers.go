@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/agenda"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalSubscribers prints fetch statistics for a feed's subscription URLs.
+func runCalSubscribers(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "subscribers")
+	feedID := fs.String("feed", "")
+	if len(fs.args) > 1 || (len(fs.args) == 1 && feedID != "") {
+		fatal("usage: pylon cal subscribers --feed <feed-id> [--agents]")
+	}
+	if len(fs.args) == 1 {
+		feedID = fs.args[0]
+	}
+	agents := fs.Bool("agents")
+	if feedID == "" {
+		fatal("usage: pylon cal subscribers --feed <feed-id> [--agents]")
+	}
+	feedID = resolveFeed(client, feedID)
+
+	stats, err := client.Subscribers(feedID)
+	if errors.Is(err, cal.ErrNotSupported) {
+		fatal("this cal server does not track subscriptions")
+	}
+	if err != nil {
+		fatal("subscribers: %v", err)
+	}
+	if len(stats.Tokens) == 0 {
+		fmt.Println(i18n.T("subscribers.none"))
+		return
+	}
+
+	now := time.Now()
+	last := func(t *time.Time) string {
+		if t == nil {
+			return "never"
+		}
+		return t.Local().Format("2006-01-02 15:04") + " (" + agenda.Relative(*t, now) + ")"
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "TOKEN\tFETCHES\tAGENTS\tLAST FETCH\n")
+	for _, t := range stats.Tokens {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", t.Token, t.Fetches, len(t.UserAgents), last(t.LastFetch))
+		if agents {
+			for _, a := range t.UserAgents {
+				_, _ = fmt.Fprintf(tw, "  %s\t%d\t\t%s\n", a.UserAgent, a.Fetches, last(a.LastFetch))
+			}
+		}
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jredh-dev/pylon/internal/suggest"
+)
+
+// unknownCommand reports an unknown subcommand of the command at path,
+// suggests close matches from the help tree, prints that command's usage and
+// exits.
+func unknownCommand(got string, path ...string) {
+	label := "unknown command"
+	if len(path) > 0 {
+		label = "unknown " + path[len(path)-1] + " command"
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s\n", label, got)
+
+	cmds := lookup(path)
+	parent := cmds[len(cmds)-1]
+	var names []string
+	for _, sub := range parent.Subcommands {
+		names = append(names, sub.Name)
+		names = append(names, sub.Aliases...)
+	}
+	// Report each command once, by its primary name, even when both the
+	// name and an alias are close.
+	var matches []string
+	seen := map[string]bool{}
+	for _, m := range suggest.Closest(got, names) {
+		name := parent.find(m).Name
+		if !seen[name] {
+			seen[name] = true
+			matches = append(matches, name)
+		}
+	}
+	fmt.Fprint(os.Stderr, formatSuggestions(matches))
+	fmt.Fprintln(os.Stderr)
+	usageFor(path...)
+	fail()
+}
+
+// unknownFlag reports a flag not accepted by the command at path, suggesting
+// the closest documented flags, and exits.
+func unknownFlag(flag string, path ...string) {
+	name, _, _ := strings.Cut(flag, "=")
+	cmds := lookup(path)
+	names := []string{"--help"}
+	for _, f := range cmds[len(cmds)-1].Flags {
+		names = append(names, "--"+f.Name)
+	}
+	fmt.Fprintf(os.Stderr, "pylon: unknown flag: %s\n%s", flag, didYouMean(name, names))
+	fail()
+}
+
+// didYouMean returns a "did you mean" line for the closest candidates to
+// got, or "" if none is close enough.
+func didYouMean(got string, candidates []string) string {
+	return formatSuggestions(suggest.Closest(got, candidates))
+}
+
+func formatSuggestions(matches []string) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(matches))
+	for i, m := range matches {
+		quoted[i] = "'" + m + "'"
+	}
+	return fmt.Sprintf("did you mean %s?\n", strings.Join(quoted, " or "))
+}
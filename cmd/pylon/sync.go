@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/bridge"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/gcal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalSync brings a feed in step with a calendar kept elsewhere.
+func runCalSync(cfg *config.Config, client *cal.Client, args []string) {
+	if len(args) == 0 {
+		usageFor("cal", "sync")
+		fail()
+	}
+	switch args[0] {
+	case "gcal", "google":
+		runCalSyncGoogle(cfg, client, args[1:])
+	default:
+		unknownCommand(args[0], "cal", "sync")
+	}
+}
+
+// runCalSyncGoogle copies the upcoming events of a Google calendar into a
+// feed, planning the changes and, with --apply, making them.
+func runCalSyncGoogle(cfg *config.Config, client *cal.Client, args []string) {
+	const usage = "usage: pylon cal sync gcal --calendar <id> --feed <id> [--credentials <file>] [--days N] [--plan | --apply]"
+	fs := parseFlags(args, "cal", "sync", "gcal")
+	calendarID := fs.String("calendar", "")
+	feedID, credentials := fs.String("feed", cfg.CalDefaultFeed), fs.String("credentials", cfg.GCalCredentials)
+	days := fs.Int("days", 180)
+	planOnly, apply := fs.Bool("plan"), fs.Bool("apply")
+	lockOpts := lockFlagsFrom(fs)
+	if len(fs.args) > 0 || calendarID == "" || feedID == "" {
+		fatal(usage)
+	}
+	feedID = resolveFeed(client, feedID)
+	if credentials == "" {
+		fatal("Google credentials required: pass --credentials <file> or set gcal.credentials (PYLON_GCAL_CREDENTIALS)")
+	}
+	if planOnly && apply {
+		fatal("use either --plan or --apply, not both")
+	}
+
+	if apply {
+		// Plan and apply under the lock, so a concurrent run can't change
+		// the feed in between.
+		acquireLock("feed-"+feedID, "cal sync gcal", lockOpts)
+	}
+
+	auth, err := googleAuth(credentials)
+	if err != nil {
+		fatal("cal sync gcal: %v", err)
+	}
+	from := time.Now()
+	to := from.AddDate(0, 0, days)
+	events, err := gcal.NewClient(auth, fetchClient()).Events(calendarID, from, to)
+	if err != nil {
+		fatal("list Google Calendar events: %v", err)
+	}
+	existing, err := client.ListEvents(feedID)
+	if err != nil {
+		fatal("list events: %v", err)
+	}
+
+	p, err := bridge.ImportGoogleEvents(client, feedID, events, existing, from, to)
+	if err != nil {
+		fatal("cal sync gcal: %v", err)
+	}
+	if !apply {
+		showPlan(p)
+		return
+	}
+	applyImport(p, "gcal.imported")
+}
+
+// googleAuth returns the token source for a credentials file. A service
+// account needs nothing more; an OAuth client is authorized once through
+// the device flow, and its refresh token kept in the state directory.
+func googleAuth(path string) (gcal.TokenSource, error) {
+	creds, err := gcal.LoadCredentials(path)
+	if err != nil {
+		return nil, err
+	}
+	if sa := creds.ServiceAccount; sa != nil {
+		sa.HTTPClient = fetchClient()
+		return sa, nil
+	}
+
+	dir, err := config.StateDir()
+	if err != nil {
+		return nil, err
+	}
+	tokens := filepath.Join(dir, "gcal-tokens.json")
+	saved, err := loadRefreshTokens(tokens)
+	if err != nil {
+		return nil, err
+	}
+	clientID := creds.OAuthClient.ClientID
+	return &gcal.DeviceFlow{
+		Client:       *creds.OAuthClient,
+		HTTPClient:   fetchClient(),
+		RefreshToken: saved[clientID],
+		Prompt: func(url, code string) {
+			fmt.Fprintln(os.Stderr, i18n.T("gcal.authorize", url, code))
+		},
+		Save: func(token string) error {
+			saved[clientID] = token
+			return saveRefreshTokens(tokens, saved)
+		},
+	}, nil
+}
+
+// loadRefreshTokens reads the refresh tokens kept for each OAuth client ID.
+func loadRefreshTokens(path string) (map[string]string, error) {
+	tokens := map[string]string{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tokens, nil
+}
+
+// saveRefreshTokens writes the refresh tokens readable by the user only,
+// since they grant access to their calendars.
+func saveRefreshTokens(path string, tokens map[string]string) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/feedcheck"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runCalVerifySubscription fetches a feed's public ICS URL without
+// credentials, like a calendar app, and reports anything that would keep an
+// event off someone's phone.
+func runCalVerifySubscription(client *cal.Client, args []string) {
+	fs := parseFlags(args, "cal", "verify-subscription")
+	if len(fs.args) != 1 {
+		fatal("usage: pylon cal verify-subscription <token|url>")
+	}
+	target := fs.args[0]
+
+	url, token := target, strings.TrimSuffix(path.Base(target), ".ics")
+	if !strings.Contains(target, "://") {
+		url = client.SubscribeURL(target)
+	}
+
+	report, err := feedcheck.Fetch(fetchClient(), url)
+	if err != nil {
+		fatal("verify: %v", err)
+	}
+	fmt.Printf("URL:           %s\n", report.URL)
+	fmt.Printf("Status:        %d\n", report.StatusCode)
+	fmt.Printf("Content-Type:  %s\n", report.ContentType)
+	fmt.Printf("Size:          %d bytes\n", report.Size)
+
+	if report.StatusCode == http.StatusOK {
+		fmt.Printf("Feed events:   %d\n", len(report.Events))
+		feeds, err := client.ListFeeds()
+		if err != nil {
+			fatal("list feeds: %v", err)
+		}
+		var feed *cal.Feed
+		for i := range feeds {
+			if feeds[i].Token == token {
+				feed = &feeds[i]
+			}
+		}
+		if feed == nil {
+			fmt.Fprintf(os.Stderr, "pylon: no feed with token %q in the API listing; skipping the comparison\n", token)
+		} else {
+			events, err := client.ListEvents(feed.ID)
+			if err != nil {
+				fatal("list events for %s: %v", feed.ID, err)
+			}
+			fmt.Printf("API events:    %d (feed %s)\n", len(events), feed.Name)
+			report.Compare(events)
+		}
+	}
+
+	fmt.Println()
+	if report.OK() {
+		fmt.Println(i18n.T("verify.ok"))
+		return
+	}
+	fmt.Println(i18n.T("verify.problems", len(report.Problems)))
+	for _, p := range report.Problems {
+		fmt.Printf("  - %s\n", p)
+	}
+	exit(1)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+)
+
+// capabilityUsers names the commands that need each cal capability, for
+// the compatibility matrix.
+var capabilityUsers = map[string]string{
+	cal.CapUpsert:      "cal event add --external-id, cal import --upsert, cal sync, bridge",
+	cal.CapRotateToken: "cal feed rotate-token",
+	cal.CapUpdateFeed:  "cal feed rename",
+	cal.CapGetEvent:    "cal event show, cal event mirror, cal pin remove",
+	cal.CapSignedURL:   "cal subscribe --expires",
+	cal.CapSubscribers: "cal subscribers",
+	cal.CapSearch:      "cal search (otherwise lists every feed)",
+	cal.CapCancel:      "cal event cancel (otherwise needs an external ID and upsert)",
+	cal.CapUpdateEvent: "cal event patch (otherwise needs an external ID and upsert)",
+}
+
+// runVersion prints pylon's version and, with --check-server, what the
+// configured cal server and Discord API support.
+func runVersion(args []string) {
+	fs := parseFlags(args, "version")
+	fs.noArgs()
+	check := fs.Bool("check-server")
+	fmt.Println("pylon", version)
+	if !check {
+		return
+	}
+
+	cfg := loadConfig()
+	fmt.Printf("cal API:       v%d\n", cal.APIVersion)
+	fmt.Printf("Discord API:   v%d\n", discord.APIVersion)
+	fmt.Println()
+
+	warned := false
+	warn := func(format string, args ...any) {
+		fmt.Fprintf(os.Stderr, "pylon: warning: "+format+"\n", args...)
+		warned = true
+	}
+
+	info, err := newCalClient(cfg, cfg.CalURL).ServerInfo()
+	switch {
+	case errors.Is(err, cal.ErrNotSupported):
+		fmt.Printf("cal server:    %s (version unknown: no /api/version)\n", cfg.CalURL)
+		fmt.Println("               Commands needing newer endpoints will say so when run.")
+	case err != nil:
+		fmt.Printf("cal server:    %s (unreachable)\n", cfg.CalURL)
+		warn("cal server: %v", err)
+	default:
+		fmt.Printf("cal server:    %s (version %s, API v%d)\n", cfg.CalURL, info.Version, info.APIVersion)
+		fmt.Println()
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "FEATURE\tSERVER\tUSED BY\n")
+		for _, c := range cal.Capabilities {
+			supported := "no"
+			if info.Supports(c) {
+				supported = "yes"
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", c, supported, capabilityUsers[c])
+		}
+		_ = tw.Flush()
+		if msg := info.Compatibility(); msg != "" {
+			warn("%s", msg)
+		}
+	}
+
+	fmt.Println()
+	var apiErr *discord.APIError
+	switch err := newDiscordClient(cfg).CheckAPIVersion(); {
+	case errors.As(err, &apiErr):
+		fmt.Printf("Discord:       API v%d not accepted\n", discord.APIVersion)
+		warn("Discord: %v; a newer pylon may use a supported API version", err)
+	case err != nil:
+		fmt.Println("Discord:       unreachable")
+		warn("Discord: %v", err)
+	default:
+		fmt.Printf("Discord:       API v%d ok\n", discord.APIVersion)
+	}
+	if warned {
+		exit(1)
+	}
+}
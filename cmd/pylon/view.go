@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/shellwords"
+)
+
+// runView handles `pylon view`, which saves command lines under a name in
+// the [views] config section and runs them later.
+func runView(args []string) {
+	if len(args) < 1 {
+		usageFor("view")
+		fail()
+	}
+	switch args[0] {
+	case "save":
+		if len(args) < 3 {
+			fatal("usage: pylon view save <name> <command...>")
+		}
+		name := args[1]
+		if name == "" || strings.ContainsAny(name, " \t=[]#") {
+			fatal("view save: invalid name %q", name)
+		}
+		words := args[2:]
+		if len(words) == 1 {
+			// The whole command as one quoted argument.
+			var err error
+			if words, err = shellwords.Split(words[0]); err != nil {
+				fatal("view save: %v", err)
+			}
+		}
+		words = viewCommandLine(words)
+		line := shellwords.Join(words)
+		f := openConfigFile()
+		f.Set("views", name, line)
+		if err := f.Save(); err != nil {
+			fatal("view save: %v", err)
+		}
+		fmt.Println(i18n.T("view.saved", name, line, f.Path()))
+
+	case "run":
+		if len(args) < 2 {
+			fatal("usage: pylon view run <name> [args...]")
+		}
+		words := viewWords(args[1])
+		run(append(words, args[2:]...))
+
+	case "show":
+		fs := parseFlags(args[1:], "view", "show")
+		if len(fs.args) != 1 {
+			fatal("usage: pylon view show <name>")
+		}
+		fmt.Println(shellwords.Join(viewWords(fs.args[0])))
+
+	case "list", "ls":
+		parseFlags(args[1:], "view", "list").noArgs()
+		views := loadConfig().Views
+		if len(views) == 0 {
+			fmt.Fprintln(os.Stderr, i18n.T("view.none"))
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "NAME\tCOMMAND\n")
+		for _, name := range slices.Sorted(maps.Keys(views)) {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\n", name, views[name])
+		}
+		_ = tw.Flush()
+
+	case "delete", "rm":
+		fs := parseFlags(args[1:], "view", "delete")
+		if len(fs.args) != 1 {
+			fatal("usage: pylon view delete <name> [--yes]")
+		}
+		name := fs.args[0]
+		command, ok := loadConfig().Views[name]
+		if !ok {
+			fatal("view delete: no view named %q", name)
+		}
+		confirmDelete(fs.Bool("yes"), i18n.T("view.confirm", name, command))
+		f := openConfigFile()
+		if !f.Unset("views", name) {
+			fatal("view delete: no view named %q", name)
+		}
+		if err := f.Save(); err != nil {
+			fatal("view delete: %v", err)
+		}
+		fmt.Println(i18n.T("view.deleted", name, f.Path()))
+
+	default:
+		unknownCommand(args[0], "view")
+	}
+}
+
+// viewCommandLine checks that words start with a pylon command and returns
+// them in full: cal and discord commands may be saved without their
+// service, as in `event list --feed 3f2a...`.
+func viewCommandLine(words []string) []string {
+	if len(words) == 0 {
+		fatal("view save: empty command")
+	}
+	if words[0] == "pylon" {
+		words = words[1:]
+		if len(words) == 0 {
+			fatal("view save: empty command")
+		}
+	}
+	switch {
+	case words[0] == "view":
+		fatal("view save: a view can't run another view")
+	case cli.find(words[0]) != nil:
+		return words
+	case calCommand.find(words[0]) != nil:
+		return append([]string{"cal"}, words...)
+	case discordCommand.find(words[0]) != nil:
+		return append([]string{"discord"}, words...)
+	}
+	fatal("view save: %q is not a pylon command", words[0])
+	return nil
+}
+
+// viewWords returns the saved command line of the named view.
+func viewWords(name string) []string {
+	line, ok := loadConfig().Views[name]
+	if !ok {
+		fatal("view: no view named %q (see pylon view list)", name)
+	}
+	words, err := shellwords.Split(line)
+	if err != nil {
+		fatal("view %s: %v", name, err)
+	}
+	if len(words) == 0 || words[0] == "view" {
+		fatal("view %s: not a runnable command: %q", name, line)
+	}
+	return words
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/config"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// runDiscordWebhook handles `pylon discord webhook`, which manages a
+// channel's webhooks through the bot API.
+func runDiscordWebhook(cfg *config.Config, client *discord.Client, args []string) {
+	if len(args) < 1 {
+		usageFor("discord", "webhook")
+		fail()
+	}
+	channelID := cfg.DiscordChannelID
+	configured := webhookID(cfg.DiscordWebhook)
+
+	switch args[0] {
+	case "create":
+		fs := parseFlags(args[1:], "discord", "webhook", "create")
+		fs.noArgs()
+		if v := fs.String("channel", ""); v != "" {
+			channelID = cfg.Channel(v)
+		}
+		name, save := fs.String("name", "pylon"), fs.Bool("save")
+		if channelID == "" {
+			fatal("usage: pylon discord webhook create --channel <id> [--name <name>] [--save]")
+		}
+		hook, err := client.CreateWebhook(channelID, name)
+		if err != nil {
+			fatal("discord webhook create: %v", err)
+		}
+		if !save {
+			fmt.Println(hook.URL())
+			return
+		}
+		f := openConfigFile()
+		f.Set("discord", "webhook", hook.URL())
+		if err := f.Save(); err != nil {
+			fatal("discord webhook create: webhook %s created but not saved: %v", hook.ID, err)
+		}
+		fmt.Println(i18n.T("webhook.saved", hook.Name, hook.ID, f.Path()))
+
+	case "list", "ls":
+		fs := parseFlags(args[1:], "discord", "webhook", "list")
+		fs.noArgs()
+		if v := fs.String("channel", ""); v != "" {
+			channelID = cfg.Channel(v)
+		}
+		if channelID == "" {
+			fatal("usage: pylon discord webhook list --channel <id>")
+		}
+		hooks, err := client.ChannelWebhooks(channelID)
+		if err != nil {
+			fatal("discord webhook list: %v", err)
+		}
+		if len(hooks) == 0 {
+			fmt.Println(i18n.T("webhook.none"))
+			return
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(tw, "ID\tTYPE\tNAME\n")
+		for _, h := range hooks {
+			name := h.Name
+			if h.ID == configured {
+				name += " (configured)"
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", h.ID, webhookType(h.Type), name)
+		}
+		_ = tw.Flush()
+
+	case "delete", "rm":
+		// --channel is accepted for symmetry; a webhook ID is enough.
+		fs := parseFlags(args[1:], "discord", "webhook", "delete")
+		if len(fs.args) > 1 {
+			fatal("discord webhook delete: unexpected argument %q", fs.args[1])
+		}
+		if len(fs.args) == 0 {
+			fatal("usage: pylon discord webhook delete <webhook-id> [--yes]")
+		}
+		id, yes := fs.args[0], fs.Bool("yes")
+		confirmDelete(yes, i18n.T("webhook.confirm", id))
+		if err := client.DeleteWebhook(id); err != nil {
+			fatal("discord webhook delete: %v", err)
+		}
+		fmt.Println(i18n.T("webhook.deleted", id))
+		if id == configured {
+			fmt.Fprintln(os.Stderr, i18n.T("webhook.stale"))
+		}
+
+	default:
+		unknownCommand(args[0], "discord", "webhook")
+	}
+}
+
+// webhookID returns the webhook ID in a webhook URL
+// (https://discord.com/api/webhooks/<id>/<token>), or "".
+func webhookID(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	_, rest, ok := strings.Cut(u.Path, "/webhooks/")
+	if !ok {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
+
+// webhookType names a Discord webhook type.
+func webhookType(t int) string {
+	switch t {
+	case discord.IncomingWebhook:
+		return "incoming"
+	case 2:
+		return "follower"
+	case 3:
+		return "application"
+	}
+	return fmt.Sprint(t)
+}
@@ -0,0 +1,117 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Attachment is a file uploaded with a message.
+type Attachment struct {
+	Name        string // filename shown in Discord
+	ContentType string
+	Data        []byte
+}
+
+// LoadAttachment reads a file for upload. The content type comes from the
+// file extension, falling back to sniffing the content.
+func LoadAttachment(path string) (Attachment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	name := filepath.Base(path)
+	ctype := mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))
+	if ctype == "" {
+		ctype = http.DetectContentType(data)
+	}
+	return Attachment{Name: name, ContentType: ctype, Data: data}, nil
+}
+
+// multipartBody encodes a message payload and its files the way Discord
+// expects: the JSON payload in a payload_json part and each file in a
+// files[n] part, referenced from the payload's attachments list.
+func multipartBody(payload map[string]any, files []Attachment) (body []byte, contentType string, err error) {
+	refs := make([]map[string]any, len(files))
+	for i, f := range files {
+		refs[i] = map[string]any{"id": i, "filename": f.Name}
+	}
+	payload["attachments"] = refs
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal payload: %w", err)
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="payload_json"`)
+	h.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(payloadJSON); err != nil {
+		return nil, "", err
+	}
+
+	for i, f := range files {
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename=%q`, i, f.Name))
+		ctype := f.ContentType
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+		h.Set("Content-Type", ctype)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(f.Data); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// RefreshAttachmentURLs renews signed CDN attachment URLs. Discord expires
+// the links to uploaded files after about a day, so a link kept elsewhere
+// (e.g. on a calendar event) must be refreshed to keep working. The result
+// maps each URL Discord could refresh to its new form.
+func (c *Client) RefreshAttachmentURLs(urls []string) (map[string]string, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	payload, err := json.Marshal(map[string][]string{"attachment_urls": urls})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	body, err := c.botDo(http.MethodPost, c.baseURL+"/attachments/refresh-urls", payload)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		RefreshedURLs []struct {
+			Original  string `json:"original"`
+			Refreshed string `json:"refreshed"`
+		} `json:"refreshed_urls"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	refreshed := make(map[string]string, len(resp.RefreshedURLs))
+	for _, r := range resp.RefreshedURLs {
+		refreshed[r.Original] = r.Refreshed
+	}
+	return refreshed, nil
+}
@@ -0,0 +1,192 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// uploaded is a decoded multipart message as Discord would see it.
+type uploaded struct {
+	payload map[string]any
+	files   map[string]*multipart.Part
+	data    map[string]string
+}
+
+func readUpload(t *testing.T, r *http.Request) uploaded {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("expected multipart/form-data, got %q", r.Header.Get("Content-Type"))
+	}
+	u := uploaded{files: map[string]*multipart.Part{}, data: map[string]string{}}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		data, _ := io.ReadAll(part)
+		if part.FormName() == "payload_json" {
+			if err := json.Unmarshal(data, &u.payload); err != nil {
+				t.Fatalf("decode payload_json: %v", err)
+			}
+			continue
+		}
+		u.files[part.FormName()] = part
+		u.data[part.FormName()] = string(data)
+	}
+	return u
+}
+
+func testFiles() []Attachment {
+	return []Attachment{
+		{Name: "report.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+		{Name: "chart.png", ContentType: "image/png", Data: []byte("\x89PNG")},
+	}
+}
+
+func checkUpload(t *testing.T, u uploaded, content string) {
+	t.Helper()
+	if u.payload["content"] != content {
+		t.Errorf("expected content %q, got %v", content, u.payload["content"])
+	}
+	refs, _ := u.payload["attachments"].([]any)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 attachment refs, got %v", u.payload["attachments"])
+	}
+	for i, want := range testFiles() {
+		name := fmt.Sprintf("files[%d]", i)
+		part, ok := u.files[name]
+		if !ok {
+			t.Fatalf("missing part %s", name)
+		}
+		if part.FileName() != want.Name {
+			t.Errorf("%s: expected filename %q, got %q", name, want.Name, part.FileName())
+		}
+		if ct := part.Header.Get("Content-Type"); ct != want.ContentType {
+			t.Errorf("%s: expected content type %q, got %q", name, want.ContentType, ct)
+		}
+		if u.data[name] != string(want.Data) {
+			t.Errorf("%s: unexpected data %q", name, u.data[name])
+		}
+	}
+}
+
+func TestSendMessageFiles(t *testing.T) {
+	var got uploaded
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = readUpload(t, r)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := NewClient("", srv.URL)
+	if err := client.SendMessageFiles("here's the report", testFiles()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkUpload(t, got, "here's the report")
+}
+
+func TestSendChannelMessageFiles(t *testing.T) {
+	var got uploaded
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/channels/chan-1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bot test-token" {
+			t.Errorf("unexpected auth %q", auth)
+		}
+		got = readUpload(t, r)
+		_, _ = w.Write([]byte(`{"id":"new-1"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	msg, err := client.SendChannelMessageFiles("chan-1", "here's the report", "msg-9", testFiles())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.ID != "new-1" {
+		t.Errorf("expected message ID %q, got %q", "new-1", msg.ID)
+	}
+	checkUpload(t, got, "here's the report")
+	if ref, _ := got.payload["message_reference"].(map[string]any); ref["message_id"] != "msg-9" {
+		t.Errorf("expected reply reference, got %v", got.payload["message_reference"])
+	}
+}
+
+func TestLoadAttachment(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{write("notes.txt", "hello"), "text/plain; charset=utf-8"},
+		{write("CHART.PNG", "\x89PNG\r\n\x1a\n"), "image/png"},
+		{write("noext", "\x89PNG\r\n\x1a\n"), "image/png"},
+		{write("blob", "\x00\x01\x02"), "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		a, err := LoadAttachment(tt.path)
+		if err != nil {
+			t.Fatalf("LoadAttachment(%s): %v", tt.path, err)
+		}
+		if a.Name != filepath.Base(tt.path) {
+			t.Errorf("expected name %q, got %q", filepath.Base(tt.path), a.Name)
+		}
+		if a.ContentType != tt.want {
+			t.Errorf("%s: expected content type %q, got %q", a.Name, tt.want, a.ContentType)
+		}
+	}
+
+	if _, err := LoadAttachment(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestRefreshAttachmentURLs(t *testing.T) {
+	old := "https://cdn.discordapp.com/attachments/1/2/flyer.png?ex=1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/attachments/refresh-urls" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req struct {
+			URLs []string `json:"attachment_urls"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.URLs) != 1 || req.URLs[0] != old {
+			t.Errorf("unexpected payload %+v, %v", req, err)
+		}
+		_, _ = w.Write([]byte(`{"refreshed_urls":[{"original":"` + old + `","refreshed":"https://cdn.discordapp.com/attachments/1/2/flyer.png?ex=2"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	got, err := client.RefreshAttachmentURLs([]string{old})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got[old] != "https://cdn.discordapp.com/attachments/1/2/flyer.png?ex=2" {
+		t.Errorf("refreshed = %v", got)
+	}
+}
@@ -0,0 +1,173 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// APIError is an error response from the Discord API.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("discord API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// CurrentUser returns the bot's own user, which makes it a cheap check that
+// the bot token is valid.
+func (c *Client) CurrentUser() (*Author, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	body, err := c.botGet(c.baseURL + "/users/@me")
+	if err != nil {
+		return nil, err
+	}
+	var u Author
+	if err := json.Unmarshal(body, &u); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &u, nil
+}
+
+// CheckAPIVersion asks Discord for the gateway URL, which needs no
+// credentials, to check that the API version the client uses (APIVersion,
+// unless WithBaseURL says otherwise) is still served. Discord answers
+// retired versions with an error.
+func (c *Client) CheckAPIVersion() error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/gateway", nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
+
+// WebhookInfo describes a webhook.
+type WebhookInfo struct {
+	ID        string `json:"id"`
+	Type      int    `json:"type,omitempty"`
+	Name      string `json:"name"`
+	ChannelID string `json:"channel_id"`
+	GuildID   string `json:"guild_id"`
+	// Token is the secret part of an incoming webhook's URL. Discord only
+	// includes it for incoming webhooks, and to a bot with Manage Webhooks
+	// or to the webhook URL itself.
+	Token string `json:"token,omitempty"`
+}
+
+// Webhook fetches the configured webhook's details without posting
+// anything, checking that its URL is still valid.
+func (c *Client) Webhook() (*WebhookInfo, error) {
+	if c.webhookURL == "" {
+		return nil, fmt.Errorf("webhook URL not configured (set PYLON_DISCORD_WEBHOOK)")
+	}
+	req, err := http.NewRequest(http.MethodGet, c.webhookURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	var w WebhookInfo
+	if err := json.Unmarshal(body, &w); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &w, nil
+}
+
+// Guild is a Discord server.
+type Guild struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Owner and Members are only filled in by Guilds: whether the bot
+	// owns the guild, and Discord's approximate member count.
+	Owner   bool `json:"owner,omitempty"`
+	Members int  `json:"approximate_member_count,omitempty"`
+}
+
+// guildPage is the most guilds /users/@me/guilds returns at once.
+const guildPage = 200
+
+// GetGuild fetches a guild the bot is a member of.
+func (c *Client) GetGuild(guildID string) (*Guild, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	body, err := c.botGet(c.baseURL + "/guilds/" + guildID)
+	if err != nil {
+		return nil, err
+	}
+	var g Guild
+	if err := json.Unmarshal(body, &g); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &g, nil
+}
+
+// Guilds returns every guild the bot is a member of, in ID order,
+// fetching them a page at a time.
+func (c *Client) Guilds() ([]Guild, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	var all []Guild
+	after := ""
+	for {
+		q := url.Values{"limit": {strconv.Itoa(guildPage)}, "with_counts": {"true"}}
+		if after != "" {
+			q.Set("after", after)
+		}
+		body, err := c.botGet(c.baseURL + "/users/@me/guilds?" + q.Encode())
+		if err != nil {
+			return nil, err
+		}
+		var page []Guild
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("parse response: %w", err)
+		}
+		all = append(all, page...)
+		if len(page) < guildPage {
+			return all, nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
+
+// GetChannel fetches a channel or thread the bot can see.
+func (c *Client) GetChannel(channelID string) (*Channel, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	body, err := c.botGet(c.baseURL + "/channels/" + channelID)
+	if err != nil {
+		return nil, err
+	}
+	var ch Channel
+	if err := json.Unmarshal(body, &ch); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &ch, nil
+}
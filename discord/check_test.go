@@ -0,0 +1,243 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCurrentUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		response   string
+		wantName   string
+		wantStatus int
+	}{
+		{"valid token", http.StatusOK, `{"id":"1","username":"pylon-bot"}`, "pylon-bot", 0},
+		{"bad token", http.StatusUnauthorized, `{"message":"401: Unauthorized","code":0}`, "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/users/@me" || r.Header.Get("Authorization") != "Bot test-token" {
+					t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := NewClient("test-token", "")
+			client.baseURL = srv.URL
+			u, err := client.CurrentUser()
+			if tt.wantStatus != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+					t.Fatalf("got %v, want APIError with status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u.Username != tt.wantName {
+				t.Errorf("username = %q, want %q", u.Username, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		response   string
+		wantStatus int
+	}{
+		{"valid", http.StatusOK, `{"id":"9","name":"Announcements","channel_id":"123","guild_id":"456"}`, 0},
+		{"deleted", http.StatusNotFound, `{"message":"Unknown Webhook","code":10015}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("webhook check must not post, got %s", r.Method)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			info, err := NewClient("", srv.URL+"/api/webhooks/9/secret").Webhook()
+			if tt.wantStatus != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+					t.Fatalf("got %v, want APIError with status %d", err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if info.Name != "Announcements" || info.ChannelID != "123" {
+				t.Errorf("info = %+v", info)
+			}
+		})
+	}
+}
+
+func TestGuildsAndChannel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/guilds/456":
+			_, _ = w.Write([]byte(`{"id":"456","name":"Ops"}`))
+		case "/users/@me/guilds":
+			_, _ = w.Write([]byte(`[{"id":"456","name":"Ops"},{"id":"789","name":"Home"}]`))
+		case "/channels/123":
+			_, _ = w.Write([]byte(`{"id":"123","name":"general","type":0,"guild_id":"456"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Unknown Channel","code":10003}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+
+	g, err := client.GetGuild("456")
+	if err != nil || g.Name != "Ops" {
+		t.Fatalf("GetGuild = %+v, %v", g, err)
+	}
+	gs, err := client.Guilds()
+	if err != nil || len(gs) != 2 || gs[1].Name != "Home" {
+		t.Fatalf("Guilds = %+v, %v", gs, err)
+	}
+	ch, err := client.GetChannel("123")
+	if err != nil || ch.Name != "general" || ch.GuildID != "456" {
+		t.Fatalf("GetChannel = %+v, %v", ch, err)
+	}
+
+	_, err = client.GetChannel("999")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("GetChannel(999) = %v, want 404 APIError", err)
+	}
+}
+
+func TestGuildsPages(t *testing.T) {
+	var afters []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("limit") != "200" || q.Get("with_counts") != "true" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		after := q.Get("after")
+		afters = append(afters, after)
+		n := 200
+		if after != "" {
+			n = 3
+		}
+		gs := make([]Guild, n)
+		for i := range gs {
+			gs[i] = Guild{ID: fmt.Sprint(len(afters)*1000 + i), Name: "g", Members: 10}
+		}
+		_ = json.NewEncoder(w).Encode(gs)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	gs, err := client.Guilds()
+	if err != nil {
+		t.Fatalf("Guilds: %v", err)
+	}
+	if len(gs) != 203 || gs[0].Members != 10 {
+		t.Errorf("got %d guilds, first %+v; want 203", len(gs), gs[0])
+	}
+	if len(afters) != 2 || afters[0] != "" || afters[1] != "1199" {
+		t.Errorf("after params = %q, want \"\" then the last ID of page one", afters)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","username":"pylon-bot"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "", WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	if u, err := client.CurrentUser(); err != nil || u.Username != "pylon-bot" {
+		t.Fatalf("CurrentUser = %+v, %v", u, err)
+	}
+}
+
+func TestWithReadOnly(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", srv.URL+"/api/webhooks/9/secret", WithBaseURL(srv.URL), WithReadOnly())
+	if _, err := client.ReadMessages("123", 5); err != nil {
+		t.Fatalf("ReadMessages: %v", err)
+	}
+	for name, call := range map[string]func() error{
+		"SendMessage": func() error { return client.SendMessage("hi") },
+		"SendChannelMessage": func() error {
+			_, err := client.SendChannelMessage("123", "hi", "")
+			return err
+		},
+		"React": func() error { return client.React("123", "456", "👍") },
+	} {
+		err := call()
+		if !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s = %v, want ErrReadOnly", name, err)
+		}
+		if err != nil && strings.Contains(err.Error(), "secret") {
+			t.Errorf("%s error leaks the webhook token: %v", name, err)
+		}
+	}
+	if len(methods) != 1 || methods[0] != http.MethodGet {
+		t.Errorf("server saw %v, want only the GET", methods)
+	}
+}
+
+func TestCheckAPIVersion(t *testing.T) {
+	if want := "/v" + strconv.Itoa(APIVersion); !strings.HasSuffix(apiBase, want) {
+		t.Errorf("apiBase %q does not end in %q", apiBase, want)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"served", http.StatusOK},
+		{"retired", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/gateway" || r.Header.Get("Authorization") != "" {
+					t.Errorf("unexpected request %s %q", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"url":"wss://gateway.discord.gg"}`))
+			}))
+			defer srv.Close()
+
+			err := NewClient("", "", WithBaseURL(srv.URL)).CheckAPIVersion()
+			if (err != nil) != (tt.status != http.StatusOK) {
+				t.Errorf("CheckAPIVersion = %v for status %d", err, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,534 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/httpx"
+)
+
+// APIVersion is the Discord API version the client uses; apiBase must
+// match.
+const APIVersion = 10
+
+const apiBase = "https://discord.com/api/v10"
+
+// Client talks to the Discord API.
+type Client struct {
+	botToken   string
+	webhookURL string
+	baseURL    string // Bot API base, overridden in tests
+	httpClient *http.Client
+	retries    int
+	limiter    *RateLimiter
+	readOnly   bool
+	mentions   *AllowedMentions
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRetries retries rate-limited (429) and transient 5xx responses up to n
+// times, honouring Discord's Retry-After and X-RateLimit-Reset-After headers.
+// The default is no retries.
+func WithRetries(n int) Option {
+	return func(c *Client) { c.retries = n }
+}
+
+// WithHTTPClient sends requests through hc instead of a default client
+// with a 15s timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithBaseURL sends Bot API requests to url instead of Discord's v10 API,
+// e.g. to go through a proxy. Webhook requests use the webhook URL as is.
+func WithBaseURL(url string) Option {
+	return func(c *Client) { c.baseURL = url }
+}
+
+// WithReadOnly makes the client refuse every request that could change
+// something (sending, reacting, moderating: anything but GET and HEAD) with
+// ErrReadOnly, before it is sent.
+func WithReadOnly() Option {
+	return func(c *Client) { c.readOnly = true }
+}
+
+// AllowedMentions limits who the mentions in a message's text notify, as
+// Discord's allowed_mentions: only the kinds in Parse ("users", "roles",
+// "everyone"), plus the author of a message replied to if RepliedUser. The
+// mentions still show; they just don't ping anyone else.
+type AllowedMentions struct {
+	Parse       []string `json:"parse"`
+	RepliedUser bool     `json:"replied_user"`
+}
+
+// WithAllowedMentions sends every message with m as its allowed mentions,
+// e.g. so an "@everyone" quoted in an automated report doesn't ping the
+// whole server. By default Discord notifies everyone mentioned.
+func WithAllowedMentions(m AllowedMentions) Option {
+	if m.Parse == nil {
+		m.Parse = []string{} // null would mean the default
+	}
+	return func(c *Client) { c.mentions = &m }
+}
+
+// ErrReadOnly is returned for a request a WithReadOnly client refused.
+var ErrReadOnly = errors.New("read-only mode")
+
+// WithRateLimiter shares l between clients, or gives a client one that
+// persists its state (see NewRateLimiter). By default each client tracks
+// rate limits on its own, in memory.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) { c.limiter = l }
+}
+
+// NewClient creates a Discord client. botToken is used for reading
+// messages/channels (Bot API), webhookURL is used for sending messages.
+func NewClient(botToken, webhookURL string, opts ...Option) *Client {
+	c := &Client{
+		botToken:   botToken,
+		webhookURL: webhookURL,
+		baseURL:    apiBase,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+		limiter: NewRateLimiter(""),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Message is a Discord message.
+type Message struct {
+	ID        string `json:"id"`
+	Type      int    `json:"type,omitempty"` // MessageTypeReply for a reply
+	Content   string `json:"content"`
+	Timestamp string `json:"timestamp"`
+	Author    Author `json:"author"`
+	// Mentions are the users mentioned in Content, which lets a Resolver
+	// name them without further requests.
+	Mentions  []Mention `json:"mentions,omitempty"`
+	Reference *struct {
+		Content string `json:"content"`
+		Author  Author `json:"author"`
+	} `json:"referenced_message"`
+	// ReplyTo identifies the message this one replies to, even when that
+	// message has since been deleted.
+	ReplyTo     *MessageRef      `json:"message_reference,omitempty"`
+	Attachments []AttachmentInfo `json:"attachments,omitempty"`
+	Reactions   []Reaction       `json:"reactions,omitempty"`
+}
+
+// MessageTypeReply is the Message.Type of a reply.
+const MessageTypeReply = 19
+
+// MessageRef points at another message.
+type MessageRef struct {
+	MessageID string `json:"message_id"`
+	ChannelID string `json:"channel_id,omitempty"`
+}
+
+// AttachmentInfo describes a file attached to a received message.
+type AttachmentInfo struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	URL         string `json:"url"`
+	Size        int    `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// Author is a Discord message author.
+type Author struct {
+	ID         string `json:"id,omitempty"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+}
+
+// DisplayName returns the best display name for an author.
+func (a Author) DisplayName() string {
+	if a.GlobalName != "" {
+		return a.GlobalName
+	}
+	return a.Username
+}
+
+// Channel is a Discord guild channel. Threads are channels too; for them
+// ParentID is the channel they were started in.
+type Channel struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         int    `json:"type"`
+	Position     int    `json:"position"`
+	ParentID     string `json:"parent_id,omitempty"`
+	GuildID      string `json:"guild_id,omitempty"`
+	MessageCount int    `json:"message_count,omitempty"`
+}
+
+// SendMessage posts a plain text message to the configured webhook.
+func (c *Client) SendMessage(message string) error {
+	return c.SendMessageFiles(message, nil)
+}
+
+// SendMessageFiles posts a message with file attachments to the configured
+// webhook. With no files it is the same as SendMessage.
+func (c *Client) SendMessageFiles(message string, files []Attachment) error {
+	return c.SendThreadMessage("", message, files)
+}
+
+// SendThreadMessage posts a message with optional attachments to a thread in
+// the webhook's channel. An empty threadID posts to the channel itself.
+func (c *Client) SendThreadMessage(threadID, message string, files []Attachment) error {
+	if c.webhookURL == "" {
+		return fmt.Errorf("webhook URL not configured (set PYLON_DISCORD_WEBHOOK)")
+	}
+
+	content := map[string]any{"content": message}
+	if c.mentions != nil {
+		content["allowed_mentions"] = c.mentions
+	}
+	var payload []byte
+	contentType := "application/json"
+	var err error
+	if len(files) > 0 {
+		payload, contentType, err = multipartBody(content, files)
+	} else {
+		payload, err = json.Marshal(content)
+	}
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	target := c.webhookURL
+	if threadID != "" {
+		u, err := url.Parse(target)
+		if err != nil {
+			return fmt.Errorf("parse webhook URL: %w", err)
+		}
+		q := u.Query()
+		q.Set("thread_id", threadID)
+		u.RawQuery = q.Encode()
+		target = u.String()
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.send(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// SendChannelMessage posts a message to a channel using the bot token, which
+// works for any channel the bot can see (unlike the webhook, which is bound to
+// one channel). If replyTo is non-empty the message is sent as a reply to that
+// message ID. The created message is returned.
+func (c *Client) SendChannelMessage(channelID, message, replyTo string) (*Message, error) {
+	return c.SendChannelMessageFiles(channelID, message, replyTo, nil)
+}
+
+// SendChannelMessageFiles is SendChannelMessage with file attachments.
+func (c *Client) SendChannelMessageFiles(channelID, message, replyTo string, files []Attachment) (*Message, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID required")
+	}
+
+	payload := map[string]any{"content": message}
+	if c.mentions != nil {
+		payload["allowed_mentions"] = c.mentions
+	}
+	if replyTo != "" {
+		payload["message_reference"] = map[string]string{"message_id": replyTo}
+	}
+	var reqBody []byte
+	var header http.Header
+	var err error
+	if len(files) > 0 {
+		var contentType string
+		reqBody, contentType, err = multipartBody(payload, files)
+		header = http.Header{"Content-Type": {contentType}}
+	} else {
+		reqBody, err = json.Marshal(payload)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/messages", c.baseURL, channelID)
+	body, err := c.botDoHeader(http.MethodPost, url, reqBody, header)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &msg, nil
+}
+
+// ReadMessages fetches the latest messages from a channel. Limit is capped at
+// 100 by the Discord API; defaults to 20 if out of range.
+func (c *Client) ReadMessages(channelID string, limit int) ([]Message, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID required")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/messages?limit=%d", c.baseURL, channelID, limit)
+	body, err := c.botGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []Message
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	// API returns newest-first; reverse to chronological order.
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+
+	return msgs, nil
+}
+
+// GetMessage fetches one message from a channel by ID.
+func (c *Client) GetMessage(channelID, messageID string) (*Message, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" || messageID == "" {
+		return nil, fmt.Errorf("channel and message ID required")
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/messages/%s", c.baseURL, channelID, messageID)
+	body, err := c.botGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &msg, nil
+}
+
+// FillReferences gives each reply in msgs that arrived without the message
+// it replies to (Discord leaves it out at times) a Reference, taken from
+// msgs when the target is among them and otherwise fetched by ID from the
+// target's channel, or channelID if the reference doesn't name one. Each
+// target is fetched at most once. A target that can't be fetched, usually
+// because it was deleted, is left out and shows as deleted.
+func (c *Client) FillReferences(channelID string, msgs []Message) {
+	known := map[string]*Message{}
+	for i := range msgs {
+		known[msgs[i].ID] = &msgs[i]
+	}
+	for i := range msgs {
+		m := &msgs[i]
+		if m.Type != MessageTypeReply || m.ReplyTo == nil || m.Reference != nil {
+			continue
+		}
+		target, ok := known[m.ReplyTo.MessageID]
+		if !ok {
+			ch := m.ReplyTo.ChannelID
+			if ch == "" {
+				ch = channelID
+			}
+			// A failed fetch is remembered as nil so it isn't retried.
+			target, _ = c.GetMessage(ch, m.ReplyTo.MessageID)
+			known[m.ReplyTo.MessageID] = target
+		}
+		if target != nil {
+			m.Reference = &struct {
+				Content string `json:"content"`
+				Author  Author `json:"author"`
+			}{Content: target.Content, Author: target.Author}
+		}
+	}
+}
+
+// ListChannels returns text channels visible to the bot in a guild.
+func (c *Client) ListChannels(guildID string) ([]Channel, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if guildID == "" {
+		return nil, fmt.Errorf("guild ID required")
+	}
+
+	url := fmt.Sprintf("%s/guilds/%s/channels", c.baseURL, guildID)
+	body, err := c.botGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Channel
+	if err := json.Unmarshal(body, &all); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+
+	// Filter to text channels (type 0) only.
+	var text []Channel
+	for _, ch := range all {
+		if ch.Type == 0 {
+			text = append(text, ch)
+		}
+	}
+	return text, nil
+}
+
+// SendDirectMessage sends a message to a user in a direct message from the
+// bot. Discord only allows this to users who share a guild with the bot
+// and accept direct messages from its members.
+func (c *Client) SendDirectMessage(userID, message string) (*Message, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("user ID required")
+	}
+	payload, err := json.Marshal(map[string]string{"recipient_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	body, err := c.botDo(http.MethodPost, c.baseURL+"/users/@me/channels", payload)
+	if err != nil {
+		return nil, fmt.Errorf("open direct message: %w", err)
+	}
+	var dm Channel
+	if err := json.Unmarshal(body, &dm); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return c.SendChannelMessage(dm.ID, message, "")
+}
+
+// FormatMessages renders messages for terminal output, leaving mention and
+// emoji tokens as they are.
+func FormatMessages(msgs []Message) string {
+	var r *Resolver
+	return r.Format(msgs)
+}
+
+// Format renders messages for terminal output with mentions and custom
+// emoji made readable.
+func (r *Resolver) Format(msgs []Message) string {
+	var sb strings.Builder
+	for _, m := range msgs {
+		ts := m.Timestamp
+		if len(ts) >= 19 {
+			ts = ts[:19]
+		}
+		author := m.Author.DisplayName()
+		content := r.Resolve(m.Content)
+		if content == "" {
+			content = "(no text)"
+		}
+		if m.Reference != nil {
+			ref := m.Reference
+			refAuthor := ref.Author.DisplayName()
+			refContent := r.Resolve(ref.Content)
+			if refContent == "" {
+				refContent = "(no text)"
+			}
+			fmt.Fprintf(&sb, "[%s] %s (reply to %s: %q): %s\n", ts, author, refAuthor, refContent, content)
+		} else if m.Type == MessageTypeReply && m.ReplyTo != nil {
+			fmt.Fprintf(&sb, "[%s] %s (reply to a deleted message): %s\n", ts, author, content)
+		} else {
+			fmt.Fprintf(&sb, "[%s] %s: %s\n", ts, author, content)
+		}
+	}
+	return sb.String()
+}
+
+// send sends req once its rate-limit bucket allows, with retries, and
+// records the limits the response reports.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.readOnly && req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return nil, fmt.Errorf("%w: refusing %s", ErrReadOnly, route(req.Method, req.URL.Path))
+	}
+	key := route(req.Method, req.URL.Path)
+	if err := c.limiter.wait(req.Context(), key); err != nil {
+		return nil, err
+	}
+	resp, err := httpx.Do(c.httpClient, req, c.retries)
+	if resp != nil {
+		c.limiter.update(key, resp)
+	}
+	return resp, err
+}
+
+// botGet performs an authenticated GET request against the Discord Bot API.
+func (c *Client) botGet(url string) ([]byte, error) {
+	return c.botDo(http.MethodGet, url, nil)
+}
+
+// botDo performs an authenticated request against the Discord Bot API. A
+// non-nil payload is sent as a JSON body. Any 2xx status is a success.
+func (c *Client) botDo(method, url string, payload []byte) ([]byte, error) {
+	return c.botDoHeader(method, url, payload, nil)
+}
+
+// botDoHeader is botDo with extra request headers, which override the
+// defaults (e.g. a multipart Content-Type).
+func (c *Client) botDoHeader(method, url string, payload []byte, header http.Header) ([]byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bot "+c.botToken)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -80,6 +81,195 @@ func TestSendMessage(t *testing.T) {
 	}
 }
 
+func TestSendChannelMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		botToken  string
+		channelID string
+		replyTo   string
+		status    int
+		wantErr   bool
+	}{
+		{
+			name:      "success",
+			botToken:  "test-token",
+			channelID: "chan-1",
+			status:    http.StatusOK,
+		},
+		{
+			name:      "reply",
+			botToken:  "test-token",
+			channelID: "chan-1",
+			replyTo:   "msg-9",
+			status:    http.StatusOK,
+		},
+		{
+			name:      "api error",
+			botToken:  "test-token",
+			channelID: "chan-1",
+			status:    http.StatusForbidden,
+			wantErr:   true,
+		},
+		{
+			name:      "no bot token",
+			channelID: "chan-1",
+			wantErr:   true,
+		},
+		{
+			name:     "no channel ID",
+			botToken: "test-token",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody map[string]any
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+				if r.URL.Path != "/channels/"+tt.channelID+"/messages" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if auth := r.Header.Get("Authorization"); auth != "Bot "+tt.botToken {
+					t.Errorf("expected auth %q, got %q", "Bot "+tt.botToken, auth)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+					t.Fatalf("decode body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"id":"new-1","content":"hi"}`))
+			}))
+			defer srv.Close()
+
+			client := NewClient(tt.botToken, "")
+			client.baseURL = srv.URL
+			msg, err := client.SendChannelMessage(tt.channelID, "hi", tt.replyTo)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if msg.ID != "new-1" {
+				t.Errorf("expected message ID %q, got %q", "new-1", msg.ID)
+			}
+			if gotBody["content"] != "hi" {
+				t.Errorf("expected content %q, got %v", "hi", gotBody["content"])
+			}
+			ref, hasRef := gotBody["message_reference"].(map[string]any)
+			if tt.replyTo == "" {
+				if hasRef {
+					t.Error("expected no message_reference")
+				}
+			} else if !hasRef || ref["message_id"] != tt.replyTo {
+				t.Errorf("expected reply to %q, got %v", tt.replyTo, gotBody["message_reference"])
+			}
+		})
+	}
+}
+
+func TestAllowedMentions(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Mentions json.RawMessage `json:"allowed_mentions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		bodies = append(bodies, string(body.Mentions))
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		opts []Option
+		want string
+	}{
+		{nil, ""},
+		{[]Option{WithAllowedMentions(AllowedMentions{})}, `{"parse":[],"replied_user":false}`},
+		{[]Option{WithAllowedMentions(AllowedMentions{Parse: []string{"users"}, RepliedUser: true})}, `{"parse":["users"],"replied_user":true}`},
+	}
+	for _, tt := range tests {
+		bodies = nil
+		client := NewClient("token", srv.URL, append(tt.opts, WithBaseURL(srv.URL))...)
+		if err := client.SendMessage("@everyone look"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.SendChannelMessage("c1", "@everyone look", "m1"); err != nil {
+			t.Fatal(err)
+		}
+		for _, got := range bodies {
+			if got != tt.want {
+				t.Errorf("allowed_mentions = %s, want %s", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestSendDirectMessage(t *testing.T) {
+	var paths []string
+	var recipient, content string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/users/@me/channels":
+			recipient, _ = body["recipient_id"].(string)
+			_, _ = w.Write([]byte(`{"id":"dm-1","type":1}`))
+		case "/channels/dm-1/messages":
+			content, _ = body["content"].(string)
+			_, _ = w.Write([]byte(`{"id":"msg-1","channel_id":"dm-1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	msg, err := client.SendDirectMessage("user-1", "job failing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.ID != "msg-1" || recipient != "user-1" || content != "job failing" {
+		t.Errorf("msg = %+v, recipient = %q, content = %q", msg, recipient, content)
+	}
+	if want := "POST /users/@me/channels,POST /channels/dm-1/messages"; strings.Join(paths, ",") != want {
+		t.Errorf("requests = %v, want %s", paths, want)
+	}
+}
+
+func TestSendMessageRetriesRateLimit(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("X-RateLimit-Reset-After", "0.001")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := NewClient("", srv.URL, WithRetries(2))
+	if err := client.SendMessage("hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
 func TestReadMessages(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -396,6 +586,61 @@ func TestFormatMessages(t *testing.T) {
 	}
 }
 
+func TestFillReferences(t *testing.T) {
+	var fetched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = append(fetched, r.URL.Path)
+		switch r.URL.Path {
+		case "/channels/chan-1/messages/1":
+			_, _ = w.Write([]byte(`{"id":"1","content":"way back","author":{"username":"alice"}}`))
+		case "/channels/chan-2/messages/5":
+			_, _ = w.Write([]byte(`{"id":"5","content":"elsewhere","author":{"username":"carol"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Unknown Message","code":10008}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+
+	reply := func(id, to, channel string) Message {
+		return Message{ID: id, Type: MessageTypeReply, Content: "re", Author: Author{Username: "bob"},
+			ReplyTo: &MessageRef{MessageID: to, ChannelID: channel}}
+	}
+	msgs := []Message{
+		{ID: "10", Content: "in window", Author: Author{Username: "dave"}},
+		reply("11", "1", ""),
+		reply("12", "10", ""),
+		reply("13", "1", "chan-1"),
+		reply("14", "5", "chan-2"),
+		reply("15", "4", ""),
+		reply("16", "4", ""),
+	}
+	client.FillReferences("chan-1", msgs)
+
+	want := []string{"", "alice: way back", "dave: in window", "alice: way back", "carol: elsewhere", "", ""}
+	for i, m := range msgs {
+		got := ""
+		if m.Reference != nil {
+			got = m.Reference.Author.DisplayName() + ": " + m.Reference.Content
+		}
+		if got != want[i] {
+			t.Errorf("message %s reference = %q, want %q", m.ID, got, want[i])
+		}
+	}
+	wantFetched := []string{"/channels/chan-1/messages/1", "/channels/chan-2/messages/5", "/channels/chan-1/messages/4"}
+	if strings.Join(fetched, " ") != strings.Join(wantFetched, " ") {
+		t.Errorf("fetched %v, want %v", fetched, wantFetched)
+	}
+
+	out := FormatMessages(msgs[5:6])
+	if !strings.Contains(out, "(reply to a deleted message)") {
+		t.Errorf("reply to a deleted message formats as %q", out)
+	}
+}
+
 func TestAuthorDisplayName(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,10 @@
+// Package discord is a small client for the parts of the Discord API pylon
+// uses: posting through a webhook, and reading channels, threads, reactions
+// and members with a bot token. Either credential may be empty if the
+// methods needing it aren't used.
+//
+//	client := discord.NewClient(os.Getenv("DISCORD_BOT_TOKEN"), "", discord.WithRetries(3))
+//	msgs, err := client.ReadMessages(channelID, 50)
+//
+// Error responses from the Bot API are *APIError.
+package discord
@@ -0,0 +1,152 @@
+package discord
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// discordEpoch is the start of Discord's snowflake clock.
+const discordEpoch = 1420070400000
+
+// Snowflake returns the smallest message ID that could have been sent at t,
+// for paging history from a point in time.
+func Snowflake(t time.Time) string {
+	ms := t.UnixMilli() - discordEpoch
+	if ms < 0 {
+		ms = 0
+	}
+	return strconv.FormatUint(uint64(ms)<<22, 10)
+}
+
+// History returns every message in a channel sent after the message ID
+// after ("0" for the whole history), oldest first, paging 100 at a time.
+func (c *Client) History(channelID, after string) ([]Message, error) {
+	var all []Message
+	err := c.HistoryPages(channelID, after, func(page []Message) error {
+		all = append(all, page...)
+		return nil
+	})
+	return all, err
+}
+
+// HistoryPages is History calling fn with each page of up to 100 messages,
+// oldest first, as it arrives, so a long export can save its progress. An
+// error from fn stops the paging and is returned.
+func (c *Client) HistoryPages(channelID, after string, fn func([]Message) error) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" {
+		return fmt.Errorf("channel ID required")
+	}
+
+	for {
+		url := fmt.Sprintf("%s/channels/%s/messages?limit=100&after=%s", c.baseURL, channelID, after)
+		body, err := c.botGet(url)
+		if err != nil {
+			return err
+		}
+		var page []Message
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("parse response: %w", err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		// Pages come newest-first; the next one starts after the newest.
+		after = page[0].ID
+		slices.Reverse(page)
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < 100 {
+			return nil
+		}
+	}
+}
+
+// ExportFormats are the formats WriteExport accepts.
+var ExportFormats = []string{"json", "csv", "md"}
+
+// ExportedMessage is one message in an export, flattened for archiving.
+type ExportedMessage struct {
+	ID          string           `json:"id"`
+	Timestamp   string           `json:"timestamp"`
+	AuthorID    string           `json:"author_id"`
+	Author      string           `json:"author"`
+	Content     string           `json:"content"`
+	Attachments []AttachmentInfo `json:"attachments,omitempty"`
+	ReplyTo     string           `json:"reply_to,omitempty"`
+}
+
+func exported(m Message) ExportedMessage {
+	e := ExportedMessage{
+		ID:          m.ID,
+		Timestamp:   m.Timestamp,
+		AuthorID:    m.Author.ID,
+		Author:      m.Author.DisplayName(),
+		Content:     m.Content,
+		Attachments: m.Attachments,
+	}
+	if m.ReplyTo != nil {
+		e.ReplyTo = m.ReplyTo.MessageID
+	}
+	return e
+}
+
+// WriteExport writes msgs to w as JSON, CSV or Markdown.
+func WriteExport(w io.Writer, channelID string, msgs []Message, format string) error {
+	switch format {
+	case "json":
+		out := make([]ExportedMessage, len(msgs))
+		for i, m := range msgs {
+			out[i] = exported(m)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"id", "timestamp", "author_id", "author", "content", "attachments", "reply_to"})
+		for _, m := range msgs {
+			e := exported(m)
+			urls := make([]string, len(e.Attachments))
+			for i, a := range e.Attachments {
+				urls[i] = a.URL
+			}
+			_ = cw.Write([]string{e.ID, e.Timestamp, e.AuthorID, e.Author, e.Content, strings.Join(urls, " "), e.ReplyTo})
+		}
+		cw.Flush()
+		return cw.Error()
+
+	case "md":
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "# Channel %s\n", channelID)
+		for _, m := range msgs {
+			e := exported(m)
+			fmt.Fprintf(&sb, "\n### %s · %s\n<a id=\"%s\"></a>\n\n", e.Author, e.Timestamp, e.ID)
+			if e.ReplyTo != "" {
+				fmt.Fprintf(&sb, "> ↪ reply to [%s](#%s)\n\n", e.ReplyTo, e.ReplyTo)
+			}
+			if e.Content != "" {
+				sb.WriteString(e.Content + "\n")
+			}
+			for _, a := range e.Attachments {
+				fmt.Fprintf(&sb, "\n- [%s](%s) (%d bytes)", a.Filename, a.URL, a.Size)
+			}
+			if len(e.Attachments) > 0 {
+				sb.WriteString("\n")
+			}
+		}
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+	return fmt.Errorf("unknown format %q: want json, csv or md", format)
+}
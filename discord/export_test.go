@@ -0,0 +1,101 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnowflake(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.UnixMilli(discordEpoch), "0"},
+		{time.UnixMilli(discordEpoch + 1), "4194304"},
+		{time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), "0"},
+	}
+	for _, tt := range tests {
+		if got := Snowflake(tt.t); got != tt.want {
+			t.Errorf("Snowflake(%v) = %s, want %s", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	// 250 messages with IDs 1..250, served newest-first like Discord.
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("limit") != "100" {
+			t.Errorf("limit = %q", r.URL.Query().Get("limit"))
+		}
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		var page []Message
+		for id := min(after+100, 250); id > after; id-- {
+			page = append(page, Message{ID: strconv.Itoa(id)})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	msgs, err := client.History("123", "40")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msgs) != 210 {
+		t.Fatalf("got %d messages, want 210", len(msgs))
+	}
+	for i, m := range msgs {
+		if m.ID != strconv.Itoa(41+i) {
+			t.Fatalf("msgs[%d].ID = %s, want %d (oldest first)", i, m.ID, 41+i)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("%d requests, want 3", requests)
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	msgs := []Message{
+		{ID: "1", Timestamp: "2025-01-02T10:00:00+00:00", Author: Author{ID: "7", Username: "ana"}, Content: "ship it, then"},
+		{
+			ID: "2", Timestamp: "2025-01-02T10:05:00+00:00", Author: Author{ID: "8", Username: "bo", GlobalName: "Bo"},
+			Content:     "logs attached",
+			ReplyTo:     &MessageRef{MessageID: "1"},
+			Attachments: []AttachmentInfo{{ID: "9", Filename: "log.txt", URL: "https://cdn.example/log.txt", Size: 12}},
+		},
+	}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"json", []string{`"author": "Bo"`, `"reply_to": "1"`, `"filename": "log.txt"`}},
+		{"csv", []string{"id,timestamp,author_id,author,content,attachments,reply_to\n", `1,2025-01-02T10:00:00+00:00,7,ana,"ship it, then",,` + "\n", "https://cdn.example/log.txt,1\n"}},
+		{"md", []string{"# Channel 123\n", "### Bo · 2025-01-02T10:05:00+00:00", "> ↪ reply to [1](#1)", "- [log.txt](https://cdn.example/log.txt) (12 bytes)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteExport(&buf, "123", msgs, tt.format); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("output missing %q:\n%s", w, buf.String())
+				}
+			}
+		})
+	}
+
+	if err := WriteExport(&bytes.Buffer{}, "123", msgs, "xml"); err == nil {
+		t.Error("want error for unknown format")
+	}
+}
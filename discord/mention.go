@@ -0,0 +1,143 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Mention is a user mentioned in a message. Member carries the user's
+// server nickname when the message is from a guild.
+type Mention struct {
+	Author
+	Member *struct {
+		Nick string `json:"nick"`
+	} `json:"member,omitempty"`
+}
+
+// Resolver makes the tokens Discord stores in message text readable:
+// <@id> and <@!id> become @name, <@&id> @role, <#id> #channel, and custom
+// emoji <:party:id> (or animated <a:party:id>) become :party:. Names are
+// looked up at most once each and cached; a token that can't be resolved is
+// left as it is. A nil Resolver leaves text unchanged.
+type Resolver struct {
+	c        *Client
+	guildID  string
+	users    map[string]string
+	channels map[string]string
+	roles    map[string]string // nil until the guild's roles are loaded
+}
+
+// NewResolver returns a Resolver for messages from guildID, seeded with the
+// authors and mentioned users of msgs. Without a guild ID role mentions stay
+// unresolved.
+func (c *Client) NewResolver(guildID string, msgs []Message) *Resolver {
+	r := &Resolver{c: c, guildID: guildID, users: map[string]string{}, channels: map[string]string{}}
+	for _, m := range msgs {
+		if m.Author.ID != "" {
+			r.users[m.Author.ID] = m.Author.DisplayName()
+		}
+		for _, u := range m.Mentions {
+			name := u.DisplayName()
+			if u.Member != nil && u.Member.Nick != "" {
+				name = u.Member.Nick
+			}
+			r.users[u.ID] = name
+		}
+	}
+	return r
+}
+
+var token = regexp.MustCompile(`<(@!?|@&|#)(\d+)>|<a?:(\w+):\d+>`)
+
+// Resolve returns text with its mention and emoji tokens replaced.
+func (r *Resolver) Resolve(text string) string {
+	if r == nil {
+		return text
+	}
+	return token.ReplaceAllStringFunc(text, func(tok string) string {
+		m := token.FindStringSubmatch(tok)
+		if m[3] != "" {
+			return ":" + m[3] + ":"
+		}
+		var name string
+		switch m[1] {
+		case "@", "@!":
+			name = r.user(m[2])
+		case "@&":
+			name = r.role(m[2])
+		case "#":
+			if name = r.channel(m[2]); name != "" {
+				return "#" + name
+			}
+			return tok
+		}
+		if name == "" {
+			return tok
+		}
+		return "@" + name
+	})
+}
+
+func (r *Resolver) user(id string) string {
+	if name, ok := r.users[id]; ok {
+		return name
+	}
+	var name string
+	if r.guildID != "" {
+		var member struct {
+			Nick string `json:"nick"`
+			User Author `json:"user"`
+		}
+		if r.get(fmt.Sprintf("/guilds/%s/members/%s", r.guildID, id), &member) {
+			name = member.Nick
+			if name == "" {
+				name = member.User.DisplayName()
+			}
+		}
+	}
+	if name == "" {
+		var u Author
+		if r.get("/users/"+id, &u) {
+			name = u.DisplayName()
+		}
+	}
+	r.users[id] = name
+	return name
+}
+
+func (r *Resolver) channel(id string) string {
+	if name, ok := r.channels[id]; ok {
+		return name
+	}
+	var ch Channel
+	r.get("/channels/"+id, &ch)
+	r.channels[id] = ch.Name
+	return ch.Name
+}
+
+func (r *Resolver) role(id string) string {
+	if r.roles == nil {
+		r.roles = map[string]string{}
+		var roles []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		if r.guildID != "" && r.get("/guilds/"+r.guildID+"/roles", &roles) {
+			for _, role := range roles {
+				r.roles[role.ID] = role.Name
+			}
+		}
+	}
+	return r.roles[id]
+}
+
+// get fetches path from the bot API into v, reporting success. Failures are
+// not errors here: the token is just left unresolved.
+func (r *Resolver) get(path string, v any) bool {
+	if r.c.botToken == "" {
+		return false
+	}
+	body, err := r.c.botGet(r.c.baseURL + path)
+	return err == nil && json.Unmarshal(body, v) == nil
+}
@@ -0,0 +1,80 @@
+package discord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolver(t *testing.T) {
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/guilds/g1/roles":
+			_, _ = w.Write([]byte(`[{"id":"900","name":"mods"}]`))
+		case "/channels/700":
+			_, _ = w.Write([]byte(`{"id":"700","name":"general","type":0}`))
+		case "/guilds/g1/members/300":
+			_, _ = w.Write([]byte(`{"nick":"","user":{"id":"300","username":"carol","global_name":"Carol"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	msgs := []Message{{
+		Author: Author{ID: "100", Username: "alice", GlobalName: "Alice"},
+		Mentions: []Mention{{
+			Author: Author{ID: "200", Username: "bob"},
+			Member: &struct {
+				Nick string `json:"nick"`
+			}{Nick: "Bobby"},
+		}},
+	}}
+	r := client.NewResolver("g1", msgs)
+
+	tests := []struct {
+		in, want string
+	}{
+		{"hi <@200>", "hi @Bobby"},
+		{"cc <@!100>", "cc @Alice"},
+		{"ping <@300>", "ping @Carol"},
+		{"ask <@&900>", "ask @mods"},
+		{"see <#700>", "see #general"},
+		{"party <:party:6789> <a:dance:1234>", "party :party: :dance:"},
+		{"who <@999> <#998> <@&997>", "who <@999> <#998> <@&997>"},
+		{"no tokens <here>", "no tokens <here>"},
+	}
+	for _, tt := range tests {
+		if got := r.Resolve(tt.in); got != tt.want {
+			t.Errorf("Resolve(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	// Lookups are cached, misses included.
+	r.Resolve("<#700> <@999> <@&900>")
+	for path, n := range requests {
+		if n != 1 {
+			t.Errorf("%s requested %d times, want 1", path, n)
+		}
+	}
+}
+
+func TestResolverFormat(t *testing.T) {
+	msgs := []Message{{
+		Timestamp: "2026-02-18T10:30:00.000Z",
+		Content:   "thanks <@200> <:tada:1>",
+		Author:    Author{ID: "100", Username: "alice"},
+		Mentions:  []Mention{{Author: Author{ID: "200", Username: "bob"}}},
+	}}
+	want := "[2026-02-18T10:30:00] alice: thanks @bob :tada:\n"
+	if got := NewClient("", "").NewResolver("", msgs).Format(msgs); got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+	if got := FormatMessages(msgs); got != "[2026-02-18T10:30:00] alice: thanks <@200> <:tada:1>\n" {
+		t.Errorf("FormatMessages should leave tokens: %q", got)
+	}
+}
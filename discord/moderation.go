@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// MaxTimeout is the longest timeout Discord allows.
+const MaxTimeout = 28 * 24 * time.Hour
+
+// ErrReasonRequired is returned by moderation actions called without a
+// reason. pylon always records why an action was taken in the audit log.
+var ErrReasonRequired = errors.New("a reason is required for moderation actions")
+
+// maxReason is Discord's limit on X-Audit-Log-Reason, in characters.
+const maxReason = 512
+
+// TimeoutMember prevents a member from talking or reacting for d. A zero d
+// lifts an existing timeout. The reason is recorded in the audit log.
+func (c *Client) TimeoutMember(guildID, userID string, d time.Duration, reason string) error {
+	if d < 0 || d > MaxTimeout {
+		return fmt.Errorf("timeout must be between 0 and 28 days, got %s", d)
+	}
+	var until any // JSON null lifts the timeout
+	if d > 0 {
+		until = time.Now().Add(d).UTC().Format(time.RFC3339)
+	}
+	payload, err := json.Marshal(map[string]any{"communication_disabled_until": until})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	return c.moderate(http.MethodPatch, guildID, userID, "/members/", payload, reason)
+}
+
+// KickMember removes a member from the guild. They can rejoin with an
+// invite. The reason is recorded in the audit log.
+func (c *Client) KickMember(guildID, userID, reason string) error {
+	return c.moderate(http.MethodDelete, guildID, userID, "/members/", nil, reason)
+}
+
+// BanMember bans a user from the guild and deletes their messages from the
+// last deleteMessages (at most 7 days; zero keeps them). The reason is
+// recorded in the audit log.
+func (c *Client) BanMember(guildID, userID string, deleteMessages time.Duration, reason string) error {
+	if deleteMessages < 0 || deleteMessages > 7*24*time.Hour {
+		return fmt.Errorf("message deletion window must be between 0 and 7 days, got %s", deleteMessages)
+	}
+	payload, err := json.Marshal(map[string]int64{"delete_message_seconds": int64(deleteMessages / time.Second)})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	return c.moderate(http.MethodPut, guildID, userID, "/bans/", payload, reason)
+}
+
+// moderate sends a member-targeted request with the audit log reason.
+func (c *Client) moderate(method, guildID, userID, resource string, payload []byte, reason string) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if guildID == "" {
+		return fmt.Errorf("guild ID required")
+	}
+	if userID == "" {
+		return fmt.Errorf("user ID required")
+	}
+	if reason == "" {
+		return ErrReasonRequired
+	}
+	if r := []rune(reason); len(r) > maxReason {
+		reason = string(r[:maxReason])
+	}
+
+	u := fmt.Sprintf("%s/guilds/%s%s%s", c.baseURL, guildID, resource, userID)
+	header := http.Header{}
+	// Discord requires the reason to be URL-encoded.
+	header.Set("X-Audit-Log-Reason", url.PathEscape(reason))
+	_, err := c.botDoHeader(method, u, payload, header)
+	return err
+}
@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestModeration(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(c *Client) error
+		wantMethod string
+		wantPath   string
+		wantBody   string // key expected in the JSON body; empty for none
+		status     int
+		wantErr    bool
+	}{
+		{
+			name:       "timeout",
+			call:       func(c *Client) error { return c.TimeoutMember("g1", "u1", time.Hour, "spam links") },
+			wantMethod: http.MethodPatch,
+			wantPath:   "/guilds/g1/members/u1",
+			wantBody:   "communication_disabled_until",
+			status:     http.StatusOK,
+		},
+		{
+			name:       "kick",
+			call:       func(c *Client) error { return c.KickMember("g1", "u1", "spam links") },
+			wantMethod: http.MethodDelete,
+			wantPath:   "/guilds/g1/members/u1",
+			status:     http.StatusNoContent,
+		},
+		{
+			name:       "ban",
+			call:       func(c *Client) error { return c.BanMember("g1", "u1", 24*time.Hour, "spam links") },
+			wantMethod: http.MethodPut,
+			wantPath:   "/guilds/g1/bans/u1",
+			wantBody:   "delete_message_seconds",
+			status:     http.StatusNoContent,
+		},
+		{
+			name:       "missing permission",
+			call:       func(c *Client) error { return c.KickMember("g1", "u1", "spam links") },
+			wantMethod: http.MethodDelete,
+			wantPath:   "/guilds/g1/members/u1",
+			status:     http.StatusForbidden,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod {
+					t.Errorf("expected %s, got %s", tt.wantMethod, r.Method)
+				}
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("expected path %s, got %s", tt.wantPath, r.URL.Path)
+				}
+				if got := r.Header.Get("X-Audit-Log-Reason"); got != "spam%20links" {
+					t.Errorf("expected encoded audit reason, got %q", got)
+				}
+				if tt.wantBody != "" {
+					var body map[string]any
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Fatalf("decode body: %v", err)
+					}
+					if _, ok := body[tt.wantBody]; !ok {
+						t.Errorf("expected %q in body, got %v", tt.wantBody, body)
+					}
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			client := NewClient("test-token", "")
+			client.baseURL = srv.URL
+			err := tt.call(client)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTimeoutMemberLift(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	if err := client.TimeoutMember("g1", "u1", 0, "appeal accepted"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, ok := body["communication_disabled_until"]; !ok || v != nil {
+		t.Errorf("expected null communication_disabled_until, got %v", body)
+	}
+}
+
+func TestModerationValidation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+
+	if err := client.KickMember("g1", "u1", ""); !errors.Is(err, ErrReasonRequired) {
+		t.Errorf("expected ErrReasonRequired, got %v", err)
+	}
+	if err := client.KickMember("", "u1", "r"); err == nil {
+		t.Error("expected error for missing guild")
+	}
+	if err := client.TimeoutMember("g1", "u1", 29*24*time.Hour, "r"); err == nil {
+		t.Error("expected error for timeout over 28 days")
+	}
+	if err := client.BanMember("g1", "u1", 8*24*time.Hour, "r"); err == nil {
+		t.Error("expected error for deletion window over 7 days")
+	}
+	if err := NewClient("", "").KickMember("g1", "u1", "r"); err == nil {
+		t.Error("expected error without bot token")
+	}
+}
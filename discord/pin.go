@@ -0,0 +1,49 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Pin pins a message in its channel. The bot needs the Manage Messages
+// permission (Pin Messages in newer guilds), and a channel holds at most 50
+// pins.
+func (c *Client) Pin(channelID, messageID string) error {
+	return c.pin(http.MethodPut, channelID, messageID)
+}
+
+// Unpin unpins a message.
+func (c *Client) Unpin(channelID, messageID string) error {
+	return c.pin(http.MethodDelete, channelID, messageID)
+}
+
+func (c *Client) pin(method, channelID, messageID string) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" || messageID == "" {
+		return fmt.Errorf("channel ID and message ID required")
+	}
+	_, err := c.botDo(method, fmt.Sprintf("%s/channels/%s/pins/%s", c.baseURL, channelID, messageID), nil)
+	return err
+}
+
+// Pins returns the channel's pinned messages, most recently pinned first.
+func (c *Client) Pins(channelID string) ([]Message, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID required")
+	}
+	body, err := c.botGet(fmt.Sprintf("%s/channels/%s/pins", c.baseURL, channelID))
+	if err != nil {
+		return nil, err
+	}
+	var msgs []Message
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return msgs, nil
+}
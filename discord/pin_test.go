@@ -0,0 +1,67 @@
+package discord
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPinUnpin(t *testing.T) {
+	tests := []struct {
+		name       string
+		call       func(*Client) error
+		wantMethod string
+	}{
+		{name: "pin", call: func(c *Client) error { return c.Pin("chan-1", "msg-1") }, wantMethod: http.MethodPut},
+		{name: "unpin", call: func(c *Client) error { return c.Unpin("chan-1", "msg-1") }, wantMethod: http.MethodDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.wantMethod || r.URL.Path != "/channels/chan-1/pins/msg-1" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				called = true
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			client := NewClient("test-token", "")
+			client.baseURL = srv.URL
+			if err := tt.call(client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !called {
+				t.Error("expected a request")
+			}
+		})
+	}
+
+	if err := NewClient("test-token", "").Pin("chan-1", ""); err == nil {
+		t.Error("expected an error without a message ID")
+	}
+}
+
+func TestPins(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/channels/chan-1/pins" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[
+			{"id":"msg-2","content":"On-call rota","author":{"username":"alice"}},
+			{"id":"msg-1","content":"Runbook","author":{"username":"bob"}}
+		]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	pins, err := client.Pins("chan-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pins) != 2 || pins[0].ID != "msg-2" || pins[1].Content != "Runbook" {
+		t.Errorf("pins = %+v", pins)
+	}
+}
@@ -0,0 +1,188 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/httpx"
+)
+
+// maxLimitWait caps how long a request waits for its bucket to reset, so a
+// bogus saved reset time can't stall a client.
+const maxLimitWait = time.Minute
+
+// RateLimiter delays requests that Discord's X-RateLimit-* headers say
+// would be rate limited, instead of sending them to get a 429. Buckets are
+// tracked per route, so sending to one channel doesn't hold up another.
+//
+// A RateLimiter may be shared by several clients. Given a path, it also
+// keeps its state in that file, so consecutive processes (a script calling
+// pylon discord msg in a loop) respect the limits their predecessors hit.
+type RateLimiter struct {
+	mu      sync.Mutex
+	path    string
+	buckets map[string]*bucket // by route
+	global  time.Time          // all requests wait until then
+
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+type bucket struct {
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+type limiterState struct {
+	Buckets map[string]*bucket `json:"buckets"`
+	Global  time.Time          `json:"global,omitzero"`
+}
+
+// NewRateLimiter returns a rate limiter, loading and saving its state at
+// path unless path is empty. An unreadable state file is ignored: the
+// limits are only an optimisation, and the server enforces them anyway.
+func NewRateLimiter(path string) *RateLimiter {
+	l := &RateLimiter{
+		path:    path,
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+		sleep:   sleepCtx,
+	}
+	if path != "" {
+		var s limiterState
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &s) == nil {
+			if s.Buckets != nil {
+				l.buckets = s.Buckets
+			}
+			l.global = s.Global
+		}
+	}
+	return l
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// wait blocks until route may be requested, and counts the request against
+// its bucket.
+func (l *RateLimiter) wait(ctx context.Context, route string) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		until := l.global
+		b := l.buckets[route]
+		if b != nil && b.Remaining <= 0 && b.Reset.After(until) {
+			until = b.Reset
+		}
+		if !until.After(now) {
+			if b != nil && b.Remaining > 0 {
+				b.Remaining--
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+		if err := l.sleep(ctx, min(until.Sub(now), maxLimitWait)); err != nil {
+			return err
+		}
+		if until.Sub(now) > maxLimitWait {
+			return nil
+		}
+	}
+}
+
+// update records the limits a response reports for route.
+func (l *RateLimiter) update(route string, resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	changed := false
+
+	if resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("X-RateLimit-Global") == "true" {
+		if d, ok := httpx.RetryAfter(resp); ok {
+			l.global = now.Add(d)
+			changed = true
+		}
+	}
+	remaining, err1 := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	resetAfter, err2 := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Reset-After"), 64)
+	if err1 == nil && err2 == nil {
+		l.buckets[route] = &bucket{
+			Remaining: remaining,
+			Reset:     now.Add(time.Duration(resetAfter * float64(time.Second))),
+		}
+		changed = true
+	}
+	if changed && l.path != "" {
+		l.save(now)
+	}
+}
+
+// save writes the buckets that haven't reset yet. Errors are ignored for
+// the same reason unreadable state is.
+func (l *RateLimiter) save(now time.Time) {
+	s := limiterState{Buckets: map[string]*bucket{}}
+	for route, b := range l.buckets {
+		if b.Reset.After(now) {
+			s.Buckets[route] = b
+		}
+	}
+	if l.global.After(now) {
+		s.Global = l.global
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), ".ratelimit-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil && cerr == nil {
+		_ = os.Rename(tmp.Name(), l.path)
+	}
+}
+
+// route identifies the rate-limit bucket of a request: its method and
+// path, with IDs other than the channel, guild or webhook replaced, since
+// Discord buckets per route and per those major parameters. Webhook tokens
+// are replaced too, to keep them out of the saved state.
+func route(method, path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		switch {
+		case parts[i-1] == "channels" || parts[i-1] == "guilds" || parts[i-1] == "webhooks":
+			continue
+		case i >= 2 && parts[i-2] == "webhooks":
+			parts[i] = ":token"
+		case parts[i-1] == "reactions":
+			// Every emoji and user shares the reactions bucket.
+			parts = append(parts[:i], ":reaction")
+		default:
+			if _, err := strconv.ParseUint(parts[i], 10, 64); err == nil {
+				parts[i] = ":id"
+			}
+		}
+	}
+	return method + " /" + strings.Join(parts, "/")
+}
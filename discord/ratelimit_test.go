@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"POST", "/api/v10/channels/123/messages", "POST /api/v10/channels/123/messages"},
+		{"DELETE", "/api/v10/channels/123/messages/456", "DELETE /api/v10/channels/123/messages/:id"},
+		{"GET", "/api/v10/channels/123/messages/456/reactions/%F0%9F%8E%9F/@me", "GET /api/v10/channels/123/messages/:id/reactions/:reaction"},
+		{"POST", "/api/webhooks/789/s3cret-token", "POST /api/webhooks/789/:token"},
+		{"GET", "/api/v10/guilds/42/members/99", "GET /api/v10/guilds/42/members/:id"},
+		{"GET", "/users/@me", "GET /users/@me"},
+	}
+	for _, tt := range tests {
+		if got := route(tt.method, tt.path); got != tt.want {
+			t.Errorf("route(%s %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+// fakeClock drives a RateLimiter without real sleeps.
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) install(l *RateLimiter) {
+	l.now = func() time.Time { return c.now }
+	l.sleep = func(_ context.Context, d time.Duration) error {
+		c.slept = append(c.slept, d)
+		c.now = c.now.Add(d)
+		return nil
+	}
+}
+
+func limited(remaining, resetAfter string) *http.Response {
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", remaining)
+	h.Set("X-RateLimit-Reset-After", resetAfter)
+	return &http.Response{StatusCode: http.StatusOK, Header: h}
+}
+
+func TestRateLimiterWaits(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	l := NewRateLimiter("")
+	clock.install(l)
+	ctx := context.Background()
+
+	// One request left: it goes at once, the next waits for the reset.
+	l.update("POST /channels/1/messages", limited("1", "2.5"))
+	_ = l.wait(ctx, "POST /channels/1/messages")
+	if len(clock.slept) != 0 {
+		t.Fatalf("first request slept %v", clock.slept)
+	}
+	_ = l.wait(ctx, "POST /channels/1/messages")
+	if len(clock.slept) != 1 || clock.slept[0] != 2500*time.Millisecond {
+		t.Fatalf("second request slept %v, want [2.5s]", clock.slept)
+	}
+
+	// Other routes are unaffected.
+	l.update("POST /channels/1/messages", limited("0", "5"))
+	_ = l.wait(ctx, "POST /channels/2/messages")
+	if len(clock.slept) != 1 {
+		t.Errorf("request to another channel slept %v", clock.slept[1:])
+	}
+
+	// A global limit holds up every route.
+	h := http.Header{}
+	h.Set("X-RateLimit-Global", "true")
+	h.Set("Retry-After", "1")
+	l.update("GET /users/@me", &http.Response{StatusCode: http.StatusTooManyRequests, Header: h})
+	_ = l.wait(ctx, "POST /channels/2/messages")
+	if len(clock.slept) != 2 || clock.slept[1] != time.Second {
+		t.Errorf("slept %v, want a 1s wait for the global limit", clock.slept)
+	}
+}
+
+func TestRateLimiterPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+	clock := &fakeClock{now: time.Now()}
+
+	l := NewRateLimiter(path)
+	clock.install(l)
+	l.update("POST /api/webhooks/9/:token", limited("0", "3"))
+
+	// A later process loads the exhausted bucket and waits it out.
+	next := NewRateLimiter(path)
+	clock.install(next)
+	_ = next.wait(context.Background(), "POST /api/webhooks/9/:token")
+	if len(clock.slept) != 1 || clock.slept[0] <= 2*time.Second {
+		t.Errorf("slept %v, want about 3s", clock.slept)
+	}
+}
+
+func TestClientQueuesRateLimitedRequests(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", "0.75")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	l := NewRateLimiter("")
+	clock.install(l)
+	client := NewClient("", srv.URL+"/api/webhooks/9/token", WithRateLimiter(l))
+	for range 3 {
+		if err := client.SendMessage("hi"); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+	}
+	if requests != 3 || len(clock.slept) != 2 {
+		t.Errorf("%d requests, slept %v; want 3 requests and 2 waits", requests, clock.slept)
+	}
+}
@@ -0,0 +1,106 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Reaction is the tally of one emoji on a message.
+type Reaction struct {
+	Count int   `json:"count"`
+	Me    bool  `json:"me"` // the bot reacted with it
+	Emoji Emoji `json:"emoji"`
+}
+
+// Emoji is a Unicode emoji (Name only) or a custom guild emoji.
+type Emoji struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Animated bool   `json:"animated,omitempty"`
+}
+
+// String returns a Unicode emoji as is and a custom one as :name:.
+func (e Emoji) String() string {
+	if e.ID == "" {
+		return e.Name
+	}
+	return ":" + e.Name + ":"
+}
+
+// FormatReactions renders reaction counts on one line, e.g. "👍 3  :party: 1".
+func FormatReactions(rs []Reaction) string {
+	parts := make([]string, len(rs))
+	for i, r := range rs {
+		parts[i] = fmt.Sprintf("%s %d", r.Emoji, r.Count)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// reactionEmoji converts an emoji as typed, either a Unicode emoji or a
+// custom one as <:name:id>, <a:name:id> or name:id, to its URL path form.
+func reactionEmoji(emoji string) string {
+	e := strings.TrimSuffix(strings.TrimPrefix(emoji, "<"), ">")
+	if e != emoji {
+		e = strings.TrimPrefix(e, "a:")
+		e = strings.TrimPrefix(e, ":")
+	}
+	return url.PathEscape(e)
+}
+
+// Reactors returns every user who reacted to a message with emoji, paging
+// 100 at a time.
+func (c *Client) Reactors(channelID, messageID, emoji string) ([]Author, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" || messageID == "" || emoji == "" {
+		return nil, fmt.Errorf("channel ID, message ID and emoji required")
+	}
+
+	base := fmt.Sprintf("%s/channels/%s/messages/%s/reactions/%s?limit=100", c.baseURL, channelID, messageID, reactionEmoji(emoji))
+	var all []Author
+	after := ""
+	for {
+		u := base
+		if after != "" {
+			u += "&after=" + after
+		}
+		body, err := c.botGet(u)
+		if err != nil {
+			return nil, err
+		}
+		var page []Author
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("parse response: %w", err)
+		}
+		all = append(all, page...)
+		if len(page) < 100 {
+			return all, nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
+
+// React adds the bot's reaction with emoji to a message.
+func (c *Client) React(channelID, messageID, emoji string) error {
+	return c.ownReaction(http.MethodPut, channelID, messageID, emoji)
+}
+
+// Unreact removes the bot's reaction with emoji from a message.
+func (c *Client) Unreact(channelID, messageID, emoji string) error {
+	return c.ownReaction(http.MethodDelete, channelID, messageID, emoji)
+}
+
+func (c *Client) ownReaction(method, channelID, messageID, emoji string) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" || messageID == "" || emoji == "" {
+		return fmt.Errorf("channel ID, message ID and emoji required")
+	}
+	_, err := c.botDo(method, fmt.Sprintf("%s/channels/%s/messages/%s/reactions/%s/@me", c.baseURL, channelID, messageID, reactionEmoji(emoji)), nil)
+	return err
+}
@@ -0,0 +1,88 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestReactionEmoji(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"🎟️", "%F0%9F%8E%9F%EF%B8%8F"},
+		{"<:ticket:123>", "ticket:123"},
+		{"<a:party:456>", "party:456"},
+		{"ticket:123", "ticket:123"},
+	}
+	for _, tt := range tests {
+		if got := reactionEmoji(tt.in); got != tt.want {
+			t.Errorf("reactionEmoji(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReactors(t *testing.T) {
+	// 150 users with IDs 1..150, in ID order like Discord.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/channels/1/messages/2/reactions/ticket:9" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		var page []Author
+		for id := after + 1; id <= min(after+100, 150); id++ {
+			page = append(page, Author{ID: strconv.Itoa(id), Username: "u" + strconv.Itoa(id)})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	users, err := client.Reactors("1", "2", "<:ticket:9>")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 150 || users[149].Username != "u150" {
+		t.Fatalf("got %d users, last %+v", len(users), users[len(users)-1])
+	}
+}
+
+func TestReact(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "", WithBaseURL(srv.URL))
+	if err := client.React("1", "2", "👍"); err != nil {
+		t.Fatalf("React: %v", err)
+	}
+	if err := client.Unreact("1", "2", "<:yes:9>"); err != nil {
+		t.Fatalf("Unreact: %v", err)
+	}
+	want := []string{
+		"PUT /channels/1/messages/2/reactions/%F0%9F%91%8D/@me",
+		"DELETE /channels/1/messages/2/reactions/yes:9/@me",
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestFormatReactions(t *testing.T) {
+	var msg Message
+	if err := json.Unmarshal([]byte(`{"id":"1","reactions":[
+		{"count":3,"me":true,"emoji":{"id":null,"name":"👍"}},
+		{"count":1,"me":false,"emoji":{"id":"9","name":"party","animated":true}}
+	]}`), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := FormatReactions(msg.Reactions), "👍 3  :party: 1"; got != want {
+		t.Errorf("FormatReactions = %q, want %q", got, want)
+	}
+}
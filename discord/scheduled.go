@@ -0,0 +1,153 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Scheduled event statuses.
+const (
+	EventScheduled = 1
+	EventActive    = 2
+	EventCompleted = 3
+	EventCanceled  = 4
+)
+
+// Scheduled event entity types: where the event takes place.
+const (
+	EntityStage    = 1
+	EntityVoice    = 2
+	EntityExternal = 3
+)
+
+// ScheduledEvent is a guild's native scheduled event, as listed in the
+// server's Events tab.
+type ScheduledEvent struct {
+	ID             string         `json:"id"`
+	GuildID        string         `json:"guild_id"`
+	ChannelID      string         `json:"channel_id"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description"`
+	Start          time.Time      `json:"scheduled_start_time"`
+	End            *time.Time     `json:"scheduled_end_time"`
+	Status         int            `json:"status"`
+	EntityType     int            `json:"entity_type"`
+	EntityMetadata *EventMetadata `json:"entity_metadata"`
+}
+
+// EventMetadata holds the location of an event that isn't in a voice or
+// stage channel.
+type EventMetadata struct {
+	Location string `json:"location"`
+}
+
+// Location returns where an external event takes place, or "" for events
+// in a voice or stage channel.
+func (e *ScheduledEvent) Location() string {
+	if e.EntityMetadata == nil {
+		return ""
+	}
+	return e.EntityMetadata.Location
+}
+
+// URL returns the event's link in the Discord client.
+func (e *ScheduledEvent) URL() string {
+	return "https://discord.com/events/" + e.GuildID + "/" + e.ID
+}
+
+// ScheduledEvents returns a guild's scheduled events. Discord lists
+// upcoming and active events; completed and canceled ones drop out of the
+// listing soon after.
+func (c *Client) ScheduledEvents(guildID string) ([]ScheduledEvent, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	body, err := c.botGet(c.baseURL + "/guilds/" + guildID + "/scheduled-events")
+	if err != nil {
+		return nil, err
+	}
+	var events []ScheduledEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return events, nil
+}
+
+// EventParams describes a scheduled event to create or change. With a
+// ChannelID the event is in that voice channel; otherwise it is an
+// external event, for which Discord requires a Location and an End.
+type EventParams struct {
+	Name        string
+	Description string
+	Start       time.Time
+	End         *time.Time
+	Location    string
+	ChannelID   string
+}
+
+// payload returns the request body for p.
+func (p *EventParams) payload() ([]byte, error) {
+	body := map[string]any{
+		"name":                 p.Name,
+		"description":          p.Description,
+		"scheduled_start_time": p.Start.UTC().Format(time.RFC3339),
+		"privacy_level":        2, // guild only, the one level Discord allows
+	}
+	if p.End != nil {
+		body["scheduled_end_time"] = p.End.UTC().Format(time.RFC3339)
+	}
+	if p.ChannelID != "" {
+		body["entity_type"] = EntityVoice
+		body["channel_id"] = p.ChannelID
+	} else {
+		if p.Location == "" || p.End == nil {
+			return nil, fmt.Errorf("an event outside a voice channel needs a location and an end time")
+		}
+		body["entity_type"] = EntityExternal
+		body["channel_id"] = nil
+		body["entity_metadata"] = EventMetadata{Location: p.Location}
+	}
+	return json.Marshal(body)
+}
+
+// CreateScheduledEvent creates a scheduled event in a guild. The bot needs
+// the Manage Events permission.
+func (c *Client) CreateScheduledEvent(guildID string, p *EventParams) (*ScheduledEvent, error) {
+	return c.writeScheduledEvent(http.MethodPost, c.baseURL+"/guilds/"+guildID+"/scheduled-events", p)
+}
+
+// ModifyScheduledEvent replaces the details of a scheduled event with p.
+// Discord only allows changing the time of events that haven't started.
+func (c *Client) ModifyScheduledEvent(guildID, eventID string, p *EventParams) (*ScheduledEvent, error) {
+	return c.writeScheduledEvent(http.MethodPatch, c.baseURL+"/guilds/"+guildID+"/scheduled-events/"+eventID, p)
+}
+
+func (c *Client) writeScheduledEvent(method, url string, p *EventParams) (*ScheduledEvent, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	payload, err := p.payload()
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.botDo(method, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	var e ScheduledEvent
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &e, nil
+}
+
+// DeleteScheduledEvent deletes a scheduled event.
+func (c *Client) DeleteScheduledEvent(guildID, eventID string) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	_, err := c.botDo(http.MethodDelete, c.baseURL+"/guilds/"+guildID+"/scheduled-events/"+eventID, nil)
+	return err
+}
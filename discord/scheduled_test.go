@@ -0,0 +1,95 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScheduledEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/guilds/456/scheduled-events" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[
+			{"id":"1","guild_id":"456","name":"Game night","scheduled_start_time":"2026-03-06T19:00:00+00:00","status":1,"channel_id":"77"},
+			{"id":"2","guild_id":"456","name":"Meetup","scheduled_start_time":"2026-03-07T12:00:00+00:00","scheduled_end_time":"2026-03-07T15:00:00+00:00","status":1,"entity_metadata":{"location":"Main St Cafe"}}
+		]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "", WithBaseURL(srv.URL))
+	events, err := client.ScheduledEvents("456")
+	if err != nil {
+		t.Fatalf("ScheduledEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if e := events[0]; e.Name != "Game night" || e.End != nil || e.Location() != "" || !e.Start.Equal(time.Date(2026, 3, 6, 19, 0, 0, 0, time.UTC)) {
+		t.Errorf("events[0] = %+v", e)
+	}
+	if e := events[1]; e.Location() != "Main St Cafe" || e.End == nil || e.URL() != "https://discord.com/events/456/2" {
+		t.Errorf("events[1] = %+v", e)
+	}
+}
+
+func TestWriteScheduledEvent(t *testing.T) {
+	var got []map[string]any
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, body)
+		_, _ = w.Write([]byte(`{"id":"9","guild_id":"456","name":"Meetup","scheduled_start_time":"2026-03-07T12:00:00+00:00","status":1,"entity_type":3}`))
+	}))
+	defer srv.Close()
+	client := NewClient("test-token", "", WithBaseURL(srv.URL))
+
+	start := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+	if _, err := client.CreateScheduledEvent("456", &EventParams{Name: "Meetup", Start: start}); err == nil {
+		t.Error("external event without location and end: want error")
+	}
+	e, err := client.CreateScheduledEvent("456", &EventParams{Name: "Meetup", Start: start, End: &end, Location: "Main St Cafe"})
+	if err != nil {
+		t.Fatalf("CreateScheduledEvent: %v", err)
+	}
+	if e.ID != "9" || e.EntityType != EntityExternal {
+		t.Errorf("created = %+v", e)
+	}
+	if _, err := client.ModifyScheduledEvent("456", "9", &EventParams{Name: "Voice chat", Start: start, ChannelID: "77"}); err != nil {
+		t.Fatalf("ModifyScheduledEvent: %v", err)
+	}
+	if err := client.DeleteScheduledEvent("456", "9"); err != nil {
+		t.Fatalf("DeleteScheduledEvent: %v", err)
+	}
+
+	wantMethods := []string{
+		"POST /guilds/456/scheduled-events",
+		"PATCH /guilds/456/scheduled-events/9",
+		"DELETE /guilds/456/scheduled-events/9",
+	}
+	if !reflect.DeepEqual(methods, wantMethods) {
+		t.Errorf("requests = %q, want %q", methods, wantMethods)
+	}
+	create := got[0]
+	if create["entity_type"] != float64(EntityExternal) || create["scheduled_end_time"] != "2026-03-07T15:00:00Z" ||
+		create["entity_metadata"].(map[string]any)["location"] != "Main St Cafe" {
+		t.Errorf("create body = %v", create)
+	}
+	modify := got[1]
+	if modify["entity_type"] != float64(EntityVoice) || modify["channel_id"] != "77" {
+		t.Errorf("modify body = %v", modify)
+	}
+}
@@ -0,0 +1,75 @@
+package discord
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is the most characters Discord accepts in a message.
+const MaxMessageLength = 2000
+
+// SplitMessage breaks text into messages of at most limit characters,
+// preferring to break between paragraphs, then lines, then words. A code
+// block that spans a break is closed at the end of one message and opened
+// again at the start of the next, so each message renders on its own. Text
+// that fits is returned as it is.
+func SplitMessage(text string, limit int) []string {
+	const fence = "```"
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+	var msgs []string
+	inFence := false
+	for text != "" {
+		prefix := ""
+		if inFence {
+			prefix = fence + "\n"
+		}
+		room := limit - utf8.RuneCountInString(prefix)
+		if utf8.RuneCountInString(text) <= room {
+			msgs = append(msgs, prefix+text)
+			break
+		}
+		// Leave room to close a code block.
+		head := prefixRunes(text, room-len(fence)-1)
+		cut, skip := len(head), 0
+		for _, sep := range []string{"\n\n", "\n", " "} {
+			if i := strings.LastIndex(head, sep); i > 0 {
+				cut, skip = i, len(sep)
+				break
+			}
+		}
+		chunk := text[:cut]
+		text = text[cut+skip:]
+		if fences(chunk)%2 == 1 {
+			inFence = !inFence
+		}
+		if inFence {
+			chunk += "\n" + fence
+		}
+		msgs = append(msgs, prefix+chunk)
+	}
+	return msgs
+}
+
+// prefixRunes returns the first n runes of s.
+func prefixRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// fences counts the lines of s that open or close a code block.
+func fences(s string) int {
+	n := 0
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,52 @@
+package discord
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"fits", "deploy finished", []string{"deploy finished"}},
+		{"paragraphs", "first para\n\nsecond line two", []string{"first para", "second line two"}},
+		{"lines", "one two\nthree four five", []string{"one two", "three four five"}},
+		{"words", "alpha beta gamma delta", []string{"alpha beta", "gamma delta"}},
+		{"long word", "abcdefghijklmnopqrstuvwxyz", []string{"abcdefghijklmn", "opqrstuvwxyz"}},
+		{"runes", "ääääääääääääääääääää", []string{"ääääääääääääää", "ääääää"}},
+		{
+			"code block",
+			"```\nline one\nline two\n```",
+			[]string{"```\nline one\n```", "```\nline two\n```"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitMessage(tt.in, 18)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitMessage(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMessageLimit(t *testing.T) {
+	text := strings.Repeat("status: all green, nothing to report\n", 200) + "```\n" + strings.Repeat("x ", 3000) + "\n```"
+	msgs := SplitMessage(text, MaxMessageLength)
+	if len(msgs) < 2 {
+		t.Fatalf("got %d messages, want several", len(msgs))
+	}
+	for i, m := range msgs {
+		if n := utf8.RuneCountInString(m); n > MaxMessageLength {
+			t.Errorf("message %d is %d characters", i, n)
+		}
+		if fences(m)%2 != 0 {
+			t.Errorf("message %d leaves a code block open", i)
+		}
+	}
+}
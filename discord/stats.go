@@ -0,0 +1,186 @@
+package discord
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stats summarizes a window of channel messages.
+type Stats struct {
+	Total   int
+	First   time.Time // oldest message with a parseable timestamp
+	Last    time.Time // newest message with a parseable timestamp
+	Authors []Count   // messages per author, most active first
+	Emoji   []Count   // emoji uses, most used first
+	Hours   [24]int   // messages per hour of day
+}
+
+// Count pairs a name with how often it occurred.
+type Count struct {
+	Name string
+	N    int
+}
+
+// customEmoji matches Discord custom emoji such as <:pylon:1234> or
+// animated <a:wave:5678>; they are counted by name.
+var customEmoji = regexp.MustCompile(`<a?:(\w+):\d+>`)
+
+// ComputeStats tallies authors, hour-of-day activity (in loc) and emoji use
+// across msgs.
+func ComputeStats(msgs []Message, loc *time.Location) Stats {
+	s := Stats{Total: len(msgs)}
+	authors := map[string]int{}
+	emoji := map[string]int{}
+
+	for _, m := range msgs {
+		authors[m.Author.DisplayName()]++
+
+		if ts, err := time.Parse(time.RFC3339, m.Timestamp); err == nil {
+			ts = ts.In(loc)
+			s.Hours[ts.Hour()]++
+			if s.First.IsZero() || ts.Before(s.First) {
+				s.First = ts
+			}
+			if ts.After(s.Last) {
+				s.Last = ts
+			}
+		}
+
+		for _, e := range customEmoji.FindAllStringSubmatch(m.Content, -1) {
+			emoji[":"+e[1]+":"]++
+		}
+		for _, e := range unicodeEmoji(customEmoji.ReplaceAllString(m.Content, "")) {
+			emoji[e]++
+		}
+	}
+
+	s.Authors = sortCounts(authors)
+	s.Emoji = sortCounts(emoji)
+	return s
+}
+
+// sortCounts orders counts by frequency, then name for stable output.
+func sortCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for name, n := range m {
+		counts = append(counts, Count{name, n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].N != counts[j].N {
+			return counts[i].N > counts[j].N
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// unicodeEmoji extracts emoji from s. Sequences joined with a zero-width
+// joiner (e.g. family or profession emoji) count as one; variation
+// selectors and skin-tone modifiers are kept with their base.
+func unicodeEmoji(s string) []string {
+	const (
+		zwj       = '\u200d'
+		variation = '\ufe0f'
+	)
+	var out []string
+	var cur []rune
+	joining := false
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = nil
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == zwj && len(cur) > 0:
+			cur = append(cur, r)
+			joining = true
+		case r == variation || isSkinTone(r):
+			if len(cur) > 0 {
+				cur = append(cur, r)
+			}
+		case isRegional(r) && len(cur) == 1 && isRegional(cur[0]):
+			// Two regional indicators form one flag.
+			cur = append(cur, r)
+		case isEmoji(r):
+			if !joining {
+				flush()
+			}
+			cur = append(cur, r)
+			joining = false
+		default:
+			flush()
+			joining = false
+		}
+	}
+	flush()
+	return out
+}
+
+func isSkinTone(r rune) bool { return r >= 0x1F3FB && r <= 0x1F3FF }
+
+func isRegional(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F300 && r <= 0x1FAFF: // pictographs, emoticons, transport, symbols
+		return !isSkinTone(r)
+	case r >= 0x2600 && r <= 0x27BF: // misc symbols, dingbats
+		return true
+	case isRegional(r):
+		return true
+	}
+	return false
+}
+
+// FormatStats renders s as a plain-text report. top limits the author and
+// emoji lists; 0 means no limit.
+func FormatStats(s Stats, top int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Messages: %d\n", s.Total)
+	if !s.First.IsZero() {
+		fmt.Fprintf(&sb, "Window:   %s to %s\n", s.First.Format("2006-01-02 15:04"), s.Last.Format("2006-01-02 15:04"))
+	}
+
+	sb.WriteString("\nBy author:\n")
+	writeCounts(&sb, s.Authors, top)
+
+	sb.WriteString("\nBy hour:\n")
+	peak := 0
+	for _, n := range s.Hours {
+		peak = max(peak, n)
+	}
+	const width = 40
+	for h, n := range s.Hours {
+		bar := 0
+		if peak > 0 {
+			bar = (n*width + peak - 1) / peak
+		}
+		fmt.Fprintf(&sb, "  %02d  %-*s %d\n", h, width, strings.Repeat("#", bar), n)
+	}
+
+	sb.WriteString("\nTop emoji:\n")
+	if len(s.Emoji) == 0 {
+		sb.WriteString("  (none)\n")
+	} else {
+		writeCounts(&sb, s.Emoji, top)
+	}
+	return sb.String()
+}
+
+func writeCounts(sb *strings.Builder, counts []Count, top int) {
+	if top > 0 && len(counts) > top {
+		counts = counts[:top]
+	}
+	w := 0
+	for _, c := range counts {
+		w = max(w, len([]rune(c.Name)))
+	}
+	for _, c := range counts {
+		fmt.Fprintf(sb, "  %-*s %d\n", w, c.Name, c.N)
+	}
+}
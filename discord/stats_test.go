@@ -0,0 +1,98 @@
+package discord
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	msgs := []Message{
+		{Timestamp: "2026-02-18T09:15:00.000000+00:00", Content: "morning 👋", Author: Author{Username: "alice", GlobalName: "Alice"}},
+		{Timestamp: "2026-02-18T09:45:00.000000+00:00", Content: "👋👋 <:pylon:1234>", Author: Author{Username: "bob"}},
+		{Timestamp: "2026-02-18T14:00:00.000000+00:00", Content: "ship it 🚀 <a:party:99>", Author: Author{Username: "alice", GlobalName: "Alice"}},
+		{Timestamp: "not a time", Content: "👍🏽 ❤️", Author: Author{Username: "carol"}},
+	}
+
+	s := ComputeStats(msgs, time.UTC)
+
+	if s.Total != 4 {
+		t.Errorf("Total = %d, want 4", s.Total)
+	}
+	wantAuthors := []Count{{"Alice", 2}, {"bob", 1}, {"carol", 1}}
+	if !reflect.DeepEqual(s.Authors, wantAuthors) {
+		t.Errorf("Authors = %v, want %v", s.Authors, wantAuthors)
+	}
+	if s.Hours[9] != 2 || s.Hours[14] != 1 {
+		t.Errorf("Hours[9]=%d Hours[14]=%d, want 2 and 1", s.Hours[9], s.Hours[14])
+	}
+	wantEmoji := []Count{{"👋", 3}, {":party:", 1}, {":pylon:", 1}, {"❤️", 1}, {"👍🏽", 1}, {"🚀", 1}}
+	if !reflect.DeepEqual(s.Emoji, wantEmoji) {
+		t.Errorf("Emoji = %v, want %v", s.Emoji, wantEmoji)
+	}
+	if got := s.First.Format(time.RFC3339); got != "2026-02-18T09:15:00Z" {
+		t.Errorf("First = %s", got)
+	}
+	if got := s.Last.Format(time.RFC3339); got != "2026-02-18T14:00:00Z" {
+		t.Errorf("Last = %s", got)
+	}
+}
+
+func TestComputeStatsLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*3600)
+	s := ComputeStats([]Message{{Timestamp: "2026-02-18T03:00:00Z"}}, loc)
+	if s.Hours[22] != 1 {
+		t.Errorf("expected message in hour 22 local, got %v", s.Hours)
+	}
+}
+
+func TestUnicodeEmoji(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"no emoji here", nil},
+		{"a🎉b🎉", []string{"🎉", "🎉"}},
+		{"👩‍💻 coding", []string{"👩‍💻"}},
+		{"🇩🇪", []string{"🇩🇪"}},
+		{"✅ done", []string{"✅"}},
+	}
+	for _, tt := range tests {
+		if got := unicodeEmoji(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("unicodeEmoji(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatStats(t *testing.T) {
+	s := Stats{
+		Total:   3,
+		Authors: []Count{{"Alice", 2}, {"bob", 1}},
+		Emoji:   []Count{{"🚀", 4}, {"👋", 1}},
+	}
+	s.Hours[9] = 2
+	s.Hours[10] = 1
+
+	out := FormatStats(s, 1)
+	for _, want := range []string{
+		"Messages: 3\n",
+		"  Alice 2\n",
+		"  09  " + strings.Repeat("#", 40) + " 2\n",
+		"  10  " + strings.Repeat("#", 20) + strings.Repeat(" ", 20) + " 1\n",
+		"  🚀 4\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "bob") || strings.Contains(out, "👋") {
+		t.Errorf("top=1 should drop the rest:\n%s", out)
+	}
+	if strings.Contains(out, "Window:") {
+		t.Errorf("no window expected without timestamps:\n%s", out)
+	}
+	if !strings.Contains(FormatStats(Stats{}, 0), "Top emoji:\n  (none)\n") {
+		t.Error("expected (none) for empty emoji list")
+	}
+}
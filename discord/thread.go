@@ -0,0 +1,103 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// publicThread is Discord's channel type for a public thread.
+const publicThread = 11
+
+// autoArchiveMinutes are the inactivity timeouts Discord accepts for threads.
+var autoArchiveMinutes = []int{60, 1440, 4320, 10080}
+
+// CreateThread starts a public thread in channelID. Discord hides the
+// thread after autoArchive of inactivity; the value is rounded up to one
+// Discord accepts (1h, 1d, 3d or 1w). The thread is returned as a Channel.
+func (c *Client) CreateThread(channelID, name string, autoArchive time.Duration) (*Channel, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID required")
+	}
+
+	minutes := autoArchiveMinutes[len(autoArchiveMinutes)-1]
+	for _, m := range autoArchiveMinutes {
+		if time.Duration(m)*time.Minute >= autoArchive {
+			minutes = m
+			break
+		}
+	}
+	payload, err := json.Marshal(map[string]any{
+		"name":                  name,
+		"type":                  publicThread,
+		"auto_archive_duration": minutes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/threads", c.baseURL, channelID)
+	body, err := c.botDo(http.MethodPost, url, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var thread Channel
+	if err := json.Unmarshal(body, &thread); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &thread, nil
+}
+
+// ArchiveThread archives a thread so it drops out of the channel's active
+// list. It can still be read and is unarchived if someone posts in it.
+func (c *Client) ArchiveThread(threadID string) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if threadID == "" {
+		return fmt.Errorf("thread ID required")
+	}
+	url := fmt.Sprintf("%s/channels/%s", c.baseURL, threadID)
+	_, err := c.botDo(http.MethodPatch, url, []byte(`{"archived":true}`))
+	return err
+}
+
+// ListActiveThreads returns the guild's active (unarchived) threads the bot
+// can see. With a non-empty channelID only threads started in that channel
+// are returned.
+func (c *Client) ListActiveThreads(guildID, channelID string) ([]Channel, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if guildID == "" {
+		return nil, fmt.Errorf("guild ID required")
+	}
+
+	url := fmt.Sprintf("%s/guilds/%s/threads/active", c.baseURL, guildID)
+	body, err := c.botGet(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Threads []Channel `json:"threads"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	if channelID == "" {
+		return resp.Threads, nil
+	}
+	var out []Channel
+	for _, th := range resp.Threads {
+		if th.ParentID == channelID {
+			out = append(out, th)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,141 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateThread(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoArchive time.Duration
+		wantMinutes float64
+	}{
+		{name: "exact", autoArchive: time.Hour, wantMinutes: 60},
+		{name: "rounds up", autoArchive: 2 * time.Hour, wantMinutes: 1440},
+		{name: "capped at a week", autoArchive: 30 * 24 * time.Hour, wantMinutes: 10080},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/channels/chan-1/threads" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode body: %v", err)
+				}
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":"thread-1","name":"Standup","type":11}`))
+			}))
+			defer srv.Close()
+
+			client := NewClient("test-token", "")
+			client.baseURL = srv.URL
+			thread, err := client.CreateThread("chan-1", "Standup", tt.autoArchive)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if thread.ID != "thread-1" {
+				t.Errorf("expected thread ID %q, got %q", "thread-1", thread.ID)
+			}
+			if body["name"] != "Standup" || body["type"] != float64(publicThread) {
+				t.Errorf("unexpected payload %v", body)
+			}
+			if body["auto_archive_duration"] != tt.wantMinutes {
+				t.Errorf("expected auto_archive_duration %v, got %v", tt.wantMinutes, body["auto_archive_duration"])
+			}
+		})
+	}
+}
+
+func TestArchiveThread(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/channels/thread-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"id":"thread-1"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	if err := client.ArchiveThread("thread-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body["archived"] != true {
+		t.Errorf("expected archived=true, got %v", body)
+	}
+	if err := NewClient("", "").ArchiveThread("thread-1"); err == nil {
+		t.Error("expected error without bot token")
+	}
+}
+
+func TestListActiveThreads(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/guilds/guild-1/threads/active" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"threads":[
+			{"id":"t1","name":"Standup","type":11,"parent_id":"chan-1","message_count":4},
+			{"id":"t2","name":"Launch","type":11,"parent_id":"chan-2"}
+		],"members":[]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+
+	all, err := client.ListActiveThreads("guild-1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected 2 threads, got %d", len(all))
+	}
+
+	threads, err := client.ListActiveThreads("guild-1", "chan-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(threads) != 1 || threads[0].ID != "t1" || threads[0].MessageCount != 4 {
+		t.Errorf("unexpected threads %+v", threads)
+	}
+}
+
+func TestSendThreadMessage(t *testing.T) {
+	var query string
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := NewClient("", srv.URL+"/api/webhooks/1/abc?wait=false")
+	if err := client.SendThreadMessage("thread-1", "notes are up", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "thread_id=thread-1&wait=false" {
+		t.Errorf("unexpected query %q", query)
+	}
+	if body["content"] != "notes are up" {
+		t.Errorf("unexpected payload %v", body)
+	}
+
+	if err := client.SendMessage("no thread"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query != "wait=false" {
+		t.Errorf("plain send changed the query: %q", query)
+	}
+}
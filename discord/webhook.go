@@ -0,0 +1,90 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookBase is where Discord serves webhook URLs.
+const webhookBase = "https://discord.com/api/webhooks"
+
+// IncomingWebhook is Discord's type for a webhook that posts messages sent
+// to its URL, the kind pylon sends through. The others (channel followers
+// and application webhooks) have no URL.
+const IncomingWebhook = 1
+
+// URL returns the URL that posts through the webhook, or "" when Token is
+// not known.
+func (w *WebhookInfo) URL() string {
+	if w.Token == "" {
+		return ""
+	}
+	return webhookBase + "/" + w.ID + "/" + w.Token
+}
+
+// CreateWebhook creates an incoming webhook in channelID. The bot needs the
+// Manage Webhooks permission there; the returned webhook has its Token, so
+// URL gives the URL to send through. Discord refuses names containing
+// "clyde" or "discord".
+func (c *Client) CreateWebhook(channelID, name string) (*WebhookInfo, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID required")
+	}
+	if name == "" {
+		return nil, fmt.Errorf("webhook name required")
+	}
+	payload, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/webhooks", c.baseURL, channelID)
+	body, err := c.botDo(http.MethodPost, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	var w WebhookInfo
+	if err := json.Unmarshal(body, &w); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return &w, nil
+}
+
+// ChannelWebhooks lists the webhooks of channelID. The bot needs the Manage
+// Webhooks permission there.
+func (c *Client) ChannelWebhooks(channelID string) ([]WebhookInfo, error) {
+	if c.botToken == "" {
+		return nil, fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID required")
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/webhooks", c.baseURL, channelID)
+	body, err := c.botGet(url)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []WebhookInfo
+	if err := json.Unmarshal(body, &hooks); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return hooks, nil
+}
+
+// DeleteWebhook deletes a webhook, after which its URL stops working.
+func (c *Client) DeleteWebhook(webhookID string) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_DISCORD_BOT_TOKEN)")
+	}
+	if webhookID == "" {
+		return fmt.Errorf("webhook ID required")
+	}
+	url := fmt.Sprintf("%s/webhooks/%s", c.baseURL, webhookID)
+	_, err := c.botDo(http.MethodDelete, url, nil)
+	return err
+}
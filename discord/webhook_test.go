@@ -0,0 +1,92 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/channels/chan-1/webhooks" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bot test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"id":"hook-1","type":1,"name":"pylon","channel_id":"chan-1","token":"s3cret"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	hook, err := client.CreateWebhook("chan-1", "pylon")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body["name"] != "pylon" {
+		t.Errorf("unexpected payload %v", body)
+	}
+	if want := "https://discord.com/api/webhooks/hook-1/s3cret"; hook.URL() != want {
+		t.Errorf("URL() = %q, want %q", hook.URL(), want)
+	}
+}
+
+func TestChannelWebhooks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/channels/chan-1/webhooks" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[
+			{"id":"hook-1","type":1,"name":"pylon","channel_id":"chan-1","token":"s3cret"},
+			{"id":"hook-2","type":2,"name":"announcements","channel_id":"chan-1"}
+		]`))
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	hooks, err := client.ChannelWebhooks("chan-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hooks) != 2 {
+		t.Fatalf("expected 2 webhooks, got %d", len(hooks))
+	}
+	if hooks[0].Type != IncomingWebhook || hooks[0].URL() == "" {
+		t.Errorf("first webhook = %+v", hooks[0])
+	}
+	if hooks[1].URL() != "" {
+		t.Errorf("follower webhook URL = %q, want none", hooks[1].URL())
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/webhooks/hook-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test-token", "")
+	client.baseURL = srv.URL
+	if err := client.DeleteWebhook("hook-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected a DELETE request")
+	}
+
+	if err := NewClient("", "").DeleteWebhook("hook-1"); err == nil {
+		t.Error("expected an error without a bot token")
+	}
+}
@@ -0,0 +1,231 @@
+// Package agenda builds the day-by-day view of upcoming events printed by
+// `pylon cal agenda`.
+package agenda
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+	"github.com/jredh-dev/pylon/internal/recur"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// Entry is one line of the agenda: an event, or an event's deadline.
+type Entry struct {
+	Event    cal.Event
+	At       time.Time // when the entry sorts; the deadline for deadline entries
+	Deadline bool
+	Ongoing  bool // started before the agenda window
+}
+
+// Day groups the entries falling on one calendar day.
+type Day struct {
+	Date    time.Time // midnight in the agenda's location
+	Entries []Entry
+}
+
+// Build returns the days from today through days-1 days ahead (in loc) that
+// have at least one entry. Events in progress at the start of today are
+// listed under today; deadlines get an entry of their own on the day they
+// fall due. Recurring events appear once per occurrence; cancelled events
+// are left out.
+func Build(events []cal.Event, now time.Time, days int, loc *time.Location) []Day {
+	from := timeutil.StartOfDay(now, loc)
+	to := timeutil.AddDays(from, days)
+	events = recur.ExpandAll(events, from, to)
+
+	byDay := map[int][]Entry{}
+	add := func(at time.Time, e Entry) {
+		if at.Before(from) || !at.Before(to) {
+			return
+		}
+		n := timeutil.DaysBetween(from, at, loc)
+		byDay[n] = append(byDay[n], e)
+	}
+
+	for _, e := range events {
+		if strings.EqualFold(e.Status, "cancelled") {
+			continue
+		}
+		start := e.Start
+		if e.AllDay {
+			// All-day dates are calendar dates; don't let the zone they were
+			// stored in shift them to a neighbouring day.
+			y, m, d := e.Start.Date()
+			start = time.Date(y, m, d, 0, 0, 0, 0, loc)
+		}
+		if start.Before(from) && e.End != nil && e.End.After(from) {
+			add(from, Entry{Event: e, At: start, Ongoing: true})
+		} else {
+			add(start, Entry{Event: e, At: start})
+		}
+		if e.Deadline != nil {
+			add(*e.Deadline, Entry{Event: e, At: *e.Deadline, Deadline: true})
+		}
+	}
+
+	var out []Day
+	for n := range days {
+		entries := byDay[n]
+		if len(entries) == 0 {
+			continue
+		}
+		// All-day and ongoing entries first, then by time.
+		sort.SliceStable(entries, func(i, j int) bool {
+			a, b := entries[i], entries[j]
+			if fa, fb := a.Event.AllDay || a.Ongoing, b.Event.AllDay || b.Ongoing; fa != fb {
+				return fa
+			}
+			return a.At.Before(b.At)
+		})
+		out = append(out, Day{Date: timeutil.AddDays(from, n), Entries: entries})
+	}
+	return out
+}
+
+// Format renders days as text. Times are shown in loc along with how far
+// they are from now; feeds maps feed IDs to names and, when it has more than
+// one entry, each line is tagged with its feed. Labels and dates are in
+// the active i18n language.
+func Format(days []Day, now time.Time, loc *time.Location, feeds map[string]string) string {
+	today := timeutil.StartOfDay(now, loc)
+	var sb strings.Builder
+	for i, d := range days {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(dayLabel(d.Date, today, loc) + "\n")
+		for _, e := range d.Entries {
+			when, rel := entryTimes(e, now, loc)
+			summary := e.Event.Summary
+			if e.Deadline {
+				summary = i18n.T("agenda.deadline", summary)
+			}
+			if len(feeds) > 1 {
+				if name := feeds[e.Event.FeedID]; name != "" {
+					summary += " [" + name + "]"
+				}
+			}
+			line := fmt.Sprintf("  %-13s %s", when, summary)
+			if rel != "" {
+				line += "  (" + rel + ")"
+			}
+			sb.WriteString(line + "\n")
+			if e.Event.Location != "" && !e.Deadline {
+				fmt.Fprintf(&sb, "  %-13s @ %s\n", "", e.Event.Location)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Pins returns the current pins among events, most recently pinned first,
+// and the other events. Cancelled and expired pins are dropped.
+func Pins(events []cal.Event, now time.Time) (pins, rest []cal.Event) {
+	for _, e := range events {
+		if !e.Pinned() {
+			rest = append(rest, e)
+			continue
+		}
+		if !strings.EqualFold(e.Status, "cancelled") && !e.Expired(now) {
+			pins = append(pins, e)
+		}
+	}
+	sort.SliceStable(pins, func(i, j int) bool { return pins[i].Start.After(pins[j].Start) })
+	return pins, rest
+}
+
+// FormatPins renders pins as a "Pinned" section headed like a day of the
+// agenda, each with the date it was pinned and, if set, its until date.
+// feeds tags lines as in Format.
+func FormatPins(pins []cal.Event, feeds map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T("agenda.pinned") + "\n")
+	for _, e := range pins {
+		summary := e.Summary
+		if len(feeds) > 1 {
+			if name := feeds[e.FeedID]; name != "" {
+				summary += " [" + name + "]"
+			}
+		}
+		// Like all-day events, pins are dated by calendar date.
+		line := fmt.Sprintf("  %-13s %s", i18n.Day(e.Start), summary)
+		if e.End != nil {
+			line += "  (" + i18n.T("agenda.until", i18n.Day(e.End.AddDate(0, 0, -1))) + ")"
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+func dayLabel(date, today time.Time, loc *time.Location) string {
+	label := i18n.Day(date)
+	switch timeutil.DaysBetween(today, date, loc) {
+	case 0:
+		return i18n.T("agenda.today", label)
+	case 1:
+		return i18n.T("agenda.tomorrow", label)
+	}
+	return label
+}
+
+// entryTimes returns the time column and the relative time for e.
+func entryTimes(e Entry, now time.Time, loc *time.Location) (when, rel string) {
+	switch {
+	case e.Deadline:
+		return e.At.In(loc).Format("15:04"), Relative(e.At, now)
+	case e.Event.AllDay:
+		return i18n.T("agenda.all_day"), ""
+	case e.Ongoing:
+		when = i18n.T("agenda.ongoing")
+	default:
+		when = e.At.In(loc).Format("15:04")
+		if e.Event.End != nil && e.Event.End.After(e.At) {
+			end := e.Event.End.In(loc)
+			if timeutil.DaysBetween(e.At, *e.Event.End, loc) > 0 {
+				when += "–" + i18n.Weekday(end.Weekday())
+			} else {
+				when += "–" + end.Format("15:04")
+			}
+		}
+	}
+	if e.Event.End != nil && !now.Before(e.At) && now.Before(*e.Event.End) {
+		return when, "now, ends " + Relative(*e.Event.End, now)
+	}
+	return when, Relative(e.At, now)
+}
+
+// Relative describes t relative to now: "in 2h", "in 3d", "45m ago" or
+// "now" within the current minute.
+func Relative(t, now time.Time) string {
+	d := t.Sub(now).Round(time.Minute)
+	switch {
+	case d == 0:
+		return "now"
+	case d > 0:
+		return "in " + compact(d)
+	default:
+		return compact(-d) + " ago"
+	}
+}
+
+// compact formats a positive duration with its two largest units ("2d3h",
+// "1h30m", "45m").
+func compact(d time.Duration) string {
+	days, h, m := int(d/(24*time.Hour)), int(d.Hours())%24, int(d.Minutes())%60
+	switch {
+	case days > 0 && h > 0:
+		return fmt.Sprintf("%dd%dh", days, h)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case h > 0 && m > 0:
+		return fmt.Sprintf("%dh%dm", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dm", m)
+}
@@ -0,0 +1,169 @@
+package agenda
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func at(t time.Time) *time.Time { return &t }
+
+func TestBuild(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, loc)
+
+	events := []cal.Event{
+		{ID: "standup", Summary: "Standup", Start: time.Date(2026, 3, 2, 9, 0, 0, 0, loc), End: at(time.Date(2026, 3, 2, 9, 15, 0, 0, loc))},
+		{ID: "review", Summary: "Review", Start: time.Date(2026, 3, 3, 14, 0, 0, 0, loc)},
+		{ID: "offsite", Summary: "Offsite", Start: time.Date(2026, 2, 27, 9, 0, 0, 0, loc), End: at(time.Date(2026, 3, 4, 17, 0, 0, 0, loc))},
+		// Stored at UTC midnight; must still land on 3 March.
+		{ID: "launch", Summary: "Launch", AllDay: true, Start: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: "report", Summary: "Report", Start: time.Date(2026, 2, 1, 9, 0, 0, 0, loc), Deadline: at(time.Date(2026, 3, 2, 17, 0, 0, 0, loc))},
+		{ID: "cancelled", Summary: "Cancelled", Status: "CANCELLED", Start: time.Date(2026, 3, 2, 12, 0, 0, 0, loc)},
+		{ID: "later", Summary: "Later", Start: time.Date(2026, 3, 9, 9, 0, 0, 0, loc)},
+		{ID: "past", Summary: "Past", Start: time.Date(2026, 3, 1, 9, 0, 0, 0, loc)},
+	}
+
+	days := Build(events, now, 3, loc)
+	var got []string
+	for _, d := range days {
+		var ids []string
+		for _, e := range d.Entries {
+			id := e.Event.ID
+			if e.Deadline {
+				id += "!"
+			}
+			ids = append(ids, id)
+		}
+		got = append(got, d.Date.Format("01-02")+" "+strings.Join(ids, ","))
+	}
+	want := []string{
+		"03-02 offsite,standup,report!",
+		"03-03 launch,review",
+	}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("Build = %q, want %q", got, want)
+	}
+	if !days[0].Entries[0].Ongoing {
+		t.Error("offsite should be marked ongoing")
+	}
+}
+
+func TestBuildAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The agenda window spans the spring-forward change on 8 March; an event
+	// late on the last day must still be inside it.
+	now := time.Date(2026, 3, 7, 8, 0, 0, 0, ny)
+	events := []cal.Event{
+		{ID: "late", Start: time.Date(2026, 3, 8, 23, 30, 0, 0, ny)},
+		{ID: "next", Start: time.Date(2026, 3, 9, 0, 30, 0, 0, ny)},
+	}
+	days := Build(events, now, 2, ny)
+	if len(days) != 1 || len(days[0].Entries) != 1 || days[0].Entries[0].Event.ID != "late" {
+		t.Fatalf("Build = %+v, want only 'late' on 8 March", days)
+	}
+	if got := days[0].Date; !got.Equal(time.Date(2026, 3, 8, 0, 0, 0, 0, ny)) {
+		t.Errorf("day = %v, want 8 March", got)
+	}
+}
+
+func TestBuildRecurring(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, loc)
+	events := []cal.Event{{
+		ID:      "standup",
+		Start:   time.Date(2026, 2, 2, 9, 0, 0, 0, loc),
+		RRule:   "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		ExDates: []time.Time{time.Date(2026, 3, 4, 9, 0, 0, 0, loc)},
+	}}
+	var got []string
+	for _, d := range Build(events, now, 7, loc) {
+		got = append(got, d.Date.Format("Mon"))
+	}
+	if want := "Mon Fri"; strings.Join(got, " ") != want {
+		t.Errorf("days = %v, want %s", got, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, loc)
+	events := []cal.Event{
+		{FeedID: "a", Summary: "Standup", Location: "Room 1", Start: time.Date(2026, 3, 2, 9, 45, 0, 0, loc), End: at(time.Date(2026, 3, 2, 10, 15, 0, 0, loc))},
+		{FeedID: "b", Summary: "Report", Start: time.Date(2026, 2, 1, 9, 0, 0, 0, loc), Deadline: at(time.Date(2026, 3, 2, 17, 0, 0, 0, loc))},
+		{FeedID: "a", Summary: "Launch", AllDay: true, Start: time.Date(2026, 3, 3, 0, 0, 0, 0, loc)},
+		{FeedID: "a", Summary: "Review", Start: time.Date(2026, 3, 5, 14, 0, 0, 0, loc)},
+	}
+	got := Format(Build(events, now, 7, loc), now, loc, map[string]string{"a": "Team", "b": "Work"})
+	want := `Today, Mon 2 Mar
+  09:45–10:15   Standup [Team]  (now, ends in 15m)
+                @ Room 1
+  17:00         ⚠ DEADLINE: Report [Work]  (in 7h)
+
+Tomorrow, Tue 3 Mar
+  all day       Launch [Team]
+
+Thu 5 Mar
+  14:00         Review [Team]  (in 3d4h)
+`
+	if got != want {
+		t.Errorf("Format =\n%s\nwant\n%s", got, want)
+	}
+
+	// A single feed isn't worth tagging.
+	if got := Format(Build(events[:1], now, 1, loc), now, loc, map[string]string{"a": "Team"}); strings.Contains(got, "[Team]") {
+		t.Errorf("single feed tagged:\n%s", got)
+	}
+}
+
+func TestPins(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, loc)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, loc) }
+	events := []cal.Event{
+		{FeedID: "a", Summary: "Wifi password rotates Friday", Categories: "pin", AllDay: true, Start: day(2)},
+		{FeedID: "a", Summary: "Standup", Start: time.Date(2026, 3, 4, 9, 0, 0, 0, loc)},
+		{FeedID: "b", Summary: "Office closed", Categories: "team,pin", AllDay: true, Start: day(3), End: at(day(7))},
+		{FeedID: "a", Summary: "Old news", Categories: "pin", AllDay: true, Start: day(1), End: at(day(2))},
+		{FeedID: "a", Summary: "Retracted", Categories: "pin", Status: "CANCELLED", AllDay: true, Start: day(3)},
+	}
+
+	pins, rest := Pins(events, now)
+	if len(rest) != 1 || rest[0].Summary != "Standup" {
+		t.Errorf("rest = %+v, want only Standup", rest)
+	}
+	got := FormatPins(pins, map[string]string{"a": "Team", "b": "Office"})
+	want := `Pinned
+  Tue 3 Mar     Office closed [Office]  (until Fri 6 Mar)
+  Mon 2 Mar     Wifi password rotates Friday [Team]
+`
+	if got != want {
+		t.Errorf("FormatPins =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRelative(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 20 * time.Second, want: "now"},
+		{d: 45 * time.Minute, want: "in 45m"},
+		{d: 2 * time.Hour, want: "in 2h"},
+		{d: 90 * time.Minute, want: "in 1h30m"},
+		{d: 49 * time.Hour, want: "in 2d1h"},
+		{d: 72 * time.Hour, want: "in 3d"},
+		{d: -30 * time.Minute, want: "30m ago"},
+	}
+	for _, tt := range tests {
+		if got := Relative(now.Add(tt.d), now); got != tt.want {
+			t.Errorf("Relative(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
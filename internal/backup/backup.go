@@ -0,0 +1,243 @@
+// Package backup plans restoring a cal.Backup into a cal server. The
+// restore is idempotent: feeds and events already on the server are
+// matched to the backup rather than created again, so a restore can be
+// repeated, or resumed after a failure, without duplicating anything.
+package backup
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+// Target is the part of the cal client a restore writes through.
+type Target interface {
+	CreateFeed(name, slug string) (*cal.CreateFeedResponse, error)
+	UpdateFeed(id string, req *cal.UpdateFeedRequest) (*cal.Feed, error)
+	CreateEvent(req *cal.CreateEventRequest) (*cal.Event, error)
+	UpsertEvent(externalUID string, req *cal.CreateEventRequest) (*cal.Event, bool, error)
+	UpdateEvent(id string, req *cal.CreateEventRequest) (*cal.Event, error)
+}
+
+// Restore plans the changes that bring the server in line with b. feeds
+// are the server's feeds and events lists the events of one of them.
+//
+// A backed-up feed is matched to a feed on the server with the same ID,
+// or else the same token, and created with its token as the slug if there
+// is none, so subscribe URLs keep working. A matched feed whose name,
+// token or .ics settings differ is updated.
+//
+// Events are matched within their feed by ID, by external ID, or else by
+// the UID the feed publishes them under. Missing events are upserted by
+// their external ID, or "restore:<backed-up ID>" if they have none, so a
+// restore that is repeated finds them again even on servers that don't
+// keep the UID. They are created with the UID too, so subscribers'
+// calendar apps keep treating them as the same events where the server
+// does keep it. Events that differ from the backup are updated; events
+// that aren't in the backup are left alone.
+func Restore(t Target, b *cal.Backup, feeds []cal.Feed, events func(feedID string) ([]cal.Event, error)) (*plan.Plan, error) {
+	byID, byToken := map[string]cal.Feed{}, map[string]cal.Feed{}
+	for _, f := range feeds {
+		byID[f.ID] = f
+		byToken[f.Token] = f
+	}
+
+	p := &plan.Plan{}
+	for _, fb := range b.Feeds {
+		want := fb.Feed
+		cur, ok := byID[want.ID]
+		if !ok && want.Token != "" {
+			cur, ok = byToken[want.Token]
+		}
+		if !ok {
+			restoreFeed(t, p, fb)
+			continue
+		}
+
+		if diffs := plan.Fields(feedFields(cur), feedFields(want)); len(diffs) > 0 {
+			id := cur.ID
+			p.Add(plan.Change{
+				Action: plan.Update, Kind: "feed", ID: id, Title: cur.Name, Diffs: diffs,
+				Apply: func() error {
+					req := settings(want)
+					req.Name = want.Name
+					if want.Token != cur.Token {
+						req.Slug = want.Token
+					}
+					_, err := t.UpdateFeed(id, req)
+					return err
+				},
+			})
+		}
+		have, err := events(cur.ID)
+		if err != nil {
+			return nil, fmt.Errorf("list events for %s: %w", cur.ID, err)
+		}
+		feedID := cur.ID
+		restoreEvents(t, p, fb.Events, have, func() string { return feedID })
+	}
+	return p, nil
+}
+
+// Events plans restoring want, events saved from some feed such as those in
+// a cal.Archive, into the feed feedID, whose events are have. They are
+// matched and created as Restore does, so restoring them again finds them.
+func Events(t Target, feedID string, want, have []cal.Event) *plan.Plan {
+	p := &plan.Plan{}
+	restoreEvents(t, p, want, have, func() string { return feedID })
+	return p
+}
+
+// restoreFeed plans creating a feed that isn't on the server, and then its
+// events in it.
+func restoreFeed(t Target, p *plan.Plan, fb cal.FeedBackup) {
+	want := fb.Feed
+	var created string // the new feed's ID, once it exists
+	p.Add(plan.Change{
+		Action: plan.Create, Kind: "feed", ID: want.ID, Title: want.Name,
+		Apply: func() error {
+			f, err := t.CreateFeed(want.Name, want.Token)
+			if err != nil {
+				return err
+			}
+			created = f.ID
+			// A new feed has the default settings; only set others.
+			if feedFields(want) != (restoredFeed{Name: want.Name, Token: want.Token}) {
+				if _, err := t.UpdateFeed(f.ID, settings(want)); err != nil {
+					return fmt.Errorf("feed created, but its .ics settings weren't restored: %w", err)
+				}
+			}
+			return nil
+		},
+	})
+	restoreEvents(t, p, fb.Events, nil, func() string { return created })
+}
+
+// restoreEvents plans creating and updating events in the feed whose ID
+// feedID returns when the changes are applied.
+func restoreEvents(t Target, p *plan.Plan, want, have []cal.Event, feedID func() string) {
+	byID, byExt, byUID := map[string]*cal.Event{}, map[string]*cal.Event{}, map[string]*cal.Event{}
+	for i := range have {
+		byID[have[i].ID] = &have[i]
+		if have[i].ExternalID != "" {
+			byExt[have[i].ExternalID] = &have[i]
+		}
+		byUID[have[i].ICalUID()] = &have[i]
+	}
+
+	for _, w := range want {
+		key := restoreKey(&w)
+		cur, ok := byID[w.ID]
+		byKey := false
+		if !ok {
+			cur, ok = byExt[key]
+			byKey = ok
+		}
+		if !ok {
+			cur, ok = byUID[w.ICalUID()]
+		}
+		if !ok {
+			p.Add(plan.Change{
+				Action: plan.Create, Kind: "event", ID: w.ID, Title: w.Summary,
+				Apply: func() error {
+					id := feedID()
+					if id == "" {
+						return errors.New("its feed wasn't created")
+					}
+					req := w.CreateRequest(id)
+					req.UID = w.ICalUID()
+					_, _, err := t.UpsertEvent(key, req)
+					if errors.Is(err, cal.ErrNotSupported) {
+						_, err = t.CreateEvent(req)
+					}
+					return err
+				},
+			})
+			continue
+		}
+
+		if w.ExternalID == "" {
+			w.ExternalID = cur.ExternalID
+		}
+		before, after := restoredFields(cur), restoredFields(&w)
+		if byKey && cur.UID == "" {
+			// The server dropped the UID when it was restored; it can't be
+			// set, so don't plan an update for it on every restore.
+			after.UID = before.UID
+		}
+		if diffs := plan.Fields(before.CreateRequest(cur.FeedID), after.CreateRequest(cur.FeedID)); len(diffs) > 0 {
+			id := cur.ID
+			p.Add(plan.Change{
+				Action: plan.Update, Kind: "event", ID: id, Title: cur.Summary, Diffs: diffs,
+				Apply: func() error {
+					_, err := t.UpdateEvent(id, after.CreateRequest(cur.FeedID))
+					return err
+				},
+			})
+		}
+	}
+}
+
+// restoreKey returns the external ID a restore creates e under: its own,
+// or one derived from its ID in the backup.
+func restoreKey(e *cal.Event) string {
+	if e.ExternalID != "" {
+		return e.ExternalID
+	}
+	return "restore:" + e.ID
+}
+
+// restoredFeed holds the parts of a feed a restore sets.
+type restoredFeed struct {
+	Name            string `json:"name"`
+	Token           string `json:"token"`
+	ProdID          string `json:"prodid"`
+	CalendarName    string `json:"calendar_name"`
+	RefreshInterval string `json:"refresh_interval"`
+	OmitAlarms      bool   `json:"omit_alarms"`
+}
+
+func feedFields(f cal.Feed) restoredFeed {
+	return restoredFeed{f.Name, f.Token, f.ProdID, f.CalendarName, f.RefreshInterval, f.OmitAlarms}
+}
+
+// settings returns the update that sets all of f's .ics settings, those
+// at their defaults included.
+func settings(f cal.Feed) *cal.UpdateFeedRequest {
+	return &cal.UpdateFeedRequest{
+		ProdID:          &f.ProdID,
+		CalendarName:    &f.CalendarName,
+		RefreshInterval: &f.RefreshInterval,
+		OmitAlarms:      &f.OmitAlarms,
+	}
+}
+
+// restoredFields returns the fields of e a restore sets, with times in UTC
+// and its published UID, so that only real changes show up as diffs. The
+// SEQUENCE is left out: the server raises it on every update, so it never
+// matches the backup once an event has been restored. No status is the
+// server's default, CONFIRMED.
+func restoredFields(e *cal.Event) *cal.Event {
+	c := *e
+	c.UID = e.ICalUID()
+	c.Sequence = 0
+	if c.Status == "" {
+		c.Status = "CONFIRMED"
+	}
+	c.Start = e.Start.UTC()
+	if e.End != nil {
+		end := e.End.UTC()
+		c.End = &end
+	}
+	if e.Deadline != nil {
+		d := e.Deadline.UTC()
+		c.Deadline = &d
+	}
+	c.ExDates = nil
+	for _, t := range e.ExDates {
+		c.ExDates = append(c.ExDates, t.UTC())
+	}
+	return &c
+}
@@ -0,0 +1,238 @@
+package backup
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/calserver"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+// newServer starts an empty cal server and returns a client for it.
+func newServer(t *testing.T) *cal.Client {
+	t.Helper()
+	store, err := calserver.OpenStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(calserver.New(store))
+	t.Cleanup(srv.Close)
+	return cal.NewClient(srv.URL)
+}
+
+// restore plans restoring b into client and applies the plan, failing the
+// test on any error. It returns the plan.
+func restore(t *testing.T, client *cal.Client, b *cal.Backup) *plan.Plan {
+	t.Helper()
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Restore(client, b, feeds, client.ListEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Apply(func(c plan.Change, err error) {
+		if err != nil {
+			t.Errorf("%s %s %s: %v", c.Action, c.Kind, c.ID, err)
+		}
+	})
+	return p
+}
+
+func counts(p *plan.Plan) [3]int {
+	c, u, d := p.Counts()
+	return [3]int{c, u, d}
+}
+
+func TestRestore(t *testing.T) {
+	start := time.Date(2026, 3, 6, 19, 0, 0, 0, time.FixedZone("CET", 3600))
+	end := start.Add(2 * time.Hour)
+	b := &cal.Backup{Version: cal.BackupVersion, Feeds: []cal.FeedBackup{
+		{
+			Feed: cal.Feed{ID: "old-1", Name: "Team", Token: "team", RefreshInterval: "PT1H"},
+			Events: []cal.Event{
+				{ID: "e1", FeedID: "old-1", Summary: "Standup", Start: start, End: &end, Status: "CONFIRMED", Sequence: 3},
+				{ID: "e2", FeedID: "old-1", Summary: "Retro", Start: start.AddDate(0, 0, 7), UID: "retro@example.com"},
+			},
+		},
+		{
+			Feed:   cal.Feed{ID: "old-2", Name: "On call", Token: "3b8e0c1e-52a4-4e7c-9d4c-8f4f2f3c9a10"},
+			Events: []cal.Event{{ID: "e3", FeedID: "old-2", Summary: "Rotation", Start: start, AllDay: true}},
+		},
+	}}
+
+	client := newServer(t)
+	if got, want := counts(restore(t, client, b)), [3]int{5, 0, 0}; got != want {
+		t.Fatalf("first restore: (create, update, delete) = %v, want %v", got, want)
+	}
+
+	feeds, err := client.ListFeeds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feeds) != 2 || feeds[0].Token != "team" || feeds[0].RefreshInterval != "PT1H" || feeds[1].Token != b.Feeds[1].Feed.Token {
+		t.Fatalf("feeds = %+v", feeds)
+	}
+	events, err := client.ListEvents(feeds[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].ICalUID() != "e1" || events[1].ICalUID() != "retro@example.com" || events[0].Sequence < 3 {
+		t.Fatalf("events keep their UID and SEQUENCE: %+v", events)
+	}
+
+	// Restoring again changes nothing.
+	if p := restore(t, client, b); len(p.Changes) != 0 {
+		t.Errorf("second restore planned %d change(s): %+v", len(p.Changes), p.Changes)
+	}
+
+	// Changes made since the backup are undone; events added since are kept.
+	req := events[0].CreateRequest(feeds[0].ID)
+	req.Summary = "Daily standup"
+	if _, err := client.UpdateEvent(events[0].ID, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateEvent(&cal.CreateEventRequest{FeedID: feeds[0].ID, Summary: "New", Start: start.Format(time.RFC3339)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UpdateFeed(feeds[1].ID, &cal.UpdateFeedRequest{Name: "Pager"}); err != nil {
+		t.Fatal(err)
+	}
+	p := restore(t, client, b)
+	if got, want := counts(p), [3]int{0, 2, 0}; got != want {
+		t.Fatalf("third restore: (create, update, delete) = %v, want %v", got, want)
+	}
+	events, _ = client.ListEvents(feeds[0].ID)
+	if len(events) != 3 || events[0].Summary != "Standup" {
+		t.Errorf("events after restore = %+v", events)
+	}
+}
+
+func TestRestoreMatchesByID(t *testing.T) {
+	// On the same server, a renamed feed whose token was rotated is still
+	// the backed-up feed, and gets its name and token back.
+	client := newServer(t)
+	f, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &cal.Backup{Version: cal.BackupVersion, Feeds: []cal.FeedBackup{{Feed: cal.Feed{ID: f.ID, Name: "Team", Token: "team"}}}}
+	if _, err := client.UpdateFeed(f.ID, &cal.UpdateFeedRequest{Name: "Old team", Slug: "leaked"}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := restore(t, client, b)
+	if len(p.Changes) != 1 || p.Changes[0].Action != plan.Update || len(p.Changes[0].Diffs) != 2 {
+		t.Fatalf("changes = %+v", p.Changes)
+	}
+	feeds, _ := client.ListFeeds()
+	if len(feeds) != 1 || feeds[0].Name != "Team" || feeds[0].Token != "team" {
+		t.Errorf("feeds = %+v", feeds)
+	}
+}
+
+// dropUID is a cal client for a server that ignores the UID of new and
+// updated events, as some deployments do.
+type dropUID struct{ *cal.Client }
+
+func (c dropUID) CreateEvent(req *cal.CreateEventRequest) (*cal.Event, error) {
+	r := *req
+	r.UID = ""
+	return c.Client.CreateEvent(&r)
+}
+
+func (c dropUID) UpsertEvent(externalUID string, req *cal.CreateEventRequest) (*cal.Event, bool, error) {
+	r := *req
+	r.UID = ""
+	return c.Client.UpsertEvent(externalUID, &r)
+}
+
+func (c dropUID) UpdateEvent(id string, req *cal.CreateEventRequest) (*cal.Event, error) {
+	r := *req
+	r.UID = ""
+	return c.Client.UpdateEvent(id, &r)
+}
+
+func TestRestoreWithoutUIDs(t *testing.T) {
+	start := time.Date(2026, 3, 6, 19, 0, 0, 0, time.UTC)
+	b := &cal.Backup{Version: cal.BackupVersion, Feeds: []cal.FeedBackup{{
+		Feed: cal.Feed{ID: "old-1", Name: "Team", Token: "team"},
+		Events: []cal.Event{
+			{ID: "e1", FeedID: "old-1", Summary: "Standup", Start: start},
+			{ID: "e2", FeedID: "old-1", Summary: "Deploy", Start: start, ExternalID: "ci-42"},
+		},
+	}}}
+
+	client := newServer(t)
+	target := dropUID{client}
+	for i, want := range []int{3, 0} {
+		feeds, err := client.ListFeeds()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := Restore(target, b, feeds, client.ListEvents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.Changes) != want {
+			t.Fatalf("restore %d planned %d change(s), want %d: %+v", i+1, len(p.Changes), want, p.Changes)
+		}
+		p.Apply(func(c plan.Change, err error) {
+			if err != nil {
+				t.Errorf("%s %s %s: %v", c.Action, c.Kind, c.ID, err)
+			}
+		})
+	}
+
+	feeds, _ := client.ListFeeds()
+	events, err := client.ListEvents(feeds[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].ExternalID != "restore:e1" || events[1].ExternalID != "ci-42" {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestEventsTwice(t *testing.T) {
+	// Archived events were deleted from the feed, and restoring the archive
+	// again must not create them a second time.
+	start := time.Date(2025, 3, 6, 19, 0, 0, 0, time.UTC)
+	archived := []cal.Event{
+		{ID: "e1", FeedID: "old-1", Summary: "Standup", Start: start, UID: "standup@example.com"},
+		{ID: "e2", FeedID: "old-1", Summary: "Deploy", Start: start, ExternalID: "ci-42"},
+		{ID: "e3", FeedID: "old-1", Summary: "Retro", Start: start},
+	}
+
+	client := newServer(t)
+	f, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{3, 0} {
+		have, err := client.ListEvents(f.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := Events(client, f.ID, archived, have)
+		if len(p.Changes) != want {
+			t.Fatalf("restore %d planned %d change(s), want %d: %+v", i+1, len(p.Changes), want, p.Changes)
+		}
+		p.Apply(func(c plan.Change, err error) {
+			if err != nil {
+				t.Errorf("%s %s %s: %v", c.Action, c.Kind, c.ID, err)
+			}
+		})
+	}
+
+	events, err := client.ListEvents(f.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Errorf("events = %+v", events)
+	}
+}
@@ -0,0 +1,134 @@
+// Package bridge keeps pylon feeds in step with calendars held elsewhere.
+// Each bridge builds a plan.Plan, so its changes can be reviewed with
+// --plan before --apply makes them.
+package bridge
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+// Target is the part of the cal client a bridge writes through.
+type Target interface {
+	UpsertEvent(externalUID string, req *cal.CreateEventRequest) (*cal.Event, bool, error)
+	DeleteEvent(id string) error
+}
+
+const discordEventPrefix = "discord-event:"
+
+// DiscordEventID returns the external ID of the pylon copy of a Discord
+// scheduled event.
+func DiscordEventID(id string) string {
+	return discordEventPrefix + id
+}
+
+// FromDiscordEvent converts a Discord scheduled event to the pylon event
+// that represents it. Events without an end (voice and stage events) get
+// none; canceled events are marked CANCELLED so subscribers see them drop.
+func FromDiscordEvent(e discord.ScheduledEvent) cal.Event {
+	status := "CONFIRMED"
+	if e.Status == discord.EventCanceled {
+		status = "CANCELLED"
+	}
+	ev := cal.Event{
+		Summary:     e.Name,
+		Description: e.Description,
+		Location:    e.Location(),
+		URL:         e.URL(),
+		Start:       e.Start.UTC(),
+		Status:      status,
+		ExternalID:  DiscordEventID(e.ID),
+	}
+	if e.End != nil {
+		end := e.End.UTC()
+		ev.End = &end
+	}
+	return ev
+}
+
+// ImportDiscordEvents plans the changes that bring feedID in step with a
+// guild's scheduled events: new events are created, changed ones updated,
+// and copies of upcoming events that are no longer listed deleted. Copies
+// of past events stay, since Discord drops finished events from its
+// listing. Events exported from pylon are left out, so a feed exported to
+// the guild isn't imported back. existing is the feed's current events.
+func ImportDiscordEvents(t Target, feedID string, events []discord.ScheduledEvent, existing []cal.Event, now time.Time) *plan.Plan {
+	var want []cal.Event
+	for _, de := range events {
+		if _, ok := ExportedFrom(de); !ok {
+			want = append(want, FromDiscordEvent(de))
+		}
+	}
+	upcoming := func(e cal.Event) bool { return e.Start.After(now) }
+	return importEvents(t, feedID, discordEventPrefix, want, existing, upcoming, "no longer on Discord")
+}
+
+// importEvents plans the changes that make the copies in feedID, the
+// events whose external IDs start with prefix, match want: missing ones
+// are created, changed ones updated, and copies that are no longer wanted
+// deleted if stale says they may go.
+func importEvents(t Target, feedID, prefix string, want, existing []cal.Event, stale func(cal.Event) bool, reason string) *plan.Plan {
+	have := map[string]*cal.Event{}
+	for i := range existing {
+		if strings.HasPrefix(existing[i].ExternalID, prefix) {
+			have[existing[i].ExternalID] = &existing[i]
+		}
+	}
+
+	p := &plan.Plan{}
+	listed := map[string]bool{}
+	for _, w := range want {
+		listed[w.ExternalID] = true
+		req := w.CreateRequest(feedID)
+		upsert := func() error {
+			_, _, err := t.UpsertEvent(w.ExternalID, req)
+			return err
+		}
+
+		cur, ok := have[w.ExternalID]
+		if !ok {
+			p.Add(plan.Change{Action: plan.Create, Kind: "event", ID: w.ExternalID, Title: w.Summary, Apply: upsert})
+			continue
+		}
+		if diffs := plan.Fields(importedFields(cur).CreateRequest(feedID), req); len(diffs) > 0 {
+			p.Add(plan.Change{Action: plan.Update, Kind: "event", ID: cur.ID, Title: cur.Summary, Diffs: diffs, Apply: upsert})
+		}
+	}
+
+	for _, e := range existing {
+		if _, ok := have[e.ExternalID]; !ok || listed[e.ExternalID] || !stale(e) {
+			continue
+		}
+		id := e.ID
+		p.Add(plan.Change{
+			Action: plan.Delete, Kind: "event", ID: id, Title: e.Summary,
+			Reason: reason,
+			Apply:  func() error { return t.DeleteEvent(id) },
+		})
+	}
+	return p
+}
+
+// importedFields returns the fields of e an import sets, with times in UTC,
+// so that only real changes show up as diffs.
+func importedFields(e *cal.Event) *cal.Event {
+	c := &cal.Event{
+		Summary:     e.Summary,
+		Description: e.Description,
+		Location:    e.Location,
+		URL:         e.URL,
+		Start:       e.Start.UTC(),
+		AllDay:      e.AllDay,
+		Status:      e.Status,
+		ExternalID:  e.ExternalID,
+	}
+	if e.End != nil {
+		end := e.End.UTC()
+		c.End = &end
+	}
+	return c
+}
@@ -0,0 +1,99 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+// fakeTarget records the writes a plan makes.
+type fakeTarget struct {
+	upserted []string
+	deleted  []string
+}
+
+func (f *fakeTarget) UpsertEvent(uid string, req *cal.CreateEventRequest) (*cal.Event, bool, error) {
+	f.upserted = append(f.upserted, uid)
+	return &cal.Event{ExternalID: uid}, true, nil
+}
+
+func (f *fakeTarget) DeleteEvent(id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestImportDiscordEvents(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	cet := time.FixedZone("CET", 3600)
+	start := time.Date(2026, 3, 6, 19, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	past := time.Date(2026, 2, 1, 19, 0, 0, 0, time.UTC)
+	future := time.Date(2026, 4, 1, 19, 0, 0, 0, time.UTC)
+
+	events := []discord.ScheduledEvent{
+		{ID: "1", GuildID: "g", Name: "Game night", Start: start, End: &end, Status: discord.EventScheduled},
+		{ID: "2", GuildID: "g", Name: "Meetup", Start: start, Status: discord.EventScheduled,
+			EntityMetadata: &discord.EventMetadata{Location: "Cafe"}},
+		{ID: "3", GuildID: "g", Name: "AMA", Start: start, Status: discord.EventScheduled},
+	}
+	unchanged := FromDiscordEvent(events[0])
+	unchanged.ID, unchanged.FeedID = "p1", "feed"
+	// The server may return times in another zone; that isn't a change.
+	unchangedStart, unchangedEnd := unchanged.Start.In(cet), unchanged.End.In(cet)
+	unchanged.Start, unchanged.End = unchangedStart, &unchangedEnd
+
+	existing := []cal.Event{
+		unchanged,
+		{ID: "p2", FeedID: "feed", Summary: "Meetup", Start: start, Status: "CONFIRMED",
+			URL: "https://discord.com/events/g/2", ExternalID: DiscordEventID("2")},
+		{ID: "p4", FeedID: "feed", Summary: "Cancelled talk", Start: future, ExternalID: DiscordEventID("4")},
+		{ID: "p5", FeedID: "feed", Summary: "Last month", Start: past, ExternalID: DiscordEventID("5")},
+		{ID: "p6", FeedID: "feed", Summary: "Hand-made", Start: future},
+	}
+
+	ft := &fakeTarget{}
+	p := ImportDiscordEvents(ft, "feed", events, existing, now)
+
+	type change struct {
+		action plan.Action
+		id     string
+	}
+	var got []change
+	for _, c := range p.Changes {
+		got = append(got, change{c.Action, c.ID})
+	}
+	want := []change{
+		{plan.Update, "p2"},
+		{plan.Create, "discord-event:3"},
+		{plan.Delete, "p4"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if d := p.Changes[0].Diffs; len(d) != 1 || d[0].Field != "location" || d[0].New != "Cafe" {
+		t.Errorf("update diffs = %+v, want only the location", d)
+	}
+
+	p.Apply(nil)
+	if len(ft.upserted) != 2 || ft.upserted[0] != "discord-event:2" || ft.upserted[1] != "discord-event:3" {
+		t.Errorf("upserted %v", ft.upserted)
+	}
+	if len(ft.deleted) != 1 || ft.deleted[0] != "p4" {
+		t.Errorf("deleted %v", ft.deleted)
+	}
+}
+
+func TestFromDiscordEventCanceled(t *testing.T) {
+	e := FromDiscordEvent(discord.ScheduledEvent{ID: "9", GuildID: "g", Name: "Off", Status: discord.EventCanceled})
+	if e.Status != "CANCELLED" || e.ExternalID != "discord-event:9" || e.URL != "https://discord.com/events/g/9" {
+		t.Errorf("FromDiscordEvent = %+v", e)
+	}
+}
@@ -0,0 +1,181 @@
+package bridge
+
+import (
+	"cmp"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/plan"
+	"github.com/jredh-dev/pylon/internal/recur"
+)
+
+// GuildEvents is the part of the Discord client an export writes through.
+type GuildEvents interface {
+	CreateScheduledEvent(guildID string, p *discord.EventParams) (*discord.ScheduledEvent, error)
+	ModifyScheduledEvent(guildID, eventID string, p *discord.EventParams) (*discord.ScheduledEvent, error)
+	DeleteScheduledEvent(guildID, eventID string) error
+}
+
+// exportTag ends the description of a Discord event exported from a feed,
+// naming the occurrence it copies, so later exports find it again and
+// imports leave it out.
+const exportTag = "[pylon:"
+
+// Discord's limits on scheduled event fields, in characters.
+const (
+	maxEventName        = 100
+	maxEventDescription = 1000
+	maxEventLocation    = 100
+)
+
+// ExportKey identifies an occurrence of a pylon event among exported
+// Discord events: the event ID, and for a recurring event the start.
+func ExportKey(e cal.Event) string {
+	if e.RRule == "" {
+		return e.ID
+	}
+	return e.ID + "@" + e.Start.UTC().Format("20060102T150405Z")
+}
+
+// ExportedFrom returns the ExportKey of the occurrence a Discord event was
+// exported from, or false if it wasn't exported by pylon.
+func ExportedFrom(e discord.ScheduledEvent) (string, bool) {
+	i := strings.LastIndex(e.Description, exportTag)
+	if i < 0 || !strings.HasSuffix(e.Description, "]") {
+		return "", false
+	}
+	return e.Description[i+len(exportTag) : len(e.Description)-1], true
+}
+
+// ToDiscordEvent returns the scheduled event that represents an occurrence
+// of a pylon event: in the voice channel channelID if one is given, or else
+// an external event at the event's location, falling back to its URL.
+// External events need an end, so one without gets an hour.
+func ToDiscordEvent(e cal.Event, channelID string) *discord.EventParams {
+	tag := exportTag + ExportKey(e) + "]"
+	desc := truncate(e.Description, maxEventDescription-len(tag)-2)
+	p := &discord.EventParams{
+		Name:        truncate(cmp.Or(e.Summary, "(no title)"), maxEventName),
+		Description: strings.TrimSpace(desc + "\n\n" + tag),
+		Start:       e.Start.UTC(),
+		ChannelID:   channelID,
+	}
+	end := e.Start.Add(time.Hour)
+	if e.End != nil && e.End.After(e.Start) {
+		end = *e.End
+	}
+	end = end.UTC()
+	p.End = &end
+	if channelID == "" {
+		p.Location = truncate(cmp.Or(e.Location, e.URL, "See the calendar"), maxEventLocation)
+	}
+	return p
+}
+
+// truncate shortens s to at most n characters, marking the cut.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// exportedFields are the fields of a Discord event an export sets, so that
+// only real changes show up as diffs.
+type exportedFields struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Location    string    `json:"location"`
+	ChannelID   string    `json:"channel"`
+}
+
+func fieldsOf(p *discord.EventParams) exportedFields {
+	f := exportedFields{Name: p.Name, Description: p.Description, Start: p.Start.UTC(), Location: p.Location, ChannelID: p.ChannelID}
+	if p.End != nil {
+		f.End = p.End.UTC()
+	}
+	return f
+}
+
+func discordFields(e *discord.ScheduledEvent) exportedFields {
+	f := exportedFields{Name: e.Name, Description: e.Description, Start: e.Start.UTC(), Location: e.Location()}
+	if e.EntityType != discord.EntityExternal {
+		f.ChannelID = e.ChannelID
+	}
+	if e.End != nil {
+		f.End = e.End.UTC()
+	}
+	return f
+}
+
+// ExportDiscordEvents plans the changes that make a guild's scheduled
+// events show the occurrences of events starting in [from, to): missing
+// ones are created and changed ones updated, and exported events whose
+// occurrence is gone or cancelled are deleted. Only events pylon exported
+// are touched, and only while they are still scheduled; Discord doesn't
+// allow changing events that have started. existing is the guild's
+// current scheduled events.
+func ExportDiscordEvents(g GuildEvents, guildID, channelID string, events []cal.Event, existing []discord.ScheduledEvent, from, to time.Time) *plan.Plan {
+	have := map[string]*discord.ScheduledEvent{}
+	for i := range existing {
+		if key, ok := ExportedFrom(existing[i]); ok {
+			have[key] = &existing[i]
+		}
+	}
+
+	p := &plan.Plan{}
+	wanted := map[string]bool{}
+	for _, e := range recur.ExpandAll(events, from, to) {
+		if e.Start.Before(from) || !e.Start.Before(to) || strings.EqualFold(e.Status, "cancelled") {
+			continue
+		}
+		key := ExportKey(e)
+		wanted[key] = true
+		params := ToDiscordEvent(e, channelID)
+
+		cur, ok := have[key]
+		if !ok {
+			p.Add(plan.Change{
+				Action: plan.Create, Kind: "discord event", ID: key, Title: params.Name,
+				Apply: func() error {
+					_, err := g.CreateScheduledEvent(guildID, params)
+					return err
+				},
+			})
+			continue
+		}
+		if cur.Status != discord.EventScheduled {
+			continue
+		}
+		if diffs := plan.Fields(discordFields(cur), fieldsOf(params)); len(diffs) > 0 {
+			id := cur.ID
+			p.Add(plan.Change{
+				Action: plan.Update, Kind: "discord event", ID: id, Title: cur.Name, Diffs: diffs,
+				Apply: func() error {
+					_, err := g.ModifyScheduledEvent(guildID, id, params)
+					return err
+				},
+			})
+		}
+	}
+
+	for _, e := range existing {
+		key, ok := ExportedFrom(e)
+		// Copies after the window may still be wanted once it gets there.
+		if !ok || wanted[key] || e.Status != discord.EventScheduled || !e.Start.Before(to) {
+			continue
+		}
+		id := e.ID
+		p.Add(plan.Change{
+			Action: plan.Delete, Kind: "discord event", ID: id, Title: e.Name,
+			Reason: "no longer in the feed",
+			Apply:  func() error { return g.DeleteScheduledEvent(guildID, id) },
+		})
+	}
+	return p
+}
@@ -0,0 +1,117 @@
+package bridge
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/discord"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+// fakeGuild records the writes an export makes.
+type fakeGuild struct {
+	calls []string
+}
+
+func (f *fakeGuild) CreateScheduledEvent(guildID string, p *discord.EventParams) (*discord.ScheduledEvent, error) {
+	f.calls = append(f.calls, "create "+p.Name)
+	return &discord.ScheduledEvent{}, nil
+}
+
+func (f *fakeGuild) ModifyScheduledEvent(guildID, id string, p *discord.EventParams) (*discord.ScheduledEvent, error) {
+	f.calls = append(f.calls, "modify "+id)
+	return &discord.ScheduledEvent{}, nil
+}
+
+func (f *fakeGuild) DeleteScheduledEvent(guildID, id string) error {
+	f.calls = append(f.calls, "delete "+id)
+	return nil
+}
+
+// exported returns the Discord event an up-to-date export of e would have.
+func exported(id string, e cal.Event, status int) discord.ScheduledEvent {
+	p := ToDiscordEvent(e, "")
+	return discord.ScheduledEvent{
+		ID: id, GuildID: "g", Name: p.Name, Description: p.Description, Start: p.Start, End: p.End,
+		Status: status, EntityType: discord.EntityExternal, EntityMetadata: &discord.EventMetadata{Location: p.Location},
+	}
+}
+
+func TestExportDiscordEvents(t *testing.T) {
+	from := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 14)
+	at := func(days int) time.Time { return from.AddDate(0, 0, days) }
+
+	events := []cal.Event{
+		{ID: "new", Summary: "Launch party", Start: at(1), Location: "Rooftop"},
+		{ID: "same", Summary: "Game night", Start: at(2)},
+		{ID: "moved", Summary: "Meetup", Start: at(3)},
+		{ID: "off", Summary: "Talk", Start: at(4), Status: "CANCELLED"},
+		{ID: "past", Summary: "Yesterday", Start: at(-1)},
+		{ID: "later", Summary: "Next month", Start: at(30)},
+		{ID: "weekly", Summary: "Standup", Start: at(0).Add(time.Hour), RRule: "FREQ=WEEKLY"},
+	}
+	movedBefore := events[2]
+	movedBefore.Start = at(5)
+
+	existing := []discord.ScheduledEvent{
+		exported("d-same", events[1], discord.EventScheduled),
+		exported("d-moved", movedBefore, discord.EventScheduled),
+		exported("d-off", events[3], discord.EventScheduled),
+		exported("d-gone", cal.Event{ID: "gone", Summary: "Deleted", Start: at(6)}, discord.EventScheduled),
+		exported("d-live", cal.Event{ID: "live", Summary: "Happening", Start: at(0)}, discord.EventActive),
+		exported("d-far", cal.Event{ID: "far", Summary: "Far off", Start: at(40)}, discord.EventScheduled),
+		{ID: "d-own", GuildID: "g", Name: "Made on Discord", Start: at(2), Status: discord.EventScheduled},
+	}
+
+	g := &fakeGuild{}
+	p := ExportDiscordEvents(g, "g", "", events, existing, from, to)
+	p.Apply(nil)
+	want := []string{
+		"create Launch party",
+		"modify d-moved",
+		"create Standup",
+		"create Standup",
+		"delete d-off",
+		"delete d-gone",
+	}
+	if !slices.Equal(g.calls, want) {
+		t.Errorf("calls = %q\nwant %q", g.calls, want)
+	}
+	for _, c := range p.Changes {
+		if c.Action == plan.Update && (len(c.Diffs) != 2 || c.Diffs[0].Field != "start" || c.Diffs[1].Field != "end") {
+			t.Errorf("update diffs = %+v, want start and end", c.Diffs)
+		}
+	}
+}
+
+func TestToDiscordEvent(t *testing.T) {
+	start := time.Date(2026, 3, 6, 19, 0, 0, 0, time.UTC)
+	e := cal.Event{ID: "e1", Summary: "Game night", Description: "Bring snacks", Start: start, URL: "https://example.com/gn"}
+	p := ToDiscordEvent(e, "")
+	if p.Location != "https://example.com/gn" || p.End == nil || !p.End.Equal(start.Add(time.Hour)) {
+		t.Errorf("external event = %+v", p)
+	}
+	if key, ok := ExportedFrom(discord.ScheduledEvent{Description: p.Description}); !ok || key != "e1" {
+		t.Errorf("ExportedFrom(%q) = %q, %v", p.Description, key, ok)
+	}
+	if !strings.HasPrefix(p.Description, "Bring snacks\n\n") {
+		t.Errorf("description = %q", p.Description)
+	}
+
+	e.Description = strings.Repeat("x", 2000)
+	if p := ToDiscordEvent(e, "77"); len([]rune(p.Description)) > maxEventDescription || p.Location != "" || p.ChannelID != "77" {
+		t.Errorf("voice event = %d characters of description, location %q", len([]rune(p.Description)), p.Location)
+	}
+}
+
+func TestImportSkipsExported(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	e := exported("d1", cal.Event{ID: "p1", Summary: "From pylon", Start: now.Add(time.Hour)}, discord.EventScheduled)
+	if p := ImportDiscordEvents(&fakeTarget{}, "feed", []discord.ScheduledEvent{e}, nil, now); len(p.Changes) != 0 {
+		t.Errorf("import of an exported event planned %+v", p.Changes)
+	}
+}
@@ -0,0 +1,71 @@
+package bridge
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/gcal"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+const googleEventPrefix = "gcal:"
+
+// GoogleEventID returns the external ID of the pylon copy of a Google
+// Calendar event (or occurrence).
+func GoogleEventID(id string) string {
+	return googleEventPrefix + id
+}
+
+// FromGoogleEvent converts a Google Calendar event to the pylon event that
+// represents it. Recurring events arrive expanded, so each occurrence is
+// an event of its own. The Google UID is not kept: occurrences share it,
+// and subscribers who also have the Google calendar would see the copies
+// merged into the originals.
+func FromGoogleEvent(e gcal.Event) (cal.Event, error) {
+	start, err := e.Start.Time()
+	if err != nil {
+		return cal.Event{}, fmt.Errorf("event %s: start: %w", e.ID, err)
+	}
+	ev := cal.Event{
+		Summary:     e.Summary,
+		Description: e.Description,
+		Location:    e.Location,
+		URL:         e.HTMLLink,
+		Start:       start.UTC(),
+		AllDay:      e.Start.AllDay(),
+		Status:      strings.ToUpper(e.Status),
+		ExternalID:  GoogleEventID(e.ID),
+	}
+	if ev.Status == "" {
+		ev.Status = "CONFIRMED"
+	}
+	if e.End.Date != "" || e.End.DateTime != nil {
+		end, err := e.End.Time()
+		if err != nil {
+			return cal.Event{}, fmt.Errorf("event %s: end: %w", e.ID, err)
+		}
+		end = end.UTC()
+		ev.End = &end
+	}
+	return ev, nil
+}
+
+// ImportGoogleEvents plans the changes that bring feedID in step with the
+// events of a Google calendar between from and to: new events are created,
+// changed ones updated, and copies of events in that window that are no
+// longer on Google deleted. Copies outside the window, past events
+// included, are left alone. existing is the feed's current events.
+func ImportGoogleEvents(t Target, feedID string, events []gcal.Event, existing []cal.Event, from, to time.Time) (*plan.Plan, error) {
+	want := make([]cal.Event, 0, len(events))
+	for _, ge := range events {
+		e, err := FromGoogleEvent(ge)
+		if err != nil {
+			return nil, err
+		}
+		want = append(want, e)
+	}
+	inWindow := func(e cal.Event) bool { return !e.Start.Before(from) && e.Start.Before(to) }
+	return importEvents(t, feedID, googleEventPrefix, want, existing, inWindow, "no longer on Google Calendar"), nil
+}
@@ -0,0 +1,111 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/gcal"
+	"github.com/jredh-dev/pylon/internal/plan"
+)
+
+func TestFromGoogleEvent(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	end := start.Add(15 * time.Minute)
+	tests := []struct {
+		name    string
+		in      gcal.Event
+		want    cal.Event
+		wantErr bool
+	}{
+		{
+			name: "timed",
+			in: gcal.Event{ID: "a_20260302", Status: "confirmed", Summary: "Standup", Location: "Room 1",
+				HTMLLink: "https://www.google.com/calendar/event?eid=x",
+				Start:    gcal.EventTime{DateTime: &start}, End: gcal.EventTime{DateTime: &end}},
+			want: cal.Event{Summary: "Standup", Location: "Room 1", URL: "https://www.google.com/calendar/event?eid=x",
+				Start: start.UTC(), End: ptr(end.UTC()), Status: "CONFIRMED", ExternalID: "gcal:a_20260302"},
+		},
+		{
+			name: "all day",
+			in: gcal.Event{ID: "b", Status: "tentative", Summary: "Offsite",
+				Start: gcal.EventTime{Date: "2026-03-10"}, End: gcal.EventTime{Date: "2026-03-12"}},
+			want: cal.Event{Summary: "Offsite", Start: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+				End: ptr(time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)), AllDay: true, Status: "TENTATIVE", ExternalID: "gcal:b"},
+		},
+		{name: "no start", in: gcal.Event{ID: "c"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromGoogleEvent(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diffs := plan.Fields(got.CreateRequest("f"), tt.want.CreateRequest("f")); len(diffs) > 0 {
+				t.Errorf("FromGoogleEvent differs: %+v", diffs)
+			}
+		})
+	}
+}
+
+func TestImportGoogleEvents(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 6, 0)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	events := []gcal.Event{
+		{ID: "same", Summary: "Standup", Status: "confirmed", Start: gcal.EventTime{DateTime: &start}},
+		{ID: "moved", Summary: "Review", Status: "confirmed", Start: gcal.EventTime{DateTime: &start}},
+		{ID: "new", Summary: "Launch", Status: "confirmed", Start: gcal.EventTime{Date: "2026-04-01"}},
+	}
+	same, _ := FromGoogleEvent(events[0])
+	same.ID = "p1"
+	existing := []cal.Event{
+		same,
+		{ID: "p2", Summary: "Review", Start: start.Add(time.Hour), Status: "CONFIRMED", ExternalID: GoogleEventID("moved")},
+		{ID: "p3", Summary: "Dropped", Start: start, Status: "CONFIRMED", ExternalID: GoogleEventID("gone")},
+		{ID: "p4", Summary: "Last year", Start: from.AddDate(-1, 0, 0), ExternalID: GoogleEventID("old")},
+		{ID: "p5", Summary: "Far ahead", Start: to.AddDate(0, 1, 0), ExternalID: GoogleEventID("later")},
+		{ID: "p6", Summary: "Hand-made", Start: start},
+	}
+
+	ft := &fakeTarget{}
+	p, err := ImportGoogleEvents(ft, "feed", events, existing, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type change struct {
+		action plan.Action
+		id     string
+	}
+	var got []change
+	for _, c := range p.Changes {
+		got = append(got, change{c.Action, c.ID})
+	}
+	want := []change{
+		{plan.Update, "p2"},
+		{plan.Create, "gcal:new"},
+		{plan.Delete, "p3"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	p.Apply(nil)
+	if len(ft.upserted) != 2 || ft.upserted[0] != "gcal:moved" || ft.upserted[1] != "gcal:new" {
+		t.Errorf("upserted %v", ft.upserted)
+	}
+	if len(ft.deleted) != 1 || ft.deleted[0] != "p3" {
+		t.Errorf("deleted %v", ft.deleted)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,672 @@
+// Package calserver is a minimal implementation of the cal service API that
+// package cal consumes: feeds, events, search and the public /<token>.ics
+// subscription endpoint. It backs pylon cal serve, for local development
+// and tests without a deployed cal service. Data lives in memory and, when
+// the store has a path, in a JSON file.
+//
+// Signed subscription URLs and subscriber statistics are not implemented;
+// their routes are missing, so clients see ErrNotSupported as they would
+// from an older server.
+package calserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/ics"
+)
+
+// maxBody bounds request bodies.
+const maxBody = 1 << 20
+
+// Server serves the cal API from a Store.
+type Server struct {
+	store *Store
+	mux   *http.ServeMux
+
+	// Now returns the current time, for timestamps. Tests may replace it.
+	Now func() time.Time
+	// Version is reported by /api/version.
+	Version string
+	// SkipCancelled leaves cancelled events out of the .ics feeds instead
+	// of publishing them with STATUS:CANCELLED.
+	SkipCancelled bool
+	// Location is the time zone recurring events are published in when
+	// they were sent with a bare UTC offset that matches it, so their
+	// occurrences keep their wall-clock time across daylight saving
+	// changes. It defaults to time.Local.
+	Location *time.Location
+	// BaseURL is the public URL feed subscription URLs are built on, for
+	// a server behind a proxy that rewrites the host. If empty they are
+	// built from the request's Host, and X-Forwarded-Proto or TLS for the
+	// scheme.
+	BaseURL string
+}
+
+// capabilities are the optional endpoints this server implements.
+var capabilities = []string{cal.CapUpsert, cal.CapRotateToken, cal.CapUpdateFeed, cal.CapGetEvent, cal.CapSearch, cal.CapCancel, cal.CapUpdateEvent}
+
+// New returns a server for store.
+func New(store *Store) *Server {
+	s := &Server{store: store, mux: http.NewServeMux(), Now: time.Now, Version: "dev", Location: time.Local}
+	s.mux.HandleFunc("GET /api/version", s.version)
+	s.mux.HandleFunc("POST /api/feeds", s.createFeed)
+	s.mux.HandleFunc("GET /api/feeds", s.listFeeds)
+	s.mux.HandleFunc("PATCH /api/feeds/{id}", s.updateFeed)
+	s.mux.HandleFunc("DELETE /api/feeds/{id}", s.deleteFeed)
+	s.mux.HandleFunc("POST /api/feeds/{id}/rotate-token", s.rotateToken)
+	s.mux.HandleFunc("GET /api/feeds/{id}/events", s.listEvents)
+	s.mux.HandleFunc("POST /api/events", s.createEvent)
+	s.mux.HandleFunc("PUT /api/events/external/{uid}", s.upsertEvent)
+	s.mux.HandleFunc("GET /api/events/{id}", s.getEvent)
+	s.mux.HandleFunc("PUT /api/events/{id}", s.updateEvent)
+	s.mux.HandleFunc("POST /api/events/{id}/cancel", s.cancelEvent)
+	s.mux.HandleFunc("DELETE /api/events/{id}", s.deleteEvent)
+	s.mux.HandleFunc("GET /api/search", s.search)
+	s.mux.HandleFunc("GET /{file}", s.serveICS)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) version(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, cal.ServerInfo{
+		Version:       s.Version,
+		APIVersion:    cal.APIVersion,
+		MinAPIVersion: cal.APIVersion,
+		Capabilities:  capabilities,
+	})
+}
+
+func (s *Server) createFeed(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	token := req.Slug
+	if token == "" {
+		token = newID()
+	}
+	if st.tokenTaken(token, "") {
+		writeError(w, http.StatusConflict, "feed already exists")
+		return
+	}
+	now := s.Now().UTC()
+	f := cal.Feed{ID: newID(), Name: req.Name, Token: token, CreatedAt: now, UpdatedAt: now}
+	st.feeds = append(st.feeds, f)
+	if !s.save(w) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.feedResponse(r, f))
+}
+
+func (s *Server) listFeeds(w http.ResponseWriter, r *http.Request) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	writeListing(w, r, nonNil(st.feeds))
+}
+
+func (s *Server) updateFeed(w http.ResponseWriter, r *http.Request) {
+	var req cal.UpdateFeedRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := st.feed(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "feed not found")
+		return
+	}
+	f := &st.feeds[i]
+	if req.Slug != "" {
+		if st.tokenTaken(req.Slug, f.ID) {
+			writeError(w, http.StatusConflict, "slug already in use")
+			return
+		}
+		f.Token = req.Slug
+	}
+	if req.Name != "" {
+		f.Name = req.Name
+	}
+	if req.RefreshInterval != nil && *req.RefreshInterval != "" {
+		if d, err := ics.ParseDuration(*req.RefreshInterval); err != nil || d <= 0 {
+			writeError(w, http.StatusBadRequest, "refresh_interval must be a positive duration such as PT1H")
+			return
+		}
+	}
+	setIfGiven(&f.ProdID, req.ProdID)
+	setIfGiven(&f.CalendarName, req.CalendarName)
+	setIfGiven(&f.RefreshInterval, req.RefreshInterval)
+	setIfGiven(&f.OmitAlarms, req.OmitAlarms)
+	f.UpdatedAt = s.Now().UTC()
+	if !s.save(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, f)
+}
+
+// setIfGiven sets *dst to *v unless v is nil.
+func setIfGiven[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+func (s *Server) deleteFeed(w http.ResponseWriter, r *http.Request) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	id := r.PathValue("id")
+	i := st.feed(id)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "feed not found")
+		return
+	}
+	st.feeds = slices.Delete(st.feeds, i, i+1)
+	st.events = slices.DeleteFunc(st.events, func(e cal.Event) bool { return e.FeedID == id })
+	if !s.save(w) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) rotateToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Slug string `json:"slug"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := st.feed(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "feed not found")
+		return
+	}
+	f := &st.feeds[i]
+	token := req.Slug
+	if token == "" {
+		token = newID()
+	}
+	if st.tokenTaken(token, f.ID) {
+		writeError(w, http.StatusConflict, "slug already in use")
+		return
+	}
+	f.Token = token
+	f.UpdatedAt = s.Now().UTC()
+	if !s.save(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.feedResponse(r, *f))
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	id := r.PathValue("id")
+	if st.feed(id) < 0 {
+		writeError(w, http.StatusNotFound, "feed not found")
+		return
+	}
+	events := []cal.Event{}
+	for _, e := range st.events {
+		if e.FeedID == id {
+			events = append(events, e)
+		}
+	}
+	writeListing(w, r, events)
+}
+
+func (s *Server) createEvent(w http.ResponseWriter, r *http.Request) {
+	var req cal.CreateEventRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := s.Now().UTC()
+	e, err := eventFrom(&req, now)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if st.feed(e.FeedID) < 0 {
+		writeError(w, http.StatusNotFound, "feed not found")
+		return
+	}
+	if st.uidTaken(e.FeedID, e.UID, "") {
+		writeError(w, http.StatusConflict, "uid already in use in this feed")
+		return
+	}
+	e.ID, e.CreatedAt = newID(), now
+	st.events = append(st.events, *e)
+	if !s.save(w) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, e)
+}
+
+func (s *Server) upsertEvent(w http.ResponseWriter, r *http.Request) {
+	var req cal.CreateEventRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	req.ExternalID = r.PathValue("uid")
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := s.Now().UTC()
+	e, err := eventFrom(&req, now)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if st.feed(e.FeedID) < 0 {
+		writeError(w, http.StatusNotFound, "feed not found")
+		return
+	}
+	status := http.StatusCreated
+	i := slices.IndexFunc(st.events, func(old cal.Event) bool { return old.ExternalID == e.ExternalID })
+	except := ""
+	if i >= 0 {
+		except = st.events[i].ID
+	}
+	if st.uidTaken(e.FeedID, e.UID, except) {
+		writeError(w, http.StatusConflict, "uid already in use in this feed")
+		return
+	}
+	if i >= 0 {
+		if !revise(st.events[i], e) {
+			writeJSON(w, http.StatusOK, st.events[i])
+			return
+		}
+		st.events[i] = *e
+		status = http.StatusOK
+	} else {
+		e.ID, e.CreatedAt = newID(), now
+		st.events = append(st.events, *e)
+	}
+	if !s.save(w) {
+		return
+	}
+	writeJSON(w, status, e)
+}
+
+func (s *Server) updateEvent(w http.ResponseWriter, r *http.Request) {
+	var req cal.CreateEventRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := st.event(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	e, err := eventFrom(&req, s.Now().UTC())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if e.FeedID != st.events[i].FeedID {
+		writeError(w, http.StatusBadRequest, "feed_id can't be changed")
+		return
+	}
+	if st.uidTaken(e.FeedID, e.UID, st.events[i].ID) {
+		writeError(w, http.StatusConflict, "uid already in use in this feed")
+		return
+	}
+	if revise(st.events[i], e) {
+		st.events[i] = *e
+		if !s.save(w) {
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, st.events[i])
+}
+
+func (s *Server) getEvent(w http.ResponseWriter, r *http.Request) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := st.event(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, st.events[i])
+}
+
+// cancelEvent marks an event CANCELLED and raises its SEQUENCE, so
+// subscribers replace their copy. Cancelling twice changes nothing.
+func (s *Server) cancelEvent(w http.ResponseWriter, r *http.Request) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := st.event(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	e := &st.events[i]
+	if e.Status != "CANCELLED" {
+		e.Status = "CANCELLED"
+		e.Sequence++
+		e.UpdatedAt = s.Now().UTC()
+		if !s.save(w) {
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (s *Server) deleteEvent(w http.ResponseWriter, r *http.Request) {
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := st.event(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	st.events = slices.Delete(st.events, i, i+1)
+	if !s.save(w) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	q := cal.SearchQuery{Text: v.Get("q"), FeedIDs: v["feed_id"], Category: v.Get("category")}
+	for name, t := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if val := v.Get(name); val != "" {
+			var err error
+			if *t, err = time.Parse(time.RFC3339, val); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: want RFC 3339", name))
+				return
+			}
+		}
+	}
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	events := []cal.Event{}
+	for _, e := range st.events {
+		if q.Matches(&e) {
+			events = append(events, e)
+		}
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// serveICS publishes a feed at /<token>.ics, without authentication, as
+// calendar apps fetch it. Cancelled events stay in the feed, with their
+// raised SEQUENCE, unless SkipCancelled is set: a subscriber only removes
+// an event it is told was cancelled, and keeps one that just disappears.
+func (s *Server) serveICS(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	st := s.store
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	i := slices.IndexFunc(st.feeds, func(f cal.Feed) bool { return f.Token == token })
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	var events []cal.Event
+	for _, e := range st.events {
+		if s.SkipCancelled && e.Status == "CANCELLED" {
+			continue
+		}
+		if e.FeedID == st.feeds[i].ID {
+			events = append(events, s.zoned(e))
+		}
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	_ = ics.Write(w, ics.FromFeed(st.feeds[i], events))
+}
+
+// zoned returns e with its times in s.Location if it recurs and its start
+// has a bare UTC offset, as RFC 3339 times do, that is the one s.Location
+// has at that instant. Other events are published as they are.
+func (s *Server) zoned(e cal.Event) cal.Event {
+	loc := s.Location
+	if loc == nil || e.RRule == "" || e.AllDay || e.Start.Location().String() != "" {
+		return e
+	}
+	_, have := e.Start.Zone()
+	if _, want := e.Start.In(loc).Zone(); have != want {
+		return e
+	}
+	e.Start = e.Start.In(loc)
+	if e.End != nil {
+		end := e.End.In(loc)
+		e.End = &end
+	}
+	exdates := make([]time.Time, len(e.ExDates))
+	for i, t := range e.ExDates {
+		exdates[i] = t.In(loc)
+	}
+	e.ExDates = exdates
+	return e
+}
+
+// revise turns e into the next revision of the stored event old: its
+// identity carries over, and SEQUENCE goes one past old's, or to the
+// requested one if that is higher. It reports false, leaving old as it is, if nothing changed,
+// so subscribers have nothing to refetch.
+func revise(old cal.Event, e *cal.Event) bool {
+	e.ID, e.CreatedAt = old.ID, old.CreatedAt
+	if e.UID == "" {
+		e.UID = old.UID
+	}
+	if sameContent(old, *e) {
+		return false
+	}
+	e.Sequence = max(e.Sequence, old.Sequence+1)
+	return true
+}
+
+// sameContent reports whether a and b differ only in bookkeeping: their
+// revision and when they were changed. They are compared as JSON, as
+// parsed times in the same zone don't share a *time.Location.
+func sameContent(a, b cal.Event) bool {
+	a.Sequence, b.Sequence = 0, 0
+	a.UpdatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// eventFrom validates req and builds the event it describes, without an ID.
+func eventFrom(req *cal.CreateEventRequest, now time.Time) (*cal.Event, error) {
+	if req.FeedID == "" {
+		return nil, errors.New("feed_id is required")
+	}
+	if req.Summary == "" {
+		return nil, errors.New("summary is required")
+	}
+	start, err := time.Parse(time.RFC3339, req.Start)
+	if err != nil {
+		return nil, errors.New("start must be an RFC 3339 time")
+	}
+	e := &cal.Event{
+		FeedID:      req.FeedID,
+		Summary:     req.Summary,
+		Description: req.Description,
+		Location:    req.Location,
+		URL:         req.URL,
+		Start:       start,
+		AllDay:      req.AllDay,
+		Status:      strings.ToUpper(req.Status),
+		Categories:  req.Categories,
+		ExternalID:  req.ExternalID,
+		RRule:       req.RRule,
+		Alarms:      req.Alarms,
+		Attachments: req.Attachments,
+		UID:         req.UID,
+		Sequence:    req.Sequence,
+		UpdatedAt:   now,
+	}
+	if e.Status == "" {
+		e.Status = "CONFIRMED"
+	}
+	if e.Sequence < 0 {
+		return nil, errors.New("sequence must not be negative")
+	}
+	if req.End != "" {
+		end, err := time.Parse(time.RFC3339, req.End)
+		if err != nil {
+			return nil, errors.New("end must be an RFC 3339 time")
+		}
+		if end.Before(start) {
+			return nil, errors.New("end is before start")
+		}
+		e.End = &end
+	}
+	if req.Deadline != "" {
+		d, err := time.Parse(time.RFC3339, req.Deadline)
+		if err != nil {
+			return nil, errors.New("deadline must be an RFC 3339 time")
+		}
+		e.Deadline = &d
+	}
+	for _, x := range req.ExDates {
+		t, err := time.Parse(time.RFC3339, x)
+		if err != nil {
+			return nil, errors.New("exdates must be RFC 3339 times")
+		}
+		e.ExDates = append(e.ExDates, t)
+	}
+	for _, a := range req.Alarms {
+		if _, err := ics.ParseDuration(a); err != nil {
+			return nil, fmt.Errorf("alarm: %w", err)
+		}
+	}
+	for _, a := range req.Attachments {
+		if u, err := url.Parse(a); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("attachment %q is not an http(s) URL", a)
+		}
+	}
+	return e, nil
+}
+
+func (s *Server) feedResponse(r *http.Request, f cal.Feed) cal.CreateFeedResponse {
+	return cal.CreateFeedResponse{ID: f.ID, Name: f.Name, Token: f.Token, URL: s.baseURL(r) + "/" + f.Token + ".ics"}
+}
+
+// baseURL returns the URL the server is reached at by r's client.
+func (s *Server) baseURL(r *http.Request) string {
+	if s.BaseURL != "" {
+		return strings.TrimSuffix(s.BaseURL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// save persists the store, answering 500 if that fails. The caller holds
+// the store's lock.
+func (s *Server) save(w http.ResponseWriter) bool {
+	if err := s.store.save(); err != nil {
+		writeError(w, http.StatusInternalServerError, "save: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeListing writes v as JSON with an ETag of its content, or only 304
+// Not Modified when the request's If-None-Match already has it, so polling
+// clients can skip unchanged listings.
+func writeListing(w http.ResponseWriter, r *http.Request, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if strings.TrimSpace(tag) == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(data, '\n'))
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,474 @@
+package calserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/feedcheck"
+	"github.com/jredh-dev/pylon/internal/ics"
+)
+
+func newTestServer(t *testing.T, path string) (*cal.Client, *httptest.Server) {
+	t.Helper()
+	store, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	srv := httptest.NewServer(New(store))
+	t.Cleanup(srv.Close)
+	return cal.NewClient(srv.URL), srv
+}
+
+func TestFeedsAndEvents(t *testing.T) {
+	client, _ := newTestServer(t, "")
+
+	feed, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	if feed.Token != "team" || feed.URL != client.SubscribeURL("team") {
+		t.Errorf("unexpected feed %+v", feed)
+	}
+	if _, err := client.CreateFeed("Other", "team"); err == nil {
+		t.Error("expected a conflict for a taken slug")
+	}
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e, err := client.CreateEvent(&cal.CreateEventRequest{
+		FeedID: feed.ID, Summary: "Standup", Start: start.Format(time.RFC3339),
+		End: start.Add(15 * time.Minute).Format(time.RFC3339), Categories: "meeting",
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	if e.ID == "" || e.Status != "CONFIRMED" || !e.Start.Equal(start) {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if _, err := client.CreateEvent(&cal.CreateEventRequest{FeedID: feed.ID, Summary: "No start"}); err == nil {
+		t.Error("expected an error for a missing start")
+	}
+
+	got, err := client.GetEvent(e.ID)
+	if err != nil || got.Summary != "Standup" {
+		t.Errorf("GetEvent = %+v, %v", got, err)
+	}
+
+	req := &cal.CreateEventRequest{FeedID: feed.ID, Summary: "Meetup", Start: start.Format(time.RFC3339)}
+	first, created, err := client.UpsertEvent("meetup-1", req)
+	if err != nil || !created {
+		t.Fatalf("first upsert: created=%v err=%v", created, err)
+	}
+	req.Summary = "Meetup (moved)"
+	second, created, err := client.UpsertEvent("meetup-1", req)
+	if err != nil || created || second.ID != first.ID || second.Summary != "Meetup (moved)" {
+		t.Errorf("second upsert = %+v, created=%v, err=%v", second, created, err)
+	}
+
+	events, err := client.ListEvents(feed.ID)
+	if err != nil || len(events) != 2 {
+		t.Fatalf("ListEvents = %d events, %v", len(events), err)
+	}
+	found, err := client.SearchEvents(&cal.SearchQuery{Category: "meeting"})
+	if err != nil || len(found) != 1 || found[0].ID != e.ID {
+		t.Errorf("SearchEvents = %+v, %v", found, err)
+	}
+
+	if err := client.DeleteEvent(e.ID); err != nil {
+		t.Fatalf("DeleteEvent: %v", err)
+	}
+	if _, err := client.GetEvent(e.ID); err == nil || errors.Is(err, cal.ErrNotSupported) {
+		t.Errorf("GetEvent after delete = %v, want a not-found error", err)
+	}
+
+	if err := client.DeleteFeed(feed.ID); err != nil {
+		t.Fatalf("DeleteFeed: %v", err)
+	}
+	if feeds, _ := client.ListFeeds(); len(feeds) != 0 {
+		t.Errorf("feeds after delete = %+v", feeds)
+	}
+}
+
+func TestFeedURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		header  string
+		want    string
+	}{
+		{"request host", "", "", "http://cal.example/team.ics"},
+		{"forwarded proto", "", "https", "https://cal.example/team.ics"},
+		{"bogus forwarded proto", "", "gopher", "http://cal.example/team.ics"},
+		{"base URL", "https://pylon.example/cal/", "http", "https://pylon.example/cal/team.ics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := OpenStore("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := New(store)
+			s.BaseURL = tt.baseURL
+			req := httptest.NewRequest("POST", "http://cal.example/api/feeds", strings.NewReader(`{"name":"Team","slug":"team"}`))
+			if tt.header != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			var feed cal.CreateFeedResponse
+			if err := json.NewDecoder(rec.Body).Decode(&feed); err != nil {
+				t.Fatal(err)
+			}
+			if feed.URL != tt.want {
+				t.Errorf("URL = %q, want %q", feed.URL, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateAndRotate(t *testing.T) {
+	client, srv := newTestServer(t, "")
+	feed, err := client.CreateFeed("Team", "")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+
+	updated, err := client.UpdateFeed(feed.ID, &cal.UpdateFeedRequest{Name: "Team Berlin", Slug: "berlin"})
+	if err != nil || updated.Name != "Team Berlin" || updated.Token != "berlin" {
+		t.Fatalf("UpdateFeed = %+v, %v", updated, err)
+	}
+	rotated, err := client.RotateFeedToken(feed.ID, "")
+	if err != nil || rotated.Token == "berlin" {
+		t.Fatalf("RotateFeedToken = %+v, %v", rotated, err)
+	}
+	resp, err := http.Get(srv.URL + "/berlin.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("old token still served: %s", resp.Status)
+	}
+
+	info, err := client.ServerInfo()
+	if err != nil || info.APIVersion != cal.APIVersion || !info.Supports(cal.CapSearch) || info.Supports(cal.CapSubscribers) {
+		t.Errorf("ServerInfo = %+v, %v", info, err)
+	}
+	if _, err := client.Subscribers(feed.ID); !errors.Is(err, cal.ErrNotSupported) {
+		t.Errorf("Subscribers = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestServeICS(t *testing.T) {
+	client, srv := newTestServer(t, "")
+	feed, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for _, req := range []*cal.CreateEventRequest{
+		{FeedID: feed.ID, Summary: "Standup, daily", Start: start.Format(time.RFC3339), End: start.Add(15 * time.Minute).Format(time.RFC3339), Alarms: []string{"-PT10M"}},
+		{FeedID: feed.ID, Summary: "Launch", Start: "2026-04-01T00:00:00Z", AllDay: true, Deadline: "2026-03-31T12:00:00Z"},
+	} {
+		if _, err := client.CreateEvent(req); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+	}
+
+	report, err := feedcheck.Fetch(srv.Client(), client.SubscribeURL(feed.Token))
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	events, err := client.ListEvents(feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.Compare(events)
+	if !report.OK() {
+		t.Errorf("feed problems: %q", report.Problems)
+	}
+	if len(report.Events) != 2 {
+		t.Errorf("feed has %d events, want 2", len(report.Events))
+	}
+}
+
+func TestServeICSZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	store, err := OpenStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := New(store)
+	handler.Location = berlin
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	client := cal.NewClient(srv.URL)
+
+	feed, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	for _, req := range []*cal.CreateEventRequest{
+		{FeedID: feed.ID, Summary: "Weekly", Start: "2026-10-15T09:00:00+02:00", RRule: "FREQ=WEEKLY", ExDates: []string{"2026-10-29T09:00:00+01:00"}},
+		{FeedID: feed.ID, Summary: "Elsewhere", Start: "2026-10-15T09:00:00+05:00", RRule: "FREQ=WEEKLY"},
+		{FeedID: feed.ID, Summary: "Once", Start: "2026-10-15T09:00:00+02:00"},
+	} {
+		if _, err := client.CreateEvent(req); err != nil {
+			t.Fatalf("CreateEvent: %v", err)
+		}
+	}
+
+	resp, err := srv.Client().Get(client.SubscribeURL(feed.Token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	c, err := ics.Parse(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zones := map[string]string{}
+	for _, e := range c.Events {
+		zones[e.Summary] = e.Start.Location().String()
+		if e.Summary == "Weekly" && (len(e.ExDates) != 1 || e.ExDates[0].Location().String() != "Europe/Berlin") {
+			t.Errorf("Weekly exdates %v", e.ExDates)
+		}
+	}
+	want := map[string]string{"Weekly": "Europe/Berlin", "Elsewhere": "UTC", "Once": "UTC"}
+	for summary, zone := range want {
+		if zones[summary] != zone {
+			t.Errorf("%s published in %q, want %q", summary, zones[summary], zone)
+		}
+	}
+}
+
+func TestFeedICSSettings(t *testing.T) {
+	client, srv := newTestServer(t, "")
+	feed, err := client.CreateFeed("team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if _, err := client.CreateEvent(&cal.CreateEventRequest{FeedID: feed.ID, Summary: "Standup", Start: start.Format(time.RFC3339), Alarms: []string{"-PT10M"}}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	str := func(s string) *string { return &s }
+	omit := true
+	if _, err := client.UpdateFeed(feed.ID, &cal.UpdateFeedRequest{RefreshInterval: str("1 hour")}); err == nil {
+		t.Error("invalid refresh interval accepted")
+	}
+	updated, err := client.UpdateFeed(feed.ID, &cal.UpdateFeedRequest{
+		ProdID: str("-//Example//EN"), CalendarName: str("Team Berlin"), RefreshInterval: str("PT1H"), OmitAlarms: &omit,
+	})
+	if err != nil || updated.Name != "team" || updated.CalendarName != "Team Berlin" || !updated.OmitAlarms {
+		t.Fatalf("UpdateFeed = %+v, %v", updated, err)
+	}
+
+	fetch := func() *ics.Calendar {
+		t.Helper()
+		resp, err := srv.Client().Get(client.SubscribeURL("team"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		c, err := ics.Parse(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	c := fetch()
+	if c.ProdID != "-//Example//EN" || c.Name != "Team Berlin" || c.RefreshInterval != time.Hour || len(c.Events[0].Alarms) != 0 {
+		t.Errorf("feed with settings = %+v", c)
+	}
+
+	// Empty values restore the defaults; fields not given stay.
+	if _, err := client.UpdateFeed(feed.ID, &cal.UpdateFeedRequest{ProdID: str(""), CalendarName: str("")}); err != nil {
+		t.Fatal(err)
+	}
+	c = fetch()
+	if c.ProdID != ics.ProdID || c.Name != "team" || c.RefreshInterval != time.Hour || len(c.Events[0].Alarms) != 0 {
+		t.Errorf("feed after reset = %+v", c)
+	}
+}
+
+func TestCancelEvent(t *testing.T) {
+	store, _ := OpenStore("")
+	handler := New(store)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	client := cal.NewClient(srv.URL)
+
+	feed, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	ev, err := client.CreateEvent(&cal.CreateEventRequest{FeedID: feed.ID, Summary: "Standup", Start: "2026-03-02T09:00:00Z"})
+	if err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		got, err := client.CancelEvent(ev.ID)
+		if err != nil || got.Status != "CANCELLED" || got.Sequence != 1 {
+			t.Fatalf("CancelEvent #%d = %+v, %v", i+1, got, err)
+		}
+	}
+	if _, err := client.CancelEvent("missing"); err == nil || errors.Is(err, cal.ErrNotSupported) {
+		t.Errorf("CancelEvent(missing) = %v, want a not found error", err)
+	}
+
+	fetch := func() *ics.Calendar {
+		t.Helper()
+		resp, err := srv.Client().Get(client.SubscribeURL(feed.Token))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		c, err := ics.Parse(resp.Body)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		return c
+	}
+	c := fetch()
+	if c.Method != "PUBLISH" || len(c.Events) != 1 || c.Events[0].Status != "CANCELLED" || c.Events[0].Sequence != 1 {
+		t.Errorf("feed = %+v, want the event cancelled at SEQUENCE 1", c)
+	}
+
+	handler.SkipCancelled = true
+	if c := fetch(); len(c.Events) != 0 {
+		t.Errorf("with SkipCancelled the feed has %d events, want 0", len(c.Events))
+	}
+}
+
+func TestUpsertSequence(t *testing.T) {
+	client, srv := newTestServer(t, "")
+	feed, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	req := &cal.CreateEventRequest{FeedID: feed.ID, Summary: "Standup", Start: "2026-03-02T09:00:00+01:00", UID: "standup@example.com", Sequence: 3}
+
+	steps := []struct {
+		name    string
+		change  func()
+		wantSeq int
+	}{
+		{name: "imported", change: func() {}, wantSeq: 3},
+		{name: "unchanged", change: func() { req.Sequence = 0 }, wantSeq: 3},
+		{name: "edited", change: func() { req.Location = "Room 2" }, wantSeq: 4},
+		{name: "newer source", change: func() { req.Location, req.Sequence = "Room 3", 9 }, wantSeq: 9},
+		{name: "cancelled", change: func() { req.Status, req.Sequence = "CANCELLED", 0 }, wantSeq: 10},
+	}
+	for _, step := range steps {
+		step.change()
+		e, _, err := client.UpsertEvent("ext-1", req)
+		if err != nil {
+			t.Fatalf("%s: UpsertEvent: %v", step.name, err)
+		}
+		if e.Sequence != step.wantSeq || e.UID != "standup@example.com" {
+			t.Errorf("%s: sequence %d, uid %q; want %d", step.name, e.Sequence, e.UID, step.wantSeq)
+		}
+	}
+
+	all, err := client.ListEvents(feed.ID)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("ListEvents = %+v, %v", all, err)
+	}
+	patched, err := all[0].PatchRequest([]byte(`{"status": "CONFIRMED", "summary": "Daily standup"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, err := client.UpdateEvent(all[0].ID, patched); err != nil || e.Summary != "Daily standup" || e.Sequence != 11 || e.ID != all[0].ID {
+		t.Errorf("UpdateEvent = %+v, %v", e, err)
+	}
+	if e, err := client.UpdateEvent(all[0].ID, patched); err != nil || e.Sequence != 11 {
+		t.Errorf("unchanged UpdateEvent = %+v, %v; want sequence 11 kept", e, err)
+	}
+
+	dup := &cal.CreateEventRequest{FeedID: feed.ID, Summary: "Copy", Start: "2026-03-02T09:00:00Z", UID: "standup@example.com"}
+	var apiErr *cal.APIError
+	if _, err := client.CreateEvent(dup); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("CreateEvent with a taken UID = %v, want 409", err)
+	}
+
+	resp, err := srv.Client().Get(client.SubscribeURL(feed.Token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	c, err := ics.Parse(resp.Body)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(c.Events) != 1 || c.Events[0].UID != "standup@example.com" || c.Events[0].Sequence != 11 {
+		t.Errorf("feed = %+v", c.Events)
+	}
+}
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cal.json")
+	client, _ := newTestServer(t, path)
+	feed, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+	if _, err := client.CreateEvent(&cal.CreateEventRequest{FeedID: feed.ID, Summary: "Standup", Start: "2026-03-02T09:00:00Z"}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+
+	reopened, _ := newTestServer(t, path)
+	events, err := reopened.ListEvents(feed.ID)
+	if err != nil || len(events) != 1 || events[0].Summary != "Standup" {
+		t.Errorf("after reopening: %+v, %v", events, err)
+	}
+}
+
+func TestListingETag(t *testing.T) {
+	client, srv := newTestServer(t, "")
+	feed, err := client.CreateFeed("Team", "team")
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+
+	get := func(etag string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/feeds/"+feed.ID+"/events", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	first := get("")
+	etag := first.Header.Get("ETag")
+	if first.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("first listing: %d, ETag %q", first.StatusCode, etag)
+	}
+	if resp := get(`"other", ` + etag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("unchanged listing: %d, want 304", resp.StatusCode)
+	}
+
+	if _, err := client.CreateEvent(&cal.CreateEventRequest{FeedID: feed.ID, Summary: "Standup", Start: "2026-03-02T09:00:00Z"}); err != nil {
+		t.Fatalf("CreateEvent: %v", err)
+	}
+	resp := get(etag)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Errorf("changed listing: %d, ETag %q", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
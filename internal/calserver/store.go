@@ -0,0 +1,134 @@
+package calserver
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+// Store holds feeds and events in memory and, when it has a path, saves
+// them to a JSON file after every change.
+type Store struct {
+	path string
+
+	mu     sync.Mutex
+	feeds  []cal.Feed
+	events []cal.Event
+}
+
+type storeFile struct {
+	Feeds  []cal.Feed  `json:"feeds"`
+	Events []cal.Event `json:"events"`
+}
+
+// OpenStore loads the store at path, starting empty if the file doesn't
+// exist yet. An empty path keeps everything in memory.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s.feeds, s.events = f.Feeds, f.Events
+	return s, nil
+}
+
+// save writes the store via a temp file and rename, so a crash never
+// leaves it truncated. The caller holds s.mu.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(storeFile{Feeds: s.feeds, Events: s.events}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".cal-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// feed returns the index of the feed with id, or -1. The caller holds s.mu.
+func (s *Store) feed(id string) int {
+	for i, f := range s.feeds {
+		if f.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// event returns the index of the event with id, or -1. The caller holds
+// s.mu.
+func (s *Store) event(id string) int {
+	for i, e := range s.events {
+		if e.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// tokenTaken reports whether a feed other than except uses token. The
+// caller holds s.mu.
+func (s *Store) tokenTaken(token, except string) bool {
+	for _, f := range s.feeds {
+		if f.Token == token && f.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+// uidTaken reports whether an event in feedID other than except publishes
+// uid. Events without a UID publish their ID, which is never reused. The
+// caller holds s.mu.
+func (s *Store) uidTaken(feedID, uid, except string) bool {
+	if uid == "" {
+		return false
+	}
+	for _, e := range s.events {
+		if e.FeedID == feedID && e.ID != except && e.ICalUID() == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// newID returns a random version 4 UUID.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
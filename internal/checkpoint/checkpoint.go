@@ -0,0 +1,109 @@
+// Package checkpoint keeps the progress of a long export in an append-only
+// NDJSON file, one record per line, so an interrupted run can pick up after
+// the last record saved instead of starting over.
+package checkpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Read returns the records saved at path. A missing file is an error
+// matching os.ErrNotExist. A final line cut short by a crash is dropped,
+// since its record was never fully saved.
+func Read[T any](path string) ([]T, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []T
+	for n, line := range bytes.Split(complete(data), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var r T
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n+1, err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// complete returns data up to the end of its last complete record; every
+// complete record ends in a newline.
+func complete(data []byte) []byte {
+	return data[:bytes.LastIndexByte(data, '\n')+1]
+}
+
+// Writer appends records to a checkpoint file.
+type Writer[T any] struct {
+	f *os.File
+}
+
+// Create opens the checkpoint at path for writing, continuing it if resume
+// is set and starting it afresh otherwise. A resumed checkpoint loses the
+// final line cut short by a crash, which Read skips, so new records don't
+// run into it.
+func Create[T any](path string, resume bool) (*Writer[T], error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if !resume {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		return &Writer[T]{f: f}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(complete(data)); n < len(data) {
+		if err := f.Truncate(int64(n)); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &Writer[T]{f: f}, nil
+}
+
+// Write saves records and syncs them to disk, so they survive a crash
+// right after.
+func (w *Writer[T]) Write(records []T) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	if _, err := w.f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return w.f.Sync()
+}
+
+// Close closes the file, keeping it for a later resume.
+func (w *Writer[T]) Close() error {
+	return w.f.Close()
+}
+
+// Done closes and removes the checkpoint once the export has finished.
+func (w *Writer[T]) Done() error {
+	err := w.f.Close()
+	if rerr := os.Remove(w.f.Name()); rerr != nil && !errors.Is(rerr, os.ErrNotExist) {
+		return rerr
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package checkpoint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type record struct {
+	ID string `json:"id"`
+}
+
+func TestResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "export.ndjson")
+
+	if _, err := Read[record](path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Read(missing) error = %v, want os.ErrNotExist", err)
+	}
+
+	w, err := Create[record](path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]record{{"1"}, {"2"}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	// A resumed run appends; a fresh one starts over.
+	w, _ = Create[record](path, true)
+	_ = w.Write([]record{{"3"}})
+	_ = w.Close()
+	got, err := Read[record](path)
+	if err != nil || !reflect.DeepEqual(got, []record{{"1"}, {"2"}, {"3"}}) {
+		t.Fatalf("Read after resume = %v, %v", got, err)
+	}
+
+	w, _ = Create[record](path, false)
+	_ = w.Write([]record{{"9"}})
+	if err := w.Done(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("checkpoint still exists after Done: %v", err)
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.ndjson")
+	if err := os.WriteFile(path, []byte("{\"id\":\"1\"}\n{\"id\":\"2\"}\n{\"id\":"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read[record](path)
+	if err != nil || !reflect.DeepEqual(got, []record{{"1"}, {"2"}}) {
+		t.Fatalf("Read = %v, %v; want the two complete records", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{\"id\":\"1\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read[record](path); err == nil {
+		t.Error("want error for a corrupt complete line")
+	}
+}
+
+func TestResumeAfterPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.ndjson")
+	if err := os.WriteFile(path, []byte("{\"id\":\"1\"}\n{\"id\":"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := Create[record](path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]record{{"2"}, {"3"}}); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	got, err := Read[record](path)
+	if err != nil || !reflect.DeepEqual(got, []record{{"1"}, {"2"}, {"3"}}) {
+		t.Fatalf("Read after resume = %v, %v; want the partial line replaced", got, err)
+	}
+}
@@ -6,25 +6,111 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"strconv"
+
+	"github.com/jredh-dev/pylon/internal/httpx"
+	"github.com/jredh-dev/pylon/internal/timeutil"
 )
 
 // Config holds pylon configuration.
 type Config struct {
 	CalURL string // base URL for the cal service API
 
+	// CalAPIKey, when set, is sent with every cal API request, as a bearer
+	// token unless CalAuthHeader names a different header.
+	CalAPIKey     string
+	CalAuthHeader string
+
+	CalDefaultFeed string // feed used by commands that don't get --feed
+
+	// CalNotifyChanges posts a summary to Discord when pylon updates or
+	// cancels an event, unless --notify-changes=false is given;
+	// CalNotifyChannel is where (default: the webhook).
+	CalNotifyChanges bool
+	CalNotifyChannel string
+
 	DiscordWebhook   string // Discord webhook URL for sending messages
 	DiscordBotToken  string // Discord bot token for reading messages/channels
 	DiscordGuildID   string // Default Discord guild (server) ID
 	DiscordChannelID string // Default Discord channel ID for reading
+
+	// DiscordAttachmentChannel is the channel (ID or alias) that hosts the
+	// files uploaded by pylon cal event attach.
+	DiscordAttachmentChannel string
+
+	SlackBotToken  string // Slack bot token (xoxb-...) for reading and posting
+	SlackWebhook   string // Slack incoming webhook URL for posting
+	SlackChannelID string // Default Slack channel ID
+
+	// ChannelAliases maps names from the [channels] section to channel IDs,
+	// so --channel can take a name instead of an ID.
+	ChannelAliases map[string]string
+
+	// Views maps names from the [views] section to saved command lines,
+	// run with `pylon view run <name>`.
+	Views map[string]string
+
+	// DiscordAllowModeration enables the kick/ban/timeout commands, which
+	// are off unless explicitly turned on.
+	DiscordAllowModeration bool
+
+	HTTPRetries int // retries for transient API failures (429/5xx)
+
+	// HTTPProxy, HTTPCAFile and HTTPInsecureSkipVerify configure the
+	// transport of the cal and Discord clients; see httpx.TransportOptions.
+	// Without a proxy, HTTP_PROXY and HTTPS_PROXY apply as usual.
+	HTTPProxy              string
+	HTTPCAFile             string
+	HTTPInsecureSkipVerify bool
+
+	// ReadOnly makes the cal and Discord clients refuse every request that
+	// could change something, so a shared token can't be used to edit.
+	ReadOnly bool
+
+	// AnnounceUTM* are added as utm_* parameters to event URLs in Discord
+	// announcements, and AnnounceShortener, when set, is the shortener
+	// endpoint they are passed through.
+	AnnounceUTMSource   string
+	AnnounceUTMMedium   string
+	AnnounceUTMCampaign string
+	AnnounceShortener   string
+
+	// Retention* drive pylon retention: events that started longer than
+	// RetentionEvents ago are archived to RetentionArchiveDir and deleted,
+	// and files in RetentionExportDir older than RetentionExportKeep are
+	// removed. The ages are kept as written ("2y", "90d"); empty disables
+	// that half of the policy.
+	RetentionEvents     string
+	RetentionArchiveDir string
+	RetentionExportKeep string
+	RetentionExportDir  string
+
+	// Daemon* configure the watchdog of long-running jobs (pylon remind,
+	// pylon retention): after DaemonFailureThreshold consecutive failures
+	// (0 for the default) a job notifies DaemonAdminChannel and/or
+	// DaemonAdminUser, by direct message.
+	DaemonAdminChannel     string
+	DaemonAdminUser        string
+	DaemonFailureThreshold int
+
+	// GCalCredentials is the Google Cloud credentials file pylon cal sync
+	// gcal reads with: a service account key or an OAuth client.
+	GCalCredentials string
+
+	Language string // UI language code for user-facing messages (e.g. "es")
+
+	file    string              // config file being parsed, for sources
+	sources map[string][]Source // by key name, in the order applied
 }
 
-// Load reads configuration from ~/.pylonrc (INI-style sections), then applies
-// environment variable overrides. Env vars always take precedence over the
-// config file. If ~/.pylonrc does not exist, only env vars are used.
+// Load reads configuration from the config file (INI-style sections, see
+// Path), then applies environment variable overrides. Env vars always take
+// precedence over the config file. If no config file exists, only env vars
+// are used.
 func Load() (*Config, error) {
 	cfg := &Config{
-		CalURL: "http://localhost:8085",
+		CalURL:      "http://localhost:8085",
+		HTTPRetries: 3,
 	}
 
 	// Load from file first.
@@ -33,36 +119,88 @@ func Load() (*Config, error) {
 	}
 
 	// Env vars override file values.
-	cfg.applyEnv()
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
 
-// loadFile reads ~/.pylonrc if it exists. The file uses INI-style sections:
+// loadFile reads the config file if it exists. The file uses INI-style
+// sections:
 //
 //	[cal]
 //	url = http://localhost:8085
+//	api_key = ...
+//	auth_header = X-Api-Key
+//	default_feed = ...
+//	notify_changes = false
+//	notify_channel = ...
 //
 //	[discord]
 //	webhook = https://discord.com/api/webhooks/...
 //	bot_token = ...
 //	guild_id = ...
 //	channel_id = ...
+//	attachment_channel = ...
+//	allow_moderation = false
+//
+//	[slack]
+//	bot_token = xoxb-...
+//	webhook = https://hooks.slack.com/services/...
+//	channel_id = C0123456789
+//
+//	[http]
+//	retries = 3
+//	proxy = http://proxy.corp:3128
+//	ca_file = /etc/ssl/corp-ca.pem
+//	insecure_skip_verify = false
+//	read_only = false
+//
+//	[announce]
+//	utm_source = discord
+//	utm_medium = social
+//	utm_campaign = ...
+//	shortener = https://is.gd/create.php?format=simple&url={url}
+//
+//	[retention]
+//	events_older_than = 2y
+//	archive_dir = /var/lib/pylon/archive
+//	discord_export_keep = 90d
+//	export_dir = /var/lib/pylon/exports
+//
+//	[daemon]
+//	admin_channel = ops
+//	admin_user = 1234567890
+//	failure_threshold = 3
+//
+//	[gcal]
+//	credentials = /etc/pylon/google.json
+//
+//	[ui]
+//	language = es
+//
+//	[channels]
+//	ops = 1234567890
+//
+//	[views]
+//	standup = cal search --category work --from today --to +1d
 func (c *Config) loadFile() error {
-	path, err := rcPath()
+	path, explicit, err := resolvePath()
 	if err != nil {
 		return nil // can't determine home dir, skip file
 	}
 
 	f, err := os.Open(path)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) && !explicit {
 			return nil // no config file is fine
 		}
-		return fmt.Errorf("open %s: %w", path, err)
+		return err
 	}
 	defer f.Close()
 
+	c.file = path
 	return c.parse(f)
 }
 
@@ -70,43 +208,56 @@ func (c *Config) loadFile() error {
 func (c *Config) parse(r io.Reader) error {
 	scanner := bufio.NewScanner(r)
 	section := ""
+	lineNo := 0
 
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		lineNo++
+		line := scanner.Text()
 
-		// Skip empty lines and comments.
-		if line == "" || strings.HasPrefix(line, "#") {
+		if name, ok := sectionHeader(line); ok {
+			section = name
 			continue
 		}
 
-		// Section header.
-		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
-			section = strings.TrimSpace(line[1 : len(line)-1])
+		// Skips blanks, comments and malformed lines.
+		key, value, ok := splitLine(line)
+		if !ok {
 			continue
 		}
 
-		// Key = value.
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
+		if err := c.set(section, key, value); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if k, ok := LookupKey(section + "." + key); ok {
+			c.record(k.Name, Source{Path: c.file, Line: lineNo, Value: value})
 		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		c.set(section, key, value)
 	}
 
 	return scanner.Err()
 }
 
-// set applies a single config value from the given section and key.
-func (c *Config) set(section, key, value string) {
+// set applies a single config value from the given section and key. Unknown
+// sections and keys are ignored; malformed values are an error.
+func (c *Config) set(section, key, value string) error {
 	switch section {
 	case "cal":
 		switch key {
 		case "url":
 			c.CalURL = value
+		case "api_key":
+			c.CalAPIKey = value
+		case "auth_header":
+			c.CalAuthHeader = value
+		case "default_feed":
+			c.CalDefaultFeed = value
+		case "notify_changes":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("[cal] notify_changes: invalid boolean %q", value)
+			}
+			c.CalNotifyChanges = b
+		case "notify_channel":
+			c.CalNotifyChannel = value
 		}
 	case "discord":
 		switch key {
@@ -118,15 +269,177 @@ func (c *Config) set(section, key, value string) {
 			c.DiscordGuildID = value
 		case "channel_id":
 			c.DiscordChannelID = value
+		case "attachment_channel":
+			c.DiscordAttachmentChannel = value
+		case "allow_moderation":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("[discord] allow_moderation: invalid boolean %q", value)
+			}
+			c.DiscordAllowModeration = b
+		}
+	case "slack":
+		switch key {
+		case "bot_token":
+			c.SlackBotToken = value
+		case "webhook":
+			c.SlackWebhook = value
+		case "channel_id":
+			c.SlackChannelID = value
+		}
+	case "http":
+		switch key {
+		case "retries":
+			n, err := parseRetries(value)
+			if err != nil {
+				return fmt.Errorf("[http] retries: %w", err)
+			}
+			c.HTTPRetries = n
+		case "proxy":
+			if _, err := httpx.ParseProxy(value); err != nil {
+				return fmt.Errorf("[http] proxy: %w", err)
+			}
+			c.HTTPProxy = value
+		case "ca_file":
+			c.HTTPCAFile = value
+		case "insecure_skip_verify":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("[http] insecure_skip_verify: invalid boolean %q", value)
+			}
+			c.HTTPInsecureSkipVerify = b
+		case "read_only":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("[http] read_only: invalid boolean %q", value)
+			}
+			c.ReadOnly = b
+		}
+	case "announce":
+		switch key {
+		case "utm_source":
+			c.AnnounceUTMSource = value
+		case "utm_medium":
+			c.AnnounceUTMMedium = value
+		case "utm_campaign":
+			c.AnnounceUTMCampaign = value
+		case "shortener":
+			c.AnnounceShortener = value
+		}
+	case "retention":
+		switch key {
+		case "events_older_than":
+			if err := parseAge(value); err != nil {
+				return fmt.Errorf("[retention] events_older_than: %w", err)
+			}
+			c.RetentionEvents = value
+		case "archive_dir":
+			c.RetentionArchiveDir = value
+		case "discord_export_keep":
+			if err := parseAge(value); err != nil {
+				return fmt.Errorf("[retention] discord_export_keep: %w", err)
+			}
+			c.RetentionExportKeep = value
+		case "export_dir":
+			c.RetentionExportDir = value
+		}
+	case "daemon":
+		switch key {
+		case "admin_channel":
+			c.DaemonAdminChannel = value
+		case "admin_user":
+			c.DaemonAdminUser = value
+		case "failure_threshold":
+			n, err := parseThreshold(value)
+			if err != nil {
+				return fmt.Errorf("[daemon] failure_threshold: %w", err)
+			}
+			c.DaemonFailureThreshold = n
 		}
+	case "gcal":
+		switch key {
+		case "credentials":
+			c.GCalCredentials = value
+		}
+	case "ui":
+		switch key {
+		case "language":
+			c.Language = value
+		}
+	case "channels":
+		if c.ChannelAliases == nil {
+			c.ChannelAliases = make(map[string]string)
+		}
+		c.ChannelAliases[key] = value
+	case "views":
+		if c.Views == nil {
+			c.Views = make(map[string]string)
+		}
+		c.Views[key] = value
+	}
+	return nil
+}
+
+// Channel resolves a channel alias to its ID. Anything that isn't an alias
+// is returned unchanged.
+func (c *Config) Channel(s string) string {
+	if id, ok := c.ChannelAliases[s]; ok {
+		return id
+	}
+	return s
+}
+
+// parseRetries parses a non-negative retry count.
+func parseRetries(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid retry count %q", value)
+	}
+	return n, nil
+}
+
+// parseThreshold parses a positive failure count.
+func parseThreshold(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid failure count %q", value)
+	}
+	return n, nil
+}
+
+// parseAge checks a retention age such as "2y" or "90d".
+func parseAge(value string) error {
+	d, err := timeutil.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid age %q: want e.g. 90d or 2y", value)
 	}
+	return nil
 }
 
 // applyEnv overrides config values with environment variables when set.
-func (c *Config) applyEnv() {
+func (c *Config) applyEnv() error {
 	if v := os.Getenv("PYLON_CAL_URL"); v != "" {
 		c.CalURL = v
 	}
+	if v := os.Getenv("PYLON_CAL_API_KEY"); v != "" {
+		c.CalAPIKey = v
+	}
+	if v := os.Getenv("PYLON_CAL_AUTH_HEADER"); v != "" {
+		c.CalAuthHeader = v
+	}
+	if v := os.Getenv("PYLON_CAL_DEFAULT_FEED"); v != "" {
+		c.CalDefaultFeed = v
+	}
+	if v := os.Getenv("PYLON_CAL_NOTIFY_CHANGES"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("PYLON_CAL_NOTIFY_CHANGES: invalid boolean %q", v)
+		}
+		c.CalNotifyChanges = b
+	}
+	if v := os.Getenv("PYLON_CAL_NOTIFY_CHANNEL"); v != "" {
+		c.CalNotifyChannel = v
+	}
 	if v := os.Getenv("PYLON_DISCORD_WEBHOOK"); v != "" {
 		c.DiscordWebhook = v
 	}
@@ -139,6 +452,183 @@ func (c *Config) applyEnv() {
 	if v := os.Getenv("PYLON_DISCORD_CHANNEL_ID"); v != "" {
 		c.DiscordChannelID = v
 	}
+	if v := os.Getenv("PYLON_DISCORD_ATTACHMENT_CHANNEL"); v != "" {
+		c.DiscordAttachmentChannel = v
+	}
+	if v := os.Getenv("PYLON_DISCORD_ALLOW_MODERATION"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("PYLON_DISCORD_ALLOW_MODERATION: invalid boolean %q", v)
+		}
+		c.DiscordAllowModeration = b
+	}
+	if v := os.Getenv("PYLON_SLACK_BOT_TOKEN"); v != "" {
+		c.SlackBotToken = v
+	}
+	if v := os.Getenv("PYLON_SLACK_WEBHOOK"); v != "" {
+		c.SlackWebhook = v
+	}
+	if v := os.Getenv("PYLON_SLACK_CHANNEL_ID"); v != "" {
+		c.SlackChannelID = v
+	}
+	if v := os.Getenv("PYLON_HTTP_RETRIES"); v != "" {
+		n, err := parseRetries(v)
+		if err != nil {
+			return fmt.Errorf("PYLON_HTTP_RETRIES: %w", err)
+		}
+		c.HTTPRetries = n
+	}
+	if v := os.Getenv("PYLON_HTTP_PROXY"); v != "" {
+		if _, err := httpx.ParseProxy(v); err != nil {
+			return fmt.Errorf("PYLON_HTTP_PROXY: %w", err)
+		}
+		c.HTTPProxy = v
+	}
+	if v := os.Getenv("PYLON_HTTP_CA_FILE"); v != "" {
+		c.HTTPCAFile = v
+	}
+	if v := os.Getenv("PYLON_HTTP_INSECURE_SKIP_VERIFY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("PYLON_HTTP_INSECURE_SKIP_VERIFY: invalid boolean %q", v)
+		}
+		c.HTTPInsecureSkipVerify = b
+	}
+	if v := os.Getenv("PYLON_READ_ONLY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("PYLON_READ_ONLY: invalid boolean %q", v)
+		}
+		c.ReadOnly = b
+	}
+	if v := os.Getenv("PYLON_ANNOUNCE_UTM_SOURCE"); v != "" {
+		c.AnnounceUTMSource = v
+	}
+	if v := os.Getenv("PYLON_ANNOUNCE_UTM_MEDIUM"); v != "" {
+		c.AnnounceUTMMedium = v
+	}
+	if v := os.Getenv("PYLON_ANNOUNCE_UTM_CAMPAIGN"); v != "" {
+		c.AnnounceUTMCampaign = v
+	}
+	if v := os.Getenv("PYLON_ANNOUNCE_SHORTENER"); v != "" {
+		c.AnnounceShortener = v
+	}
+	if v := os.Getenv("PYLON_RETENTION_EVENTS_OLDER_THAN"); v != "" {
+		if err := parseAge(v); err != nil {
+			return fmt.Errorf("PYLON_RETENTION_EVENTS_OLDER_THAN: %w", err)
+		}
+		c.RetentionEvents = v
+	}
+	if v := os.Getenv("PYLON_RETENTION_ARCHIVE_DIR"); v != "" {
+		c.RetentionArchiveDir = v
+	}
+	if v := os.Getenv("PYLON_RETENTION_DISCORD_EXPORT_KEEP"); v != "" {
+		if err := parseAge(v); err != nil {
+			return fmt.Errorf("PYLON_RETENTION_DISCORD_EXPORT_KEEP: %w", err)
+		}
+		c.RetentionExportKeep = v
+	}
+	if v := os.Getenv("PYLON_RETENTION_EXPORT_DIR"); v != "" {
+		c.RetentionExportDir = v
+	}
+	if v := os.Getenv("PYLON_DAEMON_ADMIN_CHANNEL"); v != "" {
+		c.DaemonAdminChannel = v
+	}
+	if v := os.Getenv("PYLON_DAEMON_ADMIN_USER"); v != "" {
+		c.DaemonAdminUser = v
+	}
+	if v := os.Getenv("PYLON_DAEMON_FAILURE_THRESHOLD"); v != "" {
+		n, err := parseThreshold(v)
+		if err != nil {
+			return fmt.Errorf("PYLON_DAEMON_FAILURE_THRESHOLD: %w", err)
+		}
+		c.DaemonFailureThreshold = n
+	}
+	if v := os.Getenv("PYLON_GCAL_CREDENTIALS"); v != "" {
+		c.GCalCredentials = v
+	}
+	if v := os.Getenv("PYLON_LANGUAGE"); v != "" {
+		c.Language = v
+	}
+	for _, k := range Keys {
+		if v := os.Getenv(k.Env); v != "" {
+			c.record(k.Name, Source{Env: k.Env, Value: v})
+		}
+	}
+	return nil
+}
+
+// override is the config file chosen with SetPath (the --config flag).
+var override string
+
+// SetPath makes Load and Path use the config file at path, taking
+// precedence over PYLON_CONFIG and the default locations. An empty path
+// restores the default lookup.
+func SetPath(path string) {
+	override = path
+}
+
+// PathOrigin names what chose the config file: "--config" after SetPath,
+// "PYLON_CONFIG" when that is set, or "" for the default lookup.
+func PathOrigin() string {
+	switch {
+	case override != "":
+		return "--config"
+	case os.Getenv("PYLON_CONFIG") != "":
+		return "PYLON_CONFIG"
+	}
+	return ""
+}
+
+// Path returns the config file pylon reads and `pylon config set` writes.
+// In order of precedence:
+//
+//  1. the file given to SetPath (--config)
+//  2. $PYLON_CONFIG
+//  3. ~/.pylonrc, if it exists
+//  4. pylon/config in the user config directory ($XDG_CONFIG_HOME or
+//     ~/.config on Linux, ~/Library/Application Support on macOS,
+//     %AppData% on Windows), if it exists
+//  5. ~/.pylonrc, the default for new files
+func Path() (string, error) {
+	path, _, err := resolvePath()
+	return path, err
+}
+
+// resolvePath implements Path. explicit reports whether the location was
+// chosen by the user, in which case a missing file is an error for Load.
+func resolvePath() (path string, explicit bool, err error) {
+	if override != "" {
+		return override, true, nil
+	}
+	if p := os.Getenv("PYLON_CONFIG"); p != "" {
+		return p, true, nil
+	}
+	rc, err := rcPath()
+	if err != nil {
+		return "", false, err
+	}
+	if _, err := os.Stat(rc); err == nil {
+		return rc, false, nil
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		p := filepath.Join(dir, "pylon", "config")
+		if _, err := os.Stat(p); err == nil {
+			return p, false, nil
+		}
+	}
+	return rc, false, nil
+}
+
+// StateDir returns the directory for pylon's persistent runtime state
+// (reminder bookkeeping and the like): $XDG_STATE_HOME/pylon, falling back to
+// ~/.local/state/pylon, or %LocalAppData%\pylon on Windows. PYLON_STATE_DIR
+// overrides these. The directory is not created.
+func StateDir() (string, error) {
+	if dir := os.Getenv("PYLON_STATE_DIR"); dir != "" {
+		return dir, nil
+	}
+	return defaultStateDir()
 }
 
 // rcPath returns the path to ~/.pylonrc.
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -10,6 +12,7 @@ import (
 func TestLoadDefaults(t *testing.T) {
 	// Clear all env vars to ensure defaults.
 	t.Setenv("PYLON_CAL_URL", "")
+	t.Setenv("PYLON_CAL_API_KEY", "")
 	t.Setenv("PYLON_DISCORD_WEBHOOK", "")
 	t.Setenv("PYLON_DISCORD_BOT_TOKEN", "")
 	t.Setenv("PYLON_DISCORD_GUILD_ID", "")
@@ -140,6 +143,47 @@ url = http://custom.example.com
 	}
 }
 
+func TestChannelAliases(t *testing.T) {
+	input := `[discord]
+channel_id = 111
+
+[channels]
+ops = 222
+`
+
+	cfg := &Config{}
+	if err := cfg.parse(strings.NewReader(input)); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	tests := map[string]string{"ops": "222", "333": "333", "": ""}
+	for in, want := range tests {
+		if got := cfg.Channel(in); got != want {
+			t.Errorf("Channel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestViews(t *testing.T) {
+	input := `[views]
+standup = cal search --category work --from today --to '+1d'
+ops=discord read --channel ops --limit 20
+`
+
+	cfg := &Config{}
+	if err := cfg.parse(strings.NewReader(input)); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	want := map[string]string{
+		"standup": "cal search --category work --from today --to '+1d'",
+		"ops":     "discord read --channel ops --limit 20",
+	}
+	if !maps.Equal(cfg.Views, want) {
+		t.Errorf("Views = %q, want %q", cfg.Views, want)
+	}
+}
+
 func TestParseUnknownKeyIgnored(t *testing.T) {
 	input := `[cal]
 url = http://example.com
@@ -228,7 +272,9 @@ channel_id = file-channel
 	t.Setenv("PYLON_DISCORD_GUILD_ID", "")
 	t.Setenv("PYLON_DISCORD_CHANNEL_ID", "")
 
-	cfg.applyEnv()
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
 
 	// Env set -> overrides file.
 	if cfg.CalURL != "http://from-env.example.com" {
@@ -328,3 +374,353 @@ url = http://only-cal.example.com
 		t.Errorf("DiscordWebhook = %q, expected empty", cfg.DiscordWebhook)
 	}
 }
+
+func TestParseHTTPRetries(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{name: "valid", input: "[http]\nretries = 5\n", want: 5},
+		{name: "zero disables", input: "[http]\nretries = 0\n", want: 0},
+		{name: "not a number", input: "[http]\nretries = lots\n", wantErr: true},
+		{name: "negative", input: "[http]\nretries = -1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{HTTPRetries: 3}
+			err := cfg.parse(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), "line 2") {
+					t.Errorf("expected line number in error, got %q", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if cfg.HTTPRetries != tt.want {
+				t.Errorf("HTTPRetries = %d, want %d", cfg.HTTPRetries, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHTTPTransport(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name:  "all set",
+			input: "[http]\nproxy = proxy.corp:3128\nca_file = /etc/ssl/corp.pem\ninsecure_skip_verify = true\n",
+			want:  Config{HTTPProxy: "proxy.corp:3128", HTTPCAFile: "/etc/ssl/corp.pem", HTTPInsecureSkipVerify: true},
+		},
+		{name: "bad proxy", input: "[http]\nproxy = ftp://proxy.corp\n", wantErr: true},
+		{name: "bad boolean", input: "[http]\ninsecure_skip_verify = sometimes\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			err := cfg.parse(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if cfg.HTTPProxy != tt.want.HTTPProxy || cfg.HTTPCAFile != tt.want.HTTPCAFile || cfg.HTTPInsecureSkipVerify != tt.want.HTTPInsecureSkipVerify {
+				t.Errorf("got proxy=%q ca_file=%q insecure=%v", cfg.HTTPProxy, cfg.HTTPCAFile, cfg.HTTPInsecureSkipVerify)
+			}
+		})
+	}
+}
+
+func TestHTTPRetriesEnv(t *testing.T) {
+	cfg := &Config{HTTPRetries: 3}
+	t.Setenv("PYLON_HTTP_RETRIES", "1")
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if cfg.HTTPRetries != 1 {
+		t.Errorf("HTTPRetries = %d, want 1", cfg.HTTPRetries)
+	}
+
+	t.Setenv("PYLON_HTTP_RETRIES", "nope")
+	if err := cfg.applyEnv(); err == nil {
+		t.Fatal("expected error for invalid PYLON_HTTP_RETRIES")
+	}
+}
+
+func TestReadOnlyEnv(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parse(strings.NewReader("[http]\nread_only = true\n")); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !cfg.ReadOnly {
+		t.Error("read_only = true not applied")
+	}
+
+	t.Setenv("PYLON_READ_ONLY", "0")
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if cfg.ReadOnly {
+		t.Error("PYLON_READ_ONLY=0 did not override the file")
+	}
+
+	t.Setenv("PYLON_READ_ONLY", "maybe")
+	if err := cfg.applyEnv(); err == nil {
+		t.Fatal("expected error for invalid PYLON_READ_ONLY")
+	}
+}
+
+func TestParseUILanguage(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parse(strings.NewReader("[ui]\nlanguage = es\n")); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if cfg.Language != "es" {
+		t.Errorf("Language = %q, want %q", cfg.Language, "es")
+	}
+
+	t.Setenv("PYLON_LANGUAGE", "de")
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if cfg.Language != "de" {
+		t.Errorf("Language = %q, want env override %q", cfg.Language, "de")
+	}
+}
+
+func TestParseCalDefaultFeed(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parse(strings.NewReader("[cal]\ndefault_feed = 3f2a\n")); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if cfg.CalDefaultFeed != "3f2a" {
+		t.Errorf("CalDefaultFeed = %q, want %q", cfg.CalDefaultFeed, "3f2a")
+	}
+
+	t.Setenv("PYLON_CAL_DEFAULT_FEED", "9b1c")
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if cfg.CalDefaultFeed != "9b1c" {
+		t.Errorf("CalDefaultFeed = %q, want env override %q", cfg.CalDefaultFeed, "9b1c")
+	}
+}
+
+func TestParseAnnounce(t *testing.T) {
+	cfg := &Config{}
+	in := "[announce]\nutm_source = discord\nutm_medium = social\nutm_campaign = spring\nshortener = https://is.gd/create.php?format=simple&url={url}\n"
+	if err := cfg.parse(strings.NewReader(in)); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if cfg.AnnounceUTMSource != "discord" || cfg.AnnounceUTMMedium != "social" || cfg.AnnounceUTMCampaign != "spring" {
+		t.Errorf("UTM = %q/%q/%q", cfg.AnnounceUTMSource, cfg.AnnounceUTMMedium, cfg.AnnounceUTMCampaign)
+	}
+	if cfg.AnnounceShortener != "https://is.gd/create.php?format=simple&url={url}" {
+		t.Errorf("AnnounceShortener = %q", cfg.AnnounceShortener)
+	}
+
+	t.Setenv("PYLON_ANNOUNCE_UTM_CAMPAIGN", "summer")
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if cfg.AnnounceUTMCampaign != "summer" {
+		t.Errorf("AnnounceUTMCampaign = %q, want env override %q", cfg.AnnounceUTMCampaign, "summer")
+	}
+}
+
+func TestParseRetention(t *testing.T) {
+	cfg := &Config{}
+	in := "[retention]\nevents_older_than = 2y\narchive_dir = /srv/archive\ndiscord_export_keep = 90d\nexport_dir = /srv/exports\n"
+	if err := cfg.parse(strings.NewReader(in)); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if cfg.RetentionEvents != "2y" || cfg.RetentionArchiveDir != "/srv/archive" || cfg.RetentionExportKeep != "90d" || cfg.RetentionExportDir != "/srv/exports" {
+		t.Errorf("unexpected retention settings %+v", cfg)
+	}
+
+	for _, in := range []string{"[retention]\nevents_older_than = forever\n", "[retention]\ndiscord_export_keep = 0d\n"} {
+		if err := (&Config{}).parse(strings.NewReader(in)); err == nil {
+			t.Errorf("parse(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseCalAPIKey(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parse(strings.NewReader("[cal]\napi_key = file-key\nauth_header = X-Api-Key\n")); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if cfg.CalAPIKey != "file-key" || cfg.CalAuthHeader != "X-Api-Key" {
+		t.Errorf("got key %q, header %q", cfg.CalAPIKey, cfg.CalAuthHeader)
+	}
+
+	t.Setenv("PYLON_CAL_API_KEY", "env-key")
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if cfg.CalAPIKey != "env-key" {
+		t.Errorf("CalAPIKey = %q, want env override %q", cfg.CalAPIKey, "env-key")
+	}
+}
+
+func TestParseAllowModeration(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "default off", input: "[discord]\nguild_id = 1\n", want: false},
+		{name: "enabled", input: "[discord]\nallow_moderation = true\n", want: true},
+		{name: "explicitly off", input: "[discord]\nallow_moderation = 0\n", want: false},
+		{name: "invalid", input: "[discord]\nallow_moderation = sure\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			err := cfg.parse(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if cfg.DiscordAllowModeration != tt.want {
+				t.Errorf("DiscordAllowModeration = %v, want %v", cfg.DiscordAllowModeration, tt.want)
+			}
+		})
+	}
+
+	cfg := &Config{}
+	t.Setenv("PYLON_DISCORD_ALLOW_MODERATION", "true")
+	if err := cfg.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	if !cfg.DiscordAllowModeration {
+		t.Error("expected env var to enable moderation")
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows uses %LocalAppData%")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Setenv("PYLON_STATE_DIR", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	dir, err := StateDir()
+	if err != nil {
+		t.Fatalf("StateDir: %v", err)
+	}
+	if want := filepath.Join(home, ".local", "state", "pylon"); dir != want {
+		t.Errorf("default StateDir = %q, want %q", dir, want)
+	}
+
+	t.Setenv("XDG_STATE_HOME", "/xdg/state")
+	if dir, _ := StateDir(); dir != "/xdg/state/pylon" {
+		t.Errorf("XDG StateDir = %q", dir)
+	}
+
+	t.Setenv("PYLON_STATE_DIR", "/explicit")
+	if dir, _ := StateDir(); dir != "/explicit" {
+		t.Errorf("override StateDir = %q", dir)
+	}
+}
+
+func TestPath(t *testing.T) {
+	home := t.TempDir()
+	xdg := filepath.Join(t.TempDir(), "xdg")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv("PYLON_CONFIG", "")
+	t.Cleanup(func() { SetPath("") })
+
+	rc := filepath.Join(home, ".pylonrc")
+	xdgFile := filepath.Join(xdg, "pylon", "config")
+	mustPath := func() string {
+		t.Helper()
+		p, err := Path()
+		if err != nil {
+			t.Fatalf("Path: %v", err)
+		}
+		return p
+	}
+
+	if got := mustPath(); got != rc {
+		t.Errorf("no files: Path = %q, want %q", got, rc)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(xdgFile), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(xdgFile, []byte("[cal]\nurl = http://xdg\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustPath(); got != xdgFile {
+		t.Errorf("XDG file only: Path = %q, want %q", got, xdgFile)
+	}
+
+	if err := os.WriteFile(rc, []byte("[cal]\nurl = http://rc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustPath(); got != rc {
+		t.Errorf("both files: Path = %q, want ~/.pylonrc", got)
+	}
+
+	t.Setenv("PYLON_CONFIG", "/etc/pylon/env.conf")
+	if got := mustPath(); got != "/etc/pylon/env.conf" {
+		t.Errorf("PYLON_CONFIG: Path = %q", got)
+	}
+
+	SetPath("/mnt/pylon.conf")
+	if got := mustPath(); got != "/mnt/pylon.conf" {
+		t.Errorf("SetPath: Path = %q", got)
+	}
+}
+
+func TestLoadExplicitPath(t *testing.T) {
+	t.Setenv("PYLON_CAL_URL", "")
+	t.Setenv("PYLON_CONFIG", "")
+	t.Cleanup(func() { SetPath("") })
+
+	file := filepath.Join(t.TempDir(), "mounted.conf")
+	if err := os.WriteFile(file, []byte("[cal]\nurl = http://mounted\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	SetPath(file)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.CalURL != "http://mounted" {
+		t.Errorf("CalURL = %q, want value from --config file", cfg.CalURL)
+	}
+
+	// A file the user named explicitly must exist.
+	SetPath(filepath.Join(t.TempDir(), "missing.conf"))
+	if _, err := Load(); err == nil {
+		t.Error("expected error for missing explicit config file")
+	}
+}
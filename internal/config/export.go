@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Shells lists the syntaxes Export can write.
+var Shells = []string{"sh", "fish", "powershell"}
+
+// Export writes c as environment variable assignments in the given shell's
+// syntax, one per set key, so the effective configuration can be reproduced
+// somewhere without the config file (a CI job, a container). Unset keys are
+// left out. Secrets are written as comments with their value masked unless
+// showSecrets is true, so evaluating the output never sets a wrong value.
+func Export(w io.Writer, c *Config, shell string, showSecrets bool) error {
+	var assign func(name, value string) string
+	switch shell {
+	case "sh":
+		assign = func(name, value string) string { return "export " + name + "=" + shQuote(value) }
+	case "fish":
+		assign = func(name, value string) string { return "set -gx " + name + " " + fishQuote(value) }
+	case "powershell":
+		assign = func(name, value string) string { return "$env:" + name + " = " + psQuote(value) }
+	default:
+		return fmt.Errorf("unknown shell %q (want %s)", shell, strings.Join(Shells, ", "))
+	}
+
+	for _, k := range Keys {
+		v := k.get(c)
+		if v == "" {
+			continue
+		}
+		if k.Secret && !showSecrets {
+			if _, err := fmt.Fprintf(w, "# %s is set (%s); use --show-secrets to include it\n", k.Env, Mask(v)); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintln(w, assign(k.Env, v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shQuote single-quotes s for POSIX shells, where nothing inside single
+// quotes is special except the closing quote.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish, which allows \' and \\ inside.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// psQuote single-quotes s for PowerShell, which doubles embedded quotes.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	cfg := &Config{
+		CalURL:      "https://cal.example.com",
+		CalAPIKey:   "sk-0123456789",
+		HTTPRetries: 3,
+		Language:    "it's",
+	}
+	tests := []struct {
+		shell       string
+		showSecrets bool
+		want        []string
+	}{
+		{"sh", false, []string{
+			"export PYLON_CAL_URL='https://cal.example.com'",
+			"# PYLON_CAL_API_KEY is set (sk-0****); use --show-secrets to include it",
+			"export PYLON_CAL_NOTIFY_CHANGES='false'",
+			"export PYLON_DISCORD_ALLOW_MODERATION='false'",
+			"export PYLON_HTTP_RETRIES='3'",
+			"export PYLON_HTTP_INSECURE_SKIP_VERIFY='false'",
+			"export PYLON_READ_ONLY='false'",
+			`export PYLON_LANGUAGE='it'\''s'`,
+		}},
+		{"sh", true, []string{
+			"export PYLON_CAL_URL='https://cal.example.com'",
+			"export PYLON_CAL_API_KEY='sk-0123456789'",
+			"export PYLON_CAL_NOTIFY_CHANGES='false'",
+			"export PYLON_DISCORD_ALLOW_MODERATION='false'",
+			"export PYLON_HTTP_RETRIES='3'",
+			"export PYLON_HTTP_INSECURE_SKIP_VERIFY='false'",
+			"export PYLON_READ_ONLY='false'",
+			`export PYLON_LANGUAGE='it'\''s'`,
+		}},
+		{"fish", true, []string{
+			"set -gx PYLON_CAL_URL 'https://cal.example.com'",
+			"set -gx PYLON_CAL_API_KEY 'sk-0123456789'",
+			"set -gx PYLON_CAL_NOTIFY_CHANGES 'false'",
+			"set -gx PYLON_DISCORD_ALLOW_MODERATION 'false'",
+			"set -gx PYLON_HTTP_RETRIES '3'",
+			"set -gx PYLON_HTTP_INSECURE_SKIP_VERIFY 'false'",
+			"set -gx PYLON_READ_ONLY 'false'",
+			`set -gx PYLON_LANGUAGE 'it\'s'`,
+		}},
+		{"powershell", false, []string{
+			"$env:PYLON_CAL_URL = 'https://cal.example.com'",
+			"# PYLON_CAL_API_KEY is set (sk-0****); use --show-secrets to include it",
+			"$env:PYLON_CAL_NOTIFY_CHANGES = 'false'",
+			"$env:PYLON_DISCORD_ALLOW_MODERATION = 'false'",
+			"$env:PYLON_HTTP_RETRIES = '3'",
+			"$env:PYLON_HTTP_INSECURE_SKIP_VERIFY = 'false'",
+			"$env:PYLON_READ_ONLY = 'false'",
+			"$env:PYLON_LANGUAGE = 'it''s'",
+		}},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := Export(&sb, cfg, tt.shell, tt.showSecrets); err != nil {
+			t.Fatalf("Export(%s): %v", tt.shell, err)
+		}
+		if got, want := sb.String(), strings.Join(tt.want, "\n")+"\n"; got != want {
+			t.Errorf("Export(%s, showSecrets=%v):\n%s\nwant:\n%s", tt.shell, tt.showSecrets, got, want)
+		}
+	}
+}
+
+func TestExportUnknownShell(t *testing.T) {
+	if err := Export(&strings.Builder{}, &Config{}, "csh", false); err == nil {
+		t.Error("expected an error for an unknown shell")
+	}
+}
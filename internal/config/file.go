@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File is an editable view of a config file. Edits touch only the affected
+// lines, so comments, blank lines and unknown keys are preserved.
+type File struct {
+	path  string
+	lines []string
+}
+
+// OpenFile reads the config file at path. A missing file yields an empty
+// File that will be created on Save.
+func OpenFile(path string) (*File, error) {
+	f := &File{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		f.lines = append(f.lines, scanner.Text())
+	}
+	return f, scanner.Err()
+}
+
+// Path returns the file's location on disk.
+func (f *File) Path() string { return f.path }
+
+// Get returns the raw value of section.key as written in the file.
+func (f *File) Get(section, key string) (string, bool) {
+	for _, i := range f.keyLines(section, key) {
+		_, v, _ := splitLine(f.lines[i])
+		return v, true
+	}
+	return "", false
+}
+
+// Set writes section.key = value, replacing an existing entry in place or
+// appending it to the section (creating the section if needed).
+func (f *File) Set(section, key, value string) {
+	entry := key + " = " + value
+	if idx := f.keyLines(section, key); len(idx) > 0 {
+		f.lines[idx[0]] = entry
+		// Drop duplicates so the new value is the only one.
+		for j := len(idx) - 1; j > 0; j-- {
+			f.remove(idx[j])
+		}
+		return
+	}
+
+	start, end, ok := f.sectionRange(section)
+	if !ok {
+		if len(f.lines) > 0 && strings.TrimSpace(f.lines[len(f.lines)-1]) != "" {
+			f.lines = append(f.lines, "")
+		}
+		f.lines = append(f.lines, "["+section+"]", entry)
+		return
+	}
+
+	// Insert after the last non-blank line of the section so the blank line
+	// separating it from the next section stays put.
+	insert := end
+	for insert > start+1 && strings.TrimSpace(f.lines[insert-1]) == "" {
+		insert--
+	}
+	f.lines = append(f.lines[:insert], append([]string{entry}, f.lines[insert:]...)...)
+}
+
+// Unset removes section.key, reporting whether it was present.
+func (f *File) Unset(section, key string) bool {
+	idx := f.keyLines(section, key)
+	for j := len(idx) - 1; j >= 0; j-- {
+		f.remove(idx[j])
+	}
+	return len(idx) > 0
+}
+
+// Save writes the file atomically with owner-only permissions, since it may
+// hold tokens.
+func (f *File) Save() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".pylonrc-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	content := strings.Join(f.lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// String returns the file contents.
+func (f *File) String() string {
+	return strings.Join(f.lines, "\n") + "\n"
+}
+
+// keyLines returns the line indexes holding section.key.
+func (f *File) keyLines(section, key string) []int {
+	var idx []int
+	current := ""
+	for i, line := range f.lines {
+		if name, ok := sectionHeader(line); ok {
+			current = name
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if k, _, ok := splitLine(line); ok && k == key {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// sectionRange returns the header line of the first matching section and the
+// index just past its last line.
+func (f *File) sectionRange(section string) (start, end int, ok bool) {
+	start = -1
+	for i, line := range f.lines {
+		name, isHeader := sectionHeader(line)
+		if !isHeader {
+			continue
+		}
+		if start >= 0 {
+			return start, i, true
+		}
+		if name == section {
+			start = i
+		}
+	}
+	if start >= 0 {
+		return start, len(f.lines), true
+	}
+	return 0, 0, false
+}
+
+func (f *File) remove(i int) {
+	f.lines = append(f.lines[:i], f.lines[i+1:]...)
+}
+
+// sectionHeader parses a "[name]" line.
+func sectionHeader(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		return strings.TrimSpace(line[1 : len(line)-1]), true
+	}
+	return "", false
+}
+
+// splitLine parses a "key = value" line, skipping comments and blanks.
+func splitLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), true
+}
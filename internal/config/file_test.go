@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleRC = `# pylon configuration
+
+[cal]
+# where the cal service lives
+url = http://localhost:8085
+
+[discord]
+webhook = https://discord.com/api/webhooks/1/abc
+`
+
+func openSample(t *testing.T) *File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".pylonrc")
+	if err := os.WriteFile(path, []byte(sampleRC), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	f, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return f
+}
+
+func TestFileSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		key     string
+		value   string
+		want    string
+	}{
+		{
+			name:    "replace existing keeps comments",
+			section: "cal",
+			key:     "url",
+			value:   "https://cal.example.com",
+			want: `# pylon configuration
+
+[cal]
+# where the cal service lives
+url = https://cal.example.com
+
+[discord]
+webhook = https://discord.com/api/webhooks/1/abc
+`,
+		},
+		{
+			name:    "add to existing section before blank line",
+			section: "cal",
+			key:     "api_key",
+			value:   "k",
+			want: `# pylon configuration
+
+[cal]
+# where the cal service lives
+url = http://localhost:8085
+api_key = k
+
+[discord]
+webhook = https://discord.com/api/webhooks/1/abc
+`,
+		},
+		{
+			name:    "add to last section",
+			section: "discord",
+			key:     "guild_id",
+			value:   "g-1",
+			want: `# pylon configuration
+
+[cal]
+# where the cal service lives
+url = http://localhost:8085
+
+[discord]
+webhook = https://discord.com/api/webhooks/1/abc
+guild_id = g-1
+`,
+		},
+		{
+			name:    "new section appended",
+			section: "ui",
+			key:     "language",
+			value:   "es",
+			want: `# pylon configuration
+
+[cal]
+# where the cal service lives
+url = http://localhost:8085
+
+[discord]
+webhook = https://discord.com/api/webhooks/1/abc
+
+[ui]
+language = es
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := openSample(t)
+			f.Set(tt.section, tt.key, tt.value)
+			if got := f.String(); got != tt.want {
+				t.Errorf("unexpected file:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSetRemovesDuplicates(t *testing.T) {
+	f := &File{lines: []string{"[cal]", "url = a", "url = b"}}
+	f.Set("cal", "url", "c")
+	if got, want := f.String(), "[cal]\nurl = c\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFileGetUnset(t *testing.T) {
+	f := openSample(t)
+
+	if v, ok := f.Get("cal", "url"); !ok || v != "http://localhost:8085" {
+		t.Errorf("Get(cal.url) = %q, %v", v, ok)
+	}
+	if _, ok := f.Get("discord", "url"); ok {
+		t.Error("expected discord.url to be absent")
+	}
+
+	if !f.Unset("discord", "webhook") {
+		t.Fatal("expected Unset to report removal")
+	}
+	if _, ok := f.Get("discord", "webhook"); ok {
+		t.Error("expected webhook to be removed")
+	}
+	if f.Unset("discord", "webhook") {
+		t.Error("expected second Unset to report nothing removed")
+	}
+}
+
+func TestFileSaveRoundTrip(t *testing.T) {
+	f := openSample(t)
+	f.Set("discord", "bot_token", "secret")
+	if err := f.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	info, err := os.Stat(f.Path())
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected 0600 permissions, got %o", perm)
+	}
+
+	reopened, err := OpenFile(f.Path())
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if reopened.String() != f.String() {
+		t.Errorf("round trip mismatch:\n%s\nvs\n%s", reopened.String(), f.String())
+	}
+}
+
+func TestOpenFileMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", ".pylonrc")
+	f, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	f.Set("cal", "url", "http://x")
+	if err := f.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != "[cal]\nurl = http://x\n" {
+		t.Errorf("unexpected content %q", data)
+	}
+}
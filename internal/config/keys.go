@@ -0,0 +1,149 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Key describes a configuration setting addressable as "section.key".
+type Key struct {
+	Name   string // section.key as written in the config file
+	Env    string // environment variable override
+	Secret bool   // masked in listings
+	Help   string
+	get    func(*Config) string
+}
+
+// Keys lists every supported setting, in display order.
+var Keys = []Key{
+	{Name: "cal.url", Env: "PYLON_CAL_URL", Help: "Base URL for the cal service",
+		get: func(c *Config) string { return c.CalURL }},
+	{Name: "cal.api_key", Env: "PYLON_CAL_API_KEY", Secret: true, Help: "API key sent to the cal service",
+		get: func(c *Config) string { return c.CalAPIKey }},
+	{Name: "cal.auth_header", Env: "PYLON_CAL_AUTH_HEADER", Help: "Header carrying the API key (default: Authorization: Bearer)",
+		get: func(c *Config) string { return c.CalAuthHeader }},
+	{Name: "cal.default_feed", Env: "PYLON_CAL_DEFAULT_FEED", Help: "Feed for pylon cal quick when --feed is not given",
+		get: func(c *Config) string { return c.CalDefaultFeed }},
+	{Name: "cal.notify_changes", Env: "PYLON_CAL_NOTIFY_CHANGES", Help: "Post a summary to Discord when pylon updates or cancels an event (true/false)",
+		get: func(c *Config) string { return strconv.FormatBool(c.CalNotifyChanges) }},
+	{Name: "cal.notify_channel", Env: "PYLON_CAL_NOTIFY_CHANNEL", Help: "Channel for change summaries (default: the webhook)",
+		get: func(c *Config) string { return c.CalNotifyChannel }},
+	{Name: "discord.webhook", Env: "PYLON_DISCORD_WEBHOOK", Secret: true, Help: "Webhook URL for sending messages",
+		get: func(c *Config) string { return c.DiscordWebhook }},
+	{Name: "discord.bot_token", Env: "PYLON_DISCORD_BOT_TOKEN", Secret: true, Help: "Bot token for reading messages/channels",
+		get: func(c *Config) string { return c.DiscordBotToken }},
+	{Name: "discord.guild_id", Env: "PYLON_DISCORD_GUILD_ID", Help: "Default guild (server) ID",
+		get: func(c *Config) string { return c.DiscordGuildID }},
+	{Name: "discord.channel_id", Env: "PYLON_DISCORD_CHANNEL_ID", Help: "Default channel ID for reading",
+		get: func(c *Config) string { return c.DiscordChannelID }},
+	{Name: "discord.attachment_channel", Env: "PYLON_DISCORD_ATTACHMENT_CHANNEL", Help: "Channel hosting files for pylon cal event attach",
+		get: func(c *Config) string { return c.DiscordAttachmentChannel }},
+	{Name: "discord.allow_moderation", Env: "PYLON_DISCORD_ALLOW_MODERATION", Help: "Enable kick/ban/timeout commands (true/false)",
+		get: func(c *Config) string { return strconv.FormatBool(c.DiscordAllowModeration) }},
+	{Name: "slack.bot_token", Env: "PYLON_SLACK_BOT_TOKEN", Secret: true, Help: "Slack bot token (xoxb-...) for reading and posting",
+		get: func(c *Config) string { return c.SlackBotToken }},
+	{Name: "slack.webhook", Env: "PYLON_SLACK_WEBHOOK", Secret: true, Help: "Slack incoming webhook URL for posting",
+		get: func(c *Config) string { return c.SlackWebhook }},
+	{Name: "slack.channel_id", Env: "PYLON_SLACK_CHANNEL_ID", Help: "Default Slack channel ID",
+		get: func(c *Config) string { return c.SlackChannelID }},
+	{Name: "http.retries", Env: "PYLON_HTTP_RETRIES", Help: "Retries for transient API failures",
+		get: func(c *Config) string { return strconv.Itoa(c.HTTPRetries) }},
+	{Name: "http.proxy", Env: "PYLON_HTTP_PROXY", Help: "Proxy URL for cal and Discord requests (default: HTTP(S)_PROXY)",
+		get: func(c *Config) string { return c.HTTPProxy }},
+	{Name: "http.ca_file", Env: "PYLON_HTTP_CA_FILE", Help: "PEM file of extra CA certificates to trust",
+		get: func(c *Config) string { return c.HTTPCAFile }},
+	{Name: "http.insecure_skip_verify", Env: "PYLON_HTTP_INSECURE_SKIP_VERIFY", Help: "Skip TLS certificate verification (true/false)",
+		get: func(c *Config) string { return strconv.FormatBool(c.HTTPInsecureSkipVerify) }},
+	{Name: "http.read_only", Env: "PYLON_READ_ONLY", Help: "Refuse every cal and Discord request that changes something (true/false)",
+		get: func(c *Config) string { return strconv.FormatBool(c.ReadOnly) }},
+	{Name: "announce.utm_source", Env: "PYLON_ANNOUNCE_UTM_SOURCE", Help: "utm_source added to announced event URLs",
+		get: func(c *Config) string { return c.AnnounceUTMSource }},
+	{Name: "announce.utm_medium", Env: "PYLON_ANNOUNCE_UTM_MEDIUM", Help: "utm_medium added to announced event URLs",
+		get: func(c *Config) string { return c.AnnounceUTMMedium }},
+	{Name: "announce.utm_campaign", Env: "PYLON_ANNOUNCE_UTM_CAMPAIGN", Help: "utm_campaign added to announced event URLs",
+		get: func(c *Config) string { return c.AnnounceUTMCampaign }},
+	{Name: "announce.shortener", Env: "PYLON_ANNOUNCE_SHORTENER", Help: "URL shortener for announced event URLs ({url} is the long URL)",
+		get: func(c *Config) string { return c.AnnounceShortener }},
+	{Name: "retention.events_older_than", Env: "PYLON_RETENTION_EVENTS_OLDER_THAN", Help: "Archive and delete events older than this (e.g. 2y)",
+		get: func(c *Config) string { return c.RetentionEvents }},
+	{Name: "retention.archive_dir", Env: "PYLON_RETENTION_ARCHIVE_DIR", Help: "Where pylon retention writes event archives",
+		get: func(c *Config) string { return c.RetentionArchiveDir }},
+	{Name: "retention.discord_export_keep", Env: "PYLON_RETENTION_DISCORD_EXPORT_KEEP", Help: "Remove Discord export files older than this (e.g. 90d)",
+		get: func(c *Config) string { return c.RetentionExportKeep }},
+	{Name: "retention.export_dir", Env: "PYLON_RETENTION_EXPORT_DIR", Help: "Directory holding Discord export files",
+		get: func(c *Config) string { return c.RetentionExportDir }},
+	{Name: "daemon.admin_channel", Env: "PYLON_DAEMON_ADMIN_CHANNEL", Help: "Channel told when a long-running job keeps failing",
+		get: func(c *Config) string { return c.DaemonAdminChannel }},
+	{Name: "daemon.admin_user", Env: "PYLON_DAEMON_ADMIN_USER", Help: "User sent a direct message when a long-running job keeps failing",
+		get: func(c *Config) string { return c.DaemonAdminUser }},
+	{Name: "daemon.failure_threshold", Env: "PYLON_DAEMON_FAILURE_THRESHOLD", Help: "Consecutive failures before notifying (default 3)",
+		get: func(c *Config) string {
+			if c.DaemonFailureThreshold == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.DaemonFailureThreshold)
+		}},
+	{Name: "gcal.credentials", Env: "PYLON_GCAL_CREDENTIALS", Help: "Google service account key or OAuth client file for cal sync gcal",
+		get: func(c *Config) string { return c.GCalCredentials }},
+	{Name: "ui.language", Env: "PYLON_LANGUAGE", Help: "Language for status messages",
+		get: func(c *Config) string { return c.Language }},
+}
+
+// LookupKey finds a key by its "section.key" name.
+func LookupKey(name string) (Key, bool) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// KeyNames returns all key names, sorted.
+func KeyNames() []string {
+	names := make([]string, len(Keys))
+	for i, k := range Keys {
+		names[i] = k.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the effective value of a key.
+func (c *Config) Get(name string) (string, bool) {
+	k, ok := LookupKey(name)
+	if !ok {
+		return "", false
+	}
+	return k.get(c), true
+}
+
+// Validate checks that value is acceptable for the named key without
+// modifying any configuration.
+func Validate(name, value string) error {
+	section, key := SplitKey(name)
+	var scratch Config
+	return scratch.set(section, key, value)
+}
+
+// SplitKey splits "section.key" into its parts. A name without a dot has an
+// empty section.
+func SplitKey(name string) (section, key string) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+// Mask hides most of a secret value, keeping a short prefix so different
+// secrets can still be told apart.
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return "****"
+	}
+	return value[:4] + "****"
+}
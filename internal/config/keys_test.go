@@ -0,0 +1,78 @@
+package config
+
+import "testing"
+
+func TestConfigGet(t *testing.T) {
+	cfg := &Config{CalURL: "http://cal", DiscordBotToken: "tok", HTTPRetries: 2}
+
+	tests := []struct {
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{key: "cal.url", want: "http://cal", wantOK: true},
+		{key: "discord.bot_token", want: "tok", wantOK: true},
+		{key: "http.retries", want: "2", wantOK: true},
+		{key: "discord.guild_id", want: "", wantOK: true},
+		{key: "cal.nope", wantOK: false},
+	}
+	for _, tt := range tests {
+		got, ok := cfg.Get(tt.key)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("Get(%q) = %q, %v; want %q, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestKeysRoundTripThroughSet(t *testing.T) {
+	// Every registered key must be settable via the file parser, otherwise
+	// `pylon config set` would write values Load ignores.
+	for _, k := range Keys {
+		section, key := SplitKey(k.Name)
+		value := "v"
+		switch k.Name {
+		case "http.retries":
+			value = "7"
+		case "daemon.failure_threshold":
+			value = "5"
+		case "cal.notify_changes", "discord.allow_moderation", "http.insecure_skip_verify", "http.read_only":
+			value = "true"
+		case "http.proxy":
+			value = "http://proxy.corp:3128"
+		case "retention.events_older_than", "retention.discord_export_keep":
+			value = "90d"
+		}
+		var cfg Config
+		if err := cfg.set(section, key, value); err != nil {
+			t.Fatalf("set %s: %v", k.Name, err)
+		}
+		if got, _ := cfg.Get(k.Name); got != value {
+			t.Errorf("%s: set %q, got %q", k.Name, value, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("http.retries", "3"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := Validate("http.retries", "many"); err == nil {
+		t.Error("expected error for invalid retries")
+	}
+	if err := Validate("daemon.failure_threshold", "0"); err == nil {
+		t.Error("expected error for a zero failure threshold")
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := map[string]string{
+		"":                 "",
+		"short":            "****",
+		"MTIzNDU2Nzg5.abc": "MTIz****",
+	}
+	for in, want := range tests {
+		if got := Mask(in); got != want {
+			t.Errorf("Mask(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
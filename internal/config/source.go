@@ -0,0 +1,37 @@
+package config
+
+import "fmt"
+
+// Source records one place a setting was given a value.
+type Source struct {
+	Env   string // environment variable, for values from the environment
+	Path  string // config file, for values from a file
+	Line  int    // line in Path
+	Value string // the value as written
+}
+
+// String describes the source: "PYLON_CAL_URL", "/home/me/.pylonrc:3" or
+// "line 3" for a config read from elsewhere.
+func (s Source) String() string {
+	switch {
+	case s.Env != "":
+		return s.Env
+	case s.Path != "":
+		return fmt.Sprintf("%s:%d", s.Path, s.Line)
+	}
+	return fmt.Sprintf("line %d", s.Line)
+}
+
+func (c *Config) record(name string, s Source) {
+	if c.sources == nil {
+		c.sources = map[string][]Source{}
+	}
+	c.sources[name] = append(c.sources[name], s)
+}
+
+// Sources returns where the named setting was given values, in the order
+// they were applied: config file lines first, then the environment. The
+// last one is the effective value; none means the default is in effect.
+func (c *Config) Sources(name string) []Source {
+	return c.sources[name]
+}
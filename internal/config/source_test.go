@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSources(t *testing.T) {
+	for _, k := range Keys {
+		t.Setenv(k.Env, "")
+	}
+	path := filepath.Join(t.TempDir(), "config")
+	data := "[cal]\nurl = http://one\n\n[discord]\nguild_id = 42\n\n[cal]\nurl = http://two\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PYLON_CONFIG", path)
+	t.Setenv("PYLON_CAL_URL", "http://env")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []Source{
+		{Path: path, Line: 2, Value: "http://one"},
+		{Path: path, Line: 8, Value: "http://two"},
+		{Env: "PYLON_CAL_URL", Value: "http://env"},
+	}
+	if got := cfg.Sources("cal.url"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sources(cal.url) = %+v, want %+v", got, want)
+	}
+	if got := cfg.Sources("discord.guild_id"); len(got) != 1 || got[0].String() != path+":5" {
+		t.Errorf("Sources(discord.guild_id) = %v, want %s:5", got, path)
+	}
+	if got := cfg.Sources("http.retries"); len(got) != 0 {
+		t.Errorf("Sources(http.retries) = %v, want none (default)", got)
+	}
+	if got := PathOrigin(); got != "PYLON_CONFIG" {
+		t.Errorf("PathOrigin() = %q, want PYLON_CONFIG", got)
+	}
+}
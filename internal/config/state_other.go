@@ -0,0 +1,19 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func defaultStateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "pylon"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "pylon"), nil
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultStateDir uses %LocalAppData%: state is per machine and shouldn't
+// roam with the profile the way %AppData% (where the config file lives)
+// does.
+func defaultStateDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pylon"), nil
+}
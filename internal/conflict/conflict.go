@@ -0,0 +1,128 @@
+// Package conflict merges two edited copies of a record against the
+// version both started from (a three-way merge), field by field. Fields
+// only one side changed merge by themselves; fields both sides changed
+// differently are conflicts, settled by a Resolver: interactively with
+// Prompt, or by a fixed strategy for automation.
+//
+// Records are JSON objects, compared one top-level member at a time, so
+// any type with JSON tags (cal.CreateEventRequest, say) can be merged.
+package conflict
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrConflict is returned for a conflict the resolver refused to settle.
+var ErrConflict = errors.New("conflicting edits")
+
+// Conflict is a field both sides changed differently. Values are JSON; a
+// nil value means the field is absent on that side.
+type Conflict struct {
+	Field  string
+	Base   json.RawMessage
+	Local  json.RawMessage
+	Remote json.RawMessage
+}
+
+// A Resolver picks the value a conflicting field gets: one side's, or a
+// merged value of its own. Returning nil removes the field.
+type Resolver interface {
+	Resolve(c Conflict) (json.RawMessage, error)
+}
+
+// ResolverFunc adapts a function to a Resolver.
+type ResolverFunc func(Conflict) (json.RawMessage, error)
+
+func (f ResolverFunc) Resolve(c Conflict) (json.RawMessage, error) { return f(c) }
+
+// Strategies are the non-interactive resolvers Strategy accepts.
+var Strategies = []string{"local", "remote", "fail"}
+
+// Strategy returns the resolver for a --non-interactive strategy: "local"
+// or "remote" keeps that side's value in every conflict, and "fail" stops
+// at the first with ErrConflict.
+func Strategy(name string) (Resolver, error) {
+	switch name {
+	case "local":
+		return ResolverFunc(func(c Conflict) (json.RawMessage, error) { return c.Local, nil }), nil
+	case "remote":
+		return ResolverFunc(func(c Conflict) (json.RawMessage, error) { return c.Remote, nil }), nil
+	case "fail":
+		return ResolverFunc(func(c Conflict) (json.RawMessage, error) {
+			return nil, fmt.Errorf("%w in %s", ErrConflict, c.Field)
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown strategy %q: want local, remote or fail", name)
+}
+
+// Merge three-way merges local and remote, two edits of base, and returns
+// the merged object and the conflicts r settled, in field order.
+func Merge(base, local, remote []byte, r Resolver) (merged []byte, conflicts []Conflict, err error) {
+	var b, l, rm map[string]json.RawMessage
+	for _, side := range []struct {
+		name string
+		data []byte
+		into *map[string]json.RawMessage
+	}{{"base", base, &b}, {"local", local, &l}, {"remote", remote, &rm}} {
+		if err := json.Unmarshal(side.data, side.into); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", side.name, err)
+		}
+	}
+
+	var fields []string
+	for _, m := range []map[string]json.RawMessage{b, l, rm} {
+		for k := range m {
+			if !slices.Contains(fields, k) {
+				fields = append(fields, k)
+			}
+		}
+	}
+	slices.Sort(fields)
+
+	out := map[string]json.RawMessage{}
+	for _, f := range fields {
+		bv, lv, rv := b[f], l[f], rm[f]
+		var v json.RawMessage
+		switch {
+		case equal(lv, rv), equal(rv, bv):
+			v = lv
+		case equal(lv, bv):
+			v = rv
+		default:
+			c := Conflict{Field: f, Base: bv, Local: lv, Remote: rv}
+			if v, err = r.Resolve(c); err != nil {
+				return nil, conflicts, err
+			}
+			conflicts = append(conflicts, c)
+		}
+		if v != nil {
+			out[f] = v
+		}
+	}
+	merged, err = json.Marshal(out)
+	return merged, conflicts, err
+}
+
+// equal reports whether two JSON values are the same, ignoring layout and
+// member order. Absent (nil) only equals absent.
+func equal(a, b json.RawMessage) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ca, errA := canonical(a)
+	cb, errB := canonical(b)
+	return errA == nil && errB == nil && bytes.Equal(ca, cb)
+}
+
+// canonical re-encodes v with sorted object members.
+func canonical(v json.RawMessage) ([]byte, error) {
+	var x any
+	if err := json.Unmarshal(v, &x); err != nil {
+		return nil, err
+	}
+	return json.Marshal(x)
+}
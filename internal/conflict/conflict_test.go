@@ -0,0 +1,150 @@
+package conflict
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := `{"summary":"Standup","location":"Room 1","all_day":false}`
+	tests := []struct {
+		name          string
+		local, remote string
+		strategy      string
+		want          string
+		conflicts     []string
+		err           error
+	}{
+		{
+			name:   "unchanged",
+			local:  base,
+			remote: base,
+			want:   `{"all_day":false,"location":"Room 1","summary":"Standup"}`,
+		},
+		{
+			name:   "each side changes a different field",
+			local:  `{"summary":"Daily standup","location":"Room 1","all_day":false}`,
+			remote: `{"summary":"Standup","location":"Room 2","all_day":false}`,
+			want:   `{"all_day":false,"location":"Room 2","summary":"Daily standup"}`,
+		},
+		{
+			name:   "same change on both sides",
+			local:  `{"summary":"Standup","location":"Room 2","all_day":false}`,
+			remote: `{"all_day":false, "location":"Room 2","summary":"Standup"}`,
+			want:   `{"all_day":false,"location":"Room 2","summary":"Standup"}`,
+		},
+		{
+			name:   "one side removes a field",
+			local:  `{"summary":"Standup","all_day":false}`,
+			remote: base,
+			want:   `{"all_day":false,"summary":"Standup"}`,
+		},
+		{
+			name:      "conflict keeps local",
+			local:     `{"summary":"Standup","location":"Room 2","all_day":false}`,
+			remote:    `{"summary":"Standup","location":"Room 3","all_day":false}`,
+			strategy:  "local",
+			want:      `{"all_day":false,"location":"Room 2","summary":"Standup"}`,
+			conflicts: []string{"location"},
+		},
+		{
+			name:      "conflict keeps remote",
+			local:     `{"summary":"Standup","location":"Room 2","all_day":true}`,
+			remote:    `{"summary":"Standup","location":"Room 3"}`,
+			strategy:  "remote",
+			want:      `{"location":"Room 3","summary":"Standup"}`,
+			conflicts: []string{"all_day", "location"},
+		},
+		{
+			name:     "conflict fails",
+			local:    `{"summary":"A","location":"Room 1","all_day":false}`,
+			remote:   `{"summary":"B","location":"Room 1","all_day":false}`,
+			strategy: "fail",
+			err:      ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Strategy(tt.strategy)
+			if tt.strategy == "" {
+				r = ResolverFunc(func(c Conflict) (json.RawMessage, error) {
+					t.Fatalf("unexpected conflict in %s", c.Field)
+					return nil, nil
+				})
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			got, conflicts, err := Merge([]byte(base), []byte(tt.local), []byte(tt.remote), r)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("merged = %s, want %s", got, tt.want)
+			}
+			var fields []string
+			for _, c := range conflicts {
+				fields = append(fields, c.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.conflicts, ",") {
+				t.Errorf("conflicts = %v, want %v", fields, tt.conflicts)
+			}
+		})
+	}
+}
+
+func TestStrategyUnknown(t *testing.T) {
+	if _, err := Strategy("ask"); err == nil {
+		t.Error("Strategy(ask) succeeded")
+	}
+}
+
+func TestPrompt(t *testing.T) {
+	c := Conflict{
+		Field:  "location",
+		Base:   []byte(`"Room 1"`),
+		Local:  []byte(`"Room 2"`),
+		Remote: []byte(`"Room 3"`),
+	}
+	tests := []struct {
+		name  string
+		input string
+		c     Conflict
+		want  string
+		err   error
+	}{
+		{name: "local", input: "l\n", c: c, want: `"Room 2"`},
+		{name: "remote", input: "R\n", c: c, want: `"Room 3"`},
+		{name: "asks again", input: "x\nremote\n", c: c, want: `"Room 3"`},
+		{name: "merge text", input: "m\nRoom 2 and 3\n", c: c, want: `"Room 2 and 3"`},
+		{name: "merge text that is JSON", input: "m\n42\n", c: c, want: `"42"`},
+		{
+			name:  "merge JSON",
+			input: "m\n[\"a\",\"b\"]\n",
+			c:     Conflict{Field: "categories", Local: []byte(`["a"]`), Remote: []byte(`["b"]`)},
+			want:  `["a","b"]`,
+		},
+		{name: "end of input", input: "", c: c, err: ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			p := &Prompt{In: bufio.NewReader(strings.NewReader(tt.input)), Out: &out}
+			got, err := p.Resolve(tt.c)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			if !strings.Contains(out.String(), tt.c.Field) {
+				t.Errorf("prompt does not name the field:\n%s", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package conflict
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompt resolves conflicts interactively: it shows both versions of each
+// conflicting field on Out and reads the choice from In, l for the local
+// value, r for the remote one, or m to type a merged value. A merged value
+// is JSON, or plain text for a field that holds text.
+type Prompt struct {
+	In  *bufio.Reader
+	Out io.Writer
+}
+
+// Resolve asks which value c.Field should get. End of input leaves the
+// conflict unresolved.
+func (p *Prompt) Resolve(c Conflict) (json.RawMessage, error) {
+	fmt.Fprintf(p.Out, "\nBoth sides changed %s:\n", c.Field)
+	fmt.Fprintf(p.Out, "  base:   %s\n", show(c.Base))
+	fmt.Fprintf(p.Out, "  local:  %s\n", show(c.Local))
+	fmt.Fprintf(p.Out, "  remote: %s\n", show(c.Remote))
+	for {
+		switch strings.ToLower(p.ask("Keep [l]ocal, [r]emote, or [m]erge?")) {
+		case "l", "local":
+			return c.Local, nil
+		case "r", "remote":
+			return c.Remote, nil
+		case "m", "merge":
+			v := p.ask("Value:")
+			if json.Valid([]byte(v)) && !isText(c) {
+				return json.RawMessage(v), nil
+			}
+			return json.Marshal(v)
+		case "\x04":
+			return nil, fmt.Errorf("%w in %s: no answer", ErrConflict, c.Field)
+		}
+	}
+}
+
+// ask prints question and returns the answer, or "\x04" at end of input.
+func (p *Prompt) ask(question string) string {
+	fmt.Fprint(p.Out, question+" ")
+	line, err := p.In.ReadString('\n')
+	if err != nil && line == "" {
+		return "\x04"
+	}
+	return strings.TrimSpace(line)
+}
+
+// isText reports whether the field holds a string on either side, so a
+// merged value is taken as typed even if it happens to be valid JSON.
+func isText(c Conflict) bool {
+	for _, v := range []json.RawMessage{c.Local, c.Remote, c.Base} {
+		if len(v) > 0 {
+			return v[0] == '"'
+		}
+	}
+	return false
+}
+
+func show(v json.RawMessage) string {
+	if v == nil {
+		return "(not set)"
+	}
+	return string(v)
+}
@@ -0,0 +1,222 @@
+// Package digest posts summaries of upcoming events to Discord. One run can
+// serve many routes, each sending a set of feeds to its own destination, so
+// a single cron entry covers every team in an organization.
+package digest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+// MaxMessage is Discord's limit on the length of a message.
+const MaxMessage = 2000
+
+// Destination is where a digest is posted. The zero value means the
+// configured default (the webhook, or the default channel).
+type Destination struct {
+	Webhook string // webhook URL
+	Channel string // channel or thread ID, posted to with the bot token
+}
+
+// ParseDestination parses "webhook:<url>", a bare https:// webhook URL,
+// "channel:<id>" or "default".
+func ParseDestination(s string) (Destination, error) {
+	switch {
+	case s == "default":
+		return Destination{}, nil
+	case strings.HasPrefix(s, "webhook:"):
+		return Destination{Webhook: strings.TrimPrefix(s, "webhook:")}, nil
+	case strings.HasPrefix(s, "https://"):
+		return Destination{Webhook: s}, nil
+	case strings.HasPrefix(s, "channel:") && len(s) > len("channel:"):
+		return Destination{Channel: strings.TrimPrefix(s, "channel:")}, nil
+	}
+	return Destination{}, fmt.Errorf("invalid destination %q: want webhook:<url>, channel:<id> or default", s)
+}
+
+// String describes d without revealing a webhook's secret token.
+func (d Destination) String() string {
+	switch {
+	case d.Channel != "":
+		return "channel:" + d.Channel
+	case d.Webhook != "":
+		if i := strings.LastIndex(d.Webhook, "/"); i > 0 {
+			return "webhook:" + d.Webhook[:i] + "/…"
+		}
+		return "webhook"
+	}
+	return "default"
+}
+
+// Route sends the events of some feeds to one destination.
+type Route struct {
+	Feeds []string
+	To    Destination
+	Line  int // line in the routes file, 0 if not from one
+}
+
+// ParseRoutes reads routes, one per line:
+//
+//	# team calendars
+//	3f2a...,9b1c... -> channel:1234567890
+//	7d4e...         -> webhook:https://discord.com/api/webhooks/...
+//
+// Blank lines and lines starting with # are ignored.
+func ParseRoutes(r io.Reader) ([]Route, error) {
+	var routes []Route
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		feeds, dest, ok := strings.Cut(line, "->")
+		if !ok {
+			return nil, fmt.Errorf("line %d: want <feed>[,<feed>...] -> <destination>", n)
+		}
+		route := Route{Line: n}
+		for _, f := range strings.Split(feeds, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				route.Feeds = append(route.Feeds, f)
+			}
+		}
+		if len(route.Feeds) == 0 {
+			return nil, fmt.Errorf("line %d: no feeds", n)
+		}
+		to, err := ParseDestination(strings.TrimSpace(dest))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		route.To = to
+		routes = append(routes, route)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no routes")
+	}
+	return routes, nil
+}
+
+// Result is the outcome of one route.
+type Result struct {
+	Route Route
+	Err   error
+}
+
+// Run delivers every route. Each distinct feed is fetched once, and feeds
+// and routes are processed at most parallel at a time. A failure affects
+// only the routes involved: a feed that can't be fetched fails the routes
+// that include it, and a destination that rejects the post fails only its
+// route. render turns a route's events into message text; an empty result
+// means there is nothing to post.
+func Run(routes []Route, fetch func(feedID string) ([]cal.Event, error), render func(Route, []cal.Event) string,
+	send func(Destination, string) error, parallel int) []Result {
+	if parallel < 1 {
+		parallel = 1
+	}
+	sem := make(chan struct{}, parallel)
+
+	type fetched struct {
+		events []cal.Event
+		err    error
+	}
+	feeds := map[string]*fetched{}
+	var wg sync.WaitGroup
+	for _, r := range routes {
+		for _, id := range r.Feeds {
+			if feeds[id] != nil {
+				continue
+			}
+			f := &fetched{}
+			feeds[id] = f
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				f.events, f.err = fetch(id)
+			}()
+		}
+	}
+	wg.Wait()
+
+	results := make([]Result, len(routes))
+	for i, r := range routes {
+		results[i].Route = r
+		var events []cal.Event
+		for _, id := range r.Feeds {
+			f := feeds[id]
+			if f.err != nil {
+				results[i].Err = fmt.Errorf("feed %s: %w", id, f.err)
+				break
+			}
+			events = append(events, f.events...)
+		}
+		if results[i].Err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			text := render(r, events)
+			if text == "" {
+				return
+			}
+			for _, msg := range Split(text, MaxMessage) {
+				if err := send(r.To, msg); err != nil {
+					results[i].Err = err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Split breaks text into messages of at most limit bytes, each wrapped in a
+// code block so the agenda's columns line up. Breaks fall between lines; a
+// single line too long for a message is cut.
+func Split(text string, limit int) []string {
+	const open, close = "```\n", "```"
+	room := limit - len(open) - len(close)
+	var msgs []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			msgs = append(msgs, open+cur.String()+close)
+			cur.Reset()
+		}
+	}
+	for _, line := range strings.SplitAfter(strings.TrimRight(text, "\n")+"\n", "\n") {
+		if line == "" {
+			continue
+		}
+		for len(line) > room {
+			flush()
+			cut := room - 1 // leave room for the newline
+			for cut > 0 && !isRuneStart(line[cut]) {
+				cut--
+			}
+			msgs = append(msgs, open+line[:cut]+"\n"+close)
+			line = line[cut:]
+		}
+		if cur.Len()+len(line) > room {
+			flush()
+		}
+		cur.WriteString(line)
+	}
+	flush()
+	return msgs
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
@@ -0,0 +1,164 @@
+package digest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func TestParseRoutes(t *testing.T) {
+	input := `# org digests
+feed-a, feed-b -> channel:123
+
+feed-c -> webhook:https://discord.com/api/webhooks/1/secret
+feed-d -> https://discord.com/api/webhooks/2/secret
+feed-e -> default
+`
+	routes, err := ParseRoutes(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseRoutes: %v", err)
+	}
+	want := []struct {
+		feeds string
+		to    string
+		line  int
+	}{
+		{"feed-a feed-b", "channel:123", 2},
+		{"feed-c", "webhook:https://discord.com/api/webhooks/1/…", 4},
+		{"feed-d", "webhook:https://discord.com/api/webhooks/2/…", 5},
+		{"feed-e", "default", 6},
+	}
+	if len(routes) != len(want) {
+		t.Fatalf("got %d routes, want %d", len(routes), len(want))
+	}
+	for i, w := range want {
+		r := routes[i]
+		if got := strings.Join(r.Feeds, " "); got != w.feeds {
+			t.Errorf("route %d feeds = %q, want %q", i, got, w.feeds)
+		}
+		if got := r.To.String(); got != w.to {
+			t.Errorf("route %d to = %q, want %q", i, got, w.to)
+		}
+		if r.Line != w.line {
+			t.Errorf("route %d line = %d, want %d", i, r.Line, w.line)
+		}
+	}
+}
+
+func TestParseRoutesErrors(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"no arrow", "feed-a channel:1\n", "line 1"},
+		{"no feeds", " , -> channel:1\n", "no feeds"},
+		{"bad destination", "feed-a -> irc:#x\n", "invalid destination"},
+		{"empty", "# nothing\n", "no routes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRoutes(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	routes := []Route{
+		{Feeds: []string{"a", "b"}, To: Destination{Channel: "1"}},
+		{Feeds: []string{"b", "broken"}, To: Destination{Channel: "2"}},
+		{Feeds: []string{"a"}, To: Destination{Channel: "rejects"}},
+		{Feeds: []string{"empty"}, To: Destination{Channel: "3"}},
+	}
+
+	var mu sync.Mutex
+	fetches := map[string]int{}
+	fetch := func(id string) ([]cal.Event, error) {
+		mu.Lock()
+		fetches[id]++
+		mu.Unlock()
+		switch id {
+		case "broken":
+			return nil, errors.New("503")
+		case "empty":
+			return nil, nil
+		}
+		return []cal.Event{{Summary: "event in " + id}}, nil
+	}
+	render := func(_ Route, events []cal.Event) string {
+		var names []string
+		for _, e := range events {
+			names = append(names, e.Summary)
+		}
+		return strings.Join(names, "\n")
+	}
+	sent := map[string]string{}
+	send := func(d Destination, msg string) error {
+		if d.Channel == "rejects" {
+			return errors.New("403 missing access")
+		}
+		mu.Lock()
+		sent[d.Channel] += msg
+		mu.Unlock()
+		return nil
+	}
+
+	results := Run(routes, fetch, render, send, 2)
+
+	for id, n := range fetches {
+		if n != 1 {
+			t.Errorf("feed %s fetched %d times, want 1", id, n)
+		}
+	}
+	wantErr := []string{"", "feed broken: 503", "403 missing access", ""}
+	for i, r := range results {
+		got := ""
+		if r.Err != nil {
+			got = r.Err.Error()
+		}
+		if got != wantErr[i] {
+			t.Errorf("route %d error = %q, want %q", i, got, wantErr[i])
+		}
+	}
+	if want := "```\nevent in a\nevent in b\n```"; sent["1"] != want {
+		t.Errorf("channel 1 got %q, want %q", sent["1"], want)
+	}
+	if _, ok := sent["2"]; ok {
+		t.Error("route with a failed feed should not post")
+	}
+	if _, ok := sent["3"]; ok {
+		t.Error("empty render should not post")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	lines := strings.Repeat("0123456789\n", 10) // 110 bytes
+	msgs := Split(lines, 50)
+	var joined strings.Builder
+	for _, m := range msgs {
+		if len(m) > 50 {
+			t.Errorf("message of %d bytes exceeds limit", len(m))
+		}
+		if !strings.HasPrefix(m, "```\n") || !strings.HasSuffix(m, "\n```") {
+			t.Errorf("message not wrapped in a code block: %q", m)
+		}
+		joined.WriteString(strings.TrimSuffix(strings.TrimPrefix(m, "```\n"), "```"))
+	}
+	if joined.String() != lines {
+		t.Errorf("split lost text: %q", joined.String())
+	}
+
+	long := strings.Repeat("é", 40) // 80 bytes on one line
+	for _, m := range Split(long, 30) {
+		if len(m) > 30 {
+			t.Errorf("message of %d bytes exceeds limit", len(m))
+		}
+		if !strings.HasSuffix(m, "\n```") || strings.ContainsRune(m, '�') {
+			t.Errorf("bad cut: %q", m)
+		}
+	}
+}
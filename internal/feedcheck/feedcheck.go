@@ -0,0 +1,296 @@
+// Package feedcheck verifies a published ICS feed the way a calendar app
+// sees it: fetched without credentials, checked for the encoding mistakes
+// that make phones silently drop events, parsed, and compared against the
+// events the cal API says the feed has.
+package feedcheck
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/ics"
+)
+
+// maxBody bounds how much of a feed is read.
+const maxBody = 32 << 20
+
+// Report is the outcome of checking a feed.
+type Report struct {
+	URL         string
+	StatusCode  int
+	ContentType string
+	Size        int
+	Events      []ics.Event // events parsed from the feed
+	Problems    []string
+}
+
+// OK reports whether no problems were found.
+func (r *Report) OK() bool { return len(r.Problems) == 0 }
+
+func (r *Report) problem(format string, args ...any) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+}
+
+// Fetch downloads url with no credentials, like a calendar app would, and
+// checks and parses the response. An error is returned only if the feed
+// could not be fetched at all; everything else is a problem in the report.
+func Fetch(hc *http.Client, url string) (*Report, error) {
+	if strings.HasPrefix(url, "webcal://") {
+		url = "https://" + strings.TrimPrefix(url, "webcal://")
+	}
+	resp, err := hc.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", url, err)
+	}
+
+	r := &Report{URL: url, StatusCode: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), Size: len(body)}
+	if resp.StatusCode != http.StatusOK {
+		r.problem("HTTP %d: calendar apps treat anything but 200 as a broken subscription", resp.StatusCode)
+		return r, nil
+	}
+	r.checkContentType()
+	r.checkBody(body)
+	return r, nil
+}
+
+func (r *Report) checkContentType() {
+	if r.ContentType == "" {
+		r.problem("no Content-Type; some apps require text/calendar")
+		return
+	}
+	mt, params, err := mime.ParseMediaType(r.ContentType)
+	if err != nil {
+		r.problem("malformed Content-Type %q", r.ContentType)
+		return
+	}
+	if mt != "text/calendar" {
+		r.problem("Content-Type is %s, not text/calendar; some apps refuse the feed", mt)
+	}
+	if cs := params["charset"]; cs != "" && !strings.EqualFold(cs, "utf-8") {
+		r.problem("charset is %s; RFC 5545 feeds must be UTF-8", cs)
+	}
+}
+
+// tzidParam finds TZID parameters so unknown zones can be reported; the
+// parser quietly falls back to local time for them, and so do most phones.
+var tzidParam = regexp.MustCompile(`;TZID="?([^";:]+)"?`)
+
+func (r *Report) checkBody(body []byte) {
+	if bytes.HasPrefix(body, []byte("\xef\xbb\xbf")) {
+		r.problem("body starts with a UTF-8 byte order mark; Google Calendar rejects it")
+		body = body[3:]
+	}
+
+	var badUTF8, bareLF, long []int
+	badTZ := map[string]bool{}
+	for i, line := range bytes.SplitAfter(body, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		n := i + 1
+		if !utf8.Valid(line) {
+			badUTF8 = append(badUTF8, n)
+		}
+		if bytes.HasSuffix(line, []byte("\n")) && !bytes.HasSuffix(line, []byte("\r\n")) {
+			bareLF = append(bareLF, n)
+		}
+		if len(bytes.TrimRight(line, "\r\n")) > 75 {
+			long = append(long, n)
+		}
+		for _, m := range tzidParam.FindAllSubmatch(line, -1) {
+			tz := string(m[1])
+			if _, err := time.LoadLocation(tz); err != nil && !badTZ[tz] {
+				badTZ[tz] = true
+				r.problem("unknown TZID %q (line %d); times using it are shown in the wrong zone", tz, n)
+			}
+		}
+	}
+	if len(badUTF8) > 0 {
+		r.problem("invalid UTF-8 on %s; text is garbled or the event dropped", lineList(badUTF8))
+	}
+	if len(bareLF) > 0 {
+		r.problem("%s end with LF instead of CRLF", lineList(bareLF))
+	}
+	if len(long) > 0 {
+		r.problem("%s longer than 75 octets and not folded", lineList(long))
+	}
+
+	text := string(body)
+	for _, want := range []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:"} {
+		if !strings.Contains(text, want) {
+			r.problem("missing %s", strings.TrimSuffix(want, ":"))
+		}
+	}
+
+	r.checkComponents(text)
+
+	c, err := ics.Parse(bytes.NewReader(body))
+	if err != nil {
+		r.problem("does not parse: %v", err)
+		return
+	}
+	r.Events = c.Events
+	for _, e := range c.Events {
+		if e.End != nil && e.End.Before(e.Start) {
+			r.problem("event %s ends before it starts; calendar apps drop it", e.UID)
+		}
+	}
+}
+
+// checkComponents checks the structure the parser is lenient about:
+// BEGIN and END in pairs, and every VEVENT with the UID and DTSTART that
+// calendar apps need, a UID of its own, and not both DTEND and DURATION.
+// Events sharing a UID are fine when all but one have a RECURRENCE-ID,
+// which marks them as changed occurrences of a recurring event.
+func (r *Report) checkComponents(text string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.NewReplacer("\n ", "", "\n\t", "").Replace(text)
+
+	var stack []string
+	var event map[string]bool
+	var uid string
+	var start int
+	uids := map[string]int{}
+	for i, line := range strings.Split(text, "\n") {
+		name, value, _ := strings.Cut(line, ":")
+		name, _, _ = strings.Cut(name, ";")
+		name = strings.ToUpper(name)
+		switch name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(value))
+			if strings.EqualFold(value, "VEVENT") {
+				event, uid, start = map[string]bool{}, "", i+1
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(value) {
+				r.problem("END:%s on line %d doesn't close the open component; the rest of the feed may be ignored", value, i+1)
+				return
+			}
+			stack = stack[:len(stack)-1]
+			if !strings.EqualFold(value, "VEVENT") {
+				continue
+			}
+			for _, want := range []string{"UID", "DTSTART"} {
+				if !event[want] {
+					r.problem("event starting on line %d has no %s; calendar apps drop it", start, want)
+				}
+			}
+			if event["DTEND"] && event["DURATION"] {
+				r.problem("event starting on line %d has both DTEND and DURATION", start)
+			}
+			if uid != "" && !event["RECURRENCE-ID"] {
+				uids[uid]++
+			}
+			event = nil
+			continue
+		}
+		// Properties of alarms and other components inside an event are
+		// the component's own.
+		if event != nil && len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+			event[name] = true
+			if name == "UID" {
+				uid = value
+			}
+		}
+	}
+	if len(stack) > 0 {
+		r.problem("BEGIN:%s is never closed; the feed is cut short", stack[len(stack)-1])
+	}
+	var dups []string
+	for uid, n := range uids {
+		if n > 1 {
+			dups = append(dups, uid)
+		}
+	}
+	slices.Sort(dups)
+	for _, uid := range dups {
+		r.problem("UID %s is used by %d events; calendar apps keep only one of them", uid, uids[uid])
+	}
+}
+
+// lineList names up to three line numbers ("line 4", "lines 4, 9, 12 and 3
+// more").
+func lineList(lines []int) string {
+	if len(lines) == 1 {
+		return fmt.Sprintf("line %d", lines[0])
+	}
+	var s []string
+	for _, n := range lines[:min(3, len(lines))] {
+		s = append(s, fmt.Sprint(n))
+	}
+	out := "lines " + strings.Join(s, ", ")
+	if len(lines) > 3 {
+		out += fmt.Sprintf(" and %d more", len(lines)-3)
+	}
+	return out
+}
+
+// Compare adds a problem to r for every API event missing from the feed,
+// every feed event the API doesn't know, and every event whose summary or
+// times differ between the two. Feed events are matched by UID (the event
+// ID, optionally followed by "@host", or its external ID), falling back to
+// summary and start time.
+func (r *Report) Compare(api []cal.Event) {
+	used := make([]bool, len(r.Events))
+	match := func(e cal.Event) int {
+		for i, f := range r.Events {
+			if !used[i] && (f.UID == e.ID || strings.HasPrefix(f.UID, e.ID+"@") || e.ExternalID != "" && f.UID == e.ExternalID) {
+				return i
+			}
+		}
+		for i, f := range r.Events {
+			if !used[i] && f.Summary == e.Summary && f.Start.Equal(e.Start) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, e := range api {
+		i := match(e)
+		if i < 0 {
+			r.problem("missing from feed: %q at %s (ID %s)", e.Summary, e.Start.Format(time.RFC3339), e.ID)
+			continue
+		}
+		used[i] = true
+		f := r.Events[i]
+		if f.Summary != e.Summary {
+			r.problem("%s: summary differs: API %q, feed %q (escaping or encoding problem?)", e.ID, e.Summary, f.Summary)
+		}
+		if !sameStart(e, f) {
+			r.problem("%s: start differs: API %s, feed %s", e.ID, e.Start.Format(time.RFC3339), f.Start.Format(time.RFC3339))
+		}
+		if e.End != nil && f.End != nil && !e.AllDay && !e.End.Equal(*f.End) {
+			r.problem("%s: end differs: API %s, feed %s", e.ID, e.End.Format(time.RFC3339), f.End.Format(time.RFC3339))
+		}
+	}
+	for i, f := range r.Events {
+		if !used[i] {
+			r.problem("in feed but not in the API: %q (UID %s)", f.Summary, f.UID)
+		}
+	}
+}
+
+// sameStart compares start times; all-day events only by date, since a
+// DATE value carries no zone.
+func sameStart(e cal.Event, f ics.Event) bool {
+	if e.AllDay || f.AllDay {
+		return e.AllDay == f.AllDay && e.Start.Format(time.DateOnly) == f.Start.Format(time.DateOnly)
+	}
+	return e.Start.Equal(f.Start)
+}
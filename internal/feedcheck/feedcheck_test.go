@@ -0,0 +1,141 @@
+package feedcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+const goodFeed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//cal//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:ev-1@cal.example.com\r\n" +
+	"SUMMARY:Standup\\, daily\r\n" +
+	"DTSTART:20260302T090000Z\r\n" +
+	"DTEND:20260302T091500Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:ev-2@cal.example.com\r\n" +
+	"SUMMARY:Launch\r\n" +
+	"DTSTART;VALUE=DATE:20260401\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func serve(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("feed fetched with credentials")
+		}
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func apiEvents() []cal.Event {
+	end := time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC)
+	return []cal.Event{
+		{ID: "ev-1", Summary: "Standup, daily", Start: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), End: &end},
+		{ID: "ev-2", Summary: "Launch", AllDay: true, Start: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestFetchGoodFeed(t *testing.T) {
+	srv := serve(t, "text/calendar; charset=utf-8", goodFeed)
+	r, err := Fetch(srv.Client(), srv.URL+"/team.ics")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.Compare(apiEvents())
+	if !r.OK() {
+		t.Errorf("unexpected problems: %q", r.Problems)
+	}
+	if len(r.Events) != 2 {
+		t.Errorf("expected 2 events, got %d", len(r.Events))
+	}
+}
+
+func TestFetchProblems(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{name: "content type", contentType: "text/plain", body: goodFeed, want: "not text/calendar"},
+		{name: "charset", contentType: "text/calendar; charset=iso-8859-1", body: goodFeed, want: "must be UTF-8"},
+		{name: "bom", contentType: "text/calendar", body: "\xef\xbb\xbf" + goodFeed, want: "byte order mark"},
+		{name: "bare LF", contentType: "text/calendar", body: strings.ReplaceAll(goodFeed, "\r\n", "\n"), want: "LF instead of CRLF"},
+		{name: "invalid UTF-8", contentType: "text/calendar", body: strings.Replace(goodFeed, "Launch", "L\xe4unch", 1), want: "invalid UTF-8 on line 12"},
+		{name: "long line", contentType: "text/calendar", body: strings.Replace(goodFeed, "Launch", strings.Repeat("x", 80), 1), want: "not folded"},
+		{name: "unknown tz", contentType: "text/calendar", body: strings.Replace(goodFeed, "DTSTART:20260302T090000Z", "DTSTART;TZID=Mars/Olympus:20260302T090000", 1), want: `unknown TZID "Mars/Olympus"`},
+		{name: "no prodid", contentType: "text/calendar", body: strings.Replace(goodFeed, "PRODID:-//cal//EN\r\n", "", 1), want: "missing PRODID"},
+		{name: "unparseable", contentType: "text/calendar", body: strings.Replace(goodFeed, "20260302T090000Z", "tomorrow", 1), want: "does not parse"},
+		{name: "no uid", contentType: "text/calendar", body: strings.Replace(goodFeed, "UID:ev-2@cal.example.com\r\n", "", 1), want: "line 10 has no UID"},
+		{name: "no start", contentType: "text/calendar", body: strings.Replace(goodFeed, "DTSTART;VALUE=DATE:20260401\r\n", "", 1), want: "has no DTSTART"},
+		{name: "duplicate uid", contentType: "text/calendar", body: strings.Replace(goodFeed, "ev-2@", "ev-1@", 1), want: "UID ev-1@cal.example.com is used by 2 events"},
+		{name: "end before start", contentType: "text/calendar", body: strings.Replace(goodFeed, "DTEND:20260302T091500Z", "DTEND:20260302T081500Z", 1), want: "ev-1@cal.example.com ends before it starts"},
+		{name: "end and duration", contentType: "text/calendar", body: strings.Replace(goodFeed, "DTEND:20260302T091500Z\r\n", "DTEND:20260302T091500Z\r\nDURATION:PT15M\r\n", 1), want: "both DTEND and DURATION"},
+		{name: "unclosed", contentType: "text/calendar", body: strings.Replace(goodFeed, "END:VEVENT\r\nBEGIN:VEVENT", "BEGIN:VEVENT", 1), want: "doesn't close"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serve(t, tt.contentType, tt.body)
+			r, err := Fetch(srv.Client(), srv.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(strings.Join(r.Problems, "\n"), tt.want) {
+				t.Errorf("problems %q do not mention %q", r.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	r, err := Fetch(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.OK() || r.StatusCode != http.StatusNotFound || !strings.Contains(r.Problems[0], "HTTP 404") {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	srv := serve(t, "text/calendar", goodFeed)
+	r, err := Fetch(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	api := apiEvents()
+	api[0].Summary = "Standup, daily ☕"
+	api[1].ID, api[1].Summary = "ev-9", "Retro" // unknown to the feed
+	r.Compare(api)
+
+	got := strings.Join(r.Problems, "\n")
+	for _, want := range []string{
+		`ev-1: summary differs`,
+		`missing from feed: "Retro"`,
+		`in feed but not in the API: "Launch"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("problems missing %q:\n%s", want, got)
+		}
+	}
+	if len(r.Problems) != 3 {
+		t.Errorf("expected 3 problems, got %d:\n%s", len(r.Problems), got)
+	}
+}
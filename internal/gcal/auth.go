@@ -0,0 +1,372 @@
+package gcal
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Google's OAuth endpoints.
+const (
+	TokenURL      = "https://oauth2.googleapis.com/token"
+	DeviceCodeURL = "https://oauth2.googleapis.com/device/code"
+)
+
+// Credentials is a credentials file from the Google Cloud console: a
+// service account key, or an OAuth client of the "TVs and Limited Input
+// devices" type, which authorizes a user through the device flow. Exactly
+// one of the fields is set.
+type Credentials struct {
+	ServiceAccount *ServiceAccount
+	OAuthClient    *OAuthClient
+}
+
+// LoadCredentials reads a credentials file.
+func LoadCredentials(path string) (*Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseCredentials(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// ParseCredentials parses the contents of a credentials file.
+func ParseCredentials(data []byte) (*Credentials, error) {
+	var f struct {
+		Type      string       `json:"type"`
+		Installed *OAuthClient `json:"installed"`
+		Web       *OAuthClient `json:"web"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse credentials: %w", err)
+	}
+	switch {
+	case f.Type == "service_account":
+		var sa ServiceAccount
+		if err := json.Unmarshal(data, &sa); err != nil {
+			return nil, fmt.Errorf("parse service account: %w", err)
+		}
+		if sa.ClientEmail == "" || sa.PrivateKey == "" {
+			return nil, errors.New("service account key lacks client_email or private_key")
+		}
+		return &Credentials{ServiceAccount: &sa}, nil
+	case f.Installed != nil, f.Web != nil:
+		c := f.Installed
+		if c == nil {
+			c = f.Web
+		}
+		if c.ClientID == "" {
+			return nil, errors.New("OAuth client lacks client_id")
+		}
+		return &Credentials{OAuthClient: c}, nil
+	}
+	return nil, errors.New("not a service account key or OAuth client file")
+}
+
+// cachedToken is an access token and when it expires.
+type cachedToken struct {
+	value  string
+	expiry time.Time
+}
+
+// valid reports whether the token can still be used at now, with a minute
+// to spare for the request it goes out with.
+func (t *cachedToken) valid(now time.Time) bool {
+	return t.value != "" && now.Add(time.Minute).Before(t.expiry)
+}
+
+// tokenResponse is a successful reply from the token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// oauthError is an error reply from an OAuth endpoint. Code is e.g.
+// invalid_grant or, while a device flow waits, authorization_pending.
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *oauthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// postForm posts form to an OAuth endpoint and decodes the reply into v.
+func postForm(hc *http.Client, endpoint string, form url.Values, v any) error {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.PostForm(endpoint, form)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		var oe oauthError
+		if json.Unmarshal(body, &oe) == nil && oe.Code != "" {
+			return &oe
+		}
+		return parseError(resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// ServiceAccount authenticates as a service account, which reads the
+// calendars shared with its ClientEmail. It signs a JWT with the account's
+// key and trades it for an access token.
+type ServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"` // PEM
+	TokenURI    string `json:"token_uri"`   // default TokenURL
+
+	HTTPClient *http.Client
+
+	token cachedToken
+	now   func() time.Time // for tests
+}
+
+// Token returns an access token, requesting a new one when the last has
+// expired.
+func (s *ServiceAccount) Token() (string, error) {
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	if s.token.valid(now) {
+		return s.token.value, nil
+	}
+	assertion, err := s.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	var tr tokenResponse
+	err = postForm(s.HTTPClient, s.tokenURL(), url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}, &tr)
+	if err != nil {
+		return "", fmt.Errorf("service account token: %w", err)
+	}
+	s.token = cachedToken{tr.AccessToken, now.Add(time.Duration(tr.ExpiresIn) * time.Second)}
+	return s.token.value, nil
+}
+
+func (s *ServiceAccount) tokenURL() string {
+	if s.TokenURI != "" {
+		return s.TokenURI
+	}
+	return TokenURL
+}
+
+// assertion returns the signed JWT asking for a read-only calendar token,
+// valid for an hour from now.
+func (s *ServiceAccount) assertion(now time.Time) (string, error) {
+	key, err := parseKey(s.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.ClientEmail,
+		"scope": Scope,
+		"aud":   s.tokenURL(),
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("sign token request: %w", err)
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
+
+// parseKey parses a service account's PEM private key, PKCS #8 as Google
+// issues them or PKCS #1.
+func parseKey(s string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("service account private_key is not PEM")
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	rk, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private_key is not an RSA key")
+	}
+	return rk, nil
+}
+
+// OAuthClient is an OAuth client registered in the Google Cloud console.
+type OAuthClient struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	TokenURI     string `json:"token_uri"` // default TokenURL
+}
+
+// DeviceFlow authenticates as a user who authorizes the OAuth client
+// once, by opening a URL on any device and entering a code. The refresh
+// token it gets is handed to Save, and later runs that set RefreshToken
+// need nobody at the keyboard.
+type DeviceFlow struct {
+	Client        OAuthClient
+	DeviceCodeURL string // default DeviceCodeURL
+	HTTPClient    *http.Client
+	RefreshToken  string
+
+	// Prompt tells the user where to go and which code to enter.
+	Prompt func(verificationURL, userCode string)
+	// Save, if set, keeps a new refresh token for later runs.
+	Save func(refreshToken string) error
+
+	token cachedToken
+	now   func() time.Time    // for tests
+	sleep func(time.Duration) // for tests
+}
+
+// Token returns an access token: a cached one, one from the refresh token,
+// or, when there is no refresh token or Google revoked it, one the user
+// authorizes through the device flow.
+func (d *DeviceFlow) Token() (string, error) {
+	if d.now == nil {
+		d.now = time.Now
+	}
+	if d.token.valid(d.now()) {
+		return d.token.value, nil
+	}
+	if d.RefreshToken != "" {
+		var tr tokenResponse
+		err := postForm(d.HTTPClient, d.tokenURL(), url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {d.RefreshToken},
+			"client_id":     {d.Client.ClientID},
+			"client_secret": {d.Client.ClientSecret},
+		}, &tr)
+		var oe *oauthError
+		switch {
+		case err == nil:
+			return d.keep(tr)
+		case !errors.As(err, &oe) || oe.Code != "invalid_grant":
+			return "", fmt.Errorf("refresh token: %w", err)
+		}
+		// Revoked or expired: authorize again.
+		d.RefreshToken = ""
+	}
+	return d.authorize()
+}
+
+func (d *DeviceFlow) tokenURL() string {
+	if d.Client.TokenURI != "" {
+		return d.Client.TokenURI
+	}
+	return TokenURL
+}
+
+// keep caches the access token of tr and saves a new refresh token.
+func (d *DeviceFlow) keep(tr tokenResponse) (string, error) {
+	d.token = cachedToken{tr.AccessToken, d.now().Add(time.Duration(tr.ExpiresIn) * time.Second)}
+	if tr.RefreshToken != "" && tr.RefreshToken != d.RefreshToken {
+		d.RefreshToken = tr.RefreshToken
+		if d.Save != nil {
+			if err := d.Save(tr.RefreshToken); err != nil {
+				return "", fmt.Errorf("save refresh token: %w", err)
+			}
+		}
+	}
+	return d.token.value, nil
+}
+
+// authorize runs the device flow: it asks for a user code, shows it with
+// Prompt and polls until the user has approved or the code expires.
+func (d *DeviceFlow) authorize() (string, error) {
+	if d.Prompt == nil {
+		return "", errors.New("authorization needed, but nobody can be asked")
+	}
+	endpoint := d.DeviceCodeURL
+	if endpoint == "" {
+		endpoint = DeviceCodeURL
+	}
+	var dc struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURL string `json:"verification_url"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	err := postForm(d.HTTPClient, endpoint, url.Values{
+		"client_id": {d.Client.ClientID},
+		"scope":     {Scope},
+	}, &dc)
+	if err != nil {
+		return "", fmt.Errorf("device code: %w", err)
+	}
+	verify := dc.VerificationURL
+	if verify == "" {
+		verify = dc.VerificationURI
+	}
+	d.Prompt(verify, dc.UserCode)
+
+	sleep := d.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	interval := max(time.Duration(dc.Interval)*time.Second, time.Second)
+	deadline := d.now().Add(time.Duration(dc.ExpiresIn) * time.Second)
+	for d.now().Before(deadline) {
+		sleep(interval)
+		var tr tokenResponse
+		err := postForm(d.HTTPClient, d.tokenURL(), url.Values{
+			"grant_type":    {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code":   {dc.DeviceCode},
+			"client_id":     {d.Client.ClientID},
+			"client_secret": {d.Client.ClientSecret},
+		}, &tr)
+		var oe *oauthError
+		switch {
+		case err == nil:
+			return d.keep(tr)
+		case errors.As(err, &oe) && oe.Code == "authorization_pending":
+		case errors.As(err, &oe) && oe.Code == "slow_down":
+			interval += 5 * time.Second
+		default:
+			return "", fmt.Errorf("device authorization: %w", err)
+		}
+	}
+	return "", errors.New("device authorization: the code expired before it was entered")
+}
@@ -0,0 +1,164 @@
+// Package gcal reads events from the Google Calendar API, authenticating
+// with a service account key or an OAuth client through the device flow.
+// It only reads: pylon never writes to Google.
+package gcal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// APIBase is the Google Calendar API the client talks to.
+const APIBase = "https://www.googleapis.com/calendar/v3"
+
+// Scope is the OAuth scope pylon asks for: read access to calendars.
+const Scope = "https://www.googleapis.com/auth/calendar.readonly"
+
+// TokenSource hands out access tokens, refreshing them as they expire.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// Client lists events from Google Calendar.
+type Client struct {
+	BaseURL    string // default APIBase
+	HTTPClient *http.Client
+	Auth       TokenSource
+}
+
+// NewClient returns a client authenticating with auth. A nil hc means
+// http.DefaultClient.
+func NewClient(auth TokenSource, hc *http.Client) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &Client{BaseURL: APIBase, HTTPClient: hc, Auth: auth}
+}
+
+// Event is a Google Calendar event, or one occurrence of a recurring one.
+type Event struct {
+	ID          string    `json:"id"`
+	Status      string    `json:"status"` // confirmed, tentative or cancelled
+	Summary     string    `json:"summary"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	HTMLLink    string    `json:"htmlLink"`
+	Start       EventTime `json:"start"`
+	End         EventTime `json:"end"`
+	// RecurringEventID is set on occurrences of a recurring event.
+	RecurringEventID string `json:"recurringEventId,omitempty"`
+}
+
+// EventTime is when an event starts or ends: Date (YYYY-MM-DD) for all-day
+// events, DateTime otherwise. An all-day event's end date is exclusive.
+type EventTime struct {
+	Date     string     `json:"date,omitempty"`
+	DateTime *time.Time `json:"dateTime,omitempty"`
+}
+
+// AllDay reports whether t is a date rather than a time.
+func (t EventTime) AllDay() bool {
+	return t.Date != ""
+}
+
+// Time returns t as a time: midnight UTC for a date, which is how pylon
+// stores all-day events.
+func (t EventTime) Time() (time.Time, error) {
+	if t.DateTime != nil {
+		return *t.DateTime, nil
+	}
+	if t.Date == "" {
+		return time.Time{}, errors.New("event time has neither date nor dateTime")
+	}
+	return time.Parse(time.DateOnly, t.Date)
+}
+
+// APIError is an error response from Google.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("google API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Events returns the events of calendarID that overlap [from, to), with
+// recurring events expanded into their occurrences, ordered by start.
+// Deleted events are left out. "primary" names the authenticated user's
+// main calendar.
+func (c *Client) Events(calendarID string, from, to time.Time) ([]Event, error) {
+	var all []Event
+	pageToken := ""
+	for {
+		q := url.Values{
+			"singleEvents": {"true"},
+			"orderBy":      {"startTime"},
+			"timeMin":      {from.UTC().Format(time.RFC3339)},
+			"timeMax":      {to.UTC().Format(time.RFC3339)},
+			"maxResults":   {"2500"},
+		}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		var page struct {
+			Items         []Event `json:"items"`
+			NextPageToken string  `json:"nextPageToken"`
+		}
+		path := "/calendars/" + url.PathEscape(calendarID) + "/events?" + q.Encode()
+		if err := c.get(path, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Items...)
+		if page.NextPageToken == "" {
+			return all, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// get fetches path from the API and decodes the JSON response into v.
+func (c *Client) get(path string, v any) error {
+	token, err := c.Auth.Token()
+	if err != nil {
+		return fmt.Errorf("google auth: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return parseError(resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// parseError turns an API error body, {"error": {"message": ...}}, into an
+// APIError.
+func parseError(status int, body []byte) error {
+	var api struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &api) == nil && api.Error.Message != "" {
+		return &APIError{StatusCode: status, Message: api.Error.Message}
+	}
+	return &APIError{StatusCode: status, Message: strings.TrimSpace(string(body))}
+}
@@ -0,0 +1,73 @@
+package gcal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type staticToken string
+
+func (s staticToken) Token() (string, error) { return string(s), nil }
+
+func TestEvents(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendars/team@example.com/events" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		q := r.URL.Query()
+		if q.Get("singleEvents") != "true" || q.Get("timeMin") != "2026-03-01T00:00:00Z" || q.Get("timeMax") != "2026-04-01T00:00:00Z" {
+			t.Errorf("query = %v", q)
+		}
+		switch q.Get("pageToken") {
+		case "":
+			_, _ = w.Write([]byte(`{"items":[{"id":"a","summary":"Standup","start":{"dateTime":"2026-03-02T09:00:00+01:00"},"end":{"dateTime":"2026-03-02T09:15:00+01:00"}}],"nextPageToken":"p2"}`))
+		case "p2":
+			_, _ = w.Write([]byte(`{"items":[{"id":"b","summary":"Offsite","start":{"date":"2026-03-10"},"end":{"date":"2026-03-12"}}]}`))
+		default:
+			t.Errorf("unexpected page %q", q.Get("pageToken"))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(staticToken("tok"), srv.Client())
+	c.BaseURL = srv.URL
+	events, err := c.Events("team@example.com", from, to)
+	if err != nil {
+		t.Fatalf("Events: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != "a" || events[1].ID != "b" {
+		t.Fatalf("events = %+v", events)
+	}
+	start, err := events[0].Start.Time()
+	if err != nil || !start.Equal(time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)) || events[0].Start.AllDay() {
+		t.Errorf("timed start = %v, %v", start, err)
+	}
+	day, err := events[1].End.Time()
+	if err != nil || !day.Equal(time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)) || !events[1].End.AllDay() {
+		t.Errorf("all-day end = %v, %v", day, err)
+	}
+}
+
+func TestEventsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Not Found"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(staticToken("tok"), srv.Client())
+	c.BaseURL = srv.URL
+	_, err := c.Events("nope", time.Now(), time.Now().Add(time.Hour))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Not Found" {
+		t.Fatalf("err = %v, want a 404 APIError", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Package heatmap renders the year view of `pylon cal heatmap`: one cell
+// per day, shaded by how many events fall on it, in weekly columns like a
+// contribution graph.
+package heatmap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/recur"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// Year holds the number of events on each day of a calendar year.
+type Year struct {
+	Year   int
+	Loc    *time.Location
+	Counts []int // indexed by day of the year, from 0
+}
+
+// Count tallies events over year in loc. Recurring events count once per
+// occurrence and an event spanning several days counts on each of them;
+// cancelled events are left out.
+func Count(events []cal.Event, year int, loc *time.Location) *Year {
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	to := from.AddDate(1, 0, 0)
+	y := &Year{Year: year, Loc: loc, Counts: make([]int, timeutil.DaysBetween(from, to, loc))}
+
+	for _, e := range recur.ExpandAll(events, from, to) {
+		if strings.EqualFold(e.Status, "cancelled") {
+			continue
+		}
+		first, last := days(e, loc)
+		for d := first; !d.After(last); d = timeutil.AddDays(d, 1) {
+			if n := timeutil.DaysBetween(from, d, loc); n >= 0 && n < len(y.Counts) {
+				y.Counts[n]++
+			}
+		}
+	}
+	return y
+}
+
+// days returns the midnights of the first and last day e covers in loc.
+// All-day dates are calendar dates and their end is exclusive; a timed
+// event ending exactly at midnight doesn't cover the day after.
+func days(e cal.Event, loc *time.Location) (first, last time.Time) {
+	if e.AllDay {
+		y, m, d := e.Start.Date()
+		first = time.Date(y, m, d, 0, 0, 0, 0, loc)
+		last = first
+		if e.End != nil {
+			y, m, d := e.End.Date()
+			if end := time.Date(y, m, d, 0, 0, 0, 0, loc); end.After(first) {
+				last = timeutil.AddDays(end, -1)
+			}
+		}
+		return first, last
+	}
+	first = timeutil.StartOfDay(e.Start, loc)
+	last = first
+	if e.End != nil && e.End.After(e.Start) {
+		last = timeutil.StartOfDay(e.End.Add(-time.Nanosecond), loc)
+	}
+	return first, last
+}
+
+// Max returns the highest count of any day.
+func (y *Year) Max() int {
+	m := 0
+	for _, n := range y.Counts {
+		m = max(m, n)
+	}
+	return m
+}
+
+// Total returns the number of event-days in the year.
+func (y *Year) Total() int {
+	t := 0
+	for _, n := range y.Counts {
+		t += n
+	}
+	return t
+}
+
+// Shades are the cells for no events and the four levels of density.
+var (
+	Shades = []string{"·", "░", "▒", "▓", "█"}
+	ASCII  = []string{".", "-", "+", "*", "#"}
+)
+
+// level maps a count to a shade, spreading 1..max over four levels.
+func level(n, max int) int {
+	if n <= 0 || max <= 0 {
+		return 0
+	}
+	return min(4, (n*4+max-1)/max)
+}
+
+// Render draws the year with weeks as columns, Monday to Sunday top to
+// bottom, month names above the week each month starts in, and a legend.
+func (y *Year) Render(shades []string) string {
+	jan1 := time.Date(y.Year, 1, 1, 0, 0, 0, 0, y.Loc)
+	lead := (int(jan1.Weekday()) + 6) % 7 // days before Jan 1 in its week
+	weeks := (lead + len(y.Counts) + 6) / 7
+	peak := y.Max()
+
+	var sb strings.Builder
+	months := []byte(strings.Repeat(" ", weeks+4))
+	for m := time.January; m <= time.December; m++ {
+		day := timeutil.DaysBetween(jan1, time.Date(y.Year, m, 1, 0, 0, 0, 0, y.Loc), y.Loc)
+		col := 4 + (lead+day)/7
+		copy(months[min(col, len(months)-3):], m.String()[:3])
+	}
+	sb.WriteString(strings.TrimRight(string(months), " ") + "\n")
+
+	labels := []string{"Mon", "", "Wed", "", "Fri", "", "Sun"}
+	for row := 0; row < 7; row++ {
+		line := fmt.Sprintf("%-4s", labels[row])
+		for w := 0; w < weeks; w++ {
+			day := w*7 + row - lead
+			if day < 0 || day >= len(y.Counts) {
+				line += " "
+				continue
+			}
+			line += shades[level(y.Counts[day], peak)]
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	fmt.Fprintf(&sb, "\n    Less %s More\n", strings.Join(shades, " "))
+	return sb.String()
+}
+
+// Week is a Monday-to-Sunday week and its event count.
+type Week struct {
+	Start time.Time
+	Count int
+}
+
+// Busiest returns the week with the most events. Weeks are clipped to the
+// year.
+func (y *Year) Busiest() Week {
+	jan1 := time.Date(y.Year, 1, 1, 0, 0, 0, 0, y.Loc)
+	lead := (int(jan1.Weekday()) + 6) % 7
+	var best Week
+	for start := -lead; start < len(y.Counts); start += 7 {
+		n := 0
+		for d := max(start, 0); d < min(start+7, len(y.Counts)); d++ {
+			n += y.Counts[d]
+		}
+		if n > best.Count {
+			best = Week{Start: timeutil.AddDays(jan1, start), Count: n}
+		}
+	}
+	return best
+}
+
+// Gap is a run of days without events.
+type Gap struct {
+	First, Last time.Time
+	Days        int
+}
+
+// LongestGap returns the longest run of empty days within [from, to) of
+// the year, e.g. to stop at today for the current year. It is zero when
+// every day has an event.
+func (y *Year) LongestGap(from, to time.Time) Gap {
+	jan1 := time.Date(y.Year, 1, 1, 0, 0, 0, 0, y.Loc)
+	lo := max(0, timeutil.DaysBetween(jan1, from, y.Loc))
+	hi := min(len(y.Counts), timeutil.DaysBetween(jan1, to, y.Loc))
+	var best Gap
+	run := 0
+	for d := lo; d <= hi; d++ {
+		if d < hi && y.Counts[d] == 0 {
+			run++
+			continue
+		}
+		if run > best.Days {
+			best = Gap{First: timeutil.AddDays(jan1, d-run), Last: timeutil.AddDays(jan1, d-1), Days: run}
+		}
+		run = 0
+	}
+	return best
+}
@@ -0,0 +1,68 @@
+package heatmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func TestCount(t *testing.T) {
+	loc := time.UTC
+	at := func(m time.Month, d, h int) time.Time { return time.Date(2026, m, d, h, 0, 0, 0, loc) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	events := []cal.Event{
+		{Start: at(time.January, 1, 9)},
+		{Start: at(time.January, 1, 14), End: ptr(at(time.January, 1, 15))},
+		// Timed, overnight: counts on both days.
+		{Start: at(time.January, 2, 22), End: ptr(at(time.January, 3, 2))},
+		// All-day, three days with an exclusive end.
+		{Start: at(time.February, 1, 0), End: ptr(at(time.February, 4, 0)), AllDay: true},
+		// Weekly through January: Mondays 5, 12, 19 and 26.
+		{Start: at(time.January, 5, 10), RRule: "FREQ=WEEKLY;UNTIL=20260131T000000Z"},
+		{Start: at(time.March, 1, 9), Status: "CANCELLED"},
+		{Start: time.Date(2025, 12, 31, 9, 0, 0, 0, loc)},
+	}
+	y := Count(events, 2026, loc)
+	if len(y.Counts) != 365 {
+		t.Fatalf("len(Counts) = %d", len(y.Counts))
+	}
+	want := map[int]int{0: 2, 1: 1, 2: 1, 4: 1, 11: 1, 18: 1, 25: 1, 31: 1, 32: 1, 33: 1}
+	for day, n := range y.Counts {
+		if n != want[day] {
+			t.Errorf("day %d: count %d, want %d", day, n, want[day])
+		}
+	}
+	if y.Max() != 2 || y.Total() != 11 {
+		t.Errorf("Max = %d, Total = %d", y.Max(), y.Total())
+	}
+
+	if w := y.Busiest(); w.Count != 4 || !w.Start.Equal(at(time.December, 29, 0).AddDate(-1, 0, 0)) {
+		t.Errorf("Busiest = %+v", w)
+	}
+	gap := y.LongestGap(at(time.January, 1, 0), at(time.March, 1, 0))
+	if gap.Days != 25 || !gap.First.Equal(at(time.February, 4, 0)) {
+		t.Errorf("LongestGap = %+v", gap)
+	}
+}
+
+func TestRender(t *testing.T) {
+	y := &Year{Year: 2026, Loc: time.UTC, Counts: make([]int, 365)}
+	y.Counts[0] = 4 // Thursday, Jan 1
+	y.Counts[1] = 1
+	out := y.Render(ASCII)
+	lines := strings.Split(out, "\n")
+	if !strings.HasPrefix(lines[0], "    Jan Feb Mar") {
+		t.Errorf("month row = %q", lines[0])
+	}
+	// 2026 starts on a Thursday: the first column has Jan 1 on its
+	// Thursday row and blanks above.
+	if !strings.HasPrefix(lines[1], "Mon  .") || !strings.HasPrefix(lines[4], "    #.") || !strings.HasPrefix(lines[5], "Fri -.") {
+		t.Errorf("unexpected grid:\n%s", out)
+	}
+	if len(lines[1]) != 4+53 {
+		t.Errorf("row is %d wide, want 57", len(lines[1]))
+	}
+}
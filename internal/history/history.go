@@ -0,0 +1,166 @@
+// Package history keeps the command lines pylon has run, with secrets
+// stripped, for `pylon history` and `pylon redo`.
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/checkpoint"
+	"github.com/jredh-dev/pylon/internal/shellwords"
+)
+
+// Redacted replaces secrets in recorded arguments.
+const Redacted = "<redacted>"
+
+// Entry is one recorded command line.
+type Entry struct {
+	Time time.Time `json:"time"`
+	Dir  string    `json:"dir,omitempty"` // working directory, for relative paths
+	Args []string  `json:"args"`          // arguments after "pylon"
+}
+
+// String returns the command line as it could be typed into a shell.
+func (e Entry) String() string {
+	return "pylon " + shellwords.Join(e.Args)
+}
+
+// HasSecrets reports whether arguments were redacted, so the command
+// can't be run again as recorded.
+func (e Entry) HasSecrets() bool {
+	return slices.ContainsFunc(e.Args, func(a string) bool { return strings.Contains(a, Redacted) })
+}
+
+// Redact returns a copy of args with secrets replaced by Redacted: the
+// values of flags named like a token, secret or password, the value of
+// `config set` for a key secret reports as secret, and URLs that carry
+// credentials, such as webhook URLs or ones with a password or token.
+func Redact(args []string, secret func(key string) bool) []string {
+	out := slices.Clone(args)
+	for i := 0; i < len(out); i++ {
+		a := out[i]
+		if name, value, ok := strings.Cut(a, "="); ok && strings.HasPrefix(name, "--") {
+			if secretFlag(name) {
+				out[i] = name + "=" + Redacted
+			} else {
+				out[i] = name + "=" + redactURL(value)
+			}
+			continue
+		}
+		if strings.HasPrefix(a, "--") && secretFlag(a) && i+1 < len(out) {
+			i++
+			out[i] = Redacted
+			continue
+		}
+		if a == "config" && i+3 < len(out) && out[i+1] == "set" && secret != nil && secret(out[i+2]) {
+			out[i+3] = Redacted
+			i += 3
+			continue
+		}
+		out[i] = redactURL(a)
+	}
+	return out
+}
+
+func secretFlag(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "password")
+}
+
+// redactURL redacts a credential-bearing URL in s, which may have a prefix
+// such as "webhook:".
+func redactURL(s string) string {
+	i := strings.Index(s, "://")
+	if i < 0 {
+		return s
+	}
+	start := strings.LastIndexAny(s[:i], ":=") + 1
+	u, err := url.Parse(s[start:])
+	if err != nil || u.Host == "" {
+		return s
+	}
+	if strings.Contains(u.Path, "/webhooks/") || strings.Contains(u.Host, "hooks.slack.com") {
+		return s[:start] + Redacted
+	}
+	if _, ok := u.User.Password(); ok {
+		user := u.User.Username()
+		u.User = nil
+		return s[:start] + strings.Replace(u.String(), "://", "://"+user+":"+Redacted+"@", 1)
+	}
+	params := strings.Split(u.RawQuery, "&")
+	changed := false
+	for i, p := range params {
+		k, _, _ := strings.Cut(p, "=")
+		if secretFlag(k) || strings.EqualFold(k, "key") || strings.EqualFold(k, "sig") {
+			params[i] = k + "=" + Redacted
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = strings.Join(params, "&")
+		return s[:start] + u.String()
+	}
+	return s
+}
+
+// Read returns the entries recorded at path, oldest first. A missing file
+// is an empty history.
+func Read(path string) ([]Entry, error) {
+	entries, err := checkpoint.Read[Entry](path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return entries, err
+}
+
+// Append records e at path. Once the file grows past a few hundred KB it
+// is trimmed to the last keep entries.
+func Append(path string, e Entry, keep int) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	info, serr := f.Stat()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || serr != nil || info.Size() < 256<<10 {
+		return err
+	}
+	return trim(path, keep)
+}
+
+// trim rewrites the history at path with only its last keep entries.
+func trim(path string, keep int) error {
+	entries, err := Read(path)
+	if err != nil || len(entries) <= keep {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries[len(entries)-keep:] {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
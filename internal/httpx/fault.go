@@ -0,0 +1,134 @@
+package httpx
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Fault kinds a Faults transport can inject.
+const (
+	Fault429     = "429"
+	Fault500     = "500"
+	FaultTimeout = "timeout"
+)
+
+// Faults is an http.RoundTripper that fails a random share of requests
+// without sending them, to exercise retry and error handling end to end.
+// Each failed request gets one of Kinds, picked at random: a 429 with
+// Retry-After, a 500, or a timeout error.
+type Faults struct {
+	Next  http.RoundTripper // nil means http.DefaultTransport
+	Rate  float64           // share of requests to fail, 0 to 1
+	Kinds []string          // empty means all kinds
+
+	mu   sync.Mutex
+	rand *rand.Rand // nil means the global source; set in tests
+}
+
+// ParseFaults parses a fault specification: a rate, as a fraction or a
+// percentage, optionally followed by a colon and the comma-separated kinds
+// to inject, e.g. "0.1", "25%" or "0.2:429,timeout".
+func ParseFaults(s string) (*Faults, error) {
+	rate, kinds, _ := strings.Cut(strings.TrimSpace(s), ":")
+	f := &Faults{}
+	var err error
+	if pct, ok := strings.CutSuffix(rate, "%"); ok {
+		f.Rate, err = strconv.ParseFloat(pct, 64)
+		f.Rate /= 100
+	} else {
+		f.Rate, err = strconv.ParseFloat(rate, 64)
+	}
+	if err != nil || f.Rate < 0 || f.Rate > 1 {
+		return nil, fmt.Errorf("invalid fault rate %q: want a number from 0 to 1 or a percentage", rate)
+	}
+	if kinds == "" {
+		return f, nil
+	}
+	for _, k := range strings.Split(kinds, ",") {
+		k = strings.ToLower(strings.TrimSpace(k))
+		switch k {
+		case Fault429, Fault500, FaultTimeout:
+			f.Kinds = append(f.Kinds, k)
+		default:
+			return nil, fmt.Errorf("invalid fault kind %q: want 429, 500 or timeout", k)
+		}
+	}
+	return f, nil
+}
+
+// RoundTrip fails the request at the configured rate and sends it
+// otherwise.
+func (f *Faults) RoundTrip(req *http.Request) (*http.Response, error) {
+	kinds := f.Kinds
+	if len(kinds) == 0 {
+		kinds = []string{Fault429, Fault500, FaultTimeout}
+	}
+	f.mu.Lock()
+	fail := f.float() < f.Rate
+	kind := kinds[f.intN(len(kinds))]
+	f.mu.Unlock()
+
+	if !fail {
+		next := f.Next
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		return next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	switch kind {
+	case Fault429:
+		return faultResponse(req, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}}), nil
+	case Fault500:
+		return faultResponse(req, http.StatusInternalServerError, http.Header{}), nil
+	}
+	return nil, timeoutError{}
+}
+
+func (f *Faults) float() float64 {
+	if f.rand == nil {
+		return rand.Float64()
+	}
+	return f.rand.Float64()
+}
+
+func (f *Faults) intN(n int) int {
+	if f.rand == nil {
+		return rand.IntN(n)
+	}
+	return f.rand.IntN(n)
+}
+
+// faultResponse builds an injected error response. The body carries both
+// "error" and "message" so the cal and Discord clients both report it.
+func faultResponse(req *http.Request, status int, header http.Header) *http.Response {
+	msg := fmt.Sprintf("injected fault: %d %s", status, http.StatusText(status))
+	body := fmt.Sprintf(`{"error":%q,"message":%q}`, msg, msg)
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// timeoutError looks like a client timeout to callers checking
+// net.Error.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "injected fault: timeout awaiting response" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
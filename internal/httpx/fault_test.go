@@ -0,0 +1,104 @@
+package httpx
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestParseFaults(t *testing.T) {
+	tests := []struct {
+		in      string
+		rate    float64
+		kinds   []string
+		wantErr bool
+	}{
+		{in: "0.1", rate: 0.1},
+		{in: "25%", rate: 0.25},
+		{in: "1:429, Timeout", rate: 1, kinds: []string{Fault429, FaultTimeout}},
+		{in: "0", rate: 0},
+		{in: "1.5", wantErr: true},
+		{in: "often", wantErr: true},
+		{in: "0.5:404", wantErr: true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFaults(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseFaults(%q): expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil || f.Rate != tt.rate || !slices.Equal(f.Kinds, tt.kinds) {
+			t.Errorf("ParseFaults(%q) = %+v, %v", tt.in, f, err)
+		}
+	}
+}
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		kind       string
+		wantStatus int
+	}{
+		{kind: Fault429, wantStatus: http.StatusTooManyRequests},
+		{kind: Fault500, wantStatus: http.StatusInternalServerError},
+		{kind: FaultTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			noSleep(t)
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+			}))
+			defer srv.Close()
+
+			f := &Faults{Next: srv.Client().Transport, Rate: 1, Kinds: []string{tt.kind}}
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			resp, err := Do(&http.Client{Transport: f}, req, 2)
+			if tt.wantStatus == 0 {
+				var ne net.Error
+				if !errors.As(err, &ne) || !ne.Timeout() {
+					t.Fatalf("expected a timeout error, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Do: %v", err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+				}
+			}
+			if calls != 0 {
+				t.Errorf("an injected fault reached the server %d times", calls)
+			}
+		})
+	}
+}
+
+func TestFaultsRate(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer srv.Close()
+
+	f := &Faults{Next: srv.Client().Transport, Rate: 0.3, Kinds: []string{Fault500}, rand: rand.New(rand.NewPCG(1, 2))}
+	hc := &http.Client{Transport: f}
+	const n = 200
+	for i := 0; i < n; i++ {
+		resp, err := hc.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if failed := n - calls; failed < 40 || failed > 80 {
+		t.Errorf("%d of %d requests failed at a rate of 0.3", failed, n)
+	}
+}
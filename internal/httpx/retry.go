@@ -0,0 +1,130 @@
+// Package httpx holds HTTP plumbing shared by the service clients.
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// minBackoff is the first exponential backoff delay.
+	minBackoff = 500 * time.Millisecond
+	// maxBackoff caps the exponential backoff delay.
+	maxBackoff = 10 * time.Second
+	// maxWait is the longest server-requested delay we are willing to honour.
+	// A longer Retry-After is treated as a hard failure.
+	maxWait = time.Minute
+)
+
+// sleep waits for d or until ctx is done. Overridden in tests.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Do sends req with hc, retrying transient failures up to retries times.
+//
+// 429 and 503 responses mean the server did not process the request, so they
+// are retried for any method. 502, 504 and transport errors are ambiguous and
+// only retried for idempotent methods, so a flaky proxy can never duplicate a
+// POST. Delays honour Retry-After and Discord's X-RateLimit-Reset-After
+// headers, falling back to exponential backoff with jitter.
+func Do(hc *http.Client, req *http.Request, retries int) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := hc.Do(req)
+		if attempt >= retries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// Body can't be replayed.
+			return resp, err
+		}
+
+		wait := backoff(attempt)
+		if resp != nil {
+			if d, ok := RetryAfter(resp); ok {
+				if d > maxWait {
+					return resp, err
+				}
+				wait = d
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether a request outcome is worth retrying.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return idempotent(req.Method) && req.Context().Err() == nil && !errors.Is(err, ErrBudget)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(req.Method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RetryAfter extracts the server-requested delay from a response. It
+// understands Retry-After in both delta-seconds and HTTP-date form, and
+// Discord's X-RateLimit-Reset-After (fractional seconds).
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(time.Until(t), 0), true
+		}
+	}
+	if v := resp.Header.Get("X-RateLimit-Reset-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+	}
+	return 0, false
+}
+
+// backoff returns the delay before retry number attempt+1: exponential from
+// minBackoff, capped at maxBackoff, with up to 50% random jitter.
+func backoff(attempt int) time.Duration {
+	d := minBackoff << attempt
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
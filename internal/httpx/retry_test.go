@@ -0,0 +1,223 @@
+package httpx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// noSleep replaces the package sleep for the duration of a test and records
+// each requested delay.
+func noSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	t.Cleanup(func() { sleep = orig })
+	return &waits
+}
+
+func TestDoRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int // status per attempt; last one repeats
+		retries    int
+		wantStatus int
+		wantCalls  int
+	}{
+		{
+			name:       "503 then success",
+			method:     http.MethodGet,
+			statuses:   []int{503, 200},
+			retries:    3,
+			wantStatus: 200,
+			wantCalls:  2,
+		},
+		{
+			name:       "429 retried for POST",
+			method:     http.MethodPost,
+			statuses:   []int{429, 201},
+			retries:    3,
+			wantStatus: 201,
+			wantCalls:  2,
+		},
+		{
+			name:       "502 not retried for POST",
+			method:     http.MethodPost,
+			statuses:   []int{502, 201},
+			retries:    3,
+			wantStatus: 502,
+			wantCalls:  1,
+		},
+		{
+			name:       "502 retried for GET",
+			method:     http.MethodGet,
+			statuses:   []int{502, 502, 200},
+			retries:    3,
+			wantStatus: 200,
+			wantCalls:  3,
+		},
+		{
+			name:       "gives up after retries",
+			method:     http.MethodGet,
+			statuses:   []int{503},
+			retries:    2,
+			wantStatus: 503,
+			wantCalls:  3,
+		},
+		{
+			name:       "zero retries",
+			method:     http.MethodGet,
+			statuses:   []int{503},
+			retries:    0,
+			wantStatus: 503,
+			wantCalls:  1,
+		},
+		{
+			name:       "500 not retried",
+			method:     http.MethodGet,
+			statuses:   []int{500, 200},
+			retries:    3,
+			wantStatus: 500,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noSleep(t)
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					body, _ := io.ReadAll(r.Body)
+					if string(body) != "payload" {
+						t.Errorf("attempt %d: expected body %q, got %q", calls+1, "payload", body)
+					}
+				}
+				status := tt.statuses[min(calls, len(tt.statuses)-1)]
+				calls++
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			var body io.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader("payload")
+			}
+			req, err := http.NewRequest(tt.method, srv.URL, body)
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+
+			resp, err := Do(srv.Client(), req, tt.retries)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestDoHonorsRetryAfter(t *testing.T) {
+	waits := noSleep(t)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := Do(srv.Client(), req, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(*waits) != 1 || (*waits)[0] != 2*time.Second {
+		t.Errorf("expected a single 2s wait, got %v", *waits)
+	}
+}
+
+func TestDoRejectsLongRetryAfter(t *testing.T) {
+	noSleep(t)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := Do(srv.Client(), req, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", header: "Retry-After", value: "5", want: 5 * time.Second, wantOK: true},
+		{name: "discord reset after", header: "X-RateLimit-Reset-After", value: "1.5", want: 1500 * time.Millisecond, wantOK: true},
+		{name: "past http date", header: "Retry-After", value: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0, wantOK: true},
+		{name: "garbage", header: "Retry-After", value: "soon", wantOK: false},
+		{name: "missing", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set(tt.header, tt.value)
+			}
+			got, ok := RetryAfter(resp)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if ok && got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	for attempt := 0; attempt < 40; attempt++ {
+		d := backoff(attempt)
+		if d < minBackoff/2 || d > maxBackoff {
+			t.Errorf("attempt %d: backoff %v out of bounds", attempt, d)
+		}
+	}
+}
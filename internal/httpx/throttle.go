@@ -0,0 +1,54 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrBudget is returned once a Throttle's request budget is used up.
+var ErrBudget = errors.New("request budget exhausted")
+
+// Throttle is an http.RoundTripper that spaces requests at least Interval
+// apart and refuses any beyond the first Max (0 means no limit). Retries
+// count as requests. It is safe for concurrent use and may be shared by
+// several clients to cap their combined load.
+type Throttle struct {
+	Next     http.RoundTripper // nil means http.DefaultTransport
+	Interval time.Duration
+	Max      int
+
+	mu    sync.Mutex
+	next  time.Time // earliest start of the next request
+	count int
+}
+
+// RoundTrip waits for the request's turn and sends it.
+func (t *Throttle) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if t.Max > 0 && t.count >= t.Max {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%w (--max-requests %d)", ErrBudget, t.Max)
+	}
+	t.count++
+	now := time.Now()
+	start := now
+	if t.next.After(now) {
+		start = t.next
+	}
+	t.next = start.Add(t.Interval)
+	t.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
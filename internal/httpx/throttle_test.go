@@ -0,0 +1,56 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  time.Duration
+		max       int
+		requests  int
+		wantCalls int
+		wantWaits int
+	}{
+		{name: "no limits", requests: 3, wantCalls: 3},
+		{name: "budget", max: 2, requests: 4, wantCalls: 2},
+		{name: "spaced", interval: time.Hour, requests: 3, wantCalls: 3, wantWaits: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			waits := noSleep(t)
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+			}))
+			defer srv.Close()
+
+			th := &Throttle{Next: srv.Client().Transport, Interval: tt.interval, Max: tt.max}
+			hc := &http.Client{Transport: th}
+			for i := 0; i < tt.requests; i++ {
+				req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+				resp, err := Do(hc, req, 3)
+				if err != nil {
+					if !errors.Is(err, ErrBudget) {
+						t.Fatalf("request %d: unexpected error: %v", i+1, err)
+					}
+					continue
+				}
+				resp.Body.Close()
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+			if len(*waits) != tt.wantWaits {
+				t.Errorf("expected %d waits, got %v", tt.wantWaits, *waits)
+			}
+		})
+	}
+}
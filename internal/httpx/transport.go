@@ -0,0 +1,83 @@
+package httpx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// TransportOptions configure how the service clients reach the network,
+// for sites behind a proxy or with a private CA.
+type TransportOptions struct {
+	// Proxy is used for every request. Empty means HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY from the environment, as Go's default.
+	Proxy string
+	// CAFile is a PEM bundle of CA certificates trusted in addition to
+	// the system roots.
+	CAFile string
+	// InsecureSkipVerify turns off certificate verification entirely.
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether o leaves Go's default transport as it is.
+func (o TransportOptions) IsZero() bool {
+	return o == TransportOptions{}
+}
+
+// ParseProxy parses a proxy URL. A bare host:port means an HTTP proxy.
+func ParseProxy(s string) (*url.URL, error) {
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q", s)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https or socks5", s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: no host", s)
+	}
+	return u, nil
+}
+
+// NewTransport returns a copy of http.DefaultTransport with o applied.
+func NewTransport(o TransportOptions) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if o.Proxy != "" {
+		u, err := ParseProxy(o.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if o.CAFile == "" && !o.InsecureSkipVerify {
+		return t, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: o.InsecureSkipVerify}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("ca_file: " + o.CAFile + ": no PEM certificates found")
+		}
+		cfg.RootCAs = pool
+	}
+	t.TLSClientConfig = cfg
+	return t, nil
+}
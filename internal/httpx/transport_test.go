@@ -0,0 +1,105 @@
+package httpx
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProxy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "http://proxy.corp:3128", want: "http://proxy.corp:3128"},
+		{in: "proxy.corp:3128", want: "http://proxy.corp:3128"},
+		{in: "socks5://127.0.0.1:1080", want: "socks5://127.0.0.1:1080"},
+		{in: "ftp://proxy.corp", wantErr: true},
+		{in: "http://", wantErr: true},
+	}
+	for _, tt := range tests {
+		u, err := ParseProxy(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseProxy(%q) = %v, want an error", tt.in, u)
+			}
+			continue
+		}
+		if err != nil || u.String() != tt.want {
+			t.Errorf("ParseProxy(%q) = %v, %v, want %s", tt.in, u, err, tt.want)
+		}
+	}
+}
+
+func TestNewTransportProxy(t *testing.T) {
+	var got string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.String() // proxies see the absolute URL
+	}))
+	defer proxy.Close()
+
+	tr, err := NewTransport(TransportOptions{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	resp, err := (&http.Client{Transport: tr}).Get("http://cal.internal.example/api/feeds")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if got != "http://cal.internal.example/api/feeds" {
+		t.Errorf("proxy saw %q", got)
+	}
+}
+
+func TestNewTransportTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	badFile := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(badFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		opts       TransportOptions
+		wantErr    bool
+		wantGetErr bool
+	}{
+		{name: "system roots only", opts: TransportOptions{}, wantGetErr: true},
+		{name: "ca file", opts: TransportOptions{CAFile: caFile}},
+		{name: "skip verify", opts: TransportOptions{InsecureSkipVerify: true}},
+		{name: "no certificates", opts: TransportOptions{CAFile: badFile}, wantErr: true},
+		{name: "missing file", opts: TransportOptions{CAFile: filepath.Join(t.TempDir(), "none.pem")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := NewTransport(tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewTransport: %v", err)
+			}
+			resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantGetErr {
+				t.Errorf("Get error = %v, want error %v", err, tt.wantGetErr)
+			}
+		})
+	}
+}
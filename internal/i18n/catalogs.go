@@ -0,0 +1,359 @@
+package i18n
+
+// en is the reference catalog. Every key used by pylon must be present here.
+var en = Catalog{
+	"feed.created":      "Created feed:",
+	"feed.none":         "No feeds.",
+	"feed.deleted":      "Feed deleted.",
+	"feed.rotated":      "Token rotated; the old subscribe URL no longer works.",
+	"feed.updated":      "Feed updated:",
+	"event.created":     "Created event:",
+	"event.updated":     "Updated event:",
+	"event.none":        "No events.",
+	"categories.none":   "No events have categories.",
+	"heatmap.empty":     "No events in %d.",
+	"heatmap.busiest":   "Busiest week: week of %s (%d events)",
+	"heatmap.gap":       "Longest gap: %s – %s (%d days)",
+	"notify.changed":    "✏️ **%s** (%s) was changed:",
+	"notify.cancelled":  "🚫 **%s** (%s) was cancelled.",
+	"notify.confirmed":  "✅ **%s** (%s) is back on.",
+	"history.none":      "No commands in the history.",
+	"scheduled.none":    "No scheduled events.",
+	"scheduled.created": "Scheduled event %s created: %s",
+	"bridge.exported":   "Discord scheduled events: %d created, %d updated, %d deleted, %d failed.",
+	"backup.written":    "Backed up %d feed(s) and %d event(s) to %s.",
+	"backup.restored":   "Restored: %d feed(s) created or updated, %d event(s) created, %d updated, %d failed.",
+	"event.deleted":     "Event deleted.",
+	"event.cancelled":   "Cancelled %q (sequence %d).",
+	"event.confirmed":   "Confirmed %q (sequence %d).",
+	"event.still_on":    "%q is already confirmed.",
+	"event.moved":       "Moved %q to feed %s as event %s.",
+	"event.copied":      "Copied %q to feed %s as event %s.",
+	"attach.refreshed":  "Refreshed %d attachment link(s).",
+	"attach.none":       "No attachment links to refresh.",
+	"patch.summary":     "Patched %d event(s), %d failed.",
+	"mirror.created":    "Mirrored to feed %s as event %s.",
+	"mirror.synced":     "Mirrors: %d checked, %d updated, %d deleted, %d failed.",
+	"bridge.imported":   "Discord events: %d created, %d updated, %d deleted, %d failed.",
+	"gcal.authorize":    "To let pylon read your Google Calendar, open %s and enter the code %s",
+	"gcal.imported":     "Google Calendar events: %d created, %d updated, %d deleted, %d failed.",
+	"plan.none":         "No changes.",
+	"plan.summary":      "Plan: %d to create, %d to update, %d to delete. Run again with --apply to make these changes.",
+	"subscribe.hint":    "To subscribe in your calendar app, use the webcal URL.",
+	"subscribe.google":  "For Google Calendar, use the https URL in 'Other calendars > From URL'.",
+	"subscribe.copied":  "Subscribe URL copied to the clipboard.",
+	"subscribers.none":  "No fetches recorded for this feed.",
+	"verify.ok":         "No problems found.",
+	"verify.problems":   "%d problem(s) found:",
+	"message.sent":      "Message sent.",
+	"message.sent_id":   "Message sent (ID %s).",
+	"message.none":      "No messages found.",
+	"react.added":       "Reacted with %s.",
+	"react.removed":     "Removed reaction %s.",
+	"discord.pinned":    "Pinned message %s.",
+	"discord.unpinned":  "Unpinned message %s.",
+	"discord.no_pins":   "No pinned messages.",
+	"thread.none":       "No active threads.",
+	"daemon.none":       "No job has run yet (jobs are pylon remind and pylon retention loops).",
+	"daemon.failing":    "pylon %s on %s has failed %d times in a row: %s",
+	"daemon.recovered":  "pylon %s on %s is working again after %d failures.",
+	"webhook.none":      "No webhooks in this channel.",
+	"webhook.saved":     "Created webhook %s (%s) and saved its URL as discord.webhook in %s.",
+	"webhook.deleted":   "Deleted webhook %s.",
+	"webhook.confirm":   "Delete webhook %s? Anything posting to its URL will stop working. [y/N]",
+	"webhook.stale":     "discord.webhook still points at the deleted webhook; set a new one with pylon discord webhook create --save.",
+	"guild.none":        "The bot is not in any guild.",
+	"slack.no_channels": "No channels visible to the bot.",
+	"archive.would":     "%s: would archive %d event(s)",
+	"archive.feed":      "%s: archived %d event(s) to %s",
+	"archive.dry_run":   "Dry run: %d event(s) before %s would be archived.",
+	"archive.confirm":   "Archive and delete %d event(s)? [y/N]",
+	"archive.summary":   "Archived %d event(s), %d failed.",
+	"archive.restored":  "Restored %d event(s), %d failed.",
+	"archive.skipped":   "Skipped %d archive(s) that could not be restored: %s",
+	"retention.would":   "Would remove %s",
+	"retention.remove":  "Removed %s",
+	"prune.none":        "No events started before %s.",
+	"prune.confirm":     "Delete %d event(s)? [y/N]",
+	"delete.aborted":    "Nothing deleted.",
+	"delete.needs_yes":  "stdin is not a terminal; pass --yes to delete without confirming",
+	"feed.confirm":      "Delete feed %q and %d event(s)? [y/N]",
+	"event.confirm":     "Delete event %q (%s)? [y/N]",
+	"pin.confirm":       "Unpin %q? [y/N]",
+	"prune.summary":     "Deleted %d event(s), %d failed.",
+	"import.summary":    "Imported %d event(s), %d failed.",
+	"import.dry_run":    "Dry run: %d event(s) would be imported.",
+	"agenda.none":       "Nothing scheduled in the next %d day(s).",
+	"agenda.today":      "Today, %s",
+	"agenda.tomorrow":   "Tomorrow, %s",
+	"agenda.deadline":   "⚠ DEADLINE: %s",
+	"agenda.all_day":    "all day",
+	"agenda.ongoing":    "ongoing",
+	"agenda.pinned":     "Pinned",
+	"agenda.until":      "until %s",
+	"date.day":          "%s %d %s",
+	"date.weekdays":     "Sun Mon Tue Wed Thu Fri Sat",
+	"date.months":       "Jan Feb Mar Apr May Jun Jul Aug Sep Oct Nov Dec",
+	"search.none":       "No events match %q.",
+	"pin.added":         "Pinned to %s: %s",
+	"pin.none":          "No pins.",
+	"pin.removed":       "Unpinned: %s",
+	"digest.summary":    "Digests: %d posted, %d failed.",
+	"doctor.ok":         "All checks passed.",
+	"doctor.failed":     "Some checks failed; see the hints above.",
+	"completion.wrote":  "Wrote the %s completion to %s.",
+	"completion.ok":     "Checked: a new %s shell loads it.",
+	"audit.summary":     "Checked %d entries: %d dead, %d could not be checked.",
+	"export.done":       "Exported %d message(s).",
+	"pick.prompt":       "Channel (1-%d):",
+	"pick.saved":        "Set %s = %s (#%s) in %s.",
+	"view.saved":        "Saved view %s = %s in %s.",
+	"view.deleted":      "Deleted view %s from %s.",
+	"view.confirm":      "Delete view %s (%s)? [y/N]",
+	"view.none":         "No views saved (see pylon view save).",
+	"reactors.none":     "Nobody has reacted with %s.",
+	"remind.start":      "⏰ %s starts in %s (%s)",
+	"remind.deadline":   "⏰ Deadline reached: %s (%s)",
+	"remind.location":   "📍 %s",
+	"remind.agenda":     "🗓️ %s (%s)",
+	"remind.no_agenda":  "No agenda yet. Add notes here.",
+	"remind.follow_up":  "📝 {summary} has ended. Any action items? Reply here.",
+}
+
+var es = Catalog{
+	"feed.created":      "Feed creado:",
+	"feed.none":         "No hay feeds.",
+	"feed.deleted":      "Feed eliminado.",
+	"feed.rotated":      "Token renovado; la URL de suscripción anterior ya no funciona.",
+	"feed.updated":      "Feed actualizado:",
+	"event.created":     "Evento creado:",
+	"event.updated":     "Evento actualizado:",
+	"event.none":        "No hay eventos.",
+	"categories.none":   "Ningún evento tiene categorías.",
+	"heatmap.empty":     "No hay eventos en %d.",
+	"heatmap.busiest":   "Semana más ocupada: semana del %s (%d eventos)",
+	"heatmap.gap":       "Hueco más largo: %s – %s (%d días)",
+	"notify.changed":    "✏️ **%s** (%s) ha cambiado:",
+	"notify.cancelled":  "🚫 **%s** (%s) se ha cancelado.",
+	"notify.confirmed":  "✅ **%s** (%s) vuelve a estar confirmado.",
+	"history.none":      "No hay comandos en el historial.",
+	"scheduled.none":    "No hay eventos programados.",
+	"scheduled.created": "Evento programado %s creado: %s",
+	"bridge.exported":   "Eventos programados de Discord: %d creados, %d actualizados, %d eliminados, %d con errores.",
+	"backup.written":    "Copia de seguridad de %d feed(s) y %d evento(s) en %s.",
+	"backup.restored":   "Restaurado: %d feed(s) creado(s) o actualizado(s), %d evento(s) creado(s), %d actualizado(s), %d fallido(s).",
+	"event.deleted":     "Evento eliminado.",
+	"event.cancelled":   "Cancelado %q (secuencia %d).",
+	"event.confirmed":   "Confirmado %q (secuencia %d).",
+	"event.still_on":    "%q ya está confirmado.",
+	"event.moved":       "%q movido al feed %s como evento %s.",
+	"event.copied":      "%q copiado al feed %s como evento %s.",
+	"attach.refreshed":  "Se renovaron %d enlace(s) de adjuntos.",
+	"attach.none":       "No hay enlaces de adjuntos que renovar.",
+	"patch.summary":     "%d evento(s) modificado(s), %d con error.",
+	"mirror.created":    "Reflejado en el feed %s como evento %s.",
+	"mirror.synced":     "Réplicas: %d revisadas, %d actualizadas, %d eliminadas, %d con errores.",
+	"bridge.imported":   "Eventos de Discord: %d creados, %d actualizados, %d eliminados, %d con errores.",
+	"gcal.authorize":    "Para que pylon lea tu Google Calendar, abre %s e introduce el código %s",
+	"gcal.imported":     "Eventos de Google Calendar: %d creados, %d actualizados, %d eliminados, %d con errores.",
+	"plan.none":         "Sin cambios.",
+	"plan.summary":      "Plan: %d para crear, %d para actualizar, %d para eliminar. Vuelva a ejecutar con --apply para aplicar los cambios.",
+	"subscribe.hint":    "Para suscribirte desde tu aplicación de calendario, usa la URL webcal.",
+	"subscribe.google":  "En Google Calendar, usa la URL https en 'Otros calendarios > Desde URL'.",
+	"subscribe.copied":  "URL de suscripción copiada al portapapeles.",
+	"subscribers.none":  "No hay descargas registradas para este feed.",
+	"verify.ok":         "No se encontraron problemas.",
+	"verify.problems":   "%d problema(s) encontrado(s):",
+	"message.sent":      "Mensaje enviado.",
+	"message.sent_id":   "Mensaje enviado (ID %s).",
+	"message.none":      "No se encontraron mensajes.",
+	"react.added":       "Reacción %s añadida.",
+	"react.removed":     "Reacción %s quitada.",
+	"discord.pinned":    "Mensaje %s fijado.",
+	"discord.unpinned":  "Mensaje %s desfijado.",
+	"discord.no_pins":   "No hay mensajes fijados.",
+	"thread.none":       "No hay hilos activos.",
+	"daemon.none":       "Ningún trabajo se ha ejecutado aún (los trabajos son los bucles de pylon remind y pylon retention).",
+	"daemon.failing":    "pylon %s en %s ha fallado %d veces seguidas: %s",
+	"daemon.recovered":  "pylon %s en %s vuelve a funcionar tras %d fallos.",
+	"webhook.none":      "No hay webhooks en este canal.",
+	"webhook.saved":     "Webhook %s (%s) creado y su URL guardada como discord.webhook en %s.",
+	"webhook.deleted":   "Webhook %s eliminado.",
+	"webhook.confirm":   "¿Eliminar el webhook %s? Lo que publique en su URL dejará de funcionar. [y/N]",
+	"webhook.stale":     "discord.webhook sigue apuntando al webhook eliminado; crea otro con pylon discord webhook create --save.",
+	"guild.none":        "El bot no está en ningún servidor.",
+	"slack.no_channels": "El bot no ve ningún canal.",
+	"archive.would":     "%s: se archivarían %d evento(s)",
+	"archive.feed":      "%s: %d evento(s) archivado(s) en %s",
+	"archive.dry_run":   "Simulación: se archivarían %d evento(s) anteriores a %s.",
+	"archive.confirm":   "¿Archivar y eliminar %d evento(s)? [y/N]",
+	"archive.summary":   "%d evento(s) archivado(s), %d fallido(s).",
+	"archive.restored":  "%d evento(s) restaurado(s), %d fallido(s).",
+	"archive.skipped":   "Se omitieron %d archivo(s) que no se pudieron restaurar: %s",
+	"retention.would":   "Se eliminaría %s",
+	"retention.remove":  "Eliminado %s",
+	"prune.none":        "Ningún evento comenzó antes de %s.",
+	"prune.confirm":     "¿Eliminar %d evento(s)? [y/N]",
+	"delete.aborted":    "No se eliminó nada.",
+	"delete.needs_yes":  "la entrada no es una terminal; usa --yes para eliminar sin confirmar",
+	"feed.confirm":      "¿Eliminar el calendario %q y %d evento(s)? [y/N]",
+	"event.confirm":     "¿Eliminar el evento %q (%s)? [y/N]",
+	"pin.confirm":       "¿Desfijar %q? [y/N]",
+	"prune.summary":     "%d evento(s) eliminado(s), %d fallido(s).",
+	"import.summary":    "%d evento(s) importado(s), %d fallido(s).",
+	"import.dry_run":    "Simulación: se importarían %d evento(s).",
+	"agenda.none":       "No hay nada programado en los próximos %d día(s).",
+	"agenda.today":      "Hoy, %s",
+	"agenda.tomorrow":   "Mañana, %s",
+	"agenda.deadline":   "⚠ FECHA LÍMITE: %s",
+	"agenda.all_day":    "todo el día",
+	"agenda.ongoing":    "en curso",
+	"agenda.pinned":     "Fijados",
+	"agenda.until":      "hasta %s",
+	"date.day":          "%s %d %s",
+	"date.weekdays":     "dom lun mar mié jue vie sáb",
+	"date.months":       "ene feb mar abr may jun jul ago sept oct nov dic",
+	"search.none":       "Ningún evento coincide con %q.",
+	"pin.added":         "Fijado en %s: %s",
+	"pin.none":          "No hay anuncios fijados.",
+	"pin.removed":       "Desfijado: %s",
+	"digest.summary":    "Resúmenes: %d publicados, %d con errores.",
+	"doctor.ok":         "Todas las comprobaciones pasaron.",
+	"doctor.failed":     "Algunas comprobaciones fallaron; consulta las sugerencias de arriba.",
+	"completion.wrote":  "Se escribió el autocompletado de %s en %s.",
+	"completion.ok":     "Comprobado: una nueva shell %s lo carga.",
+	"audit.summary":     "%d entradas revisadas: %d inservibles, %d sin verificar.",
+	"export.done":       "%d mensaje(s) exportado(s).",
+	"pick.prompt":       "Canal (1-%d):",
+	"pick.saved":        "%s = %s (#%s) guardado en %s.",
+	"view.saved":        "Vista %s = %s guardada en %s.",
+	"view.deleted":      "Vista %s eliminada de %s.",
+	"view.confirm":      "¿Eliminar la vista %s (%s)? [y/N]",
+	"view.none":         "No hay vistas guardadas (ver pylon view save).",
+	"reactors.none":     "Nadie ha reaccionado con %s.",
+	"remind.start":      "⏰ %s empieza en %s (%s)",
+	"remind.deadline":   "⏰ Plazo vencido: %s (%s)",
+	"remind.location":   "📍 %s",
+	"remind.agenda":     "🗓️ %s (%s)",
+	"remind.no_agenda":  "Aún no hay agenda. Añadid notas aquí.",
+	"remind.follow_up":  "📝 {summary} ha terminado. ¿Tareas pendientes? Responded aquí.",
+}
+
+var de = Catalog{
+	"feed.created":      "Feed erstellt:",
+	"feed.none":         "Keine Feeds.",
+	"feed.deleted":      "Feed gelöscht.",
+	"feed.rotated":      "Token erneuert; die alte Abo-URL funktioniert nicht mehr.",
+	"feed.updated":      "Feed aktualisiert:",
+	"event.created":     "Termin erstellt:",
+	"event.updated":     "Termin aktualisiert:",
+	"event.none":        "Keine Termine.",
+	"categories.none":   "Keine Termine mit Kategorien.",
+	"heatmap.empty":     "Keine Termine in %d.",
+	"heatmap.busiest":   "Vollste Woche: Woche vom %s (%d Termine)",
+	"heatmap.gap":       "Längste Lücke: %s – %s (%d Tage)",
+	"notify.changed":    "✏️ **%s** (%s) wurde geändert:",
+	"notify.cancelled":  "🚫 **%s** (%s) wurde abgesagt.",
+	"notify.confirmed":  "✅ **%s** (%s) findet wieder statt.",
+	"history.none":      "Keine Befehle im Verlauf.",
+	"scheduled.none":    "Keine geplanten Events.",
+	"scheduled.created": "Geplantes Event %s erstellt: %s",
+	"bridge.exported":   "Geplante Discord-Events: %d erstellt, %d aktualisiert, %d gelöscht, %d fehlgeschlagen.",
+	"backup.written":    "%d Feed(s) und %d Termin(e) nach %s gesichert.",
+	"backup.restored":   "Wiederhergestellt: %d Feed(s) angelegt oder aktualisiert, %d Termin(e) angelegt, %d aktualisiert, %d fehlgeschlagen.",
+	"event.deleted":     "Termin gelöscht.",
+	"event.cancelled":   "%q abgesagt (Sequenz %d).",
+	"event.confirmed":   "%q bestätigt (Sequenz %d).",
+	"event.still_on":    "%q ist bereits bestätigt.",
+	"event.moved":       "%[1]q als Termin %[3]s in Feed %[2]s verschoben.",
+	"event.copied":      "%[1]q als Termin %[3]s in Feed %[2]s kopiert.",
+	"attach.refreshed":  "%d Anhang-Link(s) erneuert.",
+	"attach.none":       "Keine Anhang-Links zu erneuern.",
+	"patch.summary":     "%d Termin(e) geändert, %d fehlgeschlagen.",
+	"mirror.created":    "In Feed %s als Termin %s gespiegelt.",
+	"mirror.synced":     "Spiegel: %d geprüft, %d aktualisiert, %d gelöscht, %d fehlgeschlagen.",
+	"bridge.imported":   "Discord-Events: %d erstellt, %d aktualisiert, %d gelöscht, %d fehlgeschlagen.",
+	"gcal.authorize":    "Damit pylon deinen Google Kalender lesen kann, öffne %s und gib den Code %s ein",
+	"gcal.imported":     "Google-Kalender-Termine: %d erstellt, %d aktualisiert, %d gelöscht, %d fehlgeschlagen.",
+	"plan.none":         "Keine Änderungen.",
+	"plan.summary":      "Plan: %d anlegen, %d aktualisieren, %d löschen. Mit --apply erneut ausführen, um die Änderungen vorzunehmen.",
+	"subscribe.hint":    "Zum Abonnieren in deiner Kalender-App die webcal-URL verwenden.",
+	"subscribe.google":  "Für Google Kalender die https-URL unter 'Weitere Kalender > Per URL' verwenden.",
+	"subscribe.copied":  "Abo-URL in die Zwischenablage kopiert.",
+	"subscribers.none":  "Für diesen Feed sind keine Abrufe verzeichnet.",
+	"verify.ok":         "Keine Probleme gefunden.",
+	"verify.problems":   "%d Problem(e) gefunden:",
+	"message.sent":      "Nachricht gesendet.",
+	"message.sent_id":   "Nachricht gesendet (ID %s).",
+	"message.none":      "Keine Nachrichten gefunden.",
+	"react.added":       "Mit %s reagiert.",
+	"react.removed":     "Reaktion %s entfernt.",
+	"discord.pinned":    "Nachricht %s angeheftet.",
+	"discord.unpinned":  "Nachricht %s nicht mehr angeheftet.",
+	"discord.no_pins":   "Keine angehefteten Nachrichten.",
+	"thread.none":       "Keine aktiven Threads.",
+	"daemon.none":       "Noch kein Job gelaufen (Jobs sind die Schleifen von pylon remind und pylon retention).",
+	"daemon.failing":    "pylon %s auf %s ist %d-mal in Folge fehlgeschlagen: %s",
+	"daemon.recovered":  "pylon %s auf %s läuft nach %d Fehlschlägen wieder.",
+	"webhook.none":      "Keine Webhooks in diesem Kanal.",
+	"webhook.saved":     "Webhook %s (%s) erstellt und die URL als discord.webhook in %s gespeichert.",
+	"webhook.deleted":   "Webhook %s gelöscht.",
+	"webhook.confirm":   "Webhook %s löschen? Was an seine URL sendet, funktioniert dann nicht mehr. [y/N]",
+	"webhook.stale":     "discord.webhook zeigt noch auf den gelöschten Webhook; neuen mit pylon discord webhook create --save anlegen.",
+	"guild.none":        "Der Bot ist auf keinem Server.",
+	"slack.no_channels": "Der Bot sieht keine Kanäle.",
+	"archive.would":     "%s: %d Termin(e) würden archiviert",
+	"archive.feed":      "%s: %d Termin(e) nach %s archiviert",
+	"archive.dry_run":   "Probelauf: %d Termin(e) vor %s würden archiviert.",
+	"archive.confirm":   "%d Termin(e) archivieren und löschen? [y/N]",
+	"archive.summary":   "%d Termin(e) archiviert, %d fehlgeschlagen.",
+	"archive.restored":  "%d Termin(e) wiederhergestellt, %d fehlgeschlagen.",
+	"archive.skipped":   "%d Archiv(e) übersprungen, die nicht wiederhergestellt werden konnten: %s",
+	"retention.would":   "Würde %s entfernen",
+	"retention.remove":  "%s entfernt",
+	"prune.none":        "Keine Termine vor %s.",
+	"prune.confirm":     "%d Termin(e) löschen? [y/N]",
+	"delete.aborted":    "Nichts gelöscht.",
+	"delete.needs_yes":  "die Eingabe ist kein Terminal; --yes löscht ohne Nachfrage",
+	"feed.confirm":      "Kalender %q und %d Termin(e) löschen? [y/N]",
+	"event.confirm":     "Termin %q (%s) löschen? [y/N]",
+	"pin.confirm":       "%q lösen? [y/N]",
+	"prune.summary":     "%d Termin(e) gelöscht, %d fehlgeschlagen.",
+	"import.summary":    "%d Termin(e) importiert, %d fehlgeschlagen.",
+	"import.dry_run":    "Probelauf: %d Termin(e) würden importiert.",
+	"agenda.none":       "Nichts geplant in den nächsten %d Tag(en).",
+	"agenda.today":      "Heute, %s",
+	"agenda.tomorrow":   "Morgen, %s",
+	"agenda.deadline":   "⚠ FRIST: %s",
+	"agenda.all_day":    "ganztägig",
+	"agenda.ongoing":    "läuft",
+	"agenda.pinned":     "Angeheftet",
+	"agenda.until":      "bis %s",
+	"date.day":          "%s %d. %s",
+	"date.weekdays":     "So. Mo. Di. Mi. Do. Fr. Sa.",
+	"date.months":       "Jan. Feb. März Apr. Mai Juni Juli Aug. Sept. Okt. Nov. Dez.",
+	"search.none":       "Keine Termine passen zu %q.",
+	"pin.added":         "Angeheftet an %s: %s",
+	"pin.none":          "Keine angehefteten Hinweise.",
+	"pin.removed":       "Gelöst: %s",
+	"digest.summary":    "Zusammenfassungen: %d gesendet, %d fehlgeschlagen.",
+	"doctor.ok":         "Alle Prüfungen bestanden.",
+	"doctor.failed":     "Einige Prüfungen sind fehlgeschlagen; siehe die Hinweise oben.",
+	"completion.wrote":  "%s-Vervollständigung nach %s geschrieben.",
+	"completion.ok":     "Geprüft: eine neue %s-Shell lädt sie.",
+	"audit.summary":     "%d Einträge geprüft: %d ungültig, %d nicht prüfbar.",
+	"export.done":       "%d Nachricht(en) exportiert.",
+	"pick.prompt":       "Kanal (1-%d):",
+	"pick.saved":        "%s = %s (#%s) in %s gespeichert.",
+	"view.saved":        "Ansicht %s = %s in %s gespeichert.",
+	"view.deleted":      "Ansicht %s aus %s entfernt.",
+	"view.confirm":      "Ansicht %s (%s) löschen? [y/N]",
+	"view.none":         "Keine Ansichten gespeichert (siehe pylon view save).",
+	"reactors.none":     "Niemand hat mit %s reagiert.",
+	"remind.start":      "⏰ %s beginnt in %s (%s)",
+	"remind.deadline":   "⏰ Frist erreicht: %s (%s)",
+	"remind.location":   "📍 %s",
+	"remind.agenda":     "🗓️ %s (%s)",
+	"remind.no_agenda":  "Noch keine Agenda. Notizen bitte hier.",
+	"remind.follow_up":  "📝 {summary} ist vorbei. Gibt es Aufgaben? Bitte hier antworten.",
+}
@@ -0,0 +1,31 @@
+package i18n
+
+import (
+	"strings"
+	"time"
+)
+
+// Weekday returns the short name of d in the active language.
+func Weekday(d time.Weekday) string {
+	return nth(T("date.weekdays"), int(d))
+}
+
+// Month returns the short name of m in the active language.
+func Month(m time.Month) string {
+	return nth(T("date.months"), int(m)-1)
+}
+
+// Day formats the date of t with its weekday, as "Mon 2 Jan" does in
+// English.
+func Day(t time.Time) string {
+	return T("date.day", Weekday(t.Weekday()), t.Day(), Month(t.Month()))
+}
+
+// nth returns the i-th of the space-separated names in list.
+func nth(list string, i int) string {
+	names := strings.Fields(list)
+	if i < 0 || i >= len(names) {
+		return ""
+	}
+	return names[i]
+}
@@ -0,0 +1,73 @@
+// Package i18n translates pylon's user-facing messages.
+//
+// Messages are looked up by key in the active language's catalog, falling
+// back to English and finally to the key itself, so a missing translation
+// never hides output. Values are fmt format strings.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Catalog maps message keys to format strings.
+type Catalog map[string]string
+
+var catalogs = map[string]Catalog{
+	"en": en,
+	"es": es,
+	"de": de,
+}
+
+var active = en
+
+// SetLanguage selects the catalog used by T. It accepts bare codes ("es") as
+// well as locale strings ("es_ES.UTF-8", "de-AT"). An empty string selects
+// English.
+func SetLanguage(lang string) error {
+	code := Normalize(lang)
+	if code == "" {
+		active = en
+		return nil
+	}
+	c, ok := catalogs[code]
+	if !ok {
+		return fmt.Errorf("unsupported language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	active = c
+	return nil
+}
+
+// Normalize reduces a locale string to its lowercase language code.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// Languages returns the available language codes, sorted.
+func Languages() []string {
+	codes := make([]string, 0, len(catalogs))
+	for code := range catalogs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// T returns the translation of key formatted with args.
+func T(key string, args ...any) string {
+	format, ok := active[key]
+	if !ok {
+		if format, ok = en[key]; !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestT(t *testing.T) {
+	t.Cleanup(func() { _ = SetLanguage("") })
+
+	tests := []struct {
+		name string
+		lang string
+		key  string
+		args []any
+		want string
+	}{
+		{name: "english", lang: "en", key: "feed.deleted", want: "Feed deleted."},
+		{name: "spanish", lang: "es", key: "feed.deleted", want: "Feed eliminado."},
+		{name: "german with args", lang: "de", key: "message.sent_id", args: []any{"42"}, want: "Nachricht gesendet (ID 42)."},
+		{name: "locale string", lang: "es_ES.UTF-8", key: "event.none", want: "No hay eventos."},
+		{name: "unknown key falls back to key", lang: "de", key: "no.such.key", want: "no.such.key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetLanguage(tt.lang); err != nil {
+				t.Fatalf("SetLanguage(%q): %v", tt.lang, err)
+			}
+			if got := T(tt.key, tt.args...); got != tt.want {
+				t.Errorf("T(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbackToEnglish(t *testing.T) {
+	t.Cleanup(func() { delete(catalogs, "xx"); _ = SetLanguage("") })
+
+	catalogs["xx"] = Catalog{}
+	if err := SetLanguage("xx"); err != nil {
+		t.Fatalf("SetLanguage: %v", err)
+	}
+	if got := T("feed.none"); got != "No feeds." {
+		t.Errorf("expected English fallback, got %q", got)
+	}
+}
+
+func TestSetLanguageUnsupported(t *testing.T) {
+	err := SetLanguage("klingon")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "en") {
+		t.Errorf("expected available languages in error, got %q", err)
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for code, c := range catalogs {
+		for key := range en {
+			if _, ok := c[key]; !ok {
+				t.Errorf("%s catalog missing key %q", code, key)
+			}
+		}
+		for key := range c {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s catalog has key %q not in en", code, key)
+			}
+		}
+	}
+}
+
+func TestDay(t *testing.T) {
+	t.Cleanup(func() { _ = SetLanguage("") })
+
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for lang, want := range map[string]string{
+		"en": "Mon 2 Mar",
+		"es": "lun 2 mar",
+		"de": "Mo. 2. März",
+	} {
+		if err := SetLanguage(lang); err != nil {
+			t.Fatal(err)
+		}
+		if got := Day(date); got != want {
+			t.Errorf("%s: Day = %q, want %q", lang, got, want)
+		}
+	}
+	for code, c := range catalogs {
+		if n := len(strings.Fields(c["date.weekdays"])); n != 7 {
+			t.Errorf("%s catalog has %d weekdays", code, n)
+		}
+		if n := len(strings.Fields(c["date.months"])); n != 12 {
+			t.Errorf("%s catalog has %d months", code, n)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"":            "",
+		"EN":          "en",
+		"de-AT":       "de",
+		"es_MX.UTF-8": "es",
+		" fr ":        "fr",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package ics
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+// CreateRequest converts e into a request for creating it in feedID, the
+// inverse of FromEvent.
+//
+// Alarms relative to the event become its alarm triggers, which apply to
+// every occurrence; those relative to the end are moved to the start.
+// pylon events carry a single absolute alarm, the deadline, so the
+// earliest alarm at a fixed time becomes the deadline, and extra counts
+// the ones beyond it that pylon cannot represent.
+func (e Event) CreateRequest(feedID string) (req *cal.CreateEventRequest, extra int) {
+	req = &cal.CreateEventRequest{
+		FeedID:      feedID,
+		Summary:     e.Summary,
+		Description: e.Description,
+		Location:    e.Location,
+		URL:         e.URL,
+		Start:       e.Start.Format(time.RFC3339),
+		AllDay:      e.AllDay,
+		Status:      e.Status,
+		Categories:  e.Categories,
+		RRule:       e.RRule,
+		Attachments: e.Attachments,
+	}
+	if e.End != nil {
+		req.End = e.End.Format(time.RFC3339)
+	}
+	for _, t := range e.ExDates {
+		req.ExDates = append(req.ExDates, t.Format(time.RFC3339))
+	}
+	var deadline *time.Time
+	for _, a := range e.Alarms {
+		switch {
+		case a.At != nil:
+			if deadline != nil {
+				extra++
+			}
+			if deadline == nil || a.At.Before(*deadline) {
+				deadline = a.At
+			}
+		case a.Related == "END" && e.End != nil:
+			req.Alarms = append(req.Alarms, FormatDuration(e.End.Sub(e.Start)+a.Trigger))
+		default:
+			req.Alarms = append(req.Alarms, FormatDuration(a.Trigger))
+		}
+	}
+	if deadline != nil {
+		req.Deadline = deadline.Format(time.RFC3339)
+	}
+	return req, extra
+}
+
+// FromEvent converts a cal event into a VEVENT, the inverse of
+// CreateRequest: its deadline becomes an absolute alarm, and each of its
+// alarm triggers a relative one. Triggers that don't parse are dropped.
+func FromEvent(e cal.Event) Event {
+	out := Event{
+		UID:         e.ICalUID(),
+		Summary:     e.Summary,
+		Description: e.Description,
+		Location:    e.Location,
+		URL:         e.URL,
+		Status:      e.Status,
+		Categories:  e.Categories,
+		Start:       e.Start,
+		End:         e.End,
+		AllDay:      e.AllDay,
+		RRule:       e.RRule,
+		ExDates:     e.ExDates,
+		Attachments: e.Attachments,
+		Stamp:       e.UpdatedAt,
+		Sequence:    e.Sequence,
+	}
+	if e.Deadline != nil {
+		out.Alarms = append(out.Alarms, Alarm{Action: "DISPLAY", Description: "Deadline: " + e.Summary, At: e.Deadline})
+	}
+	for _, trigger := range e.Alarms {
+		if d, err := ParseDuration(trigger); err == nil {
+			out.Alarms = append(out.Alarms, Alarm{Action: "DISPLAY", Trigger: d, Related: "START"})
+		}
+	}
+	return out
+}
+
+// FromFeed returns the calendar a feed publishes: its events, named and
+// presented as the feed's settings say.
+func FromFeed(f cal.Feed, events []cal.Event) *Calendar {
+	c := &Calendar{Name: cmp.Or(f.CalendarName, f.Name), Method: "PUBLISH", ProdID: f.ProdID}
+	if d, err := ParseDuration(f.RefreshInterval); err == nil && d > 0 {
+		c.RefreshInterval = d
+	}
+	for _, e := range events {
+		ev := FromEvent(e)
+		if f.OmitAlarms {
+			ev.Alarms = nil
+		}
+		c.Events = append(c.Events, ev)
+	}
+	return c
+}
+
+// DropCancelled removes c's cancelled events. Subscribers then keep any
+// copy they already have, so it suits one-off exports better than feeds.
+func (c *Calendar) DropCancelled() {
+	c.Events = slices.DeleteFunc(c.Events, func(e Event) bool {
+		return strings.EqualFold(e.Status, "cancelled")
+	})
+}
+
+// FirstAlarm returns the earliest time any of e's alarms fires.
+func (e Event) FirstAlarm() (time.Time, bool) {
+	var first time.Time
+	for _, a := range e.Alarms {
+		if t := a.Time(e); first.IsZero() || t.Before(first) {
+			first = t
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// Redact returns e as a free/busy entry: its times, duration and recurrence
+// are kept and everything else is dropped, with "Busy" as the summary. The
+// UID is replaced by a hash of it, so it no longer gives away external IDs
+// but stays stable across exports and calendar apps update the entry in
+// place.
+func Redact(e Event) Event {
+	sum := sha256.Sum256([]byte(e.UID))
+	return Event{
+		UID:      hex.EncodeToString(sum[:16]) + "@busy",
+		Summary:  "Busy",
+		Status:   e.Status,
+		Start:    e.Start,
+		End:      e.End,
+		AllDay:   e.AllDay,
+		RRule:    e.RRule,
+		ExDates:  e.ExDates,
+		Stamp:    e.Stamp,
+		Sequence: e.Sequence,
+	}
+}
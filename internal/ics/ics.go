@@ -0,0 +1,465 @@
+// Package ics reads and writes iCalendar (RFC 5545) data: the events of a
+// calendar and their alarms. It covers what pylon imports and what
+// pylon cal serve publishes; recurrence rules are kept verbatim for package
+// recur, and free/busy components are ignored.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// Calendar is a parsed VCALENDAR.
+type Calendar struct {
+	Name   string // X-WR-CALNAME, if present
+	Method string // METHOD, e.g. PUBLISH; empty for a plain calendar file
+	ProdID string // PRODID; Write uses pylon's when empty
+	// RefreshInterval is how often subscribers should poll, written as
+	// REFRESH-INTERVAL and X-PUBLISHED-TTL; zero leaves it to them.
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is a VEVENT.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string
+	Categories  string // comma-separated
+	Start       time.Time
+	End         *time.Time
+	AllDay      bool
+	RRule       string      // raw RRULE value, see package recur
+	ExDates     []time.Time // EXDATE exceptions to RRule
+	Alarms      []Alarm
+	Attachments []string  // ATTACH URIs
+	Stamp       time.Time // DTSTAMP, when the event was last changed
+	// Sequence is the revision number calendar apps use to tell a newer
+	// copy of the event from a stale one. It goes up on every significant
+	// change, including cancellation.
+	Sequence int
+}
+
+// Alarm is a VALARM. Its trigger is either relative to the event start (or
+// end, when Related is "END") or an absolute time.
+type Alarm struct {
+	Action      string // DISPLAY, AUDIO, EMAIL
+	Description string
+	Trigger     time.Duration // offset, negative for before
+	Related     string        // START or END
+	At          *time.Time    // absolute trigger; overrides Trigger
+}
+
+// Time returns when the alarm fires for e. Alarms relative to the end of an
+// event without one are relative to its start.
+func (a Alarm) Time(e Event) time.Time {
+	if a.At != nil {
+		return *a.At
+	}
+	base := e.Start
+	if a.Related == "END" && e.End != nil {
+		base = *e.End
+	}
+	return timeutil.AddNominal(base, a.Trigger)
+}
+
+// property is one content line: NAME;PARAM=VALUE:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads a calendar. Unknown properties and components are skipped;
+// malformed required values (dates, durations) are errors that name the
+// line.
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{}
+	var stack []string
+	var ev *Event
+	var alarm *Alarm
+	var duration *time.Duration
+
+	for _, l := range lines {
+		p, ok := parseLine(l.text)
+		if !ok {
+			continue
+		}
+		fail := func(err error) (*Calendar, error) {
+			return nil, fmt.Errorf("line %d: %s: %w", l.num, p.name, err)
+		}
+
+		switch p.name {
+		case "BEGIN":
+			comp := strings.ToUpper(p.value)
+			stack = append(stack, comp)
+			switch {
+			case comp == "VEVENT" && ev == nil:
+				ev = &Event{}
+				duration = nil
+			case comp == "VALARM" && ev != nil:
+				alarm = &Alarm{Related: "START"}
+			}
+			continue
+		case "END":
+			comp := strings.ToUpper(p.value)
+			if len(stack) == 0 || stack[len(stack)-1] != comp {
+				return nil, fmt.Errorf("line %d: END:%s without matching BEGIN", l.num, comp)
+			}
+			stack = stack[:len(stack)-1]
+			switch {
+			case comp == "VALARM" && alarm != nil:
+				ev.Alarms = append(ev.Alarms, *alarm)
+				alarm = nil
+			case comp == "VEVENT" && ev != nil:
+				if ev.Start.IsZero() {
+					return nil, fmt.Errorf("line %d: event %q has no DTSTART", l.num, ev.Summary)
+				}
+				if ev.End == nil && duration != nil {
+					end := timeutil.AddNominal(ev.Start, *duration)
+					ev.End = &end
+				}
+				cal.Events = append(cal.Events, *ev)
+				ev = nil
+			}
+			continue
+		}
+
+		if len(stack) == 0 {
+			continue
+		}
+		switch top := stack[len(stack)-1]; {
+		case top == "VCALENDAR":
+			switch p.name {
+			case "X-WR-CALNAME":
+				cal.Name = unescape(p.value)
+			case "METHOD":
+				cal.Method = strings.ToUpper(p.value)
+			case "PRODID":
+				cal.ProdID = p.value
+			case "REFRESH-INTERVAL", "X-PUBLISHED-TTL":
+				d, err := ParseDuration(p.value)
+				if err != nil {
+					return fail(err)
+				}
+				cal.RefreshInterval = d
+			}
+
+		case top == "VALARM" && alarm != nil:
+			switch p.name {
+			case "ACTION":
+				alarm.Action = strings.ToUpper(p.value)
+			case "DESCRIPTION":
+				alarm.Description = unescape(p.value)
+			case "TRIGGER":
+				if p.params["VALUE"] == "DATE-TIME" {
+					t, _, err := parseTime(p)
+					if err != nil {
+						return fail(err)
+					}
+					alarm.At = &t
+					break
+				}
+				d, err := ParseDuration(p.value)
+				if err != nil {
+					return fail(err)
+				}
+				alarm.Trigger = d
+				if strings.EqualFold(p.params["RELATED"], "END") {
+					alarm.Related = "END"
+				}
+			}
+
+		case top == "VEVENT" && ev != nil:
+			switch p.name {
+			case "UID":
+				ev.UID = p.value
+			case "SUMMARY":
+				ev.Summary = unescape(p.value)
+			case "DESCRIPTION":
+				ev.Description = unescape(p.value)
+			case "LOCATION":
+				ev.Location = unescape(p.value)
+			case "URL":
+				ev.URL = p.value
+			case "ATTACH":
+				// Inline (base64) attachments are too big to carry around.
+				if p.params["VALUE"] != "BINARY" {
+					ev.Attachments = append(ev.Attachments, p.value)
+				}
+			case "STATUS":
+				ev.Status = strings.ToUpper(p.value)
+			case "CATEGORIES":
+				cats := unescape(p.value)
+				if ev.Categories != "" {
+					cats = ev.Categories + "," + cats
+				}
+				ev.Categories = cats
+			case "DTSTART":
+				t, allDay, err := parseTime(p)
+				if err != nil {
+					return fail(err)
+				}
+				ev.Start, ev.AllDay = t, allDay
+			case "DTEND":
+				t, _, err := parseTime(p)
+				if err != nil {
+					return fail(err)
+				}
+				ev.End = &t
+			case "DURATION":
+				d, err := ParseDuration(p.value)
+				if err != nil {
+					return fail(err)
+				}
+				duration = &d
+			case "DTSTAMP":
+				t, _, err := parseTime(p)
+				if err != nil {
+					return fail(err)
+				}
+				ev.Stamp = t
+			case "SEQUENCE":
+				n, err := strconv.Atoi(p.value)
+				if err != nil || n < 0 {
+					return fail(fmt.Errorf("invalid SEQUENCE %q", p.value))
+				}
+				ev.Sequence = n
+			case "RRULE":
+				ev.RRule = p.value
+			case "EXDATE":
+				for _, v := range strings.Split(p.value, ",") {
+					one := p
+					one.value = v
+					t, _, err := parseTime(one)
+					if err != nil {
+						return fail(err)
+					}
+					ev.ExDates = append(ev.ExDates, t)
+				}
+			}
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("unterminated %s", stack[len(stack)-1])
+	}
+	return cal, nil
+}
+
+type line struct {
+	num  int
+	text string
+}
+
+// unfold joins folded content lines (continuations start with a space or
+// tab), remembering each logical line's first physical line number.
+func unfold(r io.Reader) ([]line, error) {
+	var out []line
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	n := 0
+	for sc.Scan() {
+		n++
+		text := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(out) > 0 {
+			out[len(out)-1].text += text[1:]
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		out = append(out, line{num: n, text: text})
+	}
+	return out, sc.Err()
+}
+
+// parseLine splits a content line into name, parameters and value. Quoted
+// parameter values may contain ':' and ';'.
+func parseLine(s string) (property, bool) {
+	inQuote := false
+	colon := -1
+	for i, r := range s {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, false
+	}
+
+	head, value := s[:colon], s[colon+1:]
+	parts := splitParams(head)
+	p := property{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: value}
+	for _, kv := range parts[1:] {
+		k, v, _ := strings.Cut(kv, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, true
+}
+
+func splitParams(s string) []string {
+	var parts []string
+	inQuote := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case r == ';' && !inQuote:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescape decodes TEXT values (\n, \, \; \\).
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				sb.WriteByte('\n')
+			default:
+				sb.WriteByte(s[i])
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// parseTime parses a DATE or DATE-TIME property value. Floating times and
+// unknown TZIDs are interpreted in the local zone. allDay reports a DATE.
+func parseTime(p property) (t time.Time, allDay bool, err error) {
+	v := p.value
+	if p.params["VALUE"] == "DATE" || len(v) == 8 {
+		t, err = time.ParseInLocation("20060102", v, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err = time.Parse("20060102T150405Z", v)
+		return t, false, err
+	}
+	loc := time.Local
+	if tzid := p.params["TZID"]; tzid != "" {
+		if l, lerr := time.LoadLocation(tzid); lerr == nil {
+			loc = l
+		}
+	}
+	t, err = time.ParseInLocation("20060102T150405", v, loc)
+	return t, false, err
+}
+
+// FormatDuration formats d as an RFC 5545 duration, the inverse of
+// ParseDuration: whole days as "P1D", anything else in hours, minutes and
+// seconds ("-PT1H30M").
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d == 0 {
+		return "PT0S"
+	}
+	day := 24 * time.Hour
+	if d%day == 0 {
+		return fmt.Sprintf("%sP%dD", sign, d/day)
+	}
+	var sb strings.Builder
+	sb.WriteString(sign + "PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&sb, "%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		fmt.Fprintf(&sb, "%dM", m)
+	}
+	if s := d % time.Minute / time.Second; s > 0 {
+		fmt.Fprintf(&sb, "%dS", s)
+	}
+	return sb.String()
+}
+
+// ParseDuration parses an RFC 5545 duration such as "PT15M", "-P1D" or
+// "P1DT2H30M". Weeks ("P2W") are accepted too. Days and weeks are nominal in
+// RFC 5545, so add the result with timeutil.AddNominal rather than Add.
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := 0
+	digits := false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num = num*10 + int(r-'0')
+			digits = true
+			continue
+		case r == 'T' && !inTime && !digits:
+			inTime = true
+			continue
+		}
+		if !digits {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		var unit time.Duration
+		switch {
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		d += time.Duration(num) * unit
+		num, digits = 0, false
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid duration %q", orig)
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,239 @@
+package ics
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-CALNAME:Team\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Europe/Berlin\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup-1@example.com\r\n" +
+	"SUMMARY:Standup\\, daily\r\n" +
+	"DESCRIPTION:Line one\\nLine two that is folded across\r\n" +
+	"  two physical lines\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20260302T090000\r\n" +
+	"DURATION:PT15M\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,WE\r\n" +
+	"EXDATE;TZID=Europe/Berlin:20260304T090000,20260309T090000\r\n" +
+	"CATEGORIES:meeting,team\r\n" +
+	"STATUS:confirmed\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"DESCRIPTION:Standup soon\r\n" +
+	"TRIGGER:-PT10M\r\n" +
+	"END:VALARM\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:AUDIO\r\n" +
+	"TRIGGER;RELATED=END:PT0S\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:launch\r\n" +
+	"SUMMARY:Launch\r\n" +
+	"DTSTART;VALUE=DATE:20260401\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"TRIGGER;VALUE=DATE-TIME:20260331T120000Z\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	c, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if c.Name != "Team" {
+		t.Errorf("Name = %q", c.Name)
+	}
+	if len(c.Events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(c.Events))
+	}
+
+	e := c.Events[0]
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if want := time.Date(2026, 3, 2, 9, 0, 0, 0, berlin); !e.Start.Equal(want) {
+		t.Errorf("Start = %v, want %v", e.Start, want)
+	}
+	if e.End == nil || e.End.Sub(e.Start) != 15*time.Minute {
+		t.Errorf("End = %v, want start+15m from DURATION", e.End)
+	}
+	if e.Summary != "Standup, daily" {
+		t.Errorf("Summary = %q", e.Summary)
+	}
+	if e.Description != "Line one\nLine two that is folded across two physical lines" {
+		t.Errorf("Description = %q", e.Description)
+	}
+	if e.Status != "CONFIRMED" || e.Categories != "meeting,team" || e.UID != "standup-1@example.com" {
+		t.Errorf("unexpected fields %+v", e)
+	}
+	if e.RRule != "FREQ=WEEKLY;BYDAY=MO,WE" {
+		t.Errorf("RRule = %q", e.RRule)
+	}
+	if len(e.ExDates) != 2 || !e.ExDates[1].Equal(time.Date(2026, 3, 9, 9, 0, 0, 0, berlin)) {
+		t.Errorf("ExDates = %v", e.ExDates)
+	}
+	if len(e.Alarms) != 2 {
+		t.Fatalf("expected 2 alarms, got %d", len(e.Alarms))
+	}
+	if a := e.Alarms[0]; a.Action != "DISPLAY" || a.Trigger != -10*time.Minute || a.Description != "Standup soon" {
+		t.Errorf("unexpected first alarm %+v", a)
+	}
+	if got := e.Alarms[1].Time(e); !got.Equal(*e.End) {
+		t.Errorf("END-related alarm fires at %v, want %v", got, *e.End)
+	}
+
+	launch := c.Events[1]
+	if !launch.AllDay {
+		t.Error("expected all-day event for VALUE=DATE")
+	}
+	if a := launch.Alarms[0]; a.At == nil || !a.At.Equal(time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected absolute trigger, got %+v", a)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"bad date":       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2026-03-02\nEND:VEVENT\nEND:VCALENDAR\n",
+		"no start":       "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\nEND:VCALENDAR\n",
+		"bad trigger":    "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260302T090000Z\nBEGIN:VALARM\nTRIGGER:soon\nEND:VALARM\nEND:VEVENT\nEND:VCALENDAR\n",
+		"mismatched end": "BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n",
+		"unterminated":   "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20260302T090000Z\n",
+	}
+	for name, in := range tests {
+		if _, err := Parse(strings.NewReader(in)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "PT15M", want: 15 * time.Minute},
+		{in: "-PT1H30M", want: -90 * time.Minute},
+		{in: "+P1D", want: 24 * time.Hour},
+		{in: "P1DT2H", want: 26 * time.Hour},
+		{in: "P2W", want: 14 * 24 * time.Hour},
+		{in: "PT0S", want: 0},
+		{in: "15M", wantErr: true},
+		{in: "PT", wantErr: true},
+		{in: "P1H", wantErr: true},
+		{in: "PT5", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q): expected error", tt.in)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{-15 * time.Minute, "-PT15M"},
+		{-90 * time.Minute, "-PT1H30M"},
+		{-48 * time.Hour, "-P2D"},
+		{26 * time.Hour, "PT26H"},
+		{90 * time.Second, "PT1M30S"},
+		{0, "PT0S"},
+	}
+	for _, tt := range tests {
+		got := FormatDuration(tt.in)
+		if got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+		if back, err := ParseDuration(got); err != nil || back != tt.in {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", got, back, err, tt.in)
+		}
+	}
+}
+
+func TestCreateRequest(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+	early, late := start.Add(-48*time.Hour), start.Add(-24*time.Hour)
+	e := Event{
+		Summary: "Standup",
+		Start:   start,
+		End:     &end,
+		Alarms: []Alarm{
+			{Trigger: -5 * time.Minute, Related: "START"},
+			{Trigger: -time.Hour, Related: "START"},
+			{Trigger: 0, Related: "END"},
+			{At: &late},
+			{At: &early},
+		},
+	}
+	req, extra := e.CreateRequest("feed-1")
+	if req.FeedID != "feed-1" || req.Summary != "Standup" || req.End != end.Format(time.RFC3339) {
+		t.Errorf("unexpected request %+v", req)
+	}
+	if want := []string{"-PT5M", "-PT1H", "PT30M"}; !slices.Equal(req.Alarms, want) {
+		t.Errorf("Alarms = %q, want %q", req.Alarms, want)
+	}
+	if want := early.Format(time.RFC3339); req.Deadline != want {
+		t.Errorf("Deadline = %q, want earliest absolute alarm %q", req.Deadline, want)
+	}
+	if extra != 1 {
+		t.Errorf("extra = %d, want 1", extra)
+	}
+
+	req, extra = Event{Summary: "Quiet", Start: start}.CreateRequest("feed-1")
+	if req.Deadline != "" || extra != 0 {
+		t.Errorf("expected no deadline without alarms, got %q (%d)", req.Deadline, extra)
+	}
+}
+
+func TestParseAcrossDST(t *testing.T) {
+	const src = "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:offsite\r\n" +
+		"SUMMARY:Offsite\r\n" +
+		"DTSTART;TZID=America/New_York:20261031T090000\r\n" +
+		"DURATION:P1D\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"TRIGGER;RELATED=END:-P1D\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	c, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := c.Events[0]
+	if want := time.Date(2026, 10, 31, 9, 0, 0, 0, ny); !e.Start.Equal(want) {
+		t.Errorf("start = %v, want %v", e.Start, want)
+	}
+	// Clocks fall back overnight, so "P1D" is 25 hours here.
+	if want := time.Date(2026, 11, 1, 9, 0, 0, 0, ny); e.End == nil || !e.End.Equal(want) {
+		t.Errorf("end = %v, want %v", e.End, want)
+	}
+	if got := e.Alarms[0].Time(e); !got.Equal(e.Start) {
+		t.Errorf("alarm = %v, want %v", got, e.Start)
+	}
+}
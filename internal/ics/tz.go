@@ -0,0 +1,119 @@
+package ics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// zoneName returns the IANA name of loc, for a TZID, or "" if it has none:
+// UTC and fixed offsets are written in UTC. time.Local is named from $TZ or
+// the /etc/localtime link.
+func zoneName(loc *time.Location) string {
+	name := loc.String()
+	if loc == time.Local {
+		name = localZoneName()
+	}
+	if name == "" || name == "UTC" || name == "Local" {
+		return ""
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+func localZoneName() string {
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		return strings.TrimPrefix(tz, ":")
+	}
+	path, err := filepath.EvalSymlinks("/etc/localtime")
+	if err != nil {
+		return ""
+	}
+	if _, name, ok := strings.Cut(path, "zoneinfo/"); ok {
+		return name
+	}
+	return ""
+}
+
+// zoned reports the TZID e's times are written with: recurring events in
+// a named zone keep their wall-clock time across DST changes only if they
+// are published in that zone rather than in UTC.
+func zoned(e Event) string {
+	if e.RRule == "" || e.AllDay {
+		return ""
+	}
+	return zoneName(e.Start.Location())
+}
+
+// writeTimezone writes a VTIMEZONE for loc, named tzid, with its offsets
+// from the year of from. Its daylight saving rules repeat yearly on the
+// weekdays they fall on that year; calendar apps that know the IANA zone
+// use their own rules anyway.
+func writeTimezone(out func(name, value string), tzid string, loc *time.Location, from time.Time) {
+	start := time.Date(from.In(loc).Year(), 1, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	var changes []time.Time
+	for t := start; ; {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		changes = append(changes, next)
+		t = next
+	}
+
+	out("BEGIN", "VTIMEZONE")
+	out("TZID", tzid)
+	if len(changes) == 0 {
+		abbr, offset := start.Zone()
+		out("BEGIN", "STANDARD")
+		out("DTSTART", "19700101T000000")
+		out("TZOFFSETFROM", formatOffset(offset))
+		out("TZOFFSETTO", formatOffset(offset))
+		out("TZNAME", abbr)
+		out("END", "STANDARD")
+	}
+	for _, t := range changes {
+		kind := "STANDARD"
+		if t.IsDST() {
+			kind = "DAYLIGHT"
+		}
+		_, from := t.Add(-time.Second).Zone()
+		abbr, to := t.Zone()
+		// DTSTART is the local time the change happens at, before it.
+		onset := t.In(time.FixedZone("", from))
+		out("BEGIN", kind)
+		out("DTSTART", onset.Format("20060102T150405"))
+		if len(changes) == 2 {
+			out("RRULE", fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%s", onset.Month(), nthWeekday(onset)))
+		}
+		out("TZOFFSETFROM", formatOffset(from))
+		out("TZOFFSETTO", formatOffset(to))
+		out("TZNAME", abbr)
+		out("END", kind)
+	}
+	out("END", "VTIMEZONE")
+}
+
+// nthWeekday returns the BYDAY value picking t's date in its month: "-1SU"
+// for a last Sunday, "2SU" for a second one.
+func nthWeekday(t time.Time) string {
+	day := strings.ToUpper(t.Weekday().String()[:2])
+	if t.AddDate(0, 0, 7).Month() != t.Month() {
+		return "-1" + day
+	}
+	return fmt.Sprintf("%d%s", (t.Day()-1)/7+1, day)
+}
+
+// formatOffset formats a UTC offset in seconds as an RFC 5545 UTC-OFFSET.
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+}
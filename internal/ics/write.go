@@ -0,0 +1,187 @@
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProdID identifies calendars written by pylon.
+const ProdID = "-//jredh-dev//pylon//EN"
+
+// maxLine is the longest content line RFC 5545 allows, in octets,
+// excluding the CRLF.
+const maxLine = 75
+
+// Write writes c as an iCalendar stream: CRLF line endings, lines folded at
+// 75 octets without splitting a UTF-8 sequence, TEXT values escaped, and
+// times in UTC (all-day events as DATE values). Recurring events whose start
+// is in a named time zone are the exception: they are written in that zone
+// with a TZID, and the calendar gets a VTIMEZONE for it, so that they keep
+// their wall-clock time across DST changes. Events without a Stamp use
+// their start as DTSTAMP, and SEQUENCE is only written once it is above 0,
+// its default.
+func Write(w io.Writer, c *Calendar) error {
+	bw := bufio.NewWriter(w)
+	out := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	out("BEGIN", "VCALENDAR")
+	out("VERSION", "2.0")
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = ProdID
+	}
+	out("PRODID", prodID)
+	out("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		out("METHOD", c.Method)
+	}
+	if c.Name != "" {
+		out("X-WR-CALNAME", escape(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		// REFRESH-INTERVAL is RFC 7986's; Outlook and older apps read
+		// X-PUBLISHED-TTL.
+		out("REFRESH-INTERVAL;VALUE=DURATION", FormatDuration(c.RefreshInterval))
+		out("X-PUBLISHED-TTL", FormatDuration(c.RefreshInterval))
+	}
+	var zones []string
+	earliest := map[string]time.Time{}
+	for _, e := range c.Events {
+		tzid := zoned(e)
+		if tzid == "" {
+			continue
+		}
+		if first, ok := earliest[tzid]; !ok {
+			zones = append(zones, tzid)
+			earliest[tzid] = e.Start
+		} else if e.Start.Before(first) {
+			earliest[tzid] = e.Start
+		}
+	}
+	for _, tzid := range zones {
+		writeTimezone(out, tzid, earliest[tzid].Location(), earliest[tzid])
+	}
+	for _, e := range c.Events {
+		// when writes a DATE-TIME property: in UTC, or in e's zone.
+		tzid := zoned(e)
+		when := func(name string, t time.Time) {
+			if tzid == "" {
+				out(name, utc(t))
+				return
+			}
+			out(name+";TZID="+tzid, t.In(e.Start.Location()).Format("20060102T150405"))
+		}
+		out("BEGIN", "VEVENT")
+		out("UID", e.UID)
+		stamp := e.Stamp
+		if stamp.IsZero() {
+			stamp = e.Start
+		}
+		out("DTSTAMP", utc(stamp))
+		if e.Sequence > 0 {
+			out("SEQUENCE", strconv.Itoa(e.Sequence))
+		}
+		if e.AllDay {
+			out("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+			if e.End != nil {
+				out("DTEND;VALUE=DATE", e.End.Format("20060102"))
+			}
+		} else {
+			when("DTSTART", e.Start)
+			if e.End != nil {
+				when("DTEND", *e.End)
+			}
+		}
+		out("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			out("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			out("LOCATION", escape(e.Location))
+		}
+		if e.URL != "" {
+			out("URL", e.URL)
+		}
+		for _, a := range e.Attachments {
+			out("ATTACH", a)
+		}
+		if e.Status != "" {
+			out("STATUS", e.Status)
+		}
+		if e.Categories != "" {
+			// Commas separate the categories, so only the rest is escaped.
+			cats := strings.Split(e.Categories, ",")
+			for i, c := range cats {
+				cats[i] = escape(strings.TrimSpace(c))
+			}
+			out("CATEGORIES", strings.Join(cats, ","))
+		}
+		if e.RRule != "" {
+			out("RRULE", e.RRule)
+		}
+		for _, x := range e.ExDates {
+			if e.AllDay {
+				out("EXDATE;VALUE=DATE", x.Format("20060102"))
+			} else {
+				when("EXDATE", x)
+			}
+		}
+		for _, a := range e.Alarms {
+			out("BEGIN", "VALARM")
+			action := a.Action
+			if action == "" {
+				action = "DISPLAY"
+			}
+			out("ACTION", action)
+			desc := a.Description
+			if desc == "" {
+				desc = e.Summary
+			}
+			out("DESCRIPTION", escape(desc))
+			switch {
+			case a.At != nil:
+				out("TRIGGER;VALUE=DATE-TIME", utc(*a.At))
+			case a.Related == "END":
+				out("TRIGGER;RELATED=END", FormatDuration(a.Trigger))
+			default:
+				out("TRIGGER", FormatDuration(a.Trigger))
+			}
+			out("END", "VALARM")
+		}
+		out("END", "VEVENT")
+	}
+	out("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape encodes a TEXT value, the inverse of unescape.
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// writeFolded writes one content line, folding it into continuation lines
+// that start with a space.
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxLine
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = maxLine - 1 // the leading space counts
+	}
+	w.WriteString(line + "\r\n")
+}
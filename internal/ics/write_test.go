@@ -0,0 +1,254 @@
+package ics
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(15 * time.Minute)
+	deadline := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	launch := time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local)
+	in := &Calendar{
+		Name:            "Team, Berlin",
+		Method:          "PUBLISH",
+		ProdID:          "-//Example Corp//Team Calendar//EN",
+		RefreshInterval: time.Hour,
+		Events: []Event{
+			{
+				UID:         "standup-1",
+				Summary:     "Standup; daily, with coffee ☕",
+				Description: "Line one\nLine two " + strings.Repeat("long ünïcode text ", 8),
+				Location:    `Room \1`,
+				URL:         "https://meet.example.com/standup",
+				Attachments: []string{"https://cdn.discordapp.com/attachments/1122334455/6677889900/standup-agenda.pdf?ex=66a1b2c3&is=66a06143&hm=0f1e2d3c"},
+				Status:      "CONFIRMED",
+				Categories:  "meeting,team",
+				Start:       start,
+				End:         &end,
+				RRule:       "FREQ=WEEKLY;BYDAY=MO,WE",
+				ExDates:     []time.Time{start.AddDate(0, 0, 2)},
+				Alarms: []Alarm{
+					{Action: "DISPLAY", Description: "Standup soon", Trigger: -10 * time.Minute, Related: "START"},
+					{Action: "AUDIO", Description: "Over", Related: "END"},
+				},
+				Stamp: start.Add(-time.Hour),
+			},
+			{
+				UID:      "launch",
+				Summary:  "Launch",
+				Status:   "CANCELLED",
+				Start:    launch,
+				AllDay:   true,
+				Alarms:   []Alarm{{Action: "DISPLAY", Description: "Launch", Related: "START", At: &deadline}},
+				Stamp:    start,
+				Sequence: 2,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, in); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	for i, l := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line %d is %d octets: %q", i+1, len(l), l)
+		}
+		if strings.Contains(l, "\n") {
+			t.Errorf("line %d has a bare LF", i+1)
+		}
+	}
+
+	out, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", out, in)
+	}
+}
+
+func TestFromEvent(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	deadline := start.Add(-time.Hour)
+	e := FromEvent(cal.Event{
+		ID:       "ev-1",
+		Summary:  "Taxes",
+		Start:    start,
+		Deadline: &deadline,
+		Alarms:   []string{"-PT15M", "bogus"},
+	})
+	if e.UID != "ev-1" || len(e.Alarms) != 2 {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	if e.Alarms[0].At == nil || !e.Alarms[0].At.Equal(deadline) {
+		t.Errorf("deadline alarm = %+v", e.Alarms[0])
+	}
+	if e.Alarms[1].Trigger != -15*time.Minute {
+		t.Errorf("trigger alarm = %+v", e.Alarms[1])
+	}
+	if at, ok := e.FirstAlarm(); !ok || !at.Equal(deadline) {
+		t.Errorf("FirstAlarm = %v, %v; want the deadline", at, ok)
+	}
+}
+
+func TestFromFeed(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	events := []cal.Event{{ID: "ev-1", Summary: "Standup", Start: start, Alarms: []string{"-PT15M"}}}
+
+	c := FromFeed(cal.Feed{Name: "team"}, events)
+	if c.Name != "team" || c.ProdID != "" || c.RefreshInterval != 0 || len(c.Events[0].Alarms) != 1 {
+		t.Errorf("defaults: %+v", c)
+	}
+
+	f := cal.Feed{Name: "team", CalendarName: "Team Berlin", ProdID: "-//Example//EN", RefreshInterval: "PT30M", OmitAlarms: true}
+	c = FromFeed(f, events)
+	if c.Name != "Team Berlin" || c.ProdID != "-//Example//EN" || c.RefreshInterval != 30*time.Minute || c.Method != "PUBLISH" {
+		t.Errorf("settings: %+v", c)
+	}
+	if len(c.Events) != 1 || c.Events[0].Alarms != nil {
+		t.Errorf("alarms should be left out: %+v", c.Events)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"PRODID:-//Example//EN\r\n", "REFRESH-INTERVAL;VALUE=DURATION:PT30M\r\n", "X-PUBLISHED-TTL:PT30M\r\n", "X-WR-CALNAME:Team Berlin\r\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDropCancelled(t *testing.T) {
+	c := &Calendar{Events: []Event{
+		{UID: "a", Status: "CONFIRMED"},
+		{UID: "b", Status: "CANCELLED"},
+		{UID: "c"},
+		{UID: "d", Status: "cancelled"},
+	}}
+	c.DropCancelled()
+	var uids []string
+	for _, e := range c.Events {
+		uids = append(uids, e.UID)
+	}
+	if !slices.Equal(uids, []string{"a", "c"}) {
+		t.Errorf("kept %v, want [a c]", uids)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	in := Event{
+		UID:         "ci-4711",
+		Summary:     "Interview: Jane Doe",
+		Description: "Salary talk",
+		Location:    "Room 3",
+		URL:         "https://meet.example.com/jane",
+		Categories:  "hiring",
+		Attachments: []string{"https://cdn.example.com/cv.pdf"},
+		Alarms:      []Alarm{{Action: "DISPLAY", Trigger: -10 * time.Minute}},
+		Status:      "CONFIRMED",
+		Start:       start,
+		End:         &end,
+		RRule:       "FREQ=WEEKLY",
+		ExDates:     []time.Time{start.AddDate(0, 0, 7)},
+		Sequence:    3,
+	}
+	got := Redact(in)
+	want := Event{
+		UID:      got.UID,
+		Summary:  "Busy",
+		Status:   "CONFIRMED",
+		Start:    start,
+		End:      &end,
+		RRule:    "FREQ=WEEKLY",
+		ExDates:  in.ExDates,
+		Sequence: 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact =\n%+v\nwant\n%+v", got, want)
+	}
+	if strings.Contains(got.UID, "4711") || got.UID != Redact(in).UID || got.UID == Redact(Event{UID: "other"}).UID {
+		t.Errorf("UID %q should be an opaque, stable hash", got.UID)
+	}
+}
+
+func TestAlarmsRoundTrip(t *testing.T) {
+	// Alarms exported by FromEvent come back as alarms, and the deadline
+	// as the deadline, even for a recurring event.
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	deadline := start.Add(-24 * time.Hour)
+	e := cal.Event{Summary: "Standup", Start: start, RRule: "FREQ=WEEKLY", Alarms: []string{"-PT15M", "-PT1H"}, Deadline: &deadline}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, &Calendar{Events: []Event{FromEvent(e)}}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, extra := c.Events[0].CreateRequest("f")
+	if !slices.Equal(req.Alarms, e.Alarms) || req.Deadline != deadline.Format(time.RFC3339) || extra != 0 {
+		t.Errorf("round trip: alarms %q, deadline %q, extra %d", req.Alarms, req.Deadline, extra)
+	}
+}
+
+func TestWriteRecurringInZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	start := time.Date(2026, 10, 15, 9, 0, 0, 0, berlin)
+	end := start.Add(time.Hour)
+	in := &Calendar{Events: []Event{
+		{UID: "weekly", Summary: "Weekly", Start: start, End: &end, RRule: "FREQ=WEEKLY", ExDates: []time.Time{start.AddDate(0, 0, 14)}},
+		{UID: "once", Summary: "Once", Start: start},
+		{UID: "fixed", Summary: "Fixed", Start: start.In(time.FixedZone("", 2*3600)), RRule: "FREQ=WEEKLY"},
+	}}
+	var buf bytes.Buffer
+	if err := Write(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\n",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\n",
+		"BEGIN:STANDARD\r\nDTSTART:20261025T030000\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n",
+		"DTSTART;TZID=Europe/Berlin:20261015T090000\r\n",
+		"DTEND;TZID=Europe/Berlin:20261015T100000\r\n",
+		"EXDATE;TZID=Europe/Berlin:20261029T090000\r\n",
+		"UID:once\r\nDTSTAMP:20261015T070000Z\r\nDTSTART:20261015T070000Z\r\n",  // not recurring: UTC
+		"UID:fixed\r\nDTSTAMP:20261015T070000Z\r\nDTSTART:20261015T070000Z\r\n", // no zone name: UTC
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+	if n := strings.Count(text, "BEGIN:VTIMEZONE"); n != 1 {
+		t.Errorf("%d VTIMEZONEs, want 1", n)
+	}
+
+	out, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// After the change to winter time the series is still at 09:00.
+	got := out.Events[0]
+	if got.Start.Location().String() != "Europe/Berlin" || !got.Start.Equal(start) || !got.ExDates[0].Equal(in.Events[0].ExDates[0]) {
+		t.Errorf("parsed %+v", got)
+	}
+	if h := got.Start.AddDate(0, 0, 21).Hour(); h != 9 {
+		t.Errorf("occurrence after DST at %d:00", h)
+	}
+}
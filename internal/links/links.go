@@ -0,0 +1,143 @@
+// Package links prepares event URLs for announcements: tagging them with
+// UTM parameters so community managers can see which posts people click
+// through from, and optionally passing them through a URL shortener.
+package links
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UTM holds the utm_* query parameters added to announced URLs. Empty
+// fields are left out.
+type UTM struct {
+	Source   string
+	Medium   string
+	Campaign string
+}
+
+// IsZero reports whether no parameters are set.
+func (u UTM) IsZero() bool { return u == UTM{} }
+
+// Tag adds u's parameters to rawURL. Parameters the URL already carries are
+// kept, so a link that was tagged by hand is never overridden.
+func Tag(rawURL string, u UTM) (string, error) {
+	if u.IsZero() {
+		return rawURL, nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		// Only web links are tracked; mailto:, tel: and friends pass through.
+		return rawURL, nil
+	}
+	q := parsed.Query()
+	for _, p := range [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+	} {
+		if p[1] != "" && !q.Has(p[0]) {
+			q.Set(p[0], p[1])
+		}
+	}
+	parsed.RawQuery = q.Encode()
+	return parsed.String(), nil
+}
+
+// maxShort bounds how much of a shortener response is read.
+const maxShort = 4 << 10
+
+// Shortener turns long URLs into short ones through a web service that
+// answers a GET with the short URL as plain text, such as
+// https://is.gd/create.php?format=simple&url={url}.
+type Shortener struct {
+	// Endpoint is the request URL. {url} is replaced with the query-escaped
+	// long URL; without it the long URL is appended as the url parameter.
+	Endpoint string
+	Client   *http.Client // nil uses a client with a 10s timeout
+}
+
+// Shorten returns the short form of long.
+func (s *Shortener) Shorten(long string) (string, error) {
+	endpoint := s.Endpoint
+	if strings.Contains(endpoint, "{url}") {
+		endpoint = strings.ReplaceAll(endpoint, "{url}", url.QueryEscape(long))
+	} else {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return "", fmt.Errorf("shortener: %w", err)
+		}
+		q := u.Query()
+		q.Set("url", long)
+		u.RawQuery = q.Encode()
+		endpoint = u.String()
+	}
+
+	hc := s.Client
+	if hc == nil {
+		hc = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := hc.Get(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("shortener: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxShort))
+	if err != nil {
+		return "", fmt.Errorf("shortener: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("shortener: %s", resp.Status)
+	}
+	short := strings.TrimSpace(string(body))
+	if u, err := url.Parse(short); err != nil || u.Scheme == "" || u.Host == "" {
+		return "", errors.New("shortener: response is not a URL")
+	}
+	return short, nil
+}
+
+// Rewriter tags and shortens URLs for announcements. Short URLs are
+// remembered, so a long-running poller asks the shortener once per link.
+// The zero value returns URLs unchanged.
+type Rewriter struct {
+	UTM       UTM
+	Shortener *Shortener // nil to keep long URLs
+
+	short map[string]string
+}
+
+// Rewrite returns the URL to announce for rawURL. If the shortener fails the
+// tagged long URL is returned along with the error, so an announcement can
+// still go out.
+func (r *Rewriter) Rewrite(rawURL string) (string, error) {
+	if rawURL == "" {
+		return "", nil
+	}
+	tagged, err := Tag(rawURL, r.UTM)
+	if err != nil {
+		return rawURL, err
+	}
+	if r.Shortener == nil {
+		return tagged, nil
+	}
+	if short, ok := r.short[tagged]; ok {
+		return short, nil
+	}
+	short, err := r.Shortener.Shorten(tagged)
+	if err != nil {
+		return tagged, err
+	}
+	if r.short == nil {
+		r.short = make(map[string]string)
+	}
+	r.short[tagged] = short
+	return short, nil
+}
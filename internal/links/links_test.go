@@ -0,0 +1,124 @@
+package links
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTag(t *testing.T) {
+	utm := UTM{Source: "discord", Medium: "social", Campaign: "spring meetup"}
+	tests := []struct {
+		name string
+		url  string
+		utm  UTM
+		want string
+	}{
+		{name: "plain", url: "https://example.com/e/1", utm: utm,
+			want: "https://example.com/e/1?utm_campaign=spring+meetup&utm_medium=social&utm_source=discord"},
+		{name: "existing query", url: "https://example.com/e?id=1", utm: UTM{Source: "discord"},
+			want: "https://example.com/e?id=1&utm_source=discord"},
+		{name: "hand tagged", url: "https://example.com/?utm_source=newsletter", utm: UTM{Source: "discord", Medium: "social"},
+			want: "https://example.com/?utm_medium=social&utm_source=newsletter"},
+		{name: "fragment", url: "https://example.com/e#agenda", utm: UTM{Source: "discord"},
+			want: "https://example.com/e?utm_source=discord#agenda"},
+		{name: "no params", url: "https://example.com/e?b=2&a=1", utm: UTM{}, want: "https://example.com/e?b=2&a=1"},
+		{name: "not web", url: "mailto:team@example.com", utm: utm, want: "mailto:team@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Tag(tt.url, tt.utm)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Tag(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShorten(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("url")
+		_, _ = w.Write([]byte("https://sho.rt/abc\n"))
+	}))
+	defer srv.Close()
+
+	for _, endpoint := range []string{srv.URL + "/create?format=simple&url={url}", srv.URL + "/create?format=simple"} {
+		s := &Shortener{Endpoint: endpoint, Client: srv.Client()}
+		short, err := s.Shorten("https://example.com/e?id=1&x=y")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", endpoint, err)
+		}
+		if short != "https://sho.rt/abc" {
+			t.Errorf("%s: short = %q", endpoint, short)
+		}
+		if got != "https://example.com/e?id=1&x=y" {
+			t.Errorf("%s: shortener got url %q", endpoint, got)
+		}
+	}
+}
+
+func TestShortenErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{name: "status", handler: func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		}},
+		{name: "not a URL", handler: func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("Error: please enter a valid URL"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+			s := &Shortener{Endpoint: srv.URL, Client: srv.Client()}
+			if _, err := s.Shorten("https://example.com/"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRewriter(t *testing.T) {
+	calls := 0
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if fail {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("https://sho.rt/abc"))
+	}))
+	defer srv.Close()
+
+	r := &Rewriter{UTM: UTM{Source: "discord"}, Shortener: &Shortener{Endpoint: srv.URL, Client: srv.Client()}}
+	for i := 0; i < 2; i++ {
+		got, err := r.Rewrite("https://example.com/e")
+		if err != nil || got != "https://sho.rt/abc" {
+			t.Fatalf("Rewrite = %q, %v", got, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("shortener called %d times, want 1 (cached)", calls)
+	}
+
+	fail = true
+	got, err := r.Rewrite("https://example.com/other")
+	if err == nil {
+		t.Error("expected the shortener error")
+	}
+	if got != "https://example.com/other?utm_source=discord" {
+		t.Errorf("fallback = %q, want the tagged long URL", got)
+	}
+
+	if got, err := (&Rewriter{}).Rewrite("https://example.com/e"); err != nil || got != "https://example.com/e" {
+		t.Errorf("zero Rewriter = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package lock
+
+import (
+	"errors"
+	"syscall"
+)
+
+// alive reports whether a process with pid exists. Signal 0 checks without
+// signalling; EPERM means it exists but belongs to another user.
+func alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,17 @@
+package lock
+
+import "os"
+
+// alive reports whether a process with pid exists: on Windows FindProcess
+// opens the process and fails if there is none.
+func alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
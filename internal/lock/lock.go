@@ -0,0 +1,242 @@
+// Package lock implements advisory lock files, so that two pylon processes
+// don't run conflicting jobs (two syncs into one feed, two reminder loops
+// sharing a state file) at the same time. A lock is a file created
+// exclusively, holding its owner's PID, host and command; a lock left
+// behind by a process that no longer runs is stale and taken over by
+// renaming a new lock file over it.
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Info describes the holder of a lock.
+type Info struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Command string    `json:"command"`
+	Since   time.Time `json:"since"`
+}
+
+func (i Info) String() string {
+	return fmt.Sprintf("%q (pid %d on %s, since %s)", i.Command, i.PID, i.Host, i.Since.Format(time.RFC3339))
+}
+
+func (i Info) same(o Info) bool {
+	return i.PID == o.PID && i.Host == o.Host && i.Since.Equal(o.Since)
+}
+
+// ErrLocked is matched (via errors.Is) by a LockedError.
+var ErrLocked = errors.New("locked")
+
+// LockedError is returned when another live process holds the lock.
+type LockedError struct {
+	Path   string
+	Holder Info
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s is held by %s", e.Path, e.Holder)
+}
+
+func (e *LockedError) Is(target error) bool { return target == ErrLocked }
+
+// unreadableStale is how old a lock file that can't be parsed must be to
+// count as stale: a fresh one may still be being written.
+const unreadableStale = time.Minute
+
+// beforeTakeover, if set, is called between finding a lock stale and
+// taking it over, for tests to let another process in.
+var beforeTakeover func()
+
+// Lock is a held lock.
+type Lock struct {
+	path string
+	info Info
+}
+
+// Path returns the lock file's path.
+func (l *Lock) Path() string { return l.path }
+
+// Acquire takes the lock at path for command, replacing a stale lock. If a
+// live process holds it the error is a *LockedError.
+func Acquire(path, command string) (*Lock, error) {
+	host, _ := os.Hostname()
+	info := Info{PID: os.Getpid(), Host: host, Command: command, Since: time.Now().UTC().Round(0)}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	// A stale lock is replaced rather than removed, so the path always
+	// exists and nobody else can create it meanwhile. Another attempt is
+	// only needed if the lock was released while it was being looked at.
+	for range 3 {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			_, werr := f.Write(append(data, '\n'))
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				os.Remove(path)
+				return nil, werr
+			}
+			return &Lock{path: path, info: info}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		holder, stale := inspect(path, host)
+		if !stale {
+			return nil, &LockedError{Path: path, Holder: holder}
+		}
+		if beforeTakeover != nil {
+			beforeTakeover()
+		}
+		err = takeOver(path, host, info, data)
+		if err == nil {
+			return &Lock{path: path, info: info}, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	holder, _ := Read(path)
+	return nil, &LockedError{Path: path, Holder: holder}
+}
+
+// takeOver replaces the stale lock at path with one for info, written to a
+// temporary file first and renamed over it, so the lock file is never
+// missing or half written. Takeovers are serialised by a second file,
+// path+".takeover", created exclusively: under it the lock is checked again,
+// so of two processes that found the same stale lock, the second finds the
+// first's live one and gets a *LockedError. The error wraps os.ErrNotExist
+// if the lock was released meanwhile.
+func takeOver(path, host string, info Info, data []byte) error {
+	guard := path + ".takeover"
+	g, err := os.OpenFile(guard, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		// Another process is taking the lock over, or died doing so.
+		if fi, serr := os.Stat(guard); serr == nil && time.Since(fi.ModTime()) > unreadableStale {
+			os.Remove(guard)
+		}
+		holder, _ := Read(path)
+		return &LockedError{Path: path, Holder: holder}
+	}
+	if err != nil {
+		return err
+	}
+	g.Close()
+	defer os.Remove(guard)
+
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	if holder, stale := inspect(path, host); !stale {
+		return &LockedError{Path: path, Holder: holder}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".lock-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(append(data, '\n'))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return err
+	}
+	if holder, err := Read(path); err != nil || !holder.same(info) {
+		return &LockedError{Path: path, Holder: holder}
+	}
+	return nil
+}
+
+// Wait is Acquire, retrying every poll while a live process holds the
+// lock, until it is released or ctx is done. waiting, if not nil, is called
+// once with the holder before the first wait.
+func Wait(ctx context.Context, path, command string, poll time.Duration, waiting func(Info)) (*Lock, error) {
+	for first := true; ; first = false {
+		l, err := Acquire(path, command)
+		var locked *LockedError
+		if !errors.As(err, &locked) {
+			return l, err
+		}
+		if first && waiting != nil {
+			waiting(locked.Holder)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", err, ctx.Err())
+		case <-time.After(poll):
+		}
+	}
+}
+
+// Force removes the lock at path whoever holds it, then acquires it. The
+// previous holder, if any, is returned with ok set.
+func Force(path, command string) (l *Lock, previous Info, ok bool, err error) {
+	previous, err = Read(path)
+	ok = err == nil
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, previous, ok, err
+	}
+	l, err = Acquire(path, command)
+	return l, previous, ok, err
+}
+
+// Read returns the holder recorded in the lock file at path.
+func Read(path string) (Info, error) {
+	var info Info
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return info, nil
+}
+
+// inspect reads the lock at path and reports whether it is stale: left by
+// a process on this host that has exited, or unreadable and old. A lock
+// from another host (a shared state directory) is never stale, since its
+// process can't be checked.
+func inspect(path, host string) (Info, bool) {
+	info, err := Read(path)
+	if err != nil {
+		fi, serr := os.Stat(path)
+		if errors.Is(serr, os.ErrNotExist) {
+			return info, true // released meanwhile
+		}
+		return info, serr == nil && time.Since(fi.ModTime()) > unreadableStale
+	}
+	return info, info.Host == host && !alive(info.PID)
+}
+
+// Release removes the lock file, unless it was forced away and taken by
+// another process since.
+func (l *Lock) Release() error {
+	if info, err := Read(l.path); err == nil && !info.same(l.info) {
+		return nil
+	}
+	err := os.Remove(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
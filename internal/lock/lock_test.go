@@ -0,0 +1,248 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeHolder(t *testing.T, path string, info Info) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locks", "remind.lock")
+	l, err := Acquire(path, "remind")
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	info, err := Read(path)
+	if err != nil || info.PID != os.Getpid() || info.Command != "remind" {
+		t.Fatalf("Read = %+v, %v", info, err)
+	}
+
+	// This process is alive, so a second attempt is refused.
+	_, err = Acquire(path, "remind")
+	var locked *LockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrLocked) || locked.Holder.PID != os.Getpid() {
+		t.Fatalf("second Acquire = %v, want a LockedError", err)
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file still there after Release: %v", err)
+	}
+	if _, err := Acquire(path, "remind"); err != nil {
+		t.Errorf("Acquire after Release: %v", err)
+	}
+}
+
+func TestStaleLocks(t *testing.T) {
+	host, _ := os.Hostname()
+	// PIDs this large are never allocated.
+	const dead = 1 << 30
+
+	tests := []struct {
+		name      string
+		write     func(path string)
+		wantStale bool
+	}{
+		{name: "dead process on this host", wantStale: true, write: func(path string) {
+			writeHolder(t, path, Info{PID: dead, Host: host, Command: "sync"})
+		}},
+		{name: "live process on this host", wantStale: false, write: func(path string) {
+			writeHolder(t, path, Info{PID: os.Getpid(), Host: host, Command: "sync"})
+		}},
+		{name: "other host", wantStale: false, write: func(path string) {
+			writeHolder(t, path, Info{PID: dead, Host: host + "-elsewhere", Command: "sync"})
+		}},
+		{name: "fresh unreadable file", wantStale: false, write: func(path string) {
+			_ = os.WriteFile(path, []byte("{"), 0o600)
+		}},
+		{name: "old unreadable file", wantStale: true, write: func(path string) {
+			_ = os.WriteFile(path, []byte("{"), 0o600)
+			old := time.Now().Add(-2 * unreadableStale)
+			_ = os.Chtimes(path, old, old)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sync.lock")
+			tt.write(path)
+			l, err := Acquire(path, "sync")
+			if tt.wantStale {
+				if err != nil {
+					t.Fatalf("Acquire over a stale lock: %v", err)
+				}
+				if info, _ := Read(path); info.PID != os.Getpid() {
+					t.Errorf("lock holder = %+v, want this process", info)
+				}
+				_ = l.Release()
+			} else if !errors.Is(err, ErrLocked) {
+				t.Fatalf("Acquire = %v, want ErrLocked", err)
+			}
+		})
+	}
+}
+
+func TestConcurrentTakeover(t *testing.T) {
+	host, _ := os.Hostname()
+	for round := range 50 {
+		path := filepath.Join(t.TempDir(), "sync.lock")
+		writeHolder(t, path, Info{PID: 1 << 30, Host: host, Command: "dead"})
+
+		const n = 32
+		locks := make(chan *Lock, n)
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				l, err := Acquire(path, fmt.Sprint("sync-", i))
+				if err == nil {
+					locks <- l
+				} else if !errors.Is(err, ErrLocked) {
+					t.Errorf("Acquire: %v", err)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		close(locks)
+
+		var won []*Lock
+		for l := range locks {
+			won = append(won, l)
+		}
+		if len(won) != 1 {
+			t.Fatalf("round %d: %d processes took over the stale lock, want 1", round, len(won))
+		}
+		if info, err := Read(path); err != nil || !info.same(won[0].info) {
+			t.Errorf("round %d: holder = %+v, %v; want %+v", round, info, err, won[0].info)
+		}
+	}
+}
+
+func TestTakeoverInterleaved(t *testing.T) {
+	host, _ := os.Hostname()
+	path := filepath.Join(t.TempDir(), "sync.lock")
+	writeHolder(t, path, Info{PID: 1 << 30, Host: host, Command: "dead"})
+
+	// The second process finds the same stale lock and takes it over while
+	// the first is about to.
+	var second *Lock
+	beforeTakeover = func() {
+		beforeTakeover = nil
+		var err error
+		if second, err = Acquire(path, "second"); err != nil {
+			t.Errorf("second Acquire: %v", err)
+		}
+	}
+	defer func() { beforeTakeover = nil }()
+
+	_, err := Acquire(path, "first")
+	var locked *LockedError
+	if !errors.As(err, &locked) || locked.Holder.Command != "second" {
+		t.Fatalf("first Acquire = %v, want a LockedError naming the second", err)
+	}
+	if info, err := Read(path); err != nil || second == nil || !info.same(second.info) {
+		t.Errorf("holder = %+v, %v; want the second", info, err)
+	}
+}
+
+func TestAbandonedTakeover(t *testing.T) {
+	host, _ := os.Hostname()
+	path := filepath.Join(t.TempDir(), "sync.lock")
+	writeHolder(t, path, Info{PID: 1 << 30, Host: host, Command: "dead"})
+	guard := path + ".takeover"
+	if err := os.WriteFile(guard, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A takeover in progress makes the lock look held.
+	if _, err := Acquire(path, "sync"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Acquire during a takeover = %v, want ErrLocked", err)
+	}
+	// One left behind by a process that died is cleared once it is old.
+	old := time.Now().Add(-2 * unreadableStale)
+	_ = os.Chtimes(guard, old, old)
+	_, _ = Acquire(path, "sync")
+	l, err := Acquire(path, "sync")
+	if err != nil {
+		t.Fatalf("Acquire after an abandoned takeover: %v", err)
+	}
+	_ = l.Release()
+}
+
+func TestWait(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.lock")
+	held, err := Acquire(path, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var waitedFor []Info
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = held.Release()
+	}()
+	l, err := Wait(context.Background(), path, "second", 10*time.Millisecond, func(i Info) { waitedFor = append(waitedFor, i) })
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if len(waitedFor) != 1 || waitedFor[0].Command != "first" {
+		t.Errorf("waiting called with %+v, want the first holder once", waitedFor)
+	}
+	if info, _ := Read(path); info.Command != "second" {
+		t.Errorf("holder = %+v", info)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := Wait(ctx, path, "third", 10*time.Millisecond, nil); !errors.Is(err, ErrLocked) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait with a held lock = %v, want ErrLocked and a deadline error", err)
+	}
+	_ = l.Release()
+}
+
+func TestForce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.lock")
+	first, err := Acquire(path, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, previous, ok, err := Force(path, "second")
+	if err != nil || !ok || previous.Command != "first" {
+		t.Fatalf("Force = %+v, %v, %v", previous, ok, err)
+	}
+
+	// The forced-out holder releasing must not remove the new lock.
+	if err := first.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if info, err := Read(path); err != nil || info.Command != "second" {
+		t.Errorf("holder after old Release = %+v, %v", info, err)
+	}
+	_ = second.Release()
+
+	if _, _, ok, err := Force(path, "third"); err != nil || ok {
+		t.Errorf("Force on a free lock = %v, %v; want no previous holder", ok, err)
+	}
+}
@@ -0,0 +1,210 @@
+// Package mcp implements the server side of the Model Context Protocol over
+// stdio: newline-delimited JSON-RPC 2.0 messages on stdin and stdout. Only
+// tools are supported, which is all `pylon mcp serve` exposes.
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ProtocolVersion is the newest protocol revision the server speaks. A
+// client asking for an older supported revision gets that one instead.
+const ProtocolVersion = "2025-06-18"
+
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// maxMessage bounds a single incoming message.
+const maxMessage = 4 << 20
+
+// Tool is a function the client can call.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the arguments object.
+	InputSchema map[string]any
+	// Call runs the tool with the raw arguments object. A string result is
+	// returned as is, anything else as indented JSON. An error is reported
+	// to the client as a failed tool call, not a protocol error, so the
+	// model sees it and can correct itself.
+	Call func(args json.RawMessage) (any, error)
+}
+
+// Server answers MCP requests with a fixed set of tools.
+type Server struct {
+	name, version string
+	tools         []Tool
+}
+
+// NewServer returns a server that identifies itself as name and version.
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version}
+}
+
+// Add registers a tool.
+func (s *Server) Add(t Tool) {
+	s.tools = append(s.tools, t)
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r and writes responses to w until r is
+// exhausted. Requests are handled one at a time, in order.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), maxMessage)
+	enc := json.NewEncoder(w)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(line)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// handle answers one message. Notifications get no response.
+func (s *Server) handle(msg []byte) *response {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}}
+	}
+	if req.ID == nil {
+		return nil
+	}
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{codeInvalidRequest, "invalid request"}
+		return resp
+	}
+
+	var err *rpcError
+	switch req.Method {
+	case "initialize":
+		resp.Result, err = s.initialize(req.Params)
+	case "ping":
+		resp.Result = struct{}{}
+	case "tools/list":
+		resp.Result = s.listTools()
+	case "tools/call":
+		resp.Result, err = s.callTool(req.Params)
+	default:
+		err = &rpcError{codeMethodNotFound, "method not found: " + req.Method}
+	}
+	resp.Error = err
+	return resp
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{codeInvalidParams, err.Error()}
+		}
+	}
+	version := ProtocolVersion
+	if slices.Contains(supportedVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+	}, nil
+}
+
+type toolInfo struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+func (s *Server) listTools() any {
+	tools := make([]toolInfo, 0, len(s.tools))
+	for _, t := range s.tools {
+		schema := t.InputSchema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		tools = append(tools, toolInfo{t.Name, t.Description, schema})
+	}
+	return map[string]any{"tools": tools}
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+func (s *Server) callTool(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{codeInvalidParams, err.Error()}
+	}
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == p.Name })
+	if i < 0 {
+		return nil, &rpcError{codeInvalidParams, "unknown tool: " + p.Name}
+	}
+	if len(p.Arguments) == 0 {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	out, err := s.tools[i].Call(p.Arguments)
+	if err != nil {
+		return callResult{Content: []content{{"text", err.Error()}}, IsError: true}, nil
+	}
+	text, ok := out.(string)
+	if !ok {
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return callResult{Content: []content{{"text", fmt.Sprintf("encode result: %v", err)}}, IsError: true}, nil
+		}
+		text = string(b)
+	}
+	return callResult{Content: []content{{"text", text}}}, nil
+}
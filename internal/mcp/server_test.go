@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testServer() *Server {
+	s := NewServer("pylon", "test")
+	s.Add(Tool{
+		Name:        "echo",
+		Description: "Echo the text argument",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"text": map[string]any{"type": "string"}},
+		},
+		Call: func(args json.RawMessage) (any, error) {
+			var a struct{ Text string }
+			if err := json.Unmarshal(args, &a); err != nil {
+				return nil, err
+			}
+			if a.Text == "" {
+				return nil, errors.New("text is required")
+			}
+			return a.Text, nil
+		},
+	})
+	s.Add(Tool{
+		Name: "list",
+		Call: func(json.RawMessage) (any, error) { return []int{1, 2}, nil },
+	})
+	return s
+}
+
+// serve runs the server over the given request lines and decodes each
+// response line.
+func serve(t *testing.T, s *Server, lines ...string) []map[string]any {
+	t.Helper()
+	var out strings.Builder
+	if err := s.Serve(strings.NewReader(strings.Join(lines, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var resps []map[string]any
+	dec := json.NewDecoder(strings.NewReader(out.String()))
+	for dec.More() {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		resps = append(resps, m)
+	}
+	return resps
+}
+
+func TestInitialize(t *testing.T) {
+	tests := []struct {
+		name, requested, want string
+	}{
+		{"current", ProtocolVersion, ProtocolVersion},
+		{"older supported", "2024-11-05", "2024-11-05"},
+		{"unknown", "1999-01-01", ProtocolVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resps := serve(t, testServer(),
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+tt.requested+`","capabilities":{},"clientInfo":{"name":"x","version":"1"}}}`,
+				`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			)
+			if len(resps) != 1 {
+				t.Fatalf("got %d responses, want 1 (notifications are not answered)", len(resps))
+			}
+			result := resps[0]["result"].(map[string]any)
+			if got := result["protocolVersion"]; got != tt.want {
+				t.Errorf("protocolVersion = %v, want %s", got, tt.want)
+			}
+			if _, ok := result["capabilities"].(map[string]any)["tools"]; !ok {
+				t.Error("tools capability not advertised")
+			}
+			if name := result["serverInfo"].(map[string]any)["name"]; name != "pylon" {
+				t.Errorf("serverInfo.name = %v", name)
+			}
+		})
+	}
+}
+
+func TestToolsList(t *testing.T) {
+	resps := serve(t, testServer(), `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`)
+	if resps[0]["id"] != "a" {
+		t.Errorf("id = %v, want a", resps[0]["id"])
+	}
+	tools := resps[0]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 2 {
+		t.Fatalf("got %d tools, want 2", len(tools))
+	}
+	list := tools[1].(map[string]any)
+	if list["name"] != "list" {
+		t.Errorf("second tool = %v", list["name"])
+	}
+	if schema := list["inputSchema"].(map[string]any); schema["type"] != "object" {
+		t.Errorf("default schema = %v, want an object schema", schema)
+	}
+}
+
+func TestToolsCall(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    string
+		wantText  string
+		wantError bool // failed tool call (isError)
+		wantCode  float64
+	}{
+		{"string result", `{"name":"echo","arguments":{"text":"hi"}}`, "hi", false, 0},
+		{"json result", `{"name":"list"}`, "[\n  1,\n  2\n]", false, 0},
+		{"tool error", `{"name":"echo","arguments":{}}`, "text is required", true, 0},
+		{"unknown tool", `{"name":"nope"}`, "", false, codeInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resps := serve(t, testServer(), `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":`+tt.params+`}`)
+			resp := resps[0]
+			if tt.wantCode != 0 {
+				e, ok := resp["error"].(map[string]any)
+				if !ok || e["code"] != tt.wantCode {
+					t.Fatalf("got %v, want error code %v", resp, tt.wantCode)
+				}
+				return
+			}
+			result := resp["result"].(map[string]any)
+			text := result["content"].([]any)[0].(map[string]any)["text"]
+			if text != tt.wantText {
+				t.Errorf("text = %q, want %q", text, tt.wantText)
+			}
+			if isErr, _ := result["isError"].(bool); isErr != tt.wantError {
+				t.Errorf("isError = %v, want %v", isErr, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	resps := serve(t, testServer(),
+		`not json`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`,
+		`{"jsonrpc":"1.0","id":3,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"ping"}`,
+	)
+	wantCodes := []float64{codeParseError, codeMethodNotFound, codeInvalidRequest, 0}
+	if len(resps) != len(wantCodes) {
+		t.Fatalf("got %d responses, want %d", len(resps), len(wantCodes))
+	}
+	for i, want := range wantCodes {
+		e, _ := resps[i]["error"].(map[string]any)
+		if want == 0 {
+			if e != nil {
+				t.Errorf("response %d: unexpected error %v", i, e)
+			}
+			continue
+		}
+		if e == nil || e["code"] != want {
+			t.Errorf("response %d: got %v, want code %v", i, resps[i], want)
+		}
+	}
+}
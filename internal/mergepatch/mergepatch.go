@@ -0,0 +1,66 @@
+// Package mergepatch applies JSON merge patches (RFC 7396): a patch is a
+// partial document whose members replace the target's, with null removing
+// a member and nested objects merged recursively.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNotObject is returned for a patch that isn't a JSON object. RFC 7396
+// lets such a patch replace the whole document, which is never what an
+// edit of some fields means.
+var ErrNotObject = errors.New("patch is not a JSON object")
+
+// Apply returns doc with patch merged into it.
+func Apply(doc, patch []byte) ([]byte, error) {
+	d, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("document: %w", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("patch: %w", err)
+	}
+	if _, ok := p.(map[string]any); !ok {
+		return nil, ErrNotObject
+	}
+	return json.Marshal(merge(d, p))
+}
+
+// decode parses data keeping numbers as written, so large integers survive.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+// merge is the MergePatch function of RFC 7396, section 2.
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = merge(t[k], v)
+	}
+	return t
+}
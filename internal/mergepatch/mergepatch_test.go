@@ -0,0 +1,52 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	// Cases from RFC 7396, appendix A, with object patches.
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		{`{"n":12345678901234567890}`, `{"m":1}`, `{"m":1,"n":12345678901234567890}`},
+	}
+	for _, tt := range tests {
+		got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+		if err != nil {
+			t.Errorf("Apply(%s, %s): %v", tt.doc, tt.patch, err)
+			continue
+		}
+		var g, w any
+		_ = json.Unmarshal(got, &g)
+		_ = json.Unmarshal([]byte(tt.want), &w)
+		if !reflect.DeepEqual(g, w) || (tt.doc == `{"n":12345678901234567890}` && string(got) != tt.want) {
+			t.Errorf("Apply(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	if _, err := Apply([]byte(`{"a":1}`), []byte(`["a"]`)); !errors.Is(err, ErrNotObject) {
+		t.Errorf("array patch: %v, want ErrNotObject", err)
+	}
+	for _, patch := range []string{`{"a":`, `{"a":1} {"b":2}`, ``} {
+		if _, err := Apply([]byte(`{}`), []byte(patch)); err == nil {
+			t.Errorf("Apply(%q): expected an error", patch)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package metrics keeps counters and gauges for pylon's long-running
+// commands and serves them in the Prometheus text format, so a scraper can
+// alert when a loop stops making progress.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds metric families. The zero value is ready to use, and a
+// Registry is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// Default is the registry pylon's commands record into.
+var Default = &Registry{}
+
+type family struct {
+	name, help, kind string
+	labels           []string
+	samples          map[string]*sample // by joined label values
+}
+
+type sample struct {
+	values []string
+	value  float64
+}
+
+// Counter is a family of counters, one per combination of label values.
+type Counter struct {
+	r *Registry
+	f *family
+}
+
+// Gauge is a family of gauges, one per combination of label values.
+type Gauge struct {
+	r *Registry
+	f *family
+}
+
+// Counter returns the counter family called name, registering it with its
+// help text and label names the first time.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{r, r.register(name, help, "counter", labels)}
+}
+
+// Gauge returns the gauge family called name, registering it with its help
+// text and label names the first time.
+func (r *Registry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r, r.register(name, help, "gauge", labels)}
+}
+
+func (r *Registry) register(name, help, kind string, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.kind != kind || !slices.Equal(f.labels, labels) {
+			panic("metrics: " + name + " registered twice with different types or labels")
+		}
+		return f
+	}
+	if r.families == nil {
+		r.families = map[string]*family{}
+	}
+	f := &family{name: name, help: help, kind: kind, labels: labels, samples: map[string]*sample{}}
+	r.families[name] = f
+	return f
+}
+
+// Inc adds one to the counter for the label values.
+func (c *Counter) Inc(values ...string) { c.Add(1, values...) }
+
+// Add adds v, which must not be negative, to the counter for the label
+// values.
+func (c *Counter) Add(v float64, values ...string) {
+	if v < 0 {
+		panic("metrics: counter " + c.f.name + " decreased")
+	}
+	c.r.update(c.f, values, func(s *sample) { s.value += v })
+}
+
+// Set sets the gauge for the label values to v.
+func (g *Gauge) Set(v float64, values ...string) {
+	g.r.update(g.f, values, func(s *sample) { s.value = v })
+}
+
+func (r *Registry) update(f *family, values []string, fn func(*sample)) {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := f.samples[key]
+	if !ok {
+		s = &sample{values: slices.Clone(values)}
+		f.samples[key] = s
+	}
+	fn(s)
+}
+
+// Write writes every metric to w in the Prometheus text exposition format,
+// families sorted by name and samples by label values. Families without
+// samples are left out.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := r.families[name]
+		if len(f.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+		keys := make([]string, 0, len(f.samples))
+		for k := range f.samples {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			s := f.samples[k]
+			bw.WriteString(f.name)
+			if len(f.labels) > 0 {
+				pairs := make([]string, len(f.labels))
+				for i, l := range f.labels {
+					pairs[i] = l + `="` + escapeLabel(s.values[i]) + `"`
+				}
+				bw.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+			bw.WriteString(" " + formatValue(s.value) + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry's metrics, for a /metrics endpoint.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.Write(w)
+	})
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	r := &Registry{}
+	reqs := r.Counter("pylon_api_requests_total", "API requests by host and status code.", "host", "code")
+	reqs.Inc("discord.com", "200")
+	reqs.Inc("discord.com", "200")
+	reqs.Inc("cal.example.com", "429")
+	reqs.Add(0.5, "cal.example.com", "429")
+	r.Gauge("pylon_job_last_success_timestamp_seconds", "When each job last succeeded.", "job").Set(1760600000, "remind")
+	r.Gauge("pylon_up", "Always 1.").Set(1)
+	r.Counter("pylon_unused_total", "Never recorded.")
+	r.Gauge("pylon_escaped", "Line one\nline two.", "text").Set(-2, `say "hi"\`)
+
+	var sb strings.Builder
+	if err := r.Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP pylon_api_requests_total API requests by host and status code.
+# TYPE pylon_api_requests_total counter
+pylon_api_requests_total{host="cal.example.com",code="429"} 1.5
+pylon_api_requests_total{host="discord.com",code="200"} 2
+# HELP pylon_escaped Line one\nline two.
+# TYPE pylon_escaped gauge
+pylon_escaped{text="say \"hi\"\\"} -2
+# HELP pylon_job_last_success_timestamp_seconds When each job last succeeded.
+# TYPE pylon_job_last_success_timestamp_seconds gauge
+pylon_job_last_success_timestamp_seconds{job="remind"} 1.7606e+09
+# HELP pylon_up Always 1.
+# TYPE pylon_up gauge
+pylon_up 1
+`
+	if got := sb.String(); got != want {
+		t.Errorf("Write =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	r := &Registry{}
+	r.Counter("runs_total", "Runs.", "job").Inc("remind")
+	r.Counter("runs_total", "Runs.", "job").Inc("remind")
+	var sb strings.Builder
+	_ = r.Write(&sb)
+	if !strings.Contains(sb.String(), `runs_total{job="remind"} 2`) {
+		t.Errorf("a family registered twice should share its samples:\n%s", sb.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a counter again as a gauge should panic")
+		}
+	}()
+	r.Gauge("runs_total", "Runs.", "job")
+}
+
+func TestLabelCount(t *testing.T) {
+	r := &Registry{}
+	c := r.Counter("runs_total", "Runs.", "job", "result")
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc with too few label values should panic")
+		}
+	}()
+	c.Inc("remind")
+}
+
+func TestHandler(t *testing.T) {
+	r := &Registry{}
+	r.Counter("pylon_notifications_sent_total", "Notifications sent.", "kind").Inc("reminder")
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(string(body), `pylon_notifications_sent_total{kind="reminder"} 1`) {
+		t.Errorf("body =\n%s", body)
+	}
+}
@@ -0,0 +1,122 @@
+// Package output sends a command's output to a file instead of the
+// terminal. Two writers are provided: Atomic replaces the file in one step
+// when the command finishes, so readers never see a half-written capture,
+// and Append adds to the end of the file as output is produced, for
+// follow-style commands whose NDJSON records should land as they happen.
+package output
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Writer is the destination of a command's output. Exactly one of Commit
+// or Abort should be called once the command is done.
+type Writer interface {
+	// File returns the file output is written to, suitable for use as
+	// os.Stdout.
+	File() *os.File
+	// Commit makes the output final.
+	Commit() error
+	// Abort discards what an atomic writer has collected. Output an append
+	// writer has already written stays in place.
+	Abort() error
+}
+
+// Open returns an Append writer for path if appending, otherwise an Atomic
+// one.
+func Open(path string, appending bool) (Writer, error) {
+	if appending {
+		return Append(path)
+	}
+	return Atomic(path)
+}
+
+type atomicWriter struct {
+	f    *os.File
+	path string
+}
+
+// Atomic returns a writer that collects output in a temporary file next to
+// path and renames it over path on Commit. Until then path is untouched,
+// and Abort leaves it as it was.
+func Atomic(path string) (Writer, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicWriter{f: f, path: path}, nil
+}
+
+func (w *atomicWriter) File() *os.File { return w.f }
+
+func (w *atomicWriter) Commit() error {
+	// CreateTemp makes the file private; keep the mode of the file being
+	// replaced, or use the usual one for a new file.
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(w.path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	err := errors.Join(w.f.Chmod(mode), w.f.Sync(), w.f.Close())
+	if err == nil {
+		err = os.Rename(w.f.Name(), w.path)
+	}
+	if err != nil {
+		os.Remove(w.f.Name())
+	}
+	return err
+}
+
+func (w *atomicWriter) Abort() error {
+	w.f.Close()
+	return os.Remove(w.f.Name())
+}
+
+type appendWriter struct {
+	f *os.File
+}
+
+// Append returns a writer that adds output to the end of path, creating it
+// if needed. If the file doesn't end in a newline, one is added first so
+// the next record starts on a line of its own.
+func Append(path string) (Writer, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := terminateLine(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &appendWriter{f: f}, nil
+}
+
+// terminateLine writes a newline to f if it is non-empty and doesn't
+// already end with one.
+func terminateLine(f *os.File) error {
+	fi, err := f.Stat()
+	if err != nil || fi.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, fi.Size()-1); err != nil && err != io.EOF {
+		return err
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	_, err = f.Write([]byte("\n"))
+	return err
+}
+
+func (w *appendWriter) File() *os.File { return w.f }
+
+func (w *appendWriter) Commit() error {
+	return errors.Join(w.f.Sync(), w.f.Close())
+}
+
+func (w *appendWriter) Abort() error {
+	return w.f.Close()
+}
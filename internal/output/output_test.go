@@ -0,0 +1,127 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestAtomicCommit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := Atomic(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.File().WriteString("new\n")
+	if got := readFile(t, path); got != "old\n" {
+		t.Errorf("before Commit: %q, want old content", got)
+	}
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "new\n" {
+		t.Errorf("after Commit: %q, want %q", got, "new\n")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want the replaced file's 0600", fi.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("%d files left in dir, want 1", len(entries))
+	}
+}
+
+func TestAtomicAbort(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := Atomic(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.File().WriteString("partial")
+	if err := w.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "old\n" {
+		t.Errorf("after Abort: %q, want old content", got)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("%d files left in dir, want 1", len(entries))
+	}
+}
+
+func TestAtomicNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	w, err := Open(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.File().WriteString("x\n")
+	if err := w.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o644 {
+		t.Errorf("mode = %v, want 0644", fi.Mode().Perm())
+	}
+}
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // "" means no file
+		want     string
+	}{
+		{"new file", "", `{"n":1}` + "\n"},
+		{"after complete line", `{"n":0}` + "\n", `{"n":0}` + "\n" + `{"n":1}` + "\n"},
+		{"after cut-off line", `{"n":0`, `{"n":0` + "\n" + `{"n":1}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.ndjson")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w, err := Open(path, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.File().WriteString(`{"n":1}` + "\n")
+			// Appended output is visible before Commit, and kept by Abort.
+			if err := w.Abort(); err != nil {
+				t.Fatal(err)
+			}
+			if got := readFile(t, path); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Porcelain writes one line describing a created or changed object in a
+// format scripts can rely on across versions:
+//
+//	<kind> <key>=<value> <key>=<value> ...
+//
+// Keys keep their names and order; new keys are only ever added at the end.
+// A value containing whitespace, quotes, backslashes or control characters
+// is written as a Go double-quoted string, and an empty value as nothing
+// after the "=". pairs alternates keys and values.
+func Porcelain(w io.Writer, kind string, pairs ...string) error {
+	var b strings.Builder
+	b.WriteString(kind)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		b.WriteByte(' ')
+		b.WriteString(pairs[i])
+		b.WriteByte('=')
+		b.WriteString(porcelainValue(pairs[i+1]))
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func porcelainValue(v string) string {
+	if strings.ContainsAny(v, " \t\"\\") || !strconv.CanBackquote(v) {
+		return strconv.Quote(v)
+	}
+	return v
+}
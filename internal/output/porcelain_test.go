@@ -0,0 +1,52 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPorcelain(t *testing.T) {
+	tests := []struct {
+		name  string
+		kind  string
+		pairs []string
+		want  string
+	}{
+		{
+			name:  "plain values",
+			kind:  "feed",
+			pairs: []string{"id", "3f2a", "url", "https://cal.example.com/abc.ics"},
+			want:  "feed id=3f2a url=https://cal.example.com/abc.ics\n",
+		},
+		{
+			name:  "empty value",
+			kind:  "event",
+			pairs: []string{"id", "7c1e", "external_id", ""},
+			want:  "event id=7c1e external_id=\n",
+		},
+		{
+			name:  "quoted values",
+			kind:  "feed",
+			pairs: []string{"name", "Team Events", "note", `say "hi"`, "tab", "a\tb", "nl", "a\nb"},
+			want:  `feed name="Team Events" note="say \"hi\"" tab="a\tb" nl="a\nb"` + "\n",
+		},
+		{
+			name:  "odd pair dropped",
+			kind:  "event",
+			pairs: []string{"id", "7c1e", "dangling"},
+			want:  "event id=7c1e\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := Porcelain(&b, tt.kind, tt.pairs...); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
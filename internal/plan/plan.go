@@ -0,0 +1,156 @@
+// Package plan separates deciding what a reconciliation job would change
+// from changing it, so the changes can be reviewed (--plan) before they
+// are made (--apply).
+package plan
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Action is what a change does to a resource.
+type Action int
+
+const (
+	Create Action = iota
+	Update
+	Delete
+)
+
+func (a Action) String() string {
+	return [...]string{Create: "create", Update: "update", Delete: "delete"}[a]
+}
+
+var symbols = [...]string{Create: "+", Update: "~", Delete: "-"}
+
+// ANSI colors per action: green, yellow, red.
+var colors = [...]string{Create: "\x1b[32m", Update: "\x1b[33m", Delete: "\x1b[31m"}
+
+const reset = "\x1b[0m"
+
+// Diff is one changed field of an updated resource.
+type Diff struct {
+	Field    string
+	Old, New string
+}
+
+// Change is one pending change and the function that makes it.
+type Change struct {
+	Action Action
+	Kind   string // e.g. "mirror"
+	ID     string
+	Title  string // human-readable name, e.g. the event summary
+	Reason string // why, if not obvious from the diffs
+	Diffs  []Diff
+	Apply  func() error
+}
+
+// Plan is an ordered list of changes.
+type Plan struct {
+	Changes []Change
+}
+
+// Add appends a change.
+func (p *Plan) Add(c Change) {
+	p.Changes = append(p.Changes, c)
+}
+
+// Counts returns the number of changes of each action.
+func (p *Plan) Counts() (create, update, del int) {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case Create:
+			create++
+		case Update:
+			update++
+		case Delete:
+			del++
+		}
+	}
+	return create, update, del
+}
+
+// Write prints the plan as a diff: one line per change, prefixed +, ~ or -,
+// with the changed fields of updates underneath.
+func (p *Plan) Write(w io.Writer, color bool) error {
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		start, end := "", ""
+		if color {
+			start, end = colors[c.Action], reset
+		}
+		fmt.Fprintf(&sb, "%s%s %s %s", start, symbols[c.Action], c.Kind, c.ID)
+		if c.Title != "" {
+			fmt.Fprintf(&sb, " %q", c.Title)
+		}
+		if c.Reason != "" {
+			fmt.Fprintf(&sb, " (%s)", c.Reason)
+		}
+		sb.WriteString(end + "\n")
+		for _, d := range c.Diffs {
+			fmt.Fprintf(&sb, "    %s: %q → %q\n", d.Field, d.Old, d.New)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Apply makes each change in order, calling done after each with its
+// result, and returns how many succeeded and failed. A failure doesn't stop
+// the remaining changes.
+func (p *Plan) Apply(done func(Change, error)) (applied, failed int) {
+	for _, c := range p.Changes {
+		err := c.Apply()
+		if err != nil {
+			failed++
+		} else {
+			applied++
+		}
+		if done != nil {
+			done(c, err)
+		}
+	}
+	return applied, failed
+}
+
+// Fields compares two structs of the same type field by field and returns
+// the fields that differ, named by their JSON tags. Pointers and slices are
+// compared by what they hold.
+func Fields(before, after any) []Diff {
+	a, b := reflect.Indirect(reflect.ValueOf(before)), reflect.Indirect(reflect.ValueOf(after))
+	if a.Type() != b.Type() || a.Kind() != reflect.Struct {
+		panic("plan.Fields: want two structs of the same type")
+	}
+	var diffs []Diff
+	for i := 0; i < a.NumField(); i++ {
+		f := a.Type().Field(i)
+		if !f.IsExported() || reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			name = f.Name
+		}
+		diffs = append(diffs, Diff{Field: name, Old: format(a.Field(i)), New: format(b.Field(i))})
+	}
+	return diffs
+}
+
+func format(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = format(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,91 @@
+package plan
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type event struct {
+	Summary string   `json:"summary"`
+	End     *string  `json:"end,omitempty"`
+	ExDates []string `json:"exdates,omitempty"`
+	Notes   string
+	hidden  string
+}
+
+func TestFields(t *testing.T) {
+	end := "2026-03-01T10:00:00Z"
+	before := event{Summary: "Standup", ExDates: []string{"a"}, hidden: "x"}
+	after := event{Summary: "Daily standup", End: &end, ExDates: []string{"a", "b"}, Notes: "n", hidden: "y"}
+
+	got := Fields(before, &after)
+	want := []Diff{
+		{"summary", "Standup", "Daily standup"},
+		{"end", "", end},
+		{"exdates", "a", "a,b"},
+		{"Notes", "", "n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields =\n%+v\nwant\n%+v", got, want)
+	}
+	if d := Fields(before, before); d != nil {
+		t.Errorf("Fields(same) = %+v, want nil", d)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	p := &Plan{}
+	p.Add(Change{Action: Create, Kind: "mirror", ID: "m1", Title: "Launch"})
+	p.Add(Change{Action: Update, Kind: "mirror", ID: "m2", Title: "Standup", Diffs: []Diff{{"summary", "Standup", "Daily standup"}}})
+	p.Add(Change{Action: Delete, Kind: "mirror", ID: "m3", Reason: "source deleted"})
+
+	var sb strings.Builder
+	if err := p.Write(&sb, false); err != nil {
+		t.Fatal(err)
+	}
+	want := `+ mirror m1 "Launch"
+~ mirror m2 "Standup"
+    summary: "Standup" → "Daily standup"
+- mirror m3 (source deleted)
+`
+	if sb.String() != want {
+		t.Errorf("Write =\n%s\nwant\n%s", sb.String(), want)
+	}
+
+	sb.Reset()
+	_ = p.Write(&sb, true)
+	if !strings.Contains(sb.String(), "\x1b[31m- mirror m3 (source deleted)\x1b[0m\n") {
+		t.Errorf("colored output missing red delete line:\n%q", sb.String())
+	}
+
+	if c, u, d := p.Counts(); c != 1 || u != 1 || d != 1 {
+		t.Errorf("Counts = %d, %d, %d", c, u, d)
+	}
+}
+
+func TestApply(t *testing.T) {
+	var order []string
+	ok := func(id string) func() error {
+		return func() error { order = append(order, id); return nil }
+	}
+	p := &Plan{Changes: []Change{
+		{ID: "a", Apply: ok("a")},
+		{ID: "b", Apply: func() error { return errors.New("boom") }},
+		{ID: "c", Apply: ok("c")},
+	}}
+
+	var failedIDs []string
+	applied, failed := p.Apply(func(c Change, err error) {
+		if err != nil {
+			failedIDs = append(failedIDs, c.ID)
+		}
+	})
+	if applied != 2 || failed != 1 {
+		t.Errorf("Apply = %d applied, %d failed", applied, failed)
+	}
+	if !reflect.DeepEqual(order, []string{"a", "c"}) || !reflect.DeepEqual(failedIDs, []string{"b"}) {
+		t.Errorf("order %v, failed %v", order, failedIDs)
+	}
+}
@@ -0,0 +1,19 @@
+package platform
+
+import "os"
+
+// Color reports whether ANSI colors should be written to f: it must be a
+// terminal that understands them, and NO_COLOR (https://no-color.org) must
+// be unset.
+func Color(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return Terminal(f) && enableANSI(f)
+}
+
+// Terminal reports whether f is a terminal rather than a file, pipe or
+// other device such as /dev/null.
+func Terminal(f *os.File) bool {
+	return isTerminal(f)
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package platform
+
+import "os"
+
+func enableANSI(*os.File) bool { return true }
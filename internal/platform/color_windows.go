@@ -0,0 +1,25 @@
+package platform
+
+import (
+	"os"
+	"syscall"
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+// enableANSI turns on escape sequence processing for a console. Consoles
+// older than Windows 10 don't support it and get plain output.
+func enableANSI(f *os.File) bool {
+	h := syscall.Handle(f.Fd())
+	var mode uint32
+	if err := syscall.GetConsoleMode(h, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := setConsoleMode.Call(uintptr(h), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
@@ -0,0 +1,74 @@
+// Package platform wraps the operating-system specific parts of pylon:
+// handing URLs to the desktop, the clipboard, and file URLs. The per-OS
+// commands live in build-tagged files.
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnavailable is returned when the system has no tool for an action,
+// e.g. no clipboard utility on a headless Linux box.
+var ErrUnavailable = errors.New("not available on this system")
+
+// OpenURL hands u to the desktop's default handler: a browser for https,
+// the calendar app for webcal.
+func OpenURL(u string) error {
+	name, args := openCommand(u)
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("open: %w", ErrUnavailable)
+	}
+	// Start rather than Run: some openers stay attached to the handler.
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// Copy puts text on the system clipboard.
+func Copy(text string) error {
+	for _, c := range copyCommands() {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("copy: %s: %v %s", c[0], err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return fmt.Errorf("copy: %w", ErrUnavailable)
+}
+
+// FilePath returns the local path named by a file:// URL, so the same
+// argument works on every platform: file:///home/me/cal.ics,
+// file:///C:/Users/me/cal.ics and, on Windows, file://server/share/cal.ics.
+func FilePath(fileURL string) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("%s: not a file URL", fileURL)
+	}
+	p := u.Path
+	switch {
+	case u.Host != "" && u.Host != "localhost":
+		// A UNC share; only Windows has a path for it.
+		p = "//" + u.Host + p
+		if filepath.VolumeName(p) == "" {
+			return "", fmt.Errorf("%s: remote file URLs are not supported", fileURL)
+		}
+	case filepath.VolumeName(strings.TrimPrefix(p, "/")) != "":
+		// "/C:/Users/..." on Windows.
+		p = strings.TrimPrefix(p, "/")
+	}
+	return filepath.FromSlash(p), nil
+}
@@ -0,0 +1,9 @@
+package platform
+
+func openCommand(u string) (string, []string) {
+	return "open", []string{u}
+}
+
+func copyCommands() [][]string {
+	return [][]string{{"pbcopy"}}
+}
@@ -0,0 +1,16 @@
+//go:build !windows && !darwin
+
+package platform
+
+func openCommand(u string) (string, []string) {
+	return "xdg-open", []string{u}
+}
+
+// copyCommands lists the clipboard tools to try, Wayland first.
+func copyCommands() [][]string {
+	return [][]string{
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+}
@@ -0,0 +1,98 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestFilePath(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		windows bool // expected result only holds on Windows
+		wantErr bool
+	}{
+		{url: "file:///home/me/cal.ics", want: "/home/me/cal.ics"},
+		{url: "file://localhost/tmp/a%20b.ics", want: "/tmp/a b.ics"},
+		{url: "file:///C:/Users/me/cal.ics", want: `C:\Users\me\cal.ics`, windows: true},
+		{url: "file://server/share/cal.ics", want: `\\server\share\cal.ics`, windows: true},
+		{url: "https://example.com/cal.ics", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if tt.windows != (runtime.GOOS == "windows") && !tt.wantErr {
+				t.Skip("path form differs on this platform")
+			}
+			got, err := FilePath(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("FilePath(%q) = %q, want error", tt.url, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FilePath(%q): %v", tt.url, err)
+			}
+			if want := filepath.FromSlash(tt.want); got != want {
+				t.Errorf("FilePath(%q) = %q, want %q", tt.url, got, want)
+			}
+		})
+	}
+}
+
+func TestFilePathRemoteUnsupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows maps remote file URLs to UNC paths")
+	}
+	if _, err := FilePath("file://server/share/cal.ics"); err == nil {
+		t.Error("expected an error for a remote file URL")
+	}
+}
+
+func TestCopyUnavailable(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if err := Copy("x"); err == nil {
+		t.Error("expected an error with no clipboard tool on PATH")
+	}
+}
+
+func TestColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if Color(f) {
+		t.Error("Color(regular file) = true")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if Color(os.Stdout) {
+		t.Error("Color with NO_COLOR set = true")
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	// /dev/null is a character device but not a terminal; a prompt reading
+	// from it would only see EOF.
+	null, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+	if Terminal(null) {
+		t.Errorf("Terminal(%s) = true", os.DevNull)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if Terminal(r) {
+		t.Error("Terminal(pipe) = true")
+	}
+}
@@ -0,0 +1,12 @@
+package platform
+
+func openCommand(u string) (string, []string) {
+	// Unlike "cmd /c start", rundll32 doesn't reinterpret & and ^ in the URL.
+	return "rundll32", []string{"url.dll,FileProtocolHandler", u}
+}
+
+func copyCommands() [][]string {
+	// clip.exe reads the console code page; pylon only copies URLs, which
+	// are ASCII.
+	return [][]string{{"clip"}}
+}
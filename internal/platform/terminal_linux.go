@@ -0,0 +1,5 @@
+package platform
+
+import "syscall"
+
+const ioctlGetTermios = syscall.TCGETS
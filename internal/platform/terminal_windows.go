@@ -0,0 +1,13 @@
+package platform
+
+import (
+	"os"
+	"syscall"
+)
+
+// isTerminal reports whether f is a console, the only handle with a
+// console mode.
+func isTerminal(f *os.File) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(f.Fd()), &mode) == nil
+}
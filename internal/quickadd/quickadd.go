@@ -0,0 +1,403 @@
+// Package quickadd parses one-line event descriptions such as
+// "Dentist Tuesday 9am-10am at Main St Clinic #health", in the spirit of
+// Google Calendar's quick add.
+//
+// A description is split into words. Words that name a date ("today",
+// "tomorrow", a weekday, "Mar 5", "2026-03-05"), a time or time range
+// ("9am", "9:30-10:15pm", "from 9 to 11", "noon"), a length ("for 45m"),
+// a location ("at Main St Clinic") or a category ("#health") are taken out;
+// what is left is the summary.
+package quickadd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// DefaultDuration is the length of a timed event given without an end time
+// or length.
+const DefaultDuration = time.Hour
+
+// Event is a parsed description.
+type Event struct {
+	Summary    string
+	Start      time.Time
+	End        time.Time // zero for all-day events
+	AllDay     bool
+	Location   string
+	Categories []string
+}
+
+// Parse parses a description relative to now, in now's location. Without a
+// date the event is today, or tomorrow if its start time has passed;
+// without a time it is an all-day event. Weekdays and month-day dates mean
+// the next such day, today included.
+func Parse(s string, now time.Time) (*Event, error) {
+	p := &parser{words: strings.Fields(s), now: now}
+	p.parse()
+	return p.event()
+}
+
+type parser struct {
+	words []string
+	now   time.Time
+
+	summary    []string
+	location   []string
+	categories []string
+
+	date     time.Time // midnight, zero if not given
+	start    *clock
+	end      *clock
+	duration time.Duration
+}
+
+func (p *parser) parse() {
+	for i := 0; i < len(p.words); {
+		w := p.words[i]
+		if tag, ok := category(w); ok {
+			p.categories = append(p.categories, tag)
+			i++
+			continue
+		}
+		if n, ok := p.dateAt(i); ok {
+			i += n
+			continue
+		}
+		if n, ok := p.timeAt(i); ok {
+			i += n
+			continue
+		}
+		if n, ok := p.durationAt(i); ok {
+			i += n
+			continue
+		}
+		if strings.EqualFold(w, "at") && i+1 < len(p.words) && p.location == nil {
+			i++
+			for i < len(p.words) && !p.special(i) {
+				p.location = append(p.location, p.words[i])
+				i++
+			}
+			continue
+		}
+		p.summary = append(p.summary, w)
+		i++
+	}
+}
+
+// special reports whether the word at i starts a date, time, length or
+// category, which ends a location.
+func (p *parser) special(i int) bool {
+	if _, ok := category(p.words[i]); ok {
+		return true
+	}
+	saved := *p
+	defer func() { *p = saved }()
+	if _, ok := p.dateAt(i); ok {
+		return true
+	}
+	if _, ok := p.timeAt(i); ok {
+		return true
+	}
+	_, ok := p.durationAt(i)
+	return ok
+}
+
+func (p *parser) event() (*Event, error) {
+	summary := strings.TrimRight(strings.Join(p.summary, " "), ",;:- ")
+	if summary == "" {
+		return nil, errors.New("no summary: say what the event is")
+	}
+	if p.date.IsZero() && p.start == nil {
+		return nil, errors.New("no date or time: add e.g. tomorrow, Friday or 9am")
+	}
+	if p.duration > 0 && p.start == nil {
+		return nil, errors.New("a length needs a start time")
+	}
+
+	e := &Event{
+		Summary:    summary,
+		Location:   strings.TrimRight(strings.Join(p.location, " "), ",;:"),
+		Categories: p.categories,
+	}
+	loc := p.now.Location()
+	date := p.date
+	if p.start == nil {
+		e.AllDay = true
+		e.Start = date
+		return e, nil
+	}
+
+	if date.IsZero() {
+		date = timeutil.StartOfDay(p.now, loc)
+		if p.start.at(date).Before(p.now) {
+			date = timeutil.AddDays(date, 1)
+		}
+	}
+	e.Start = p.start.at(date)
+	switch {
+	case p.end != nil:
+		e.End = p.end.at(date)
+		if !e.End.After(e.Start) {
+			e.End = p.end.at(timeutil.AddDays(date, 1))
+		}
+	case p.duration > 0:
+		e.End = timeutil.AddNominal(e.Start, p.duration)
+	default:
+		e.End = e.Start.Add(DefaultDuration)
+	}
+	return e, nil
+}
+
+// category returns the tag of a "#tag" word.
+func category(w string) (string, bool) {
+	tag := strings.TrimRight(strings.TrimPrefix(w, "#"), ",;")
+	if !strings.HasPrefix(w, "#") || tag == "" {
+		return "", false
+	}
+	return tag, true
+}
+
+// word returns the word at i lowercased and without trailing punctuation,
+// or "" past the end.
+func (p *parser) word(i int) string {
+	if i >= len(p.words) {
+		return ""
+	}
+	return strings.ToLower(strings.TrimRight(p.words[i], ",;"))
+}
+
+// dateAt parses a date starting at word i and returns the number of words
+// it took.
+func (p *parser) dateAt(i int) (int, bool) {
+	if !p.date.IsZero() {
+		return 0, false
+	}
+	j := i
+	if p.word(j) == "on" {
+		j++
+	}
+	next := false
+	if p.word(j) == "next" {
+		next = true
+		j++
+	}
+
+	loc := p.now.Location()
+	today := timeutil.StartOfDay(p.now, loc)
+	w := p.word(j)
+	switch {
+	case w == "":
+		return 0, false
+	case !next && (w == "today" || w == "tonight"):
+		p.date = today
+		return j - i + 1, true
+	case !next && w == "tomorrow":
+		p.date = timeutil.AddDays(today, 1)
+		return j - i + 1, true
+	}
+	if wd, ok := weekday(w); ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if next && days == 0 {
+			days = 7
+		}
+		p.date = timeutil.AddDays(today, days)
+		return j - i + 1, true
+	}
+	if next {
+		return 0, false
+	}
+	if t, err := time.ParseInLocation("2006-01-02", w, loc); err == nil {
+		p.date = t
+		return j - i + 1, true
+	}
+	// "Mar 5" or "5 March"
+	m, mok := month(w)
+	d, dok := dayOfMonth(p.word(j + 1))
+	if !mok {
+		d, dok = dayOfMonth(w)
+		m, mok = month(p.word(j + 1))
+	}
+	if !mok || !dok {
+		return 0, false
+	}
+	t := time.Date(today.Year(), m, d, 0, 0, 0, 0, loc)
+	if t.Month() != m {
+		return 0, false // e.g. Feb 30
+	}
+	if t.Before(today) {
+		t = time.Date(today.Year()+1, m, d, 0, 0, 0, 0, loc)
+	}
+	p.date = t
+	return j - i + 2, true
+}
+
+// timeAt parses a time or time range starting at word i and returns the
+// number of words it took. A bare hour such as "9" only counts after "at"
+// or "from", or as part of a range, so numbers in the summary are left
+// alone.
+func (p *parser) timeAt(i int) (int, bool) {
+	if p.start != nil {
+		return 0, false
+	}
+	j := i
+	explicit := false
+	if w := p.word(j); (w == "at" || w == "from" || w == "@") && j+1 < len(p.words) {
+		explicit = true
+		j++
+	}
+
+	w := p.word(j)
+	for _, sep := range []string{"-", "–"} {
+		if a, b, ok := strings.Cut(w, sep); ok && a != "" && b != "" {
+			start, sok := parseClock(a, true)
+			end, eok := parseClock(b, true)
+			if !sok || !eok {
+				return 0, false
+			}
+			p.setRange(start, end)
+			return j - i + 1, true
+		}
+	}
+
+	start, ok := parseClock(w, explicit)
+	if !ok {
+		return 0, false
+	}
+	j++
+	switch p.word(j) {
+	case "-", "–", "to", "until", "till":
+		if end, ok := parseClock(p.word(j+1), true); ok {
+			p.setRange(start, end)
+			return j - i + 2, true
+		}
+	}
+	p.start = &start
+	return j - i, true
+}
+
+// setRange records a time range. A start without am/pm takes the end's,
+// unless that would put it after the end ("11-1pm" is 11am to 1pm).
+func (p *parser) setRange(start, end clock) {
+	if start.meridiem == "" && end.meridiem != "" {
+		start.meridiem = end.meridiem
+		if start.minutes() > end.minutes() {
+			start.meridiem = "am"
+		}
+	}
+	p.start, p.end = &start, &end
+}
+
+// durationAt parses "for <length>" at word i.
+func (p *parser) durationAt(i int) (int, bool) {
+	if p.duration > 0 || p.end != nil || p.word(i) != "for" {
+		return 0, false
+	}
+	d, err := timeutil.ParseDuration(p.word(i + 1))
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	p.duration = d
+	return 2, true
+}
+
+// clock is a time of day as written, with meridiem "am", "pm" or "" for a
+// 24-hour time.
+type clock struct {
+	hour, minute int
+	meridiem     string
+}
+
+// parseClock parses "9am", "9:30pm", "14:00", "noon" or "midnight", and a
+// bare hour such as "9" if bare is set.
+func parseClock(s string, bare bool) (clock, bool) {
+	switch s {
+	case "noon":
+		return clock{hour: 12, meridiem: "pm"}, true
+	case "midnight":
+		return clock{hour: 12, meridiem: "am"}, true
+	}
+	var c clock
+	for _, m := range []string{"am", "pm", "a.m.", "p.m."} {
+		if strings.HasSuffix(s, m) {
+			c.meridiem = m[:1] + "m"
+			s = strings.TrimSuffix(s, m)
+			break
+		}
+	}
+	hs, ms, hasMinutes := strings.Cut(s, ":")
+	if c.meridiem == "" && !hasMinutes && !bare {
+		return clock{}, false
+	}
+	h, err := strconv.Atoi(hs)
+	if err != nil || len(hs) > 2 {
+		return clock{}, false
+	}
+	c.hour = h
+	if hasMinutes {
+		m, err := strconv.Atoi(ms)
+		if err != nil || len(ms) != 2 || m > 59 {
+			return clock{}, false
+		}
+		c.minute = m
+	}
+	if c.meridiem != "" && (h < 1 || h > 12) || h > 23 {
+		return clock{}, false
+	}
+	return c, true
+}
+
+// minutes returns the number of minutes after midnight.
+func (c clock) minutes() int {
+	h := c.hour
+	switch {
+	case c.meridiem == "am" && h == 12:
+		h = 0
+	case c.meridiem == "pm" && h < 12:
+		h += 12
+	}
+	return h*60 + c.minute
+}
+
+// at returns the clock time on the day starting at midnight.
+func (c clock) at(midnight time.Time) time.Time {
+	y, m, d := midnight.Date()
+	mins := c.minutes()
+	return time.Date(y, m, d, mins/60, mins%60, 0, 0, midnight.Location())
+}
+
+func weekday(w string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if w == name || w == name[:3] || len(w) >= 3 && strings.HasPrefix(name, w) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func month(w string) (time.Month, bool) {
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		if len(w) >= 3 && strings.HasPrefix(name, w) || w == "sept" && m == time.September {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// dayOfMonth parses "5", "05" or "5th".
+func dayOfMonth(w string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		w = strings.TrimSuffix(w, suffix)
+	}
+	d, err := strconv.Atoi(w)
+	if err != nil || d < 1 || d > 31 || len(w) > 2 {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,98 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Monday 2 March 2026, 08:00.
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input string
+		want  Event
+	}{
+		{
+			input: "Dentist Tuesday 9am-10am at Main St Clinic #health",
+			want: Event{Summary: "Dentist", Start: at(3, 9, 0), End: at(3, 10, 0),
+				Location: "Main St Clinic", Categories: []string{"health"}},
+		},
+		{
+			input: "Standup 9:30",
+			want:  Event{Summary: "Standup", Start: at(2, 9, 30), End: at(2, 10, 30)},
+		},
+		{
+			input: "Breakfast 7am",
+			want:  Event{Summary: "Breakfast", Start: at(3, 7, 0), End: at(3, 8, 0)},
+		},
+		{
+			input: "Lunch with Sam tomorrow at noon for 90m",
+			want:  Event{Summary: "Lunch with Sam", Start: at(3, 12, 0), End: at(3, 13, 30)},
+		},
+		{
+			input: "Review 11-1pm on Friday",
+			want:  Event{Summary: "Review", Start: at(6, 11, 0), End: at(6, 13, 0)},
+		},
+		{
+			input: "Release party Mar 20 from 6 to 9pm at The Pier #team #fun",
+			want: Event{Summary: "Release party", Start: at(20, 18, 0), End: at(20, 21, 0),
+				Location: "The Pier", Categories: []string{"team", "fun"}},
+		},
+		{
+			input: "Night shift today 10pm-6am",
+			want:  Event{Summary: "Night shift", Start: at(2, 22, 0), End: at(3, 6, 0)},
+		},
+		{
+			input: "Offsite next Monday",
+			want:  Event{Summary: "Offsite", Start: at(9, 0, 0), AllDay: true},
+		},
+		{
+			input: "Planning Monday",
+			want:  Event{Summary: "Planning", Start: at(2, 0, 0), AllDay: true},
+		},
+		{
+			input: "Conference 2026-04-14 at Hall 9",
+			want: Event{Summary: "Conference", Start: time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC),
+				AllDay: true, Location: "Hall 9"},
+		},
+		{
+			input: "Ski trip 5 January",
+			want:  Event{Summary: "Ski trip", Start: time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), AllDay: true},
+		},
+		{
+			input: "Call from Bob at 14:15",
+			want:  Event{Summary: "Call from Bob", Start: at(2, 14, 15), End: at(2, 15, 15)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	for _, input := range []string{
+		"",
+		"tomorrow 9am",
+		"Lunch with Sam",
+		"Trip Friday for 2h",
+	} {
+		if _, err := Parse(input, now); err == nil {
+			t.Errorf("Parse(%q): expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package recur
+
+import (
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// Expand returns the occurrences of e that overlap [from, to), at most limit
+// of them, as copies of e with Start, End and Deadline moved by the same
+// number of calendar days. Occurrences keep the series' ID and RRule. Dates
+// in e.ExDates are skipped. A non-recurring event is returned as is,
+// whether or not it falls in the window.
+func Expand(e cal.Event, from, to time.Time, limit int) ([]cal.Event, error) {
+	if e.RRule == "" {
+		return []cal.Event{e}, nil
+	}
+	r, err := Parse(e.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start early enough to catch an occurrence already in progress.
+	var span time.Duration
+	if e.End != nil && e.End.After(e.Start) {
+		span = e.End.Sub(e.Start)
+	}
+	loc := e.Start.Location()
+
+	var out []cal.Event
+	for _, start := range r.Between(e.Start, from.Add(-span), to, 0) {
+		if excluded(e, start) {
+			continue
+		}
+		n := timeutil.DaysBetween(e.Start, start, loc)
+		occ := e
+		occ.Start = start
+		if e.End != nil {
+			end := timeutil.AddDays(*e.End, n)
+			occ.End = &end
+		}
+		if e.Deadline != nil {
+			deadline := timeutil.AddDays(*e.Deadline, n)
+			occ.Deadline = &deadline
+		}
+		if occ.End != nil && !occ.End.After(from) || occ.End == nil && start.Before(from) {
+			continue
+		}
+		out = append(out, occ)
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// ExpandAll expands every event in events over [from, to), capping each
+// series at DefaultLimit occurrences. An event whose rule can't be parsed is
+// kept as its first occurrence only, so a bad rule never hides an event.
+func ExpandAll(events []cal.Event, from, to time.Time) []cal.Event {
+	var out []cal.Event
+	for _, e := range events {
+		occs, err := Expand(e, from, to, DefaultLimit)
+		if err != nil {
+			out = append(out, e)
+			continue
+		}
+		out = append(out, occs...)
+	}
+	return out
+}
+
+// EndsBefore reports whether every occurrence of e starts before cutoff,
+// so that the whole event is in the past. A series without COUNT or UNTIL
+// never ends, and one whose rule can't be parsed is assumed not to either,
+// so commands that delete old events leave them alone.
+func EndsBefore(e cal.Event, cutoff time.Time) bool {
+	if !e.Start.Before(cutoff) {
+		return false
+	}
+	if e.RRule == "" {
+		return true
+	}
+	r, err := Parse(e.RRule)
+	if err != nil || r.Count == 0 && r.Until.IsZero() {
+		return false
+	}
+	ended := true
+	r.each(e.Start, cutoff.AddDate(100, 0, 0), func(t time.Time) bool {
+		if !t.Before(cutoff) && !excluded(e, t) {
+			ended = false
+		}
+		return ended
+	})
+	return ended
+}
+
+// excluded reports whether an occurrence starting at start is listed in
+// e.ExDates. All-day events compare calendar dates only.
+func excluded(e cal.Event, start time.Time) bool {
+	for _, x := range e.ExDates {
+		if e.AllDay {
+			if timeutil.DaysBetween(x, start, start.Location()) == 0 {
+				return true
+			}
+		} else if x.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,393 @@
+// Package recur expands recurring events (RFC 5545 RRULE and EXDATE) into
+// their concrete occurrences within a time window, so every view of a
+// calendar agrees on when a series actually happens.
+//
+// Occurrences keep the wall-clock time of the series start in its location,
+// so a 09:00 weekly meeting stays at 09:00 across DST changes. The rule parts
+// pylon's calendars use are supported: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY),
+// INTERVAL, COUNT, UNTIL, BYDAY (with ordinals such as 1MO or -1FR),
+// BYMONTHDAY, BYMONTH and WKST. Other parts are rejected rather than ignored.
+package recur
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/timeutil"
+)
+
+// DefaultLimit caps how many occurrences a single expansion returns.
+const DefaultLimit = 1000
+
+// maxPeriods bounds the scan for rules that rarely or never match, such as
+// BYMONTH=2;BYMONTHDAY=30, when the window reaches far ahead.
+const maxPeriods = 100000
+
+const day = 24 * time.Hour
+
+// Freq is an RRULE frequency.
+type Freq int
+
+const (
+	Daily Freq = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+// Weekday is a BYDAY entry. N selects the Nth such weekday of the month (or
+// year), counting from the end when negative; 0 means every one.
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed RRULE.
+type Rule struct {
+	Freq       Freq
+	Interval   int
+	Count      int       // 0 means unlimited
+	Until      time.Time // zero means unlimited; inclusive
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+
+	// untilLocal marks an UNTIL without a zone, read in the series' location.
+	untilLocal bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE". A leading
+// "RRULE:" is accepted.
+func Parse(s string) (*Rule, error) {
+	orig := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	fail := func(format string, args ...any) (*Rule, error) {
+		return nil, fmt.Errorf("invalid rrule %q: %s", orig, fmt.Sprintf(format, args...))
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		if !ok || v == "" {
+			return fail("malformed part %q", part)
+		}
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			switch strings.ToUpper(v) {
+			case "DAILY":
+				r.Freq = Daily
+			case "WEEKLY":
+				r.Freq = Weekly
+			case "MONTHLY":
+				r.Freq = Monthly
+			case "YEARLY":
+				r.Freq = Yearly
+			default:
+				return fail("unsupported FREQ %s", v)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return fail("bad INTERVAL %s", v)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return fail("bad COUNT %s", v)
+			}
+			r.Count = n
+		case "UNTIL":
+			t, local, err := parseUntil(v)
+			if err != nil {
+				return fail("bad UNTIL %s", v)
+			}
+			r.Until, r.untilLocal = t, local
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, err := parseWeekday(d)
+				if err != nil {
+					return fail("bad BYDAY %s", d)
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return fail("bad BYMONTHDAY %s", d)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, m := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(m)
+				if err != nil || n < 1 || n > 12 {
+					return fail("bad BYMONTH %s", m)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			wd, ok := weekdays[strings.ToUpper(v)]
+			if !ok {
+				return fail("bad WKST %s", v)
+			}
+			r.WeekStart = wd
+		default:
+			return fail("unsupported part %s", k)
+		}
+	}
+
+	if r.Freq == 0 {
+		return fail("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fail("COUNT and UNTIL are mutually exclusive")
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return fail("BYDAY ordinals need FREQ=MONTHLY or YEARLY")
+		}
+	}
+	return r, nil
+}
+
+func parseUntil(v string) (t time.Time, local bool, err error) {
+	switch {
+	case strings.HasSuffix(v, "Z"):
+		t, err = time.Parse("20060102T150405Z", v)
+	case len(v) == 8:
+		// A date UNTIL includes that whole day.
+		t, err = time.Parse("20060102", v)
+		t, local = t.Add(day-time.Second), true
+	default:
+		t, err = time.Parse("20060102T150405", v)
+		local = true
+	}
+	return t, local, err
+}
+
+func parseWeekday(s string) (Weekday, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return Weekday{}, fmt.Errorf("bad weekday %q", s)
+	}
+	wd, ok := weekdays[s[len(s)-2:]]
+	if !ok {
+		return Weekday{}, fmt.Errorf("bad weekday %q", s)
+	}
+	n := 0
+	if num := s[:len(s)-2]; num != "" {
+		var err error
+		n, err = strconv.Atoi(num)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return Weekday{}, fmt.Errorf("bad weekday %q", s)
+		}
+	}
+	return Weekday{N: n, Day: wd}, nil
+}
+
+// Between returns the start times of the occurrences of a series starting at
+// dtstart that fall in [from, to), in order and at most limit of them (no
+// cap when limit is 0). dtstart itself is always the first occurrence.
+func (r *Rule) Between(dtstart, from, to time.Time, limit int) []time.Time {
+	var out []time.Time
+	r.each(dtstart, to, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+		return limit == 0 || len(out) < limit
+	})
+	return out
+}
+
+// each calls fn with every occurrence before stop in order until fn returns
+// false or the rule ends.
+func (r *Rule) each(dtstart, stop time.Time, fn func(time.Time) bool) {
+	loc := dtstart.Location()
+	until := r.Until
+	if r.untilLocal {
+		until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), 0, loc)
+	}
+	done := func(t time.Time) bool {
+		return !until.IsZero() && t.After(until)
+	}
+
+	if done(dtstart) || !fn(dtstart) {
+		return
+	}
+	n := 1
+	y, m, d := dtstart.Date()
+	first := time.Date(y, m, d, 0, 0, 0, 0, time.UTC) // calendar date of dtstart
+	last := timeutil.DaysBetween(dtstart, stop, loc)
+
+	for k := 0; k < maxPeriods; k++ {
+		begin, dates := r.period(first, k)
+		if int(begin.Sub(first)/day) > last {
+			return
+		}
+		for _, date := range dates {
+			if !date.After(first) {
+				continue
+			}
+			if r.Count > 0 && n >= r.Count {
+				return
+			}
+			t := timeutil.AddDays(dtstart, int(date.Sub(first)/day))
+			if done(t) || !fn(t) {
+				return
+			}
+			n++
+		}
+	}
+}
+
+// period returns the first day of the k-th period after first and its
+// sorted candidate dates, all as midnight UTC.
+func (r *Rule) period(first time.Time, k int) (begin time.Time, dates []time.Time) {
+	step := k * r.Interval
+	switch r.Freq {
+	case Daily:
+		begin = first.AddDate(0, 0, step)
+		if r.inMonths(begin) && r.onMonthDay(begin) && r.onWeekday(begin) {
+			dates = append(dates, begin)
+		}
+	case Weekly:
+		back := (int(first.Weekday()) - int(r.WeekStart) + 7) % 7
+		begin = first.AddDate(0, 0, step*7-back)
+		for i := range 7 {
+			date := begin.AddDate(0, 0, i)
+			if r.inMonths(date) && r.weeklyDay(date, first) {
+				dates = append(dates, date)
+			}
+		}
+	case Monthly:
+		begin = time.Date(first.Year(), first.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if r.inMonths(begin) {
+			dates = r.inRange(begin, begin.AddDate(0, 1, 0), first)
+		}
+	case Yearly:
+		begin = time.Date(first.Year()+step, 1, 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) > 0 {
+			// BYDAY alone in a yearly rule counts weekdays across the year.
+			return begin, r.inRange(begin, begin.AddDate(1, 0, 0), first)
+		}
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{first.Month()}
+		}
+		for _, m := range months {
+			start := time.Date(begin.Year(), m, 1, 0, 0, 0, 0, time.UTC)
+			dates = append(dates, r.inRange(start, start.AddDate(0, 1, 0), first)...)
+		}
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	}
+	return begin, dates
+}
+
+// inRange returns the dates in [start, end) selected by BYMONTHDAY and
+// BYDAY, or the series' day of the month when neither is set. Ordinal
+// weekdays count within the range.
+func (r *Rule) inRange(start, end, first time.Time) []time.Time {
+	var dates []time.Time
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		// Months without the day (the 31st, 29 February) are skipped.
+		date := time.Date(start.Year(), start.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Month() == start.Month() {
+			dates = append(dates, date)
+		}
+		return dates
+	}
+	total := int(end.Sub(start) / day)
+	for i := range total {
+		date := start.AddDate(0, 0, i)
+		if !r.onMonthDay(date) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.onNthWeekday(date, i, total) {
+			continue
+		}
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+func (r *Rule) inMonths(date time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if date.Month() == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) onMonthDay(date time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, d := range r.ByMonthDay {
+		if d == date.Day() || d < 0 && last+1+d == date.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) onWeekday(date time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Day == date.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// weeklyDay reports whether date is selected in a weekly rule: one of BYDAY,
+// or the series' own weekday.
+func (r *Rule) weeklyDay(date, first time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return date.Weekday() == first.Weekday()
+	}
+	return r.onWeekday(date)
+}
+
+// onNthWeekday reports whether the date at index i of a total-day range
+// matches a BYDAY entry, honouring ordinals.
+func (r *Rule) onNthWeekday(date time.Time, i, total int) bool {
+	for _, d := range r.ByDay {
+		if d.Day != date.Weekday() {
+			continue
+		}
+		switch {
+		case d.N == 0:
+			return true
+		case d.N > 0 && i/7+1 == d.N:
+			return true
+		case d.N < 0 && (total-1-i)/7+1 == -d.N:
+			return true
+		}
+	}
+	return false
+}
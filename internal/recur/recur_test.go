@@ -0,0 +1,249 @@
+package recur
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func dates(ts []time.Time) string {
+	var s []string
+	for _, t := range ts {
+		s = append(s, t.Format("2006-01-02 15:04"))
+	}
+	return strings.Join(s, ", ")
+}
+
+func TestBetween(t *testing.T) {
+	utc := time.UTC
+	far := time.Date(2030, 1, 1, 0, 0, 0, 0, utc)
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		from    time.Time
+		limit   int
+		want    string
+	}{
+		{
+			name:    "daily count",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2026, 3, 30, 9, 0, 0, 0, utc),
+			want:    "2026-03-30 09:00, 2026-03-31 09:00, 2026-04-01 09:00",
+		},
+		{
+			name:    "every other week on three days",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR;COUNT=5",
+			dtstart: time.Date(2026, 3, 4, 9, 0, 0, 0, utc), // a Wednesday
+			want:    "2026-03-04 09:00, 2026-03-06 09:00, 2026-03-16 09:00, 2026-03-18 09:00, 2026-03-20 09:00",
+		},
+		{
+			name:    "last friday of the month",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			dtstart: time.Date(2026, 1, 30, 16, 0, 0, 0, utc),
+			want:    "2026-01-30 16:00, 2026-02-27 16:00, 2026-03-27 16:00",
+		},
+		{
+			name:    "first monday",
+			rule:    "FREQ=MONTHLY;BYDAY=1MO;COUNT=2",
+			dtstart: time.Date(2026, 3, 2, 10, 0, 0, 0, utc),
+			want:    "2026-03-02 10:00, 2026-04-06 10:00",
+		},
+		{
+			name:    "31st skips short months",
+			rule:    "FREQ=MONTHLY;COUNT=3",
+			dtstart: time.Date(2026, 1, 31, 12, 0, 0, 0, utc),
+			want:    "2026-01-31 12:00, 2026-03-31 12:00, 2026-05-31 12:00",
+		},
+		{
+			name:    "last day of the month",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			dtstart: time.Date(2026, 1, 31, 12, 0, 0, 0, utc),
+			want:    "2026-01-31 12:00, 2026-02-28 12:00, 2026-03-31 12:00",
+		},
+		{
+			name:    "leap day",
+			rule:    "FREQ=YEARLY;COUNT=2",
+			dtstart: time.Date(2024, 2, 29, 0, 0, 0, 0, utc),
+			want:    "2024-02-29 00:00, 2028-02-29 00:00",
+		},
+		{
+			name:    "yearly in given months",
+			rule:    "FREQ=YEARLY;BYMONTH=3,9;BYMONTHDAY=1;COUNT=3",
+			dtstart: time.Date(2026, 3, 1, 9, 0, 0, 0, utc),
+			want:    "2026-03-01 09:00, 2026-09-01 09:00, 2027-03-01 09:00",
+		},
+		{
+			name:    "until is inclusive",
+			rule:    "FREQ=DAILY;UNTIL=20260302T090000Z",
+			dtstart: time.Date(2026, 3, 1, 9, 0, 0, 0, utc),
+			want:    "2026-03-01 09:00, 2026-03-02 09:00",
+		},
+		{
+			name:    "until date covers the whole day",
+			rule:    "FREQ=DAILY;UNTIL=20260302",
+			dtstart: time.Date(2026, 3, 1, 23, 0, 0, 0, utc),
+			want:    "2026-03-01 23:00, 2026-03-02 23:00",
+		},
+		{
+			name:    "window and limit",
+			rule:    "FREQ=DAILY",
+			dtstart: time.Date(2020, 1, 1, 9, 0, 0, 0, utc),
+			from:    time.Date(2026, 3, 1, 0, 0, 0, 0, utc),
+			limit:   2,
+			want:    "2026-03-01 09:00, 2026-03-02 09:00",
+		},
+		{
+			name:    "never matching rule stops",
+			rule:    "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			dtstart: time.Date(2026, 1, 1, 9, 0, 0, 0, utc),
+			want:    "2026-01-01 09:00",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := tt.from
+			if from.IsZero() {
+				from = tt.dtstart
+			}
+			if got := dates(r.Between(tt.dtstart, from, far, tt.limit)); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetweenAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Parse("RRULE:FREQ=WEEKLY;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, ny)
+	got := r.Between(start, start, start.AddDate(1, 0, 0), 0)
+	for _, occ := range got {
+		if occ.Hour() != 9 || occ.Location() != ny {
+			t.Errorf("occurrence %v drifted off 09:00 local", occ)
+		}
+	}
+	// The change on 8 March makes the second week an hour short.
+	if d := got[1].Sub(got[0]); d != 7*24*time.Hour-time.Hour {
+		t.Errorf("first gap = %v, want 167h", d)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected error", s)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	utc := time.UTC
+	end := time.Date(2026, 3, 2, 10, 0, 0, 0, utc)
+	deadline := time.Date(2026, 3, 1, 17, 0, 0, 0, utc)
+	e := cal.Event{
+		ID:       "standup",
+		Start:    time.Date(2026, 3, 2, 9, 0, 0, 0, utc),
+		End:      &end,
+		Deadline: &deadline,
+		RRule:    "FREQ=DAILY",
+		ExDates:  []time.Time{time.Date(2026, 3, 4, 9, 0, 0, 0, utc)},
+	}
+
+	// From 09:30 on the 3rd catches that day's occurrence in progress.
+	from := time.Date(2026, 3, 3, 9, 30, 0, 0, utc)
+	to := time.Date(2026, 3, 6, 0, 0, 0, 0, utc)
+	got, err := Expand(e, from, to, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var starts []time.Time
+	for _, o := range got {
+		starts = append(starts, o.Start)
+		if o.ID != "standup" || o.End.Sub(o.Start) != time.Hour || o.Start.Sub(*o.Deadline) != 16*time.Hour {
+			t.Errorf("occurrence %+v not shifted as a whole", o)
+		}
+	}
+	if want := "2026-03-03 09:00, 2026-03-05 09:00"; dates(starts) != want {
+		t.Errorf("starts = %s, want %s", dates(starts), want)
+	}
+
+	if got, _ := Expand(e, from, to, 1); len(got) != 1 {
+		t.Errorf("limit 1 returned %d occurrences", len(got))
+	}
+
+	single := cal.Event{ID: "once", Start: time.Date(2020, 1, 1, 0, 0, 0, 0, utc)}
+	if got, _ := Expand(single, from, to, 0); len(got) != 1 || got[0].ID != "once" {
+		t.Errorf("non-recurring event = %+v, want it unchanged", got)
+	}
+}
+
+func TestExpandAll(t *testing.T) {
+	utc := time.UTC
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, utc)
+	events := []cal.Event{
+		{ID: "weekly", Start: time.Date(2026, 3, 2, 9, 0, 0, 0, utc), RRule: "FREQ=WEEKLY"},
+		{ID: "bad", Start: time.Date(2026, 3, 3, 9, 0, 0, 0, utc), RRule: "FREQ=SOMETIMES"},
+		{ID: "allday", AllDay: true, Start: time.Date(2026, 3, 1, 0, 0, 0, 0, utc), RRule: "FREQ=DAILY;COUNT=3",
+			ExDates: []time.Time{time.Date(2026, 3, 2, 0, 0, 0, 0, utc)}},
+	}
+	got := ExpandAll(events, from, from.AddDate(0, 0, 14))
+	count := map[string]int{}
+	for _, e := range got {
+		count[e.ID]++
+	}
+	if count["weekly"] != 2 || count["bad"] != 1 || count["allday"] != 2 {
+		t.Errorf("counts = %v, want weekly:2 bad:1 allday:2", count)
+	}
+}
+
+func TestEndsBefore(t *testing.T) {
+	utc := time.UTC
+	start := time.Date(2024, 1, 8, 9, 0, 0, 0, utc)
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, utc)
+	tests := []struct {
+		name  string
+		event cal.Event
+		want  bool
+	}{
+		{"single, before", cal.Event{Start: start}, true},
+		{"single, after", cal.Event{Start: cutoff.Add(time.Hour)}, false},
+		{"open-ended series", cal.Event{Start: start, RRule: "FREQ=WEEKLY"}, false},
+		{"count, ended", cal.Event{Start: start, RRule: "FREQ=WEEKLY;COUNT=10"}, true},
+		{"count, running", cal.Event{Start: start, RRule: "FREQ=MONTHLY;COUNT=36"}, false},
+		{"until, ended", cal.Event{Start: start, RRule: "FREQ=DAILY;UNTIL=20250601T000000Z"}, true},
+		{"until, running", cal.Event{Start: start, RRule: "FREQ=DAILY;UNTIL=20260301"}, false},
+		{"later occurrences excluded", cal.Event{Start: time.Date(2025, 12, 30, 9, 0, 0, 0, utc), RRule: "FREQ=DAILY;COUNT=3",
+			ExDates: []time.Time{time.Date(2026, 1, 1, 9, 0, 0, 0, utc)}}, true},
+		{"bad rule", cal.Event{Start: start, RRule: "FREQ=SOMETIMES;COUNT=2"}, false},
+	}
+	for _, tt := range tests {
+		if got := EndsBefore(tt.event, cutoff); got != tt.want {
+			t.Errorf("%s: EndsBefore = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
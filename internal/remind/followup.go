@@ -0,0 +1,66 @@
+package remind
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// FollowUp fires after an event ends, prompting attendees for action items.
+const FollowUp Kind = "follow-up"
+
+// EndOf returns when e ends. Events without an end are treated as lasting
+// an hour.
+func EndOf(e cal.Event) time.Time {
+	if e.End != nil {
+		return *e.End
+	}
+	return e.Start.Add(time.Hour)
+}
+
+// FollowUps returns the follow-up prompts due at now, oldest first. A prompt
+// is due for window after the event ends. If category is non-empty only
+// events in that category get one. Cancelled events never do.
+func FollowUps(events []cal.Event, now time.Time, window time.Duration, category string) []Notification {
+	rule := ThreadRule{Category: category}
+	var out []Notification
+	for _, e := range events {
+		if strings.EqualFold(e.Status, "CANCELLED") || (category != "" && !rule.Matches(e)) {
+			continue
+		}
+		end := EndOf(e)
+		if !now.Before(end) && now.Before(end.Add(window)) {
+			out = append(out, Notification{Key: key(e.ID, FollowUp, end), Kind: FollowUp, At: end, Event: e})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// FollowUpMessage renders a follow-up template for n. {summary}, {start},
+// {end} and {url} are replaced with the event's details; an empty template
+// uses the default prompt for the current language.
+func FollowUpMessage(template string, n Notification, loc *time.Location) string {
+	if template == "" {
+		template = i18n.T("remind.follow_up")
+	}
+	r := strings.NewReplacer(
+		"{summary}", n.Event.Summary,
+		"{start}", n.Event.Start.In(loc).Format("15:04 MST"),
+		"{end}", n.At.In(loc).Format("15:04 MST"),
+		"{url}", n.Event.URL,
+	)
+	return r.Replace(template)
+}
+
+// Prompt records where a follow-up prompt was posted so replies to it can
+// be collected later.
+type Prompt struct {
+	ChannelID string    `json:"channel_id"`
+	MessageID string    `json:"message_id"`
+	Summary   string    `json:"summary"`
+	PostedAt  time.Time `json:"posted_at"`
+}
@@ -0,0 +1,81 @@
+package remind
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func TestFollowUps(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name     string
+		event    cal.Event
+		category string
+		want     bool
+	}{
+		{name: "just ended", event: cal.Event{ID: "a", Start: at(-time.Hour), End: ptr(at(-time.Minute))}, want: true},
+		{name: "still running", event: cal.Event{ID: "a", Start: at(-time.Hour), End: ptr(at(time.Minute))}},
+		{name: "outside window", event: cal.Event{ID: "a", Start: at(-3 * time.Hour), End: ptr(at(-31 * time.Minute))}},
+		{name: "no end lasts an hour", event: cal.Event{ID: "a", Start: at(-61 * time.Minute)}, want: true},
+		{name: "cancelled", event: cal.Event{ID: "a", Start: at(-time.Hour), End: ptr(at(-time.Minute)), Status: "CANCELLED"}},
+		{name: "category match", event: cal.Event{ID: "a", Categories: "meeting", Start: at(-time.Hour), End: ptr(at(-time.Minute))}, category: "meeting", want: true},
+		{name: "category mismatch", event: cal.Event{ID: "a", Categories: "focus", Start: at(-time.Hour), End: ptr(at(-time.Minute))}, category: "meeting"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FollowUps([]cal.Event{tt.event}, now, 30*time.Minute, tt.category)
+			if (len(got) == 1) != tt.want {
+				t.Fatalf("expected due=%v, got %d", tt.want, len(got))
+			}
+			if tt.want && got[0].Kind != FollowUp {
+				t.Errorf("expected kind %s, got %s", FollowUp, got[0].Kind)
+			}
+		})
+	}
+}
+
+func TestFollowUpMessage(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	n := Notification{Kind: FollowUp, At: start.Add(30 * time.Minute), Event: cal.Event{Summary: "Standup", Start: start}}
+
+	if got := FollowUpMessage("Action items for {summary} ({start}-{end})?", n, time.UTC); got != "Action items for Standup (09:00 UTC-09:30 UTC)?" {
+		t.Errorf("unexpected message %q", got)
+	}
+	n.Event.URL = "https://sho.rt/abc"
+	if got := FollowUpMessage("Notes: {url}", n, time.UTC); got != "Notes: https://sho.rt/abc" {
+		t.Errorf("unexpected message %q", got)
+	}
+	if got := FollowUpMessage("", n, time.UTC); got != "📝 Standup has ended. Any action items? Reply here." {
+		t.Errorf("unexpected default message %q", got)
+	}
+}
+
+func TestPromptStore(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "remind.json")
+	s, _ := LoadStore(path)
+	s.SetPrompt("a", Prompt{ChannelID: "c", MessageID: "m", PostedAt: now})
+	s.SetPrompt("old", Prompt{ChannelID: "c", MessageID: "m0", PostedAt: now.Add(-10 * 24 * time.Hour)})
+	if err := s.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	s, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	s.Prune(now.Add(-7 * 24 * time.Hour))
+	if p, ok := s.Prompt("a"); !ok || p.MessageID != "m" {
+		t.Errorf("expected prompt a to survive, got %+v", p)
+	}
+	if _, ok := s.Prompt("old"); ok {
+		t.Error("expected old prompt to be pruned")
+	}
+}
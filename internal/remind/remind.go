@@ -0,0 +1,126 @@
+// Package remind decides which calendar reminders are due and remembers
+// which ones were already sent, so a restarted reminder loop never notifies
+// twice.
+package remind
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// Kind distinguishes reminder types.
+type Kind string
+
+const (
+	// Start fires a fixed lead time before an event starts.
+	Start Kind = "start"
+	// Deadline fires when an event's deadline passes.
+	Deadline Kind = "deadline"
+)
+
+// Notification is a reminder that is due.
+type Notification struct {
+	Key   string // stable dedupe key, see key()
+	Kind  Kind
+	At    time.Time // event start or deadline being reminded about
+	Event cal.Event
+}
+
+// Due returns the reminders that should be sent at now, oldest first.
+//
+// A start reminder is due once now is within before of the event start, and
+// stops being due when the event starts, so a loop that was down during that
+// window skips it rather than announcing a meeting that already began.
+// A deadline reminder is due for grace after the deadline passes. Cancelled
+// events never produce reminders.
+func Due(events []cal.Event, now time.Time, before, grace time.Duration) []Notification {
+	var out []Notification
+	for _, e := range events {
+		if strings.EqualFold(e.Status, "CANCELLED") {
+			continue
+		}
+		if !now.Before(e.Start.Add(-before)) && now.Before(e.Start) {
+			out = append(out, Notification{Key: key(e.ID, Start, e.Start), Kind: Start, At: e.Start, Event: e})
+		}
+		if e.Deadline != nil && !now.Before(*e.Deadline) && now.Before(e.Deadline.Add(grace)) {
+			out = append(out, Notification{Key: key(e.ID, Deadline, *e.Deadline), Kind: Deadline, At: *e.Deadline, Event: e})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// key identifies a reminder. It includes the target time so a rescheduled
+// event is reminded about again.
+func key(eventID string, kind Kind, at time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", eventID, kind, at.Unix())
+}
+
+// EventKey identifies e in the store's threads and prompts. Occurrences of
+// a recurring event share its ID, so their start time is added.
+func EventKey(e cal.Event) string {
+	if e.RRule == "" {
+		return e.ID
+	}
+	return fmt.Sprintf("%s@%d", e.ID, e.Start.Unix())
+}
+
+// Message renders a notification as a chat message.
+func (n Notification) Message(now time.Time, loc *time.Location) string {
+	e := n.Event
+	var msg string
+	switch n.Kind {
+	case Deadline:
+		msg = i18n.T("remind.deadline", e.Summary, n.At.In(loc).Format("Mon 15:04 MST"))
+	default:
+		msg = i18n.T("remind.start", e.Summary, Until(n.At.Sub(now)), n.At.In(loc).Format("15:04 MST"))
+	}
+	if e.Location != "" {
+		msg += "\n" + i18n.T("remind.location", e.Location)
+	}
+	if e.URL != "" {
+		msg += "\n" + e.URL
+	}
+	return msg
+}
+
+// Render renders n with a custom template instead of the default message.
+// {summary}, {when} (the start or deadline time), {in} (how long until
+// it), {location} and {url} are replaced with the event's details, so the
+// template decides where the link goes. An empty template gives Message.
+func (n Notification) Render(template string, now time.Time, loc *time.Location) string {
+	if template == "" {
+		return n.Message(now, loc)
+	}
+	r := strings.NewReplacer(
+		"{summary}", n.Event.Summary,
+		"{when}", n.At.In(loc).Format("15:04 MST"),
+		"{in}", Until(n.At.Sub(now)),
+		"{location}", n.Event.Location,
+		"{url}", n.Event.URL,
+	)
+	return r.Replace(template)
+}
+
+// Until formats a positive duration compactly, rounded to the minute
+// ("45m", "2h", "1h30m").
+func Until(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "<1m"
+	}
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
@@ -0,0 +1,173 @@
+package remind
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name     string
+		event    cal.Event
+		wantKind []Kind
+	}{
+		{
+			name:     "inside lead window",
+			event:    cal.Event{ID: "a", Start: at(20 * time.Minute)},
+			wantKind: []Kind{Start},
+		},
+		{
+			name:     "exactly at window start",
+			event:    cal.Event{ID: "a", Start: at(30 * time.Minute)},
+			wantKind: []Kind{Start},
+		},
+		{
+			name:  "too early",
+			event: cal.Event{ID: "a", Start: at(31 * time.Minute)},
+		},
+		{
+			name:  "already started",
+			event: cal.Event{ID: "a", Start: at(-time.Minute)},
+		},
+		{
+			name:  "cancelled",
+			event: cal.Event{ID: "a", Start: at(10 * time.Minute), Status: "CANCELLED"},
+		},
+		{
+			name:     "deadline just passed",
+			event:    cal.Event{ID: "a", Start: at(-48 * time.Hour), Deadline: ptr(at(-time.Minute))},
+			wantKind: []Kind{Deadline},
+		},
+		{
+			name:  "deadline outside grace",
+			event: cal.Event{ID: "a", Start: at(-48 * time.Hour), Deadline: ptr(at(-10 * time.Minute))},
+		},
+		{
+			name:  "deadline in future",
+			event: cal.Event{ID: "a", Start: at(-48 * time.Hour), Deadline: ptr(at(time.Minute))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Due([]cal.Event{tt.event}, now, 30*time.Minute, 5*time.Minute)
+			if len(got) != len(tt.wantKind) {
+				t.Fatalf("expected %d notifications, got %d", len(tt.wantKind), len(got))
+			}
+			for i, n := range got {
+				if n.Kind != tt.wantKind[i] {
+					t.Errorf("notification %d: expected %s, got %s", i, tt.wantKind[i], n.Kind)
+				}
+			}
+		})
+	}
+}
+
+func TestDueOrderAndKeys(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	events := []cal.Event{
+		{ID: "late", Start: now.Add(25 * time.Minute)},
+		{ID: "early", Start: now.Add(5 * time.Minute)},
+	}
+	got := Due(events, now, 30*time.Minute, time.Minute)
+	if len(got) != 2 || got[0].Event.ID != "early" {
+		t.Fatalf("expected early event first, got %+v", got)
+	}
+
+	// Moving the event changes its key so it is reminded about again.
+	moved := events[1]
+	moved.Start = moved.Start.Add(10 * time.Minute)
+	again := Due([]cal.Event{moved}, now, 30*time.Minute, time.Minute)
+	if again[0].Key == got[0].Key {
+		t.Error("expected a rescheduled event to get a new key")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	n := Notification{
+		Kind:  Start,
+		At:    now.Add(30 * time.Minute),
+		Event: cal.Event{Summary: "Standup", Location: "Room 1", URL: "https://meet.example.com"},
+	}
+	msg := n.Message(now, time.UTC)
+	for _, want := range []string{"Standup", "30m", "09:30 UTC", "Room 1", "https://meet.example.com"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q missing %q", msg, want)
+		}
+	}
+
+	d := Notification{Kind: Deadline, At: now, Event: cal.Event{Summary: "Taxes"}}
+	if msg := d.Message(now, time.UTC); !strings.Contains(msg, "Deadline") || !strings.Contains(msg, "Taxes") {
+		t.Errorf("unexpected deadline message %q", msg)
+	}
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	n := Notification{
+		Kind:  Start,
+		At:    now.Add(30 * time.Minute),
+		Event: cal.Event{Summary: "Meetup", Location: "Hall B", URL: "https://sho.rt/abc"},
+	}
+	got := n.Render("{summary} in {in} at {location} — RSVP {url} (starts {when})", now, time.UTC)
+	if want := "Meetup in 30m at Hall B — RSVP https://sho.rt/abc (starts 09:30 UTC)"; got != want {
+		t.Errorf("Render = %q, want %q", got, want)
+	}
+	if got := n.Render("", now, time.UTC); got != n.Message(now, time.UTC) {
+		t.Errorf("empty template = %q, want the default message", got)
+	}
+}
+
+func TestUntil(t *testing.T) {
+	tests := map[time.Duration]string{
+		20 * time.Second:                "<1m",
+		45 * time.Minute:                "45m",
+		2 * time.Hour:                   "2h",
+		90 * time.Minute:                "1h30m",
+		29*time.Minute + 40*time.Second: "30m",
+	}
+	for in, want := range tests {
+		if got := Until(in); got != want {
+			t.Errorf("Until(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "remind.json")
+	s, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	s.Mark("old", now.Add(-48*time.Hour))
+	s.Mark("new", now)
+	if err := s.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	reloaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !reloaded.Seen("old") || !reloaded.Seen("new") {
+		t.Fatal("expected both keys after reload")
+	}
+
+	reloaded.Prune(now.Add(-24 * time.Hour))
+	if reloaded.Seen("old") {
+		t.Error("expected old key to be pruned")
+	}
+	if !reloaded.Seen("new") {
+		t.Error("expected new key to survive pruning")
+	}
+}
@@ -0,0 +1,134 @@
+package remind
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Store records which reminders were sent and which event threads exist. It
+// is persisted as JSON so restarts keep their memory.
+type Store struct {
+	path    string
+	Sent    map[string]time.Time `json:"sent"`
+	Threads map[string]Thread    `json:"threads,omitempty"` // by event ID
+	Prompts map[string]Prompt    `json:"prompts,omitempty"` // follow-ups, by event ID
+}
+
+// LoadStore reads the store at path; a missing file yields an empty store.
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, Sent: map[string]time.Time{}, Threads: map[string]Thread{}, Prompts: map[string]Prompt{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if s.Sent == nil {
+		s.Sent = map[string]time.Time{}
+	}
+	if s.Threads == nil {
+		s.Threads = map[string]Thread{}
+	}
+	if s.Prompts == nil {
+		s.Prompts = map[string]Prompt{}
+	}
+	return s, nil
+}
+
+// Seen reports whether the reminder with key was already sent.
+func (s *Store) Seen(key string) bool {
+	_, ok := s.Sent[key]
+	return ok
+}
+
+// Mark records key as sent at t.
+func (s *Store) Mark(key string, t time.Time) {
+	s.Sent[key] = t
+}
+
+// Thread returns the thread recorded for an event.
+func (s *Store) Thread(eventID string) (Thread, bool) {
+	t, ok := s.Threads[eventID]
+	return t, ok
+}
+
+// SetThread records the thread for an event.
+func (s *Store) SetThread(eventID string, t Thread) {
+	s.Threads[eventID] = t
+}
+
+// Prompt returns the follow-up prompt posted for an event.
+func (s *Store) Prompt(eventID string) (Prompt, bool) {
+	p, ok := s.Prompts[eventID]
+	return p, ok
+}
+
+// SetPrompt records the follow-up prompt posted for an event.
+func (s *Store) SetPrompt(eventID string, p Prompt) {
+	s.Prompts[eventID] = p
+}
+
+// ThreadsToArchive returns the event IDs of open threads whose archive time
+// has passed at now, sorted for deterministic processing.
+func (s *Store) ThreadsToArchive(now time.Time) []string {
+	var ids []string
+	for id, t := range s.Threads {
+		if !t.Archived && !now.Before(t.ArchiveAt) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Prune forgets reminders sent before cutoff, and archived threads and
+// follow-up prompts older than it, so the file stays small.
+func (s *Store) Prune(cutoff time.Time) {
+	for k, t := range s.Sent {
+		if t.Before(cutoff) {
+			delete(s.Sent, k)
+		}
+	}
+	for id, t := range s.Threads {
+		if t.Archived && t.ArchiveAt.Before(cutoff) {
+			delete(s.Threads, id)
+		}
+	}
+	for id, p := range s.Prompts {
+		if p.PostedAt.Before(cutoff) {
+			delete(s.Prompts, id)
+		}
+	}
+}
+
+// Save writes the store atomically.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".remind-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
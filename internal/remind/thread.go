@@ -0,0 +1,95 @@
+package remind
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+	"github.com/jredh-dev/pylon/internal/i18n"
+)
+
+// ThreadRule opens a Discord thread for each event in Category, Before its
+// start, and archives it ArchiveAfter the event ends.
+type ThreadRule struct {
+	Category     string
+	Before       time.Duration
+	ArchiveAfter time.Duration
+}
+
+// Matches reports whether e carries the rule's category. Categories are
+// compared case-insensitively against each comma-separated entry.
+func (r ThreadRule) Matches(e cal.Event) bool {
+	for _, c := range strings.Split(e.Categories, ",") {
+		if strings.EqualFold(strings.TrimSpace(c), r.Category) {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchiveAt returns when e's thread should be archived.
+func (r ThreadRule) ArchiveAt(e cal.Event) time.Time {
+	return EndOf(e).Add(r.ArchiveAfter)
+}
+
+// ThreadsToOpen returns the events whose thread is due at now and not yet
+// opened according to s, soonest first. A thread is due from Before the
+// start until the event ends, so a loop that was briefly down still opens it
+// for a meeting in progress. Cancelled events are skipped.
+func (r ThreadRule) ThreadsToOpen(events []cal.Event, now time.Time, s *Store) []cal.Event {
+	var out []cal.Event
+	for _, e := range events {
+		if strings.EqualFold(e.Status, "CANCELLED") || !r.Matches(e) {
+			continue
+		}
+		if _, ok := s.Thread(EventKey(e)); ok {
+			continue
+		}
+		if !now.Before(e.Start.Add(-r.Before)) && now.Before(EndOf(e)) {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.Before(out[j].Start) })
+	return out
+}
+
+// Thread records a Discord thread opened for an event.
+type Thread struct {
+	ID        string    `json:"id"`
+	ChannelID string    `json:"channel_id"`
+	Name      string    `json:"name"`
+	ArchiveAt time.Time `json:"archive_at"`
+	Archived  bool      `json:"archived,omitempty"`
+}
+
+// ThreadName derives a thread title from an event, within Discord's 100
+// character limit.
+func ThreadName(e cal.Event) string {
+	name := strings.TrimSpace(e.Summary)
+	if name == "" {
+		name = "Meeting"
+	}
+	if r := []rune(name); len(r) > 100 {
+		name = string(r[:99]) + "…"
+	}
+	return name
+}
+
+// Agenda renders the opening message posted into an event's thread: the
+// title and start time, then the description as the agenda.
+func Agenda(e cal.Event, loc *time.Location) string {
+	msg := i18n.T("remind.agenda", e.Summary, e.Start.In(loc).Format("Mon 15:04 MST"))
+	if e.Location != "" {
+		msg += "\n" + i18n.T("remind.location", e.Location)
+	}
+	if e.URL != "" {
+		msg += "\n" + e.URL
+	}
+	if desc := strings.TrimSpace(e.Description); desc != "" {
+		msg += "\n\n" + desc
+	} else {
+		msg += "\n\n" + i18n.T("remind.no_agenda")
+	}
+	return msg
+}
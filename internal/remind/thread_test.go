@@ -0,0 +1,126 @@
+package remind
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jredh-dev/pylon/cal"
+)
+
+func TestThreadsToOpen(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+	ptr := func(t time.Time) *time.Time { return &t }
+	rule := ThreadRule{Category: "meeting", Before: 10 * time.Minute, ArchiveAfter: time.Hour}
+
+	tests := []struct {
+		name  string
+		event cal.Event
+		want  bool
+	}{
+		{name: "inside window", event: cal.Event{ID: "a", Categories: "meeting", Start: at(5 * time.Minute)}, want: true},
+		{name: "category among several", event: cal.Event{ID: "a", Categories: "team, Meeting", Start: at(5 * time.Minute)}, want: true},
+		{name: "other category", event: cal.Event{ID: "a", Categories: "meetings", Start: at(5 * time.Minute)}},
+		{name: "too early", event: cal.Event{ID: "a", Categories: "meeting", Start: at(11 * time.Minute)}},
+		{name: "in progress", event: cal.Event{ID: "a", Categories: "meeting", Start: at(-30 * time.Minute), End: ptr(at(30 * time.Minute))}, want: true},
+		{name: "over", event: cal.Event{ID: "a", Categories: "meeting", Start: at(-2 * time.Hour), End: ptr(at(-time.Minute))}},
+		{name: "no end lasts an hour", event: cal.Event{ID: "a", Categories: "meeting", Start: at(-59 * time.Minute)}, want: true},
+		{name: "cancelled", event: cal.Event{ID: "a", Categories: "meeting", Start: at(5 * time.Minute), Status: "CANCELLED"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := LoadStore(filepath.Join(t.TempDir(), "remind.json"))
+			got := rule.ThreadsToOpen([]cal.Event{tt.event}, now, s)
+			if (len(got) == 1) != tt.want {
+				t.Errorf("expected due=%v, got %d events", tt.want, len(got))
+			}
+		})
+	}
+
+	t.Run("already opened", func(t *testing.T) {
+		s, _ := LoadStore(filepath.Join(t.TempDir(), "remind.json"))
+		s.SetThread("a", Thread{ID: "t1"})
+		e := cal.Event{ID: "a", Categories: "meeting", Start: at(5 * time.Minute)}
+		if got := rule.ThreadsToOpen([]cal.Event{e}, now, s); len(got) != 0 {
+			t.Errorf("expected no threads, got %d", len(got))
+		}
+	})
+}
+
+func TestThreadStore(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "remind.json")
+	s, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	s.SetThread("due", Thread{ID: "t1", ArchiveAt: now.Add(-time.Minute)})
+	s.SetThread("later", Thread{ID: "t2", ArchiveAt: now.Add(time.Hour)})
+	s.SetThread("done", Thread{ID: "t3", ArchiveAt: now.Add(-48 * time.Hour), Archived: true})
+	if err := s.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	s, err = LoadStore(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got := s.ThreadsToArchive(now); len(got) != 1 || got[0] != "due" {
+		t.Errorf("ThreadsToArchive = %v, want [due]", got)
+	}
+
+	s.Prune(now.Add(-24 * time.Hour))
+	if _, ok := s.Thread("done"); ok {
+		t.Error("expected archived thread to be pruned")
+	}
+	if _, ok := s.Thread("due"); !ok {
+		t.Error("expected open thread to survive pruning")
+	}
+}
+
+func TestThreadName(t *testing.T) {
+	if got := ThreadName(cal.Event{Summary: "  Standup "}); got != "Standup" {
+		t.Errorf("ThreadName = %q", got)
+	}
+	if got := ThreadName(cal.Event{}); got != "Meeting" {
+		t.Errorf("ThreadName(empty) = %q", got)
+	}
+	long := ThreadName(cal.Event{Summary: strings.Repeat("x", 150)})
+	if n := len([]rune(long)); n != 100 {
+		t.Errorf("expected 100 runes, got %d", n)
+	}
+}
+
+func TestEventKey(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	if got := EventKey(cal.Event{ID: "e1", Start: start}); got != "e1" {
+		t.Errorf("EventKey = %q, want e1", got)
+	}
+	series := cal.Event{ID: "e1", Start: start, RRule: "FREQ=WEEKLY"}
+	next := series
+	next.Start = start.AddDate(0, 0, 7)
+	if a, b := EventKey(series), EventKey(next); a == b || a == "e1" {
+		t.Errorf("occurrences share key %q", a)
+	}
+}
+
+func TestAgenda(t *testing.T) {
+	e := cal.Event{
+		Summary:     "Planning",
+		Start:       time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC),
+		Location:    "Room 4",
+		Description: "1. Roadmap\n2. Hiring",
+	}
+	got := Agenda(e, time.UTC)
+	for _, want := range []string{"Planning (Mon 15:00 UTC)", "Room 4", "\n\n1. Roadmap\n2. Hiring"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("agenda missing %q:\n%s", want, got)
+		}
+	}
+	if got := Agenda(cal.Event{Summary: "x"}, time.UTC); !strings.Contains(got, "No agenda yet") {
+		t.Errorf("expected placeholder agenda, got %q", got)
+	}
+}
@@ -0,0 +1,117 @@
+// Package resolve turns what a user typed for a feed or event — its full
+// ID, an unambiguous prefix of the ID, or a feed's name — into the ID the
+// cal API wants.
+package resolve
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Item is something that can be referred to by ID or name. Name is empty
+// for things only referred to by ID, which may still have a Label to tell
+// them apart in errors.
+type Item struct {
+	ID    string
+	Name  string
+	Label string // shown in errors but not matched, e.g. an event's summary
+}
+
+func (it Item) String() string {
+	if label := cmp.Or(it.Name, it.Label); label != "" {
+		return fmt.Sprintf("%s (%s)", it.ID, label)
+	}
+	return it.ID
+}
+
+// AmbiguousError is returned when a reference matches more than one item.
+type AmbiguousError struct {
+	Kind    string // "feed", "event"
+	Ref     string
+	Matches []Item
+}
+
+func (e *AmbiguousError) Error() string {
+	const show = 5
+	list := make([]string, 0, show)
+	for _, m := range e.Matches[:min(len(e.Matches), show)] {
+		list = append(list, m.String())
+	}
+	more := ""
+	if len(e.Matches) > show {
+		more = fmt.Sprintf(", and %d more", len(e.Matches)-show)
+	}
+	return fmt.Sprintf("%q matches %d %ss: %s%s", e.Ref, len(e.Matches), e.Kind, strings.Join(list, ", "), more)
+}
+
+// NotFoundError is returned when a reference matches nothing.
+type NotFoundError struct {
+	Kind string
+	Ref  string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no %s matches %q", e.Kind, e.Ref)
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IsFullID reports whether ref is a complete UUID, which needn't be looked
+// up.
+func IsFullID(ref string) bool {
+	return uuidPattern.MatchString(ref)
+}
+
+// Resolve returns the ID of the item ref refers to. It tries, in order
+// and stopping at the first rule that matches anything: the exact ID, the
+// exact name, a prefix of the ID, and part of the name. Names compare
+// without case. More than one match under a rule is an *AmbiguousError,
+// none under any a *NotFoundError.
+func Resolve(kind, ref string, items []Item) (string, error) {
+	lower := strings.ToLower(ref)
+	return resolve(kind, ref, items, append(strictRules(ref),
+		func(it Item) bool { return strings.Contains(strings.ToLower(it.Name), lower) },
+	))
+}
+
+// Strict is Resolve without the last rule: ref must be the exact ID, the
+// exact name or a prefix of the ID. Commands that delete or overwrite
+// things use it, so that a few letters of a name can't pick out a feed the
+// user didn't mean.
+func Strict(kind, ref string, items []Item) (string, error) {
+	return resolve(kind, ref, items, strictRules(ref))
+}
+
+func strictRules(ref string) []func(Item) bool {
+	lower := strings.ToLower(ref)
+	return []func(Item) bool{
+		func(it Item) bool { return it.ID == ref },
+		func(it Item) bool { return it.Name != "" && strings.EqualFold(it.Name, ref) },
+		func(it Item) bool { return strings.HasPrefix(strings.ToLower(it.ID), lower) },
+	}
+}
+
+func resolve(kind, ref string, items []Item, rules []func(Item) bool) (string, error) {
+	if ref == "" {
+		return "", &NotFoundError{Kind: kind, Ref: ref}
+	}
+	for _, match := range rules {
+		var matches []Item
+		for _, it := range items {
+			if match(it) {
+				matches = append(matches, it)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0].ID, nil
+		default:
+			return "", &AmbiguousError{Kind: kind, Ref: ref, Matches: matches}
+		}
+	}
+	return "", &NotFoundError{Kind: kind, Ref: ref}
+}
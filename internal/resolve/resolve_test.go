@@ -0,0 +1,96 @@
+package resolve
+
+import (
+	"errors"
+	"testing"
+)
+
+// resolveTest is a reference and what it should resolve to.
+type resolveTest struct {
+	ref       string
+	want      string
+	ambiguous bool
+	notFound  bool
+}
+
+func runResolveTests(t *testing.T, name string, fn func(kind, ref string, items []Item) (string, error), items []Item, tests []resolveTest) {
+	t.Helper()
+	for _, tt := range tests {
+		got, err := fn("feed", tt.ref, items)
+		var amb *AmbiguousError
+		var nf *NotFoundError
+		switch {
+		case tt.ambiguous:
+			if !errors.As(err, &amb) {
+				t.Errorf("%s(%q) = %q, %v; want ambiguous", name, tt.ref, got, err)
+			}
+		case tt.notFound:
+			if !errors.As(err, &nf) {
+				t.Errorf("%s(%q) = %q, %v; want not found", name, tt.ref, got, err)
+			}
+		case err != nil || got != tt.want:
+			t.Errorf("%s(%q) = %q, %v; want %q", name, tt.ref, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	feeds := []Item{
+		{ID: "3f2a9c1e-0000-4000-8000-000000000001", Name: "Work"},
+		{ID: "3f2b0d7a-0000-4000-8000-000000000002", Name: "Work travel"},
+		{ID: "81c4e2f0-0000-4000-8000-000000000003", Name: "Family"},
+		{ID: "work", Name: "Side projects"},
+	}
+	runResolveTests(t, "Resolve", Resolve, feeds, []resolveTest{
+		{ref: "3f2a9c1e-0000-4000-8000-000000000001", want: feeds[0].ID},
+		{ref: "3f2a", want: feeds[0].ID},
+		{ref: "3F2B", want: feeds[1].ID},
+		{ref: "3f2", ambiguous: true},
+		{ref: "work", want: "work"},             // exact ID before name
+		{ref: "WORK TRAVEL", want: feeds[1].ID}, // name, any case
+		{ref: "Family", want: feeds[2].ID},
+		{ref: "fam", want: feeds[2].ID},
+		{ref: "side", want: "work"},
+		{ref: "o", ambiguous: true},
+		{ref: "holidays", notFound: true},
+		{ref: "", notFound: true},
+	})
+}
+
+func TestStrict(t *testing.T) {
+	feeds := []Item{
+		{ID: "3f2a9c1e-0000-4000-8000-000000000001", Name: "Copy"},
+		{ID: "3f2b0d7a-0000-4000-8000-000000000002", Name: "Ops"},
+		{ID: "81c4e2f0-0000-4000-8000-000000000003", Name: "Ops archive"},
+	}
+	runResolveTests(t, "Strict", Strict, feeds, []resolveTest{
+		{ref: "3f2a", want: feeds[0].ID},
+		{ref: "3f2", ambiguous: true},
+		{ref: "ops", want: feeds[1].ID}, // exact name, any case
+		{ref: "op", notFound: true},     // only part of "Copy" and "Ops"
+		{ref: "archive", notFound: true},
+		{ref: "", notFound: true},
+	})
+}
+
+func TestAmbiguousError(t *testing.T) {
+	err := &AmbiguousError{Kind: "event", Ref: "a", Matches: []Item{
+		{ID: "a1"}, {ID: "a2", Label: "Standup"}, {ID: "a3"}, {ID: "a4"}, {ID: "a5"}, {ID: "a6"}, {ID: "a7"},
+	}}
+	want := `"a" matches 7 events: a1, a2 (Standup), a3, a4, a5, and 2 more`
+	if got := err.Error(); got != want {
+		t.Errorf("Error = %q, want %q", got, want)
+	}
+}
+
+func TestIsFullID(t *testing.T) {
+	for ref, want := range map[string]bool{
+		"3f2a9c1e-0000-4000-8000-000000000001": true,
+		"3f2a9c1e":                             false,
+		"evt-12":                               false,
+	} {
+		if got := IsFullID(ref); got != want {
+			t.Errorf("IsFullID(%q) = %v", ref, got)
+		}
+	}
+}
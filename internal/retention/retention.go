@@ -0,0 +1,66 @@
+// Package retention finds the files a retention policy says a long-running
+// pylon deployment no longer needs to keep.
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StaleFiles returns the regular files directly in dir whose extension is
+// one of exts (without the dot) and that were last modified before cutoff,
+// sorted by name. Hidden files, such as a half-written export, and
+// subdirectories are never returned.
+func StaleFiles(dir string, exts []string, cutoff time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if !slices.Contains(exts, strings.TrimPrefix(filepath.Ext(name), ".")) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // removed since the listing
+			}
+			return nil, err
+		}
+		if info.ModTime().Before(cutoff) {
+			stale = append(stale, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(stale)
+	return stale, nil
+}
+
+// Overlap reports whether directories a and b are the same or one is
+// inside the other. Relative paths are taken from the working directory;
+// symlinks are not followed.
+func Overlap(a, b string) (bool, error) {
+	a, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	if b, err = filepath.Abs(b); err != nil {
+		return false, err
+	}
+	return within(a, b) || within(b, a), nil
+}
+
+// within reports whether path is dir or inside it; both are absolute and
+// clean.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,71 @@
+package retention
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-100 * 24 * time.Hour)
+	files := []struct {
+		name string
+		mod  time.Time
+	}{
+		{"general-2025-11.json", old},
+		{"general-2025-12.csv", old},
+		{"general-2026-02.json", now.Add(-time.Hour)},
+		{"notes.txt", old},
+		{".general.json.tmp", old},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, f.mod, f.mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "old.json"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := StaleFiles(dir, []string{"json", "csv", "md"}, now.Add(-90*24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{filepath.Join(dir, "general-2025-11.json"), filepath.Join(dir, "general-2025-12.csv")}
+	if !slices.Equal(got, want) {
+		t.Errorf("StaleFiles = %q, want %q", got, want)
+	}
+
+	if _, err := StaleFiles(filepath.Join(dir, "missing"), []string{"json"}, now); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+func TestOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"/srv/pylon/archive", "/srv/pylon/archive", true},
+		{"/srv/pylon/archive/", "/srv/pylon/./archive", true},
+		{"/srv/pylon", "/srv/pylon/exports", true},
+		{"/srv/pylon/exports/archive", "/srv/pylon/exports", true},
+		{"/srv/pylon/archive", "/srv/pylon/exports", false},
+		{"/srv/pylon/archive", "/srv/pylon/archive-old", false},
+		{"/srv/pylon/..archive", "/srv/pylon", true},
+	}
+	for _, tt := range tests {
+		got, err := Overlap(tt.a, tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("Overlap(%q, %q) = %v, %v; want %v", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package shellwords splits a command line into arguments the way a POSIX
+// shell does, and joins arguments back into a line that splits the same
+// way. Only quoting is handled: there is no expansion of variables, globs
+// or ~.
+package shellwords
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnterminated is returned for a line that ends inside quotes or after
+// a lone backslash.
+var ErrUnterminated = errors.New("unterminated quote or escape")
+
+// Split splits line into arguments. Single quotes keep everything up to
+// the next single quote; double quotes do too, except that a backslash
+// escapes ", \, $ and `; a backslash outside quotes escapes any character.
+func Split(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	const (
+		bare = iota
+		single
+		double
+	)
+	state := bare
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch state {
+		case single:
+			if c == '\'' {
+				state = bare
+			} else {
+				cur.WriteByte(c)
+			}
+		case double:
+			switch {
+			case c == '"':
+				state = bare
+			case c == '\\' && i+1 < len(line) && strings.IndexByte(`"\$`+"`", line[i+1]) >= 0:
+				i++
+				cur.WriteByte(line[i])
+			default:
+				cur.WriteByte(c)
+			}
+		default:
+			switch c {
+			case ' ', '\t', '\n', '\r':
+				if inArg {
+					args = append(args, cur.String())
+					cur.Reset()
+					inArg = false
+				}
+				continue
+			case '\'':
+				state = single
+			case '"':
+				state = double
+			case '\\':
+				if i+1 == len(line) {
+					return nil, ErrUnterminated
+				}
+				i++
+				cur.WriteByte(line[i])
+			default:
+				cur.WriteByte(c)
+			}
+		}
+		inArg = true
+	}
+	if state != bare {
+		return nil, ErrUnterminated
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// Join quotes args as needed and joins them with spaces, so that Split
+// returns them unchanged.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = Quote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Quote returns s as a single argument: unchanged if it only has
+// characters that need no quoting, in single quotes otherwise.
+func Quote(s string) string {
+	if s != "" && strings.Trim(s, safe) == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+const safe = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+.,/:@%"
@@ -0,0 +1,63 @@
+package shellwords
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+		err  error
+	}{
+		{line: "", want: nil},
+		{line: "   ", want: nil},
+		{line: "cal event list --feed 3f2a", want: []string{"cal", "event", "list", "--feed", "3f2a"}},
+		{line: "  cal\tagenda \n --days 1 ", want: []string{"cal", "agenda", "--days", "1"}},
+		{line: `cal search 'team sync' --to "next week"`, want: []string{"cal", "search", "team sync", "--to", "next week"}},
+		{line: `say 'it'\''s'`, want: []string{"say", "it's"}},
+		{line: `say "a \"quoted\" \$word \n"`, want: []string{"say", `a "quoted" $word \n`}},
+		{line: `a\ b c`, want: []string{"a b", "c"}},
+		{line: `--summary=''`, want: []string{"--summary="}},
+		{line: `''`, want: []string{""}},
+		{line: `x"y"'z'`, want: []string{"xyz"}},
+		{line: `'open`, err: ErrUnterminated},
+		{line: `"open`, err: ErrUnterminated},
+		{line: `trailing\`, err: ErrUnterminated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := Split(tt.line)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinRoundTrip(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"cal", "event", "list", "--feed=3f2a"}, want: "cal event list --feed=3f2a"},
+		{args: []string{"cal", "search", "team sync"}, want: "cal search 'team sync'"},
+		{args: []string{"it's", ""}, want: `'it'\''s' ''`},
+		{args: []string{"$HOME", "a*b"}, want: `'$HOME' 'a*b'`},
+	}
+	for _, tt := range tests {
+		got := Join(tt.args)
+		if got != tt.want {
+			t.Errorf("Join(%q) = %s, want %s", tt.args, got, tt.want)
+		}
+		back, err := Split(got)
+		if err != nil || !reflect.DeepEqual(back, tt.args) {
+			t.Errorf("Split(Join(%q)) = %q, %v", tt.args, back, err)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+// Package slack is a small Slack Web API client mirroring the parts of the
+// discord package that pylon's chat commands use: posting through an
+// incoming webhook or the bot token, reading a channel's history and
+// listing channels.
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jredh-dev/pylon/internal/httpx"
+)
+
+const apiBase = "https://slack.com/api"
+
+// Client talks to the Slack Web API.
+type Client struct {
+	botToken   string
+	webhookURL string
+	baseURL    string // Web API base, overridden in tests
+	httpClient *http.Client
+	retries    int
+	readOnly   bool
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRetries retries rate-limited (429) and transient 5xx responses up to
+// n times, honouring Retry-After. The default is no retries.
+func WithRetries(n int) Option {
+	return func(c *Client) { c.retries = n }
+}
+
+// WithHTTPClient sends requests through hc instead of a default client
+// with a 15s timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithReadOnly makes the client refuse to post, with ErrReadOnly, before
+// anything is sent.
+func WithReadOnly() Option {
+	return func(c *Client) { c.readOnly = true }
+}
+
+// ErrReadOnly is returned for a request a WithReadOnly client refused.
+var ErrReadOnly = errors.New("read-only mode")
+
+// NewClient creates a Slack client. botToken (xoxb-...) is used for the
+// Web API, webhookURL for posting to the webhook's channel.
+func NewClient(botToken, webhookURL string, opts ...Option) *Client {
+	c := &Client{
+		botToken:   botToken,
+		webhookURL: webhookURL,
+		baseURL:    apiBase,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Message is a message in a channel's history.
+type Message struct {
+	TS       string `json:"ts"` // message ID and timestamp, "1712345678.000200"
+	User     string `json:"user,omitempty"`
+	Username string `json:"username,omitempty"` // set for bot and webhook posts
+	BotID    string `json:"bot_id,omitempty"`
+	Text     string `json:"text"`
+	ThreadTS string `json:"thread_ts,omitempty"`
+	Replies  int    `json:"reply_count,omitempty"`
+}
+
+// Time returns when the message was posted.
+func (m *Message) Time() time.Time {
+	sec, frac, _ := strings.Cut(m.TS, ".")
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	us, _ := strconv.ParseInt((frac + "000000")[:6], 10, 64)
+	return time.Unix(s, us*1000)
+}
+
+// Channel is a conversation the bot can see.
+type Channel struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsPrivate  bool   `json:"is_private"`
+	IsArchived bool   `json:"is_archived"`
+	IsMember   bool   `json:"is_member"`
+	NumMembers int    `json:"num_members"`
+	Topic      struct {
+		Value string `json:"value"`
+	} `json:"topic"`
+}
+
+// APIError is an error reported by the Web API in its "error" field, such
+// as channel_not_found or not_in_channel.
+type APIError struct {
+	Method string
+	Code   string
+}
+
+func (e *APIError) Error() string {
+	return "slack " + e.Method + ": " + e.Code
+}
+
+// SendMessage posts text to the configured webhook's channel.
+func (c *Client) SendMessage(text string) error {
+	if c.webhookURL == "" {
+		return fmt.Errorf("webhook URL not configured (set PYLON_SLACK_WEBHOOK)")
+	}
+	if c.readOnly {
+		return fmt.Errorf("%w: refusing to post to the webhook", ErrReadOnly)
+	}
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, c.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(payload)), nil }
+	resp, err := httpx.Do(c.httpClient, req, c.retries)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if resp.StatusCode != http.StatusOK {
+		// Webhooks answer errors in plain text, e.g. "no_text".
+		return fmt.Errorf("slack webhook: %d %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// SendChannelMessage posts text to a channel with the bot token, as a
+// reply in the thread of threadTS when it is set, and returns the posted
+// message's ts.
+func (c *Client) SendChannelMessage(channelID, text, threadTS string) (string, error) {
+	if channelID == "" {
+		return "", fmt.Errorf("channel ID required")
+	}
+	payload := map[string]string{"channel": channelID, "text": text}
+	if threadTS != "" {
+		payload["thread_ts"] = threadTS
+	}
+	var resp struct {
+		TS string `json:"ts"`
+	}
+	if err := c.call(http.MethodPost, "chat.postMessage", nil, payload, &resp); err != nil {
+		return "", err
+	}
+	return resp.TS, nil
+}
+
+// ReadMessages returns up to limit of the latest messages in a channel,
+// oldest first. Limit defaults to 20 and is capped at 1000.
+func (c *Client) ReadMessages(channelID string, limit int) ([]Message, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID required")
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 20
+	}
+	var resp struct {
+		Messages []Message `json:"messages"`
+	}
+	q := url.Values{"channel": {channelID}, "limit": {strconv.Itoa(limit)}}
+	if err := c.call(http.MethodGet, "conversations.history", q, nil, &resp); err != nil {
+		return nil, err
+	}
+	slices.Reverse(resp.Messages)
+	return resp.Messages, nil
+}
+
+// ListChannels returns the public and private channels the bot can see,
+// following pagination. Archived channels are left out unless archived is
+// set.
+func (c *Client) ListChannels(archived bool) ([]Channel, error) {
+	var all []Channel
+	cursor := ""
+	for {
+		q := url.Values{
+			"types":            {"public_channel,private_channel"},
+			"limit":            {"200"},
+			"exclude_archived": {strconv.FormatBool(!archived)},
+		}
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+		var resp struct {
+			Channels []Channel `json:"channels"`
+			Meta     struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		if err := c.call(http.MethodGet, "conversations.list", q, nil, &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Channels...)
+		if cursor = resp.Meta.NextCursor; cursor == "" {
+			return all, nil
+		}
+	}
+}
+
+// UserNames looks up the display names of user IDs, e.g. the authors of
+// messages. IDs that can't be looked up are left out.
+func (c *Client) UserNames(ids []string) map[string]string {
+	names := make(map[string]string)
+	for _, id := range ids {
+		if _, ok := names[id]; ok || id == "" {
+			continue
+		}
+		var resp struct {
+			User struct {
+				Name    string `json:"name"`
+				Profile struct {
+					DisplayName string `json:"display_name"`
+					RealName    string `json:"real_name"`
+				} `json:"profile"`
+			} `json:"user"`
+		}
+		if err := c.call(http.MethodGet, "users.info", url.Values{"user": {id}}, nil, &resp); err != nil {
+			continue
+		}
+		u := resp.User
+		names[id] = firstNonEmpty(u.Profile.DisplayName, u.Profile.RealName, u.Name)
+	}
+	return names
+}
+
+// Authors returns the user IDs of the authors of msgs, for UserNames.
+func Authors(msgs []Message) []string {
+	var ids []string
+	for _, m := range msgs {
+		if m.User != "" && !slices.Contains(ids, m.User) {
+			ids = append(ids, m.User)
+		}
+	}
+	return ids
+}
+
+// Format renders messages one per line like discord's, naming authors from
+// names (see UserNames) where possible.
+func Format(msgs []Message, names map[string]string) string {
+	var sb strings.Builder
+	for _, m := range msgs {
+		author := firstNonEmpty(names[m.User], m.Username, m.User, m.BotID)
+		text := m.Text
+		if text == "" {
+			text = "(no text)"
+		}
+		fmt.Fprintf(&sb, "[%s] %s: %s", m.Time().UTC().Format("2006-01-02T15:04:05"), author, text)
+		if m.Replies > 0 {
+			fmt.Fprintf(&sb, " (%d replies)", m.Replies)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// call invokes a Web API method with the bot token and decodes the
+// response into out. GET requests send q as the query string; POST requests
+// send payload as JSON. Slack reports most errors with status 200 and
+// "ok": false, which call turns into an *APIError.
+func (c *Client) call(httpMethod, method string, q url.Values, payload any, out any) error {
+	if c.botToken == "" {
+		return fmt.Errorf("bot token not configured (set PYLON_SLACK_BOT_TOKEN)")
+	}
+	if c.readOnly && httpMethod != http.MethodGet {
+		return fmt.Errorf("%w: refusing %s", ErrReadOnly, method)
+	}
+	target := c.baseURL + "/" + method
+	if len(q) > 0 {
+		target += "?" + q.Encode()
+	}
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
+	}
+	req, err := http.NewRequest(httpMethod, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	req.Header.Set("Authorization", "Bearer "+c.botToken)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	resp, err := httpx.Do(c.httpClient, req, c.retries)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s: %d %s", method, resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if !status.OK {
+		return &APIError{Method: method, Code: status.Error}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	c := NewClient("xoxb-test", srv.URL+"/webhook")
+	c.baseURL = srv.URL
+	return c
+}
+
+func TestSendMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK, body: "ok"},
+		{name: "error", status: http.StatusBadRequest, body: "no_text", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var p map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&p); err != nil || p["text"] != "hello" {
+					t.Errorf("unexpected payload %v, %v", p, err)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			err := c.SendMessage("hello")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendMessage error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "no_text") {
+				t.Errorf("error %q doesn't carry the webhook's reason", err)
+			}
+		})
+	}
+}
+
+func TestSendChannelMessage(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/chat.postMessage" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("unexpected Authorization %q", r.Header.Get("Authorization"))
+		}
+		var p map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		if p["channel"] != "C1" || p["text"] != "hi" || p["thread_ts"] != "1.2" {
+			t.Errorf("unexpected payload %v", p)
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C1","ts":"1712345678.000200"}`))
+	})
+	ts, err := c.SendChannelMessage("C1", "hi", "1.2")
+	if err != nil || ts != "1712345678.000200" {
+		t.Errorf("SendChannelMessage = %q, %v", ts, err)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	})
+	_, err := c.ReadMessages("C404", 5)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "channel_not_found" {
+		t.Errorf("expected channel_not_found, got %v", err)
+	}
+}
+
+func TestReadMessages(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/conversations.history" || r.URL.Query().Get("channel") != "C1" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"messages":[
+			{"ts":"1712345700.000100","user":"U2","text":"second"},
+			{"ts":"1712345678.000200","user":"U1","text":"first","reply_count":2}
+		]}`))
+	})
+	msgs, err := c.ReadMessages("C1", 2)
+	if err != nil {
+		t.Fatalf("ReadMessages: %v", err)
+	}
+	if len(msgs) != 2 || msgs[0].Text != "first" {
+		t.Fatalf("expected oldest first, got %+v", msgs)
+	}
+	if got := msgs[0].Time(); !got.Equal(time.Unix(1712345678, 200000)) {
+		t.Errorf("Time = %v", got)
+	}
+	want := "[2024-04-05T19:34:38] alice: first (2 replies)\n[2024-04-05T19:35:00] U2: second\n"
+	if got := Format(msgs, map[string]string{"U1": "alice"}); got != want {
+		t.Errorf("Format =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestListChannels(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("exclude_archived") != "true" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"ok":true,"channels":[{"id":"C1","name":"general"}],"response_metadata":{"next_cursor":"abc"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channels":[{"id":"C2","name":"ops","is_private":true}],"response_metadata":{"next_cursor":""}}`))
+	})
+	channels, err := c.ListChannels(false)
+	if err != nil {
+		t.Fatalf("ListChannels: %v", err)
+	}
+	if calls != 2 || len(channels) != 2 || channels[1].Name != "ops" || !channels[1].IsPrivate {
+		t.Errorf("got %+v after %d calls", channels, calls)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	})
+	WithReadOnly()(c)
+	if err := c.SendMessage("hi"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("SendMessage: expected ErrReadOnly, got %v", err)
+	}
+	if _, err := c.SendChannelMessage("C1", "hi", ""); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("SendChannelMessage: expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+// Package suggest finds likely intended words for mistyped commands and
+// flags.
+package suggest
+
+import (
+	"sort"
+	"strings"
+)
+
+// Closest returns the candidates most likely meant by input, best first.
+// A candidate matches if its edit distance is small relative to the input
+// length (a third of it, at least one), or if input is a prefix of it.
+// Only the best-scoring matches are returned. Comparison ignores case.
+func Closest(input string, candidates []string) []string {
+	in := strings.ToLower(input)
+	if in == "" {
+		return nil
+	}
+	limit := max(1, len([]rune(in))/3)
+
+	type match struct {
+		word string
+		dist int
+	}
+	var matches []match
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		if lc == in {
+			continue
+		}
+		d := Distance(in, lc)
+		if strings.HasPrefix(lc, in) {
+			// Treat an abbreviation as a near miss even if it is short.
+			d = min(d, 1)
+		}
+		if d <= limit {
+			matches = append(matches, match{c, d})
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].dist < matches[j].dist })
+	best := matches[0].dist
+	var out []string
+	for _, m := range matches {
+		if m.dist != best {
+			break
+		}
+		out = append(out, m.word)
+	}
+	return out
+}
+
+// Distance returns the Levenshtein edit distance between a and b.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
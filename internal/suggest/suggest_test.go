@@ -0,0 +1,53 @@
+package suggest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"evnt", "event", 1},
+		{"kitten", "sitting", 3},
+		{"subscirbe", "subscribe", 2},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	commands := []string{"feed", "event", "subscribe", "archive"}
+
+	tests := []struct {
+		name  string
+		input string
+		cands []string
+		want  []string
+	}{
+		{name: "single typo", input: "evnt", cands: commands, want: []string{"event"}},
+		{name: "transposition", input: "subscirbe", cands: commands, want: []string{"subscribe"}},
+		{name: "case insensitive", input: "FEDD", cands: commands, want: []string{"feed"}},
+		{name: "prefix", input: "arch", cands: commands, want: []string{"archive"}},
+		{name: "no match", input: "zzzzzz", cands: commands, want: nil},
+		{name: "exact match is not a suggestion", input: "feed", cands: commands, want: nil},
+		{name: "ties returned in order", input: "--fed", cands: []string{"--feed", "--fee", "--end"}, want: []string{"--feed", "--fee"}},
+		{name: "empty input", input: "", cands: commands, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Closest(tt.input, tt.cands); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Closest(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
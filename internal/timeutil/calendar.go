@@ -0,0 +1,52 @@
+package timeutil
+
+import "time"
+
+// Naive duration math breaks twice a year: on a DST change a calendar day is
+// 23 or 25 hours long, so t.Add(24*time.Hour) lands an hour off the same
+// wall-clock time. The helpers below step in calendar days instead and should
+// be used whenever "the same time N days later" is meant.
+
+// AddDays returns t moved by n calendar days, keeping its wall-clock time in
+// t's location. A wall time that doesn't exist on the target day (inside a
+// spring-forward gap) is moved forward by the length of the gap, so 02:30
+// becomes 03:30, as RFC 5545 prescribes.
+func AddDays(t time.Time, n int) time.Time {
+	r := t.AddDate(0, 0, n)
+	y, m, d := t.Date()
+	wall := time.Date(y, m, d+n, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	ry, rm, rd := r.Date()
+	if ry == wall.Year() && rm == wall.Month() && rd == wall.Day() && r.Hour() == wall.Hour() && r.Minute() == wall.Minute() {
+		return r
+	}
+	// time.Date leaves the choice of offset in a gap unspecified; read the
+	// wall time with the offset in force the day before.
+	_, off := wall.Add(-day).In(t.Location()).Zone()
+	return wall.Add(-time.Duration(off) * time.Second).In(t.Location())
+}
+
+// AddNominal adds d to t, treating whole days in d as calendar days and only
+// the remainder as elapsed time. This is how RFC 5545 defines the D and W
+// duration units, so "P1D" after 09:00 is 09:00 the next day even across a
+// DST change.
+func AddNominal(t time.Time, d time.Duration) time.Time {
+	days := d / day
+	return AddDays(t, int(days)).Add(d - days*day)
+}
+
+// StartOfDay returns midnight at the start of t's calendar day in loc.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// DaysBetween returns the number of calendar days from a to b in loc, negative
+// if b falls on an earlier day. Times on the same day are 0 days apart.
+func DaysBetween(a, b time.Time, loc *time.Location) int {
+	ay, am, ad := a.In(loc).Date()
+	by, bm, bd := b.In(loc).Date()
+	// Count in UTC, where every day is 24 hours.
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from) / day)
+}
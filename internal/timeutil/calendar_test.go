@@ -0,0 +1,140 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestAddDaysAcrossDST(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name string
+		t    time.Time
+		n    int
+		want time.Time
+	}{
+		{
+			name: "spring forward keeps wall clock",
+			t:    time.Date(2026, 3, 7, 9, 0, 0, 0, ny),
+			n:    1,
+			want: time.Date(2026, 3, 8, 9, 0, 0, 0, ny),
+		},
+		{
+			name: "fall back keeps wall clock",
+			t:    time.Date(2026, 10, 24, 9, 0, 0, 0, berlin),
+			n:    1,
+			want: time.Date(2026, 10, 25, 9, 0, 0, 0, berlin),
+		},
+		{
+			name: "backwards across spring forward",
+			t:    time.Date(2026, 3, 30, 9, 0, 0, 0, berlin),
+			n:    -7,
+			want: time.Date(2026, 3, 23, 9, 0, 0, 0, berlin),
+		},
+		{
+			name: "time in the gap moves forward",
+			t:    time.Date(2026, 3, 7, 2, 30, 0, 0, ny),
+			n:    1,
+			want: time.Date(2026, 3, 8, 3, 30, 0, 0, ny),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AddDays(tt.t, tt.n)
+			if !got.Equal(tt.want) {
+				t.Errorf("AddDays(%v, %d) = %v, want %v", tt.t, tt.n, got, tt.want)
+			}
+			if tt.t.Hour() == 9 && got.Sub(tt.t) == time.Duration(tt.n)*24*time.Hour {
+				t.Errorf("AddDays(%v, %d) moved exactly %d×24h; the DST change was ignored", tt.t, tt.n, tt.n)
+			}
+		})
+	}
+}
+
+func TestAddNominal(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	start := time.Date(2026, 10, 31, 9, 0, 0, 0, ny) // the day before fall back
+
+	tests := []struct {
+		d    time.Duration
+		want time.Time
+	}{
+		{d: 24 * time.Hour, want: time.Date(2026, 11, 1, 9, 0, 0, 0, ny)},
+		{d: 26 * time.Hour, want: time.Date(2026, 11, 1, 11, 0, 0, 0, ny)},
+		{d: 90 * time.Minute, want: time.Date(2026, 10, 31, 10, 30, 0, 0, ny)},
+		{d: -25 * time.Hour, want: time.Date(2026, 10, 30, 8, 0, 0, 0, ny)},
+	}
+	for _, tt := range tests {
+		if got := AddNominal(start, tt.d); !got.Equal(tt.want) {
+			t.Errorf("AddNominal(%v) = %v, want %v", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+
+	// 03:30 UTC on 9 March is still 8 March in New York.
+	got := StartOfDay(time.Date(2026, 3, 9, 3, 30, 0, 0, time.UTC), ny)
+	want := time.Date(2026, 3, 8, 0, 0, 0, 0, ny)
+	if !got.Equal(want) {
+		t.Errorf("StartOfDay = %v, want %v", got, want)
+	}
+	// The day of the change is only 23 hours long.
+	if n := StartOfDay(want.Add(30*time.Hour), ny).Sub(want); n != 23*time.Hour {
+		t.Errorf("8 March is %v long, want 23h", n)
+	}
+}
+
+func TestDaysBetween(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name string
+		a, b time.Time
+		want int
+	}{
+		{
+			name: "same day",
+			a:    time.Date(2026, 3, 29, 0, 30, 0, 0, berlin),
+			b:    time.Date(2026, 3, 29, 23, 30, 0, 0, berlin),
+			want: 0,
+		},
+		{
+			name: "across spring forward",
+			a:    time.Date(2026, 3, 28, 23, 0, 0, 0, berlin),
+			b:    time.Date(2026, 3, 30, 0, 30, 0, 0, berlin),
+			want: 2,
+		},
+		{
+			name: "backwards across fall back",
+			a:    time.Date(2026, 10, 26, 1, 0, 0, 0, berlin),
+			b:    time.Date(2026, 10, 24, 23, 0, 0, 0, berlin),
+			want: -2,
+		},
+		{
+			name: "uses loc, not the times' own zone",
+			a:    time.Date(2026, 3, 28, 22, 30, 0, 0, time.UTC), // 23:30 in Berlin
+			b:    time.Date(2026, 3, 28, 23, 30, 0, 0, time.UTC), // 00:30 next day
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DaysBetween(tt.a, tt.b, berlin); got != tt.want {
+				t.Errorf("DaysBetween = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+	year = 365 * day
+)
+
+// ParseDuration is like time.ParseDuration but also accepts the units d
+// (day), w (week) and y (365-day year), so ages such as "90d", "1y" or
+// "1w2d" can be written directly. Standard units may be mixed in, e.g.
+// "1d12h".
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9' || rest[i] == '.') {
+			i++
+		}
+		j := i
+		for j < len(rest) && !(rest[j] >= '0' && rest[j] <= '9' || rest[j] == '.') {
+			j++
+		}
+		if i == 0 || j == i {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		num, unit := rest[:i], rest[i:j]
+		rest = rest[j:]
+
+		var mult time.Duration
+		switch unit {
+		case "d":
+			mult = day
+		case "w":
+			mult = week
+		case "y":
+			mult = year
+		default:
+			d, err := time.ParseDuration(num + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			total += d
+			continue
+		}
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n * float64(mult))
+	}
+	return total, nil
+}
+
+// ParseCutoff resolves s to a point in time. It accepts an absolute RFC 3339
+// timestamp, a plain date (YYYY-MM-DD, midnight in loc), or an age accepted
+// by ParseDuration which is subtracted from now ("1y" means one year ago).
+// Ages in whole days step back calendar days in loc, so "7d" keeps the
+// current wall-clock time even across a DST change.
+func ParseCutoff(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, loc); err == nil {
+		return t, nil
+	}
+	d, err := ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: want RFC 3339, YYYY-MM-DD or an age like 90d", s)
+	}
+	if d%day == 0 {
+		return AddDays(now.In(loc), -int(d/day)), nil
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,119 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "standard minutes", input: "30m", want: 30 * time.Minute},
+		{name: "standard mixed", input: "1h30m", want: 90 * time.Minute},
+		{name: "days", input: "90d", want: 90 * 24 * time.Hour},
+		{name: "weeks", input: "2w", want: 14 * 24 * time.Hour},
+		{name: "years", input: "1y", want: 365 * 24 * time.Hour},
+		{name: "days and hours", input: "1d12h", want: 36 * time.Hour},
+		{name: "weeks and days", input: "1w2d", want: 9 * 24 * time.Hour},
+		{name: "fractional day", input: "1.5d", want: 36 * time.Hour},
+		{name: "surrounding space", input: " 7d ", want: 7 * 24 * time.Hour},
+		{name: "empty", input: "", wantErr: true},
+		{name: "no unit", input: "10", wantErr: true},
+		{name: "unknown unit", input: "3q", wantErr: true},
+		{name: "garbage", input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseCutoff(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "rfc3339",
+			input: "2025-01-02T03:04:05Z",
+			want:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name:  "date only",
+			input: "2025-01-02",
+			want:  time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "relative age",
+			input: "30d",
+			want:  now.Add(-30 * 24 * time.Hour),
+		},
+		{
+			name:  "relative age with hours",
+			input: "1d12h",
+			want:  now.Add(-36 * time.Hour),
+		},
+		{
+			name:    "invalid",
+			input:   "last tuesday",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCutoff(tt.input, now, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseCutoffAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, ny)
+
+	got, err := ParseCutoff("7d", now, ny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Seven calendar days back is noon EST, only 167 hours earlier.
+	if want := time.Date(2026, 3, 3, 12, 0, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("ParseCutoff(7d) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+// Package watchdog tracks the runs of pylon's long-running jobs (pylon
+// remind, pylon retention): when each last ran, how long it took, and how
+// many times in a row it has failed, so that a job that keeps failing is
+// reported instead of only logging. Each job's status is a JSON file in a
+// shared directory, so separate processes can be listed together.
+package watchdog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultThreshold is how many consecutive failures trigger a notification
+// when Watchdog.Threshold is not set.
+const DefaultThreshold = 3
+
+// Status is the recorded state of a job.
+type Status struct {
+	Job         string        `json:"job"`
+	LastRun     time.Time     `json:"last_run"`
+	Duration    time.Duration `json:"duration"`
+	LastError   string        `json:"last_error,omitempty"`
+	LastSuccess time.Time     `json:"last_success,omitzero"`
+	// Failures counts the consecutive failed runs up to LastRun; 0 means
+	// the last run succeeded.
+	Failures int `json:"failures"`
+	// Notified is set once the current run of failures was reported.
+	Notified bool `json:"notified,omitempty"`
+	PID      int  `json:"pid,omitempty"`
+}
+
+// OK reports whether the job's last run succeeded.
+func (s Status) OK() bool { return s.Failures == 0 }
+
+// Watchdog records job runs in Dir and reports failing jobs.
+type Watchdog struct {
+	Dir string
+	// Threshold is the number of consecutive failures after which Notify
+	// is called; 0 means DefaultThreshold.
+	Threshold int
+	// Notify, if set, is called once when a job reaches Threshold
+	// consecutive failures, and again with recovered set when it next
+	// succeeds.
+	Notify func(st Status, recovered bool) error
+}
+
+// Run runs fn as a run of job and records the outcome. The returned error
+// is fn's, joined with any failure to record or notify.
+func (w *Watchdog) Run(job string, fn func() error) error {
+	st, err := Load(w.Dir, job)
+	if err != nil {
+		// A corrupt status file shouldn't stop the job; start afresh.
+		st = Status{Job: job}
+	}
+
+	start := time.Now()
+	runErr := fn()
+	st.LastRun = start
+	st.Duration = time.Since(start)
+	st.PID = os.Getpid()
+
+	var notify func() error
+	if runErr == nil {
+		if st.Notified && w.Notify != nil {
+			recovered := st
+			notify = func() error { return w.Notify(recovered, true) }
+		}
+		st.Failures, st.LastError, st.Notified = 0, "", false
+		st.LastSuccess = start
+	} else {
+		st.Failures++
+		st.LastError = runErr.Error()
+		if !st.Notified && st.Failures >= w.threshold() && w.Notify != nil {
+			st.Notified = true
+			failing := st
+			notify = func() error { return w.Notify(failing, false) }
+		}
+	}
+
+	errs := []error{runErr}
+	if err := save(w.Dir, st); err != nil {
+		errs = append(errs, fmt.Errorf("watchdog: %w", err))
+	}
+	if notify != nil {
+		if err := notify(); err != nil {
+			errs = append(errs, fmt.Errorf("watchdog: notify: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watchdog) threshold() int {
+	if w.Threshold > 0 {
+		return w.Threshold
+	}
+	return DefaultThreshold
+}
+
+// Load reads job's status from dir. A job that never ran has a zero
+// Status with only Job set.
+func Load(dir, job string) (Status, error) {
+	st := Status{Job: job}
+	path, err := statusPath(dir, job)
+	if err != nil {
+		return st, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return st, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return Status{Job: job}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	st.Job = job
+	return st, nil
+}
+
+// List returns the status of every job recorded in dir, by name. A missing
+// directory has no jobs.
+func List(dir string) ([]Status, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []Status
+	for _, p := range paths {
+		st, err := Load(dir, strings.TrimSuffix(filepath.Base(p), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Job < out[j].Job })
+	return out, nil
+}
+
+// statusPath returns the file holding job's status.
+func statusPath(dir, job string) (string, error) {
+	if job == "" || strings.ContainsAny(job, `/\`) || strings.HasPrefix(job, ".") {
+		return "", fmt.Errorf("invalid job name %q", job)
+	}
+	return filepath.Join(dir, job+".json"), nil
+}
+
+// save writes st atomically.
+func save(dir string, st Status) error {
+	path, err := statusPath(dir, st.Job)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".job-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,114 @@
+package watchdog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type notice struct {
+	failures  int
+	recovered bool
+}
+
+func TestRunNotifiesOncePerFailureStreak(t *testing.T) {
+	dir := t.TempDir()
+	var notices []notice
+	w := &Watchdog{
+		Dir:       dir,
+		Threshold: 2,
+		Notify: func(st Status, recovered bool) error {
+			notices = append(notices, notice{st.Failures, recovered})
+			return nil
+		},
+	}
+	boom := errors.New("boom")
+
+	outcomes := []error{nil, boom, boom, boom, nil, boom, nil}
+	for _, o := range outcomes {
+		if err := w.Run("remind", func() error { return o }); !errors.Is(err, o) || (o == nil && err != nil) {
+			t.Fatalf("Run returned %v, want %v", err, o)
+		}
+	}
+
+	// Reported at the second failure in a row and on recovery; the lone
+	// failure later stays below the threshold.
+	want := []notice{{2, false}, {3, true}}
+	if len(notices) != len(want) {
+		t.Fatalf("notices = %v, want %v", notices, want)
+	}
+	for i := range want {
+		if notices[i] != want[i] {
+			t.Errorf("notice %d = %v, want %v", i, notices[i], want[i])
+		}
+	}
+
+	st, err := Load(dir, "remind")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !st.OK() || st.LastError != "" || st.Notified || st.LastRun.IsZero() || st.LastSuccess != st.LastRun {
+		t.Errorf("status after success = %+v", st)
+	}
+}
+
+func TestRunRecordsFailure(t *testing.T) {
+	dir := t.TempDir()
+	w := &Watchdog{Dir: dir}
+	for range 2 {
+		_ = w.Run("retention", func() error { return errors.New("list feeds: 502") })
+	}
+	st, err := Load(dir, "retention")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if st.Failures != 2 || st.LastError != "list feeds: 502" || st.Notified || !st.LastSuccess.IsZero() {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestRunReportsNotifyError(t *testing.T) {
+	w := &Watchdog{
+		Dir:       t.TempDir(),
+		Threshold: 1,
+		Notify:    func(Status, bool) error { return errors.New("no channel") },
+	}
+	boom := errors.New("boom")
+	err := w.Run("remind", func() error { return boom })
+	if !errors.Is(err, boom) || err.Error() != "boom\nwatchdog: notify: no channel" {
+		t.Errorf("Run = %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	if jobs, err := List(filepath.Join(dir, "missing")); err != nil || len(jobs) != 0 {
+		t.Fatalf("List(missing) = %v, %v", jobs, err)
+	}
+
+	w := &Watchdog{Dir: dir}
+	_ = w.Run("retention", func() error { return nil })
+	_ = w.Run("remind", func() error { return errors.New("boom") })
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := List(dir)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].Job != "remind" || jobs[1].Job != "retention" {
+		t.Fatalf("jobs = %+v", jobs)
+	}
+	if jobs[0].OK() || !jobs[1].OK() {
+		t.Errorf("OK = %v, %v; want false, true", jobs[0].OK(), jobs[1].OK())
+	}
+}
+
+func TestInvalidJobName(t *testing.T) {
+	w := &Watchdog{Dir: t.TempDir()}
+	if err := w.Run("../escape", func() error { return nil }); err == nil {
+		t.Error("expected an error for a job name with a path separator")
+	}
+}